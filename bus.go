@@ -22,6 +22,26 @@ type Bus interface {
 	Subscribe(callback FrameListener) error // Subscribe to all received CAN frames
 }
 
+// Optional interface that can be implemented by a [Bus] to report
+// loss of the underlying CAN interface, e.g. cable unplugged or driver reload.
+// Err returns a non nil error once the bus has stopped receiving frames.
+// The bus then needs to be disconnected and connected again.
+type BusErrorReporter interface {
+	Err() error
+}
+
+// Optional interface that can be implemented by a [Bus] to report the error
+// status of the CAN controller, as a combination of CanErrorXXX flags e.g. [CanErrorTxBusOff].
+type BusErrorStatusReporter interface {
+	ErrorStatus() uint16
+}
+
+// Optional interface that can be implemented by a [Bus] to restart
+// the CAN controller, e.g. for recovering from bus-off.
+type BusRestarter interface {
+	Restart() error
+}
+
 // A generic 11bit CAN frame
 type Frame struct {
 	ID    uint32
@@ -0,0 +1,177 @@
+package canopen
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var ErrForcedBusOff = errors.New("bus-off forced by fault injection")
+
+// A FaultRule describes faults injected on the frames of a range of CAN IDs.
+// Rates are probabilities between 0 & 1, evaluated independently for every frame.
+type FaultRule struct {
+	// Inclusive range of CAN IDs (without RTR flag),
+	// a rule with MinId = MaxId = 0 applies to all the frames.
+	MinId uint32
+	MaxId uint32
+	// Direction of affected frames, 0 for both
+	Direction TraceDirection
+	// Frame is lost
+	DropRate float64
+	// Frame is sent or received twice
+	DuplicateRate float64
+	// DLC is changed to another random value
+	CorruptDLCRate float64
+	// A random bit of the data is flipped
+	CorruptDataRate float64
+	// Frame is delivered after Delay, later frames are not held back
+	Delay time.Duration
+}
+
+func (rule *FaultRule) matches(direction TraceDirection, frame Frame) bool {
+	if rule.Direction != 0 && rule.Direction != direction {
+		return false
+	}
+	if rule.MinId == 0 && rule.MaxId == 0 {
+		return true
+	}
+	id := frame.ID & CanSffMask
+	return id >= rule.MinId && id <= rule.MaxId
+}
+
+// Counters of the faults injected by a [FaultInjector]
+type FaultStats struct {
+	Dropped    uint32
+	Duplicated uint32
+	Corrupted  uint32
+	Delayed    uint32
+}
+
+// FaultInjector injects faults on the frames sent & received by a [BusManager],
+// for testing the robustness of applications and of the stack itself,
+// see [BusManager.SetFaultInjector]. Rules & bus-off can be changed at runtime.
+type FaultInjector struct {
+	mu     sync.Mutex
+	rng    *rand.Rand
+	rules  []FaultRule
+	busOff bool
+	stats  FaultStats
+}
+
+// Create a new [FaultInjector] without rules, a given seed
+// always gives the same faults for the same frames.
+func NewFaultInjector(seed int64) *FaultInjector {
+	return &FaultInjector{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Add a rule, frames matching several rules get the faults of all of them
+func (f *FaultInjector) AddRule(rule FaultRule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = append(f.rules, rule)
+}
+
+// Remove all the rules
+func (f *FaultInjector) ClearRules() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = nil
+}
+
+// Force bus-off : frames are neither sent nor received and the error status
+// of the bus reports [CanErrorTxBusOff], until called again with false.
+func (f *FaultInjector) SetBusOff(busOff bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.busOff = busOff
+}
+
+// Returns true if bus-off is forced
+func (f *FaultInjector) BusOff() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.busOff
+}
+
+// Get fault counters
+func (f *FaultInjector) Stats() FaultStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+// Apply the rules to frame, returns the frames to deliver (none if dropped)
+// and their delay
+func (f *FaultInjector) apply(direction TraceDirection, frame Frame) ([]Frame, time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.busOff {
+		return nil, 0, ErrForcedBusOff
+	}
+	frames := []Frame{frame}
+	delay := time.Duration(0)
+	for i := range f.rules {
+		rule := &f.rules[i]
+		if !rule.matches(direction, frame) {
+			continue
+		}
+		if f.rng.Float64() < rule.DropRate {
+			f.stats.Dropped++
+			return nil, 0, nil
+		}
+		if f.rng.Float64() < rule.CorruptDLCRate {
+			// Any other DLC between 0 & 8
+			frames[0].DLC = (frames[0].DLC + 1 + uint8(f.rng.Intn(8))) % 9
+			f.stats.Corrupted++
+		}
+		if f.rng.Float64() < rule.CorruptDataRate {
+			bit := f.rng.Intn(64)
+			frames[0].Data[bit/8] ^= 1 << (bit % 8)
+			f.stats.Corrupted++
+		}
+		if f.rng.Float64() < rule.DuplicateRate {
+			f.stats.Duplicated++
+			frames = append(frames, frames[0])
+		}
+		if rule.Delay > 0 {
+			f.stats.Delayed++
+			delay += rule.Delay
+		}
+	}
+	// Duplicates are copies of the corrupted frame
+	for i := range frames {
+		frames[i] = frames[0]
+	}
+	return frames, delay, nil
+}
+
+// Deliver frame with faults, errors of delayed frames are ignored
+func (f *FaultInjector) inject(direction TraceDirection, frame Frame, deliver func(frame Frame) error) error {
+	frames, delay, err := f.apply(direction, frame)
+	if err != nil {
+		return err
+	}
+	if delay > 0 {
+		time.AfterFunc(delay, func() {
+			for _, frame := range frames {
+				_ = deliver(frame)
+			}
+		})
+		return nil
+	}
+	for _, frame := range frames {
+		if err := deliver(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetFaultInjector injects faults on all the frames sent & received, this can
+// be done at runtime. nil stops injecting faults.
+// Dropped frames are not reported as send errors, like frames lost on the bus.
+func (bm *BusManager) SetFaultInjector(injector *FaultInjector) {
+	bm.faults.Store(injector)
+}
@@ -0,0 +1,125 @@
+package canopen
+
+import (
+	"math/bits"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type frameCounter struct {
+	frames []Frame
+}
+
+func (c *frameCounter) Handle(frame Frame) {
+	c.frames = append(c.frames, frame)
+}
+
+func TestFaultInjector(t *testing.T) {
+	frame := NewFrame(0x181, 0, 8)
+	frame.Data = [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	t.Run("drop", func(t *testing.T) {
+		bus := &slowBus{}
+		bm := NewBusManager(bus)
+		injector := NewFaultInjector(1)
+		injector.AddRule(FaultRule{MinId: 0x180, MaxId: 0x1FF, DropRate: 0.5})
+		bm.SetFaultInjector(injector)
+		for range 1000 {
+			assert.Nil(t, bm.Send(frame))
+			assert.Nil(t, bm.Send(NewFrame(0x201, 0, 8)))
+		}
+		dropped := injector.Stats().Dropped
+		assert.InDelta(t, 500, dropped, 100)
+		assert.EqualValues(t, 2000-dropped, bus.count())
+		assert.EqualValues(t, 2000-dropped, bm.BusStats().FramesSent)
+		// Same seed, same faults
+		other := NewFaultInjector(1)
+		other.AddRule(FaultRule{DropRate: 0.5})
+		for range 1000 {
+			_ = other.inject(TraceTx, frame, func(frame Frame) error { return nil })
+		}
+		assert.Equal(t, dropped, other.Stats().Dropped)
+		bm.SetFaultInjector(nil)
+		assert.Nil(t, bm.Send(frame))
+		assert.EqualValues(t, 2001-dropped, bus.count())
+	})
+
+	t.Run("direction", func(t *testing.T) {
+		bm := NewBusManager(&slowBus{})
+		counter := &frameCounter{}
+		assert.Nil(t, bm.Subscribe(0x181, 0x7FF, false, counter))
+		injector := NewFaultInjector(1)
+		injector.AddRule(FaultRule{Direction: TraceTx, DropRate: 1})
+		bm.SetFaultInjector(injector)
+		bm.Handle(frame)
+		assert.Len(t, counter.frames, 1)
+		injector.ClearRules()
+		injector.AddRule(FaultRule{Direction: TraceRx, DropRate: 1})
+		bm.Handle(frame)
+		assert.Len(t, counter.frames, 1)
+		assert.EqualValues(t, 1, bm.BusStats().FramesReceived)
+	})
+
+	t.Run("duplicate & corrupt", func(t *testing.T) {
+		bm := NewBusManager(&slowBus{})
+		counter := &frameCounter{}
+		assert.Nil(t, bm.Subscribe(0x181, 0x7FF, false, counter))
+		injector := NewFaultInjector(1)
+		injector.AddRule(FaultRule{DuplicateRate: 1, CorruptDataRate: 1})
+		bm.SetFaultInjector(injector)
+		bm.Handle(frame)
+		assert.Len(t, counter.frames, 2)
+		assert.Equal(t, counter.frames[0], counter.frames[1])
+		diff := 0
+		for i := range frame.Data {
+			diff += bits.OnesCount8(frame.Data[i] ^ counter.frames[0].Data[i])
+		}
+		assert.Equal(t, 1, diff)
+		injector.ClearRules()
+		injector.AddRule(FaultRule{CorruptDLCRate: 1})
+		for range 100 {
+			bm.Handle(frame)
+			corrupted := counter.frames[len(counter.frames)-1]
+			assert.NotEqual(t, frame.DLC, corrupted.DLC)
+			assert.LessOrEqual(t, corrupted.DLC, uint8(8))
+		}
+		assert.Equal(t, FaultStats{Duplicated: 1, Corrupted: 101}, injector.Stats())
+	})
+
+	t.Run("delay", func(t *testing.T) {
+		bus := &slowBus{}
+		bm := NewBusManager(bus)
+		injector := NewFaultInjector(1)
+		injector.AddRule(FaultRule{Delay: 50 * time.Millisecond})
+		bm.SetFaultInjector(injector)
+		start := time.Now()
+		assert.Nil(t, bm.Send(frame))
+		assert.Equal(t, 0, bus.count())
+		assert.Eventually(t, func() bool { return bus.count() == 1 }, time.Second, time.Millisecond)
+		assert.GreaterOrEqual(t, bus.times[0].Sub(start), 50*time.Millisecond)
+	})
+
+	t.Run("bus-off", func(t *testing.T) {
+		bus := &slowBus{}
+		bm := NewBusManager(bus)
+		counter := &frameCounter{}
+		assert.Nil(t, bm.Subscribe(0x181, 0x7FF, false, counter))
+		injector := NewFaultInjector(1)
+		bm.SetFaultInjector(injector)
+		injector.SetBusOff(true)
+		assert.ErrorIs(t, bm.Send(frame), ErrForcedBusOff)
+		bm.Handle(frame)
+		assert.Nil(t, bm.Process())
+		assert.EqualValues(t, CanErrorTxBusOff, bm.Error())
+		assert.Equal(t, 0, bus.count())
+		assert.Len(t, counter.frames, 0)
+		assert.EqualValues(t, 1, bm.BusStats().SendErrors)
+		injector.SetBusOff(false)
+		assert.Nil(t, bm.Process())
+		assert.EqualValues(t, 0, bm.Error())
+		assert.Nil(t, bm.Send(frame))
+		assert.Equal(t, 1, bus.count())
+	})
+}
@@ -0,0 +1,111 @@
+package canopen
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBusLoadWindow = 1 * time.Second // Time window of bus load measurement
+	busLoadBuckets       = 10
+)
+
+// Bus load measured over the last [DefaultBusLoadWindow], sent & received frames included
+type BusLoad struct {
+	FramesPerSecond float64
+	BitsPerSecond   float64
+	// Percentage of the bitrate set with [BusManager.SetBitrate], 0 if unknown
+	Percent float64
+}
+
+// FrameBits returns the number of bits of a standard CAN frame on the bus,
+// including worst case bit stuffing and interframe space.
+func FrameBits(frame Frame) uint32 {
+	dataBits := uint32(frame.DLC) * 8
+	if frame.ID&CanRtrFlag != 0 {
+		dataBits = 0
+	}
+	// SOF, ID, RTR, IDE, r0, DLC, data & CRC are subject to bit stuffing
+	stuffed := 34 + dataBits
+	// CRC delimiter, ACK, EOF & interframe space are not
+	return stuffed + (stuffed-1)/4 + 13
+}
+
+// Sliding window of frame & bit counts, split in buckets
+type loadMeter struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	bitrate int
+	start   time.Time // Start of measurement
+	current int64     // Index of current bucket, since start
+	frames  [busLoadBuckets]uint32
+	bits    [busLoadBuckets]uint64
+}
+
+func newLoadMeter() *loadMeter {
+	return &loadMeter{now: time.Now}
+}
+
+// Index of the bucket of now, buckets that are too old are cleared
+func (m *loadMeter) advance(now time.Time) int64 {
+	if m.start.IsZero() {
+		m.start = now
+	}
+	bucket := int64(now.Sub(m.start) / (DefaultBusLoadWindow / busLoadBuckets))
+	for i := m.current + 1; i <= bucket && i <= m.current+busLoadBuckets; i++ {
+		m.frames[i%busLoadBuckets] = 0
+		m.bits[i%busLoadBuckets] = 0
+	}
+	m.current = max(m.current, bucket)
+	return m.current
+}
+
+func (m *loadMeter) add(frame Frame) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bucket := m.advance(m.now()) % busLoadBuckets
+	m.frames[bucket]++
+	m.bits[bucket] += uint64(FrameBits(frame))
+}
+
+func (m *loadMeter) load() BusLoad {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	current := m.advance(now)
+	// Buckets span the previous buckets & the elapsed part of the current one.
+	// Measurement may not span a full window yet
+	bucketDuration := DefaultBusLoadWindow / busLoadBuckets
+	spanned := (busLoadBuckets-1)*bucketDuration + now.Sub(m.start) - time.Duration(current)*bucketDuration
+	elapsed := min(now.Sub(m.start), spanned).Seconds()
+	if elapsed <= 0 {
+		return BusLoad{}
+	}
+	var frames uint32
+	var bits uint64
+	for i := range busLoadBuckets {
+		frames += m.frames[i]
+		bits += m.bits[i]
+	}
+	load := BusLoad{
+		FramesPerSecond: float64(frames) / elapsed,
+		BitsPerSecond:   float64(bits) / elapsed,
+	}
+	if m.bitrate > 0 {
+		load.Percent = 100 * load.BitsPerSecond / float64(m.bitrate)
+	}
+	return load
+}
+
+// Set the bitrate of the bus, used for computing bus load percentage.
+// This is done by the network when connecting with a bitrate.
+func (bm *BusManager) SetBitrate(bitrate int) {
+	bm.load.mu.Lock()
+	defer bm.load.mu.Unlock()
+	bm.load.bitrate = bitrate
+}
+
+// Get the current bus load, estimated from the frames sent & received
+func (bm *BusManager) BusLoad() BusLoad {
+	return bm.load.load()
+}
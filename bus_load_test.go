@@ -0,0 +1,39 @@
+package canopen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameBits(t *testing.T) {
+	assert.EqualValues(t, 135, FrameBits(NewFrame(0x181, 0, 8)))
+	assert.EqualValues(t, 55, FrameBits(NewFrame(0x0, 0, 0)))
+	assert.EqualValues(t, 55, FrameBits(NewFrame(0x701|CanRtrFlag, 0, 1)))
+}
+
+func TestBusLoad(t *testing.T) {
+	now := time.Unix(0, 0)
+	meter := newLoadMeter()
+	meter.now = func() time.Time { return now }
+	frame := NewFrame(0x181, 0, 8)
+	// 1000 frames per second during 2 seconds
+	for range 2000 {
+		meter.add(frame)
+		now = now.Add(time.Millisecond)
+	}
+	load := meter.load()
+	assert.InDelta(t, 1000, load.FramesPerSecond, 1)
+	assert.InDelta(t, 135_000, load.BitsPerSecond, 200)
+	assert.Zero(t, load.Percent)
+
+	meter.bitrate = 500_000
+	assert.InDelta(t, 27, meter.load().Percent, 0.1)
+
+	// Nothing sent or received for a while, load decreases by bucket
+	now = now.Add(500 * time.Millisecond)
+	assert.InDelta(t, 500, meter.load().FramesPerSecond, 100)
+	now = now.Add(10 * time.Second)
+	assert.Zero(t, meter.load().FramesPerSecond)
+}
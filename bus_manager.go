@@ -2,7 +2,9 @@ package canopen
 
 import (
 	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 )
 
 // Bus manager is a wrapper around the CAN bus interface
@@ -10,62 +12,160 @@ import (
 type BusManager struct {
 	logger         *slog.Logger
 	mu             sync.Mutex
+	busMu          sync.RWMutex
 	bus            Bus // Bus interface that can be adapted
-	frameListeners map[uint32][]FrameListener
+	scheduler      *txScheduler
+	frameListeners map[uint32][]FrameListener // Subscriptions to a single COB-ID
+	wildcards      []wildcardSubscription     // Subscriptions with a mask or a range
 	canError       uint16
+	framesReceived atomic.Uint32
+	framesSent     atomic.Uint32
+	sendErrors     atomic.Uint32
+	load           *loadMeter
+	tracer         atomic.Pointer[tracerHolder]
+	faults         atomic.Pointer[FaultInjector]
+	dispatcher     atomic.Pointer[rxDispatcher]
+	rxDropped      atomic.Uint32
+	rxDroppedAt    atomic.Int64 // Unix nano timestamp of last dropped frame
+}
+
+// Frame counters of a [BusManager], counters are 32 bits and wrap around.
+type BusStats struct {
+	FramesReceived uint32 // All frames received, even without listener
+	FramesSent     uint32
+	SendErrors     uint32
+	RxDropped      uint32 // Frames dropped by the RX dispatcher, see [BusManager.StartRxDispatch]
 }
 
 // Implements the FrameListener interface
 // This handles all received CAN frames from Bus
 // [listener.Handle] should not be blocking !
 func (bm *BusManager) Handle(frame Frame) {
-	bm.mu.Lock()
-	defer bm.mu.Unlock()
-	listeners, ok := bm.frameListeners[frame.ID]
-	if !ok {
+	if injector := bm.faults.Load(); injector != nil {
+		_ = injector.inject(TraceRx, frame, bm.handle)
 		return
 	}
+	_ = bm.handle(frame)
+}
+
+// Dispatch a received frame to listeners
+func (bm *BusManager) handle(frame Frame) error {
+	bm.framesReceived.Add(1)
+	bm.load.add(frame)
+	bm.trace(TraceRx, frame)
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	listeners := bm.frameListeners[frame.ID]
 	for _, listener := range listeners {
-		listener.Handle(frame)
+		bm.deliver(listener, frame)
 	}
+	for _, wildcard := range bm.wildcards {
+		// Listeners matching several subscriptions get the frame once
+		if wildcard.matches(frame.ID) && !slices.Contains(listeners, wildcard.listener) {
+			listeners = append(listeners[:len(listeners):len(listeners)], wildcard.listener)
+			bm.deliver(wildcard.listener, frame)
+		}
+	}
+	return nil
 }
 
 // Set bus
+// This can be done at runtime e.g. after recreating the bus on interface loss,
+// all the subscriptions are kept.
 func (bm *BusManager) SetBus(bus Bus) {
-	bm.mu.Lock()
-	defer bm.mu.Unlock()
+	bm.busMu.Lock()
+	defer bm.busMu.Unlock()
 	bm.bus = bus
 }
 
 func (bm *BusManager) Bus() Bus {
-	bm.mu.Lock()
-	defer bm.mu.Unlock()
+	bm.busMu.RLock()
+	defer bm.busMu.RUnlock()
 	return bm.bus
 }
 
 // Send a CAN message
 // Limited error handling
 func (bm *BusManager) Send(frame Frame) error {
-	err := bm.bus.Send(frame)
+	bm.busMu.RLock()
+	scheduler := bm.scheduler
+	bm.busMu.RUnlock()
+	if scheduler != nil {
+		return scheduler.enqueue(bm, frame)
+	}
+	err := bm.sendBus(frame)
 	if err != nil {
 		bm.logger.Warn("error sending frame", "err", err)
 	}
 	return err
 }
 
-// This should be called cyclically to update errors
+// Send a frame on the bus directly & update counters
+func (bm *BusManager) sendBus(frame Frame) error {
+	if injector := bm.faults.Load(); injector != nil {
+		err := injector.inject(TraceTx, frame, bm.sendBusNow)
+		if err == ErrForcedBusOff {
+			bm.sendErrors.Add(1)
+		}
+		return err
+	}
+	return bm.sendBusNow(frame)
+}
+
+func (bm *BusManager) sendBusNow(frame Frame) error {
+	err := bm.Bus().Send(frame)
+	if err != nil {
+		bm.sendErrors.Add(1)
+	} else {
+		bm.framesSent.Add(1)
+		bm.load.add(frame)
+		bm.trace(TraceTx, frame)
+	}
+	return err
+}
+
+// Get frame counters
+func (bm *BusManager) BusStats() BusStats {
+	return BusStats{
+		FramesReceived: bm.framesReceived.Load(),
+		FramesSent:     bm.framesSent.Load(),
+		SendErrors:     bm.sendErrors.Load(),
+		RxDropped:      bm.rxDropped.Load(),
+	}
+}
+
+// Reset frame counters
+func (bm *BusManager) ResetBusStats() {
+	bm.framesReceived.Store(0)
+	bm.framesSent.Store(0)
+	bm.sendErrors.Store(0)
+	bm.rxDropped.Store(0)
+}
+
+// This should be called cyclically to update errors,
+// reported by buses implementing [BusErrorStatusReporter]
 func (bm *BusManager) Process() error {
+	status := bm.busErrorStatus()
+	if bm.rxOverflow() {
+		status |= CanErrorRxOverflow
+	}
 	bm.mu.Lock()
 	defer bm.mu.Unlock()
-	// TODO get bus state error
-	bm.canError = 0
+	bm.canError = status
 	return nil
 }
 
-// Subscribe to a specific CAN ID
+// Subscribe to the CAN IDs matching ident on the bits set in mask, e.g. 0x7FF for a single
+// COB-ID or 0x780 with ident 0x80 for all the EMCYs (0x80 - 0xFF). rtr selects remote frames.
+// Several listeners can subscribe to the same COB-ID, they all receive the frames.
+// A listener matching several of its subscriptions receives each frame once.
 func (bm *BusManager) Subscribe(ident uint32, mask uint32, rtr bool, callback FrameListener) error {
 	bm.mu.Lock()
 	defer bm.mu.Unlock()
+	mask &= CanSffMask
+	if mask != CanSffMask {
+		return bm.subscribeWildcard(wildcardSubscription{ident: ident & mask, mask: mask, rtr: rtr, listener: callback})
+	}
 	ident = ident & CanSffMask
 	if rtr {
 		ident |= CanRtrFlag
@@ -86,6 +186,94 @@ func (bm *BusManager) Subscribe(ident uint32, mask uint32, rtr bool, callback Fr
 	return nil
 }
 
+// SubscribeRange subscribes to all the CAN IDs between first & last included,
+// e.g. 0x180 - 0x57F for all the PDOs with the predefined connection set. See [BusManager.Subscribe].
+func (bm *BusManager) SubscribeRange(first uint32, last uint32, rtr bool, callback FrameListener) error {
+	first, last = first&CanSffMask, last&CanSffMask
+	if first > last {
+		return ErrIllegalArgument
+	}
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	return bm.subscribeWildcard(wildcardSubscription{first: first, last: last, isRange: true, rtr: rtr, listener: callback})
+}
+
+func (bm *BusManager) subscribeWildcard(subscription wildcardSubscription) error {
+	if slices.Contains(bm.wildcards, subscription) {
+		bm.logger.Warn("callback for frames already present", "id", subscription.ident, "mask", subscription.mask)
+		return nil
+	}
+	bm.wildcards = append(bm.wildcards, subscription)
+	return nil
+}
+
+// Remove a listener from all the CAN IDs it is subscribed to.
+// When the RX dispatcher is running, this waits for the listener to handle its queued
+// frames, so that no frame is handled once it returns. It should not be called from
+// the Handle method of the listener itself.
+func (bm *BusManager) Unsubscribe(callback FrameListener) {
+	bm.mu.Lock()
+	for ident := range bm.frameListeners {
+		bm.removeListener(ident, callback)
+	}
+	bm.wildcards = slices.DeleteFunc(bm.wildcards, func(w wildcardSubscription) bool {
+		return w.listener == callback
+	})
+	bm.mu.Unlock()
+	// Frames are dispatched under lock, so no new frame can be queued for callback
+	if dispatcher := bm.dispatcher.Load(); dispatcher != nil {
+		dispatcher.remove(callback)
+	}
+}
+
+// UnsubscribeFrom removes a single subscription made with [BusManager.Subscribe],
+// other subscriptions of the listener are kept.
+func (bm *BusManager) UnsubscribeFrom(ident uint32, mask uint32, rtr bool, callback FrameListener) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	mask &= CanSffMask
+	if mask != CanSffMask {
+		subscription := wildcardSubscription{ident: ident & mask, mask: mask, rtr: rtr, listener: callback}
+		bm.wildcards = slices.DeleteFunc(bm.wildcards, func(w wildcardSubscription) bool {
+			return w == subscription
+		})
+		return
+	}
+	ident = ident & CanSffMask
+	if rtr {
+		ident |= CanRtrFlag
+	}
+	bm.removeListener(ident, callback)
+}
+
+// Remove listener of a single COB-ID, lock should be held
+func (bm *BusManager) removeListener(ident uint32, callback FrameListener) {
+	listeners := bm.frameListeners[ident]
+	remaining := listeners[:0:0]
+	for _, cb := range listeners {
+		if cb != callback {
+			remaining = append(remaining, cb)
+		}
+	}
+	if len(remaining) == 0 {
+		delete(bm.frameListeners, ident)
+	} else {
+		bm.frameListeners[ident] = remaining
+	}
+}
+
+// Remove all the listeners, received frames are no longer dispatched.
+// See [BusManager.Unsubscribe] when the RX dispatcher is running.
+func (bm *BusManager) UnsubscribeAll() {
+	bm.mu.Lock()
+	clear(bm.frameListeners)
+	bm.wildcards = nil
+	bm.mu.Unlock()
+	if dispatcher := bm.dispatcher.Load(); dispatcher != nil {
+		dispatcher.removeAll()
+	}
+}
+
 // Get CAN error
 func (bm *BusManager) Error() uint16 {
 	bm.mu.Lock()
@@ -99,6 +287,29 @@ func NewBusManager(bus Bus) *BusManager {
 		logger:         slog.Default(),
 		frameListeners: make(map[uint32][]FrameListener),
 		canError:       0,
+		load:           newLoadMeter(),
 	}
 	return bm
 }
+
+// Subscription to several CAN IDs, either with a mask or with a range
+type wildcardSubscription struct {
+	ident    uint32
+	mask     uint32
+	first    uint32
+	last     uint32
+	isRange  bool
+	rtr      bool
+	listener FrameListener
+}
+
+func (w wildcardSubscription) matches(id uint32) bool {
+	if (id&CanRtrFlag != 0) != w.rtr {
+		return false
+	}
+	id &= CanSffMask
+	if w.isRange {
+		return id >= w.first && id <= w.last
+	}
+	return id&w.mask == w.ident
+}
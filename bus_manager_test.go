@@ -0,0 +1,57 @@
+package canopen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribe(t *testing.T) {
+	bm := NewBusManager(&slowBus{})
+	single := &gatedListener{}
+	other := &gatedListener{}
+	emcy := &gatedListener{}
+	pdo := &gatedListener{}
+	rtr := &gatedListener{}
+	assert.Nil(t, bm.Subscribe(0x181, 0x7FF, false, single))
+	assert.Nil(t, bm.Subscribe(0x181, 0x7FF, false, other))
+	assert.Nil(t, bm.Subscribe(0x80, 0x780, false, emcy))
+	assert.Nil(t, bm.SubscribeRange(0x180, 0x57F, false, pdo))
+	assert.Nil(t, bm.Subscribe(0x181, 0x7FF, false, pdo))
+	assert.Nil(t, bm.Subscribe(0x700, 0x780, true, rtr))
+	assert.Equal(t, ErrIllegalArgument, bm.SubscribeRange(0x57F, 0x180, false, pdo))
+
+	t.Run("dispatch", func(t *testing.T) {
+		for _, id := range []uint32{0x181, 0x85, 0x17F, 0x580, 0x4FF, 0x710, 0x710 | CanRtrFlag} {
+			bm.Handle(NewFrame(id, 0, 1))
+		}
+		assert.Equal(t, 1, single.count())
+		assert.Equal(t, 1, other.count())
+		assert.Equal(t, 1, emcy.count())
+		// Subscribed twice to 0x181 but received once
+		assert.Equal(t, 2, pdo.count())
+		assert.Equal(t, 1, rtr.count())
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		bm.UnsubscribeFrom(0x181, 0x7FF, false, single)
+		bm.UnsubscribeFrom(0x181, 0x7FF, false, pdo)
+		bm.UnsubscribeFrom(0x80, 0x780, false, emcy)
+		bm.Handle(NewFrame(0x181, 0, 1))
+		bm.Handle(NewFrame(0x81, 0, 1))
+		assert.Equal(t, 1, single.count())
+		assert.Equal(t, 2, other.count())
+		assert.Equal(t, 1, emcy.count())
+		// Range subscription is kept
+		assert.Equal(t, 3, pdo.count())
+		bm.Unsubscribe(pdo)
+		bm.Handle(NewFrame(0x181, 0, 1))
+		assert.Equal(t, 3, pdo.count())
+		assert.Equal(t, 3, other.count())
+		bm.UnsubscribeAll()
+		bm.Handle(NewFrame(0x181, 0, 1))
+		bm.Handle(NewFrame(0x700|CanRtrFlag, 0, 0))
+		assert.Equal(t, 3, other.count())
+		assert.Equal(t, 1, rtr.count())
+	})
+}
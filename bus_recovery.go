@@ -0,0 +1,151 @@
+package canopen
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultBusOffCheckPeriod = 100 * time.Millisecond
+	DefaultBusOffBackoff     = 100 * time.Millisecond
+	DefaultBusOffMaxBackoff  = 10 * time.Second
+)
+
+var ErrBusOffRecovery = errors.New("failed to recover from bus-off")
+
+// Options for bus-off recovery, see [BusManager.StartBusOffRecovery]
+type BusOffRecoveryOptions struct {
+	// Period for checking the error status of the bus
+	CheckPeriod time.Duration
+	// Delay before the first restart attempt, doubled after every failed attempt
+	Backoff time.Duration
+	// Maximum delay between two restart attempts
+	MaxBackoff time.Duration
+	// Maximum number of restart attempts, 0 for unlimited. Once reached,
+	// recovery is not attempted again until the bus leaves bus-off.
+	MaxAttempts int
+	// Called when bus-off is detected
+	OnBusOff func()
+	// Called once recovered, with the number of restart attempts
+	OnRecovered func(attempts int)
+	// Called when giving up after MaxAttempts, err is the last restart error if any
+	OnFailed func(err error)
+}
+
+// Error status of the bus, 0 if not reported by the bus and bus-off is not forced
+func (bm *BusManager) busErrorStatus() uint16 {
+	status := uint16(0)
+	if reporter, ok := bm.Bus().(BusErrorStatusReporter); ok {
+		status = reporter.ErrorStatus()
+	}
+	if injector := bm.faults.Load(); injector != nil && injector.BusOff() {
+		status |= CanErrorTxBusOff
+	}
+	return status
+}
+
+// Restart the CAN controller, buses that can not be restarted
+// are disconnected & connected again.
+func (bm *BusManager) restartBus() error {
+	bus := bm.Bus()
+	if restarter, ok := bus.(BusRestarter); ok {
+		return restarter.Restart()
+	}
+	_ = bus.Disconnect()
+	err := bus.Connect()
+	if err != nil {
+		return err
+	}
+	return bus.Subscribe(bm)
+}
+
+// StartBusOffRecovery enables automatic recovery from bus-off until ctx is cancelled.
+// The bus is checked periodically and, when bus-off is detected, it is restarted with
+// an increasing delay between attempts. This requires a bus implementing [BusErrorStatusReporter],
+// buses that do not implement [BusRestarter] are disconnected & connected again.
+func (bm *BusManager) StartBusOffRecovery(ctx context.Context, opts *BusOffRecoveryOptions) {
+	if opts == nil {
+		opts = &BusOffRecoveryOptions{}
+	}
+	if opts.CheckPeriod <= 0 {
+		opts.CheckPeriod = DefaultBusOffCheckPeriod
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultBusOffBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultBusOffMaxBackoff
+	}
+	go bm.recoverBusOff(ctx, *opts)
+}
+
+func (bm *BusManager) recoverBusOff(ctx context.Context, opts BusOffRecoveryOptions) {
+	failed := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(opts.CheckPeriod):
+		}
+		if bm.busErrorStatus()&CanErrorTxBusOff == 0 {
+			failed = false
+			continue
+		}
+		if failed {
+			continue
+		}
+		bm.logger.Warn("bus-off detected, starting recovery")
+		if opts.OnBusOff != nil {
+			opts.OnBusOff()
+		}
+		attempts, err := bm.restartUntilRecovered(ctx, opts)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			failed = true
+			bm.logger.Error("bus-off recovery failed", "attempts", attempts, "err", err)
+			if opts.OnFailed != nil {
+				opts.OnFailed(err)
+			}
+			continue
+		}
+		bm.logger.Info("recovered from bus-off", "attempts", attempts)
+		if opts.OnRecovered != nil {
+			opts.OnRecovered(attempts)
+		}
+	}
+}
+
+// Restart the bus with backoff until bus-off is cleared or max attempts are reached
+func (bm *BusManager) restartUntilRecovered(ctx context.Context, opts BusOffRecoveryOptions) (int, error) {
+	backoff := opts.Backoff
+	var lastErr error
+	for attempts := 1; opts.MaxAttempts == 0 || attempts <= opts.MaxAttempts; attempts++ {
+		select {
+		case <-ctx.Done():
+			return attempts, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, opts.MaxBackoff)
+		lastErr = bm.restartBus()
+		if lastErr != nil {
+			bm.logger.Warn("failed to restart bus", "attempt", attempts, "err", lastErr)
+			continue
+		}
+		// Controller may need some time for leaving bus-off
+		select {
+		case <-ctx.Done():
+			return attempts, ctx.Err()
+		case <-time.After(opts.CheckPeriod):
+		}
+		if bm.busErrorStatus()&CanErrorTxBusOff == 0 {
+			return attempts, nil
+		}
+	}
+	if lastErr != nil {
+		return opts.MaxAttempts, errors.Join(ErrBusOffRecovery, lastErr)
+	}
+	return opts.MaxAttempts, ErrBusOffRecovery
+}
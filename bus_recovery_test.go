@@ -0,0 +1,135 @@
+package canopen
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Bus going bus-off, recovering after a given number of restarts
+type busOffBus struct {
+	mu           sync.Mutex
+	status       uint16
+	restarts     int
+	recoverAfter int // 0 never recovers
+	restartErr   error
+}
+
+func (b *busOffBus) Connect(...any) error                   { return nil }
+func (b *busOffBus) Disconnect() error                      { return nil }
+func (b *busOffBus) Subscribe(callback FrameListener) error { return nil }
+func (b *busOffBus) Send(frame Frame) error                 { return nil }
+
+func (b *busOffBus) ErrorStatus() uint16 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.status
+}
+
+func (b *busOffBus) Restart() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.restarts++
+	if b.restartErr != nil {
+		return b.restartErr
+	}
+	if b.recoverAfter > 0 && b.restarts >= b.recoverAfter {
+		b.status &^= CanErrorTxBusOff
+	}
+	return nil
+}
+
+func (b *busOffBus) setBusOff() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.status |= CanErrorTxBusOff
+	b.restarts = 0
+}
+
+func TestBusOffRecovery(t *testing.T) {
+	opts := func(events chan string) *BusOffRecoveryOptions {
+		return &BusOffRecoveryOptions{
+			CheckPeriod: 5 * time.Millisecond,
+			Backoff:     5 * time.Millisecond,
+			MaxBackoff:  20 * time.Millisecond,
+			MaxAttempts: 4,
+			OnBusOff:    func() { events <- "busoff" },
+			OnRecovered: func(attempts int) {
+				assert.Equal(t, 3, attempts)
+				events <- "recovered"
+			},
+			OnFailed: func(err error) {
+				assert.ErrorIs(t, err, ErrBusOffRecovery)
+				events <- "failed"
+			},
+		}
+	}
+	expect := func(events chan string, expected string) {
+		select {
+		case event := <-events:
+			assert.Equal(t, expected, event)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected %v", expected)
+		}
+	}
+
+	t.Run("recovered with backoff", func(t *testing.T) {
+		bus := &busOffBus{recoverAfter: 3}
+		bm := NewBusManager(bus)
+		events := make(chan string, 10)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		bm.StartBusOffRecovery(ctx, opts(events))
+
+		assert.Nil(t, bm.Process())
+		assert.EqualValues(t, 0, bm.Error())
+		bus.setBusOff()
+		assert.Nil(t, bm.Process())
+		assert.EqualValues(t, CanErrorTxBusOff, bm.Error())
+		expect(events, "busoff")
+		expect(events, "recovered")
+		assert.Nil(t, bm.Process())
+		assert.EqualValues(t, 0, bm.Error())
+
+		// Recovered again on next bus-off
+		bus.setBusOff()
+		expect(events, "busoff")
+		expect(events, "recovered")
+	})
+
+	t.Run("give up after max attempts", func(t *testing.T) {
+		restartErr := errors.New("restart failed")
+		bus := &busOffBus{restartErr: restartErr}
+		bm := NewBusManager(bus)
+		events := make(chan string, 10)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		bm.StartBusOffRecovery(ctx, opts(events))
+		bus.setBusOff()
+		expect(events, "busoff")
+		expect(events, "failed")
+		bus.mu.Lock()
+		assert.Equal(t, 4, bus.restarts)
+		bus.mu.Unlock()
+		// Not retried while still in bus-off
+		time.Sleep(50 * time.Millisecond)
+		assert.Empty(t, events)
+	})
+
+	t.Run("stopped with context", func(t *testing.T) {
+		bus := &busOffBus{}
+		bm := NewBusManager(bus)
+		events := make(chan string, 10)
+		ctx, cancel := context.WithCancel(context.Background())
+		bm.StartBusOffRecovery(ctx, opts(events))
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		bus.setBusOff()
+		time.Sleep(50 * time.Millisecond)
+		assert.Empty(t, events)
+	})
+}
@@ -0,0 +1,195 @@
+package canopen
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Direction of a traced frame
+type TraceDirection uint8
+
+const (
+	TraceRx TraceDirection = 1 // Frame received from bus
+	TraceTx TraceDirection = 2 // Frame sent on bus
+)
+
+// A FrameTracer records the frames sent & received by a [BusManager],
+// see [BusManager.SetTracer]. Trace is called from the reception & transmission
+// goroutines and should not block.
+type FrameTracer interface {
+	Trace(timestamp time.Time, direction TraceDirection, frame Frame) error
+}
+
+// Holder of the current tracer, interfaces can not be stored atomically
+type tracerHolder struct {
+	tracer FrameTracer
+}
+
+// SetTracer starts recording all the frames sent & received with tracer,
+// this can be done at runtime. nil stops recording.
+// Write errors are logged and do not affect communication.
+func (bm *BusManager) SetTracer(tracer FrameTracer) {
+	if tracer == nil {
+		bm.tracer.Store(nil)
+		return
+	}
+	bm.tracer.Store(&tracerHolder{tracer: tracer})
+}
+
+// Record a frame with current tracer, if any
+func (bm *BusManager) trace(direction TraceDirection, frame Frame) {
+	holder := bm.tracer.Load()
+	if holder == nil {
+		return
+	}
+	err := holder.tracer.Trace(time.Now(), direction, frame)
+	if err != nil {
+		bm.logger.Warn("error tracing frame", "err", err)
+	}
+}
+
+// CandumpTracer writes frames in candump log format (candump -L), e.g.
+//
+//	(1700000000.123456) can0 601#4000100000000000 T
+//
+// Direction is appended as 'R' (received) or 'T' (sent), it is ignored by canplayer.
+type CandumpTracer struct {
+	mu    sync.Mutex
+	w     io.Writer
+	iface string
+}
+
+// Create a [CandumpTracer] writing to w, iface is the interface name written on each line
+func NewCandumpTracer(w io.Writer, iface string) *CandumpTracer {
+	return &CandumpTracer{w: w, iface: iface}
+}
+
+func (t *CandumpTracer) Trace(timestamp time.Time, direction TraceDirection, frame Frame) error {
+	var data string
+	if frame.ID&CanRtrFlag != 0 {
+		data = "R"
+	} else {
+		data = strings.ToUpper(hex.EncodeToString(frame.Data[:min(frame.DLC, 8)]))
+	}
+	dir := "R"
+	if direction == TraceTx {
+		dir = "T"
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := fmt.Fprintf(t.w, "(%d.%06d) %s %03X#%s %s\n",
+		timestamp.Unix(), timestamp.Nanosecond()/1000, t.iface, frame.ID&CanSffMask, data, dir)
+	return err
+}
+
+const (
+	linkTypeCANSocketCAN = 227 // LINKTYPE_CAN_SOCKETCAN
+	pcapSnapLen          = 16  // Size of a SocketCAN frame
+)
+
+// Frame in SocketCAN format, as expected by LINKTYPE_CAN_SOCKETCAN
+func socketCANFrame(frame Frame) []byte {
+	raw := make([]byte, pcapSnapLen)
+	// CAN id is big endian, RTR flag is the same as SocketCAN
+	binary.BigEndian.PutUint32(raw[0:4], frame.ID&(CanSffMask|CanRtrFlag))
+	raw[4] = min(frame.DLC, 8)
+	copy(raw[8:], frame.Data[:])
+	return raw
+}
+
+// PcapTracer writes frames in PCAP format (LINKTYPE_CAN_SOCKETCAN),
+// which can be opened with e.g. Wireshark. PCAP does not record direction,
+// see [PcapngTracer] for this.
+type PcapTracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Create a [PcapTracer] writing to w, the file header is written immediately
+func NewPcapTracer(w io.Writer) (*PcapTracer, error) {
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header[0:4], 0xA1B2C3D4) // Microsecond timestamps
+	binary.LittleEndian.PutUint16(header[4:6], 2)
+	binary.LittleEndian.PutUint16(header[6:8], 4)
+	binary.LittleEndian.PutUint32(header[16:20], pcapSnapLen)
+	binary.LittleEndian.PutUint32(header[20:24], linkTypeCANSocketCAN)
+	_, err := w.Write(header)
+	if err != nil {
+		return nil, err
+	}
+	return &PcapTracer{w: w}, nil
+}
+
+func (t *PcapTracer) Trace(timestamp time.Time, direction TraceDirection, frame Frame) error {
+	record := make([]byte, 16, 16+pcapSnapLen)
+	binary.LittleEndian.PutUint32(record[0:4], uint32(timestamp.Unix()))
+	binary.LittleEndian.PutUint32(record[4:8], uint32(timestamp.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(record[8:12], pcapSnapLen)
+	binary.LittleEndian.PutUint32(record[12:16], pcapSnapLen)
+	record = append(record, socketCANFrame(frame)...)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := t.w.Write(record)
+	return err
+}
+
+// PcapngTracer writes frames in PCAPNG format (LINKTYPE_CAN_SOCKETCAN),
+// which can be opened with e.g. Wireshark. Direction of each frame is recorded.
+type PcapngTracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Create a [PcapngTracer] writing to w, section & interface headers are written immediately
+func NewPcapngTracer(w io.Writer) (*PcapngTracer, error) {
+	// Section header block
+	shb := make([]byte, 28)
+	binary.LittleEndian.PutUint32(shb[0:4], 0x0A0D0D0A)
+	binary.LittleEndian.PutUint32(shb[4:8], 28)
+	binary.LittleEndian.PutUint32(shb[8:12], 0x1A2B3C4D)
+	binary.LittleEndian.PutUint16(shb[12:14], 1)
+	binary.LittleEndian.PutUint16(shb[14:16], 0)
+	binary.LittleEndian.PutUint64(shb[16:24], 0xFFFFFFFFFFFFFFFF) // Section length not specified
+	binary.LittleEndian.PutUint32(shb[24:28], 28)
+	// Interface description block, timestamps are in microseconds by default
+	idb := make([]byte, 20)
+	binary.LittleEndian.PutUint32(idb[0:4], 1)
+	binary.LittleEndian.PutUint32(idb[4:8], 20)
+	binary.LittleEndian.PutUint16(idb[8:10], linkTypeCANSocketCAN)
+	binary.LittleEndian.PutUint32(idb[12:16], pcapSnapLen)
+	binary.LittleEndian.PutUint32(idb[16:20], 20)
+	_, err := w.Write(append(shb, idb...))
+	if err != nil {
+		return nil, err
+	}
+	return &PcapngTracer{w: w}, nil
+}
+
+func (t *PcapngTracer) Trace(timestamp time.Time, direction TraceDirection, frame Frame) error {
+	// Enhanced packet block, with direction in epb_flags option
+	const length = 28 + pcapSnapLen + 12 + 4
+	block := make([]byte, 28, length)
+	binary.LittleEndian.PutUint32(block[0:4], 6)
+	binary.LittleEndian.PutUint32(block[4:8], length)
+	micros := uint64(timestamp.UnixMicro())
+	binary.LittleEndian.PutUint32(block[12:16], uint32(micros>>32))
+	binary.LittleEndian.PutUint32(block[16:20], uint32(micros))
+	binary.LittleEndian.PutUint32(block[20:24], pcapSnapLen)
+	binary.LittleEndian.PutUint32(block[24:28], pcapSnapLen)
+	block = append(block, socketCANFrame(frame)...)
+	// epb_flags, inbound is 1 and outbound is 2 like [TraceDirection], then end of options
+	block = binary.LittleEndian.AppendUint16(block, 2)
+	block = binary.LittleEndian.AppendUint16(block, 4)
+	block = binary.LittleEndian.AppendUint32(block, uint32(direction))
+	block = binary.LittleEndian.AppendUint32(block, 0)
+	block = binary.LittleEndian.AppendUint32(block, length)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := t.w.Write(block)
+	return err
+}
@@ -0,0 +1,75 @@
+package canopen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceCandump(t *testing.T) {
+	bm := NewBusManager(&slowBus{})
+	buffer := &bytes.Buffer{}
+	bm.SetTracer(NewCandumpTracer(buffer, "can0"))
+	frame := NewFrame(0x601, 0, 8)
+	frame.Data = [8]byte{0x40, 0x00, 0x10, 0x00}
+	assert.Nil(t, bm.Send(frame))
+	bm.Handle(NewFrame(0x701|CanRtrFlag, 0, 0))
+	bm.SetTracer(nil)
+	assert.Nil(t, bm.Send(frame))
+
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	assert.Regexp(t, `^\(\d+\.\d{6}\) can0 601#4000100000000000 T$`, string(lines[0]))
+	assert.Regexp(t, `^\(\d+\.\d{6}\) can0 701#R R$`, string(lines[1]))
+}
+
+func TestTracePcap(t *testing.T) {
+	timestamp := time.Unix(1700000000, 123456000)
+	frame := NewFrame(0x181, 0, 2)
+	frame.Data = [8]byte{0x12, 0x34}
+	socketCAN := []byte{0, 0, 0x01, 0x81, 2, 0, 0, 0, 0x12, 0x34, 0, 0, 0, 0, 0, 0}
+
+	t.Run("pcap", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		tracer, err := NewPcapTracer(buffer)
+		assert.Nil(t, err)
+		assert.Nil(t, tracer.Trace(timestamp, TraceTx, frame))
+		raw := buffer.Bytes()
+		assert.Len(t, raw, 24+16+16)
+		assert.EqualValues(t, 0xA1B2C3D4, binary.LittleEndian.Uint32(raw[0:4]))
+		assert.EqualValues(t, 227, binary.LittleEndian.Uint32(raw[20:24]))
+		assert.EqualValues(t, 1700000000, binary.LittleEndian.Uint32(raw[24:28]))
+		assert.EqualValues(t, 123456, binary.LittleEndian.Uint32(raw[28:32]))
+		assert.Equal(t, socketCAN, raw[40:])
+	})
+
+	t.Run("pcapng", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		tracer, err := NewPcapngTracer(buffer)
+		assert.Nil(t, err)
+		assert.Nil(t, tracer.Trace(timestamp, TraceRx, frame))
+		assert.Nil(t, tracer.Trace(timestamp, TraceTx, frame))
+		raw := buffer.Bytes()
+		// Walk blocks, total length is repeated at the end of each block
+		types := []uint32{}
+		flags := []uint32{}
+		for len(raw) > 0 {
+			length := binary.LittleEndian.Uint32(raw[4:8])
+			assert.Equal(t, length, binary.LittleEndian.Uint32(raw[length-4:length]))
+			blockType := binary.LittleEndian.Uint32(raw[0:4])
+			types = append(types, blockType)
+			if blockType == 6 {
+				assert.Equal(t, socketCAN, raw[28:44])
+				flags = append(flags, binary.LittleEndian.Uint32(raw[48:52]))
+				micros := uint64(binary.LittleEndian.Uint32(raw[12:16]))<<32 | uint64(binary.LittleEndian.Uint32(raw[16:20]))
+				assert.EqualValues(t, timestamp.UnixMicro(), micros)
+			}
+			raw = raw[length:]
+		}
+		assert.Equal(t, []uint32{0x0A0D0D0A, 1, 6, 6}, types)
+		assert.Equal(t, []uint32{1, 2}, flags)
+	})
+}
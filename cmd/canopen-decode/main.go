@@ -0,0 +1,83 @@
+// Decode CANopen frames of candump logs, or of a live CAN bus
+//
+//	candump -L can0 | go run ./cmd/canopen-decode
+//	go run ./cmd/canopen-decode capture.log
+//	go run ./cmd/canopen-decode -live -i socketcan -c can0
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/trace"
+)
+
+var DEFAULT_CAN_INTERFACE = "socketcan"
+var DEFAULT_CAN_CHANNEL = "can0"
+var DEFAULT_CAN_BITRATE = 500_000
+
+// Decode every line of r, invalid lines are written as is
+func decodeLog(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		record, err := trace.ParseCandumpLine(line)
+		if err != nil {
+			fmt.Fprintf(w, "%v (%v)\n", line, err)
+			continue
+		}
+		fmt.Fprintln(w, trace.FormatRecord(record))
+	}
+	return scanner.Err()
+}
+
+func main() {
+	live := flag.Bool("live", false, "decode frames of a live CAN bus instead of a log")
+	canInterface := flag.String("i", DEFAULT_CAN_INTERFACE, "CAN interface, with -live")
+	channel := flag.String("c", DEFAULT_CAN_CHANNEL, "CAN channel, with -live")
+	bitrate := flag.Int("b", DEFAULT_CAN_BITRATE, "CAN bitrate, with -live")
+	flag.Parse()
+
+	if *live {
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+		network := network.NewNetwork(nil)
+		network.SetLogger(logger)
+		err := network.Connect(*canInterface, *channel, *bitrate)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer network.Disconnect()
+		network.SetTracer(trace.NewDecodeTracer(os.Stdout))
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		<-interrupt
+		return
+	}
+
+	var input io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		input = f
+	}
+	err := decodeLog(input, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Go types of the supported CiA 301 data types, other
+// data types (e.g. DOMAIN) are not generated
+var goTypes = map[uint8]string{
+	od.BOOLEAN:        "bool",
+	od.INTEGER8:       "int8",
+	od.INTEGER16:      "int16",
+	od.INTEGER32:      "int32",
+	od.INTEGER64:      "int64",
+	od.UNSIGNED8:      "uint8",
+	od.UNSIGNED16:     "uint16",
+	od.UNSIGNED32:     "uint32",
+	od.UNSIGNED64:     "uint64",
+	od.REAL32:         "float32",
+	od.REAL64:         "float64",
+	od.VISIBLE_STRING: "string",
+	od.UNICODE_STRING: "node.UnicodeString",
+	od.OCTET_STRING:   "[]byte",
+}
+
+// A getter and / or setter of an OD variable. Elements of an
+// ARRAY are accessed with a single indexed accessor.
+type accessor struct {
+	Name        string
+	Description string
+	Index       uint16
+	SubIndex    uint8
+	GoType      string
+	Readable    bool
+	Writable    bool
+	Indexed     bool
+}
+
+type generator struct {
+	Package string
+	Type    string
+	Source  string
+	// Accessors sorted by index & subindex
+	Accessors []accessor
+	names     map[string]bool
+}
+
+// Convert an OD name to an exported Go identifier, e.g.
+// "Node-ID of the SDO server" becomes "NodeIDOfTheSDOServer"
+func identifier(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	id := b.String()
+	if id == "" || unicode.IsDigit([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+// Add an accessor, names that are already used are
+// suffixed with the subindex of the variable
+func (g *generator) add(acc accessor) {
+	if g.names[acc.Name] || g.names["Set"+acc.Name] {
+		acc.Name = fmt.Sprintf("%vSub%v", acc.Name, acc.SubIndex)
+	}
+	g.names[acc.Name] = true
+	if acc.Writable {
+		g.names["Set"+acc.Name] = true
+	}
+	g.Accessors = append(g.Accessors, acc)
+}
+
+// Create an accessor for a variable, ok is false if data type is not supported
+func newAccessor(name string, description string, index uint16, variable *od.Variable) (accessor, bool) {
+	goType, ok := goTypes[variable.DataType]
+	if !ok {
+		return accessor{}, false
+	}
+	return accessor{
+		Name:        name,
+		Description: description,
+		Index:       index,
+		SubIndex:    variable.SubIndex,
+		GoType:      goType,
+		Readable:    variable.Attribute&od.AttributeSdoR != 0,
+		Writable:    variable.Attribute&od.AttributeSdoW != 0,
+	}, ok
+}
+
+// Add the accessors of an entry, entryName should be unique
+func (g *generator) addEntry(entry *od.Entry, entryName string) {
+	switch entry.ObjectType {
+	case od.ObjectTypeVAR, od.ObjectTypeDOMAIN:
+		variable, err := entry.SubIndex(0)
+		if err != nil {
+			return
+		}
+		if acc, ok := newAccessor(entryName, entry.Name, entry.Index, variable); ok {
+			g.add(acc)
+		}
+	case od.ObjectTypeARRAY, od.ObjectTypeRECORD:
+		// Records can have holes in subindexes
+		for sub := range 256 {
+			variable, err := entry.SubIndex(uint8(sub))
+			if err != nil {
+				continue
+			}
+			description := entry.Name + " / " + variable.Name
+			acc, ok := newAccessor(entryName+identifier(variable.Name), description, entry.Index, variable)
+			if !ok {
+				continue
+			}
+			if entry.ObjectType == od.ObjectTypeARRAY && variable.SubIndex > 0 {
+				// Array elements share the same data type, generated once
+				if variable.SubIndex > 1 {
+					continue
+				}
+				acc.Name = entryName
+				acc.Description = entry.Name
+				acc.Indexed = true
+			}
+			g.add(acc)
+		}
+	}
+}
+
+// Generate the typed accessors of an OD, source is the EDS file name
+// used in comments. Returned code is formatted.
+func generate(odict *od.ObjectDictionary, pkg string, typeName string, source string) ([]byte, error) {
+	g := &generator{Package: pkg, Type: typeName, Source: source, names: map[string]bool{}}
+	indexes := make([]uint16, 0, len(odict.Entries()))
+	for index := range odict.Entries() {
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+	// Entries with the same name (e.g. PDO parameters) are suffixed with their index
+	count := map[string]int{}
+	for _, entry := range odict.Entries() {
+		count[identifier(entry.Name)]++
+	}
+	for _, index := range indexes {
+		entry := odict.Index(index)
+		entryName := identifier(entry.Name)
+		if count[entryName] > 1 {
+			entryName = fmt.Sprintf("%v%04X", entryName, index)
+		}
+		g.addEntry(entry, entryName)
+	}
+	var buf bytes.Buffer
+	err := codeTemplate.Execute(&buf, g)
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+var codeTemplate = template.Must(template.New("code").Funcs(template.FuncMap{
+	"receiver": func(typeName string) string { return strings.ToLower(typeName[:1]) },
+}).Parse(`// Code generated by canopen-gen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/samsamfire/gocanopen/pkg/node"
+
+{{$t := .Type}}{{$r := receiver .Type}}
+// {{$t}} gives typed access to the objects of {{.Source}}
+type {{$t}} struct {
+	accessor node.ObjectAccessor
+}
+
+// Create a new [{{$t}}] backed by a node, e.g. a [node.LocalNode] or a [node.RemoteNode]
+func New{{$t}}(accessor node.ObjectAccessor) *{{$t}} {
+	return &{{$t}}{accessor: accessor}
+}
+{{range .Accessors}}{{if .Indexed}}{{if .Readable}}
+// {{.Name}} reads an element of "{{.Description}}" (x{{printf "%X" .Index}})
+func ({{$r}} *{{$t}}) {{.Name}}(subIndex uint8) ({{.GoType}}, error) {
+	return node.ReadValue[{{.GoType}}]({{$r}}.accessor, 0x{{printf "%04X" .Index}}, subIndex)
+}
+{{end}}{{if .Writable}}
+// Set{{.Name}} writes an element of "{{.Description}}" (x{{printf "%X" .Index}})
+func ({{$r}} *{{$t}}) Set{{.Name}}(subIndex uint8, value {{.GoType}}) error {
+	return node.WriteValue({{$r}}.accessor, 0x{{printf "%04X" .Index}}, subIndex, value)
+}
+{{end}}{{else}}{{if .Readable}}
+// {{.Name}} reads "{{.Description}}" (x{{printf "%X" .Index}}:{{printf "%02X" .SubIndex}})
+func ({{$r}} *{{$t}}) {{.Name}}() ({{.GoType}}, error) {
+	return node.ReadValue[{{.GoType}}]({{$r}}.accessor, 0x{{printf "%04X" .Index}}, 0x{{printf "%02X" .SubIndex}})
+}
+{{end}}{{if .Writable}}
+// Set{{.Name}} writes "{{.Description}}" (x{{printf "%X" .Index}}:{{printf "%02X" .SubIndex}})
+func ({{$r}} *{{$t}}) Set{{.Name}}(value {{.GoType}}) error {
+	return node.WriteValue({{$r}}.accessor, 0x{{printf "%04X" .Index}}, 0x{{printf "%02X" .SubIndex}}, value)
+}
+{{end}}{{end}}{{end}}`))
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "NodeIDOfTheSDOServer", identifier("Node-ID of the SDO server"))
+	assert.Equal(t, "HighestSubIndexSupported", identifier("Highest sub-index supported"))
+	assert.Equal(t, "X1stValue", identifier("1st value"))
+	assert.Equal(t, "X", identifier(" - "))
+}
+
+// Generated example should be kept up to date with the generator
+func TestGenerateExample(t *testing.T) {
+	odict, err := od.Parse("../../pkg/od/base.eds", 0)
+	assert.Nil(t, err)
+	code, err := generate(odict, "main", "Device", "base.eds")
+	assert.Nil(t, err)
+	expected, err := os.ReadFile("../../examples/generated/device_gen.go")
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(code))
+}
@@ -0,0 +1,46 @@
+// Generate a Go struct with typed getters & setters for the entries of an EDS,
+// backed by a local or remote node. Typically used with go:generate :
+//
+//	//go:generate go run github.com/samsamfire/gocanopen/cmd/canopen-gen -eds device.eds -type Device -o device_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+func main() {
+	eds := flag.String("eds", "", "EDS file to generate accessors for")
+	typeName := flag.String("type", "Device", "name of the generated type")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package of the generated file, defaults to $GOPACKAGE")
+	output := flag.String("o", "", "output file, defaults to stdout")
+	flag.Parse()
+
+	if *eds == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	odict, err := od.Parse(*eds, 0)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	code, err := generate(odict, *pkg, *typeName, filepath.Base(*eds))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		os.Stdout.Write(code)
+		return
+	}
+	err = os.WriteFile(*output, code, 0o644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
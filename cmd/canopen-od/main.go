@@ -0,0 +1,74 @@
+// Validate EDS / DCF files, or compare two of them.
+// Validation reports format problems with their line number, then content problems.
+//
+//	go run ./cmd/canopen-od validate device.eds
+//	go run ./cmd/canopen-od diff device_v1.eds device_v2.eds
+//
+// Exit code is 1 if problems or differences are found.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+const usage = `usage :
+  canopen-od validate <file.eds>
+  canopen-od diff <a.eds> <b.eds>`
+
+// Run a command, returns true if problems or differences were found
+func run(args []string, w io.Writer) (bool, error) {
+	if len(args) == 2 && args[0] == "validate" {
+		// Format problems are reported before the content problems
+		odict, diagnostics, err := od.ParseWithDiagnostics(args[1], 0, od.ParseModeLenient)
+		if err != nil {
+			return false, err
+		}
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintln(w, diagnostic)
+		}
+		err = odict.Validate()
+		if err == nil && len(diagnostics) == 0 {
+			fmt.Fprintln(w, "ok")
+			return false, nil
+		}
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			for _, problem := range joined.Unwrap() {
+				fmt.Fprintln(w, problem)
+			}
+		}
+		return true, nil
+	}
+	if len(args) == 3 && args[0] == "diff" {
+		a, err := od.Parse(args[1], 0)
+		if err != nil {
+			return false, err
+		}
+		b, err := od.Parse(args[2], 0)
+		if err != nil {
+			return false, err
+		}
+		diffs := od.Compare(a, b)
+		for _, diff := range diffs {
+			fmt.Fprintln(w, diff)
+		}
+		return len(diffs) > 0, nil
+	}
+	return false, errors.New(usage)
+}
+
+func main() {
+	found, err := run(os.Args[1:], os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if found {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,113 @@
+// Simulate CANopen devices, for developing master applications without hardware
+//
+//	go run ./cmd/simulator -eds device.eds -ids 0x10,0x11
+//	go run ./cmd/simulator -i virtualcan -c localhost:18888 -config simulation.json
+//
+// Example configuration, see [simulator.Config] :
+//
+//	{
+//	  "devices": [{
+//	    "eds": "device.eds",
+//	    "node-ids": [16, 17],
+//	    "generators": [{"index": "0x6401", "subindex": "1", "type": "sine", "offset": 1000, "amplitude": 500, "period": "2s"}],
+//	    "faults": [{"type": "heartbeat", "at": "10s", "duration": "5s"}]
+//	  }]
+//	}
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/simulator"
+)
+
+var DEFAULT_CAN_INTERFACE = "socketcan"
+var DEFAULT_CAN_CHANNEL = "can0"
+var DEFAULT_CAN_BITRATE = 500_000
+
+// Parse a comma separated list of node ids
+func parseNodeIds(value string) ([]uint8, error) {
+	ids := make([]uint8, 0)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(field, 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node id %v : %w", field, err)
+		}
+		ids = append(ids, uint8(id))
+	}
+	return ids, nil
+}
+
+func run() error {
+	canInterface := flag.String("i", DEFAULT_CAN_INTERFACE, "CAN interface")
+	channel := flag.String("c", DEFAULT_CAN_CHANNEL, "CAN channel")
+	bitrate := flag.Int("b", DEFAULT_CAN_BITRATE, "CAN bitrate")
+	configPath := flag.String("config", "", "JSON configuration of the simulated devices")
+	eds := flag.String("eds", "", "EDS of simulated devices without generators or faults, with -ids")
+	ids := flag.String("ids", "", "comma separated node ids of devices created from -eds")
+	period := flag.Duration("period", simulator.DefaultUpdatePeriod, "update period of generated values")
+	flag.Parse()
+
+	config := simulator.Config{}
+	if *configPath != "" {
+		var err error
+		config, err = simulator.LoadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+	}
+	if *eds != "" {
+		nodeIds, err := parseNodeIds(*ids)
+		if err != nil {
+			return err
+		}
+		config.Devices = append(config.Devices, simulator.DeviceConfig{EDS: *eds, NodeIds: nodeIds})
+	}
+	if len(config.Devices) == 0 {
+		return fmt.Errorf("no simulated devices, use -config or -eds")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	network := network.NewNetwork(nil)
+	network.SetLogger(logger)
+	err := network.Connect(*canInterface, *channel, *bitrate)
+	if err != nil {
+		return err
+	}
+	defer network.Disconnect()
+
+	sim := simulator.NewSimulator(&network, logger)
+	err = sim.Apply(config)
+	if err != nil {
+		return err
+	}
+	for _, device := range sim.Devices() {
+		logger.Info("simulating device", "id", device.GetID())
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	err = sim.Run(ctx, *period)
+	if err == context.Canceled {
+		return nil
+	}
+	return err
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
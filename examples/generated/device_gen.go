@@ -0,0 +1,1605 @@
+// Code generated by canopen-gen from base.eds. DO NOT EDIT.
+
+package main
+
+import "github.com/samsamfire/gocanopen/pkg/node"
+
+// Device gives typed access to the objects of base.eds
+type Device struct {
+	accessor node.ObjectAccessor
+}
+
+// Create a new [Device] backed by a node, e.g. a [node.LocalNode] or a [node.RemoteNode]
+func NewDevice(accessor node.ObjectAccessor) *Device {
+	return &Device{accessor: accessor}
+}
+
+// DeviceType reads "Device type" (x1000:00)
+func (d *Device) DeviceType() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1000, 0x00)
+}
+
+// ErrorRegister reads "Error register" (x1001:00)
+func (d *Device) ErrorRegister() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1001, 0x00)
+}
+
+// PreDefinedErrorFieldNumberOfErrors reads "Pre-defined error field / Number of errors" (x1003:00)
+func (d *Device) PreDefinedErrorFieldNumberOfErrors() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1003, 0x00)
+}
+
+// SetPreDefinedErrorFieldNumberOfErrors writes "Pre-defined error field / Number of errors" (x1003:00)
+func (d *Device) SetPreDefinedErrorFieldNumberOfErrors(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1003, 0x00, value)
+}
+
+// PreDefinedErrorField reads an element of "Pre-defined error field" (x1003)
+func (d *Device) PreDefinedErrorField(subIndex uint8) (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1003, subIndex)
+}
+
+// COBIDSYNCMessage reads "COB-ID SYNC message" (x1005:00)
+func (d *Device) COBIDSYNCMessage() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1005, 0x00)
+}
+
+// SetCOBIDSYNCMessage writes "COB-ID SYNC message" (x1005:00)
+func (d *Device) SetCOBIDSYNCMessage(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1005, 0x00, value)
+}
+
+// CommunicationCyclePeriod reads "Communication cycle period" (x1006:00)
+func (d *Device) CommunicationCyclePeriod() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1006, 0x00)
+}
+
+// SetCommunicationCyclePeriod writes "Communication cycle period" (x1006:00)
+func (d *Device) SetCommunicationCyclePeriod(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1006, 0x00, value)
+}
+
+// SynchronousWindowLength reads "Synchronous window length" (x1007:00)
+func (d *Device) SynchronousWindowLength() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1007, 0x00)
+}
+
+// SetSynchronousWindowLength writes "Synchronous window length" (x1007:00)
+func (d *Device) SetSynchronousWindowLength(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1007, 0x00, value)
+}
+
+// ManufacturerDeviceName reads "Manufacturer device name" (x1008:00)
+func (d *Device) ManufacturerDeviceName() (string, error) {
+	return node.ReadValue[string](d.accessor, 0x1008, 0x00)
+}
+
+// ManufacturerHardwareVersion reads "Manufacturer hardware version" (x1009:00)
+func (d *Device) ManufacturerHardwareVersion() (string, error) {
+	return node.ReadValue[string](d.accessor, 0x1009, 0x00)
+}
+
+// ManufacturerSoftwareVersion reads "Manufacturer software version" (x100A:00)
+func (d *Device) ManufacturerSoftwareVersion() (string, error) {
+	return node.ReadValue[string](d.accessor, 0x100A, 0x00)
+}
+
+// StoreParametersHighestSubIndexSupported reads "Store parameters / Highest sub-index supported" (x1010:00)
+func (d *Device) StoreParametersHighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1010, 0x00)
+}
+
+// StoreParameters reads an element of "Store parameters" (x1010)
+func (d *Device) StoreParameters(subIndex uint8) (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1010, subIndex)
+}
+
+// SetStoreParameters writes an element of "Store parameters" (x1010)
+func (d *Device) SetStoreParameters(subIndex uint8, value uint32) error {
+	return node.WriteValue(d.accessor, 0x1010, subIndex, value)
+}
+
+// RestoreDefaultParametersHighestSubIndexSupported reads "Restore default parameters / Highest sub-index supported" (x1011:00)
+func (d *Device) RestoreDefaultParametersHighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1011, 0x00)
+}
+
+// RestoreDefaultParameters reads an element of "Restore default parameters" (x1011)
+func (d *Device) RestoreDefaultParameters(subIndex uint8) (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1011, subIndex)
+}
+
+// SetRestoreDefaultParameters writes an element of "Restore default parameters" (x1011)
+func (d *Device) SetRestoreDefaultParameters(subIndex uint8, value uint32) error {
+	return node.WriteValue(d.accessor, 0x1011, subIndex, value)
+}
+
+// COBIDTimeStampObject reads "COB-ID time stamp object" (x1012:00)
+func (d *Device) COBIDTimeStampObject() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1012, 0x00)
+}
+
+// SetCOBIDTimeStampObject writes "COB-ID time stamp object" (x1012:00)
+func (d *Device) SetCOBIDTimeStampObject(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1012, 0x00, value)
+}
+
+// COBIDEMCY reads "COB-ID EMCY" (x1014:00)
+func (d *Device) COBIDEMCY() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1014, 0x00)
+}
+
+// SetCOBIDEMCY writes "COB-ID EMCY" (x1014:00)
+func (d *Device) SetCOBIDEMCY(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1014, 0x00, value)
+}
+
+// InhibitTimeEMCY reads "Inhibit time EMCY" (x1015:00)
+func (d *Device) InhibitTimeEMCY() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1015, 0x00)
+}
+
+// SetInhibitTimeEMCY writes "Inhibit time EMCY" (x1015:00)
+func (d *Device) SetInhibitTimeEMCY(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1015, 0x00, value)
+}
+
+// ConsumerHeartbeatTimeHighestSubIndexSupported reads "Consumer heartbeat time / Highest sub-index supported" (x1016:00)
+func (d *Device) ConsumerHeartbeatTimeHighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1016, 0x00)
+}
+
+// ConsumerHeartbeatTime reads an element of "Consumer heartbeat time" (x1016)
+func (d *Device) ConsumerHeartbeatTime(subIndex uint8) (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1016, subIndex)
+}
+
+// SetConsumerHeartbeatTime writes an element of "Consumer heartbeat time" (x1016)
+func (d *Device) SetConsumerHeartbeatTime(subIndex uint8, value uint32) error {
+	return node.WriteValue(d.accessor, 0x1016, subIndex, value)
+}
+
+// ProducerHeartbeatTime reads "Producer heartbeat time" (x1017:00)
+func (d *Device) ProducerHeartbeatTime() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1017, 0x00)
+}
+
+// SetProducerHeartbeatTime writes "Producer heartbeat time" (x1017:00)
+func (d *Device) SetProducerHeartbeatTime(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1017, 0x00, value)
+}
+
+// IdentityHighestSubIndexSupported reads "Identity / Highest sub-index supported" (x1018:00)
+func (d *Device) IdentityHighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1018, 0x00)
+}
+
+// IdentityVendorID reads "Identity / Vendor-ID" (x1018:01)
+func (d *Device) IdentityVendorID() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1018, 0x01)
+}
+
+// IdentityProductCode reads "Identity / Product code" (x1018:02)
+func (d *Device) IdentityProductCode() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1018, 0x02)
+}
+
+// IdentityRevisionNumber reads "Identity / Revision number" (x1018:03)
+func (d *Device) IdentityRevisionNumber() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1018, 0x03)
+}
+
+// IdentitySerialNumber reads "Identity / Serial number" (x1018:04)
+func (d *Device) IdentitySerialNumber() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1018, 0x04)
+}
+
+// SynchronousCounterOverflowValue reads "Synchronous counter overflow value" (x1019:00)
+func (d *Device) SynchronousCounterOverflowValue() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1019, 0x00)
+}
+
+// SetSynchronousCounterOverflowValue writes "Synchronous counter overflow value" (x1019:00)
+func (d *Device) SetSynchronousCounterOverflowValue(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1019, 0x00, value)
+}
+
+// StoreEDS reads "Store EDS" (x1021:00)
+func (d *Device) StoreEDS() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1021, 0x00)
+}
+
+// SetStoreEDS writes "Store EDS" (x1021:00)
+func (d *Device) SetStoreEDS(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1021, 0x00, value)
+}
+
+// StorageFormat reads "Storage Format" (x1022:00)
+func (d *Device) StorageFormat() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1022, 0x00)
+}
+
+// SetStorageFormat writes "Storage Format" (x1022:00)
+func (d *Device) SetStorageFormat(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1022, 0x00, value)
+}
+
+// SDOServerParameterHighestSubIndexSupported reads "SDO server parameter / Highest sub-index supported" (x1200:00)
+func (d *Device) SDOServerParameterHighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1200, 0x00)
+}
+
+// SDOServerParameterCOBIDClientToServerRx reads "SDO server parameter / COB-ID client to server (rx)" (x1200:01)
+func (d *Device) SDOServerParameterCOBIDClientToServerRx() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1200, 0x01)
+}
+
+// SDOServerParameterCOBIDServerToClientTx reads "SDO server parameter / COB-ID server to client (tx)" (x1200:02)
+func (d *Device) SDOServerParameterCOBIDServerToClientTx() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1200, 0x02)
+}
+
+// SDOClientParameterHighestSubIndexSupported reads "SDO client parameter / Highest sub-index supported" (x1280:00)
+func (d *Device) SDOClientParameterHighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1280, 0x00)
+}
+
+// SDOClientParameterCOBIDClientToServerTx reads "SDO client parameter / COB-ID client to server (tx)" (x1280:01)
+func (d *Device) SDOClientParameterCOBIDClientToServerTx() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1280, 0x01)
+}
+
+// SetSDOClientParameterCOBIDClientToServerTx writes "SDO client parameter / COB-ID client to server (tx)" (x1280:01)
+func (d *Device) SetSDOClientParameterCOBIDClientToServerTx(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1280, 0x01, value)
+}
+
+// SDOClientParameterCOBIDServerToClientRx reads "SDO client parameter / COB-ID server to client (rx)" (x1280:02)
+func (d *Device) SDOClientParameterCOBIDServerToClientRx() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1280, 0x02)
+}
+
+// SetSDOClientParameterCOBIDServerToClientRx writes "SDO client parameter / COB-ID server to client (rx)" (x1280:02)
+func (d *Device) SetSDOClientParameterCOBIDServerToClientRx(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1280, 0x02, value)
+}
+
+// SDOClientParameterNodeIDOfTheSDOServer reads "SDO client parameter / Node-ID of the SDO server" (x1280:03)
+func (d *Device) SDOClientParameterNodeIDOfTheSDOServer() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1280, 0x03)
+}
+
+// SetSDOClientParameterNodeIDOfTheSDOServer writes "SDO client parameter / Node-ID of the SDO server" (x1280:03)
+func (d *Device) SetSDOClientParameterNodeIDOfTheSDOServer(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1280, 0x03, value)
+}
+
+// RPDOCommunicationParameter1400HighestSubIndexSupported reads "RPDO communication parameter / Highest sub-index supported" (x1400:00)
+func (d *Device) RPDOCommunicationParameter1400HighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1400, 0x00)
+}
+
+// RPDOCommunicationParameter1400COBIDUsedByRPDO reads "RPDO communication parameter / COB-ID used by RPDO" (x1400:01)
+func (d *Device) RPDOCommunicationParameter1400COBIDUsedByRPDO() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1400, 0x01)
+}
+
+// SetRPDOCommunicationParameter1400COBIDUsedByRPDO writes "RPDO communication parameter / COB-ID used by RPDO" (x1400:01)
+func (d *Device) SetRPDOCommunicationParameter1400COBIDUsedByRPDO(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1400, 0x01, value)
+}
+
+// RPDOCommunicationParameter1400TransmissionType reads "RPDO communication parameter / Transmission type" (x1400:02)
+func (d *Device) RPDOCommunicationParameter1400TransmissionType() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1400, 0x02)
+}
+
+// SetRPDOCommunicationParameter1400TransmissionType writes "RPDO communication parameter / Transmission type" (x1400:02)
+func (d *Device) SetRPDOCommunicationParameter1400TransmissionType(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1400, 0x02, value)
+}
+
+// RPDOCommunicationParameter1400InhibitTime reads "RPDO communication parameter / Inhibit time" (x1400:03)
+func (d *Device) RPDOCommunicationParameter1400InhibitTime() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1400, 0x03)
+}
+
+// SetRPDOCommunicationParameter1400InhibitTime writes "RPDO communication parameter / Inhibit time" (x1400:03)
+func (d *Device) SetRPDOCommunicationParameter1400InhibitTime(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1400, 0x03, value)
+}
+
+// RPDOCommunicationParameter1400Reserved reads "RPDO communication parameter / Reserved" (x1400:04)
+func (d *Device) RPDOCommunicationParameter1400Reserved() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1400, 0x04)
+}
+
+// SetRPDOCommunicationParameter1400Reserved writes "RPDO communication parameter / Reserved" (x1400:04)
+func (d *Device) SetRPDOCommunicationParameter1400Reserved(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1400, 0x04, value)
+}
+
+// RPDOCommunicationParameter1400EventTimer reads "RPDO communication parameter / Event timer" (x1400:05)
+func (d *Device) RPDOCommunicationParameter1400EventTimer() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1400, 0x05)
+}
+
+// SetRPDOCommunicationParameter1400EventTimer writes "RPDO communication parameter / Event timer" (x1400:05)
+func (d *Device) SetRPDOCommunicationParameter1400EventTimer(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1400, 0x05, value)
+}
+
+// RPDOCommunicationParameter1401HighestSubIndexSupported reads "RPDO communication parameter / Highest sub-index supported" (x1401:00)
+func (d *Device) RPDOCommunicationParameter1401HighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1401, 0x00)
+}
+
+// RPDOCommunicationParameter1401COBIDUsedByRPDO reads "RPDO communication parameter / COB-ID used by RPDO" (x1401:01)
+func (d *Device) RPDOCommunicationParameter1401COBIDUsedByRPDO() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1401, 0x01)
+}
+
+// SetRPDOCommunicationParameter1401COBIDUsedByRPDO writes "RPDO communication parameter / COB-ID used by RPDO" (x1401:01)
+func (d *Device) SetRPDOCommunicationParameter1401COBIDUsedByRPDO(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1401, 0x01, value)
+}
+
+// RPDOCommunicationParameter1401TransmissionType reads "RPDO communication parameter / Transmission type" (x1401:02)
+func (d *Device) RPDOCommunicationParameter1401TransmissionType() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1401, 0x02)
+}
+
+// SetRPDOCommunicationParameter1401TransmissionType writes "RPDO communication parameter / Transmission type" (x1401:02)
+func (d *Device) SetRPDOCommunicationParameter1401TransmissionType(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1401, 0x02, value)
+}
+
+// RPDOCommunicationParameter1401InhibitTime reads "RPDO communication parameter / Inhibit time" (x1401:03)
+func (d *Device) RPDOCommunicationParameter1401InhibitTime() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1401, 0x03)
+}
+
+// SetRPDOCommunicationParameter1401InhibitTime writes "RPDO communication parameter / Inhibit time" (x1401:03)
+func (d *Device) SetRPDOCommunicationParameter1401InhibitTime(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1401, 0x03, value)
+}
+
+// RPDOCommunicationParameter1401Reserved reads "RPDO communication parameter / Reserved" (x1401:04)
+func (d *Device) RPDOCommunicationParameter1401Reserved() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1401, 0x04)
+}
+
+// SetRPDOCommunicationParameter1401Reserved writes "RPDO communication parameter / Reserved" (x1401:04)
+func (d *Device) SetRPDOCommunicationParameter1401Reserved(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1401, 0x04, value)
+}
+
+// RPDOCommunicationParameter1401EventTimer reads "RPDO communication parameter / Event timer" (x1401:05)
+func (d *Device) RPDOCommunicationParameter1401EventTimer() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1401, 0x05)
+}
+
+// SetRPDOCommunicationParameter1401EventTimer writes "RPDO communication parameter / Event timer" (x1401:05)
+func (d *Device) SetRPDOCommunicationParameter1401EventTimer(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1401, 0x05, value)
+}
+
+// RPDOCommunicationParameter1402HighestSubIndexSupported reads "RPDO communication parameter / Highest sub-index supported" (x1402:00)
+func (d *Device) RPDOCommunicationParameter1402HighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1402, 0x00)
+}
+
+// RPDOCommunicationParameter1402COBIDUsedByRPDO reads "RPDO communication parameter / COB-ID used by RPDO" (x1402:01)
+func (d *Device) RPDOCommunicationParameter1402COBIDUsedByRPDO() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1402, 0x01)
+}
+
+// SetRPDOCommunicationParameter1402COBIDUsedByRPDO writes "RPDO communication parameter / COB-ID used by RPDO" (x1402:01)
+func (d *Device) SetRPDOCommunicationParameter1402COBIDUsedByRPDO(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1402, 0x01, value)
+}
+
+// RPDOCommunicationParameter1402TransmissionType reads "RPDO communication parameter / Transmission type" (x1402:02)
+func (d *Device) RPDOCommunicationParameter1402TransmissionType() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1402, 0x02)
+}
+
+// SetRPDOCommunicationParameter1402TransmissionType writes "RPDO communication parameter / Transmission type" (x1402:02)
+func (d *Device) SetRPDOCommunicationParameter1402TransmissionType(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1402, 0x02, value)
+}
+
+// RPDOCommunicationParameter1402InhibitTime reads "RPDO communication parameter / Inhibit time" (x1402:03)
+func (d *Device) RPDOCommunicationParameter1402InhibitTime() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1402, 0x03)
+}
+
+// SetRPDOCommunicationParameter1402InhibitTime writes "RPDO communication parameter / Inhibit time" (x1402:03)
+func (d *Device) SetRPDOCommunicationParameter1402InhibitTime(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1402, 0x03, value)
+}
+
+// RPDOCommunicationParameter1402Reserved reads "RPDO communication parameter / Reserved" (x1402:04)
+func (d *Device) RPDOCommunicationParameter1402Reserved() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1402, 0x04)
+}
+
+// SetRPDOCommunicationParameter1402Reserved writes "RPDO communication parameter / Reserved" (x1402:04)
+func (d *Device) SetRPDOCommunicationParameter1402Reserved(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1402, 0x04, value)
+}
+
+// RPDOCommunicationParameter1402EventTimer reads "RPDO communication parameter / Event timer" (x1402:05)
+func (d *Device) RPDOCommunicationParameter1402EventTimer() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1402, 0x05)
+}
+
+// SetRPDOCommunicationParameter1402EventTimer writes "RPDO communication parameter / Event timer" (x1402:05)
+func (d *Device) SetRPDOCommunicationParameter1402EventTimer(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1402, 0x05, value)
+}
+
+// RPDOCommunicationParameter1403HighestSubIndexSupported reads "RPDO communication parameter / Highest sub-index supported" (x1403:00)
+func (d *Device) RPDOCommunicationParameter1403HighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1403, 0x00)
+}
+
+// RPDOCommunicationParameter1403COBIDUsedByRPDO reads "RPDO communication parameter / COB-ID used by RPDO" (x1403:01)
+func (d *Device) RPDOCommunicationParameter1403COBIDUsedByRPDO() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1403, 0x01)
+}
+
+// SetRPDOCommunicationParameter1403COBIDUsedByRPDO writes "RPDO communication parameter / COB-ID used by RPDO" (x1403:01)
+func (d *Device) SetRPDOCommunicationParameter1403COBIDUsedByRPDO(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1403, 0x01, value)
+}
+
+// RPDOCommunicationParameter1403TransmissionType reads "RPDO communication parameter / Transmission type" (x1403:02)
+func (d *Device) RPDOCommunicationParameter1403TransmissionType() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1403, 0x02)
+}
+
+// SetRPDOCommunicationParameter1403TransmissionType writes "RPDO communication parameter / Transmission type" (x1403:02)
+func (d *Device) SetRPDOCommunicationParameter1403TransmissionType(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1403, 0x02, value)
+}
+
+// RPDOCommunicationParameter1403InhibitTime reads "RPDO communication parameter / Inhibit time" (x1403:03)
+func (d *Device) RPDOCommunicationParameter1403InhibitTime() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1403, 0x03)
+}
+
+// SetRPDOCommunicationParameter1403InhibitTime writes "RPDO communication parameter / Inhibit time" (x1403:03)
+func (d *Device) SetRPDOCommunicationParameter1403InhibitTime(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1403, 0x03, value)
+}
+
+// RPDOCommunicationParameter1403Reserved reads "RPDO communication parameter / Reserved" (x1403:04)
+func (d *Device) RPDOCommunicationParameter1403Reserved() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1403, 0x04)
+}
+
+// SetRPDOCommunicationParameter1403Reserved writes "RPDO communication parameter / Reserved" (x1403:04)
+func (d *Device) SetRPDOCommunicationParameter1403Reserved(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1403, 0x04, value)
+}
+
+// RPDOCommunicationParameter1403EventTimer reads "RPDO communication parameter / Event timer" (x1403:05)
+func (d *Device) RPDOCommunicationParameter1403EventTimer() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1403, 0x05)
+}
+
+// SetRPDOCommunicationParameter1403EventTimer writes "RPDO communication parameter / Event timer" (x1403:05)
+func (d *Device) SetRPDOCommunicationParameter1403EventTimer(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1403, 0x05, value)
+}
+
+// RPDOMappingParameter1600NumberOfMappedApplicationObjectsInPDO reads "RPDO mapping parameter / Number of mapped application objects in PDO" (x1600:00)
+func (d *Device) RPDOMappingParameter1600NumberOfMappedApplicationObjectsInPDO() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1600, 0x00)
+}
+
+// SetRPDOMappingParameter1600NumberOfMappedApplicationObjectsInPDO writes "RPDO mapping parameter / Number of mapped application objects in PDO" (x1600:00)
+func (d *Device) SetRPDOMappingParameter1600NumberOfMappedApplicationObjectsInPDO(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1600, 0x00, value)
+}
+
+// RPDOMappingParameter1600ApplicationObject1 reads "RPDO mapping parameter / Application object 1" (x1600:01)
+func (d *Device) RPDOMappingParameter1600ApplicationObject1() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1600, 0x01)
+}
+
+// SetRPDOMappingParameter1600ApplicationObject1 writes "RPDO mapping parameter / Application object 1" (x1600:01)
+func (d *Device) SetRPDOMappingParameter1600ApplicationObject1(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1600, 0x01, value)
+}
+
+// RPDOMappingParameter1600ApplicationObject2 reads "RPDO mapping parameter / Application object 2" (x1600:02)
+func (d *Device) RPDOMappingParameter1600ApplicationObject2() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1600, 0x02)
+}
+
+// SetRPDOMappingParameter1600ApplicationObject2 writes "RPDO mapping parameter / Application object 2" (x1600:02)
+func (d *Device) SetRPDOMappingParameter1600ApplicationObject2(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1600, 0x02, value)
+}
+
+// RPDOMappingParameter1600ApplicationObject3 reads "RPDO mapping parameter / Application object 3" (x1600:03)
+func (d *Device) RPDOMappingParameter1600ApplicationObject3() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1600, 0x03)
+}
+
+// SetRPDOMappingParameter1600ApplicationObject3 writes "RPDO mapping parameter / Application object 3" (x1600:03)
+func (d *Device) SetRPDOMappingParameter1600ApplicationObject3(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1600, 0x03, value)
+}
+
+// RPDOMappingParameter1600ApplicationObject4 reads "RPDO mapping parameter / Application object 4" (x1600:04)
+func (d *Device) RPDOMappingParameter1600ApplicationObject4() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1600, 0x04)
+}
+
+// SetRPDOMappingParameter1600ApplicationObject4 writes "RPDO mapping parameter / Application object 4" (x1600:04)
+func (d *Device) SetRPDOMappingParameter1600ApplicationObject4(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1600, 0x04, value)
+}
+
+// RPDOMappingParameter1600ApplicationObject5 reads "RPDO mapping parameter / Application object 5" (x1600:05)
+func (d *Device) RPDOMappingParameter1600ApplicationObject5() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1600, 0x05)
+}
+
+// SetRPDOMappingParameter1600ApplicationObject5 writes "RPDO mapping parameter / Application object 5" (x1600:05)
+func (d *Device) SetRPDOMappingParameter1600ApplicationObject5(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1600, 0x05, value)
+}
+
+// RPDOMappingParameter1600ApplicationObject6 reads "RPDO mapping parameter / Application object 6" (x1600:06)
+func (d *Device) RPDOMappingParameter1600ApplicationObject6() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1600, 0x06)
+}
+
+// SetRPDOMappingParameter1600ApplicationObject6 writes "RPDO mapping parameter / Application object 6" (x1600:06)
+func (d *Device) SetRPDOMappingParameter1600ApplicationObject6(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1600, 0x06, value)
+}
+
+// RPDOMappingParameter1600ApplicationObject7 reads "RPDO mapping parameter / Application object 7" (x1600:07)
+func (d *Device) RPDOMappingParameter1600ApplicationObject7() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1600, 0x07)
+}
+
+// SetRPDOMappingParameter1600ApplicationObject7 writes "RPDO mapping parameter / Application object 7" (x1600:07)
+func (d *Device) SetRPDOMappingParameter1600ApplicationObject7(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1600, 0x07, value)
+}
+
+// RPDOMappingParameter1600ApplicationObject8 reads "RPDO mapping parameter / Application object 8" (x1600:08)
+func (d *Device) RPDOMappingParameter1600ApplicationObject8() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1600, 0x08)
+}
+
+// SetRPDOMappingParameter1600ApplicationObject8 writes "RPDO mapping parameter / Application object 8" (x1600:08)
+func (d *Device) SetRPDOMappingParameter1600ApplicationObject8(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1600, 0x08, value)
+}
+
+// RPDOMappingParameter1601NumberOfMappedApplicationObjectsInPDO reads "RPDO mapping parameter / Number of mapped application objects in PDO" (x1601:00)
+func (d *Device) RPDOMappingParameter1601NumberOfMappedApplicationObjectsInPDO() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1601, 0x00)
+}
+
+// SetRPDOMappingParameter1601NumberOfMappedApplicationObjectsInPDO writes "RPDO mapping parameter / Number of mapped application objects in PDO" (x1601:00)
+func (d *Device) SetRPDOMappingParameter1601NumberOfMappedApplicationObjectsInPDO(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1601, 0x00, value)
+}
+
+// RPDOMappingParameter1601ApplicationObject1 reads "RPDO mapping parameter / Application object 1" (x1601:01)
+func (d *Device) RPDOMappingParameter1601ApplicationObject1() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1601, 0x01)
+}
+
+// SetRPDOMappingParameter1601ApplicationObject1 writes "RPDO mapping parameter / Application object 1" (x1601:01)
+func (d *Device) SetRPDOMappingParameter1601ApplicationObject1(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1601, 0x01, value)
+}
+
+// RPDOMappingParameter1601ApplicationObject2 reads "RPDO mapping parameter / Application object 2" (x1601:02)
+func (d *Device) RPDOMappingParameter1601ApplicationObject2() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1601, 0x02)
+}
+
+// SetRPDOMappingParameter1601ApplicationObject2 writes "RPDO mapping parameter / Application object 2" (x1601:02)
+func (d *Device) SetRPDOMappingParameter1601ApplicationObject2(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1601, 0x02, value)
+}
+
+// RPDOMappingParameter1601ApplicationObject3 reads "RPDO mapping parameter / Application object 3" (x1601:03)
+func (d *Device) RPDOMappingParameter1601ApplicationObject3() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1601, 0x03)
+}
+
+// SetRPDOMappingParameter1601ApplicationObject3 writes "RPDO mapping parameter / Application object 3" (x1601:03)
+func (d *Device) SetRPDOMappingParameter1601ApplicationObject3(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1601, 0x03, value)
+}
+
+// RPDOMappingParameter1601ApplicationObject4 reads "RPDO mapping parameter / Application object 4" (x1601:04)
+func (d *Device) RPDOMappingParameter1601ApplicationObject4() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1601, 0x04)
+}
+
+// SetRPDOMappingParameter1601ApplicationObject4 writes "RPDO mapping parameter / Application object 4" (x1601:04)
+func (d *Device) SetRPDOMappingParameter1601ApplicationObject4(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1601, 0x04, value)
+}
+
+// RPDOMappingParameter1601ApplicationObject5 reads "RPDO mapping parameter / Application object 5" (x1601:05)
+func (d *Device) RPDOMappingParameter1601ApplicationObject5() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1601, 0x05)
+}
+
+// SetRPDOMappingParameter1601ApplicationObject5 writes "RPDO mapping parameter / Application object 5" (x1601:05)
+func (d *Device) SetRPDOMappingParameter1601ApplicationObject5(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1601, 0x05, value)
+}
+
+// RPDOMappingParameter1601ApplicationObject6 reads "RPDO mapping parameter / Application object 6" (x1601:06)
+func (d *Device) RPDOMappingParameter1601ApplicationObject6() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1601, 0x06)
+}
+
+// SetRPDOMappingParameter1601ApplicationObject6 writes "RPDO mapping parameter / Application object 6" (x1601:06)
+func (d *Device) SetRPDOMappingParameter1601ApplicationObject6(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1601, 0x06, value)
+}
+
+// RPDOMappingParameter1601ApplicationObject7 reads "RPDO mapping parameter / Application object 7" (x1601:07)
+func (d *Device) RPDOMappingParameter1601ApplicationObject7() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1601, 0x07)
+}
+
+// SetRPDOMappingParameter1601ApplicationObject7 writes "RPDO mapping parameter / Application object 7" (x1601:07)
+func (d *Device) SetRPDOMappingParameter1601ApplicationObject7(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1601, 0x07, value)
+}
+
+// RPDOMappingParameter1601ApplicationObject8 reads "RPDO mapping parameter / Application object 8" (x1601:08)
+func (d *Device) RPDOMappingParameter1601ApplicationObject8() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1601, 0x08)
+}
+
+// SetRPDOMappingParameter1601ApplicationObject8 writes "RPDO mapping parameter / Application object 8" (x1601:08)
+func (d *Device) SetRPDOMappingParameter1601ApplicationObject8(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1601, 0x08, value)
+}
+
+// RPDOMappingParameter1602NumberOfMappedApplicationObjectsInPDO reads "RPDO mapping parameter / Number of mapped application objects in PDO" (x1602:00)
+func (d *Device) RPDOMappingParameter1602NumberOfMappedApplicationObjectsInPDO() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1602, 0x00)
+}
+
+// SetRPDOMappingParameter1602NumberOfMappedApplicationObjectsInPDO writes "RPDO mapping parameter / Number of mapped application objects in PDO" (x1602:00)
+func (d *Device) SetRPDOMappingParameter1602NumberOfMappedApplicationObjectsInPDO(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1602, 0x00, value)
+}
+
+// RPDOMappingParameter1602ApplicationObject1 reads "RPDO mapping parameter / Application object 1" (x1602:01)
+func (d *Device) RPDOMappingParameter1602ApplicationObject1() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1602, 0x01)
+}
+
+// SetRPDOMappingParameter1602ApplicationObject1 writes "RPDO mapping parameter / Application object 1" (x1602:01)
+func (d *Device) SetRPDOMappingParameter1602ApplicationObject1(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1602, 0x01, value)
+}
+
+// RPDOMappingParameter1602ApplicationObject2 reads "RPDO mapping parameter / Application object 2" (x1602:02)
+func (d *Device) RPDOMappingParameter1602ApplicationObject2() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1602, 0x02)
+}
+
+// SetRPDOMappingParameter1602ApplicationObject2 writes "RPDO mapping parameter / Application object 2" (x1602:02)
+func (d *Device) SetRPDOMappingParameter1602ApplicationObject2(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1602, 0x02, value)
+}
+
+// RPDOMappingParameter1602ApplicationObject3 reads "RPDO mapping parameter / Application object 3" (x1602:03)
+func (d *Device) RPDOMappingParameter1602ApplicationObject3() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1602, 0x03)
+}
+
+// SetRPDOMappingParameter1602ApplicationObject3 writes "RPDO mapping parameter / Application object 3" (x1602:03)
+func (d *Device) SetRPDOMappingParameter1602ApplicationObject3(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1602, 0x03, value)
+}
+
+// RPDOMappingParameter1602ApplicationObject4 reads "RPDO mapping parameter / Application object 4" (x1602:04)
+func (d *Device) RPDOMappingParameter1602ApplicationObject4() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1602, 0x04)
+}
+
+// SetRPDOMappingParameter1602ApplicationObject4 writes "RPDO mapping parameter / Application object 4" (x1602:04)
+func (d *Device) SetRPDOMappingParameter1602ApplicationObject4(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1602, 0x04, value)
+}
+
+// RPDOMappingParameter1602ApplicationObject5 reads "RPDO mapping parameter / Application object 5" (x1602:05)
+func (d *Device) RPDOMappingParameter1602ApplicationObject5() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1602, 0x05)
+}
+
+// SetRPDOMappingParameter1602ApplicationObject5 writes "RPDO mapping parameter / Application object 5" (x1602:05)
+func (d *Device) SetRPDOMappingParameter1602ApplicationObject5(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1602, 0x05, value)
+}
+
+// RPDOMappingParameter1602ApplicationObject6 reads "RPDO mapping parameter / Application object 6" (x1602:06)
+func (d *Device) RPDOMappingParameter1602ApplicationObject6() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1602, 0x06)
+}
+
+// SetRPDOMappingParameter1602ApplicationObject6 writes "RPDO mapping parameter / Application object 6" (x1602:06)
+func (d *Device) SetRPDOMappingParameter1602ApplicationObject6(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1602, 0x06, value)
+}
+
+// RPDOMappingParameter1602ApplicationObject7 reads "RPDO mapping parameter / Application object 7" (x1602:07)
+func (d *Device) RPDOMappingParameter1602ApplicationObject7() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1602, 0x07)
+}
+
+// SetRPDOMappingParameter1602ApplicationObject7 writes "RPDO mapping parameter / Application object 7" (x1602:07)
+func (d *Device) SetRPDOMappingParameter1602ApplicationObject7(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1602, 0x07, value)
+}
+
+// RPDOMappingParameter1602ApplicationObject8 reads "RPDO mapping parameter / Application object 8" (x1602:08)
+func (d *Device) RPDOMappingParameter1602ApplicationObject8() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1602, 0x08)
+}
+
+// SetRPDOMappingParameter1602ApplicationObject8 writes "RPDO mapping parameter / Application object 8" (x1602:08)
+func (d *Device) SetRPDOMappingParameter1602ApplicationObject8(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1602, 0x08, value)
+}
+
+// RPDOMappingParameter1603NumberOfMappedApplicationObjectsInPDO reads "RPDO mapping parameter / Number of mapped application objects in PDO" (x1603:00)
+func (d *Device) RPDOMappingParameter1603NumberOfMappedApplicationObjectsInPDO() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1603, 0x00)
+}
+
+// SetRPDOMappingParameter1603NumberOfMappedApplicationObjectsInPDO writes "RPDO mapping parameter / Number of mapped application objects in PDO" (x1603:00)
+func (d *Device) SetRPDOMappingParameter1603NumberOfMappedApplicationObjectsInPDO(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1603, 0x00, value)
+}
+
+// RPDOMappingParameter1603ApplicationObject1 reads "RPDO mapping parameter / Application object 1" (x1603:01)
+func (d *Device) RPDOMappingParameter1603ApplicationObject1() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1603, 0x01)
+}
+
+// SetRPDOMappingParameter1603ApplicationObject1 writes "RPDO mapping parameter / Application object 1" (x1603:01)
+func (d *Device) SetRPDOMappingParameter1603ApplicationObject1(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1603, 0x01, value)
+}
+
+// RPDOMappingParameter1603ApplicationObject2 reads "RPDO mapping parameter / Application object 2" (x1603:02)
+func (d *Device) RPDOMappingParameter1603ApplicationObject2() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1603, 0x02)
+}
+
+// SetRPDOMappingParameter1603ApplicationObject2 writes "RPDO mapping parameter / Application object 2" (x1603:02)
+func (d *Device) SetRPDOMappingParameter1603ApplicationObject2(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1603, 0x02, value)
+}
+
+// RPDOMappingParameter1603ApplicationObject3 reads "RPDO mapping parameter / Application object 3" (x1603:03)
+func (d *Device) RPDOMappingParameter1603ApplicationObject3() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1603, 0x03)
+}
+
+// SetRPDOMappingParameter1603ApplicationObject3 writes "RPDO mapping parameter / Application object 3" (x1603:03)
+func (d *Device) SetRPDOMappingParameter1603ApplicationObject3(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1603, 0x03, value)
+}
+
+// RPDOMappingParameter1603ApplicationObject4 reads "RPDO mapping parameter / Application object 4" (x1603:04)
+func (d *Device) RPDOMappingParameter1603ApplicationObject4() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1603, 0x04)
+}
+
+// SetRPDOMappingParameter1603ApplicationObject4 writes "RPDO mapping parameter / Application object 4" (x1603:04)
+func (d *Device) SetRPDOMappingParameter1603ApplicationObject4(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1603, 0x04, value)
+}
+
+// RPDOMappingParameter1603ApplicationObject5 reads "RPDO mapping parameter / Application object 5" (x1603:05)
+func (d *Device) RPDOMappingParameter1603ApplicationObject5() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1603, 0x05)
+}
+
+// SetRPDOMappingParameter1603ApplicationObject5 writes "RPDO mapping parameter / Application object 5" (x1603:05)
+func (d *Device) SetRPDOMappingParameter1603ApplicationObject5(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1603, 0x05, value)
+}
+
+// RPDOMappingParameter1603ApplicationObject6 reads "RPDO mapping parameter / Application object 6" (x1603:06)
+func (d *Device) RPDOMappingParameter1603ApplicationObject6() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1603, 0x06)
+}
+
+// SetRPDOMappingParameter1603ApplicationObject6 writes "RPDO mapping parameter / Application object 6" (x1603:06)
+func (d *Device) SetRPDOMappingParameter1603ApplicationObject6(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1603, 0x06, value)
+}
+
+// RPDOMappingParameter1603ApplicationObject7 reads "RPDO mapping parameter / Application object 7" (x1603:07)
+func (d *Device) RPDOMappingParameter1603ApplicationObject7() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1603, 0x07)
+}
+
+// SetRPDOMappingParameter1603ApplicationObject7 writes "RPDO mapping parameter / Application object 7" (x1603:07)
+func (d *Device) SetRPDOMappingParameter1603ApplicationObject7(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1603, 0x07, value)
+}
+
+// RPDOMappingParameter1603ApplicationObject8 reads "RPDO mapping parameter / Application object 8" (x1603:08)
+func (d *Device) RPDOMappingParameter1603ApplicationObject8() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1603, 0x08)
+}
+
+// SetRPDOMappingParameter1603ApplicationObject8 writes "RPDO mapping parameter / Application object 8" (x1603:08)
+func (d *Device) SetRPDOMappingParameter1603ApplicationObject8(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1603, 0x08, value)
+}
+
+// TPDOCommunicationParameter1800HighestSubIndexSupported reads "TPDO communication parameter / Highest sub-index supported" (x1800:00)
+func (d *Device) TPDOCommunicationParameter1800HighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1800, 0x00)
+}
+
+// TPDOCommunicationParameter1800COBIDUsedByTPDO reads "TPDO communication parameter / COB-ID used by TPDO" (x1800:01)
+func (d *Device) TPDOCommunicationParameter1800COBIDUsedByTPDO() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1800, 0x01)
+}
+
+// SetTPDOCommunicationParameter1800COBIDUsedByTPDO writes "TPDO communication parameter / COB-ID used by TPDO" (x1800:01)
+func (d *Device) SetTPDOCommunicationParameter1800COBIDUsedByTPDO(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1800, 0x01, value)
+}
+
+// TPDOCommunicationParameter1800TransmissionType reads "TPDO communication parameter / Transmission type" (x1800:02)
+func (d *Device) TPDOCommunicationParameter1800TransmissionType() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1800, 0x02)
+}
+
+// SetTPDOCommunicationParameter1800TransmissionType writes "TPDO communication parameter / Transmission type" (x1800:02)
+func (d *Device) SetTPDOCommunicationParameter1800TransmissionType(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1800, 0x02, value)
+}
+
+// TPDOCommunicationParameter1800InhibitTime reads "TPDO communication parameter / Inhibit time" (x1800:03)
+func (d *Device) TPDOCommunicationParameter1800InhibitTime() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1800, 0x03)
+}
+
+// SetTPDOCommunicationParameter1800InhibitTime writes "TPDO communication parameter / Inhibit time" (x1800:03)
+func (d *Device) SetTPDOCommunicationParameter1800InhibitTime(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1800, 0x03, value)
+}
+
+// TPDOCommunicationParameter1800EventTimer reads "TPDO communication parameter / Event timer" (x1800:05)
+func (d *Device) TPDOCommunicationParameter1800EventTimer() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1800, 0x05)
+}
+
+// SetTPDOCommunicationParameter1800EventTimer writes "TPDO communication parameter / Event timer" (x1800:05)
+func (d *Device) SetTPDOCommunicationParameter1800EventTimer(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1800, 0x05, value)
+}
+
+// TPDOCommunicationParameter1800SYNCStartValue reads "TPDO communication parameter / SYNC start value" (x1800:06)
+func (d *Device) TPDOCommunicationParameter1800SYNCStartValue() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1800, 0x06)
+}
+
+// SetTPDOCommunicationParameter1800SYNCStartValue writes "TPDO communication parameter / SYNC start value" (x1800:06)
+func (d *Device) SetTPDOCommunicationParameter1800SYNCStartValue(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1800, 0x06, value)
+}
+
+// TPDOCommunicationParameter1801HighestSubIndexSupported reads "TPDO communication parameter / Highest sub-index supported" (x1801:00)
+func (d *Device) TPDOCommunicationParameter1801HighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1801, 0x00)
+}
+
+// TPDOCommunicationParameter1801COBIDUsedByTPDO reads "TPDO communication parameter / COB-ID used by TPDO" (x1801:01)
+func (d *Device) TPDOCommunicationParameter1801COBIDUsedByTPDO() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1801, 0x01)
+}
+
+// SetTPDOCommunicationParameter1801COBIDUsedByTPDO writes "TPDO communication parameter / COB-ID used by TPDO" (x1801:01)
+func (d *Device) SetTPDOCommunicationParameter1801COBIDUsedByTPDO(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1801, 0x01, value)
+}
+
+// TPDOCommunicationParameter1801TransmissionType reads "TPDO communication parameter / Transmission type" (x1801:02)
+func (d *Device) TPDOCommunicationParameter1801TransmissionType() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1801, 0x02)
+}
+
+// SetTPDOCommunicationParameter1801TransmissionType writes "TPDO communication parameter / Transmission type" (x1801:02)
+func (d *Device) SetTPDOCommunicationParameter1801TransmissionType(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1801, 0x02, value)
+}
+
+// TPDOCommunicationParameter1801InhibitTime reads "TPDO communication parameter / Inhibit time" (x1801:03)
+func (d *Device) TPDOCommunicationParameter1801InhibitTime() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1801, 0x03)
+}
+
+// SetTPDOCommunicationParameter1801InhibitTime writes "TPDO communication parameter / Inhibit time" (x1801:03)
+func (d *Device) SetTPDOCommunicationParameter1801InhibitTime(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1801, 0x03, value)
+}
+
+// TPDOCommunicationParameter1801EventTimer reads "TPDO communication parameter / Event timer" (x1801:05)
+func (d *Device) TPDOCommunicationParameter1801EventTimer() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1801, 0x05)
+}
+
+// SetTPDOCommunicationParameter1801EventTimer writes "TPDO communication parameter / Event timer" (x1801:05)
+func (d *Device) SetTPDOCommunicationParameter1801EventTimer(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1801, 0x05, value)
+}
+
+// TPDOCommunicationParameter1801SYNCStartValue reads "TPDO communication parameter / SYNC start value" (x1801:06)
+func (d *Device) TPDOCommunicationParameter1801SYNCStartValue() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1801, 0x06)
+}
+
+// SetTPDOCommunicationParameter1801SYNCStartValue writes "TPDO communication parameter / SYNC start value" (x1801:06)
+func (d *Device) SetTPDOCommunicationParameter1801SYNCStartValue(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1801, 0x06, value)
+}
+
+// TPDOCommunicationParameter1802HighestSubIndexSupported reads "TPDO communication parameter / Highest sub-index supported" (x1802:00)
+func (d *Device) TPDOCommunicationParameter1802HighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1802, 0x00)
+}
+
+// TPDOCommunicationParameter1802COBIDUsedByTPDO reads "TPDO communication parameter / COB-ID used by TPDO" (x1802:01)
+func (d *Device) TPDOCommunicationParameter1802COBIDUsedByTPDO() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1802, 0x01)
+}
+
+// SetTPDOCommunicationParameter1802COBIDUsedByTPDO writes "TPDO communication parameter / COB-ID used by TPDO" (x1802:01)
+func (d *Device) SetTPDOCommunicationParameter1802COBIDUsedByTPDO(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1802, 0x01, value)
+}
+
+// TPDOCommunicationParameter1802TransmissionType reads "TPDO communication parameter / Transmission type" (x1802:02)
+func (d *Device) TPDOCommunicationParameter1802TransmissionType() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1802, 0x02)
+}
+
+// SetTPDOCommunicationParameter1802TransmissionType writes "TPDO communication parameter / Transmission type" (x1802:02)
+func (d *Device) SetTPDOCommunicationParameter1802TransmissionType(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1802, 0x02, value)
+}
+
+// TPDOCommunicationParameter1802InhibitTime reads "TPDO communication parameter / Inhibit time" (x1802:03)
+func (d *Device) TPDOCommunicationParameter1802InhibitTime() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1802, 0x03)
+}
+
+// SetTPDOCommunicationParameter1802InhibitTime writes "TPDO communication parameter / Inhibit time" (x1802:03)
+func (d *Device) SetTPDOCommunicationParameter1802InhibitTime(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1802, 0x03, value)
+}
+
+// TPDOCommunicationParameter1802EventTimer reads "TPDO communication parameter / Event timer" (x1802:05)
+func (d *Device) TPDOCommunicationParameter1802EventTimer() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1802, 0x05)
+}
+
+// SetTPDOCommunicationParameter1802EventTimer writes "TPDO communication parameter / Event timer" (x1802:05)
+func (d *Device) SetTPDOCommunicationParameter1802EventTimer(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1802, 0x05, value)
+}
+
+// TPDOCommunicationParameter1802SYNCStartValue reads "TPDO communication parameter / SYNC start value" (x1802:06)
+func (d *Device) TPDOCommunicationParameter1802SYNCStartValue() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1802, 0x06)
+}
+
+// SetTPDOCommunicationParameter1802SYNCStartValue writes "TPDO communication parameter / SYNC start value" (x1802:06)
+func (d *Device) SetTPDOCommunicationParameter1802SYNCStartValue(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1802, 0x06, value)
+}
+
+// TPDOCommunicationParameter1803HighestSubIndexSupported reads "TPDO communication parameter / Highest sub-index supported" (x1803:00)
+func (d *Device) TPDOCommunicationParameter1803HighestSubIndexSupported() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1803, 0x00)
+}
+
+// TPDOCommunicationParameter1803COBIDUsedByTPDO reads "TPDO communication parameter / COB-ID used by TPDO" (x1803:01)
+func (d *Device) TPDOCommunicationParameter1803COBIDUsedByTPDO() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1803, 0x01)
+}
+
+// SetTPDOCommunicationParameter1803COBIDUsedByTPDO writes "TPDO communication parameter / COB-ID used by TPDO" (x1803:01)
+func (d *Device) SetTPDOCommunicationParameter1803COBIDUsedByTPDO(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1803, 0x01, value)
+}
+
+// TPDOCommunicationParameter1803TransmissionType reads "TPDO communication parameter / Transmission type" (x1803:02)
+func (d *Device) TPDOCommunicationParameter1803TransmissionType() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1803, 0x02)
+}
+
+// SetTPDOCommunicationParameter1803TransmissionType writes "TPDO communication parameter / Transmission type" (x1803:02)
+func (d *Device) SetTPDOCommunicationParameter1803TransmissionType(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1803, 0x02, value)
+}
+
+// TPDOCommunicationParameter1803InhibitTime reads "TPDO communication parameter / Inhibit time" (x1803:03)
+func (d *Device) TPDOCommunicationParameter1803InhibitTime() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1803, 0x03)
+}
+
+// SetTPDOCommunicationParameter1803InhibitTime writes "TPDO communication parameter / Inhibit time" (x1803:03)
+func (d *Device) SetTPDOCommunicationParameter1803InhibitTime(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1803, 0x03, value)
+}
+
+// TPDOCommunicationParameter1803EventTimer reads "TPDO communication parameter / Event timer" (x1803:05)
+func (d *Device) TPDOCommunicationParameter1803EventTimer() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x1803, 0x05)
+}
+
+// SetTPDOCommunicationParameter1803EventTimer writes "TPDO communication parameter / Event timer" (x1803:05)
+func (d *Device) SetTPDOCommunicationParameter1803EventTimer(value uint16) error {
+	return node.WriteValue(d.accessor, 0x1803, 0x05, value)
+}
+
+// TPDOCommunicationParameter1803SYNCStartValue reads "TPDO communication parameter / SYNC start value" (x1803:06)
+func (d *Device) TPDOCommunicationParameter1803SYNCStartValue() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1803, 0x06)
+}
+
+// SetTPDOCommunicationParameter1803SYNCStartValue writes "TPDO communication parameter / SYNC start value" (x1803:06)
+func (d *Device) SetTPDOCommunicationParameter1803SYNCStartValue(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1803, 0x06, value)
+}
+
+// TPDOMappingParameter1A00NumberOfMappedApplicationObjectsInPDO reads "TPDO mapping parameter / Number of mapped application objects in PDO" (x1A00:00)
+func (d *Device) TPDOMappingParameter1A00NumberOfMappedApplicationObjectsInPDO() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1A00, 0x00)
+}
+
+// SetTPDOMappingParameter1A00NumberOfMappedApplicationObjectsInPDO writes "TPDO mapping parameter / Number of mapped application objects in PDO" (x1A00:00)
+func (d *Device) SetTPDOMappingParameter1A00NumberOfMappedApplicationObjectsInPDO(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1A00, 0x00, value)
+}
+
+// TPDOMappingParameter1A00ApplicationObject1 reads "TPDO mapping parameter / Application object 1" (x1A00:01)
+func (d *Device) TPDOMappingParameter1A00ApplicationObject1() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A00, 0x01)
+}
+
+// SetTPDOMappingParameter1A00ApplicationObject1 writes "TPDO mapping parameter / Application object 1" (x1A00:01)
+func (d *Device) SetTPDOMappingParameter1A00ApplicationObject1(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A00, 0x01, value)
+}
+
+// TPDOMappingParameter1A00ApplicationObject2 reads "TPDO mapping parameter / Application object 2" (x1A00:02)
+func (d *Device) TPDOMappingParameter1A00ApplicationObject2() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A00, 0x02)
+}
+
+// SetTPDOMappingParameter1A00ApplicationObject2 writes "TPDO mapping parameter / Application object 2" (x1A00:02)
+func (d *Device) SetTPDOMappingParameter1A00ApplicationObject2(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A00, 0x02, value)
+}
+
+// TPDOMappingParameter1A00ApplicationObject3 reads "TPDO mapping parameter / Application object 3" (x1A00:03)
+func (d *Device) TPDOMappingParameter1A00ApplicationObject3() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A00, 0x03)
+}
+
+// SetTPDOMappingParameter1A00ApplicationObject3 writes "TPDO mapping parameter / Application object 3" (x1A00:03)
+func (d *Device) SetTPDOMappingParameter1A00ApplicationObject3(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A00, 0x03, value)
+}
+
+// TPDOMappingParameter1A00ApplicationObject4 reads "TPDO mapping parameter / Application object 4" (x1A00:04)
+func (d *Device) TPDOMappingParameter1A00ApplicationObject4() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A00, 0x04)
+}
+
+// SetTPDOMappingParameter1A00ApplicationObject4 writes "TPDO mapping parameter / Application object 4" (x1A00:04)
+func (d *Device) SetTPDOMappingParameter1A00ApplicationObject4(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A00, 0x04, value)
+}
+
+// TPDOMappingParameter1A00ApplicationObject5 reads "TPDO mapping parameter / Application object 5" (x1A00:05)
+func (d *Device) TPDOMappingParameter1A00ApplicationObject5() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A00, 0x05)
+}
+
+// SetTPDOMappingParameter1A00ApplicationObject5 writes "TPDO mapping parameter / Application object 5" (x1A00:05)
+func (d *Device) SetTPDOMappingParameter1A00ApplicationObject5(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A00, 0x05, value)
+}
+
+// TPDOMappingParameter1A00ApplicationObject6 reads "TPDO mapping parameter / Application object 6" (x1A00:06)
+func (d *Device) TPDOMappingParameter1A00ApplicationObject6() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A00, 0x06)
+}
+
+// SetTPDOMappingParameter1A00ApplicationObject6 writes "TPDO mapping parameter / Application object 6" (x1A00:06)
+func (d *Device) SetTPDOMappingParameter1A00ApplicationObject6(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A00, 0x06, value)
+}
+
+// TPDOMappingParameter1A00ApplicationObject7 reads "TPDO mapping parameter / Application object 7" (x1A00:07)
+func (d *Device) TPDOMappingParameter1A00ApplicationObject7() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A00, 0x07)
+}
+
+// SetTPDOMappingParameter1A00ApplicationObject7 writes "TPDO mapping parameter / Application object 7" (x1A00:07)
+func (d *Device) SetTPDOMappingParameter1A00ApplicationObject7(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A00, 0x07, value)
+}
+
+// TPDOMappingParameter1A00ApplicationObject8 reads "TPDO mapping parameter / Application object 8" (x1A00:08)
+func (d *Device) TPDOMappingParameter1A00ApplicationObject8() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A00, 0x08)
+}
+
+// SetTPDOMappingParameter1A00ApplicationObject8 writes "TPDO mapping parameter / Application object 8" (x1A00:08)
+func (d *Device) SetTPDOMappingParameter1A00ApplicationObject8(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A00, 0x08, value)
+}
+
+// TPDOMappingParameter1A01NumberOfMappedApplicationObjectsInPDO reads "TPDO mapping parameter / Number of mapped application objects in PDO" (x1A01:00)
+func (d *Device) TPDOMappingParameter1A01NumberOfMappedApplicationObjectsInPDO() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1A01, 0x00)
+}
+
+// SetTPDOMappingParameter1A01NumberOfMappedApplicationObjectsInPDO writes "TPDO mapping parameter / Number of mapped application objects in PDO" (x1A01:00)
+func (d *Device) SetTPDOMappingParameter1A01NumberOfMappedApplicationObjectsInPDO(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1A01, 0x00, value)
+}
+
+// TPDOMappingParameter1A01ApplicationObject1 reads "TPDO mapping parameter / Application object 1" (x1A01:01)
+func (d *Device) TPDOMappingParameter1A01ApplicationObject1() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A01, 0x01)
+}
+
+// SetTPDOMappingParameter1A01ApplicationObject1 writes "TPDO mapping parameter / Application object 1" (x1A01:01)
+func (d *Device) SetTPDOMappingParameter1A01ApplicationObject1(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A01, 0x01, value)
+}
+
+// TPDOMappingParameter1A01ApplicationObject2 reads "TPDO mapping parameter / Application object 2" (x1A01:02)
+func (d *Device) TPDOMappingParameter1A01ApplicationObject2() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A01, 0x02)
+}
+
+// SetTPDOMappingParameter1A01ApplicationObject2 writes "TPDO mapping parameter / Application object 2" (x1A01:02)
+func (d *Device) SetTPDOMappingParameter1A01ApplicationObject2(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A01, 0x02, value)
+}
+
+// TPDOMappingParameter1A01ApplicationObject3 reads "TPDO mapping parameter / Application object 3" (x1A01:03)
+func (d *Device) TPDOMappingParameter1A01ApplicationObject3() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A01, 0x03)
+}
+
+// SetTPDOMappingParameter1A01ApplicationObject3 writes "TPDO mapping parameter / Application object 3" (x1A01:03)
+func (d *Device) SetTPDOMappingParameter1A01ApplicationObject3(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A01, 0x03, value)
+}
+
+// TPDOMappingParameter1A01ApplicationObject4 reads "TPDO mapping parameter / Application object 4" (x1A01:04)
+func (d *Device) TPDOMappingParameter1A01ApplicationObject4() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A01, 0x04)
+}
+
+// SetTPDOMappingParameter1A01ApplicationObject4 writes "TPDO mapping parameter / Application object 4" (x1A01:04)
+func (d *Device) SetTPDOMappingParameter1A01ApplicationObject4(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A01, 0x04, value)
+}
+
+// TPDOMappingParameter1A01ApplicationObject5 reads "TPDO mapping parameter / Application object 5" (x1A01:05)
+func (d *Device) TPDOMappingParameter1A01ApplicationObject5() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A01, 0x05)
+}
+
+// SetTPDOMappingParameter1A01ApplicationObject5 writes "TPDO mapping parameter / Application object 5" (x1A01:05)
+func (d *Device) SetTPDOMappingParameter1A01ApplicationObject5(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A01, 0x05, value)
+}
+
+// TPDOMappingParameter1A01ApplicationObject6 reads "TPDO mapping parameter / Application object 6" (x1A01:06)
+func (d *Device) TPDOMappingParameter1A01ApplicationObject6() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A01, 0x06)
+}
+
+// SetTPDOMappingParameter1A01ApplicationObject6 writes "TPDO mapping parameter / Application object 6" (x1A01:06)
+func (d *Device) SetTPDOMappingParameter1A01ApplicationObject6(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A01, 0x06, value)
+}
+
+// TPDOMappingParameter1A01ApplicationObject7 reads "TPDO mapping parameter / Application object 7" (x1A01:07)
+func (d *Device) TPDOMappingParameter1A01ApplicationObject7() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A01, 0x07)
+}
+
+// SetTPDOMappingParameter1A01ApplicationObject7 writes "TPDO mapping parameter / Application object 7" (x1A01:07)
+func (d *Device) SetTPDOMappingParameter1A01ApplicationObject7(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A01, 0x07, value)
+}
+
+// TPDOMappingParameter1A01ApplicationObject8 reads "TPDO mapping parameter / Application object 8" (x1A01:08)
+func (d *Device) TPDOMappingParameter1A01ApplicationObject8() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A01, 0x08)
+}
+
+// SetTPDOMappingParameter1A01ApplicationObject8 writes "TPDO mapping parameter / Application object 8" (x1A01:08)
+func (d *Device) SetTPDOMappingParameter1A01ApplicationObject8(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A01, 0x08, value)
+}
+
+// TPDOMappingParameter1A02NumberOfMappedApplicationObjectsInPDO reads "TPDO mapping parameter / Number of mapped application objects in PDO" (x1A02:00)
+func (d *Device) TPDOMappingParameter1A02NumberOfMappedApplicationObjectsInPDO() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1A02, 0x00)
+}
+
+// SetTPDOMappingParameter1A02NumberOfMappedApplicationObjectsInPDO writes "TPDO mapping parameter / Number of mapped application objects in PDO" (x1A02:00)
+func (d *Device) SetTPDOMappingParameter1A02NumberOfMappedApplicationObjectsInPDO(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1A02, 0x00, value)
+}
+
+// TPDOMappingParameter1A02ApplicationObject1 reads "TPDO mapping parameter / Application object 1" (x1A02:01)
+func (d *Device) TPDOMappingParameter1A02ApplicationObject1() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A02, 0x01)
+}
+
+// SetTPDOMappingParameter1A02ApplicationObject1 writes "TPDO mapping parameter / Application object 1" (x1A02:01)
+func (d *Device) SetTPDOMappingParameter1A02ApplicationObject1(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A02, 0x01, value)
+}
+
+// TPDOMappingParameter1A02ApplicationObject2 reads "TPDO mapping parameter / Application object 2" (x1A02:02)
+func (d *Device) TPDOMappingParameter1A02ApplicationObject2() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A02, 0x02)
+}
+
+// SetTPDOMappingParameter1A02ApplicationObject2 writes "TPDO mapping parameter / Application object 2" (x1A02:02)
+func (d *Device) SetTPDOMappingParameter1A02ApplicationObject2(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A02, 0x02, value)
+}
+
+// TPDOMappingParameter1A02ApplicationObject3 reads "TPDO mapping parameter / Application object 3" (x1A02:03)
+func (d *Device) TPDOMappingParameter1A02ApplicationObject3() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A02, 0x03)
+}
+
+// SetTPDOMappingParameter1A02ApplicationObject3 writes "TPDO mapping parameter / Application object 3" (x1A02:03)
+func (d *Device) SetTPDOMappingParameter1A02ApplicationObject3(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A02, 0x03, value)
+}
+
+// TPDOMappingParameter1A02ApplicationObject4 reads "TPDO mapping parameter / Application object 4" (x1A02:04)
+func (d *Device) TPDOMappingParameter1A02ApplicationObject4() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A02, 0x04)
+}
+
+// SetTPDOMappingParameter1A02ApplicationObject4 writes "TPDO mapping parameter / Application object 4" (x1A02:04)
+func (d *Device) SetTPDOMappingParameter1A02ApplicationObject4(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A02, 0x04, value)
+}
+
+// TPDOMappingParameter1A02ApplicationObject5 reads "TPDO mapping parameter / Application object 5" (x1A02:05)
+func (d *Device) TPDOMappingParameter1A02ApplicationObject5() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A02, 0x05)
+}
+
+// SetTPDOMappingParameter1A02ApplicationObject5 writes "TPDO mapping parameter / Application object 5" (x1A02:05)
+func (d *Device) SetTPDOMappingParameter1A02ApplicationObject5(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A02, 0x05, value)
+}
+
+// TPDOMappingParameter1A02ApplicationObject6 reads "TPDO mapping parameter / Application object 6" (x1A02:06)
+func (d *Device) TPDOMappingParameter1A02ApplicationObject6() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A02, 0x06)
+}
+
+// SetTPDOMappingParameter1A02ApplicationObject6 writes "TPDO mapping parameter / Application object 6" (x1A02:06)
+func (d *Device) SetTPDOMappingParameter1A02ApplicationObject6(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A02, 0x06, value)
+}
+
+// TPDOMappingParameter1A02ApplicationObject7 reads "TPDO mapping parameter / Application object 7" (x1A02:07)
+func (d *Device) TPDOMappingParameter1A02ApplicationObject7() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A02, 0x07)
+}
+
+// SetTPDOMappingParameter1A02ApplicationObject7 writes "TPDO mapping parameter / Application object 7" (x1A02:07)
+func (d *Device) SetTPDOMappingParameter1A02ApplicationObject7(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A02, 0x07, value)
+}
+
+// TPDOMappingParameter1A02ApplicationObject8 reads "TPDO mapping parameter / Application object 8" (x1A02:08)
+func (d *Device) TPDOMappingParameter1A02ApplicationObject8() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A02, 0x08)
+}
+
+// SetTPDOMappingParameter1A02ApplicationObject8 writes "TPDO mapping parameter / Application object 8" (x1A02:08)
+func (d *Device) SetTPDOMappingParameter1A02ApplicationObject8(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A02, 0x08, value)
+}
+
+// TPDOMappingParameter1A03NumberOfMappedApplicationObjectsInPDO reads "TPDO mapping parameter / Number of mapped application objects in PDO" (x1A03:00)
+func (d *Device) TPDOMappingParameter1A03NumberOfMappedApplicationObjectsInPDO() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x1A03, 0x00)
+}
+
+// SetTPDOMappingParameter1A03NumberOfMappedApplicationObjectsInPDO writes "TPDO mapping parameter / Number of mapped application objects in PDO" (x1A03:00)
+func (d *Device) SetTPDOMappingParameter1A03NumberOfMappedApplicationObjectsInPDO(value uint8) error {
+	return node.WriteValue(d.accessor, 0x1A03, 0x00, value)
+}
+
+// TPDOMappingParameter1A03ApplicationObject1 reads "TPDO mapping parameter / Application object 1" (x1A03:01)
+func (d *Device) TPDOMappingParameter1A03ApplicationObject1() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A03, 0x01)
+}
+
+// SetTPDOMappingParameter1A03ApplicationObject1 writes "TPDO mapping parameter / Application object 1" (x1A03:01)
+func (d *Device) SetTPDOMappingParameter1A03ApplicationObject1(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A03, 0x01, value)
+}
+
+// TPDOMappingParameter1A03ApplicationObject2 reads "TPDO mapping parameter / Application object 2" (x1A03:02)
+func (d *Device) TPDOMappingParameter1A03ApplicationObject2() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A03, 0x02)
+}
+
+// SetTPDOMappingParameter1A03ApplicationObject2 writes "TPDO mapping parameter / Application object 2" (x1A03:02)
+func (d *Device) SetTPDOMappingParameter1A03ApplicationObject2(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A03, 0x02, value)
+}
+
+// TPDOMappingParameter1A03ApplicationObject3 reads "TPDO mapping parameter / Application object 3" (x1A03:03)
+func (d *Device) TPDOMappingParameter1A03ApplicationObject3() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A03, 0x03)
+}
+
+// SetTPDOMappingParameter1A03ApplicationObject3 writes "TPDO mapping parameter / Application object 3" (x1A03:03)
+func (d *Device) SetTPDOMappingParameter1A03ApplicationObject3(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A03, 0x03, value)
+}
+
+// TPDOMappingParameter1A03ApplicationObject4 reads "TPDO mapping parameter / Application object 4" (x1A03:04)
+func (d *Device) TPDOMappingParameter1A03ApplicationObject4() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A03, 0x04)
+}
+
+// SetTPDOMappingParameter1A03ApplicationObject4 writes "TPDO mapping parameter / Application object 4" (x1A03:04)
+func (d *Device) SetTPDOMappingParameter1A03ApplicationObject4(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A03, 0x04, value)
+}
+
+// TPDOMappingParameter1A03ApplicationObject5 reads "TPDO mapping parameter / Application object 5" (x1A03:05)
+func (d *Device) TPDOMappingParameter1A03ApplicationObject5() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A03, 0x05)
+}
+
+// SetTPDOMappingParameter1A03ApplicationObject5 writes "TPDO mapping parameter / Application object 5" (x1A03:05)
+func (d *Device) SetTPDOMappingParameter1A03ApplicationObject5(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A03, 0x05, value)
+}
+
+// TPDOMappingParameter1A03ApplicationObject6 reads "TPDO mapping parameter / Application object 6" (x1A03:06)
+func (d *Device) TPDOMappingParameter1A03ApplicationObject6() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A03, 0x06)
+}
+
+// SetTPDOMappingParameter1A03ApplicationObject6 writes "TPDO mapping parameter / Application object 6" (x1A03:06)
+func (d *Device) SetTPDOMappingParameter1A03ApplicationObject6(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A03, 0x06, value)
+}
+
+// TPDOMappingParameter1A03ApplicationObject7 reads "TPDO mapping parameter / Application object 7" (x1A03:07)
+func (d *Device) TPDOMappingParameter1A03ApplicationObject7() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A03, 0x07)
+}
+
+// SetTPDOMappingParameter1A03ApplicationObject7 writes "TPDO mapping parameter / Application object 7" (x1A03:07)
+func (d *Device) SetTPDOMappingParameter1A03ApplicationObject7(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A03, 0x07, value)
+}
+
+// TPDOMappingParameter1A03ApplicationObject8 reads "TPDO mapping parameter / Application object 8" (x1A03:08)
+func (d *Device) TPDOMappingParameter1A03ApplicationObject8() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x1A03, 0x08)
+}
+
+// SetTPDOMappingParameter1A03ApplicationObject8 writes "TPDO mapping parameter / Application object 8" (x1A03:08)
+func (d *Device) SetTPDOMappingParameter1A03ApplicationObject8(value uint32) error {
+	return node.WriteValue(d.accessor, 0x1A03, 0x08, value)
+}
+
+// BOOLEANValue reads "BOOLEAN value" (x2001:00)
+func (d *Device) BOOLEANValue() (bool, error) {
+	return node.ReadValue[bool](d.accessor, 0x2001, 0x00)
+}
+
+// SetBOOLEANValue writes "BOOLEAN value" (x2001:00)
+func (d *Device) SetBOOLEANValue(value bool) error {
+	return node.WriteValue(d.accessor, 0x2001, 0x00, value)
+}
+
+// INTEGER8Value reads "INTEGER8 value" (x2002:00)
+func (d *Device) INTEGER8Value() (int8, error) {
+	return node.ReadValue[int8](d.accessor, 0x2002, 0x00)
+}
+
+// SetINTEGER8Value writes "INTEGER8 value" (x2002:00)
+func (d *Device) SetINTEGER8Value(value int8) error {
+	return node.WriteValue(d.accessor, 0x2002, 0x00, value)
+}
+
+// INTEGER16Value reads "INTEGER16 value" (x2003:00)
+func (d *Device) INTEGER16Value() (int16, error) {
+	return node.ReadValue[int16](d.accessor, 0x2003, 0x00)
+}
+
+// SetINTEGER16Value writes "INTEGER16 value" (x2003:00)
+func (d *Device) SetINTEGER16Value(value int16) error {
+	return node.WriteValue(d.accessor, 0x2003, 0x00, value)
+}
+
+// INTEGER32Value reads "INTEGER32 value" (x2004:00)
+func (d *Device) INTEGER32Value() (int32, error) {
+	return node.ReadValue[int32](d.accessor, 0x2004, 0x00)
+}
+
+// SetINTEGER32Value writes "INTEGER32 value" (x2004:00)
+func (d *Device) SetINTEGER32Value(value int32) error {
+	return node.WriteValue(d.accessor, 0x2004, 0x00, value)
+}
+
+// UNSIGNED8Value reads "UNSIGNED8 value" (x2005:00)
+func (d *Device) UNSIGNED8Value() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x2005, 0x00)
+}
+
+// SetUNSIGNED8Value writes "UNSIGNED8 value" (x2005:00)
+func (d *Device) SetUNSIGNED8Value(value uint8) error {
+	return node.WriteValue(d.accessor, 0x2005, 0x00, value)
+}
+
+// UNSIGNED16Value reads "UNSIGNED16 value" (x2006:00)
+func (d *Device) UNSIGNED16Value() (uint16, error) {
+	return node.ReadValue[uint16](d.accessor, 0x2006, 0x00)
+}
+
+// SetUNSIGNED16Value writes "UNSIGNED16 value" (x2006:00)
+func (d *Device) SetUNSIGNED16Value(value uint16) error {
+	return node.WriteValue(d.accessor, 0x2006, 0x00, value)
+}
+
+// UNSIGNED32Value reads "UNSIGNED32 value" (x2007:00)
+func (d *Device) UNSIGNED32Value() (uint32, error) {
+	return node.ReadValue[uint32](d.accessor, 0x2007, 0x00)
+}
+
+// SetUNSIGNED32Value writes "UNSIGNED32 value" (x2007:00)
+func (d *Device) SetUNSIGNED32Value(value uint32) error {
+	return node.WriteValue(d.accessor, 0x2007, 0x00, value)
+}
+
+// REAL32Value reads "REAL32 value" (x2008:00)
+func (d *Device) REAL32Value() (float32, error) {
+	return node.ReadValue[float32](d.accessor, 0x2008, 0x00)
+}
+
+// SetREAL32Value writes "REAL32 value" (x2008:00)
+func (d *Device) SetREAL32Value(value float32) error {
+	return node.WriteValue(d.accessor, 0x2008, 0x00, value)
+}
+
+// VISIBLESTRINGValue reads "VISIBLE STRING value" (x2009:00)
+func (d *Device) VISIBLESTRINGValue() (string, error) {
+	return node.ReadValue[string](d.accessor, 0x2009, 0x00)
+}
+
+// SetVISIBLESTRINGValue writes "VISIBLE STRING value" (x2009:00)
+func (d *Device) SetVISIBLESTRINGValue(value string) error {
+	return node.WriteValue(d.accessor, 0x2009, 0x00, value)
+}
+
+// REAL64Value reads "REAL64 value" (x2011:00)
+func (d *Device) REAL64Value() (float64, error) {
+	return node.ReadValue[float64](d.accessor, 0x2011, 0x00)
+}
+
+// SetREAL64Value writes "REAL64 value" (x2011:00)
+func (d *Device) SetREAL64Value(value float64) error {
+	return node.WriteValue(d.accessor, 0x2011, 0x00, value)
+}
+
+// INTEGER64Value reads "INTEGER64 value" (x2015:00)
+func (d *Device) INTEGER64Value() (int64, error) {
+	return node.ReadValue[int64](d.accessor, 0x2015, 0x00)
+}
+
+// SetINTEGER64Value writes "INTEGER64 value" (x2015:00)
+func (d *Device) SetINTEGER64Value(value int64) error {
+	return node.WriteValue(d.accessor, 0x2015, 0x00, value)
+}
+
+// UNSIGNED64Value reads "UNSIGNED64 value" (x201B:00)
+func (d *Device) UNSIGNED64Value() (uint64, error) {
+	return node.ReadValue[uint64](d.accessor, 0x201B, 0x00)
+}
+
+// SetUNSIGNED64Value writes "UNSIGNED64 value" (x201B:00)
+func (d *Device) SetUNSIGNED64Value(value uint64) error {
+	return node.WriteValue(d.accessor, 0x201B, 0x00, value)
+}
+
+// READONLY reads "READ ONLY" (x2030:00)
+func (d *Device) READONLY() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x2030, 0x00)
+}
+
+// SetWRITEONLY writes "WRITE ONLY" (x2031:00)
+func (d *Device) SetWRITEONLY(value uint8) error {
+	return node.WriteValue(d.accessor, 0x2031, 0x00, value)
+}
+
+// READWRITE reads "READ WRITE" (x2032:00)
+func (d *Device) READWRITE() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x2032, 0x00)
+}
+
+// SetREADWRITE writes "READ WRITE" (x2032:00)
+func (d *Device) SetREADWRITE(value uint8) error {
+	return node.WriteValue(d.accessor, 0x2032, 0x00, value)
+}
+
+// NOTMAPPABLE reads "NOT MAPPABLE" (x2033:00)
+func (d *Device) NOTMAPPABLE() (uint8, error) {
+	return node.ReadValue[uint8](d.accessor, 0x2033, 0x00)
+}
+
+// SetNOTMAPPABLE writes "NOT MAPPABLE" (x2033:00)
+func (d *Device) SetNOTMAPPABLE(value uint8) error {
+	return node.WriteValue(d.accessor, 0x2033, 0x00, value)
+}
+
+// LOWLIMIT reads "LOW LIMIT" (x2100:00)
+func (d *Device) LOWLIMIT() (int32, error) {
+	return node.ReadValue[int32](d.accessor, 0x2100, 0x00)
+}
+
+// SetLOWLIMIT writes "LOW LIMIT" (x2100:00)
+func (d *Device) SetLOWLIMIT(value int32) error {
+	return node.WriteValue(d.accessor, 0x2100, 0x00, value)
+}
+
+// HIGHLIMIT reads "HIGH LIMIT" (x2101:00)
+func (d *Device) HIGHLIMIT() (int32, error) {
+	return node.ReadValue[int32](d.accessor, 0x2101, 0x00)
+}
+
+// SetHIGHLIMIT writes "HIGH LIMIT" (x2101:00)
+func (d *Device) SetHIGHLIMIT(value int32) error {
+	return node.WriteValue(d.accessor, 0x2101, 0x00, value)
+}
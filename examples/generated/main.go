@@ -0,0 +1,43 @@
+package main
+
+//go:generate go run ../../cmd/canopen-gen -eds ../../pkg/od/base.eds -type Device -o device_gen.go
+
+import (
+	"fmt"
+
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+func main() {
+	network := network.NewNetwork(nil)
+	err := network.Connect("socketcan", "can0", 500_000)
+	if err != nil {
+		panic(err)
+	}
+	defer network.Disconnect()
+
+	node, err := network.AddRemoteNode(0x10, od.Default())
+	if err != nil {
+		panic(err)
+	}
+
+	// Typed accessors generated from the EDS, instead of e.g. node.ReadUint("Producer heartbeat time", "")
+	device := NewDevice(node)
+	name, err := device.ManufacturerDeviceName()
+	if err != nil {
+		fmt.Printf("error reading node %v device name : %v\n", node.GetID(), err)
+	} else {
+		fmt.Printf("node %v device name is %v\n", node.GetID(), name)
+	}
+	err = device.SetProducerHeartbeatTime(1000)
+	if err != nil {
+		fmt.Printf("error writing node %v heartbeat time : %v\n", node.GetID(), err)
+	}
+	vendorId, err := device.IdentityVendorID()
+	if err != nil {
+		fmt.Printf("error reading node %v vendor id : %v\n", node.GetID(), err)
+	} else {
+		fmt.Printf("node %v vendor id is x%x\n", node.GetID(), vendorId)
+	}
+}
@@ -21,6 +21,9 @@ func main() {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	// Command line arguments
 	channel := flag.String("i", Interface, "socketcan channel e.g. can0,vcan0")
+	token := flag.String("token", "", "require this API token (read-write access)")
+	cert := flag.String("cert", "", "TLS certificate file, serve over HTTPS if given with -key")
+	key := flag.String("key", "", "TLS key file")
 	flag.Parse()
 
 	network := network.NewNetwork(nil)
@@ -30,6 +33,22 @@ func main() {
 		panic(e)
 	}
 	gateway := http.NewGatewayServer(&network, logger, 1, 1, 1000)
-	gateway.ListenAndServe(fmt.Sprintf(":%d", Port))
+	if *token != "" {
+		e = gateway.SetAuthorization(&http.AuthOptions{
+			Principal:  http.BearerTokenPrincipal(map[string]string{*token: "user"}),
+			Authorizer: http.RoleAuthorizer(map[string]http.Role{"user": http.RoleReadWrite}),
+		})
+		if e != nil {
+			panic(e)
+		}
+	}
+	if *cert != "" && *key != "" {
+		e = gateway.ListenAndServeTLS(fmt.Sprintf(":%d", Port), *cert, *key, nil)
+	} else {
+		e = gateway.ListenAndServe(fmt.Sprintf(":%d", Port))
+	}
+	if e != nil {
+		panic(e)
+	}
 
 }
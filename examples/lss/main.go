@@ -0,0 +1,98 @@
+// Example of node-id & bitrate commissioning using LSS (CiA 305)
+//
+//	go run main.go -vendor 0x1 -product 0x2 -revision 0x3 -serial 0x4 -node-id 0x20
+//	go run main.go -vendor 0x1 -product 0x2 -revision 0x3 -serial 0x4 -new-bitrate 250000
+//	go run main.go -vendor 0x1 -product 0x2 -revision 0x3 -serial 0x4 -read
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/network"
+)
+
+var DEFAULT_CAN_INTERFACE = "socketcan"
+var DEFAULT_CAN_CHANNEL = "can0"
+var DEFAULT_CAN_BITRATE = 500_000
+var DEFAULT_SWITCH_DELAY = 100 * time.Millisecond
+
+// Ask user for confirmation before modifying the device
+func confirm(prompt string) bool {
+	fmt.Printf("%v [y/N] ", prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func main() {
+	canInterface := flag.String("i", DEFAULT_CAN_INTERFACE, "CAN interface")
+	channel := flag.String("c", DEFAULT_CAN_CHANNEL, "CAN channel")
+	bitrate := flag.Int("b", DEFAULT_CAN_BITRATE, "CAN bitrate")
+	vendor := flag.Uint("vendor", 0, "vendor id of the device")
+	product := flag.Uint("product", 0, "product code of the device")
+	revision := flag.Uint("revision", 0, "revision number of the device")
+	serial := flag.Uint("serial", 0, "serial number of the device")
+	nodeId := flag.Uint("node-id", 0, "new node-id to configure (1-127)")
+	newBitrate := flag.Int("new-bitrate", 0, "new bitrate to configure")
+	read := flag.Bool("read", false, "read current node-id of the device")
+	yes := flag.Bool("y", false, "do not ask for confirmation")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	network := network.NewNetwork(nil)
+	network.SetLogger(logger)
+
+	err := network.Connect(*canInterface, *channel, *bitrate)
+	if err != nil {
+		panic(err)
+	}
+	defer network.Disconnect()
+
+	identity := config.Identity{
+		VendorId:       uint32(*vendor),
+		ProductCode:    uint32(*product),
+		RevisionNumber: uint32(*revision),
+		SerialNumber:   uint32(*serial),
+	}
+	// LSS does not use the node-id of the configurator
+	configurator := network.Configurator(0)
+
+	switch {
+	case *read:
+		id, err := configurator.ReadNodeIdViaLSS(identity)
+		if err != nil {
+			logger.Error("failed to read node-id", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("node-id : x%x\n", id)
+	case *nodeId != 0:
+		if !*yes && !confirm(fmt.Sprintf("set node-id of %+v to x%x ?", identity, *nodeId)) {
+			return
+		}
+		err := configurator.SetNodeIdViaLSS(identity, uint8(*nodeId))
+		if err != nil {
+			logger.Error("failed to set node-id", "error", err)
+			os.Exit(1)
+		}
+		fmt.Println("node-id stored, active after next reset communication")
+	case *newBitrate != 0:
+		if !*yes && !confirm(fmt.Sprintf("set bitrate of %+v to %v ?", identity, *newBitrate)) {
+			return
+		}
+		err := configurator.SetBitrateViaLSS(identity, *newBitrate, DEFAULT_SWITCH_DELAY)
+		if err != nil {
+			logger.Error("failed to set bitrate", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("bitrate stored, device now uses %v\n", *newBitrate)
+	default:
+		flag.Usage()
+	}
+}
@@ -3,6 +3,7 @@ package main
 // Demo used for automated testing
 
 import (
+	"context"
 	"log/slog"
 	"os"
 
@@ -45,5 +46,9 @@ func main() {
 	}
 	//Add file extension
 	node.GetOD().AddFile(0x200F, "File", "example.bin", os.O_RDONLY|os.O_CREATE, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
-	select {}
+	// Run until interrupted, then stop nodes and disconnect cleanly
+	err = network.RunUntilSignal(context.Background())
+	if err != nil {
+		logger.Error("shutdown failed", "error", err)
+	}
 }
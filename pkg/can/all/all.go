@@ -2,6 +2,9 @@ package all
 
 import (
 	_ "github.com/samsamfire/gocanopen/pkg/can/kvaser"
+	_ "github.com/samsamfire/gocanopen/pkg/can/redundant"
+	_ "github.com/samsamfire/gocanopen/pkg/can/replay"
+	_ "github.com/samsamfire/gocanopen/pkg/can/slcan"
 	_ "github.com/samsamfire/gocanopen/pkg/can/socketcanv2"
 	_ "github.com/samsamfire/gocanopen/pkg/can/virtual"
 )
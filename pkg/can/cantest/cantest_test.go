@@ -0,0 +1,78 @@
+package cantest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	frame := MustParseFrame("601#4000100000000000")
+	assert.EqualValues(t, 0x601, frame.ID)
+	assert.EqualValues(t, 8, frame.DLC)
+	assert.EqualValues(t, 0x40, frame.Data[0])
+	assert.Equal(t, "601#4000100000000000", FormatFrame(frame))
+	_, err := ParseFrame("601")
+	assert.NotNil(t, err)
+	_, err = ParseFrame("601#001122334455667788")
+	assert.NotNil(t, err)
+
+	buf := &bytes.Buffer{}
+	frames := []canopen.Frame{frame, MustParseFrame("000#0110"), MustParseFrame("080#")}
+	assert.Nil(t, WriteTrace(buf, frames))
+	read, err := ReadTrace(bytes.NewBufferString("# comment\n\n" + buf.String()))
+	assert.Nil(t, err)
+	assert.Equal(t, "", DiffTrace(frames, read))
+	assert.Contains(t, DiffTrace(frames, read[:2]), "missing 080#")
+	assert.Contains(t, DiffTrace(frames[:2], read), "unexpected 080#")
+}
+
+func TestMockBusClient(t *testing.T) {
+	bus := NewMockBus(true)
+	net := network.NewNetwork(bus)
+	assert.Nil(t, net.Connect())
+	defer net.Disconnect()
+
+	// Script a remote node 0x10 answering an expedited upload of 0x1000
+	bus.ExpectFrame(MustParseFrame("610#4000100000000000")).
+		Respond(MustParseFrame("590#4300100092010200")).
+		After(10 * time.Millisecond)
+	deviceType, err := net.ReadUint32(0x10, 0x1000, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x00020192, deviceType)
+	bus.AssertExpectations(t)
+	AssertGolden(t, "testdata/sdo_upload.trace", bus.Sent())
+
+	// No answer, client should send an abort after timeout
+	bus.Reset()
+	bus.Expect("upload request", MatchPrefix(0x610, 0x40))
+	bus.Expect("abort", MatchPrefix(0x610, 0x80))
+	net.SDOClient.SetTimeout(50)
+	_, err = net.ReadUint32(0x10, 0x1000, 0)
+	assert.NotNil(t, err)
+	bus.AssertExpectations(t)
+}
+
+func TestMockBusLocalNode(t *testing.T) {
+	bus := NewMockBus(false)
+	net := network.NewNetwork(bus)
+	assert.Nil(t, net.Connect())
+	defer net.Disconnect()
+	_, err := net.CreateLocalNode(0x20, od.Default())
+	assert.Nil(t, err)
+
+	// Boot-up message
+	bus.WaitFor(t, MatchFrame(MustParseFrame("720#00")), time.Second)
+	// Read BOOLEAN value
+	response := bus.AssertExchange(t,
+		MustParseFrame("620#4001200000000000"),
+		MatchPrefix(0x5A0, 0x4F, 0x01, 0x20, 0x00),
+		time.Second,
+	)
+	assert.EqualValues(t, 0x01, response.Data[4])
+}
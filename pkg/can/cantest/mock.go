@@ -0,0 +1,272 @@
+// Package cantest provides utilities for protocol-level testing of
+// applications using gocanopen, without needing a real or virtual CAN bus.
+package cantest
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+)
+
+// Returns true if frame matches
+type Matcher func(frame canopen.Frame) bool
+
+// Match frames with the given CAN id
+func MatchId(id uint32) Matcher {
+	return func(frame canopen.Frame) bool {
+		return frame.ID == id
+	}
+}
+
+// Match frames with the same id, DLC and data
+func MatchFrame(expected canopen.Frame) Matcher {
+	return func(frame canopen.Frame) bool {
+		length := min(frame.DLC, 8)
+		return frame.ID == expected.ID && frame.DLC == expected.DLC &&
+			slices.Equal(frame.Data[:length], expected.Data[:length])
+	}
+}
+
+// Match frames with the given id and whose data starts with prefix
+func MatchPrefix(id uint32, prefix ...byte) Matcher {
+	return func(frame canopen.Frame) bool {
+		return frame.ID == id && int(frame.DLC) >= len(prefix) &&
+			slices.Equal(frame.Data[:len(prefix)], prefix)
+	}
+}
+
+// An expected frame and the frames to reply with
+type Expectation struct {
+	description string
+	match       Matcher
+	responses   []canopen.Frame
+	delay       time.Duration
+	times       int // remaining number of matches, -1 for unlimited
+	met         bool
+}
+
+// Respond with the given frames when expectation is met
+func (e *Expectation) Respond(frames ...canopen.Frame) *Expectation {
+	e.responses = append(e.responses, frames...)
+	return e
+}
+
+// Delay the responses
+func (e *Expectation) After(delay time.Duration) *Expectation {
+	e.delay = delay
+	return e
+}
+
+// Expect the frame n times instead of once
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Expect the frame any number of times, including none
+func (e *Expectation) Always() *Expectation {
+	e.times = -1
+	e.met = true
+	return e
+}
+
+// MockBus is a scriptable implementation of [canopen.Bus].
+// Every sent frame is recorded and can be matched against expectations,
+// which can trigger responses as if they were received from the bus.
+type MockBus struct {
+	mu           sync.Mutex
+	listener     canopen.FrameListener
+	ordered      bool
+	sent         []canopen.Frame
+	unexpected   []canopen.Frame
+	expectations []*Expectation
+	wg           sync.WaitGroup
+}
+
+// Create a new mock bus. If ordered is true, expectations
+// must be met in the order in which they were added.
+func NewMockBus(ordered bool) *MockBus {
+	return &MockBus{ordered: ordered}
+}
+
+// "Connect" implementation of Bus interface
+func (b *MockBus) Connect(...any) error {
+	return nil
+}
+
+// "Disconnect" implementation of Bus interface, waits for pending responses
+func (b *MockBus) Disconnect() error {
+	b.wg.Wait()
+	return nil
+}
+
+// "Subscribe" implementation of Bus interface
+func (b *MockBus) Subscribe(listener canopen.FrameListener) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listener = listener
+	return nil
+}
+
+// "Send" implementation of Bus interface
+func (b *MockBus) Send(frame canopen.Frame) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, frame)
+	for _, e := range b.expectations {
+		if e.times == 0 {
+			continue
+		}
+		if e.match(frame) {
+			if e.times > 0 {
+				e.times--
+			}
+			if e.times == 0 {
+				e.met = true
+			}
+			b.respond(e.responses, e.delay)
+			return nil
+		}
+		if b.ordered && e.times > 0 {
+			break
+		}
+	}
+	b.unexpected = append(b.unexpected, frame)
+	return nil
+}
+
+func (b *MockBus) respond(frames []canopen.Frame, delay time.Duration) {
+	if len(frames) == 0 {
+		return
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		for _, frame := range frames {
+			b.Inject(frame)
+		}
+	}()
+}
+
+// Expect a frame matching match to be sent
+func (b *MockBus) Expect(description string, match Matcher) *Expectation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e := &Expectation{description: description, match: match, times: 1}
+	b.expectations = append(b.expectations, e)
+	return e
+}
+
+// Expect exactly the given frame to be sent
+func (b *MockBus) ExpectFrame(frame canopen.Frame) *Expectation {
+	return b.Expect(FormatFrame(frame), MatchFrame(frame))
+}
+
+// Inject a frame as if it was received from the bus
+func (b *MockBus) Inject(frame canopen.Frame) {
+	b.mu.Lock()
+	listener := b.listener
+	b.mu.Unlock()
+	if listener != nil {
+		listener.Handle(frame)
+	}
+}
+
+// All the frames sent so far
+func (b *MockBus) Sent() []canopen.Frame {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.sent)
+}
+
+// Frames sent that did not match any expectation
+func (b *MockBus) Unexpected() []canopen.Frame {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.unexpected)
+}
+
+// Clear recorded frames and expectations
+func (b *MockBus) Reset() {
+	b.wg.Wait()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = nil
+	b.unexpected = nil
+	b.expectations = nil
+}
+
+// Returns an error describing the expectations not met
+func (b *MockBus) Verify() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	unmet := []string{}
+	for _, e := range b.expectations {
+		if !e.met {
+			unmet = append(unmet, e.description)
+		}
+	}
+	if len(unmet) > 0 {
+		return fmt.Errorf("unmet expectations : %v", unmet)
+	}
+	return nil
+}
+
+// Fail test if some expectations are not met or unexpected frames were sent
+func (b *MockBus) AssertExpectations(t testing.TB) {
+	t.Helper()
+	if err := b.Verify(); err != nil {
+		t.Error(err)
+	}
+	if unexpected := b.Unexpected(); len(unexpected) > 0 {
+		t.Errorf("unexpected frames sent : %v", FormatFrames(unexpected))
+	}
+}
+
+// Wait for a frame matching match to be sent, starting from the first sent frame.
+// Test fails if no such frame is sent within timeout.
+func (b *MockBus) WaitFor(t testing.TB, match Matcher, timeout time.Duration) canopen.Frame {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		for _, frame := range b.Sent() {
+			if match(frame) {
+				return frame
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no matching frame sent within %v, sent : %v", timeout, FormatFrames(b.Sent()))
+			return canopen.Frame{}
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Inject request and assert that a frame matching response is then sent,
+// e.g. for checking the answer of a local node.
+func (b *MockBus) AssertExchange(t testing.TB, request canopen.Frame, response Matcher, timeout time.Duration) canopen.Frame {
+	t.Helper()
+	start := len(b.Sent())
+	b.Inject(request)
+	deadline := time.Now().Add(timeout)
+	for {
+		sent := b.Sent()
+		for _, frame := range sent[start:] {
+			if response(frame) {
+				return frame
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no response to %v within %v, sent : %v", FormatFrame(request), timeout, FormatFrames(sent[start:]))
+			return canopen.Frame{}
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
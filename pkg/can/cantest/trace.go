@@ -0,0 +1,138 @@
+package cantest
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	canopen "github.com/samsamfire/gocanopen"
+)
+
+// If true, [AssertGolden] overwrites golden files instead of comparing.
+// This can be bound to a test flag e.g.
+//
+//	flag.BoolVar(&cantest.UpdateGolden, "update", false, "update golden files")
+var UpdateGolden = false
+
+// Format a frame in candump compact format e.g. "601#4000100000000000"
+func FormatFrame(frame canopen.Frame) string {
+	return fmt.Sprintf("%03X#%s", frame.ID, strings.ToUpper(hex.EncodeToString(frame.Data[:min(frame.DLC, 8)])))
+}
+
+// Format frames, one per line
+func FormatFrames(frames []canopen.Frame) string {
+	lines := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		lines = append(lines, FormatFrame(frame))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Parse a frame in candump compact format e.g. "601#4000100000000000"
+func ParseFrame(s string) (canopen.Frame, error) {
+	idStr, dataStr, ok := strings.Cut(strings.TrimSpace(s), "#")
+	if !ok {
+		return canopen.Frame{}, fmt.Errorf("invalid frame %q : missing '#'", s)
+	}
+	id, err := strconv.ParseUint(idStr, 16, 32)
+	if err != nil {
+		return canopen.Frame{}, fmt.Errorf("invalid frame id %q : %w", idStr, err)
+	}
+	data, err := hex.DecodeString(dataStr)
+	if err != nil || len(data) > 8 {
+		return canopen.Frame{}, fmt.Errorf("invalid frame data %q", dataStr)
+	}
+	frame := canopen.NewFrame(uint32(id), 0, uint8(len(data)))
+	copy(frame.Data[:], data)
+	return frame, nil
+}
+
+// Helper for creating a frame from candump compact format, panics on error
+func MustParseFrame(s string) canopen.Frame {
+	frame, err := ParseFrame(s)
+	if err != nil {
+		panic(err)
+	}
+	return frame
+}
+
+// Write a trace, one frame per line
+func WriteTrace(w io.Writer, frames []canopen.Frame) error {
+	for _, frame := range frames {
+		if _, err := fmt.Fprintln(w, FormatFrame(frame)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read a trace, one frame per line. Empty lines and lines
+// starting with '#' are ignored.
+func ReadTrace(r io.Reader) ([]canopen.Frame, error) {
+	frames := []canopen.Frame{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		frame, err := ParseFrame(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %v : %w", line, err)
+		}
+		frames = append(frames, frame)
+	}
+	return frames, scanner.Err()
+}
+
+// Compare frames with expected frames, returns a description
+// of the first difference or an empty string if they are equal
+func DiffTrace(expected []canopen.Frame, actual []canopen.Frame) string {
+	for i := range max(len(expected), len(actual)) {
+		switch {
+		case i >= len(expected):
+			return fmt.Sprintf("frame %v : unexpected %v", i, FormatFrame(actual[i]))
+		case i >= len(actual):
+			return fmt.Sprintf("frame %v : missing %v", i, FormatFrame(expected[i]))
+		case !MatchFrame(expected[i])(actual[i]):
+			return fmt.Sprintf("frame %v : expected %v, got %v", i, FormatFrame(expected[i]), FormatFrame(actual[i]))
+		}
+	}
+	return ""
+}
+
+// Compare frames with the golden trace stored at path.
+// If [UpdateGolden] is set, golden trace is written instead.
+func AssertGolden(t testing.TB, path string, frames []canopen.Frame) {
+	t.Helper()
+	if UpdateGolden {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("failed to update golden trace : %v", err)
+		}
+		defer f.Close()
+		if err := WriteTrace(f, frames); err != nil {
+			t.Fatalf("failed to update golden trace : %v", err)
+		}
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open golden trace : %v", err)
+	}
+	defer f.Close()
+	expected, err := ReadTrace(f)
+	if err != nil {
+		t.Fatalf("failed to read golden trace : %v", err)
+	}
+	if diff := DiffTrace(expected, frames); diff != "" {
+		t.Errorf("trace differs from golden %v : %v", path, diff)
+	}
+}
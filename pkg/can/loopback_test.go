@@ -0,0 +1,46 @@
+package can
+
+import (
+	"testing"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	frames []canopen.Frame
+}
+
+func (r *recorder) Handle(frame canopen.Frame) { r.frames = append(r.frames, frame) }
+
+func TestLoopbackBus(t *testing.T) {
+	bus1, bus2, bus3 := NewLoopbackBus("test"), NewLoopbackBus("test"), NewLoopbackBus("other")
+	rec1, rec2, rec3 := &recorder{}, &recorder{}, &recorder{}
+	for i, bus := range []*LoopbackBus{bus1, bus2, bus3} {
+		assert.Nil(t, bus.Subscribe([]*recorder{rec1, rec2, rec3}[i]))
+	}
+	frame := canopen.NewFrame(0x123, 0, 1)
+	assert.Equal(t, ErrLoopbackNotConnected, bus1.Send(frame))
+	for _, bus := range []*LoopbackBus{bus1, bus2, bus3} {
+		assert.Nil(t, bus.Connect())
+		defer bus.Disconnect()
+	}
+	assert.Nil(t, bus1.Send(frame))
+	assert.Equal(t, []canopen.Frame{frame}, rec2.frames)
+	assert.Empty(t, rec1.frames)
+	assert.Empty(t, rec3.frames)
+
+	bus1.SetReceiveOwn(true)
+	assert.Nil(t, bus1.Send(frame))
+	assert.Len(t, rec1.frames, 1)
+	assert.Len(t, rec2.frames, 2)
+
+	assert.Nil(t, bus2.Disconnect())
+	assert.Nil(t, bus1.Send(frame))
+	assert.Len(t, rec2.frames, 2)
+
+	// Available as a regular driver
+	bus, err := NewBus("loopback", "test", 0)
+	assert.Nil(t, err)
+	assert.IsType(t, &LoopbackBus{}, bus)
+}
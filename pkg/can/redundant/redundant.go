@@ -0,0 +1,296 @@
+// Package redundant implements a CAN bus running over two physical CAN interfaces,
+// with automatic failover from the active interface to the other one when it goes
+// bus-off, is lost or becomes silent. Subscriptions are held by the [canopen.BusManager],
+// so all the COB-IDs are received transparently from the new active interface.
+package redundant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	can "github.com/samsamfire/gocanopen/pkg/can"
+)
+
+const DefaultCheckPeriod = 100 * time.Millisecond
+
+var (
+	ErrBusOff      = errors.New("interface is bus-off")
+	ErrSilent      = errors.New("interface is silent")
+	ErrChannel     = errors.New("expecting two channels e.g. socketcan:can0,socketcan:can1")
+	ErrNoInterface = errors.New("no interface available")
+)
+
+// The "redundant" driver expects two interfaces as channel, each given
+// as "driver:channel" and separated by a comma. Mode is hot standby e.g.
+//
+//	network.Connect("redundant", "socketcan:can0,socketcan:can1", 500_000)
+func init() {
+	_ = can.RegisterDriver("redundant", func(channel string, bitrate int) (canopen.Bus, error) {
+		primary, backup, err := newBuses(channel, bitrate)
+		if err != nil {
+			return nil, err
+		}
+		return NewBus(primary, backup, Options{}), nil
+	})
+}
+
+// Create the buses of a "driver:channel,driver:channel" description
+func newBuses(channel string, bitrate int) (canopen.Bus, canopen.Bus, error) {
+	parts := strings.Split(channel, ",")
+	if len(parts) != 2 {
+		return nil, nil, ErrChannel
+	}
+	buses := make([]canopen.Bus, 0, 2)
+	for _, part := range parts {
+		driver, name, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, nil, fmt.Errorf("%w, got %v", ErrChannel, part)
+		}
+		bus, err := can.NewBus(driver, name, bitrate)
+		if err != nil {
+			return nil, nil, err
+		}
+		buses = append(buses, bus)
+	}
+	return buses[0], buses[1], nil
+}
+
+// Redundancy mode
+type Mode uint8
+
+const (
+	// Frames are sent on the active interface only, the other
+	// one is kept connected and monitored, ready for failover.
+	ModeHotStandby Mode = 0
+	// Frames are sent on both interfaces. Frames are received
+	// from the active interface only, so that they are not duplicated.
+	ModeParallel Mode = 1
+)
+
+// Failover from an interface to the other one, interfaces are 0 (primary) & 1 (backup)
+type FailoverEvent struct {
+	From   int
+	To     int
+	Time   time.Time
+	Reason error
+}
+
+// Options of a redundant [Bus]
+type Options struct {
+	Mode Mode
+	// Period for checking the active interface, defaults to [DefaultCheckPeriod]
+	CheckPeriod time.Duration
+	// The active interface is considered silent if nothing was received for this time,
+	// while frames are received on the other interface. 0 disables silence detection.
+	SilenceTimeout time.Duration
+	// Called on every failover, from the monitoring goroutine
+	OnFailover func(event FailoverEvent)
+}
+
+// One of the interfaces
+type channel struct {
+	parent *Bus
+	index  int
+	bus    canopen.Bus
+	lastRx atomic.Int64 // Unix nanoseconds
+	// Error when connecting, if any
+	connectErr error
+}
+
+// Frames are only forwarded from the active interface
+func (c *channel) Handle(frame canopen.Frame) {
+	c.lastRx.Store(time.Now().UnixNano())
+	if int(c.parent.active.Load()) != c.index {
+		return
+	}
+	c.parent.mu.Lock()
+	listener := c.parent.listener
+	c.parent.mu.Unlock()
+	if listener != nil {
+		listener.Handle(frame)
+	}
+}
+
+// Health of the interface, nil if healthy
+func (c *channel) health(now time.Time, silenceTimeout time.Duration, other *channel) error {
+	if c.connectErr != nil {
+		return c.connectErr
+	}
+	if reporter, ok := c.bus.(canopen.BusErrorReporter); ok && reporter.Err() != nil {
+		return reporter.Err()
+	}
+	if reporter, ok := c.bus.(canopen.BusErrorStatusReporter); ok && reporter.ErrorStatus()&canopen.CanErrorTxBusOff != 0 {
+		return ErrBusOff
+	}
+	if silenceTimeout > 0 && other != nil &&
+		now.Sub(time.Unix(0, c.lastRx.Load())) > silenceTimeout &&
+		now.Sub(time.Unix(0, other.lastRx.Load())) <= silenceTimeout {
+		return ErrSilent
+	}
+	return nil
+}
+
+// Bus is a [canopen.Bus] over two interfaces, the primary one is active after connecting
+type Bus struct {
+	mu       sync.Mutex
+	logger   *slog.Logger
+	opts     Options
+	channels [2]*channel
+	active   atomic.Int32
+	listener canopen.FrameListener
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// Create a redundant bus from two buses, primary is active initially
+func NewBus(primary canopen.Bus, backup canopen.Bus, opts Options) *Bus {
+	if opts.CheckPeriod <= 0 {
+		opts.CheckPeriod = DefaultCheckPeriod
+	}
+	b := &Bus{logger: slog.Default(), opts: opts}
+	for i, bus := range []canopen.Bus{primary, backup} {
+		b.channels[i] = &channel{parent: b, index: i, bus: bus}
+	}
+	return b
+}
+
+// "Connect" implementation of Bus interface, both interfaces are connected.
+// Connection only fails if none of them could be connected. If the primary
+// interface could not be connected, the backup interface is active.
+func (b *Bus) Connect(args ...any) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		return nil
+	}
+	for _, c := range b.channels {
+		err := c.bus.Connect(args...)
+		if err == nil {
+			err = c.bus.Subscribe(c)
+		}
+		if err != nil {
+			b.logger.Warn("failed to connect interface", "index", c.index, "err", err)
+		}
+		c.connectErr = err
+		c.lastRx.Store(time.Now().UnixNano())
+	}
+	primary, backup := b.channels[0].connectErr, b.channels[1].connectErr
+	if primary != nil && backup != nil {
+		return errors.Join(ErrNoInterface, primary, backup)
+	}
+	if primary != nil {
+		b.active.Store(1)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	go b.monitor(ctx, b.done)
+	return nil
+}
+
+// "Disconnect" implementation of Bus interface, both interfaces are disconnected
+func (b *Bus) Disconnect() error {
+	b.mu.Lock()
+	cancel, done := b.cancel, b.done
+	b.cancel = nil
+	b.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	return errors.Join(b.channels[0].bus.Disconnect(), b.channels[1].bus.Disconnect())
+}
+
+// "Send" implementation of Bus interface. In parallel mode, frames are sent on both
+// interfaces and only the error of the active interface is returned.
+func (b *Bus) Send(frame canopen.Frame) error {
+	active := b.Active()
+	if b.opts.Mode == ModeParallel {
+		_ = b.channels[1-active].bus.Send(frame)
+	}
+	return b.channels[active].bus.Send(frame)
+}
+
+// "Subscribe" implementation of Bus interface
+func (b *Bus) Subscribe(listener canopen.FrameListener) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listener = listener
+	return nil
+}
+
+// Implements [canopen.BusErrorStatusReporter], error status of the active interface
+func (b *Bus) ErrorStatus() uint16 {
+	reporter, ok := b.channels[b.Active()].bus.(canopen.BusErrorStatusReporter)
+	if !ok {
+		return 0
+	}
+	return reporter.ErrorStatus()
+}
+
+// Implements [canopen.BusErrorReporter], an error is only reported
+// if both interfaces are lost, e.g. for reconnection by the network.
+func (b *Bus) Err() error {
+	var errs []error
+	for _, c := range b.channels {
+		reporter, ok := c.bus.(canopen.BusErrorReporter)
+		if !ok || reporter.Err() == nil {
+			return nil
+		}
+		errs = append(errs, reporter.Err())
+	}
+	return errors.Join(errs...)
+}
+
+// Index of the active interface, 0 for primary & 1 for backup
+func (b *Bus) Active() int {
+	return int(b.active.Load())
+}
+
+// Switch to the given interface manually, 0 for primary & 1 for backup
+func (b *Bus) SetActive(index int) error {
+	if index != 0 && index != 1 {
+		return canopen.ErrIllegalArgument
+	}
+	b.switchTo(index, nil)
+	return nil
+}
+
+func (b *Bus) switchTo(index int, reason error) {
+	from := int(b.active.Swap(int32(index)))
+	if from == index {
+		return
+	}
+	b.logger.Warn("switched CAN interface", "from", from, "to", index, "reason", reason)
+	if b.opts.OnFailover != nil {
+		b.opts.OnFailover(FailoverEvent{From: from, To: index, Time: time.Now(), Reason: reason})
+	}
+}
+
+// Check the active interface periodically and fail over
+// to the other one if it is unhealthy, unless it is also unhealthy.
+func (b *Bus) monitor(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.opts.CheckPeriod):
+		}
+		now := time.Now()
+		active := b.Active()
+		current, other := b.channels[active], b.channels[1-active]
+		reason := current.health(now, b.opts.SilenceTimeout, other)
+		if reason == nil || other.health(now, b.opts.SilenceTimeout, current) != nil {
+			continue
+		}
+		b.switchTo(other.index, reason)
+	}
+}
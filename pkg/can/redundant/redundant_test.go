@@ -0,0 +1,160 @@
+package redundant
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	can "github.com/samsamfire/gocanopen/pkg/can"
+	"github.com/stretchr/testify/assert"
+)
+
+type frameRecorder struct {
+	mu     sync.Mutex
+	frames []canopen.Frame
+}
+
+func (r *frameRecorder) Handle(frame canopen.Frame) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.frames = append(r.frames, frame)
+}
+
+func (r *frameRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.frames)
+}
+
+// Loopback bus that can be put in bus-off
+type busOffBus struct {
+	*can.LoopbackBus
+	busOff atomic.Bool
+}
+
+func (b *busOffBus) ErrorStatus() uint16 {
+	if b.busOff.Load() {
+		return canopen.CanErrorTxBusOff
+	}
+	return 0
+}
+
+// Peer on a channel, recording received frames
+func newPeer(t *testing.T, channel string) (*can.LoopbackBus, *frameRecorder) {
+	peer := can.NewLoopbackBus(channel)
+	recorder := &frameRecorder{}
+	assert.Nil(t, peer.Connect())
+	assert.Nil(t, peer.Subscribe(recorder))
+	t.Cleanup(func() { peer.Disconnect() })
+	return peer, recorder
+}
+
+func TestHotStandby(t *testing.T) {
+	peerA, receivedA := newPeer(t, "redundant-a")
+	peerB, receivedB := newPeer(t, "redundant-b")
+	primary := &busOffBus{LoopbackBus: can.NewLoopbackBus("redundant-a")}
+	events := make(chan FailoverEvent, 10)
+	bus := NewBus(primary, can.NewLoopbackBus("redundant-b"), Options{
+		CheckPeriod: 5 * time.Millisecond,
+		OnFailover:  func(event FailoverEvent) { events <- event },
+	})
+	// Subscriptions are kept by the bus manager on failover
+	bm := canopen.NewBusManager(bus)
+	received := &frameRecorder{}
+	assert.Nil(t, bm.Subscribe(0x181, 0x7FF, false, received))
+	assert.Nil(t, bus.Connect())
+	assert.Nil(t, bus.Subscribe(bm))
+	defer bus.Disconnect()
+	assert.Equal(t, 0, bus.Active())
+
+	assert.Nil(t, bus.Send(canopen.NewFrame(0x201, 0, 8)))
+	assert.Equal(t, 1, receivedA.count())
+	assert.Equal(t, 0, receivedB.count())
+	assert.Nil(t, peerA.Send(canopen.NewFrame(0x181, 0, 8)))
+	assert.Nil(t, peerB.Send(canopen.NewFrame(0x181, 0, 8)))
+	assert.Equal(t, 1, received.count())
+
+	primary.busOff.Store(true)
+	assert.EqualValues(t, canopen.CanErrorTxBusOff, bus.ErrorStatus())
+	select {
+	case event := <-events:
+		assert.Equal(t, 0, event.From)
+		assert.Equal(t, 1, event.To)
+		assert.ErrorIs(t, event.Reason, ErrBusOff)
+	case <-time.After(time.Second):
+		t.Fatal("no failover")
+	}
+	assert.Equal(t, 1, bus.Active())
+	assert.EqualValues(t, 0, bus.ErrorStatus())
+	assert.Nil(t, bus.Send(canopen.NewFrame(0x201, 0, 8)))
+	assert.Equal(t, 1, receivedA.count())
+	assert.Equal(t, 1, receivedB.count())
+	assert.Nil(t, peerB.Send(canopen.NewFrame(0x181, 0, 8)))
+	assert.Nil(t, peerA.Send(canopen.NewFrame(0x181, 0, 8)))
+	assert.Equal(t, 2, received.count())
+
+	// No failover back to an unhealthy interface
+	assert.Nil(t, bus.SetActive(0))
+	<-events
+	select {
+	case event := <-events:
+		assert.Equal(t, 1, event.To)
+	case <-time.After(time.Second):
+		t.Fatal("no failover")
+	}
+	assert.Equal(t, canopen.ErrIllegalArgument, bus.SetActive(2))
+}
+
+func TestSilenceAndParallel(t *testing.T) {
+	peerA, receivedA := newPeer(t, "redundant-c")
+	peerB, receivedB := newPeer(t, "redundant-d")
+	events := make(chan FailoverEvent, 10)
+	bus := NewBus(can.NewLoopbackBus("redundant-c"), can.NewLoopbackBus("redundant-d"), Options{
+		Mode:           ModeParallel,
+		CheckPeriod:    5 * time.Millisecond,
+		SilenceTimeout: 50 * time.Millisecond,
+		OnFailover:     func(event FailoverEvent) { events <- event },
+	})
+	received := &frameRecorder{}
+	assert.Nil(t, bus.Connect())
+	assert.Nil(t, bus.Subscribe(received))
+	defer bus.Disconnect()
+
+	assert.Nil(t, bus.Send(canopen.NewFrame(0x201, 0, 8)))
+	assert.Equal(t, 1, receivedA.count())
+	assert.Equal(t, 1, receivedB.count())
+
+	// Both interfaces receive traffic, only active is forwarded
+	for range 5 {
+		assert.Nil(t, peerA.Send(canopen.NewFrame(0x181, 0, 8)))
+		assert.Nil(t, peerB.Send(canopen.NewFrame(0x181, 0, 8)))
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 5, received.count())
+	assert.Empty(t, events)
+
+	// Primary becomes silent
+	timeout := time.After(time.Second)
+	for bus.Active() == 0 {
+		select {
+		case <-timeout:
+			t.Fatal("no failover")
+		case <-time.After(10 * time.Millisecond):
+		}
+		assert.Nil(t, peerB.Send(canopen.NewFrame(0x181, 0, 8)))
+	}
+	event := <-events
+	assert.ErrorIs(t, event.Reason, ErrSilent)
+}
+
+func TestDriver(t *testing.T) {
+	bus, err := can.NewBus("redundant", "loopback:redundant-e, loopback:redundant-f", 0)
+	assert.Nil(t, err)
+	assert.IsType(t, &Bus{}, bus)
+	_, err = can.NewBus("redundant", "loopback:redundant-e", 0)
+	assert.ErrorIs(t, err, ErrChannel)
+	_, err = can.NewBus("redundant", "loopback,redundant-e", 0)
+	assert.ErrorIs(t, err, ErrChannel)
+}
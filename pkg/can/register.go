@@ -1,19 +1,105 @@
 package can
 
-import canopen "github.com/samsamfire/gocanopen"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
 
+	canopen "github.com/samsamfire/gocanopen"
+)
+
+var (
+	ErrDriverExists   = errors.New("a driver is already registered with this name")
+	ErrDriverNotFound = errors.New("no driver registered with this name")
+)
+
+// Factory for creating a CAN bus of a given driver.
+// bitrate is the one given when connecting, it can be ignored
+// if the driver has no use for it.
+type DriverFactory func(channel string, bitrate int) (canopen.Bus, error)
+
+// Deprecated: use [DriverFactory] instead
 type NewInterfaceFunc func(channel string) (canopen.Bus, error)
 
+// Deprecated: use [Drivers] & [NewBus] instead, drivers registered
+// with [RegisterDriver] are also available here but bitrate is always 0.
 var AvailableInterfaces = make(map[string]NewInterfaceFunc)
 var ImplementedInterfaces = []string{
 	"socketcan",
 	"socketcanv2",
 	"virtualcan",
 	"kvaser",
+	"slcan",
+	"loopback",
+	"replay",
+	"redundant",
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]DriverFactory)
+)
+
+// Register a new CAN bus driver, e.g. for third-party transports (PCAN, gRPC bridges, ...).
+// This is typically called inside an init() function of the driver package.
+// Once registered, the driver can be used by name with [network.Network.Connect].
+func RegisterDriver(name string, factory DriverFactory) error {
+	if name == "" || factory == nil {
+		return canopen.ErrIllegalArgument
+	}
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if _, ok := drivers[name]; ok {
+		return fmt.Errorf("%w : %v", ErrDriverExists, name)
+	}
+	drivers[name] = factory
+	AvailableInterfaces[name] = func(channel string) (canopen.Bus, error) {
+		return factory(channel, 0)
+	}
+	return nil
+}
+
+// Unregister a CAN bus driver, mainly useful for testing
+func UnregisterDriver(name string) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	delete(drivers, name)
+	delete(AvailableInterfaces, name)
+}
+
+// Names of the registered drivers, sorted
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Create a new CAN bus using a registered driver
+func NewBus(name string, channel string, bitrate int) (canopen.Bus, error) {
+	driversMu.RLock()
+	factory, ok := drivers[name]
+	driversMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w : %v", ErrDriverNotFound, name)
+	}
+	return factory(channel, bitrate)
 }
 
 // Register a new CAN bus interface type
 // This should be called inside an init() function of plugin
+//
+// Deprecated: use [RegisterDriver] instead
 func RegisterInterface(interfaceType string, newInterface NewInterfaceFunc) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
 	AvailableInterfaces[interfaceType] = newInterface
+	drivers[interfaceType] = func(channel string, bitrate int) (canopen.Bus, error) {
+		return newInterface(channel)
+	}
 }
@@ -0,0 +1,45 @@
+package can
+
+import (
+	"errors"
+	"testing"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/stretchr/testify/assert"
+)
+
+type dummyBus struct {
+	channel string
+	bitrate int
+}
+
+func (b *dummyBus) Connect(...any) error                           { return nil }
+func (b *dummyBus) Disconnect() error                              { return nil }
+func (b *dummyBus) Send(frame canopen.Frame) error                 { return nil }
+func (b *dummyBus) Subscribe(callback canopen.FrameListener) error { return nil }
+
+func TestRegisterDriver(t *testing.T) {
+	factory := func(channel string, bitrate int) (canopen.Bus, error) {
+		return &dummyBus{channel: channel, bitrate: bitrate}, nil
+	}
+	defer UnregisterDriver("dummy")
+
+	assert.Equal(t, canopen.ErrIllegalArgument, RegisterDriver("", factory))
+	assert.Equal(t, canopen.ErrIllegalArgument, RegisterDriver("dummy", nil))
+	assert.Nil(t, RegisterDriver("dummy", factory))
+	assert.True(t, errors.Is(RegisterDriver("dummy", factory), ErrDriverExists))
+	assert.Contains(t, Drivers(), "dummy")
+
+	bus, err := NewBus("dummy", "chan0", 500_000)
+	assert.Nil(t, err)
+	assert.Equal(t, &dummyBus{channel: "chan0", bitrate: 500_000}, bus)
+
+	// Still available through the legacy map
+	bus, err = AvailableInterfaces["dummy"]("chan1")
+	assert.Nil(t, err)
+	assert.Equal(t, &dummyBus{channel: "chan1"}, bus)
+
+	UnregisterDriver("dummy")
+	_, err = NewBus("dummy", "chan0", 0)
+	assert.True(t, errors.Is(err, ErrDriverNotFound))
+}
@@ -0,0 +1,196 @@
+// Package replay implements a CAN bus replaying a recorded capture, so that
+// issues recorded in the field can be reproduced offline against local nodes
+// or master applications.
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	can "github.com/samsamfire/gocanopen/pkg/can"
+	"github.com/samsamfire/gocanopen/pkg/trace"
+)
+
+var ErrNotConnected = errors.New("replay bus is not connected")
+
+// The "replay" driver replays the capture file given as channel, with original
+// timing, as soon as the network is connected e.g.
+//
+//	network.Connect("replay", "capture.log", 0)
+func init() {
+	_ = can.RegisterDriver("replay", func(channel string, bitrate int) (canopen.Bus, error) {
+		return NewFileBus(channel, Options{Speed: 1, AutoStart: true})
+	})
+}
+
+// Options of a replay [Bus]
+type Options struct {
+	// Replay speed relative to the original timing, e.g. 10 replays ten times faster.
+	// 0 replays frames without any delay.
+	Speed float64
+	// Replay frames recorded as sent too. By default, only received frames & frames
+	// without direction are replayed, since sent frames are expected to be produced
+	// again by the application under test.
+	IncludeTx bool
+	// Start replaying once subscribed, instead of waiting for [Bus.Start]
+	AutoStart bool
+}
+
+// Bus is a [canopen.Bus] delivering recorded frames to its subscriber.
+// Frames sent by the application are not sent anywhere but are recorded, see [Bus.Sent].
+type Bus struct {
+	mu        sync.Mutex
+	records   []trace.Record
+	opts      Options
+	listener  canopen.FrameListener
+	connected bool
+	started   bool
+	cancel    context.CancelFunc
+	done      chan struct{}
+	replayed  int
+	sent      []canopen.Frame
+}
+
+// Create a replay bus from records, e.g. read with [trace.Read]
+func NewBus(records []trace.Record, opts Options) *Bus {
+	return &Bus{records: records, opts: opts, done: make(chan struct{})}
+}
+
+// Create a replay bus from a capture file, candump log or PCAP / PCAPNG
+func NewFileBus(path string, opts Options) (*Bus, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records, err := trace.Read(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewBus(records, opts), nil
+}
+
+// "Connect" implementation of Bus interface
+func (b *Bus) Connect(...any) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.connected = true
+	return nil
+}
+
+// "Disconnect" implementation of Bus interface, replay is stopped
+func (b *Bus) Disconnect() error {
+	b.mu.Lock()
+	cancel := b.cancel
+	b.connected = false
+	b.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-b.done
+	}
+	return nil
+}
+
+// "Send" implementation of Bus interface, frames are only recorded
+func (b *Bus) Send(frame canopen.Frame) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.connected {
+		return ErrNotConnected
+	}
+	b.sent = append(b.sent, frame)
+	return nil
+}
+
+// "Subscribe" implementation of Bus interface
+func (b *Bus) Subscribe(listener canopen.FrameListener) error {
+	b.mu.Lock()
+	b.listener = listener
+	autoStart := b.opts.AutoStart
+	b.mu.Unlock()
+	if autoStart {
+		return b.Start()
+	}
+	return nil
+}
+
+// Start replaying the capture, this can only be done once.
+// Frames are delivered from a dedicated goroutine.
+func (b *Bus) Start() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.connected {
+		return ErrNotConnected
+	}
+	if b.started {
+		return nil
+	}
+	b.started = true
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	go b.replay(ctx)
+	return nil
+}
+
+// Done is closed once all the frames have been replayed or replay is stopped
+func (b *Bus) Done() <-chan struct{} {
+	return b.done
+}
+
+// Number of frames replayed so far
+func (b *Bus) Replayed() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.replayed
+}
+
+// Frames sent by the application
+func (b *Bus) Sent() []canopen.Frame {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]canopen.Frame{}, b.sent...)
+}
+
+func (b *Bus) replay(ctx context.Context) {
+	defer close(b.done)
+	start := time.Now()
+	var first time.Time
+	for _, record := range b.records {
+		if record.Direction == canopen.TraceTx && !b.opts.IncludeTx {
+			continue
+		}
+		// Respect original timing, relative to the first replayed frame
+		if b.opts.Speed > 0 && !record.Timestamp.IsZero() {
+			if first.IsZero() {
+				first = record.Timestamp
+			}
+			offset := time.Duration(float64(record.Timestamp.Sub(first)) / b.opts.Speed)
+			delay := time.Until(start.Add(offset))
+			if delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		b.mu.Lock()
+		listener := b.listener
+		b.replayed++
+		b.mu.Unlock()
+		if listener != nil {
+			listener.Handle(record.Frame)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package replay_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/can"
+	"github.com/samsamfire/gocanopen/pkg/can/replay"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+// Recorded exchange with a node 0x10, frames sent by node are replayed only with IncludeTx
+const capture = `
+(1700000000.000000) can0 000#0110 R
+(1700000000.050000) can0 610#4018100100000000 R
+(1700000000.050500) can0 590#4318100100000000 T
+(1700000000.100000) can0 610#4000100000000000 R
+`
+
+type frameRecorder struct {
+	frames []canopen.Frame
+}
+
+func (r *frameRecorder) Handle(frame canopen.Frame) {
+	r.frames = append(r.frames, frame)
+}
+
+func readCapture(t *testing.T) []trace.Record {
+	records, err := trace.Read(strings.NewReader(capture))
+	assert.Nil(t, err)
+	return records
+}
+
+func TestReplayLocalNode(t *testing.T) {
+	bus := replay.NewBus(readCapture(t), replay.Options{})
+	net := network.NewNetwork(bus)
+	assert.Nil(t, net.Connect())
+	defer net.Disconnect()
+	local, err := net.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+	assert.Nil(t, bus.Start())
+	select {
+	case <-bus.Done():
+	case <-time.After(time.Second):
+		t.Fatal("replay did not finish")
+	}
+	assert.Equal(t, 3, bus.Replayed())
+	assert.Eventually(t, func() bool { return local.NMT.GetInternalState() == nmt.StateOperational }, time.Second, time.Millisecond)
+	// Node answered both SDO requests
+	assert.Eventually(t, func() bool {
+		responses := 0
+		for _, frame := range bus.Sent() {
+			if frame.ID == 0x590 {
+				responses++
+			}
+		}
+		return responses == 2
+	}, time.Second, time.Millisecond)
+}
+
+func TestReplayTiming(t *testing.T) {
+	t.Run("accelerated", func(t *testing.T) {
+		bus := replay.NewBus(readCapture(t), replay.Options{Speed: 2, IncludeTx: true})
+		assert.Nil(t, bus.Connect())
+		start := time.Now()
+		assert.Nil(t, bus.Start())
+		<-bus.Done()
+		elapsed := time.Since(start)
+		assert.Equal(t, 4, bus.Replayed())
+		assert.GreaterOrEqual(t, elapsed, 50*time.Millisecond)
+		assert.Less(t, elapsed, 95*time.Millisecond)
+	})
+	t.Run("stop", func(t *testing.T) {
+		bus := replay.NewBus(readCapture(t), replay.Options{Speed: 0.01})
+		assert.Nil(t, bus.Connect())
+		assert.Nil(t, bus.Start())
+		time.Sleep(10 * time.Millisecond)
+		assert.Nil(t, bus.Disconnect())
+		assert.Equal(t, 1, bus.Replayed())
+		assert.Equal(t, replay.ErrNotConnected, bus.Send(canopen.NewFrame(0x100, 0, 0)))
+	})
+}
+
+func TestReplayDriver(t *testing.T) {
+	// Captures written by tracers can be replayed
+	path := filepath.Join(t.TempDir(), "capture.pcapng")
+	buffer := &bytes.Buffer{}
+	tracer, err := canopen.NewPcapngTracer(buffer)
+	assert.Nil(t, err)
+	for _, record := range readCapture(t) {
+		assert.Nil(t, tracer.Trace(record.Timestamp, record.Direction, record.Frame))
+	}
+	assert.Nil(t, os.WriteFile(path, buffer.Bytes(), 0644))
+
+	bus, err := can.NewBus("replay", path, 0)
+	assert.Nil(t, err)
+	received := &frameRecorder{}
+	assert.Nil(t, bus.Connect())
+	// Replay starts once subscribed
+	assert.Nil(t, bus.Subscribe(received))
+	<-bus.(*replay.Bus).Done()
+	expected := []canopen.Frame{}
+	for _, record := range readCapture(t) {
+		if record.Direction != canopen.TraceTx {
+			expected = append(expected, record.Frame)
+		}
+	}
+	assert.Equal(t, expected, received.frames)
+}
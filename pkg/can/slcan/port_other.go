@@ -0,0 +1,12 @@
+//go:build !linux
+
+package slcan
+
+import (
+	"errors"
+	"io"
+)
+
+func openPort(device string, baudrate int) (io.ReadWriteCloser, error) {
+	return nil, errors.New("slcan is currently only supported on linux")
+}
@@ -0,0 +1,335 @@
+package slcan
+
+// Serial line CAN (SLCAN / Lawicel) driver, used by many USB serial
+// CAN adapters e.g. CANable, CANUSB.
+// Channel is the serial device, optionally followed by the serial
+// baudrate e.g. "/dev/ttyACM0" or "/dev/ttyUSB0@115200".
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	can "github.com/samsamfire/gocanopen/pkg/can"
+)
+
+const (
+	DefaultBaudrate       = 115200
+	DefaultReconnectDelay = 1 * time.Second
+	canEffFlag            = uint32(0x80000000) // Same as socketcan
+	canEffMask            = uint32(0x1FFFFFFF)
+)
+
+var (
+	ErrBitrate      = errors.New("unsupported bitrate for slcan")
+	ErrNotConnected = errors.New("slcan port is not open")
+)
+
+// SLCAN bitrate commands
+var bitrateCommands = map[int]string{
+	10_000:    "S0",
+	20_000:    "S1",
+	50_000:    "S2",
+	100_000:   "S3",
+	125_000:   "S4",
+	250_000:   "S5",
+	500_000:   "S6",
+	800_000:   "S7",
+	1_000_000: "S8",
+}
+
+func init() {
+	_ = can.RegisterDriver("slcan", NewBus)
+}
+
+// Opens the serial port, can be replaced for testing
+type portOpener func(device string, baudrate int) (io.ReadWriteCloser, error)
+
+type Bus struct {
+	logger         *slog.Logger
+	device         string
+	baudrate       int
+	bitrate        int
+	open           portOpener
+	reconnectDelay time.Duration
+	mu             sync.Mutex // Protects port & writes
+	port           io.ReadWriteCloser
+	listener       canopen.FrameListener
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
+	errMu          sync.Mutex
+	rxErr          error
+}
+
+// Create a new SLCAN bus. bitrate is the CAN bitrate, if 0
+// the adapter's current bitrate is kept.
+func NewBus(channel string, bitrate int) (canopen.Bus, error) {
+	return newBus(channel, bitrate, openPort)
+}
+
+func newBus(channel string, bitrate int, open portOpener) (*Bus, error) {
+	if _, ok := bitrateCommands[bitrate]; !ok && bitrate != 0 {
+		return nil, fmt.Errorf("%w : %v", ErrBitrate, bitrate)
+	}
+	device, baudStr, found := strings.Cut(channel, "@")
+	baudrate := DefaultBaudrate
+	if found {
+		var err error
+		baudrate, err = strconv.Atoi(baudStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid serial baudrate %q : %w", baudStr, err)
+		}
+	}
+	return &Bus{
+		logger:         slog.Default().With("driver", "[SLCAN]", "device", device),
+		device:         device,
+		baudrate:       baudrate,
+		bitrate:        bitrate,
+		open:           open,
+		reconnectDelay: DefaultReconnectDelay,
+	}, nil
+}
+
+// Set the delay between reconnection attempts on serial errors
+func (b *Bus) SetReconnectDelay(delay time.Duration) {
+	b.reconnectDelay = delay
+}
+
+// Open the serial port and the CAN channel
+func (b *Bus) openChannel() error {
+	port, err := b.open(b.device, b.baudrate)
+	if err != nil {
+		return err
+	}
+	// Close channel first, in case it was left open, response is ignored
+	commands := []string{"C"}
+	if b.bitrate != 0 {
+		commands = append(commands, bitrateCommands[b.bitrate])
+	}
+	commands = append(commands, "O")
+	for _, command := range commands {
+		if _, err := port.Write([]byte(command + "\r")); err != nil {
+			port.Close()
+			return err
+		}
+	}
+	b.mu.Lock()
+	b.port = port
+	b.mu.Unlock()
+	return nil
+}
+
+// Close the CAN channel and the serial port
+func (b *Bus) closeChannel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.port == nil {
+		return
+	}
+	_, _ = b.port.Write([]byte("C\r"))
+	_ = b.port.Close()
+	b.port = nil
+}
+
+// "Connect" implementation of Bus interface
+func (b *Bus) Connect(...any) error {
+	if err := b.openChannel(); err != nil {
+		return err
+	}
+	b.setErr(nil)
+	var ctx context.Context
+	ctx, b.cancel = context.WithCancel(context.Background())
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.processIncoming(ctx)
+	}()
+	return nil
+}
+
+// "Disconnect" implementation of Bus interface
+func (b *Bus) Disconnect() error {
+	if b.cancel == nil {
+		return nil
+	}
+	b.cancel()
+	// Closing the port unblocks reception
+	b.closeChannel()
+	b.wg.Wait()
+	b.cancel = nil
+	return nil
+}
+
+// "Send" implementation of Bus interface
+func (b *Bus) Send(frame canopen.Frame) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.port == nil {
+		return ErrNotConnected
+	}
+	_, err := b.port.Write(encodeFrame(frame))
+	return err
+}
+
+// "Subscribe" implementation of Bus interface
+func (b *Bus) Subscribe(listener canopen.FrameListener) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listener = listener
+	return nil
+}
+
+// Implements [canopen.BusErrorReporter], returns an error
+// while the serial port is lost and being reopened
+func (b *Bus) Err() error {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	return b.rxErr
+}
+
+func (b *Bus) setErr(err error) {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	b.rxErr = err
+}
+
+// Receive frames until ctx is cancelled, the serial port is reopened on errors
+func (b *Bus) processIncoming(ctx context.Context) {
+	for {
+		b.mu.Lock()
+		port := b.port
+		b.mu.Unlock()
+		if port != nil {
+			err := b.receive(port)
+			if ctx.Err() != nil {
+				return
+			}
+			b.logger.Warn("serial error, reconnecting", "error", err)
+			b.setErr(err)
+			b.closeChannel()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.reconnectDelay):
+		}
+		err := b.openChannel()
+		if err != nil {
+			b.logger.Debug("failed to reopen serial port", "error", err)
+			continue
+		}
+		b.logger.Info("serial port reopened")
+		b.setErr(nil)
+	}
+}
+
+// Read & dispatch frames until a read error occurs
+func (b *Bus) receive(port io.Reader) error {
+	reader := bufio.NewReader(port)
+	for {
+		line, err := reader.ReadString('\r')
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		// Acknowledgements ("\r", "z\r", "Z\r") & errors ("\a") are ignored
+		line = strings.TrimLeft(line, "\a")
+		frame, err := decodeFrame(strings.TrimSuffix(line, "\r"))
+		if err != nil {
+			continue
+		}
+		b.mu.Lock()
+		listener := b.listener
+		b.mu.Unlock()
+		if listener != nil {
+			listener.Handle(frame)
+		}
+	}
+}
+
+// Encode a frame in SLCAN format e.g. "t1232AABB\r"
+func encodeFrame(frame canopen.Frame) []byte {
+	var sb strings.Builder
+	rtr := frame.ID&canopen.CanRtrFlag != 0
+	dlc := min(frame.DLC, 8)
+	if frame.ID&canEffFlag != 0 {
+		cmd := "T"
+		if rtr {
+			cmd = "R"
+		}
+		fmt.Fprintf(&sb, "%s%08X%d", cmd, frame.ID&canEffMask, dlc)
+	} else {
+		cmd := "t"
+		if rtr {
+			cmd = "r"
+		}
+		fmt.Fprintf(&sb, "%s%03X%d", cmd, frame.ID&canopen.CanSffMask, dlc)
+	}
+	if !rtr {
+		for _, b := range frame.Data[:dlc] {
+			fmt.Fprintf(&sb, "%02X", b)
+		}
+	}
+	sb.WriteByte('\r')
+	return []byte(sb.String())
+}
+
+// Decode a frame in SLCAN format without trailing '\r'
+func decodeFrame(line string) (canopen.Frame, error) {
+	frame := canopen.Frame{}
+	if len(line) == 0 {
+		return frame, errors.New("empty line")
+	}
+	idLength := 3
+	switch line[0] {
+	case 't':
+	case 'r':
+		frame.ID |= canopen.CanRtrFlag
+	case 'T':
+		idLength = 8
+		frame.ID |= canEffFlag
+	case 'R':
+		idLength = 8
+		frame.ID |= canEffFlag | canopen.CanRtrFlag
+	default:
+		return frame, fmt.Errorf("not a frame : %q", line)
+	}
+	if len(line) < 1+idLength+1 {
+		return frame, fmt.Errorf("frame too short : %q", line)
+	}
+	id, err := strconv.ParseUint(line[1:1+idLength], 16, 32)
+	if err != nil {
+		return frame, err
+	}
+	frame.ID |= uint32(id)
+	dlc, err := strconv.ParseUint(line[1+idLength:2+idLength], 10, 8)
+	if err != nil || dlc > 8 {
+		return frame, fmt.Errorf("invalid dlc : %q", line)
+	}
+	frame.DLC = uint8(dlc)
+	if frame.ID&canopen.CanRtrFlag != 0 {
+		return frame, nil
+	}
+	data := line[2+idLength:]
+	if len(data) < 2*int(dlc) {
+		return frame, fmt.Errorf("missing data : %q", line)
+	}
+	for i := range int(dlc) {
+		value, err := strconv.ParseUint(data[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return frame, err
+		}
+		frame.Data[i] = uint8(value)
+	}
+	return frame, nil
+}
@@ -0,0 +1,116 @@
+package slcan
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/stretchr/testify/assert"
+)
+
+// Fake serial port, adapter side writes to rx
+type fakePort struct {
+	mu      sync.Mutex
+	written bytes.Buffer
+	rx      *io.PipeReader
+	adapter *io.PipeWriter
+}
+
+func newFakePort() *fakePort {
+	r, w := io.Pipe()
+	return &fakePort{rx: r, adapter: w}
+}
+
+func (p *fakePort) Read(b []byte) (int, error) { return p.rx.Read(b) }
+func (p *fakePort) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.written.Write(b)
+}
+func (p *fakePort) Close() error { return p.rx.Close() }
+func (p *fakePort) Written() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.written.String()
+}
+
+type frameRecorder struct {
+	frames chan canopen.Frame
+}
+
+func (r *frameRecorder) Handle(frame canopen.Frame) { r.frames <- frame }
+
+func TestEncodeDecode(t *testing.T) {
+	frames := map[string]canopen.Frame{
+		"t1232AABB":        {ID: 0x123, DLC: 2, Data: [8]byte{0xAA, 0xBB}},
+		"t0000":            {ID: 0, DLC: 0},
+		"r7FF1":            {ID: 0x7FF | canopen.CanRtrFlag, DLC: 1},
+		"T123456783112233": {ID: 0x12345678 | canEffFlag, DLC: 3, Data: [8]byte{0x11, 0x22, 0x33}},
+		"R123456780":       {ID: 0x12345678 | canEffFlag | canopen.CanRtrFlag},
+	}
+	for encoded, frame := range frames {
+		assert.Equal(t, encoded+"\r", string(encodeFrame(frame)))
+		decoded, err := decodeFrame(encoded)
+		assert.Nil(t, err)
+		assert.Equal(t, frame, decoded)
+	}
+	_, err := decodeFrame("T123456783112")
+	assert.NotNil(t, err)
+	for _, invalid := range []string{"", "z", "t12", "t1239", "t12321", "tXYZ0"} {
+		_, err := decodeFrame(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestBus(t *testing.T) {
+	_, err := newBus("/dev/ttyACM0", 42, nil)
+	assert.ErrorIs(t, err, ErrBitrate)
+
+	ports := make(chan *fakePort, 10)
+	opened := 0
+	open := func(device string, baudrate int) (io.ReadWriteCloser, error) {
+		assert.Equal(t, "/dev/ttyACM0", device)
+		assert.Equal(t, 230400, baudrate)
+		opened++
+		if opened == 2 {
+			return nil, errors.New("device not found")
+		}
+		port := newFakePort()
+		ports <- port
+		return port, nil
+	}
+	bus, err := newBus("/dev/ttyACM0@230400", 500_000, open)
+	assert.Nil(t, err)
+	bus.SetReconnectDelay(10 * time.Millisecond)
+	recorder := &frameRecorder{frames: make(chan canopen.Frame, 10)}
+	assert.Nil(t, bus.Subscribe(recorder))
+	assert.Nil(t, bus.Connect())
+	port := <-ports
+	assert.Equal(t, "C\rS6\rO\r", port.Written())
+
+	t.Run("send & receive", func(t *testing.T) {
+		assert.Nil(t, bus.Send(canopen.Frame{ID: 0x601, DLC: 1, Data: [8]byte{0x40}}))
+		assert.Equal(t, "C\rS6\rO\rt601140\r", port.Written())
+		_, err := port.adapter.Write([]byte("z\r\at5811AB\r"))
+		assert.Nil(t, err)
+		frame := <-recorder.frames
+		assert.Equal(t, canopen.Frame{ID: 0x581, DLC: 1, Data: [8]byte{0xAB}}, frame)
+	})
+	t.Run("reconnect on serial error", func(t *testing.T) {
+		port.adapter.CloseWithError(errors.New("device unplugged"))
+		assert.Eventually(t, func() bool { return bus.Err() != nil }, time.Second, time.Millisecond)
+		port = <-ports
+		assert.Eventually(t, func() bool { return bus.Err() == nil }, time.Second, time.Millisecond)
+		assert.Equal(t, 3, opened)
+		_, err := port.adapter.Write([]byte("t0000\r"))
+		assert.Nil(t, err)
+		frame := <-recorder.frames
+		assert.EqualValues(t, 0, frame.ID)
+	})
+	assert.Nil(t, bus.Disconnect())
+	assert.Equal(t, ErrNotConnected, bus.Send(canopen.Frame{}))
+}
@@ -0,0 +1,78 @@
+package socketcanv2
+
+import (
+	"encoding/binary"
+	"errors"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Restart a CAN interface in bus-off, this is the netlink request sent by
+// "ip link set <channel> type can restart". It requires CAP_NET_ADMIN.
+func restartLink(ifIndex int) error {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+	kernel := &unix.SockaddrNetlink{Family: unix.AF_NETLINK}
+	err = unix.Sendto(fd, restartMessage(ifIndex), 0, kernel)
+	if err != nil {
+		return err
+	}
+	buffer := make([]byte, unix.Getpagesize())
+	n, _, err := unix.Recvfrom(fd, buffer, 0)
+	if err != nil {
+		return err
+	}
+	messages, err := syscall.ParseNetlinkMessage(buffer[:n])
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		if message.Header.Type != unix.NLMSG_ERROR || len(message.Data) < 4 {
+			continue
+		}
+		// Acknowledged if error is 0, otherwise negative errno
+		errno := int32(binary.NativeEndian.Uint32(message.Data))
+		if errno != 0 {
+			return syscall.Errno(-errno)
+		}
+		return nil
+	}
+	return errors.New("no acknowledgement from netlink")
+}
+
+// RTM_NEWLINK request with IFLA_LINKINFO { IFLA_INFO_KIND "can", IFLA_INFO_DATA { IFLA_CAN_RESTART 1 } }
+func restartMessage(ifIndex int) []byte {
+	restart := make([]byte, 4)
+	binary.NativeEndian.PutUint32(restart, 1)
+	linkInfo := append(
+		routeAttribute(unix.IFLA_INFO_KIND, []byte("can\x00")),
+		routeAttribute(unix.IFLA_INFO_DATA, routeAttribute(unix.IFLA_CAN_RESTART, restart))...,
+	)
+	info := unix.IfInfomsg{Family: unix.AF_UNSPEC, Index: int32(ifIndex)}
+	payload := append(
+		(*[unix.SizeofIfInfomsg]byte)(unsafe.Pointer(&info))[:],
+		routeAttribute(unix.IFLA_LINKINFO, linkInfo)...,
+	)
+	header := unix.NlMsghdr{
+		Len:   uint32(unix.SizeofNlMsghdr + len(payload)),
+		Type:  unix.RTM_NEWLINK,
+		Flags: unix.NLM_F_REQUEST | unix.NLM_F_ACK,
+		Seq:   1,
+	}
+	return append((*[unix.SizeofNlMsghdr]byte)(unsafe.Pointer(&header))[:], payload...)
+}
+
+// Encode a netlink route attribute, padded to 4 bytes
+func routeAttribute(attributeType uint16, data []byte) []byte {
+	length := unix.SizeofRtAttr + len(data)
+	attribute := make([]byte, (length+unix.RTA_ALIGNTO-1)&^(unix.RTA_ALIGNTO-1))
+	binary.NativeEndian.PutUint16(attribute[0:], uint16(length))
+	binary.NativeEndian.PutUint16(attribute[2:], attributeType)
+	copy(attribute[unix.SizeofRtAttr:], data)
+	return attribute
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"unsafe"
 
@@ -41,6 +42,10 @@ type Bus struct {
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
 	logger     *slog.Logger
+	errMu      sync.Mutex
+	rxErr      error
+	channel    string
+	errStatus  atomic.Uint32 // CanErrorXXX flags, from error frames
 }
 
 // Create a new SocketCAN bus. This expects the CAN channel to be up.
@@ -52,22 +57,36 @@ func NewBus(channel string) (canopen.Bus, error) {
 	}
 
 	fd, err := unix.Socket(unix.AF_CAN, unix.SOCK_RAW, unix.CAN_RAW)
-	//fd, err := syscall.Socket(syscall.AF_CAN, syscall.SOCK_RAW, unix.CAN_RAW)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create CAN socket : %v", err)
+		return nil, fmt.Errorf("failed to create CAN socket : %w", err)
 	}
-	err = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &DefaultTimeVal)
+	err = configureSocket(fd, iface.Index)
 	if err != nil {
-		return nil, fmt.Errorf("failed to set read timeout %v", err)
-	}
-	addr := &unix.SockaddrCAN{Ifindex: iface.Index}
-	if err := unix.Bind(fd, addr); err != nil {
+		unix.Close(fd)
 		return nil, err
 	}
-	socketcan := &Bus{fd: fd, logger: slog.Default()}
+	socketcan := &Bus{fd: fd, logger: slog.Default(), channel: channel}
 	return socketcan, nil
 }
 
+func configureSocket(fd int, ifIndex int) error {
+	err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &DefaultTimeVal)
+	if err != nil {
+		return fmt.Errorf("failed to set read timeout : %w", err)
+	}
+	err = unix.Bind(fd, &unix.SockaddrCAN{Ifindex: ifIndex})
+	if err != nil {
+		return fmt.Errorf("failed to bind CAN socket : %w", err)
+	}
+	// Receive controller errors, bus-off & restarts as error frames
+	errMask := unix.CAN_ERR_CRTL | unix.CAN_ERR_BUSOFF | unix.CAN_ERR_RESTARTED
+	err = unix.SetsockoptInt(fd, unix.SOL_CAN_RAW, unix.CAN_RAW_ERR_FILTER, errMask)
+	if err != nil {
+		return fmt.Errorf("failed to set error filter : %w", err)
+	}
+	return nil
+}
+
 // "Connect" implementation of Bus interface
 func (b *Bus) Connect(...any) error {
 	var ctx context.Context
@@ -125,6 +144,12 @@ func (b *Bus) processIncoming(ctx context.Context) {
 			}
 			if n != 16 || err != nil {
 				b.logger.Info("exiting CAN bus reception", "error", err)
+				if err == nil {
+					err = fmt.Errorf("unexpected frame size %v", n)
+				}
+				b.errMu.Lock()
+				b.rxErr = err
+				b.errMu.Unlock()
 				return
 			}
 			// Direct translation in CANFrame
@@ -134,6 +159,10 @@ func (b *Bus) processIncoming(ctx context.Context) {
 			canopenFrame.DLC = frame.dlc
 			canopenFrame.Flags = frame.pad
 			canopenFrame.Data = frame.data
+			if frame.id&unix.CAN_ERR_FLAG != 0 {
+				b.handleErrorFrame(canopenFrame)
+				continue
+			}
 			if b.rxCallback != nil {
 				b.rxCallback.Handle(canopenFrame)
 			}
@@ -147,6 +176,73 @@ func (b *Bus) Subscribe(rxCallback canopen.FrameListener) error {
 	return nil
 }
 
+// Implements [canopen.BusErrorReporter], returns an error
+// if reception has stopped e.g. because interface went down
+func (b *Bus) Err() error {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	return b.rxErr
+}
+
+// Implements [canopen.BusErrorStatusReporter], controller errors are
+// updated from the error frames received
+func (b *Bus) ErrorStatus() uint16 {
+	return uint16(b.errStatus.Load())
+}
+
+// Implements [canopen.BusRestarter], this sends the same netlink request as
+// "ip link set <channel> type can restart" which requires CAP_NET_ADMIN.
+// Alternatively, automatic restart can be configured with "restart-ms"
+// when setting up the interface.
+func (b *Bus) Restart() error {
+	iface, err := net.InterfaceByName(b.channel)
+	if err != nil {
+		return fmt.Errorf("failed to restart %v : %w", b.channel, err)
+	}
+	err = restartLink(iface.Index)
+	if err != nil {
+		return fmt.Errorf("failed to restart %v : %w", b.channel, err)
+	}
+	return nil
+}
+
+// Controller error flags of error frames, as CanErrorXXX flags
+var crtlErrorFlags = map[uint8]uint16{
+	unix.CAN_ERR_CRTL_TX_WARNING:  canopen.CanErrorTxWarning,
+	unix.CAN_ERR_CRTL_RX_WARNING:  canopen.CanErrorRxWarning,
+	unix.CAN_ERR_CRTL_TX_PASSIVE:  canopen.CanErrorTxPassive,
+	unix.CAN_ERR_CRTL_RX_PASSIVE:  canopen.CanErrorRxPassive,
+	unix.CAN_ERR_CRTL_TX_OVERFLOW: canopen.CanErrorTxOverflow,
+	unix.CAN_ERR_CRTL_RX_OVERFLOW: canopen.CanErrorRxOverflow,
+}
+
+// Update error status from an error frame
+func (b *Bus) handleErrorFrame(frame canopen.Frame) {
+	status := uint16(b.errStatus.Load())
+	if frame.ID&unix.CAN_ERR_RESTARTED != 0 {
+		status = 0
+	}
+	if frame.ID&unix.CAN_ERR_BUSOFF != 0 {
+		status |= canopen.CanErrorTxBusOff
+	}
+	if frame.ID&unix.CAN_ERR_CRTL != 0 {
+		flags := frame.Data[1]
+		if flags&unix.CAN_ERR_CRTL_ACTIVE != 0 {
+			status &^= canopen.CanErrorWarnPassive | canopen.CanErrorTxOverflow | canopen.CanErrorRxOverflow
+		}
+		if flags&(unix.CAN_ERR_CRTL_TX_WARNING|unix.CAN_ERR_CRTL_RX_WARNING|unix.CAN_ERR_CRTL_TX_PASSIVE|unix.CAN_ERR_CRTL_RX_PASSIVE) != 0 {
+			status &^= canopen.CanErrorWarnPassive
+		}
+		for flag, canError := range crtlErrorFlags {
+			if flags&flag != 0 {
+				status |= canError
+			}
+		}
+	}
+	b.logger.Info("CAN controller error status", "status", status)
+	b.errStatus.Store(uint32(status))
+}
+
 // Enable own reception on the bus. CAN be useful when testing for example
 func (b *Bus) SetReceiveOwn(enabled bool) error {
 	enabledInt := 0
@@ -1,9 +1,11 @@
 package socketcanv2
 
 import (
+	"log/slog"
 	"os"
 	"testing"
 	"time"
+	"unsafe"
 
 	canopen "github.com/samsamfire/gocanopen"
 	"github.com/stretchr/testify/assert"
@@ -130,3 +132,45 @@ func TestFilterNoReception(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 	assert.Len(t, listener.frames, 0)
 }
+
+func TestErrorFrames(t *testing.T) {
+	bus := &Bus{logger: slog.Default()}
+	errFrame := func(id uint32, crtl uint8) canopen.Frame {
+		frame := canopen.NewFrame(unix.CAN_ERR_FLAG|id, 0, 8)
+		frame.Data[1] = crtl
+		return frame
+	}
+	bus.handleErrorFrame(errFrame(unix.CAN_ERR_CRTL, unix.CAN_ERR_CRTL_TX_WARNING))
+	assert.EqualValues(t, canopen.CanErrorTxWarning, bus.ErrorStatus())
+	bus.handleErrorFrame(errFrame(unix.CAN_ERR_CRTL, unix.CAN_ERR_CRTL_TX_PASSIVE))
+	assert.EqualValues(t, canopen.CanErrorTxPassive, bus.ErrorStatus())
+	bus.handleErrorFrame(errFrame(unix.CAN_ERR_BUSOFF, 0))
+	assert.EqualValues(t, canopen.CanErrorTxPassive|canopen.CanErrorTxBusOff, bus.ErrorStatus())
+	bus.handleErrorFrame(errFrame(unix.CAN_ERR_RESTARTED, 0))
+	assert.EqualValues(t, 0, bus.ErrorStatus())
+	bus.handleErrorFrame(errFrame(unix.CAN_ERR_CRTL, unix.CAN_ERR_CRTL_RX_OVERFLOW))
+	bus.handleErrorFrame(errFrame(unix.CAN_ERR_CRTL, unix.CAN_ERR_CRTL_ACTIVE))
+	assert.EqualValues(t, 0, bus.ErrorStatus())
+}
+
+func TestRestartMessage(t *testing.T) {
+	message := restartMessage(3)
+	header := (*unix.NlMsghdr)(unsafe.Pointer(&message[0]))
+	assert.EqualValues(t, len(message), header.Len)
+	assert.EqualValues(t, unix.RTM_NEWLINK, header.Type)
+	assert.EqualValues(t, unix.NLM_F_REQUEST|unix.NLM_F_ACK, header.Flags)
+	info := (*unix.IfInfomsg)(unsafe.Pointer(&message[unix.SizeofNlMsghdr]))
+	assert.EqualValues(t, 3, info.Index)
+	// Linkinfo attribute holding kind & restart data
+	attributes := message[unix.SizeofNlMsghdr+unix.SizeofIfInfomsg:]
+	linkInfo := (*unix.RtAttr)(unsafe.Pointer(&attributes[0]))
+	assert.EqualValues(t, unix.IFLA_LINKINFO, linkInfo.Type)
+	assert.EqualValues(t, len(attributes), linkInfo.Len)
+	assert.Equal(t, "can\x00", string(attributes[2*unix.SizeofRtAttr:2*unix.SizeofRtAttr+4]))
+}
+
+func TestRestartNotCAN(t *testing.T) {
+	// Errors are returned, e.g. interface is not CAN or missing permissions
+	assert.NotNil(t, (&Bus{channel: "lo"}).Restart())
+	assert.NotNil(t, (&Bus{channel: "doesnotexist"}).Restart())
+}
@@ -34,6 +34,8 @@ type Bus struct {
 	wg            sync.WaitGroup
 	isRunning     bool
 	errSubscriber bool
+	errMu         sync.Mutex
+	rxErr         error
 }
 
 func NewVirtualCanBus(channel string) (canopen.Bus, error) {
@@ -76,6 +78,7 @@ func (b *Bus) Connect(...any) error {
 		return err
 	}
 	b.conn = conn
+	b.setErr(nil)
 	if tcpConn, ok := conn.(*net.TCPConn); ok {
 		err := tcpConn.SetNoDelay(true)
 		if err != nil {
@@ -188,6 +191,7 @@ func (client *Bus) handleReception() {
 			} else if err != nil {
 				client.logger.Error("listening routine has closed because", "err", err)
 				client.errSubscriber = true
+				client.setErr(err)
 				client.mu.Unlock()
 				return
 			} else if client.framehandler != nil {
@@ -198,6 +202,20 @@ func (client *Bus) handleReception() {
 	}
 }
 
+func (b *Bus) setErr(err error) {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	b.rxErr = err
+}
+
+// Implements [canopen.BusErrorReporter], returns an error
+// if connection to the broker has been lost
+func (b *Bus) Err() error {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	return b.rxErr
+}
+
 func (b *Bus) SetReceiveOwn(receiveOwn bool) {
 	b.receiveOwn = receiveOwn
 }
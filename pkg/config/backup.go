@@ -0,0 +1,271 @@
+package config
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+var ErrBackupMismatch = errors.New("backup was made on another device type")
+
+// Entries that are commands or diagnostics rather than parameters, they are never backed up
+var backupExcluded = []uint16{
+	od.EntryPreDefinedErrorField,
+	od.EntryStoreParameters,
+	od.EntryRestoreDefaultParameters,
+	od.EntryProgramControl,
+}
+
+// Parameter values of a node, see [NodeConfigurator.Backup]
+type Backup struct {
+	NodeId   uint8         `json:"nodeId"`
+	Identity *Identity     `json:"identity,omitempty"`
+	Entries  []BackupEntry `json:"entries"`
+}
+
+// Raw value of a sub-entry
+type BackupEntry struct {
+	Index    uint16 `json:"index"`
+	Subindex uint8  `json:"subindex"`
+	Name     string `json:"name,omitempty"`
+	Data     []byte `json:"data"`
+}
+
+// A value written by [NodeConfigurator.Restore] because it differed from the backup
+type ValueChange struct {
+	Index    uint16
+	Subindex uint8
+	Previous []byte // Value before restoring, nil if it could not be read
+	Restored []byte
+	Err      error // Error if value could not be written
+}
+
+// Result of [NodeConfigurator.Restore]
+type RestoreReport struct {
+	Changes []ValueChange
+	Saved   bool // Parameters were stored with 0x1010
+}
+
+// Backup reads all the parameters of the node, i.e. the readable & writable entries
+// of its OD that are not domains. The OD should be set with [NodeConfigurator.SetOD].
+// Entries that cannot be read are skipped.
+func (config *NodeConfigurator) Backup() (*Backup, error) {
+	if config.od == nil {
+		return nil, od.ErrOdMissing
+	}
+	backup := &Backup{NodeId: config.nodeId, Entries: []BackupEntry{}}
+	identity, err := config.ReadIdentity()
+	if err == nil {
+		backup.Identity = identity
+	}
+	indexes := []uint16{}
+	for index, entry := range config.od.Entries() {
+		if entry.ObjectType != od.ObjectTypeDOMAIN && !slices.Contains(backupExcluded, index) {
+			indexes = append(indexes, index)
+		}
+	}
+	slices.Sort(indexes)
+	for _, index := range indexes {
+		entry := config.od.Index(index)
+		// Sub-indexes are not necessarily contiguous
+		found := 0
+		for subindex := 0; subindex <= 0xFF && found < entry.SubCount(); subindex++ {
+			variable, err := entry.SubIndex(uint8(subindex))
+			if err != nil {
+				continue
+			}
+			found++
+			if variable.Attribute&od.AttributeSdoRw != od.AttributeSdoRw || variable.DataType == od.DOMAIN {
+				continue
+			}
+			data, err := config.client.ReadAll(config.nodeId, index, uint8(subindex))
+			if err != nil {
+				config.logger.Warn("skipping parameter",
+					"index", fmt.Sprintf("x%x", index),
+					"subindex", fmt.Sprintf("x%x", subindex),
+					"error", err,
+				)
+				continue
+			}
+			backup.Entries = append(backup.Entries, BackupEntry{Index: index, Subindex: uint8(subindex), Name: variable.Name, Data: data})
+		}
+	}
+	config.logger.Info("parameters backed up", "entries", len(backup.Entries))
+	return backup, nil
+}
+
+// BackupToFile makes a [NodeConfigurator.Backup] and saves it as JSON to path
+func (config *NodeConfigurator) BackupToFile(path string) error {
+	backup, err := config.Backup()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Restore writes the values of the backup that differ from the current values of the node,
+// e.g. on a replacement device. The node should have the same vendor id & product code as
+// the backed up node, otherwise [ErrBackupMismatch] is returned.
+// PDOs are disabled while their parameters are changed and mappings are cleared before
+// being written. If the node saves parameters on command (0x1010 sub 1), they are then stored.
+// All the values are attempted, the report contains the values that changed and
+// write errors are also returned joined.
+func (config *NodeConfigurator) Restore(backup *Backup) (*RestoreReport, error) {
+	if backup.Identity != nil {
+		identity, err := config.ReadIdentity()
+		if err != nil {
+			return nil, err
+		}
+		if identity.VendorId != backup.Identity.VendorId || identity.ProductCode != backup.Identity.ProductCode {
+			return nil, ErrBackupMismatch
+		}
+	}
+	report := &RestoreReport{Changes: []ValueChange{}}
+	for _, entry := range backup.Entries {
+		current, err := config.client.ReadAll(config.nodeId, entry.Index, entry.Subindex)
+		if err == nil && bytes.Equal(current, entry.Data) {
+			continue
+		}
+		report.Changes = append(report.Changes, ValueChange{
+			Index: entry.Index, Subindex: entry.Subindex, Previous: current, Restored: entry.Data,
+		})
+	}
+	// Final COB-ID of every PDO disabled during restore, by communication index
+	cobIds := map[uint16]uint32{}
+	disabled := []uint16{}
+	for _, change := range report.Changes {
+		commIndex, ok := pdoCommunicationIndex(change.Index)
+		if !ok {
+			continue
+		}
+		if slices.Contains(disabled, commIndex) {
+			continue
+		}
+		cobId, err := config.client.ReadUint32(config.nodeId, commIndex, 1)
+		if err != nil {
+			continue
+		}
+		cobIds[commIndex] = cobId
+		disabled = append(disabled, commIndex)
+		if cobId&0x80000000 == 0 {
+			_ = config.client.WriteRaw(config.nodeId, commIndex, 1, cobId|0x80000000, false)
+		}
+	}
+	for _, entry := range backup.Entries {
+		if slices.Contains(disabled, entry.Index) && entry.Subindex == 1 && len(entry.Data) == 4 {
+			cobIds[entry.Index] = binary.LittleEndian.Uint32(entry.Data)
+		}
+	}
+	// Number of mapped objects is written last
+	mappings := []uint16{}
+	for _, change := range report.Changes {
+		if od.IsPDOMappingParam(change.Index) && !slices.Contains(mappings, change.Index) {
+			mappings = append(mappings, change.Index)
+			_ = config.client.WriteRaw(config.nodeId, change.Index, 0, uint8(0), false)
+		}
+	}
+	errs := []error{}
+	write := func(i int) {
+		change := &report.Changes[i]
+		change.Err = config.client.WriteRaw(config.nodeId, change.Index, change.Subindex, change.Restored, false)
+		if change.Err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore x%x|x%x : %w", change.Index, change.Subindex, change.Err))
+		}
+	}
+	for i, change := range report.Changes {
+		if (slices.Contains(disabled, change.Index) && change.Subindex == 1) ||
+			(slices.Contains(mappings, change.Index) && change.Subindex == 0) {
+			continue
+		}
+		write(i)
+	}
+	// Number of mapped objects, even if it did not change as mapping was cleared
+	for _, index := range mappings {
+		if i := report.find(index, 0); i >= 0 {
+			write(i)
+		} else if entry := backup.find(index, 0); entry != nil {
+			_ = config.client.WriteRaw(config.nodeId, index, 0, entry.Data, false)
+		}
+	}
+	// COB-IDs, this enables the PDOs again
+	for _, commIndex := range disabled {
+		if i := report.find(commIndex, 1); i >= 0 {
+			write(i)
+		} else {
+			_ = config.client.WriteRaw(config.nodeId, commIndex, 1, cobIds[commIndex], false)
+		}
+	}
+	report.Saved = config.saveParameters()
+	config.logger.Info("parameters restored", "changes", len(report.Changes), "saved", report.Saved)
+	return report, errors.Join(errs...)
+}
+
+// RestoreFromFile loads a backup saved with [NodeConfigurator.BackupToFile] and restores it,
+// see [NodeConfigurator.Restore]
+func (config *NodeConfigurator) RestoreFromFile(path string) (*RestoreReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	backup := &Backup{}
+	err = json.Unmarshal(data, backup)
+	if err != nil {
+		return nil, err
+	}
+	return config.Restore(backup)
+}
+
+// Store all parameters if the node supports saving on command
+func (config *NodeConfigurator) saveParameters() bool {
+	if config.od != nil && config.od.Index(od.EntryStoreParameters) == nil {
+		return false
+	}
+	features, err := config.client.ReadUint32(config.nodeId, od.EntryStoreParameters, 1)
+	if err != nil || features&0x1 == 0 {
+		return false
+	}
+	err = config.client.WriteRaw(config.nodeId, od.EntryStoreParameters, 1, od.SignatureSave, false)
+	if err != nil {
+		config.logger.Warn("failed to store parameters", "error", err)
+		return false
+	}
+	return true
+}
+
+// Position of the change of a sub-entry, -1 if it did not change
+func (report *RestoreReport) find(index uint16, subindex uint8) int {
+	return slices.IndexFunc(report.Changes, func(change ValueChange) bool {
+		return change.Index == index && change.Subindex == subindex
+	})
+}
+
+func (backup *Backup) find(index uint16, subindex uint8) *BackupEntry {
+	for i := range backup.Entries {
+		if backup.Entries[i].Index == index && backup.Entries[i].Subindex == subindex {
+			return &backup.Entries[i]
+		}
+	}
+	return nil
+}
+
+// Communication parameter index of the PDO using index, if index is a PDO parameter
+func pdoCommunicationIndex(index uint16) (uint16, bool) {
+	switch {
+	case od.IsPDOCommParam(index):
+		return index, true
+	case od.IsPDOMappingParam(index):
+		return index - (od.EntryRPDOMappingStart - od.EntryRPDOCommunicationStart), true
+	default:
+		return 0, false
+	}
+}
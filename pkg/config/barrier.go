@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+const (
+	DefaultBarrierTimeout = 1 * time.Second
+	DefaultBarrierPeriod  = 10 * time.Millisecond
+)
+
+var ErrBarrierTimeout = errors.New("configuration was not confirmed applied before timeout")
+
+// A ConfirmFunc returns true when a configuration change has been applied by the node
+type ConfirmFunc func() (bool, error)
+
+// Options used when waiting for a configuration to be applied
+type BarrierOptions struct {
+	Timeout time.Duration // Maximum time to wait for confirmation
+	Period  time.Duration // Time between two confirmation attempts
+}
+
+// ReadBack returns a [ConfirmFunc] that reads back index/subindex
+// and compares it with the expected value.
+func (config *NodeConfigurator) ReadBack(index uint16, subindex uint8, value any) ConfirmFunc {
+	return config.Handshake(index, subindex, value)
+}
+
+// Handshake returns a [ConfirmFunc] that waits for a device specific object
+// (e.g. a status or acknowledge object) to be equal to the expected value.
+func (config *NodeConfigurator) Handshake(index uint16, subindex uint8, expected any) ConfirmFunc {
+	return func() (bool, error) {
+		encoded, err := od.EncodeFromGeneric(expected)
+		if err != nil {
+			return false, err
+		}
+		raw := make([]byte, len(encoded)+1)
+		n, err := config.client.ReadRaw(config.nodeId, index, subindex, raw)
+		if err != nil {
+			return false, err
+		}
+		return bytes.Equal(raw[:n], encoded), nil
+	}
+}
+
+// WaitApplied blocks until confirm returns true or until timeout.
+// This can be used as a barrier before resuming logic that depends on the new
+// configuration, e.g. processing PDOs after a change of scaling factors.
+// SDO errors during confirmation are ignored until timeout, as some devices
+// may not respond while applying a configuration.
+func (config *NodeConfigurator) WaitApplied(confirm ConfirmFunc, opts *BarrierOptions) error {
+	if opts == nil {
+		opts = &BarrierOptions{}
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultBarrierTimeout
+	}
+	period := opts.Period
+	if period == 0 {
+		period = DefaultBarrierPeriod
+	}
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for {
+		ok, err := confirm()
+		if err == nil && ok {
+			return nil
+		}
+		lastErr = err
+		if time.Now().After(deadline) {
+			config.logger.Warn("configuration not confirmed", "timeout", timeout, "error", lastErr)
+			if lastErr != nil {
+				return errors.Join(ErrBarrierTimeout, lastErr)
+			}
+			return ErrBarrierTimeout
+		}
+		time.Sleep(period)
+	}
+}
+
+// WriteBarrier writes value to index/subindex and waits until the
+// change is confirmed as applied. If confirm is nil, the value is read back.
+// When this returns without error, any data received afterwards
+// (e.g. PDOs) reflects the new configuration.
+func (config *NodeConfigurator) WriteBarrier(
+	index uint16,
+	subindex uint8,
+	value any,
+	confirm ConfirmFunc,
+	opts *BarrierOptions,
+) error {
+	err := config.client.WriteRaw(config.nodeId, index, subindex, value, false)
+	if err != nil {
+		return err
+	}
+	if confirm == nil {
+		confirm = config.ReadBack(index, subindex, value)
+	}
+	return config.WaitApplied(confirm, opts)
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Download a concise DCF (CiA 302) to the node, e.g. generated with
+// [od.ObjectDictionary.ConciseDCF] or read from a configuration manager (0x1F22).
+// Entries are written in order, the first failing entry stops the download.
+func (config *NodeConfigurator) WriteConciseDCF(dcf []byte) error {
+	entries, err := od.DecodeConciseDCF(dcf)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err = config.client.WriteRaw(config.nodeId, entry.Index, entry.Subindex, entry.Data, false)
+		if err != nil {
+			return fmt.Errorf("failed to write x%x|x%x : %w", entry.Index, entry.Subindex, err)
+		}
+	}
+	config.logger.Debug("downloaded concise DCF", "entries", len(entries))
+	return nil
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"log/slog"
 
+	"github.com/samsamfire/gocanopen/pkg/lss"
+	"github.com/samsamfire/gocanopen/pkg/od"
 	"github.com/samsamfire/gocanopen/pkg/sdo"
 )
 
@@ -15,6 +17,8 @@ type NodeConfigurator struct {
 	logger *slog.Logger
 	client *sdo.SDOClient
 	nodeId uint8
+	lss    *lss.LSSMaster
+	od     *od.ObjectDictionary
 }
 
 // Create a new [NodeConfigurator] for given ID and SDOClient
@@ -25,3 +29,9 @@ func NewNodeConfigurator(nodeId uint8, logger *slog.Logger, client *sdo.SDOClien
 	configurator := NodeConfigurator{logger: logger.With("service", "[CONFIG]"), client: client, nodeId: nodeId}
 	return &configurator
 }
+
+// Set the object dictionary of the configured node, e.g. from its EDS.
+// It is used for validating PDO mappings before writing them, see [NodeConfigurator.RemapPDO].
+func (config *NodeConfigurator) SetOD(odict *od.ObjectDictionary) {
+	config.od = odict
+}
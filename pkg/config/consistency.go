@@ -0,0 +1,34 @@
+package config
+
+import (
+	"reflect"
+)
+
+// Value to write to a given subindex
+type SubindexValue struct {
+	Subindex uint8
+	Value    any
+}
+
+// WriteCommit writes several subindexes of a record, then writes commitValue to
+// commitSubindex so that the node applies all values at once.
+// This is meant for nodes using a shadow buffer (e.g. [od.ShadowRecord]) so that
+// partial updates are never observed by the node's application.
+// If a write fails, the zero value of commitValue is written to commitSubindex to discard
+// any pending value.
+func (config *NodeConfigurator) WriteCommit(index uint16, values []SubindexValue, commitSubindex uint8, commitValue any) error {
+	for _, value := range values {
+		err := config.client.WriteRaw(config.nodeId, index, value.Subindex, value.Value, false)
+		if err != nil {
+			config.logger.Warn("failed to write, discarding pending values",
+				"index", index,
+				"subindex", value.Subindex,
+				"error", err,
+			)
+			discard := reflect.Zero(reflect.TypeOf(commitValue)).Interface()
+			_ = config.client.WriteRaw(config.nodeId, index, commitSubindex, discard, false)
+			return err
+		}
+	}
+	return config.client.WriteRaw(config.nodeId, index, commitSubindex, commitValue, false)
+}
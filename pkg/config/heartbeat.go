@@ -1,6 +1,13 @@
 package config
 
-import "github.com/samsamfire/gocanopen/pkg/od"
+import (
+	"errors"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Returned when all the heartbeat consumer entries (0x1016) are already in use
+var ErrNoFreeMonitorEntry = errors.New("no free heartbeat consumer entry")
 
 // Read current monitored nodes
 // Returns a list of all the entries composed as the id of the monitored node
@@ -39,6 +46,45 @@ func (config *NodeConfigurator) WriteMonitoredNode(index uint8, nodeId uint8, pe
 	return config.client.WriteRaw(config.nodeId, od.EntryConsumerHeartbeatTime, index, periodAndId, false)
 }
 
+// Monitor a node with the expected heartbeat period, without having to
+// select the 0x1016 sub-index. The entry already monitoring nodeId is updated,
+// otherwise the first unused entry is taken.
+// Returns [ErrNoFreeMonitorEntry] if every entry is in use.
+func (config *NodeConfigurator) AddMonitoredNode(nodeId uint8, periodMs uint16) error {
+	monitored, err := config.ReadMonitoredNodes()
+	if err != nil {
+		return err
+	}
+	free := -1
+	for i, entry := range monitored {
+		if entry[0] == uint16(nodeId) && entry[1] != 0 {
+			return config.WriteMonitoredNode(uint8(i+1), nodeId, periodMs)
+		}
+		if free < 0 && (entry[0] == 0 || entry[1] == 0) {
+			free = i
+		}
+	}
+	if free < 0 {
+		return ErrNoFreeMonitorEntry
+	}
+	return config.WriteMonitoredNode(uint8(free+1), nodeId, periodMs)
+}
+
+// Stop monitoring a node, the corresponding 0x1016 entry is cleared.
+// Nothing is done if the node is not monitored.
+func (config *NodeConfigurator) RemoveMonitoredNode(nodeId uint8) error {
+	monitored, err := config.ReadMonitoredNodes()
+	if err != nil {
+		return err
+	}
+	for i, entry := range monitored {
+		if entry[0] == uint16(nodeId) && entry[1] != 0 {
+			return config.WriteMonitoredNode(uint8(i+1), 0, 0)
+		}
+	}
+	return nil
+}
+
 // Read a nodes heartbeat period and returns it in milliseconds
 func (config *NodeConfigurator) ReadHeartbeatPeriod() (uint16, error) {
 	return config.client.ReadUint16(config.nodeId, od.EntryProducerHeartbeatTime, 0)
@@ -48,3 +94,24 @@ func (config *NodeConfigurator) ReadHeartbeatPeriod() (uint16, error) {
 func (config *NodeConfigurator) WriteHeartbeatPeriod(periodMs uint16) error {
 	return config.client.WriteRaw(config.nodeId, od.EntryProducerHeartbeatTime, 0, periodMs, false)
 }
+
+// Read node guarding parameters, guard time (0x100C) in milliseconds
+// and life time factor (0x100D)
+func (config *NodeConfigurator) ReadNodeGuarding() (guardTimeMs uint16, lifeTimeFactor uint8, err error) {
+	guardTimeMs, err = config.client.ReadUint16(config.nodeId, od.EntryGuardTime, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	lifeTimeFactor, err = config.client.ReadUint8(config.nodeId, od.EntryLifeTimeFactor, 0)
+	return guardTimeMs, lifeTimeFactor, err
+}
+
+// Update node guarding parameters, guard time (0x100C) in milliseconds
+// and life time factor (0x100D)
+func (config *NodeConfigurator) WriteNodeGuarding(guardTimeMs uint16, lifeTimeFactor uint8) error {
+	err := config.client.WriteRaw(config.nodeId, od.EntryGuardTime, 0, guardTimeMs, false)
+	if err != nil {
+		return err
+	}
+	return config.client.WriteRaw(config.nodeId, od.EntryLifeTimeFactor, 0, lifeTimeFactor, false)
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/lss"
+)
+
+// Set the LSS master used by the LSS helpers.
+// If not set, a dedicated one is created on first use.
+func (config *NodeConfigurator) SetLSSMaster(master *lss.LSSMaster) {
+	config.lss = master
+}
+
+func (config *NodeConfigurator) lssMaster() (*lss.LSSMaster, error) {
+	if config.lss != nil {
+		return config.lss, nil
+	}
+	master, err := lss.NewLSSMaster(config.client.BusManager, config.logger, lss.DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	config.lss = master
+	return master, nil
+}
+
+func lssAddress(identity Identity) lss.Address {
+	return lss.Address{
+		VendorId:       identity.VendorId,
+		ProductCode:    identity.ProductCode,
+		RevisionNumber: identity.RevisionNumber,
+		SerialNumber:   identity.SerialNumber,
+	}
+}
+
+// Select the device with given identity and execute f while in configuration state.
+// All devices are switched back to waiting state afterwards.
+func (config *NodeConfigurator) withLSS(identity Identity, f func(master *lss.LSSMaster) error) error {
+	master, err := config.lssMaster()
+	if err != nil {
+		return err
+	}
+	err = master.SwitchStateSelective(lssAddress(identity))
+	if err != nil {
+		return err
+	}
+	err = f(master)
+	errSwitch := master.SwitchStateGlobal(lss.StateWaiting)
+	if err != nil {
+		return err
+	}
+	return errSwitch
+}
+
+// SetNodeIdViaLSS sets and stores the node-id of the device matching identity, using LSS.
+// The identity object (0x1018) of the device must be fully known, including serial number.
+// The new node-id is used by the device after its next NMT reset communication.
+func (config *NodeConfigurator) SetNodeIdViaLSS(identity Identity, newId uint8) error {
+	config.logger.Info("setting node-id via LSS", "identity", identity, "newId", newId)
+	return config.withLSS(identity, func(master *lss.LSSMaster) error {
+		err := master.ConfigureNodeId(newId)
+		if err != nil {
+			return err
+		}
+		return master.StoreConfiguration()
+	})
+}
+
+// SetBitrateViaLSS sets and stores the bitrate of the device matching identity, using LSS.
+// Bitrate must be one of [lss.BitrateTable]. The device switches to the new bitrate
+// after delay, the local CAN interface should then be reconfigured accordingly.
+func (config *NodeConfigurator) SetBitrateViaLSS(identity Identity, bitrate int, delay time.Duration) error {
+	config.logger.Info("setting bitrate via LSS", "identity", identity, "bitrate", bitrate)
+	master, err := config.lssMaster()
+	if err != nil {
+		return err
+	}
+	err = master.SwitchStateSelective(lssAddress(identity))
+	if err != nil {
+		return err
+	}
+	err = master.ConfigureBitTiming(bitrate)
+	if err == nil {
+		err = master.StoreConfiguration()
+	}
+	if err != nil {
+		_ = master.SwitchStateGlobal(lss.StateWaiting)
+		return err
+	}
+	// Device stays in configuration state after activation,
+	// switching back to waiting state is done at the new bitrate
+	return master.ActivateBitTiming(delay)
+}
+
+// ReadNodeIdViaLSS reads the currently active node-id of the device matching identity, using LSS.
+// Returns [lss.NodeIdUnconfigured] if device has no node-id.
+func (config *NodeConfigurator) ReadNodeIdViaLSS(identity Identity) (uint8, error) {
+	var nodeId uint8
+	err := config.withLSS(identity, func(master *lss.LSSMaster) error {
+		var err error
+		nodeId, err = master.InquireNodeId()
+		return err
+	})
+	return nodeId, err
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/samsamfire/gocanopen/pkg/od"
 	"github.com/samsamfire/gocanopen/pkg/pdo"
@@ -85,6 +86,13 @@ func (config *NodeConfigurator) ReadMappings(pdoNb uint16) ([]PDOMappingParamete
 	if err != nil {
 		return nil, err
 	}
+	// MPDOs have no mapping (SAM) or a single one (DAM)
+	switch nbMappings {
+	case pdo.MPDOModeSAM:
+		nbMappings = 0
+	case pdo.MPDOModeDAM:
+		nbMappings = 1
+	}
 	for i := range nbMappings {
 		rawMap, err := config.client.ReadUint32(config.nodeId, pdoMappingIndex, i+1)
 		if err != nil {
@@ -136,7 +144,7 @@ func (config *NodeConfigurator) ReadConfigurationRangePDO(
 	pdos := make([]PDOConfigurationParameter, 0)
 	for pdoNb := pdoStartNb; pdoNb <= pdoEndNb; pdoNb++ {
 		conf, err := config.ReadConfigurationPDO(pdoNb)
-		if err != nil && err == sdo.AbortNotExist {
+		if errors.Is(err, sdo.AbortNotExist) {
 			config.logger.Debug("no more pdo",
 				"type", config.getType(pdoNb),
 				"pdoNb", pdoNb,
@@ -285,3 +293,99 @@ func (config *NodeConfigurator) WriteConfigurationPDO(pdoNb uint16, conf PDOConf
 	}
 	return config.WriteMappings(pdoNb, conf.Mappings)
 }
+
+// Check that mappings fit inside of a PDO. If the OD of the node is known,
+// also check that mapped entries exist, are mappable & long enough.
+func (config *NodeConfigurator) validateMappings(pdoNb uint16, mappings []PDOMappingParameter) error {
+	if len(mappings) > int(od.MaxMappedEntriesPdo) {
+		return fmt.Errorf("too many mapped objects (%v) : %w", len(mappings), od.ErrMapLen)
+	}
+	attribute := od.AttributeTpdo
+	if pdoNb <= pdo.MaxRpdoNumber {
+		attribute = od.AttributeRpdo
+	}
+	totalBits := 0
+	for _, mapping := range mappings {
+		totalBits += int(mapping.LengthBits)
+		if mapping.LengthBits == 0 {
+			return fmt.Errorf("x%x|x%x length of 0 bits : %w", mapping.Index, mapping.Subindex, od.ErrNoMap)
+		}
+		// Dummy entries, the index is the data type
+		if mapping.Index < 0x20 && mapping.Subindex == 0 {
+			if err := od.CheckMappingLength(uint8(mapping.Index), uint32(pdo.MaxPdoLength), mapping.LengthBits); err != nil {
+				return fmt.Errorf("x%x|x%x dummy %w", mapping.Index, mapping.Subindex, err)
+			}
+			continue
+		}
+		// Unknown OD, alignment is checked by the node
+		if config.od == nil {
+			continue
+		}
+		entry := config.od.Index(mapping.Index)
+		if entry == nil {
+			return fmt.Errorf("x%x|x%x : %w", mapping.Index, mapping.Subindex, od.ErrIdxNotExist)
+		}
+		variable, err := entry.SubIndex(int(mapping.Subindex))
+		if err != nil {
+			return fmt.Errorf("x%x|x%x : %w", mapping.Index, mapping.Subindex, err)
+		}
+		if variable.Attribute&attribute == 0 {
+			return fmt.Errorf("x%x|x%x not mappable to %v : %w", mapping.Index, mapping.Subindex, config.getType(pdoNb), od.ErrNoMap)
+		}
+		if err := od.CheckMappingLength(variable.DataType, variable.DataLength(), mapping.LengthBits); err != nil {
+			return fmt.Errorf("x%x|x%x %w", mapping.Index, mapping.Subindex, err)
+		}
+	}
+	if totalBits > int(pdo.MaxPdoLength)*8 {
+		return fmt.Errorf("total length of %v bits exceeds PDO length : %w", totalBits, od.ErrMapLen)
+	}
+	return nil
+}
+
+// RemapPDO performs the complete sequence for changing a PDO mapping :
+// disable PDO, clear & write mappings, update communication parameters
+// then re-enable PDO. Mappings are validated before anything is written,
+// using the OD of the node if set with [NodeConfigurator.SetOD].
+// Inhibit time is only written for TPDOs.
+func (config *NodeConfigurator) RemapPDO(
+	pdoNb uint16,
+	mappings []PDOMappingParameter,
+	transmissionType uint8,
+	inhibitTime uint16,
+	eventTimer uint16,
+) error {
+	if pdoNb < pdo.MinPdoNumber || pdoNb > pdo.MaxPdoNumber {
+		return errors.New("pdo number is incorrect")
+	}
+	err := config.validateMappings(pdoNb, mappings)
+	if err != nil {
+		return err
+	}
+	err = config.DisablePDO(pdoNb)
+	if err != nil {
+		return err
+	}
+	err = config.WriteMappings(pdoNb, mappings)
+	if err != nil {
+		return err
+	}
+	err = config.WriteTransmissionType(pdoNb, transmissionType)
+	if err != nil {
+		return err
+	}
+	if pdoNb >= pdo.MinTpdoNumber {
+		err = config.WriteInhibitTime(pdoNb, inhibitTime)
+		if err != nil {
+			return err
+		}
+	}
+	err = config.WriteEventTimer(pdoNb, eventTimer)
+	if err != nil {
+		return err
+	}
+	if len(mappings) == 0 {
+		// Nothing mapped, PDO can't be enabled
+		return nil
+	}
+	return config.EnablePDO(pdoNb)
+}
@@ -1,6 +1,11 @@
 package config
 
-import "github.com/samsamfire/gocanopen/pkg/od"
+import (
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sync"
+)
 
 func (config *NodeConfigurator) ReadCobIdSYNC() (cobId uint32, err error) {
 	return config.client.ReadUint32(config.nodeId, od.EntryCobIdSYNC, 0x0)
@@ -56,3 +61,39 @@ func (config *NodeConfigurator) WriteCommunicationPeriod(periodUs uint32) error
 func (config *NodeConfigurator) WriteWindowLengthPdos(windowPeriodUs uint32) error {
 	return config.client.WriteRaw(config.nodeId, od.EntrySynchronousWindowLength, 0, windowPeriodUs, false)
 }
+
+// Update the SYNC period of a node at runtime, period should be a multiple of 1µs.
+// 0 disables SYNC production, or SYNC timeout monitoring for consumers.
+func (config *NodeConfigurator) WriteSYNCPeriod(period time.Duration) error {
+	periodUs, err := sync.CommCyclePeriodUs(period)
+	if err != nil {
+		return err
+	}
+	return config.WriteCommunicationPeriod(periodUs)
+}
+
+// Configure a node as SYNC producer and start producing. counterOverflow is 0
+// for SYNC messages without counter, or 2 - 240. Values are validated before
+// writing, the period is disabled while updating the counter overflow as required by CiA 301.
+func (config *NodeConfigurator) ConfigureSYNCProducer(period time.Duration, counterOverflow uint8) error {
+	periodUs, err := sync.CommCyclePeriodUs(period)
+	if err != nil {
+		return err
+	}
+	if counterOverflow == 1 || counterOverflow > 240 {
+		return sync.ErrInvalidCounterOverflow
+	}
+	err = config.WriteCommunicationPeriod(0)
+	if err != nil {
+		return err
+	}
+	err = config.WriteCounterOverflow(counterOverflow)
+	if err != nil {
+		return err
+	}
+	err = config.WriteCommunicationPeriod(periodUs)
+	if err != nil {
+		return err
+	}
+	return config.ProducerEnableSYNC()
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"log/slog"
 	"sync"
+	"time"
 
 	canopen "github.com/samsamfire/gocanopen"
 	"github.com/samsamfire/gocanopen/pkg/od"
@@ -24,6 +25,23 @@ const (
 	ErrRegManufacturer  = 0x80 // bit 7 - manufacturer specific
 )
 
+// Error register sent with every emergency
+const defaultErrorRegister = ErrRegGeneric |
+	ErrRegCurrent |
+	ErrRegVoltage |
+	ErrRegTemperature |
+	ErrRegCommunication |
+	ErrRegDevProfile |
+	ErrRegManufacturer
+
+// Emergency lost when the transmit fifo is full, see [EMCY.SetOverflowPolicy]
+type OverflowPolicy uint8
+
+const (
+	OverflowDropNewest OverflowPolicy = 0 // New emergency is not sent, default
+	OverflowDropOldest OverflowPolicy = 1 // Oldest pending emergency is discarded, so the newest ones are sent
+)
+
 // Error codes
 const (
 	ErrNoError          = 0x0000
@@ -245,6 +263,11 @@ func getErrorCodeDescription(errorCode int) string {
 	}
 }
 
+// Returns the description of a CiA 301 emergency error code
+func ErrorCodeDescription(errorCode uint16) string {
+	return getErrorCodeDescription(int(errorCode))
+}
+
 // Fifo for emergency
 type emfifo struct {
 	msg  uint32
@@ -268,7 +291,11 @@ type EMCY struct {
 	fifoWrPtr       byte
 	fifoPpPtr       byte
 	fifoOverflow    byte
-	fifoCount       byte
+	overflowPolicy  OverflowPolicy
+	history         []HistoryEntry // Oldest first
+	historySize     int
+	historyODSize   uint8 // Number of history sub-entries of 0x1003
+	now             func() time.Time
 	producerEnabled bool
 	producerIdent   uint16
 	inhibitTimeUs   uint32 // Changed by writing to object 0x1015
@@ -363,57 +390,19 @@ func (emcy *EMCY) Process(nmtIsPreOrOperational bool, timeDifferenceUs uint32, t
 		}
 		emcy.mu.Lock()
 	}
-	errorRegister := ErrRegGeneric |
-		ErrRegCurrent |
-		ErrRegVoltage |
-		ErrRegTemperature |
-		ErrRegCommunication |
-		ErrRegDevProfile |
-		ErrRegManufacturer
+	errorRegister := byte(defaultErrorRegister)
 
 	if !nmtIsPreOrOperational {
 		return
 	}
 	if len(emcy.fifo) >= 2 {
-		fifoPpPtr := emcy.fifoPpPtr
 		if emcy.inhibitTimer < emcy.inhibitTimeUs {
 			emcy.inhibitTimer += timeDifferenceUs
 		}
-		if fifoPpPtr != emcy.fifoWrPtr &&
+		if emcy.fifoPpPtr != emcy.fifoWrPtr &&
 			emcy.inhibitTimer >= emcy.inhibitTimeUs {
 			emcy.inhibitTimer = 0
-
-			emcy.fifo[fifoPpPtr].msg |= uint32(errorRegister) << 16
-			binary.LittleEndian.PutUint32(emcy.txBuffer.Data[:4], emcy.fifo[fifoPpPtr].msg)
-			_ = emcy.Send(emcy.txBuffer)
-			// Also report own emergency message
-			if emcy.rxCallback != nil {
-				errMsg := uint32(emcy.fifo[fifoPpPtr].msg)
-				emcy.rxCallback(
-					0,
-					uint16(errMsg),
-					byte(errorRegister),
-					byte(errMsg>>24),
-					emcy.fifo[fifoPpPtr].info,
-				)
-			}
-			fifoPpPtr += 1
-			if int(fifoPpPtr) < len(emcy.fifo) {
-				emcy.fifoPpPtr = fifoPpPtr
-			} else {
-				emcy.fifoPpPtr = 0
-			}
-			if emcy.fifoOverflow == 1 {
-				emcy.fifoOverflow = 2
-				emcy.mu.Unlock()
-				emcy.ErrorReport(EmEmergencyBufferFull, ErrGeneric, 0)
-				emcy.mu.Lock()
-			} else if emcy.fifoOverflow == 2 && fifoPpPtr == emcy.fifoWrPtr {
-				emcy.fifoOverflow = 0
-				emcy.mu.Unlock()
-				emcy.ErrorReset(EmEmergencyBufferFull, 0)
-				emcy.mu.Lock()
-			}
+			emcy.sendNext(errorRegister)
 		} else if timerNextUs != nil && emcy.inhibitTimeUs < emcy.inhibitTimer {
 			diff := emcy.inhibitTimeUs - emcy.inhibitTimer
 			if *timerNextUs > diff {
@@ -424,9 +413,76 @@ func (emcy *EMCY) Process(nmtIsPreOrOperational bool, timeDifferenceUs uint32, t
 	}
 }
 
+// Send the oldest pending emergency of the fifo.
+// emcy.mu should be held and the fifo should not be empty
+func (emcy *EMCY) sendNext(errorRegister byte) {
+	fifoPpPtr := emcy.fifoPpPtr
+	emcy.fifo[fifoPpPtr].msg |= uint32(errorRegister) << 16
+	binary.LittleEndian.PutUint32(emcy.txBuffer.Data[:4], emcy.fifo[fifoPpPtr].msg)
+	binary.LittleEndian.PutUint32(emcy.txBuffer.Data[4:], emcy.fifo[fifoPpPtr].info)
+	_ = emcy.Send(emcy.txBuffer)
+	// Also report own emergency message
+	if emcy.rxCallback != nil {
+		errMsg := uint32(emcy.fifo[fifoPpPtr].msg)
+		emcy.rxCallback(
+			0,
+			uint16(errMsg),
+			byte(errorRegister),
+			byte(errMsg>>24),
+			emcy.fifo[fifoPpPtr].info,
+		)
+	}
+	fifoPpPtr = emcy.nextFifoIndex(fifoPpPtr)
+	emcy.fifoPpPtr = fifoPpPtr
+	if emcy.fifoOverflow == 1 {
+		emcy.fifoOverflow = 2
+		emcy.mu.Unlock()
+		emcy.ErrorReport(EmEmergencyBufferFull, ErrGeneric, 0)
+		emcy.mu.Lock()
+	} else if emcy.fifoOverflow == 2 && fifoPpPtr == emcy.fifoWrPtr {
+		emcy.fifoOverflow = 0
+		emcy.mu.Unlock()
+		emcy.ErrorReset(EmEmergencyBufferFull, 0)
+		emcy.mu.Lock()
+	}
+}
+
+func (emcy *EMCY) nextFifoIndex(index byte) byte {
+	index++
+	if int(index) >= len(emcy.fifo) {
+		return 0
+	}
+	return index
+}
+
+// Flush sends all the pending emergencies immediately, ignoring the inhibit time (0x1015)
+// and the NMT state, e.g. before shutting down. Returns the number of emergencies sent.
+func (emcy *EMCY) Flush() int {
+	emcy.mu.Lock()
+	defer emcy.mu.Unlock()
+	sent := 0
+	for len(emcy.fifo) >= 2 && emcy.fifoPpPtr != emcy.fifoWrPtr {
+		emcy.sendNext(defaultErrorRegister)
+		sent++
+	}
+	emcy.inhibitTimer = 0
+	return sent
+}
+
+// SetOverflowPolicy selects which emergency is lost when an error is reported
+// while the transmit fifo is full. In both cases an [EmEmergencyBufferFull] error is reported.
+func (emcy *EMCY) SetOverflowPolicy(policy OverflowPolicy) {
+	emcy.mu.Lock()
+	defer emcy.mu.Unlock()
+	emcy.overflowPolicy = policy
+}
+
 // Set or reset an Error condition
 // Function adds a new Error to the history & Error will be processed by Process function
 func (emcy *EMCY) Error(setError bool, errorBit byte, errorCode uint16, infoCode uint32) {
+	if emcy == nil {
+		return
+	}
 	emcy.mu.Lock()
 	defer emcy.mu.Unlock()
 	index := errorBit >> 3
@@ -453,28 +509,36 @@ func (emcy *EMCY) Error(setError bool, errorBit byte, errorCode uint16, infoCode
 		}
 		errorCode = ErrNoError
 	}
+	emcy.errorStatusBits[index] = errorStatusBits ^ byte(bitMask)
+	emcy.recordHistory(errorBit, errorCode, infoCode)
 	errMsg := (uint32(errorBit) << 24) | uint32(errorCode)
 	if len(emcy.fifo) >= 2 {
 		fifoWrPtr := emcy.fifoWrPtr
-		fifoWrPtrNext := fifoWrPtr + 1
-		if int(fifoWrPtrNext) >= len(emcy.fifo) {
-			fifoWrPtrNext = 0
-		}
+		fifoWrPtrNext := emcy.nextFifoIndex(fifoWrPtr)
 		if fifoWrPtrNext == emcy.fifoPpPtr {
 			emcy.fifoOverflow = 1
-		} else {
-			emcy.fifo[fifoWrPtr].msg = errMsg
-			emcy.fifo[fifoWrPtr].info = infoCode
-			emcy.fifoWrPtr = fifoWrPtrNext
-			if int(emcy.fifoCount) < len(emcy.fifo)-1 {
-				emcy.fifoCount++
+			if emcy.overflowPolicy == OverflowDropNewest {
+				return
 			}
+			// Discard oldest pending emergency
+			emcy.fifoPpPtr = emcy.nextFifoIndex(emcy.fifoPpPtr)
 		}
+		emcy.fifo[fifoWrPtr].msg = errMsg
+		emcy.fifo[fifoWrPtr].info = infoCode
+		emcy.fifoWrPtr = fifoWrPtrNext
+	}
+}
+
+// Logger of EMCY, a zero value or nil EMCY (e.g. used by remote nodes) logs with the default logger
+func (emcy *EMCY) log() *slog.Logger {
+	if emcy == nil || emcy.logger == nil {
+		return slog.Default()
 	}
+	return emcy.logger
 }
 
 func (emcy *EMCY) ErrorReport(errorBit byte, errorCode uint16, infoCode uint32) {
-	emcy.logger.Info("report emergency",
+	emcy.log().Info("report emergency",
 		"code description", getErrorCodeDescription(int(errorCode)),
 		"errorCode", errorCode,
 		"bit description", getErrorStatusDescription(errorBit),
@@ -484,7 +548,7 @@ func (emcy *EMCY) ErrorReport(errorBit byte, errorCode uint16, infoCode uint32)
 }
 
 func (emcy *EMCY) ErrorReset(errorBit byte, infoCode uint32) {
-	emcy.logger.Info("reset emergency",
+	emcy.log().Info("reset emergency",
 		"description", getErrorStatusDescription(errorBit),
 		"errorBit", errorBit,
 		"infoCode", infoCode,
@@ -545,11 +609,13 @@ func NewEMCY(
 	if logger == nil {
 		logger = slog.Default()
 	}
-	emcy := &EMCY{BusManager: bm, logger: logger.With("service", "[EMCY]")}
+	emcy := &EMCY{BusManager: bm, logger: logger.With("service", "[EMCY]"), now: time.Now}
 	// TODO handle error register ptr
 	// emergency.errorRegister
 	fifoSize := entry1003.SubCount()
 	emcy.fifo = make([]emfifo, fifoSize)
+	emcy.historyODSize = uint8(fifoSize - 1)
+	emcy.historySize = fifoSize - 1
 
 	// Get cob id initial & verify
 	cobIdEmergency, ret := entry1014.Uint32(0)
@@ -62,26 +62,21 @@ func readEntry1003(stream *od.Stream, data []byte, countRead *uint16) error {
 	em.mu.Lock()
 	defer em.mu.Unlock()
 
-	if len(em.fifo) < 2 {
+	if em.historyODSize == 0 {
 		return od.ErrDevIncompat
 	}
+	count := em.historyODCount()
 	if stream.Subindex == 0 {
-		data[0] = em.fifoCount
+		data[0] = count
 		*countRead = 1
 		return nil
 	}
-	if stream.Subindex > em.fifoCount {
+	if stream.Subindex > count {
 		return od.ErrNoData
 	}
-	// Most recent error is in subindex 1 and stored behind fifoWrPtr
-	index := int(em.fifoWrPtr) - int(stream.Subindex)
-	if index >= len(em.fifo) {
-		return od.ErrDevIncompat
-	}
-	if index < 0 {
-		index += len(em.fifo)
-	}
-	binary.LittleEndian.PutUint32(data, em.fifo[index].msg)
+	// Most recent error is in subindex 1 and stored last
+	entry := em.history[len(em.history)-int(stream.Subindex)]
+	binary.LittleEndian.PutUint32(data, entry.value())
 	*countRead = 4
 	return nil
 }
@@ -101,8 +96,8 @@ func writeEntry1003(stream *od.Stream, data []byte, countWritten *uint16) error
 	em.mu.Lock()
 	defer em.mu.Unlock()
 
-	// Clear error history
-	em.fifoCount = 0
+	// Clear error history, also the one not shown inside of OD
+	em.history = em.history[:0]
 	*countWritten = 1
 	return nil
 }
@@ -0,0 +1,77 @@
+package emergency
+
+import "time"
+
+// An emergency recorded in the error history of [EMCY], see [EMCY.History]
+type HistoryEntry struct {
+	Time      time.Time
+	ErrorCode uint16 // ErrNoError for an error reset
+	ErrorBit  byte
+	InfoCode  uint32
+}
+
+// Value of the entry inside of the pre-defined error field (0x1003)
+func (entry HistoryEntry) value() uint32 {
+	return uint32(entry.ErrorBit)<<24 | uint32(entry.ErrorCode)
+}
+
+// SetHistorySize changes the number of emergencies kept in the error history, independently
+// of the number of sub-entries of the pre-defined error field (0x1003) which only shows
+// the most recent ones. It defaults to the number of sub-entries of 0x1003.
+// The most recent emergencies are kept when reducing the size.
+func (emcy *EMCY) SetHistorySize(size int) {
+	emcy.mu.Lock()
+	defer emcy.mu.Unlock()
+	emcy.historySize = max(size, 0)
+	emcy.trimHistory()
+}
+
+// History returns the error history, most recent emergency first like inside of 0x1003.
+// It includes the error resets, and also the emergencies that could not be sent
+// because the transmit fifo was full.
+func (emcy *EMCY) History() []HistoryEntry {
+	emcy.mu.Lock()
+	defer emcy.mu.Unlock()
+	history := make([]HistoryEntry, len(emcy.history))
+	for i, entry := range emcy.history {
+		history[len(history)-1-i] = entry
+	}
+	return history
+}
+
+// ClearHistory clears the error history, like writing 0 to 0x1003 sub-index 0
+func (emcy *EMCY) ClearHistory() {
+	emcy.mu.Lock()
+	defer emcy.mu.Unlock()
+	emcy.history = emcy.history[:0]
+}
+
+// Number of entries shown inside of 0x1003, emcy.mu should be held
+func (emcy *EMCY) historyODCount() uint8 {
+	return uint8(min(len(emcy.history), int(emcy.historyODSize)))
+}
+
+// Add an emergency to the history, emcy.mu should be held
+func (emcy *EMCY) recordHistory(errorBit byte, errorCode uint16, infoCode uint32) {
+	if emcy.historySize == 0 {
+		return
+	}
+	now := time.Now
+	if emcy.now != nil {
+		now = emcy.now
+	}
+	emcy.history = append(emcy.history, HistoryEntry{
+		Time:      now(),
+		ErrorCode: errorCode,
+		ErrorBit:  errorBit,
+		InfoCode:  infoCode,
+	})
+	emcy.trimHistory()
+}
+
+// Drop the oldest entries above history size, emcy.mu should be held
+func (emcy *EMCY) trimHistory() {
+	if extra := len(emcy.history) - emcy.historySize; extra > 0 {
+		emcy.history = append(emcy.history[:0], emcy.history[extra:]...)
+	}
+}
@@ -0,0 +1,181 @@
+package emergency
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRateWindow       = 1 * time.Second
+	DefaultRateMaxPerWindow = 10
+)
+
+// Options for [RateLimiter]
+type RateLimiterOptions struct {
+	// Duration of the rate limiting window, identical EMCYs from the
+	// same node received within this window are dropped
+	Window time.Duration
+	// Maximum number of EMCYs forwarded per node and per window.
+	// Above this, node is considered in an EMCY storm and every EMCY is suppressed
+	// until a window with less than MaxPerWindow EMCYs.
+	MaxPerWindow int
+}
+
+// StormEvent is emitted once when a node starts flooding EMCYs
+// and once when it stops
+type StormEvent struct {
+	Ident      uint16 // CAN ID of the emergency, 0 for own emergencies
+	Active     bool   // True on storm start, false on storm end
+	Start      time.Time
+	End        time.Time // Zero while storm is active
+	Suppressed uint64    // EMCYs suppressed during the storm
+}
+
+type EMCYStormCallback func(event StormEvent)
+
+// Statistics of received EMCYs for a single node
+type RateStats struct {
+	Received   uint64 // Total number of EMCYs received
+	Forwarded  uint64 // Number of EMCYs passed to callback
+	Duplicates uint64 // Number of identical EMCYs dropped
+	Suppressed uint64 // Number of EMCYs dropped during storms
+	Storms     uint64 // Number of storms detected
+	InStorm    bool
+}
+
+type emcyMessage struct {
+	errorCode     uint16
+	errorRegister byte
+	errorBit      byte
+	infoCode      uint32
+}
+
+type rateState struct {
+	stats       RateStats
+	windowStart time.Time
+	windowCount int
+	last        emcyMessage
+	lastTime    time.Time
+	stormStart  time.Time
+	stormCount  uint64
+}
+
+// RateLimiter protects an [EMCYRxCallback] against misbehaving devices
+// flooding the bus with EMCYs. It drops duplicates and suppresses EMCYs of nodes
+// that exceed a configurable rate, emitting a single storm event instead.
+// It is used by setting [RateLimiter.Handle] as the EMCY callback :
+//
+//	limiter := NewRateLimiter(callback, onStorm, nil, nil)
+//	emcy.SetCallback(limiter.Handle)
+type RateLimiter struct {
+	logger   *slog.Logger
+	mu       sync.Mutex
+	opts     RateLimiterOptions
+	callback EMCYRxCallback
+	onStorm  EMCYStormCallback
+	nodes    map[uint16]*rateState
+	now      func() time.Time
+}
+
+// Create a new [RateLimiter] forwarding EMCYs to callback.
+// onStorm is optional.
+func NewRateLimiter(callback EMCYRxCallback, onStorm EMCYStormCallback, opts *RateLimiterOptions, logger *slog.Logger) *RateLimiter {
+	if opts == nil {
+		opts = &RateLimiterOptions{}
+	}
+	if opts.Window == 0 {
+		opts.Window = DefaultRateWindow
+	}
+	if opts.MaxPerWindow == 0 {
+		opts.MaxPerWindow = DefaultRateMaxPerWindow
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RateLimiter{
+		logger:   logger.With("service", "[EMCY]"),
+		opts:     *opts,
+		callback: callback,
+		onStorm:  onStorm,
+		nodes:    make(map[uint16]*rateState),
+		now:      time.Now,
+	}
+}
+
+// Handle implements [EMCYRxCallback]
+func (r *RateLimiter) Handle(ident uint16, errorCode uint16, errorRegister byte, errorBit byte, infoCode uint32) {
+	r.mu.Lock()
+	now := r.now()
+	state, ok := r.nodes[ident]
+	if !ok {
+		state = &rateState{windowStart: now}
+		r.nodes[ident] = state
+	}
+	state.stats.Received++
+	var events []StormEvent
+
+	// Start a new window, storm ends if previous window was below threshold
+	if now.Sub(state.windowStart) >= r.opts.Window {
+		if state.stats.InStorm && state.windowCount <= r.opts.MaxPerWindow {
+			state.stats.InStorm = false
+			events = append(events, StormEvent{Ident: ident, Start: state.stormStart, End: now, Suppressed: state.stormCount})
+			r.logger.Info("emergency storm ended", "ident", ident, "suppressed", state.stormCount)
+		}
+		state.windowStart = now
+		state.windowCount = 0
+	}
+	state.windowCount++
+	msg := emcyMessage{errorCode, errorRegister, errorBit, infoCode}
+	forward := false
+
+	switch {
+	case state.stats.InStorm:
+		state.stats.Suppressed++
+		state.stormCount++
+	case state.windowCount > r.opts.MaxPerWindow:
+		state.stats.InStorm = true
+		state.stats.Storms++
+		state.stats.Suppressed++
+		state.stormStart = now
+		state.stormCount = 1
+		events = append(events, StormEvent{Ident: ident, Active: true, Start: now, Suppressed: 1})
+		r.logger.Warn("emergency storm detected, suppressing", "ident", ident, "maxPerWindow", r.opts.MaxPerWindow)
+	case msg == state.last && now.Sub(state.lastTime) < r.opts.Window:
+		state.stats.Duplicates++
+	default:
+		state.stats.Forwarded++
+		state.last = msg
+		state.lastTime = now
+		forward = true
+	}
+	callback, onStorm := r.callback, r.onStorm
+	r.mu.Unlock()
+
+	if onStorm != nil {
+		for _, event := range events {
+			onStorm(event)
+		}
+	}
+	if forward && callback != nil {
+		callback(ident, errorCode, errorRegister, errorBit, infoCode)
+	}
+}
+
+// Returns statistics for every node that sent an EMCY, indexed by CAN ID
+func (r *RateLimiter) Stats() map[uint16]RateStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make(map[uint16]RateStats, len(r.nodes))
+	for ident, state := range r.nodes {
+		stats[ident] = state.stats
+	}
+	return stats
+}
+
+// Reset all statistics and storm states
+func (r *RateLimiter) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nodes = make(map[uint16]*rateState)
+}
@@ -0,0 +1,69 @@
+package emergency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	forwarded := 0
+	events := []StormEvent{}
+	limiter := NewRateLimiter(
+		func(ident, errorCode uint16, errorRegister, errorBit byte, infoCode uint32) { forwarded++ },
+		func(event StormEvent) { events = append(events, event) },
+		&RateLimiterOptions{Window: time.Second, MaxPerWindow: 5},
+		nil,
+	)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	t.Run("duplicates", func(t *testing.T) {
+		limiter.Handle(0x81, ErrGeneric, 0, 0, 0)
+		limiter.Handle(0x81, ErrGeneric, 0, 0, 0)
+		limiter.Handle(0x81, ErrGeneric, 0, 0, 1)
+		assert.Equal(t, 2, forwarded)
+		assert.EqualValues(t, 1, limiter.Stats()[0x81].Duplicates)
+		// Same EMCY after window is forwarded
+		now = now.Add(time.Second)
+		limiter.Handle(0x81, ErrGeneric, 0, 0, 1)
+		assert.Equal(t, 3, forwarded)
+	})
+
+	t.Run("storm", func(t *testing.T) {
+		limiter.Reset()
+		forwarded = 0
+		for i := range 20 {
+			limiter.Handle(0x82, ErrGeneric, 0, 0, uint32(i))
+		}
+		// Other nodes are not affected
+		limiter.Handle(0x83, ErrGeneric, 0, 0, 0)
+		assert.Equal(t, 6, forwarded)
+		assert.Len(t, events, 1)
+		assert.True(t, events[0].Active)
+		stats := limiter.Stats()[0x82]
+		assert.True(t, stats.InStorm)
+		assert.EqualValues(t, 15, stats.Suppressed)
+		assert.EqualValues(t, 1, stats.Storms)
+
+		// Still flooding in next window
+		now = now.Add(time.Second)
+		for i := range 10 {
+			limiter.Handle(0x82, ErrGeneric, 0, 0, uint32(i))
+		}
+		assert.Equal(t, 6, forwarded)
+		assert.Len(t, events, 1)
+
+		// Quiet window, storm ends
+		now = now.Add(time.Second)
+		limiter.Handle(0x82, ErrGeneric, 0, 0, 0)
+		now = now.Add(time.Second)
+		limiter.Handle(0x82, ErrGeneric, 0, 0, 1)
+		assert.Len(t, events, 2)
+		assert.False(t, events[1].Active)
+		assert.EqualValues(t, 26, events[1].Suppressed)
+		assert.Equal(t, 7, forwarded)
+		assert.False(t, limiter.Stats()[0x82].InStorm)
+	})
+}
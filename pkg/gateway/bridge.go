@@ -0,0 +1,181 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/network"
+)
+
+const (
+	sdoRequestId  = 0x600 // Default COB-ID of SDO requests, client to server
+	sdoResponseId = 0x580 // Default COB-ID of SDO responses, server to client
+)
+
+var (
+	ErrUnknownNetwork = errors.New("unknown network number")
+	ErrRouteRange     = errors.New("invalid node id range for route")
+	ErrRouteConflict  = errors.New("node id range is already routed on this network")
+)
+
+// SDORoute forwards the SDO requests received on network From for node ids
+// FirstId to LastId to network To, and the responses back. Node ids are translated
+// so that FirstId on network From is TargetId on network To, e.g. with FirstId 0x50
+// & TargetId 0x10, requests to 0x650 are forwarded to 0x610 and responses from
+// 0x590 are forwarded back to 0x5D0.
+type SDORoute struct {
+	From     uint16 // CiA 309 network number where requests are received
+	To       uint16 // CiA 309 network number where requests are forwarded
+	FirstId  uint8
+	LastId   uint8
+	TargetId uint8 // Node id of FirstId on network To
+}
+
+// Translate a node id of network From to network To
+func (route SDORoute) target(nodeId uint8) uint8 {
+	return nodeId - route.FirstId + route.TargetId
+}
+
+// SDOBridge forwards SDO transfers between networks, according to a routing table.
+// Every SDO frame is forwarded as is, so that all transfer types, including block
+// transfers, are supported. Timeouts are handled by the SDO client & server.
+type SDOBridge struct {
+	logger   *slog.Logger
+	mu       sync.Mutex
+	networks map[uint16]*network.Network
+	routes   []SDORoute
+	// One listener per network, subscribed to routed COB-IDs
+	listeners map[uint16]*bridgeListener
+}
+
+// Listener of the SDO frames of one network
+type bridgeListener struct {
+	bridge *SDOBridge
+	number uint16
+}
+
+func (l *bridgeListener) Handle(frame canopen.Frame) {
+	l.bridge.forward(l.number, frame)
+}
+
+// Create a bridge between networks, keyed by their CiA 309 network number
+func NewSDOBridge(networks map[uint16]*network.Network, logger *slog.Logger) *SDOBridge {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SDOBridge{
+		logger:    logger.With("service", "[BRIDGE]"),
+		networks:  networks,
+		listeners: make(map[uint16]*bridgeListener),
+	}
+}
+
+// Subscribe to SDO frames of nodeId on a network, bridge lock should be held
+func (b *SDOBridge) subscribe(number uint16, cobId uint32) error {
+	listener, ok := b.listeners[number]
+	if !ok {
+		listener = &bridgeListener{bridge: b, number: number}
+		b.listeners[number] = listener
+	}
+	return b.networks[number].Subscribe(cobId, 0x7FF, false, listener)
+}
+
+// AddRoute adds a route to the routing table. Routed node id ranges
+// should not overlap on the same network and networks should be connected.
+func (b *SDOBridge) AddRoute(route SDORoute) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.networks[route.From] == nil || b.networks[route.To] == nil || route.From == route.To {
+		return fmt.Errorf("%w : %v -> %v", ErrUnknownNetwork, route.From, route.To)
+	}
+	if route.FirstId == 0 || route.FirstId > route.LastId || route.LastId > 127 ||
+		route.TargetId == 0 || int(route.LastId)-int(route.FirstId)+int(route.TargetId) > 127 {
+		return ErrRouteRange
+	}
+	for _, existing := range b.routes {
+		if existing.From == route.From && existing.FirstId <= route.LastId && route.FirstId <= existing.LastId {
+			return fmt.Errorf("%w : x%x-x%x", ErrRouteConflict, existing.FirstId, existing.LastId)
+		}
+	}
+	for nodeId := int(route.FirstId); nodeId <= int(route.LastId); nodeId++ {
+		err := b.subscribe(route.From, sdoRequestId+uint32(nodeId))
+		if err != nil {
+			return err
+		}
+		err = b.subscribe(route.To, sdoResponseId+uint32(route.target(uint8(nodeId))))
+		if err != nil {
+			return err
+		}
+	}
+	b.routes = append(b.routes, route)
+	b.logger.Info("added SDO route", "from", route.From, "to", route.To,
+		"first", route.FirstId, "last", route.LastId, "target", route.TargetId)
+	return nil
+}
+
+// RemoveRoute removes the route of network number & node id, if any
+func (b *SDOBridge) RemoveRoute(number uint16, nodeId uint8) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.routes = slices.DeleteFunc(b.routes, func(route SDORoute) bool {
+		return route.From == number && nodeId >= route.FirstId && nodeId <= route.LastId
+	})
+}
+
+// Routes returns a copy of the routing table
+func (b *SDOBridge) Routes() []SDORoute {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.routes)
+}
+
+// Resolve returns the network number & node id to which SDO requests for
+// nodeId on the given network are forwarded, ok is false if not routed.
+func (b *SDOBridge) Resolve(number uint16, nodeId uint8) (to uint16, targetId uint8, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, route := range b.routes {
+		if route.From == number && nodeId >= route.FirstId && nodeId <= route.LastId {
+			return route.To, route.target(nodeId), true
+		}
+	}
+	return 0, 0, false
+}
+
+// Forward a frame received on network number according to routing table
+func (b *SDOBridge) forward(number uint16, frame canopen.Frame) {
+	id := frame.ID & canopen.CanSffMask
+	var destination *network.Network
+	b.mu.Lock()
+	for _, route := range b.routes {
+		switch {
+		case id >= sdoRequestId && route.From == number:
+			nodeId := uint8(id - sdoRequestId)
+			if nodeId >= route.FirstId && nodeId <= route.LastId {
+				destination = b.networks[route.To]
+				frame.ID = sdoRequestId + uint32(route.target(nodeId))
+			}
+		case id < sdoRequestId && route.To == number:
+			targetId := uint8(id - sdoResponseId)
+			if targetId >= route.TargetId && targetId <= route.target(route.LastId) {
+				destination = b.networks[route.From]
+				frame.ID = sdoResponseId + uint32(targetId-route.TargetId+route.FirstId)
+			}
+		}
+		if destination != nil {
+			break
+		}
+	}
+	b.mu.Unlock()
+	if destination == nil {
+		return
+	}
+	err := destination.Send(frame)
+	if err != nil {
+		b.logger.Warn("failed to forward SDO frame", "id", id, "err", err)
+	}
+}
@@ -0,0 +1,77 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/can"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLoopbackNetwork(t *testing.T, channel string) *network.Network {
+	bus := can.NewLoopbackBus(channel)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	assert.Nil(t, net.Connect())
+	t.Cleanup(net.Disconnect)
+	return &net
+}
+
+func TestSDOBridge(t *testing.T) {
+	front := newLoopbackNetwork(t, "bridge-front")
+	back := newLoopbackNetwork(t, "bridge-back")
+	local, err := back.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+	bridge := NewSDOBridge(map[uint16]*network.Network{1: front, 2: back}, nil)
+
+	t.Run("invalid routes", func(t *testing.T) {
+		assert.ErrorIs(t, bridge.AddRoute(SDORoute{From: 1, To: 3, FirstId: 0x50, LastId: 0x50, TargetId: 0x10}), ErrUnknownNetwork)
+		assert.ErrorIs(t, bridge.AddRoute(SDORoute{From: 1, To: 1, FirstId: 0x50, LastId: 0x50, TargetId: 0x10}), ErrUnknownNetwork)
+		assert.ErrorIs(t, bridge.AddRoute(SDORoute{From: 1, To: 2, FirstId: 0x51, LastId: 0x50, TargetId: 0x10}), ErrRouteRange)
+		assert.ErrorIs(t, bridge.AddRoute(SDORoute{From: 1, To: 2, FirstId: 0x50, LastId: 0x5F, TargetId: 0x78}), ErrRouteRange)
+	})
+
+	assert.Nil(t, bridge.AddRoute(SDORoute{From: 1, To: 2, FirstId: 0x50, LastId: 0x5F, TargetId: 0x10}))
+	assert.ErrorIs(t, bridge.AddRoute(SDORoute{From: 1, To: 2, FirstId: 0x40, LastId: 0x50, TargetId: 0x10}), ErrRouteConflict)
+	to, targetId, ok := bridge.Resolve(1, 0x52)
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, to)
+	assert.EqualValues(t, 0x12, targetId)
+	_, _, ok = bridge.Resolve(2, 0x52)
+	assert.False(t, ok)
+
+	t.Run("expedited", func(t *testing.T) {
+		value, err := front.ReadUint32(0x50, 0x1000, 0)
+		assert.Nil(t, err)
+		expected, _ := local.ReadUint(uint16(0x1000), uint8(0))
+		assert.EqualValues(t, expected, value)
+		assert.Nil(t, front.WriteRaw(0x50, 0x2002, 0, int8(0x10), false))
+		written, _ := local.ReadInt("INTEGER8 value", "")
+		assert.EqualValues(t, 0x10, written)
+	})
+
+	t.Run("segmented & block", func(t *testing.T) {
+		assert.Nil(t, front.WriteRaw(0x50, 0x2009, 0, "bridged", true))
+		written, _ := local.ReadString("VISIBLE STRING value", "")
+		assert.Equal(t, "bridged", written)
+		read, err := front.ReadAllWith(context.Background(), 0x50, 0x2009, 0, sdo.TransferOptions{Block: sdo.BlockNever})
+		assert.Nil(t, err)
+		assert.Equal(t, "bridged", string(read))
+		eds, err := front.ReadAllWith(context.Background(), 0x50, 0x1021, 0, sdo.TransferOptions{Block: sdo.BlockAlways})
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(eds), "[FileInfo]"))
+	})
+
+	t.Run("not routed", func(t *testing.T) {
+		_, err := front.ReadUint32(0x60, 0x1000, 0)
+		assert.ErrorIs(t, err, sdo.AbortTimeout)
+		bridge.RemoveRoute(1, 0x55)
+		assert.Empty(t, bridge.Routes())
+		_, err = front.ReadUint32(0x50, 0x1000, 0)
+		assert.ErrorIs(t, err, sdo.AbortTimeout)
+	})
+}
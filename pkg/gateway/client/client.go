@@ -0,0 +1,157 @@
+// Package client is a typed Go client for the CANopen HTTP gateway (CiA 309-5),
+// see [gwhttp.GatewayServer]. It builds the gateway URLs & bodies so that
+// remote applications don't need to.
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/gateway"
+	gwhttp "github.com/samsamfire/gocanopen/pkg/gateway/http"
+)
+
+// Options for creating a [Client]
+type Options struct {
+	// CiA 309 network number, defaults to 1
+	NetworkId uint16
+	// HTTP client used for requests, e.g. for TLS configuration.
+	// Defaults to [http.DefaultClient] settings.
+	HTTPClient *http.Client
+	// API token sent as "Authorization: Bearer <token>"
+	Token string
+	// HTTP basic auth credentials, used if Username is not empty
+	Username string
+	Password string
+	Logger   *slog.Logger
+}
+
+// Client of a gateway, safe for concurrent use. Requests are serialized
+// as the gateway protocol uses sequence numbers.
+type Client struct {
+	mu sync.Mutex
+	gw *gwhttp.GatewayClient
+}
+
+// Adds authentication headers to every request
+type authTransport struct {
+	base     http.RoundTripper
+	token    string
+	username string
+	password string
+}
+
+func (t *authTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	if t.token != "" {
+		r.Header.Set("Authorization", "Bearer "+t.token)
+	} else if t.username != "" {
+		r.SetBasicAuth(t.username, t.password)
+	}
+	return t.base.RoundTrip(r)
+}
+
+// Create a new client for the gateway at baseURL, e.g. "http://localhost:8090"
+func New(baseURL string, opts Options) *Client {
+	if opts.NetworkId == 0 {
+		opts.NetworkId = 1
+	}
+	gw := gwhttp.NewGatewayClient(baseURL, gwhttp.API_VERSION, int(opts.NetworkId), opts.Logger)
+	if opts.HTTPClient != nil {
+		gw.Client = *opts.HTTPClient
+	}
+	if opts.Token != "" || opts.Username != "" {
+		base := gw.Client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		gw.Client.Transport = &authTransport{base: base, token: opts.Token, username: opts.Username, password: opts.Password}
+	}
+	return &Client{gw: gw}
+}
+
+// Send a request, node is a node id or one of "all", "default" & "none"
+func (c *Client) do(method string, node string, command string, request any, response gwhttp.GatewayResponse) error {
+	var body io.Reader
+	if request != nil {
+		encoded, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+	if response == nil {
+		response = new(gwhttp.GatewayResponseBase)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gw.Do(method, "/"+node+"/"+command, body, response)
+}
+
+func hexValue(value uint64) string {
+	return "0x" + strconv.FormatUint(value, 16)
+}
+
+// Node returns a handle for accessing a single node
+func (c *Client) Node(nodeId uint8) *Node {
+	return &Node{client: c, id: strconv.Itoa(int(nodeId))}
+}
+
+// DefaultNode returns a handle for accessing the default node of the gateway, see [Client.SetDefaultNode]
+func (c *Client) DefaultNode() *Node {
+	return &Node{client: c, id: "default"}
+}
+
+// All returns a handle for broadcasting NMT commands to all the nodes.
+// SDO accesses are not supported on it.
+func (c *Client) All() *Node {
+	return &Node{client: c, id: "all"}
+}
+
+// Version returns the gateway version information
+func (c *Client) Version() (*gateway.GatewayVersion, error) {
+	version := new(gwhttp.VersionInfo)
+	err := c.do(http.MethodGet, "none", "info/version", nil, version)
+	if err != nil {
+		return nil, err
+	}
+	return version.GatewayVersion, nil
+}
+
+// SetSDOTimeout updates the timeout of the gateway SDO client
+func (c *Client) SetSDOTimeout(timeout time.Duration) error {
+	request := gwhttp.SDOSetTimeoutRequest{Value: hexValue(uint64(timeout.Milliseconds()))}
+	return c.do(http.MethodPut, "all", "set/sdo-timeout", request, nil)
+}
+
+// SetDefaultNetwork updates the default network of the gateway
+func (c *Client) SetDefaultNetwork(networkId uint16) error {
+	return c.do(http.MethodPut, "none", "set/network", gwhttp.SetDefaultNetOrNode{Value: hexValue(uint64(networkId))}, nil)
+}
+
+// SetDefaultNode updates the default node of the gateway
+func (c *Client) SetDefaultNode(nodeId uint8) error {
+	return c.do(http.MethodPut, "none", "set/node", gwhttp.SetDefaultNetOrNode{Value: hexValue(uint64(nodeId))}, nil)
+}
+
+// Decode data of a read response, sent as a big endian hexadecimal string
+func decodeData(data string) ([]byte, error) {
+	if len(data) < 2 || data[:2] != "0x" {
+		return nil, fmt.Errorf("invalid data %q", data)
+	}
+	raw, err := hex.DecodeString(data[2:])
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(raw)
+	return raw, nil
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/config"
+	gwhttp "github.com/samsamfire/gocanopen/pkg/gateway/http"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	local, err := net.CreateLocalNode(0x68, od.Default())
+	assert.Nil(t, err)
+	gw := gwhttp.NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw)
+	defer ts.Close()
+	client := New(ts.URL, Options{})
+	node := client.Node(0x68)
+
+	t.Run("sdo", func(t *testing.T) {
+		assert.Nil(t, node.WriteUint8(0x2002, 0, 0x33))
+		v8, err := node.ReadUint8(0x2002, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x33, v8)
+		assert.Nil(t, node.WriteUint32(0x2007, 0, 0x12345678))
+		v32, err := node.ReadUint32(0x2007, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x12345678, v32)
+		_, err = node.ReadUint16(0x2007, 0)
+		assert.NotNil(t, err)
+		name, err := node.ReadString(0x1008, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "DUT", name)
+		_, err = node.Read(0x1234, 0)
+		assert.Equal(t, &gwhttp.GatewayError{Code: 0x6020000}, err)
+		_, err = client.All().Read(0x2002, 0)
+		assert.Equal(t, gwhttp.ErrGwUnsupportedNode, err)
+	})
+
+	t.Run("values", func(t *testing.T) {
+		assert.Nil(t, node.WriteValue(0x2003, 0, -300))
+		value, err := node.ReadValue(0x2003, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, -300, value)
+		assert.Nil(t, node.WriteValue(0x2001, 0, true))
+		value, err = node.ReadValue(0x2001, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, true, value)
+		values, err := node.ReadEntry(0x1018)
+		assert.Nil(t, err)
+		assert.Len(t, values, 4)
+		assert.Equal(t, &gwhttp.GatewayError{Code: 0x6070010}, node.WriteValue(0x2001, 0, "yes"))
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		results, err := node.Batch([]gwhttp.BatchItem{
+			{Command: "w", Index: "0x2005", Subindex: "0", Value: json.RawMessage(`"0x11"`), Datatype: "u8"},
+			{Command: "r", Index: "0x2005", Subindex: "0"},
+			{Command: "r", Index: "0x4000", Subindex: "0"},
+		}, false)
+		assert.Nil(t, err)
+		assert.Len(t, results, 3)
+		assert.Nil(t, results[0].GetError())
+		assert.Equal(t, "0x11", results[1].Data)
+		assert.Equal(t, &gwhttp.GatewayError{Code: 0x6020000}, results[2].GetError())
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		n, err := node.ReadStream(0x1008, 0, buffer)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, n)
+		assert.Equal(t, "DUT", buffer.String())
+	})
+
+	t.Run("nmt & heartbeat", func(t *testing.T) {
+		assert.Nil(t, node.PreOperational())
+		assert.Eventually(t, func() bool {
+			return local.NMT.GetInternalState() == nmt.StatePreOperational
+		}, time.Second, 10*time.Millisecond)
+		assert.Nil(t, client.All().Start())
+		assert.Eventually(t, func() bool {
+			return local.NMT.GetInternalState() == nmt.StateOperational
+		}, time.Second, 10*time.Millisecond)
+		assert.Nil(t, node.EnableHeartbeat(300))
+		period, err := local.HeartbeatPeriod()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 300, period)
+		assert.Nil(t, node.DisableHeartbeat())
+		assert.Nil(t, node.EnableGuarding(100, 3))
+		assert.Nil(t, node.DisableGuarding())
+	})
+
+	t.Run("pdo", func(t *testing.T) {
+		err := node.ConfigureTPDO(1, PDOConfig{
+			CobId:            0x1E8,
+			TransmissionType: 0xFE,
+			EventTimer:       100,
+			Mappings:         []config.PDOMappingParameter{{Index: 0x2002, Subindex: 0, LengthBits: 8}},
+		})
+		assert.Nil(t, err)
+		conf, err := net.Configurator(0x68).ReadConfigurationPDO(257)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x1E8, conf.CanId)
+		assert.EqualValues(t, 100, conf.EventTimer)
+		assert.Len(t, conf.Mappings, 1)
+	})
+
+	t.Run("gateway", func(t *testing.T) {
+		version, err := client.Version()
+		assert.Nil(t, err)
+		assert.NotNil(t, version)
+		assert.Nil(t, client.SetDefaultNode(0x68))
+		v8, err := client.DefaultNode().ReadUint8(0x2002, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x33, v8)
+	})
+
+	t.Run("token", func(t *testing.T) {
+		err := gw.SetAuthorization(&gwhttp.AuthOptions{
+			Principal:  gwhttp.BearerTokenPrincipal(map[string]string{"secret": "hmi"}),
+			Authorizer: gwhttp.RoleAuthorizer(map[string]gwhttp.Role{"hmi": gwhttp.RoleReadOnly}),
+		})
+		assert.Nil(t, err)
+		defer gw.SetAuthorization(nil)
+		_, err = node.ReadUint8(0x2002, 0)
+		assert.Equal(t, gwhttp.ErrGwWrongPassword, err)
+		authenticated := New(ts.URL, Options{Token: "secret"}).Node(0x68)
+		_, err = authenticated.ReadUint8(0x2002, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, gwhttp.ErrGwNodeAccessDenied, authenticated.WriteUint8(0x2002, 0, 1))
+	})
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	gwhttp "github.com/samsamfire/gocanopen/pkg/gateway/http"
+	"github.com/samsamfire/gocanopen/pkg/lss"
+)
+
+// LSS master of the gateway, commands are sent to the whole network.
+// A single slave is selected with [LSS.SwitchSelective].
+type LSS struct {
+	client *Client
+}
+
+// LSS returns the LSS commands of the gateway
+func (c *Client) LSS() *LSS {
+	return &LSS{client: c}
+}
+
+func (l *LSS) value(command string, value uint64) error {
+	return l.client.do(http.MethodPut, "all", command, gwhttp.LSSValueRequest{Value: hexValue(value)}, nil)
+}
+
+// Switch all slaves to waiting or configuration state (lss.StateWaiting, lss.StateConfiguration)
+func (l *LSS) SwitchGlobal(state uint8) error {
+	return l.value("lss/switch/glob", uint64(state))
+}
+
+// Switch a single slave to configuration state
+func (l *LSS) SwitchSelective(address lss.Address) error {
+	request := gwhttp.LSSAddress{
+		VendorId:       hexValue(uint64(address.VendorId)),
+		ProductCode:    hexValue(uint64(address.ProductCode)),
+		RevisionNumber: hexValue(uint64(address.RevisionNumber)),
+		SerialNumber:   hexValue(uint64(address.SerialNumber)),
+	}
+	return l.client.do(http.MethodPut, "all", "lss/switch/sel", request, nil)
+}
+
+// Configure node-id of the slave in configuration state
+func (l *LSS) SetNodeId(nodeId uint8) error {
+	return l.value("lss/set/node", uint64(nodeId))
+}
+
+// Configure bitrate of the slave in configuration state, using the CiA 305 table
+func (l *LSS) ConfigureBitrate(bitrate int) error {
+	index, ok := lss.BitrateTable[bitrate]
+	if !ok {
+		return lss.ErrBitrateInvalid
+	}
+	request := gwhttp.LSSConfigureBitrateRequest{TableSelector: "0", TableIndex: strconv.Itoa(int(index))}
+	return l.client.do(http.MethodPut, "all", "lss/conf/bitrate", request, nil)
+}
+
+// Activate the configured bitrate on all slaves after the switch delay
+func (l *LSS) ActivateBitrate(delay time.Duration) error {
+	return l.value("lss/activate/bitrate", uint64(delay.Milliseconds()))
+}
+
+// Store configuration of the slave in configuration state
+func (l *LSS) Store() error {
+	return l.client.do(http.MethodPut, "all", "lss/store", nil, nil)
+}
+
+// Inquire node-id of the slave in configuration state
+func (l *LSS) InquireNodeId() (uint8, error) {
+	response := new(gwhttp.LSSGetNodeResponse)
+	err := l.client.do(http.MethodGet, "all", "lss/get/node", nil, response)
+	if err != nil {
+		return 0, err
+	}
+	nodeId, err := strconv.ParseUint(response.Value, 0, 8)
+	return uint8(nodeId), err
+}
+
+// Inquire address of the slave in configuration state
+func (l *LSS) InquireAddress() (lss.Address, error) {
+	response := new(gwhttp.LSSInquireAddressResponse)
+	err := l.client.do(http.MethodGet, "all", "lss/inquire/addr", nil, response)
+	if err != nil {
+		return lss.Address{}, err
+	}
+	if response.LSSAddress == nil {
+		return lss.Address{}, gwhttp.ErrGwRequestNotProcessed
+	}
+	fields := []string{response.VendorId, response.ProductCode, response.RevisionNumber, response.SerialNumber}
+	values := [4]uint32{}
+	for i, field := range fields {
+		value, err := strconv.ParseUint(field, 0, 32)
+		if err != nil {
+			return lss.Address{}, err
+		}
+		values[i] = uint32(value)
+	}
+	return lss.Address{VendorId: values[0], ProductCode: values[1], RevisionNumber: values[2], SerialNumber: values[3]}, nil
+}
@@ -0,0 +1,301 @@
+package client
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	gwhttp "github.com/samsamfire/gocanopen/pkg/gateway/http"
+)
+
+// Node accessed through the gateway, see [Client.Node]
+type Node struct {
+	client *Client
+	id     string
+}
+
+func sdoCommand(command string, index uint16, subIndex uint8) string {
+	return fmt.Sprintf("%s/0x%x/0x%x", command, index, subIndex)
+}
+
+func (node *Node) read(command string, index uint16, subIndex uint8) ([]byte, error) {
+	response := new(gwhttp.SDOReadResponse)
+	err := node.client.do(http.MethodGet, node.id, sdoCommand(command, index, subIndex), nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return decodeData(response.Data)
+}
+
+// Read an object via SDO, data is returned as stored in the OD (little endian)
+func (node *Node) Read(index uint16, subIndex uint8) ([]byte, error) {
+	return node.read("r", index, subIndex)
+}
+
+// Read an object via SDO block transfer, e.g. for big objects
+func (node *Node) ReadBlock(index uint16, subIndex uint8) ([]byte, error) {
+	return node.read("rb", index, subIndex)
+}
+
+// Read an object of an expected size
+func (node *Node) readExactly(index uint16, subIndex uint8, size int) ([]byte, error) {
+	data, err := node.Read(index, subIndex)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf("unexpected length x%x|x%x : expected %v, got %v", index, subIndex, size, len(data))
+	}
+	return data, nil
+}
+
+func (node *Node) ReadUint8(index uint16, subIndex uint8) (uint8, error) {
+	data, err := node.readExactly(index, subIndex, 1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+func (node *Node) ReadUint16(index uint16, subIndex uint8) (uint16, error) {
+	data, err := node.readExactly(index, subIndex, 2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(data), nil
+}
+
+func (node *Node) ReadUint32(index uint16, subIndex uint8) (uint32, error) {
+	data, err := node.readExactly(index, subIndex, 4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(data), nil
+}
+
+func (node *Node) ReadUint64(index uint16, subIndex uint8) (uint64, error) {
+	data, err := node.readExactly(index, subIndex, 8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(data), nil
+}
+
+// Read a VISIBLE_STRING
+func (node *Node) ReadString(index uint16, subIndex uint8) (string, error) {
+	data, err := node.Read(index, subIndex)
+	return string(data), err
+}
+
+// Write an object via SDO. value is given as a string e.g. "0x10" or "-5",
+// datatype is one of the gateway data types e.g. "u8", "i32", "r32", "vs".
+func (node *Node) Write(index uint16, subIndex uint8, value string, datatype string) error {
+	request := gwhttp.SDOWriteRequest{Value: value, Datatype: datatype}
+	return node.client.do(http.MethodPut, node.id, sdoCommand("w", index, subIndex), request, nil)
+}
+
+// Like [Node.Write] but using SDO block transfer
+func (node *Node) WriteBlock(index uint16, subIndex uint8, value string, datatype string) error {
+	request := gwhttp.SDOWriteRequest{Value: value, Datatype: datatype}
+	return node.client.do(http.MethodPut, node.id, sdoCommand("wb", index, subIndex), request, nil)
+}
+
+func (node *Node) WriteUint8(index uint16, subIndex uint8, value uint8) error {
+	return node.Write(index, subIndex, hexValue(uint64(value)), "u8")
+}
+
+func (node *Node) WriteUint16(index uint16, subIndex uint8, value uint16) error {
+	return node.Write(index, subIndex, hexValue(uint64(value)), "u16")
+}
+
+func (node *Node) WriteUint32(index uint16, subIndex uint8, value uint32) error {
+	return node.Write(index, subIndex, hexValue(uint64(value)), "u32")
+}
+
+func (node *Node) WriteUint64(index uint16, subIndex uint8, value uint64) error {
+	return node.Write(index, subIndex, hexValue(value), "u64")
+}
+
+func (node *Node) WriteInt32(index uint16, subIndex uint8, value int32) error {
+	return node.Write(index, subIndex, strconv.FormatInt(int64(value), 10), "i32")
+}
+
+// Write a VISIBLE_STRING
+func (node *Node) WriteString(index uint16, subIndex uint8, value string) error {
+	return node.Write(index, subIndex, value, "vs")
+}
+
+// Read an object & stream its content to w, without buffering, see [gwhttp.GatewayClient.ReadStream]
+func (node *Node) ReadStream(index uint16, subIndex uint8, w io.Writer) (int64, error) {
+	nodeId, err := node.numericId()
+	if err != nil {
+		return 0, err
+	}
+	node.client.mu.Lock()
+	defer node.client.mu.Unlock()
+	return node.client.gw.ReadStream(nodeId, index, subIndex, w)
+}
+
+// Write an object with data streamed from r, see [gwhttp.GatewayClient.WriteStream]
+func (node *Node) WriteStream(index uint16, subIndex uint8, r io.Reader) (int64, error) {
+	nodeId, err := node.numericId()
+	if err != nil {
+		return 0, err
+	}
+	node.client.mu.Lock()
+	defer node.client.mu.Unlock()
+	return node.client.gw.WriteStream(nodeId, index, subIndex, r)
+}
+
+func (node *Node) numericId() (uint8, error) {
+	id, err := strconv.ParseUint(node.id, 10, 8)
+	if err != nil {
+		return 0, gwhttp.ErrGwUnsupportedNode
+	}
+	return uint8(id), nil
+}
+
+// NMT start
+func (node *Node) Start() error {
+	return node.client.do(http.MethodPut, node.id, "start", nil, nil)
+}
+
+// NMT stop
+func (node *Node) Stop() error {
+	return node.client.do(http.MethodPut, node.id, "stop", nil, nil)
+}
+
+// NMT enter pre-operational
+func (node *Node) PreOperational() error {
+	return node.client.do(http.MethodPut, node.id, "preop", nil, nil)
+}
+
+// NMT reset node
+func (node *Node) ResetNode() error {
+	return node.client.do(http.MethodPut, node.id, "reset/node", nil, nil)
+}
+
+// NMT reset communication
+func (node *Node) ResetCommunication() error {
+	return node.client.do(http.MethodPut, node.id, "reset/comm", nil, nil)
+}
+
+// Enable heartbeat production of the node with the given period
+func (node *Node) EnableHeartbeat(periodMs uint16) error {
+	request := gwhttp.HeartbeatRequest{Value: strconv.Itoa(int(periodMs))}
+	return node.client.do(http.MethodPut, node.id, "enable/heartbeat", request, nil)
+}
+
+// Disable heartbeat production of the node
+func (node *Node) DisableHeartbeat() error {
+	return node.client.do(http.MethodPut, node.id, "disable/heartbeat", nil, nil)
+}
+
+// Start node guarding of the node by the gateway
+func (node *Node) EnableGuarding(guardTimeMs uint16, lifeTimeFactor uint8) error {
+	request := gwhttp.GuardingRequest{
+		GuardTime:      strconv.Itoa(int(guardTimeMs)),
+		LifeTimeFactor: strconv.Itoa(int(lifeTimeFactor)),
+	}
+	return node.client.do(http.MethodPut, node.id, "enable/guarding", request, nil)
+}
+
+// Stop node guarding of the node by the gateway
+func (node *Node) DisableGuarding() error {
+	return node.client.do(http.MethodPut, node.id, "disable/guarding", nil, nil)
+}
+
+// PDO configuration written by [Node.ConfigureRPDO] & [Node.ConfigureTPDO]
+type PDOConfig struct {
+	CobId            uint16
+	Disabled         bool
+	TransmissionType uint8
+	InhibitTime      uint16 // x100us
+	EventTimer       uint16 // ms
+	Mappings         []config.PDOMappingParameter
+}
+
+func (node *Node) configurePDO(kind string, pdoNb uint16, conf PDOConfig) error {
+	cobId := uint64(conf.CobId)
+	if conf.Disabled {
+		cobId |= 1 << 31
+	}
+	request := gwhttp.PDOSetRequest{
+		CobId:            hexValue(cobId),
+		TransmissionType: strconv.Itoa(int(conf.TransmissionType)),
+		InhibitTime:      strconv.Itoa(int(conf.InhibitTime)),
+		EventTimer:       strconv.Itoa(int(conf.EventTimer)),
+		Mappings:         make([]string, 0, len(conf.Mappings)),
+	}
+	for _, mapping := range conf.Mappings {
+		raw := uint64(mapping.Index)<<16 | uint64(mapping.Subindex)<<8 | uint64(mapping.LengthBits)
+		request.Mappings = append(request.Mappings, fmt.Sprintf("0x%08x", raw))
+	}
+	return node.client.do(http.MethodPut, node.id, fmt.Sprintf("set/%s/%d", kind, pdoNb), request, nil)
+}
+
+// Configure an RPDO of the node, pdoNb is 1-based
+func (node *Node) ConfigureRPDO(pdoNb uint16, conf PDOConfig) error {
+	return node.configurePDO("rpdo", pdoNb, conf)
+}
+
+// Configure a TPDO of the node, pdoNb is 1-based
+func (node *Node) ConfigureTPDO(pdoNb uint16, conf PDOConfig) error {
+	return node.configurePDO("tpdo", pdoNb, conf)
+}
+
+func valueCommand(command string, index uint16, subIndex uint8) string {
+	return sdoCommand(command, index, subIndex) + "?format=json"
+}
+
+// Read an object decoded by the gateway using the node OD, see [gwhttp.ValueMediaType].
+// Value is a bool, a string or a float64 for numbers, as decoded by encoding/json.
+func (node *Node) ReadValue(index uint16, subIndex uint8) (any, error) {
+	response := new(gwhttp.SDOValueResponse)
+	err := node.client.do(http.MethodGet, node.id, valueCommand("r", index, subIndex), nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Value, nil
+}
+
+// Read all the sub-indexes (starting from 1) of an ARRAY or RECORD, decoded using the node OD
+func (node *Node) ReadEntry(index uint16) ([]any, error) {
+	response := new(gwhttp.SDOValueResponse)
+	err := node.client.do(http.MethodGet, node.id, fmt.Sprintf("r/0x%x?format=json", index), nil, response)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := response.Value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value for x%x : %v", index, response.Value)
+	}
+	return values, nil
+}
+
+// Write an object encoded by the gateway using the node OD, value is
+// marshalled to JSON e.g. a bool, number or string
+func (node *Node) WriteValue(index uint16, subIndex uint8, value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	request := gwhttp.SDOValueWriteRequest{Value: encoded}
+	return node.client.do(http.MethodPut, node.id, valueCommand("w", index, subIndex), request, nil)
+}
+
+// Batch sends several SDO accesses to the node in a single request, see [gwhttp.BatchRequest].
+// Results are in the same order as the items, check each result with [gwhttp.BatchResult.GetError].
+func (node *Node) Batch(items []gwhttp.BatchItem, stopOnError bool) ([]gwhttp.BatchResult, error) {
+	response := new(gwhttp.BatchResponse)
+	request := gwhttp.BatchRequest{StopOnError: stopOnError, Items: items}
+	err := node.client.do(http.MethodPut, node.id, "batch", request, response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
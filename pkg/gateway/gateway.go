@@ -1,7 +1,17 @@
 package gateway
 
 import (
+	"context"
+	"io"
 	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+	"github.com/samsamfire/gocanopen/pkg/lss"
 
 	"github.com/samsamfire/gocanopen/pkg/network"
 	"github.com/samsamfire/gocanopen/pkg/nmt"
@@ -22,6 +32,10 @@ type BaseGateway struct {
 	defaultNetwork uint16
 	defaultNodeId  uint8
 	sdoBuffer      []byte
+	sdoMu          sync.Mutex   // SDO client is used for one transfer at a time
+	blockThreshold uint32       // 0 if block transfers are disabled
+	hbPeriod       atomic.Int64 // Heartbeat period in ns, 0 if not started
+	hbLast         atomic.Int64 // Unix nano timestamp of last heartbeat sent
 }
 
 func NewBaseGateway(network *network.Network, logger *slog.Logger, defaultNetwork uint16, defaultNodeId uint8, sdoUploadBufferSize int) *BaseGateway {
@@ -92,7 +106,8 @@ func (gw *BaseGateway) NMTCommand(id uint8, command nmt.Command) error {
 
 // Set SDO timeout
 func (gw *BaseGateway) SetSDOTimeout(timeoutMs uint32) error {
-	// TODO : maybe add mutex in case ongoing transfer
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
 	gw.network.SDOClient.SetTimeout(timeoutMs)
 	gw.network.SDOClient.SetTimeoutBlockTransfer(timeoutMs)
 	gw.logger.Debug("changing sdo client timeout", "timeoutMs", timeoutMs)
@@ -104,9 +119,48 @@ func (gw *BaseGateway) Buffer() []byte {
 	return gw.sdoBuffer
 }
 
+// Enable SDO block transfers for reads & writes bigger than threshold (in bytes).
+// 0 disables block transfers, which is the default, except for streams which
+// then use the SDO client threshold.
+func (gw *BaseGateway) SetSDOBlockThreshold(threshold uint32) {
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	gw.blockThreshold = threshold
+}
+
+// Transfer options for regular reads & writes
+func (gw *BaseGateway) transferOptions() sdo.TransferOptions {
+	if gw.blockThreshold == 0 {
+		return sdo.TransferOptions{Block: sdo.BlockNever}
+	}
+	return sdo.TransferOptions{Block: sdo.BlockAuto, BlockThreshold: gw.blockThreshold}
+}
+
 // Read SDO
 func (gw *BaseGateway) ReadSDO(nodeId uint8, index uint16, subindex uint8) (int, error) {
-	return gw.network.ReadRaw(nodeId, index, subindex, gw.sdoBuffer)
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	return gw.readSDO(nodeId, index, subindex, gw.transferOptions())
+}
+
+// Read SDO using block upload, fails if node does not support it
+func (gw *BaseGateway) ReadSDOBlock(nodeId uint8, index uint16, subindex uint8) (int, error) {
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	return gw.readSDO(nodeId, index, subindex, sdo.TransferOptions{Block: sdo.BlockAlways})
+}
+
+func (gw *BaseGateway) readSDO(nodeId uint8, index uint16, subindex uint8, opts sdo.TransferOptions) (int, error) {
+	r, err := gw.network.NewRawReaderWith(context.Background(), nodeId, index, subindex, 0, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	n, err := r.Read(gw.sdoBuffer)
+	if err != nil && err != io.EOF {
+		return n, err
+	}
+	return n, nil
 }
 
 // Write SDO
@@ -115,7 +169,138 @@ func (gw *BaseGateway) WriteSDO(nodeId uint8, index uint16, subindex uint8, valu
 	if err != nil {
 		return sdo.AbortTypeMismatch
 	}
-	return gw.network.WriteRaw(nodeId, index, subindex, encodedValue, false)
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	return gw.network.WriteRawWith(context.Background(), nodeId, index, subindex, encodedValue, gw.transferOptions())
+}
+
+// Write SDO using block download, fails if node does not support it
+func (gw *BaseGateway) WriteSDOBlock(nodeId uint8, index uint16, subindex uint8, value string, datatype uint8) error {
+	encodedValue, err := od.EncodeFromString(value, datatype, 0)
+	if err != nil {
+		return sdo.AbortTypeMismatch
+	}
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	return gw.network.WriteRawWith(context.Background(), nodeId, index, subindex, encodedValue, sdo.TransferOptions{Block: sdo.BlockAlways})
+}
+
+// Write already encoded data via SDO, optionally using block download
+func (gw *BaseGateway) WriteSDORaw(nodeId uint8, index uint16, subindex uint8, data []byte, block bool) error {
+	opts := gw.transferOptions()
+	if block {
+		opts = sdo.TransferOptions{Block: sdo.BlockAlways}
+	}
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	return gw.network.WriteRawWith(context.Background(), nodeId, index, subindex, data, opts)
+}
+
+// Read SDO and stream data to w, without buffering it.
+// Block transfer is used whenever supported by the node.
+func (gw *BaseGateway) ReadSDOStream(ctx context.Context, nodeId uint8, index uint16, subindex uint8, w io.Writer) (int64, error) {
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	opts := sdo.TransferOptions{Block: sdo.BlockAuto, BlockThreshold: gw.blockThreshold}
+	r, err := gw.network.NewRawReaderWith(ctx, nodeId, index, subindex, 0, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	return io.Copy(w, r)
+}
+
+// Write SDO with data streamed from r until EOF, without buffering it.
+// size is the total size if known, 0 otherwise.
+// Block transfer is used whenever supported by the node.
+func (gw *BaseGateway) WriteSDOStream(ctx context.Context, nodeId uint8, index uint16, subindex uint8, r io.Reader, size uint32) (int64, error) {
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	opts := sdo.TransferOptions{Block: sdo.BlockAuto, BlockThreshold: gw.blockThreshold}
+	w, err := gw.network.NewRawWriterWith(ctx, nodeId, index, subindex, size, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer w.Close()
+	// Not io.Copy, as r could implement io.WriterTo, resulting in several transfers
+	return w.ReadFrom(r)
+}
+
+// Set heartbeat producer period of a node in milliseconds, 0 disables it
+func (gw *BaseGateway) SetHeartbeatPeriod(nodeId uint8, periodMs uint16) error {
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	return gw.network.Configurator(nodeId).WriteHeartbeatPeriod(periodMs)
+}
+
+// Start node guarding of a node by the network, with guard time in milliseconds.
+// Guarding events are reported like heartbeat events, see [network.Network.StartNodeGuardingWith]
+func (gw *BaseGateway) StartNodeGuarding(nodeId uint8, guardTimeMs uint16, lifeTimeFactor uint8) error {
+	return gw.network.StartNodeGuardingWith(nodeId, time.Duration(guardTimeMs)*time.Millisecond, lifeTimeFactor)
+}
+
+// Stop node guarding of a node by the network
+func (gw *BaseGateway) StopNodeGuarding(nodeId uint8) {
+	gw.network.StopNodeGuarding(nodeId)
+}
+
+// Configure a PDO of a node, pdoNb is 1-256 for RPDOs and 257-512 for TPDOs.
+// PDO is disabled during the update, as required by CiA 301, and re-enabled if enabled is true.
+func (gw *BaseGateway) ConfigurePDO(nodeId uint8, pdoNb uint16, conf config.PDOConfigurationParameter, enabled bool) error {
+	gw.sdoMu.Lock()
+	defer gw.sdoMu.Unlock()
+	configurator := gw.network.Configurator(nodeId)
+	err := configurator.DisablePDO(pdoNb)
+	if err != nil {
+		return err
+	}
+	err = configurator.WriteConfigurationPDO(pdoNb, conf)
+	if err != nil || !enabled {
+		return err
+	}
+	return configurator.EnablePDO(pdoNb)
+}
+
+// LSS master of the network, nil if network is not connected
+func (gw *BaseGateway) LSSMaster() *lss.LSSMaster {
+	return gw.network.LSSMaster()
+}
+
+// Network used by the gateway
+func (gw *BaseGateway) Network() *network.Network {
+	return gw.network
+}
+
+// StartHeartbeat periodically sends a CANopen heartbeat for the gateway with the given node id
+// so that the gateway process itself can be monitored by other nodes on the bus.
+// Heartbeat is stopped when ctx is cancelled.
+func (gw *BaseGateway) StartHeartbeat(ctx context.Context, nodeId uint8, period time.Duration) {
+	gw.hbPeriod.Store(int64(period))
+	frame := canopen.NewFrame(uint32(heartbeat.ServiceId)+uint32(nodeId), 0, 1)
+	frame.Data[0] = nmt.StateOperational
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			err := gw.network.Send(frame)
+			if err == nil {
+				gw.hbLast.Store(time.Now().UnixNano())
+			}
+			select {
+			case <-ctx.Done():
+				gw.hbPeriod.Store(0)
+				gw.logger.Info("exiting gateway heartbeat")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Returns the last time the gateway heartbeat was sent and its period.
+// Period is 0 if heartbeat is not running.
+func (gw *BaseGateway) Heartbeat() (last time.Time, period time.Duration) {
+	return time.Unix(0, gw.hbLast.Load()), time.Duration(gw.hbPeriod.Load())
 }
 
 // Disconnect from network
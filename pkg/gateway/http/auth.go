@@ -0,0 +1,292 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrAccessDenied       = errors.New("access denied") // Can be returned by an [Authorizer]
+	ErrNoAuthorizer       = errors.New("no authorizer given")
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrBadCredentials     = errors.New("invalid credentials")
+)
+
+// Kind of operation requested on the gateway
+type Operation string
+
+const (
+	OperationRead    Operation = "read"    // SDO / PDO read
+	OperationWrite   Operation = "write"   // SDO / PDO write
+	OperationNMT     Operation = "nmt"     // NMT commands (start, stop, reset, ...)
+	OperationConfig  Operation = "config"  // Gateway configuration (set/...)
+	OperationInfo    Operation = "info"    // Gateway information (info/...)
+	OperationLSS     Operation = "lss"     // LSS commands (lss/...)
+	OperationEvents  Operation = "events"  // Events stream (/events)
+	OperationMetrics Operation = "metrics" // Prometheus metrics (/metrics)
+)
+
+// Operation submitted to an [Authorizer]
+type AccessRequest struct {
+	Operation Operation
+	Command   string // Raw command, e.g. "r/0x2000/0"
+	NetworkId uint16
+	NodeId    uint8 // 0 means all nodes
+	Index     uint16
+	Subindex  uint8
+	Principal string // Authenticated user, empty if anonymous
+	Client    string // Client IP address
+}
+
+// Decides whether a gateway operation is allowed, e.g. by querying
+// an LDAP directory or an OIDC based policy engine.
+// A nil error allows the operation, any error denies it.
+type Authorizer interface {
+	Authorize(ctx context.Context, req AccessRequest) error
+}
+
+// Adapter to allow the use of ordinary functions as [Authorizer]
+type AuthorizerFunc func(ctx context.Context, req AccessRequest) error
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, req AccessRequest) error {
+	return f(ctx, req)
+}
+
+// Extracts the principal from an HTTP request, e.g. by validating a bearer token.
+// An error means that the request could not be authenticated.
+type PrincipalFunc func(r *http.Request) (string, error)
+
+// Default [PrincipalFunc], uses HTTP basic auth user name without
+// checking the password. Requests without credentials are anonymous.
+func BasicAuthPrincipal(r *http.Request) (string, error) {
+	user, _, _ := r.BasicAuth()
+	return user, nil
+}
+
+// BasicAuthCredentials returns a [PrincipalFunc] checking HTTP basic auth
+// against the given user names & passwords. The principal is the user name.
+func BasicAuthCredentials(users map[string]string) PrincipalFunc {
+	return func(r *http.Request) (string, error) {
+		user, password, ok := r.BasicAuth()
+		if !ok {
+			return "", ErrMissingCredentials
+		}
+		expected, known := users[user]
+		if !known || subtle.ConstantTimeCompare([]byte(password), []byte(expected)) != 1 {
+			return "", ErrBadCredentials
+		}
+		return user, nil
+	}
+}
+
+// BearerTokenPrincipal returns a [PrincipalFunc] checking API tokens given with
+// an "Authorization: Bearer <token>" header. tokens maps every valid token
+// to its principal.
+func BearerTokenPrincipal(tokens map[string]string) PrincipalFunc {
+	return func(r *http.Request) (string, error) {
+		scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return "", ErrMissingCredentials
+		}
+		for valid, principal := range tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(valid)) == 1 {
+				return principal, nil
+			}
+		}
+		return "", ErrBadCredentials
+	}
+}
+
+// Access level of a principal, see [RoleAuthorizer]
+type Role uint8
+
+const (
+	RoleReadOnly  Role = 1 // SDO / PDO reads, gateway information, events & metrics
+	RoleReadWrite Role = 2 // Every operation
+)
+
+// RoleAuthorizer returns an [Authorizer] allowing operations depending on the
+// role of the principal. Principals without a role, e.g. anonymous, are denied.
+func RoleAuthorizer(roles map[string]Role) Authorizer {
+	return AuthorizerFunc(func(ctx context.Context, req AccessRequest) error {
+		switch roles[req.Principal] {
+		case RoleReadWrite:
+			return nil
+		case RoleReadOnly:
+			switch req.Operation {
+			case OperationRead, OperationInfo, OperationEvents, OperationMetrics:
+				return nil
+			}
+		}
+		return ErrAccessDenied
+	})
+}
+
+type AuthOptions struct {
+	Authorizer Authorizer
+	Principal  PrincipalFunc // Defaults to [BasicAuthPrincipal]
+	// Logger for audit trail of allowed & denied operations.
+	// Defaults to the gateway logger.
+	AuditLogger *slog.Logger
+	// Serve /metrics without authentication, e.g. for a Prometheus server
+	// scraping without credentials. Otherwise it is authorized like any
+	// other operation, with [OperationMetrics].
+	PublicMetrics bool
+}
+
+// SetAuthorization enables authorization of every gateway operation.
+// Passing nil disables it. This should be called before serving requests.
+func (g *GatewayServer) SetAuthorization(opts *AuthOptions) error {
+	if opts == nil {
+		g.auth = nil
+		return nil
+	}
+	if opts.Authorizer == nil {
+		return ErrNoAuthorizer
+	}
+	auth := *opts
+	if auth.Principal == nil {
+		auth.Principal = BasicAuthPrincipal
+	}
+	if auth.AuditLogger == nil {
+		auth.AuditLogger = g.logger
+	}
+	auth.AuditLogger = auth.AuditLogger.With("audit", true)
+	g.auth = &auth
+	return nil
+}
+
+// Get the operation corresponding to a command, based on its first part
+func commandOperation(command string) Operation {
+	first, rest, _ := strings.Cut(command, "/")
+	switch first {
+	case "stream":
+		return commandOperation(rest)
+	case "r", "read", "rb", "read-block":
+		return OperationRead
+	case "w", "write", "wb", "write-block":
+		return OperationWrite
+	case "set":
+		return OperationConfig
+	case "info":
+		return OperationInfo
+	case "lss":
+		return OperationLSS
+	default:
+		return OperationNMT
+	}
+}
+
+// Resolve special node tokens
+func (g *GatewayServer) resolveNodeId(nodeId int) uint8 {
+	switch nodeId {
+	case TOKEN_DEFAULT, TOKEN_NONE:
+		return g.DefaultNodeId()
+	case TOKEN_ALL:
+		return 0
+	default:
+		return uint8(nodeId)
+	}
+}
+
+// Authorize an operation & log the decision, returns true if request can proceed.
+// If denied, an error response is sent to the client.
+func (g *GatewayServer) authorize(w http.ResponseWriter, req *GatewayRequest, index uint16, subindex uint8) bool {
+	if g.auth == nil {
+		return true
+	}
+	access := AccessRequest{
+		Operation: commandOperation(req.command),
+		Command:   req.command,
+		NetworkId: uint16(req.networkId),
+		NodeId:    g.resolveNodeId(req.nodeId),
+		Index:     index,
+		Subindex:  subindex,
+		Principal: req.principal,
+		Client:    req.client,
+	}
+	if req.networkId < 0 {
+		access.NetworkId = g.DefaultNetworkId()
+	}
+	err := g.auth.Authorizer.Authorize(req.ctx, access)
+	attrs := []any{
+		"principal", access.Principal,
+		"client", access.Client,
+		"operation", access.Operation,
+		"command", access.Command,
+		"network", access.NetworkId,
+		"node", access.NodeId,
+		"index", fmt.Sprintf("x%x", access.Index),
+		"subindex", fmt.Sprintf("x%x", access.Subindex),
+	}
+	if err == nil {
+		g.auth.AuditLogger.Info("operation allowed", attrs...)
+		return true
+	}
+	g.auth.AuditLogger.Warn("operation denied", append(attrs, "reason", err)...)
+	w.WriteHeader(http.StatusForbidden)
+	w.Write(NewResponseError(int(req.sequence), ErrGwNodeAccessDenied))
+	return false
+}
+
+// Authenticate the request, returns true if request can proceed.
+// If authentication fails, an error response is sent to the client.
+func (g *GatewayServer) authenticate(w http.ResponseWriter, raw *http.Request, req *GatewayRequest) bool {
+	if g.auth == nil {
+		return true
+	}
+	principal, err := g.auth.Principal(raw)
+	if err != nil {
+		g.auth.AuditLogger.Warn("authentication failed",
+			"client", req.client,
+			"command", req.command,
+			"reason", err,
+		)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write(NewResponseError(int(req.sequence), ErrGwWrongPassword))
+		return false
+	}
+	req.principal = principal
+	return true
+}
+
+// Authenticate & authorize a request to an endpoint outside of CiA 309-5 (e.g. /events),
+// returns true if request can proceed. If denied, an error response is sent to the client.
+func (g *GatewayServer) authorizeEndpoint(w http.ResponseWriter, r *http.Request, operation Operation, nodeId uint8) bool {
+	if g.auth == nil {
+		return true
+	}
+	access := AccessRequest{
+		Operation: operation,
+		Command:   string(operation),
+		NetworkId: g.DefaultNetworkId(),
+		NodeId:    nodeId,
+		Client:    clientId(r),
+	}
+	principal, err := g.auth.Principal(r)
+	if err != nil {
+		g.auth.AuditLogger.Warn("authentication failed", "client", access.Client, "command", access.Command, "reason", err)
+		http.Error(w, "authentication failed", http.StatusUnauthorized)
+		return false
+	}
+	access.Principal = principal
+	err = g.auth.Authorizer.Authorize(r.Context(), access)
+	attrs := []any{
+		"principal", access.Principal,
+		"client", access.Client,
+		"operation", access.Operation,
+		"node", access.NodeId,
+	}
+	if err != nil {
+		g.auth.AuditLogger.Warn("operation denied", append(attrs, "reason", err)...)
+		http.Error(w, "access denied", http.StatusForbidden)
+		return false
+	}
+	g.auth.AuditLogger.Info("operation allowed", attrs...)
+	return true
+}
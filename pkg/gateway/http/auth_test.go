@@ -0,0 +1,206 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+// Adds basic auth credentials to every request
+type basicAuthTransport struct {
+	user string
+}
+
+func (t *basicAuthTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.SetBasicAuth(t.user, "")
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestAuthorization(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	_, err = net.CreateLocalNode(0x66, od.Default())
+	assert.Nil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw.serveMux)
+	defer ts.Close()
+
+	// Operators can only read, admins can do everything
+	var requests []AccessRequest
+	authorizer := AuthorizerFunc(func(ctx context.Context, req AccessRequest) error {
+		requests = append(requests, req)
+		if req.Principal == "admin" || (req.Principal == "operator" && req.Operation == OperationRead) {
+			return nil
+		}
+		return ErrAccessDenied
+	})
+	audit := &bytes.Buffer{}
+	assert.Equal(t, ErrNoAuthorizer, gw.SetAuthorization(&AuthOptions{}))
+	assert.Nil(t, gw.SetAuthorization(&AuthOptions{
+		Authorizer:  authorizer,
+		AuditLogger: slog.New(slog.NewTextHandler(audit, nil)),
+	}))
+	operator := NewGatewayClient(ts.URL, API_VERSION, 1, nil)
+	operator.Transport = &basicAuthTransport{user: "operator"}
+	admin := NewGatewayClient(ts.URL, API_VERSION, 1, nil)
+	admin.Transport = &basicAuthTransport{user: "admin"}
+
+	t.Run("sdo", func(t *testing.T) {
+		_, _, err := operator.ReadRaw(0x66, 0x2002, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, AccessRequest{
+			Operation: OperationRead,
+			Command:   "r/8194/0",
+			NetworkId: 1,
+			NodeId:    0x66,
+			Index:     0x2002,
+			Principal: "operator",
+			Client:    "127.0.0.1",
+		}, requests[len(requests)-1])
+		err = operator.WriteRaw(0x66, 0x2002, 0, "0x10", "i8")
+		assert.Equal(t, ErrGwNodeAccessDenied, err)
+		assert.Nil(t, admin.WriteRaw(0x66, 0x2002, 0, "0x10", "i8"))
+	})
+
+	t.Run("nmt", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/cia309-5/1.0/10/1/all/start", "application/json", nil)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, OperationNMT, requests[len(requests)-1].Operation)
+		assert.EqualValues(t, 0, requests[len(requests)-1].NodeId)
+		assert.Equal(t, "", requests[len(requests)-1].Principal)
+	})
+
+	t.Run("audit", func(t *testing.T) {
+		assert.Contains(t, audit.String(), "operation allowed")
+		assert.Contains(t, audit.String(), "operation denied")
+		assert.Contains(t, audit.String(), "principal=operator")
+	})
+
+	t.Run("public endpoints & metrics", func(t *testing.T) {
+		get := func(user string, path string) int {
+			req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+			assert.Nil(t, err)
+			if user != "" {
+				req.SetBasicAuth(user, "")
+			}
+			resp, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+		assert.Equal(t, http.StatusOK, get("", "/healthz"))
+		assert.Equal(t, http.StatusOK, get("", "/openapi.json"))
+		assert.Equal(t, http.StatusForbidden, get("", "/metrics"))
+		assert.Equal(t, http.StatusForbidden, get("operator", "/metrics"))
+		assert.Equal(t, OperationMetrics, requests[len(requests)-1].Operation)
+		assert.Equal(t, http.StatusOK, get("admin", "/metrics"))
+		assert.Nil(t, gw.SetAuthorization(&AuthOptions{Authorizer: authorizer, PublicMetrics: true}))
+		assert.Equal(t, http.StatusOK, get("", "/metrics"))
+	})
+
+	t.Run("authentication failure", func(t *testing.T) {
+		assert.Nil(t, gw.SetAuthorization(&AuthOptions{
+			Authorizer: authorizer,
+			Principal: func(r *http.Request) (string, error) {
+				return "", ErrAccessDenied
+			},
+		}))
+		_, _, err := admin.ReadRaw(0x66, 0x2002, 0)
+		assert.Equal(t, ErrGwWrongPassword, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Nil(t, gw.SetAuthorization(nil))
+		assert.Nil(t, operator.WriteRaw(0x66, 0x2002, 0, "0x10", "i8"))
+	})
+}
+
+// Adds headers to every request, on top of the given transport
+type headerTransport struct {
+	base   http.RoundTripper
+	header http.Header
+}
+
+func (t *headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	for key, values := range t.header {
+		r.Header[key] = values
+	}
+	return t.base.RoundTrip(r)
+}
+
+func TestAuthenticationTLS(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	_, err = net.CreateLocalNode(0x67, od.Default())
+	assert.Nil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewTLSServer(gw.serveMux)
+	defer ts.Close()
+
+	authorizer := RoleAuthorizer(map[string]Role{"viewer": RoleReadOnly, "engineer": RoleReadWrite})
+	newClient := func(header http.Header) *GatewayClient {
+		client := NewGatewayClient(ts.URL, API_VERSION, 1, nil)
+		client.Transport = &headerTransport{base: ts.Client().Transport, header: header}
+		return client
+	}
+	basic := func(user, password string) http.Header {
+		r, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+		r.SetBasicAuth(user, password)
+		return r.Header
+	}
+
+	t.Run("basic auth", func(t *testing.T) {
+		assert.Nil(t, gw.SetAuthorization(&AuthOptions{
+			Authorizer: authorizer,
+			Principal:  BasicAuthCredentials(map[string]string{"viewer": "v", "engineer": "e"}),
+		}))
+		_, _, err := newClient(basic("viewer", "v")).ReadRaw(0x67, 0x2002, 0)
+		assert.Nil(t, err)
+		err = newClient(basic("viewer", "v")).WriteRaw(0x67, 0x2002, 0, "0x10", "i8")
+		assert.Equal(t, ErrGwNodeAccessDenied, err)
+		assert.Nil(t, newClient(basic("engineer", "e")).WriteRaw(0x67, 0x2002, 0, "0x10", "i8"))
+		_, _, err = newClient(basic("engineer", "wrong")).ReadRaw(0x67, 0x2002, 0)
+		assert.Equal(t, ErrGwWrongPassword, err)
+		_, _, err = newClient(nil).ReadRaw(0x67, 0x2002, 0)
+		assert.Equal(t, ErrGwWrongPassword, err)
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		assert.Nil(t, gw.SetAuthorization(&AuthOptions{
+			Authorizer: authorizer,
+			Principal:  BearerTokenPrincipal(map[string]string{"token-v": "viewer", "token-e": "engineer"}),
+		}))
+		bearer := func(token string) http.Header {
+			return http.Header{"Authorization": []string{"Bearer " + token}}
+		}
+		_, _, err := newClient(bearer("token-v")).ReadRaw(0x67, 0x2002, 0)
+		assert.Nil(t, err)
+		err = newClient(bearer("token-v")).WriteRaw(0x67, 0x2002, 0, "0x10", "i8")
+		assert.Equal(t, ErrGwNodeAccessDenied, err)
+		assert.Nil(t, newClient(bearer("token-e")).WriteRaw(0x67, 0x2002, 0, "0x10", "i8"))
+		_, _, err = newClient(bearer("unknown")).ReadRaw(0x67, 0x2002, 0)
+		assert.Equal(t, ErrGwWrongPassword, err)
+	})
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Maximum number of SDO accesses inside of a single batch
+const MAX_BATCH_SIZE = 256
+
+// Batch of SDO accesses to a node, this is not part of CiA 309-5.
+// Items are processed in order, as if they were sent as individual requests
+// to the node of the batch request, i.e. with the same authorization & quotas.
+type BatchRequest struct {
+	// Stop processing at the first failed item, remaining items have no result
+	StopOnError bool        `json:"stop-on-error,omitempty"`
+	Items       []BatchItem `json:"items"`
+}
+
+// A single SDO access of a [BatchRequest]
+type BatchItem struct {
+	Command  string          `json:"command"` // One of r, w, rb, wb
+	Index    string          `json:"index"`
+	Subindex string          `json:"subindex,omitempty"` // Can only be omitted for JSON values, see [ValueMediaType]
+	Value    json.RawMessage `json:"value,omitempty"`    // A string e.g. "0x10", or any JSON value for JSON values
+	Datatype string          `json:"datatype,omitempty"` // Not used for JSON values
+}
+
+// Outcome of a [BatchItem], fields are the same as for the individual request
+type BatchResult struct {
+	Response string          `json:"response"`
+	Data     string          `json:"data,omitempty"`
+	Length   int             `json:"length,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Datatype string          `json:"datatype,omitempty"`
+	Message  string          `json:"message,omitempty"`
+}
+
+// Extract error if any inside of result
+func (result *BatchResult) GetError() error {
+	return (&GatewayResponseBase{Response: result.Response}).GetError()
+}
+
+// Response to a [BatchRequest]. Response is "OK" if the batch was processed,
+// even if some items failed. Results are in the same order as the items.
+type BatchResponse struct {
+	*GatewayResponseBase
+	Results []BatchResult `json:"results"`
+}
+
+// Records the response of a batch item
+type bufferWriter struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (b *bufferWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferWriter) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
+
+func (b *bufferWriter) WriteHeader(status int) {}
+
+// Create the request of a single batch item
+func newBatchItemRequest(req *GatewayRequest, item BatchItem) (*GatewayRequest, error) {
+	switch item.Command {
+	case "r", "w", "rb", "wb":
+	default:
+		return nil, ErrGwRequestNotSupported
+	}
+	command := item.Command + "/" + strings.ToLower(item.Index)
+	if item.Subindex != "" {
+		command += "/" + strings.ToLower(item.Subindex)
+	}
+	itemReq := *req
+	itemReq.command = command
+	itemReq.parameters = nil
+	if item.Command == "w" || item.Command == "wb" {
+		parameters := map[string]any{"value": item.Value}
+		if item.Datatype != "" {
+			parameters["datatype"] = item.Datatype
+		}
+		encoded, err := json.Marshal(parameters)
+		if err != nil {
+			return nil, ErrGwSyntaxError
+		}
+		itemReq.parameters = encoded
+	}
+	return &itemReq, nil
+}
+
+// Process a single batch item with the regular SDO handlers
+func (g *GatewayServer) processBatchItem(req *GatewayRequest, item BatchItem) BatchResult {
+	itemReq, err := newBatchItemRequest(req, item)
+	if err != nil {
+		return BatchResult{Response: toGatewayError(err).Error()}
+	}
+	route, ok := g.findRoute(itemReq.command)
+	if !ok {
+		return BatchResult{Response: ErrGwRequestNotSupported.Error()}
+	}
+	buffer := &bufferWriter{header: http.Header{}}
+	dw := doneWriter{ResponseWriter: buffer, done: new(bool)}
+	err = route(dw, itemReq)
+	if err != nil {
+		return BatchResult{Response: toGatewayError(err).Error()}
+	}
+	if !*dw.done {
+		return BatchResult{Response: "OK"}
+	}
+	var result BatchResult
+	err = json.Unmarshal(buffer.body.Bytes(), &result)
+	if err != nil {
+		return BatchResult{Response: ErrGwRequestNotProcessed.Error()}
+	}
+	return result
+}
+
+// Handle a batch of SDO accesses, see [BatchRequest]
+func (g *GatewayServer) handleBatch(w doneWriter, req *GatewayRequest) error {
+	var batch BatchRequest
+	err := json.Unmarshal(req.parameters, &batch)
+	if err != nil || len(batch.Items) > MAX_BATCH_SIZE {
+		return ErrGwSyntaxError
+	}
+	if _, err := targetNode(g.BaseGateway, req, false); err != nil {
+		return err
+	}
+	results := make([]BatchResult, 0, len(batch.Items))
+	for _, item := range batch.Items {
+		result := g.processBatchItem(req, item)
+		results = append(results, result)
+		if batch.StopOnError && result.GetError() != nil {
+			g.logger.Debug("batch stopped on error", "command", item.Command, "index", item.Index, "response", result.Response)
+			break
+		}
+	}
+	resp := BatchResponse{
+		GatewayResponseBase: NewResponseBase(int(req.sequence), "OK"),
+		Results:             results,
+	}
+	respRaw, err := json.Marshal(resp)
+	if err != nil {
+		return ErrGwRequestNotProcessed
+	}
+	w.Write(respRaw)
+	return nil
+}
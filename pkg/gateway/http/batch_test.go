@@ -0,0 +1,127 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	local, err := net.CreateLocalNode(0x69, od.Default())
+	assert.Nil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw)
+	defer ts.Close()
+
+	send := func(uri string, batch BatchRequest) *BatchResponse {
+		encoded, _ := json.Marshal(batch)
+		req, _ := http.NewRequest(http.MethodPut, ts.URL+uri, bytes.NewReader(encoded))
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		decoded := new(BatchResponse)
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(decoded))
+		return decoded
+	}
+	str := func(value string) json.RawMessage {
+		encoded, _ := json.Marshal(value)
+		return encoded
+	}
+
+	t.Run("ordered results", func(t *testing.T) {
+		resp := send("/cia309-5/1.0/1/1/0x69/batch", BatchRequest{Items: []BatchItem{
+			{Command: "w", Index: "0x2005", Subindex: "0", Value: str("0x20"), Datatype: "u8"},
+			{Command: "r", Index: "0x2005", Subindex: "0"},
+			{Command: "r", Index: "0x4000", Subindex: "0"},
+			{Command: "rb", Index: "0x2007", Subindex: "0"},
+			{Command: "start"},
+		}})
+		assert.Equal(t, "OK", resp.Response)
+		assert.Equal(t, "1", resp.Sequence)
+		assert.Len(t, resp.Results, 5)
+		assert.Equal(t, "OK", resp.Results[0].Response)
+		assert.Equal(t, "0x20", resp.Results[1].Data)
+		assert.Equal(t, 1, resp.Results[1].Length)
+		assert.Equal(t, "ERROR:0x6020000", resp.Results[2].Response)
+		assert.Equal(t, NewGatewayError(0x6020000), resp.Results[2].GetError())
+		assert.Equal(t, "0x22222222", resp.Results[3].Data)
+		assert.Equal(t, "ERROR:100", resp.Results[4].Response)
+		value, _ := local.GetOD().Index(0x2005).Uint8(0)
+		assert.EqualValues(t, 0x20, value)
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		resp := send("/cia309-5/1.0/2/1/0x69/batch", BatchRequest{StopOnError: true, Items: []BatchItem{
+			{Command: "w", Index: "0x2005", Subindex: "0", Value: str("0x30"), Datatype: "u8"},
+			{Command: "w", Index: "0x2005", Subindex: "0", Value: str("0x30"), Datatype: "xx"},
+			{Command: "w", Index: "0x2005", Subindex: "0", Value: str("0x40"), Datatype: "u8"},
+		}})
+		assert.Equal(t, "OK", resp.Response)
+		assert.Len(t, resp.Results, 2)
+		assert.Equal(t, "ERROR:100", resp.Results[1].Response)
+		value, _ := local.GetOD().Index(0x2005).Uint8(0)
+		assert.EqualValues(t, 0x30, value)
+	})
+
+	t.Run("json values", func(t *testing.T) {
+		resp := send("/cia309-5/1.0/3/1/0x69/batch?format=json", BatchRequest{Items: []BatchItem{
+			{Command: "w", Index: "0x2001", Subindex: "0", Value: json.RawMessage("false")},
+			{Command: "r", Index: "0x2001", Subindex: "0"},
+			{Command: "r", Index: "0x1018"},
+			{Command: "w", Index: "0x2001", Subindex: "0", Value: json.RawMessage("12")},
+		}})
+		assert.Len(t, resp.Results, 4)
+		assert.Equal(t, "OK", resp.Results[0].Response)
+		assert.Equal(t, json.RawMessage("false"), resp.Results[1].Value)
+		assert.Equal(t, "b", resp.Results[1].Datatype)
+		var identity []uint32
+		assert.Nil(t, json.Unmarshal(resp.Results[2].Value, &identity))
+		assert.Len(t, identity, 4)
+		assert.Equal(t, "ERROR:0x6070010", resp.Results[3].Response)
+		assert.Contains(t, resp.Results[3].Message, "expected a boolean")
+	})
+
+	t.Run("authorization", func(t *testing.T) {
+		err := gw.SetAuthorization(&AuthOptions{
+			Principal:  BearerTokenPrincipal(map[string]string{"token": "hmi"}),
+			Authorizer: RoleAuthorizer(map[string]Role{"hmi": RoleReadOnly}),
+		})
+		assert.Nil(t, err)
+		defer gw.SetAuthorization(nil)
+		encoded, _ := json.Marshal(BatchRequest{Items: []BatchItem{
+			{Command: "r", Index: "0x2005", Subindex: "0"},
+			{Command: "w", Index: "0x2005", Subindex: "0", Value: str("0x50"), Datatype: "u8"},
+		}})
+		req, _ := http.NewRequest(http.MethodPut, ts.URL+"/cia309-5/1.0/4/1/0x69/batch", bytes.NewReader(encoded))
+		req.Header.Set("Authorization", "Bearer token")
+		httpResp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		defer httpResp.Body.Close()
+		resp := new(BatchResponse)
+		assert.Nil(t, json.NewDecoder(httpResp.Body).Decode(resp))
+		assert.Len(t, resp.Results, 2)
+		assert.Equal(t, "OK", resp.Results[0].Response)
+		assert.Equal(t, ErrGwNodeAccessDenied.Error(), resp.Results[1].Response)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		resp := send("/cia309-5/1.0/5/1/all/batch", BatchRequest{})
+		assert.Equal(t, "ERROR:107", resp.Response)
+		resp = send("/cia309-5/1.0/6/1/0x69/batch", BatchRequest{Items: make([]BatchItem, MAX_BATCH_SIZE+1)})
+		assert.Equal(t, "ERROR:101", resp.Response)
+	})
+}
@@ -39,21 +39,36 @@ func NewGatewayClient(baseURL string, apiVersion string, networkId int, logger *
 // Does error checking : http related errors, json decode errors
 // or actual gateway errors
 func (client *GatewayClient) Do(method string, uri string, body io.Reader, response GatewayResponse) error {
+	httpResp, err := client.send(method, uri, body)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	return client.decode(httpResp, response)
+}
+
+// Send a new HTTP request to CiA endpoint, with the next sequence number
+func (client *GatewayClient) send(method string, uri string, body io.Reader) (*http.Response, error) {
 	client.currentSequenceNb += 1
 	baseUri := client.baseURL + "/cia309-5" + fmt.Sprintf("/%s/%d/%d", client.apiVersion, client.currentSequenceNb, client.networkId)
 	req, err := http.NewRequest(method, baseUri+uri, body)
 	if err != nil {
 		client.logger.Error("failed to create request", "err", err)
-		return err
+		return nil, err
 	}
 	// HTTP request
 	httpResp, err := client.Client.Do(req)
 	if err != nil {
 		client.logger.Error("failed request", "err", err)
-		return err
+		return nil, err
 	}
+	return httpResp, nil
+}
+
+// Decode a JSON response & check for errors
+func (client *GatewayClient) decode(httpResp *http.Response, response GatewayResponse) error {
 	// Decode JSON "generic" response
-	err = json.NewDecoder(httpResp.Body).Decode(response)
+	err := json.NewDecoder(httpResp.Body).Decode(response)
 	if err != nil {
 		client.logger.Error("failed to decode response", "err", err)
 		return err
@@ -95,6 +110,42 @@ func (client *GatewayClient) WriteRaw(nodeId uint8, index uint16, subIndex uint8
 	return client.Do(http.MethodPut, fmt.Sprintf("/%d/w/%d/%d", nodeId, index, subIndex), bytes.NewBuffer(encodedReq), resp)
 }
 
+// Read an object via SDO and stream its raw content to w, without buffering it.
+// This uses the gateway stream endpoint, which is not part of CiA 309-5.
+func (client *GatewayClient) ReadStream(nodeId uint8, index uint16, subIndex uint8, w io.Writer) (int64, error) {
+	httpResp, err := client.send(http.MethodGet, fmt.Sprintf("/%d/stream/r/%d/%d", nodeId, index, subIndex), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer httpResp.Body.Close()
+	// Errors occuring before any data is sent are regular responses
+	if httpResp.Header.Get("Content-Type") != streamContentType {
+		return 0, client.decode(httpResp, new(GatewayResponseBase))
+	}
+	n, err := io.Copy(w, httpResp.Body)
+	if err != nil {
+		return n, err
+	}
+	// Trailer is only available once body is fully read
+	resp := GatewayResponseBase{Response: httpResp.Trailer.Get(StreamResponseTrailer)}
+	if resp.Response == "" {
+		return n, fmt.Errorf("missing %v trailer", StreamResponseTrailer)
+	}
+	return n, resp.GetError()
+}
+
+// Write an object via SDO with data streamed from r until EOF, returns the
+// number of bytes written. Chunked transfer-encoding is used if size of r can't
+// be determined. This uses the gateway stream endpoint, which is not part of CiA 309-5.
+func (client *GatewayClient) WriteStream(nodeId uint8, index uint16, subIndex uint8, r io.Reader) (int64, error) {
+	resp := new(SDOStreamWriteResponse)
+	err := client.Do(http.MethodPut, fmt.Sprintf("/%d/stream/w/%d/%d", nodeId, index, subIndex), r, resp)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Length, nil
+}
+
 // Update SDO client timeout
 func (client *GatewayClient) SetSDOTimeout(timeoutMs uint16) error {
 	req := new(SDOSetTimeoutRequest)
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommands(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	_, err = net.CreateLocalNode(0x66, od.Default())
+	assert.Nil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 0x66, 100)
+	ts := httptest.NewServer(gw.serveMux)
+	defer ts.Close()
+	client := NewGatewayClient(ts.URL, API_VERSION, 1, nil)
+
+	do := func(method string, uri string, body any) error {
+		raw, _ := json.Marshal(body)
+		return client.Do(method, uri, bytes.NewReader(raw), new(GatewayResponseBase))
+	}
+
+	t.Run("nmt", func(t *testing.T) {
+		assert.Nil(t, do(http.MethodPut, "/0x66/start", nil))
+		assert.Nil(t, do(http.MethodPut, "/all/preop", nil))
+		assert.Nil(t, do(http.MethodPut, "/default/reset/comm", nil))
+		assert.Equal(t, ErrGwUnsupportedNode, do(http.MethodPut, "/200/start", nil))
+	})
+
+	t.Run("guarding", func(t *testing.T) {
+		requests := &guardRequestCounter{}
+		assert.Nil(t, net.Subscribe(0x766, 0x7FF, true, requests))
+		defer net.Unsubscribe(requests)
+		assert.Nil(t, do(http.MethodPut, "/0x66/enable/guarding", GuardingRequest{GuardTime: "10", LifeTimeFactor: "3"}))
+		assert.Eventually(t, func() bool { return requests.count.Load() >= 2 }, time.Second, time.Millisecond)
+		assert.Nil(t, do(http.MethodPut, "/0x66/disable/guarding", nil))
+		time.Sleep(20 * time.Millisecond)
+		count := requests.count.Load()
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, count, requests.count.Load())
+		assert.Equal(t, ErrGwSyntaxError, do(http.MethodPut, "/0x66/enable/guarding", nil))
+		assert.Equal(t, ErrGwSyntaxError, do(http.MethodPut, "/0x66/enable/guarding", GuardingRequest{GuardTime: "0", LifeTimeFactor: "3"}))
+		assert.Equal(t, ErrGwSyntaxError, do(http.MethodPut, "/0x66/enable/guarding", GuardingRequest{GuardTime: "10", LifeTimeFactor: "256"}))
+		assert.Equal(t, ErrGwUnsupportedNode, do(http.MethodPut, "/all/enable/guarding", GuardingRequest{GuardTime: "10", LifeTimeFactor: "3"}))
+	})
+
+	t.Run("heartbeat", func(t *testing.T) {
+		assert.Nil(t, do(http.MethodPut, "/0x66/enable/heartbeat", HeartbeatRequest{Value: "500"}))
+		value, _, err := client.ReadRaw(0x66, od.EntryProducerHeartbeatTime, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "0x01f4", value)
+		assert.Nil(t, do(http.MethodPut, "/0x66/disable/heartbeat", nil))
+		value, _, err = client.ReadRaw(0x66, od.EntryProducerHeartbeatTime, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "0x0000", value)
+		assert.Equal(t, ErrGwSyntaxError, do(http.MethodPut, "/0x66/enable/heartbeat", HeartbeatRequest{Value: "0"}))
+		assert.Equal(t, ErrGwUnsupportedNode, do(http.MethodPut, "/all/enable/heartbeat", HeartbeatRequest{Value: "500"}))
+	})
+
+	t.Run("sdo errors", func(t *testing.T) {
+		_, _, err := client.ReadRaw(0x66, 0x4000, 0)
+		assert.Equal(t, NewGatewayError(int(sdo.AbortNotExist)), err)
+		err = do(http.MethodGet, "/all/r/0x2002/0", nil)
+		assert.Equal(t, ErrGwUnsupportedNode, err)
+	})
+
+	t.Run("sdo block", func(t *testing.T) {
+		assert.Nil(t, do(http.MethodPut, "/0x66/wb/0x2002/0", SDOWriteRequest{Value: "0x12", Datatype: "i8"}))
+		resp := new(SDOReadResponse)
+		assert.Nil(t, client.Do(http.MethodGet, "/0x66/rb/0x2002/0", nil, resp))
+		assert.Equal(t, "0x12", resp.Data)
+		assert.Nil(t, client.Do(http.MethodGet, "/default/read-block/0x2002/0", nil, resp))
+		assert.Equal(t, "0x12", resp.Data)
+	})
+
+	t.Run("pdo configuration", func(t *testing.T) {
+		conf := PDOSetRequest{
+			CobId:            "0x1E6",
+			TransmissionType: "254",
+			EventTimer:       "100",
+			Mappings:         []string{"0x20010008", "0x20020008"},
+		}
+		assert.Nil(t, do(http.MethodPut, "/0x66/set/tpdo/1", conf))
+		configurator := net.Configurator(0x66)
+		tpdo, err := configurator.ReadConfigurationPDO(257)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x1E6, tpdo.CanId)
+		assert.EqualValues(t, 254, tpdo.TransmissionType)
+		assert.EqualValues(t, 100, tpdo.EventTimer)
+		assert.Len(t, tpdo.Mappings, 2)
+		enabled, err := configurator.ReadEnabledPDO(257)
+		assert.Nil(t, err)
+		assert.True(t, enabled)
+
+		// Disabled via COB-ID
+		conf.CobId = "0x800001E6"
+		assert.Nil(t, do(http.MethodPut, "/0x66/set/tpdo/1", conf))
+		enabled, err = configurator.ReadEnabledPDO(257)
+		assert.Nil(t, err)
+		assert.False(t, enabled)
+
+		conf.Mappings = []string{"0x20070040", "0x20010008"}
+		assert.Equal(t, ErrGwPDOLengthExceeded, do(http.MethodPut, "/0x66/set/tpdo/1", conf))
+		assert.Equal(t, ErrGwSyntaxError, do(http.MethodPut, "/0x66/set/rpdo/0", conf))
+		assert.Equal(t, ErrGwRequestNotSupported, do(http.MethodGet, "/0x66/r/p/1", nil))
+	})
+
+	t.Run("lss", func(t *testing.T) {
+		net.LSSMaster().SetTimeout(50 * time.Millisecond)
+		defer net.LSSMaster().SetTimeout(1000 * time.Millisecond)
+		assert.Nil(t, do(http.MethodPut, "/none/lss/switch/glob", LSSValueRequest{Value: "1"}))
+		assert.Nil(t, do(http.MethodPut, "/none/lss/switch/glob", LSSValueRequest{Value: "0"}))
+		assert.Equal(t, ErrGwSyntaxError, do(http.MethodPut, "/none/lss/switch/glob", LSSValueRequest{Value: "2"}))
+		// No LSS slave on the bus
+		assert.Equal(t, ErrGwTimeout, do(http.MethodGet, "/none/lss/get/node", nil))
+		assert.Equal(t, ErrGwLSSNodeIDNotSupported, do(http.MethodPut, "/none/lss/set/node", LSSValueRequest{Value: "200"}))
+		assert.Equal(t, ErrGwLSSBitRateNotSupported, do(http.MethodPut, "/none/lss/conf/bitrate",
+			LSSConfigureBitrateRequest{TableSelector: "0", TableIndex: "5"}))
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		assert.Equal(t, ErrGwUnsupportedNode, do(http.MethodPut, "/none/set/node", SetDefaultNetOrNode{Value: "200"}))
+		assert.Nil(t, do(http.MethodPut, "/none/set/node", SetDefaultNetOrNode{Value: fmt.Sprint(0x66)}))
+		assert.Nil(t, do(http.MethodPut, "/none/set/network", SetDefaultNetOrNode{Value: "1"}))
+	})
+}
+
+// Counts node guarding requests (RTR frames)
+type guardRequestCounter struct {
+	count atomic.Int32
+}
+
+func (c *guardRequestCounter) Handle(frame canopen.Frame) {
+	c.count.Add(1)
+}
@@ -1,6 +1,13 @@
 package http
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/samsamfire/gocanopen/pkg/lss"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
 
 var ERROR_GATEWAY_DESCRIPTION_MAP = map[int]string{
 	100: "Request not supported",
@@ -75,3 +82,32 @@ func (e *GatewayError) Error() string {
 	// Return as a hex value (sdo aborts)
 	return fmt.Sprintf("ERROR:0x%x", e.Code)
 }
+
+// Convert any error to the corresponding CiA 309-5 error.
+// SDO aborts are forwarded as is, i.e. "ERROR:0x<abort code>"
+func toGatewayError(err error) *GatewayError {
+	var gwErr *GatewayError
+	if errors.As(err, &gwErr) {
+		return gwErr
+	}
+	var abort sdo.Abort
+	if errors.As(err, &abort) {
+		return &GatewayError{Code: int(abort)}
+	}
+	var lssErr *lss.ConfigurationError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, lss.ErrTimeout):
+		return ErrGwTimeout
+	case errors.Is(err, lss.ErrNodeIdInvalid):
+		return ErrGwLSSNodeIDNotSupported
+	case errors.Is(err, lss.ErrBitrateInvalid):
+		return ErrGwLSSBitRateNotSupported
+	case errors.Is(err, lss.ErrStoreUnsupported), errors.Is(err, lss.ErrStoreFailed):
+		return ErrGwLSSParameterStoringFailed
+	case errors.As(err, &lssErr):
+		return ErrGwLSSImplementationError
+	default:
+		// Apparently no "internal error"
+		return ErrGwRequestNotProcessed
+	}
+}
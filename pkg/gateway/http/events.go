@@ -0,0 +1,336 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+const (
+	DefaultEventsBufferSize = 64
+	DefaultEventsKeepAlive  = 15 * time.Second
+)
+
+// Names of the server-sent events
+const (
+	EventEmergency = "emcy"
+	EventHeartbeat = "heartbeat"
+	EventTPDO      = "tpdo"
+)
+
+// Data of an [EventEmergency] event
+type EmergencyEvent struct {
+	Node          uint8     `json:"node"`
+	Time          time.Time `json:"time"`
+	ErrorCode     string    `json:"error-code"`
+	ErrorRegister string    `json:"error-register"`
+	ErrorBit      string    `json:"error-bit"`
+	InfoCode      string    `json:"info-code"`
+	Description   string    `json:"description"`
+}
+
+// Data of an [EventHeartbeat] event, sent on every NMT state change
+type HeartbeatEvent struct {
+	Node     uint8     `json:"node"`
+	Time     time.Time `json:"time"`
+	State    string    `json:"state"`
+	Previous string    `json:"previous,omitempty"` // Empty on first heartbeat seen
+}
+
+// A mapped value of a TPDO, decoded with the OD of the node
+type TPDOValue struct {
+	Index    string `json:"index"`
+	Subindex string `json:"subindex"`
+	Value    string `json:"value"`
+}
+
+// Data of an [EventTPDO] event
+type TPDOEvent struct {
+	Node   uint8       `json:"node"`
+	Time   time.Time   `json:"time"`
+	Pdo    uint8       `json:"pdo"` // 1 to 4, default COB-IDs only
+	CobId  string      `json:"cob-id"`
+	Data   string      `json:"data"`
+	Values []TPDOValue `json:"values,omitempty"` // Only if OD of node is known
+}
+
+type serverEvent struct {
+	name   string
+	nodeId uint8
+	data   []byte
+}
+
+type eventClient struct {
+	nodeId uint8 // 0 means all nodes
+	tpdo   bool
+	events chan serverEvent
+}
+
+// Dispatches network events to all the connected clients.
+// Events are pushed from the CAN reception, slow clients
+// lose events instead of blocking it.
+type eventBroker struct {
+	network *network.Network
+	logger  *slog.Logger
+	// Not held while subscribing, as frames are handled with bus manager locked
+	startMu sync.Mutex
+	started bool
+	mu      sync.Mutex
+	clients map[*eventClient]struct{}
+	states  map[uint8]uint8
+	nbTPDO  int
+}
+
+func newEventBroker(network *network.Network, logger *slog.Logger) *eventBroker {
+	return &eventBroker{
+		network: network,
+		logger:  logger,
+		clients: make(map[*eventClient]struct{}),
+		states:  make(map[uint8]uint8),
+	}
+}
+
+// Start listening to network events, only done once
+func (broker *eventBroker) start() error {
+	broker.startMu.Lock()
+	defer broker.startMu.Unlock()
+	if broker.started {
+		return nil
+	}
+	err := broker.network.OnEmergency(network.EmergencyAllNodes, broker.onEmergency)
+	if err != nil {
+		return err
+	}
+	for nodeId := uint32(1); nodeId <= 127; nodeId++ {
+		err = broker.network.Subscribe(heartbeat.ServiceId+nodeId, 0x7FF, false, broker)
+		if err != nil {
+			return err
+		}
+		for pdo := uint32(0); pdo < 4; pdo++ {
+			err = broker.network.Subscribe(0x180+pdo*0x100+nodeId, 0x7FF, false, broker)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	broker.started = true
+	return nil
+}
+
+func (broker *eventBroker) subscribe(nodeId uint8, tpdo bool, bufferSize int) *eventClient {
+	client := &eventClient{nodeId: nodeId, tpdo: tpdo, events: make(chan serverEvent, bufferSize)}
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	broker.clients[client] = struct{}{}
+	if tpdo {
+		broker.nbTPDO++
+	}
+	return client
+}
+
+func (broker *eventBroker) unsubscribe(client *eventClient) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	delete(broker.clients, client)
+	if client.tpdo {
+		broker.nbTPDO--
+	}
+}
+
+// Send event to all interested clients, without blocking
+func (broker *eventBroker) publish(name string, nodeId uint8, data any) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		broker.logger.Warn("failed to encode event", "event", name, "err", err)
+		return
+	}
+	event := serverEvent{name: name, nodeId: nodeId, data: raw}
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	for client := range broker.clients {
+		if client.nodeId != 0 && client.nodeId != nodeId {
+			continue
+		}
+		if name == EventTPDO && !client.tpdo {
+			continue
+		}
+		select {
+		case client.events <- event:
+		default:
+		}
+	}
+}
+
+func (broker *eventBroker) onEmergency(emcy network.Emergency) {
+	broker.publish(EventEmergency, emcy.NodeId, EmergencyEvent{
+		Node:          emcy.NodeId,
+		Time:          emcy.Time,
+		ErrorCode:     fmt.Sprintf("0x%04X", emcy.ErrorCode),
+		ErrorRegister: fmt.Sprintf("0x%02X", emcy.ErrorRegister),
+		ErrorBit:      fmt.Sprintf("0x%02X", emcy.ErrorBit),
+		InfoCode:      fmt.Sprintf("0x%08X", emcy.InfoCode),
+		Description:   emcy.Description(),
+	})
+}
+
+// Handle heartbeat & TPDO frames
+func (broker *eventBroker) Handle(frame canopen.Frame) {
+	nodeId := uint8(frame.ID & 0x7F)
+	if frame.ID&0x780 == heartbeat.ServiceId {
+		broker.handleHeartbeat(frame, nodeId)
+		return
+	}
+	broker.mu.Lock()
+	nbTPDO := broker.nbTPDO
+	broker.mu.Unlock()
+	if nbTPDO == 0 {
+		return
+	}
+	data := frame.Data[:min(frame.DLC, 8)]
+	pdoNb := uint8((frame.ID-0x180)>>8) + 1
+	broker.publish(EventTPDO, nodeId, TPDOEvent{
+		Node:   nodeId,
+		Time:   time.Now(),
+		Pdo:    pdoNb,
+		CobId:  fmt.Sprintf("0x%X", frame.ID),
+		Data:   fmt.Sprintf("%X", data),
+		Values: broker.decodeTPDO(nodeId, pdoNb, data),
+	})
+}
+
+func (broker *eventBroker) handleHeartbeat(frame canopen.Frame, nodeId uint8) {
+	if frame.DLC != 1 {
+		return
+	}
+	state := frame.Data[0] & 0x7F
+	broker.mu.Lock()
+	previous, seen := broker.states[nodeId]
+	broker.states[nodeId] = state
+	broker.mu.Unlock()
+	if seen && previous == state {
+		return
+	}
+	event := HeartbeatEvent{Node: nodeId, Time: time.Now(), State: nmt.StateDescription(state)}
+	if seen {
+		event.Previous = nmt.StateDescription(previous)
+	}
+	broker.publish(EventHeartbeat, nodeId, event)
+}
+
+// Decode TPDO content using the mapping parameters of the node OD.
+// Returns nil if the OD is unknown or the mapping cannot be decoded.
+func (broker *eventBroker) decodeTPDO(nodeId uint8, pdoNb uint8, data []byte) []TPDOValue {
+	odict, err := broker.network.GetOD(nodeId)
+	if err != nil {
+		return nil
+	}
+	mapping := odict.Index(od.TPDOMappingParamIndex(uint16(pdoNb)))
+	if mapping == nil {
+		return nil
+	}
+	nbMapped, err := mapping.Uint8(0)
+	if err != nil {
+		return nil
+	}
+	values := []TPDOValue{}
+	offset := 0
+	for i := uint8(1); i <= nbMapped; i++ {
+		param, err := mapping.Uint32(i)
+		if err != nil {
+			return nil
+		}
+		index := uint16(param >> 16)
+		subindex := uint8(param >> 8)
+		nbBits := int(param & 0xFF)
+		if nbBits%8 != 0 || offset+nbBits/8 > len(data) {
+			return nil
+		}
+		raw := data[offset : offset+nbBits/8]
+		offset += nbBits / 8
+		value := fmt.Sprintf("0x%X", raw)
+		entry := odict.Index(index)
+		if entry != nil {
+			variable, err := entry.SubIndex(int(subindex))
+			if err == nil {
+				decoded, err := od.DecodeToString(raw, variable.DataType, 10)
+				if err == nil {
+					value = decoded
+				}
+			}
+		}
+		values = append(values, TPDOValue{
+			Index:    fmt.Sprintf("0x%04X", index),
+			Subindex: fmt.Sprintf("0x%02X", subindex),
+			Value:    value,
+		})
+	}
+	return values
+}
+
+// Server-sent events (SSE) stream of network events, not part of CiA 309-5.
+// Query parameters :
+//   - node : only send events of this node id (default all nodes)
+//   - tpdo : set to true to also receive TPDOs
+func (g *GatewayServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	query := r.URL.Query()
+	nodeId := uint64(0)
+	if raw := query.Get("node"); raw != "" {
+		var err error
+		nodeId, err = strconv.ParseUint(raw, 0, 8)
+		if err != nil || nodeId < 1 || nodeId > 127 {
+			http.Error(w, "invalid node id", http.StatusBadRequest)
+			return
+		}
+	}
+	tpdo, _ := strconv.ParseBool(query.Get("tpdo"))
+	if !g.authorizeEndpoint(w, r, OperationEvents, uint8(nodeId)) {
+		return
+	}
+	err := g.events.start()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	client := g.events.subscribe(uint8(nodeId), tpdo, DefaultEventsBufferSize)
+	defer g.events.unsubscribe(client)
+	g.logger.Debug("events client connected", "client", clientId(r), "node", nodeId, "tpdo", tpdo)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(DefaultEventsKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			g.logger.Debug("events client disconnected", "client", clientId(r))
+			return
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case event := <-client.events:
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.name, event.data)
+		}
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvents(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	_, err = net.AddRemoteNode(0x21, od.Default())
+	assert.Nil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw.serveMux)
+	defer ts.Close()
+
+	send := func(id uint32, data ...byte) {
+		frame := canopen.NewFrame(id, 0, uint8(len(data)))
+		copy(frame.Data[:], data)
+		assert.Nil(t, net.Send(frame))
+	}
+
+	// Returns a function reading the next event name & data
+	connect := func(query string) (*http.Response, func() (string, string)) {
+		resp, err := http.Get(ts.URL + "/events" + query)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		events := make(chan [2]string, 10)
+		go func() {
+			scanner := bufio.NewScanner(resp.Body)
+			name := ""
+			for scanner.Scan() {
+				line := scanner.Text()
+				if after, ok := strings.CutPrefix(line, "event: "); ok {
+					name = after
+				} else if after, ok := strings.CutPrefix(line, "data: "); ok {
+					events <- [2]string{name, after}
+				}
+			}
+		}()
+		next := func() (string, string) {
+			select {
+			case event := <-events:
+				return event[0], event[1]
+			case <-time.After(2 * time.Second):
+				t.Fatal("timeout waiting for event")
+				return "", ""
+			}
+		}
+		return resp, next
+	}
+
+	t.Run("heartbeat and emcy", func(t *testing.T) {
+		resp, next := connect("?node=0x20")
+		defer resp.Body.Close()
+		send(0x720, 0x7F)
+		send(0x720, 0x7F) // No change, no event
+		send(0x720, 0x05)
+		send(0x71F, 0x05) // Filtered out
+		send(0xA0, 0x10, 0x81, 0x11, 0x00, 0x01, 0x02, 0x03, 0x04)
+		send(0x1A0, 0x01) // TPDOs not requested
+
+		hb := HeartbeatEvent{}
+		name, data := next()
+		assert.Equal(t, EventHeartbeat, name)
+		assert.Nil(t, json.Unmarshal([]byte(data), &hb))
+		assert.EqualValues(t, 0x20, hb.Node)
+		assert.Equal(t, "PRE-OPERATIONAL", hb.State)
+		assert.Equal(t, "", hb.Previous)
+
+		name, data = next()
+		assert.Equal(t, EventHeartbeat, name)
+		assert.Nil(t, json.Unmarshal([]byte(data), &hb))
+		assert.Equal(t, "OPERATIONAL", hb.State)
+		assert.Equal(t, "PRE-OPERATIONAL", hb.Previous)
+
+		emcy := EmergencyEvent{}
+		name, data = next()
+		assert.Equal(t, EventEmergency, name)
+		assert.Nil(t, json.Unmarshal([]byte(data), &emcy))
+		assert.EqualValues(t, 0x20, emcy.Node)
+		assert.Equal(t, "0x8110", emcy.ErrorCode)
+		assert.Equal(t, "0x11", emcy.ErrorRegister)
+		assert.Equal(t, "0x04030201", emcy.InfoCode)
+	})
+
+	t.Run("tpdo", func(t *testing.T) {
+		resp, next := connect("?node=0x21&tpdo=true")
+		defer resp.Body.Close()
+		// 0x1A00 of default OD maps 0x2002 (INTEGER8)
+		send(0x1A1, 0x05)
+		tpdo := TPDOEvent{}
+		name, data := next()
+		assert.Equal(t, EventTPDO, name)
+		assert.Nil(t, json.Unmarshal([]byte(data), &tpdo))
+		assert.EqualValues(t, 0x21, tpdo.Node)
+		assert.EqualValues(t, 1, tpdo.Pdo)
+		assert.Equal(t, "0x1A1", tpdo.CobId)
+		assert.Equal(t, "05", tpdo.Data)
+		assert.Equal(t, []TPDOValue{{Index: "0x2002", Subindex: "0x00", Value: "5"}}, tpdo.Values)
+	})
+
+	t.Run("invalid node", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/events?node=200")
+		assert.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
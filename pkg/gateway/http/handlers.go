@@ -11,25 +11,27 @@ import (
 
 	"github.com/samsamfire/gocanopen/pkg/gateway"
 	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
 )
 
 // Wrapper around [http.ResponseWriter] but keeps track of any writes already done
 // This allows us to perform default behaviour if handler has not already sent a response
+// done is shared between copies, as handlers receive the writer by value
 type doneWriter struct {
 	http.ResponseWriter
-	done bool
+	done *bool
 }
 
 // Handle a [GatewayRequest] according to CiA 309-5
 type GatewayRequestHandler func(w doneWriter, req *GatewayRequest) error
 
 func (w *doneWriter) WriteHeader(status int) {
-	w.done = true
+	*w.done = true
 	w.ResponseWriter.WriteHeader(status)
 }
 
 func (w *doneWriter) Write(b []byte) (int, error) {
-	w.done = true
+	*w.done = true
 	return w.ResponseWriter.Write(b)
 }
 
@@ -63,21 +65,27 @@ func (g *GatewayServer) newRequestFromRaw(r *http.Request) (*GatewayRequest, err
 	nodeInt, err := parseNodeOrNetworkParam(nodeStr)
 	if err != nil || nodeInt == 0 || nodeInt > 127 {
 		g.logger.Error("error processing node param", "param", nodeStr)
+		return nil, ErrGwUnsupportedNode
 	}
 
-	// Unmarshall request body
-	var parameters json.RawMessage
-	err = json.NewDecoder(r.Body).Decode(&parameters)
-	if err != nil && err != io.EOF {
-		g.logger.Warn("failed to unmarshal request body", "err", err)
-		return nil, ErrGwSyntaxError
-	}
 	request := &GatewayRequest{
 		nodeId:     nodeInt,
 		networkId:  netInt,
 		command:    match[5], // Contains rest of URL after node
 		sequence:   uint32(sequence),
-		parameters: parameters,
+		jsonValues: wantsJSONValues(r),
+	}
+	// Streams have a raw body, which is processed by the handler
+	if strings.HasPrefix(request.command, "stream/") {
+		request.body = r.Body
+		request.size = r.ContentLength
+		return request, nil
+	}
+	// Unmarshall request body
+	err = json.NewDecoder(r.Body).Decode(&request.parameters)
+	if err != nil && err != io.EOF {
+		g.logger.Warn("failed to unmarshal request body", "err", err)
+		return nil, ErrGwSyntaxError
 	}
 	return request, nil
 }
@@ -91,55 +99,145 @@ func (g *GatewayServer) handleRequest(w http.ResponseWriter, raw *http.Request)
 		w.Write(NewResponseError(0, err))
 		return
 	}
-	// An api command (URI) is in the form /command/sub-command/... etc...
-	// and can have variable parameters such as indexes as well as a body.
-	// We first check inside a map that the full command is present inside of a handler map.
-	// If full command is not found we then check again
-	// but with truncated command up to the first "/".
-	// e.g. '/reset/node' exists and is handled straight away
-	// '/read/0x2000/0x0' does not exist in map, so we then check 'read' which does exist
-	var route GatewayRequestHandler
-	route, ok := g.routes[req.command]
-	if !ok {
-		indexFirstSep := strings.Index(req.command, "/")
-		var firstCommand string
-		if indexFirstSep != -1 {
-			firstCommand = req.command[:indexFirstSep]
-		} else {
-			firstCommand = req.command
-		}
-		route, ok = g.routes[firstCommand]
-		if !ok {
-			g.logger.Debug("no handler found", "command", req.command, "firstCommand", firstCommand)
-			w.Write(NewResponseError(int(req.sequence), ErrGwRequestNotSupported))
+	req.client = clientId(raw)
+	req.ctx = raw.Context()
+	if !g.authenticate(w, raw, req) {
+		return
+	}
+	if g.quotas != nil {
+		err := g.quotas.acquire(req.client)
+		defer g.quotas.release(req.client)
+		if !g.checkQuota(w, req, err) {
 			return
 		}
 	}
+	route, ok := g.findRoute(req.command)
+	if !ok {
+		g.logger.Debug("no handler found", "command", req.command)
+		w.Write(NewResponseError(int(req.sequence), ErrGwRequestNotSupported))
+		return
+	}
+	// SDO accesses are authorized once index & subindex are known,
+	// batches are authorized for every SDO access they contain
+	operation := commandOperation(req.command)
+	if operation != OperationRead && operation != OperationWrite && req.command != "batch" && !g.authorize(w, req, 0, 0) {
+		return
+	}
 	// Process the actual command
-	dw := doneWriter{ResponseWriter: w, done: false}
+	dw := doneWriter{ResponseWriter: w, done: new(bool)}
 	err = route(dw, req)
 	if err != nil {
 		w.Write(NewResponseError(int(req.sequence), err))
 		return
 	}
-	if !dw.done {
+	if !*dw.done {
 		// No response specific command has been given, reply with default success
 		dw.Write(NewResponseSuccess(int(req.sequence)))
 		return
 	}
 }
 
-// Create a handler for processing NMT request
+// Find the handler of a command.
+// An api command (URI) is in the form /command/sub-command/... etc...
+// and can have variable parameters such as indexes as well as a body.
+// We first check inside a map that the full command is present inside of a handler map.
+// If full command is not found we then check again with the command truncated
+// up to the last "/", until a handler is found (longest prefix).
+// e.g. '/reset/node' exists and is handled straight away
+// '/read/0x2000/0x0' does not exist in map, nor does 'read/0x2000', so we then check 'read' which does exist
+// '/set/rpdo/1' is handled by 'set/rpdo'
+func (g *GatewayServer) findRoute(command string) (GatewayRequestHandler, bool) {
+	route, ok := g.routes[command]
+	prefix := command
+	for !ok {
+		indexLastSep := strings.LastIndex(prefix, "/")
+		if indexLastSep == -1 {
+			return nil, false
+		}
+		prefix = prefix[:indexLastSep]
+		route, ok = g.routes[prefix]
+	}
+	return route, true
+}
+
+// Get node targeted by request, resolving "default" & "none" to the default node.
+// If broadcast is true, "all" is allowed and returns 0.
+func targetNode(bg *gateway.BaseGateway, req *GatewayRequest, broadcast bool) (uint8, error) {
+	switch req.nodeId {
+	case TOKEN_DEFAULT, TOKEN_NONE:
+		if bg.DefaultNodeId() == 0 {
+			return 0, ErrGwNoDefaultNodeSet
+		}
+		return bg.DefaultNodeId(), nil
+	case TOKEN_ALL:
+		if !broadcast {
+			return 0, ErrGwUnsupportedNode
+		}
+		return 0, nil
+	default:
+		return uint8(req.nodeId), nil
+	}
+}
+
+// Create a handler for processing NMT request, for a single node or broadcast
 func createNmtHandler(bg *gateway.BaseGateway, command nmt.Command) GatewayRequestHandler {
 	return func(w doneWriter, req *GatewayRequest) error {
-		switch req.nodeId {
-		case TOKEN_DEFAULT, TOKEN_NONE:
-			return bg.NMTCommand(bg.DefaultNodeId(), command)
-		case TOKEN_ALL:
-			return bg.NMTCommand(0, command)
-		default:
-			return bg.NMTCommand(uint8(req.nodeId), command)
+		nodeId, err := targetNode(bg, req, true)
+		if err != nil {
+			return err
+		}
+		return bg.NMTCommand(nodeId, command)
+	}
+}
+
+// Create a handler for enabling or disabling heartbeat production of a node
+// Period is given in ms inside of the body when enabling
+func (g *GatewayServer) createHeartbeatHandler(enable bool) GatewayRequestHandler {
+	return func(w doneWriter, req *GatewayRequest) error {
+		nodeId, err := targetNode(g.BaseGateway, req, false)
+		if err != nil {
+			return err
+		}
+		period := uint64(0)
+		if enable {
+			var heartbeat HeartbeatRequest
+			err := json.Unmarshal(req.parameters, &heartbeat)
+			if err != nil {
+				return ErrGwSyntaxError
+			}
+			period, err = strconv.ParseUint(heartbeat.Value, 0, 16)
+			if err != nil || period == 0 {
+				return ErrGwSyntaxError
+			}
+		}
+		return g.SetHeartbeatPeriod(nodeId, uint16(period))
+	}
+}
+
+func (g *GatewayServer) createGuardingHandler(enable bool) GatewayRequestHandler {
+	return func(w doneWriter, req *GatewayRequest) error {
+		nodeId, err := targetNode(g.BaseGateway, req, false)
+		if err != nil {
+			return err
+		}
+		if !enable {
+			g.StopNodeGuarding(nodeId)
+			return nil
 		}
+		var guarding GuardingRequest
+		err = json.Unmarshal(req.parameters, &guarding)
+		if err != nil {
+			return ErrGwSyntaxError
+		}
+		guardTime, err := strconv.ParseUint(guarding.GuardTime, 0, 16)
+		if err != nil || guardTime == 0 {
+			return ErrGwSyntaxError
+		}
+		lifeTimeFactor, err := strconv.ParseUint(guarding.LifeTimeFactor, 0, 8)
+		if err != nil || lifeTimeFactor == 0 {
+			return ErrGwSyntaxError
+		}
+		return g.StartNodeGuarding(nodeId, uint16(guardTime), uint8(lifeTimeFactor))
 	}
 }
 
@@ -154,7 +252,7 @@ func handlerNotSupported(w doneWriter, req *GatewayRequest) error {
 func (g *GatewayServer) handlerRead(w doneWriter, req *GatewayRequest) error {
 	matchSDO := regSDO.FindStringSubmatch(req.command)
 	if len(matchSDO) >= 2 {
-		return g.handlerSDORead(w, req, matchSDO)
+		return g.handlerSDORead(w, req, matchSDO, false)
 	}
 	matchPDO := regPDO.FindStringSubmatch(req.command)
 	if len(matchPDO) >= 2 {
@@ -163,18 +261,54 @@ func (g *GatewayServer) handlerRead(w doneWriter, req *GatewayRequest) error {
 	return ErrGwSyntaxError
 }
 
-func (g *GatewayServer) handlerSDORead(w doneWriter, req *GatewayRequest, commands []string) error {
+// Handle an SDO block read or write
+func (g *GatewayServer) handleSDOBlock(w doneWriter, req *GatewayRequest) error {
+	matchSDO := regSDOBlock.FindStringSubmatch(req.command)
+	if len(matchSDO) < 2 {
+		return ErrGwSyntaxError
+	}
+	switch matchSDO[1] {
+	case "rb", "read-block":
+		return g.handlerSDORead(w, req, matchSDO, true)
+	default:
+		return g.handlerSDOWrite(w, req, matchSDO, true)
+	}
+}
+
+func (g *GatewayServer) handlerSDORead(w doneWriter, req *GatewayRequest, commands []string, block bool) error {
+	if req.jsonValues {
+		return g.handlerSDOReadValue(w, req, commands, block)
+	}
 	index, subindex, err := parseSdoCommand(commands[1:])
 	if err != nil {
 		g.logger.Error("unable to parse SDO command", "err", err)
 		return err
 	}
+	nodeId, err := targetNode(g.BaseGateway, req, false)
+	if err != nil {
+		return err
+	}
+	if !g.authorize(&w, req, uint16(index), uint8(subindex)) {
+		return nil
+	}
 
-	n, err := g.ReadSDO(uint8(req.nodeId), uint16(index), uint8(subindex))
+	if g.quotas != nil && !g.checkQuota(&w, req, g.quotas.sdo(req.client)) {
+		return nil
+	}
+	var n int
+	if block {
+		n, err = g.ReadSDOBlock(nodeId, uint16(index), uint8(subindex))
+	} else {
+		n, err = g.ReadSDO(nodeId, uint16(index), uint8(subindex))
+	}
 	if err != nil {
 		w.Write(NewResponseError(int(req.sequence), err))
 		return nil
 	}
+	// Transfer has already been done, but don't send back data
+	if g.quotas != nil && !g.checkQuota(&w, req, g.quotas.transfer(n)) {
+		return nil
+	}
 	buf := g.Buffer()[:n]
 	slices.Reverse(buf)
 	resp := SDOReadResponse{
@@ -195,7 +329,7 @@ func (g *GatewayServer) handlerSDORead(w doneWriter, req *GatewayRequest, comman
 func (g *GatewayServer) handleWrite(w doneWriter, req *GatewayRequest) error {
 	matchSDO := regSDO.FindStringSubmatch(req.command)
 	if len(matchSDO) >= 2 {
-		return g.handlerSDOWrite(w, req, matchSDO)
+		return g.handlerSDOWrite(w, req, matchSDO, false)
 	}
 	matchPDO := regPDO.FindStringSubmatch(req.command)
 	if len(matchPDO) >= 2 {
@@ -204,12 +338,22 @@ func (g *GatewayServer) handleWrite(w doneWriter, req *GatewayRequest) error {
 	return ErrGwSyntaxError
 }
 
-func (g *GatewayServer) handlerSDOWrite(w doneWriter, req *GatewayRequest, commands []string) error {
+func (g *GatewayServer) handlerSDOWrite(w doneWriter, req *GatewayRequest, commands []string, block bool) error {
+	if req.jsonValues {
+		return g.handlerSDOWriteValue(w, req, commands, block)
+	}
 	index, subindex, err := parseSdoCommand(commands[1:])
 	if err != nil {
 		g.logger.Error("unable to parse SDO command", "err", err)
 		return err
 	}
+	nodeId, err := targetNode(g.BaseGateway, req, false)
+	if err != nil {
+		return err
+	}
+	if !g.authorize(&w, req, uint16(index), uint8(subindex)) {
+		return nil
+	}
 
 	var sdoWrite SDOWriteRequest
 	err = json.Unmarshal(req.parameters, &sdoWrite)
@@ -221,7 +365,20 @@ func (g *GatewayServer) handlerSDOWrite(w doneWriter, req *GatewayRequest, comma
 		g.logger.Error("requested datatype is wrong or unsupported", "dataType", sdoWrite.Datatype)
 		return ErrGwRequestNotSupported
 	}
-	err = g.WriteSDO(uint8(req.nodeId), uint16(index), uint8(subindex), sdoWrite.Value, datatype)
+	if g.quotas != nil {
+		encoded, err := od.EncodeFromString(sdoWrite.Value, datatype, 0)
+		if err == nil && !g.checkQuota(&w, req, g.quotas.transfer(len(encoded))) {
+			return nil
+		}
+		if !g.checkQuota(&w, req, g.quotas.sdo(req.client)) {
+			return nil
+		}
+	}
+	if block {
+		err = g.WriteSDOBlock(nodeId, uint16(index), uint8(subindex), sdoWrite.Value, datatype)
+	} else {
+		err = g.WriteSDO(nodeId, uint16(index), uint8(subindex), sdoWrite.Value, datatype)
+	}
 	if err != nil {
 		w.Write(NewResponseError(int(req.sequence), err))
 		return nil
@@ -284,9 +441,12 @@ func (g *GatewayServer) handleSetDefaultNode(w doneWriter, req *GatewayRequest)
 		return ErrGwSyntaxError
 	}
 	nodeId, err := strconv.ParseUint(defaultNode.Value, 0, 64)
-	if err != nil || nodeId > 0xFF || nodeId == 0 {
+	if err != nil {
 		return ErrGwSyntaxError
 	}
+	if nodeId == 0 || nodeId > 127 {
+		return ErrGwUnsupportedNode
+	}
 	g.SetDefaultNodeId(uint8(nodeId))
 	respRaw := NewResponseSuccess(int(req.sequence))
 	w.Write(respRaw)
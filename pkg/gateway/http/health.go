@@ -0,0 +1,79 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultHealthMaxAge  = 1 * time.Second
+	DefaultHealthBacklog = 100
+)
+
+type HealthNode struct {
+	Id             uint8     `json:"id"`
+	Healthy        bool      `json:"healthy"`
+	Running        bool      `json:"running"`
+	LastMain       time.Time `json:"last_main"`
+	LastBackground time.Time `json:"last_background"`
+	SDOBacklog     int       `json:"sdo_backlog"`
+}
+
+// Response of the /healthz and /readyz endpoints
+type HealthResponse struct {
+	Status        string       `json:"status"` // "ok" or "unavailable"
+	BusConnected  bool         `json:"bus_connected"`
+	LastHeartbeat *time.Time   `json:"last_heartbeat,omitempty"`
+	Nodes         []HealthNode `json:"nodes,omitempty"`
+}
+
+// Set the thresholds used by readiness endpoint.
+// maxAge is the maximum time since last node processing and
+// maxBacklog the maximum number of pending SDO frames per node
+func (g *GatewayServer) SetHealthThresholds(maxAge time.Duration, maxBacklog int) {
+	g.healthMaxAge = maxAge
+	g.healthMaxBacklog = maxBacklog
+}
+
+func writeHealth(w http.ResponseWriter, resp HealthResponse, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	resp.Status = "ok"
+	if !ok {
+		resp.Status = "unavailable"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Liveness : gateway is serving requests and its heartbeat, if started, is running
+func (g *GatewayServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	resp := HealthResponse{BusConnected: g.Network().Connected()}
+	ok := true
+	last, period := g.Heartbeat()
+	if period != 0 {
+		resp.LastHeartbeat = &last
+		ok = time.Since(last) <= 3*period
+	}
+	writeHealth(w, resp, ok)
+}
+
+// Readiness : bus is connected, all nodes are processing and backlogs are within limits
+func (g *GatewayServer) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	network := g.Network()
+	resp := HealthResponse{BusConnected: network.Connected()}
+	ok := resp.BusConnected
+	for _, health := range network.NodesHealth() {
+		node := HealthNode{
+			Id:             health.Id,
+			Healthy:        health.Healthy(g.healthMaxAge) && health.SDOBacklog <= g.healthMaxBacklog,
+			Running:        health.Running,
+			LastMain:       health.LastMain,
+			LastBackground: health.LastBackground,
+			SDOBacklog:     health.SDOBacklog,
+		}
+		ok = ok && node.Healthy
+		resp.Nodes = append(resp.Nodes, node)
+	}
+	writeHealth(w, resp, ok)
+}
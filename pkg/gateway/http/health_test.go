@@ -0,0 +1,60 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	_, err = net.CreateLocalNode(0x66, od.Default())
+	assert.Nil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw.serveMux)
+	defer ts.Close()
+
+	get := func(endpoint string) (int, HealthResponse) {
+		resp, err := http.Get(ts.URL + endpoint)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		health := HealthResponse{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&health))
+		return resp.StatusCode, health
+	}
+
+	t.Run("liveness", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		gw.StartHeartbeat(ctx, 0x7F, 50*time.Millisecond)
+		time.Sleep(100 * time.Millisecond)
+		status, health := get("/healthz")
+		assert.Equal(t, http.StatusOK, status)
+		assert.NotNil(t, health.LastHeartbeat)
+		cancel()
+	})
+	t.Run("readiness", func(t *testing.T) {
+		time.Sleep(100 * time.Millisecond)
+		status, health := get("/readyz")
+		assert.Equal(t, http.StatusOK, status)
+		assert.True(t, health.BusConnected)
+		assert.Len(t, health.Nodes, 1)
+		// Stop processing, gateway should not be ready anymore
+		net.Disconnect()
+		status, health = get("/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.False(t, health.BusConnected)
+	})
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/lss"
+)
+
+// LSS commands are sent to the whole network, node parameter is ignored.
+// Selection of a single slave is done with lss/switch/sel.
+
+// Get LSS master or error if not available
+func (g *GatewayServer) lssMaster() (*lss.LSSMaster, error) {
+	master := g.LSSMaster()
+	if master == nil {
+		return nil, ErrGwRequestNotProcessed
+	}
+	return master, nil
+}
+
+// Parse a single value request
+func parseLSSValue(req *GatewayRequest, bitSize int) (uint64, error) {
+	var lssValue LSSValueRequest
+	err := json.Unmarshal(req.parameters, &lssValue)
+	if err != nil {
+		return 0, ErrGwSyntaxError
+	}
+	value, err := strconv.ParseUint(lssValue.Value, 0, bitSize)
+	if err != nil {
+		return 0, ErrGwSyntaxError
+	}
+	return value, nil
+}
+
+// Switch all slaves to waiting (0) or configuration (1) state
+func (g *GatewayServer) handleLSSSwitchGlobal(w doneWriter, req *GatewayRequest) error {
+	master, err := g.lssMaster()
+	if err != nil {
+		return err
+	}
+	state, err := parseLSSValue(req, 8)
+	if err != nil || (uint8(state) != lss.StateWaiting && uint8(state) != lss.StateConfiguration) {
+		return ErrGwSyntaxError
+	}
+	return master.SwitchStateGlobal(uint8(state))
+}
+
+// Switch a single slave to configuration state
+func (g *GatewayServer) handleLSSSwitchSelective(w doneWriter, req *GatewayRequest) error {
+	master, err := g.lssMaster()
+	if err != nil {
+		return err
+	}
+	var address LSSAddress
+	err = json.Unmarshal(req.parameters, &address)
+	if err != nil {
+		return ErrGwSyntaxError
+	}
+	fields := []string{address.VendorId, address.ProductCode, address.RevisionNumber, address.SerialNumber}
+	values := [4]uint32{}
+	for i, field := range fields {
+		value, err := strconv.ParseUint(field, 0, 32)
+		if err != nil {
+			return ErrGwSyntaxError
+		}
+		values[i] = uint32(value)
+	}
+	return master.SwitchStateSelective(lss.Address{
+		VendorId:       values[0],
+		ProductCode:    values[1],
+		RevisionNumber: values[2],
+		SerialNumber:   values[3],
+	})
+}
+
+// Configure node-id of slave in configuration state
+func (g *GatewayServer) handleLSSSetNode(w doneWriter, req *GatewayRequest) error {
+	master, err := g.lssMaster()
+	if err != nil {
+		return err
+	}
+	nodeId, err := parseLSSValue(req, 8)
+	if err != nil {
+		return err
+	}
+	return master.ConfigureNodeId(uint8(nodeId))
+}
+
+// Configure bit timing of slave in configuration state.
+// Only the CiA 305 standard table (selector 0) is supported.
+func (g *GatewayServer) handleLSSConfigureBitrate(w doneWriter, req *GatewayRequest) error {
+	master, err := g.lssMaster()
+	if err != nil {
+		return err
+	}
+	var conf LSSConfigureBitrateRequest
+	err = json.Unmarshal(req.parameters, &conf)
+	if err != nil {
+		return ErrGwSyntaxError
+	}
+	selector, err := strconv.ParseUint(conf.TableSelector, 0, 8)
+	if err != nil {
+		return ErrGwSyntaxError
+	}
+	index, err := strconv.ParseUint(conf.TableIndex, 0, 8)
+	if err != nil {
+		return ErrGwSyntaxError
+	}
+	if selector != 0 {
+		return ErrGwLSSBitRateNotSupported
+	}
+	for bitrate, tableIndex := range lss.BitrateTable {
+		if uint64(tableIndex) == index {
+			return master.ConfigureBitTiming(bitrate)
+		}
+	}
+	return ErrGwLSSBitRateNotSupported
+}
+
+// Activate bit timing, switch delay is given in ms
+func (g *GatewayServer) handleLSSActivateBitrate(w doneWriter, req *GatewayRequest) error {
+	master, err := g.lssMaster()
+	if err != nil {
+		return err
+	}
+	delay, err := parseLSSValue(req, 16)
+	if err != nil {
+		return err
+	}
+	return master.ActivateBitTiming(time.Duration(delay) * time.Millisecond)
+}
+
+// Store configuration of slave in configuration state
+func (g *GatewayServer) handleLSSStore(w doneWriter, req *GatewayRequest) error {
+	master, err := g.lssMaster()
+	if err != nil {
+		return err
+	}
+	return master.StoreConfiguration()
+}
+
+// Inquire node-id of slave in configuration state
+func (g *GatewayServer) handleLSSGetNode(w doneWriter, req *GatewayRequest) error {
+	master, err := g.lssMaster()
+	if err != nil {
+		return err
+	}
+	nodeId, err := master.InquireNodeId()
+	if err != nil {
+		return err
+	}
+	resp := LSSGetNodeResponse{
+		GatewayResponseBase: NewResponseBase(int(req.sequence), "OK"),
+		Value:               fmt.Sprintf("0x%x", nodeId),
+	}
+	respRaw, err := json.Marshal(resp)
+	if err != nil {
+		return ErrGwRequestNotProcessed
+	}
+	w.Write(respRaw)
+	return nil
+}
+
+// Inquire LSS address of slave in configuration state
+func (g *GatewayServer) handleLSSInquireAddress(w doneWriter, req *GatewayRequest) error {
+	master, err := g.lssMaster()
+	if err != nil {
+		return err
+	}
+	address, err := master.InquireAddress()
+	if err != nil {
+		return err
+	}
+	resp := LSSInquireAddressResponse{
+		GatewayResponseBase: NewResponseBase(int(req.sequence), "OK"),
+		LSSAddress: &LSSAddress{
+			VendorId:       fmt.Sprintf("0x%x", address.VendorId),
+			ProductCode:    fmt.Sprintf("0x%x", address.ProductCode),
+			RevisionNumber: fmt.Sprintf("0x%x", address.RevisionNumber),
+			SerialNumber:   fmt.Sprintf("0x%x", address.SerialNumber),
+		},
+	}
+	respRaw, err := json.Marshal(resp)
+	if err != nil {
+		return ErrGwRequestNotProcessed
+	}
+	w.Write(respRaw)
+	return nil
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samsamfire/gocanopen/pkg/network"
+)
+
+// Write a single sample in Prometheus text format
+func writeMetric(w io.Writer, name string, labels string, value uint32) {
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s%s %d\n", name, labels, value)
+}
+
+// Write metric help & type, samples are written by fn
+func writeMetricFamily(w io.Writer, name string, help string, fn func(name string)) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	fn(name)
+}
+
+// Write a gauge with a single sample
+func writeGauge(w io.Writer, name string, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// Write network counters in Prometheus text format, see [network.Network.Stats]
+func writeMetrics(w io.Writer, stats network.Stats) {
+	ids := make([]int, 0, len(stats.Nodes))
+	for id := range stats.Nodes {
+		ids = append(ids, int(id))
+	}
+	slices.Sort(ids)
+	perNode := func(value func(node *network.NodeStats) (uint32, bool)) func(name string) {
+		return func(name string) {
+			for _, id := range ids {
+				if v, ok := value(stats.Nodes[uint8(id)]); ok {
+					writeMetric(w, name, fmt.Sprintf(`node="%d"`, id), v)
+				}
+			}
+		}
+	}
+	single := func(value uint32) func(name string) {
+		return func(name string) { writeMetric(w, name, "", value) }
+	}
+
+	writeMetricFamily(w, "canopen_bus_frames_received_total", "CAN frames received.", single(stats.Bus.FramesReceived))
+	writeMetricFamily(w, "canopen_bus_frames_sent_total", "CAN frames sent.", single(stats.Bus.FramesSent))
+	writeMetricFamily(w, "canopen_bus_send_errors_total", "CAN frames that could not be sent.", single(stats.Bus.SendErrors))
+	writeGauge(w, "canopen_bus_load_frames_per_second", "CAN frames per second sent & received.", stats.Load.FramesPerSecond)
+	writeGauge(w, "canopen_bus_load_bits_per_second", "CAN bits per second sent & received.", stats.Load.BitsPerSecond)
+	writeGauge(w, "canopen_bus_load_percent", "CAN bus load in percent of the bitrate, 0 if unknown.", stats.Load.Percent)
+
+	writeMetricFamily(w, "canopen_sdo_transfers_total", "SDO transfers made by the network.", func(name string) {
+		writeMetric(w, name, `direction="upload"`, stats.SDO.Uploads)
+		writeMetric(w, name, `direction="download"`, stats.SDO.Downloads)
+	})
+	writeMetricFamily(w, "canopen_sdo_bytes_total", "Bytes transferred with SDO.", func(name string) {
+		writeMetric(w, name, `direction="upload"`, stats.SDO.BytesUploaded)
+		writeMetric(w, name, `direction="download"`, stats.SDO.BytesDownloaded)
+	})
+	writeMetricFamily(w, "canopen_sdo_failed_total", "SDO transfers finished with an error.", single(stats.SDO.Failed))
+	writeMetricFamily(w, "canopen_sdo_retries_total", "SDO block transfers retried.", single(stats.SDO.Retries))
+	writeMetricFamily(w, "canopen_sdo_aborts_total", "SDO transfers aborted, by abort code.", func(name string) {
+		codes := make([]string, 0, len(stats.SDO.Aborts))
+		counts := map[string]uint32{}
+		for abort, count := range stats.SDO.Aborts {
+			code := fmt.Sprintf("0x%08X", uint32(abort))
+			codes = append(codes, code)
+			counts[code] = count
+		}
+		slices.Sort(codes)
+		for _, code := range codes {
+			writeMetric(w, name, fmt.Sprintf(`code="%s"`, code), counts[code])
+		}
+	})
+	writeMetricFamily(w, "canopen_node_sdo_transfers_total", "SDO transfers made with the client dedicated to a node.",
+		perNode(func(node *network.NodeStats) (uint32, bool) {
+			return node.SDO.Uploads + node.SDO.Downloads, true
+		}))
+
+	writeMetricFamily(w, "canopen_node_main_overruns_total", "Main processing cycles longer than their period.",
+		perNode(func(node *network.NodeStats) (uint32, bool) { return node.Processor.MainOverruns, true }))
+	writeMetricFamily(w, "canopen_node_background_overruns_total", "Background processing cycles longer than their period.",
+		perNode(func(node *network.NodeStats) (uint32, bool) { return node.Processor.BackgroundOverruns, true }))
+
+	local := func(value func(d *network.NodeStats) uint32) func(name string) {
+		return perNode(func(node *network.NodeStats) (uint32, bool) {
+			if node.Diagnostics == nil {
+				return 0, false
+			}
+			return value(node), true
+		})
+	}
+	writeMetricFamily(w, "canopen_node_tpdo_sent_total", "TPDOs sent by a local node.",
+		local(func(node *network.NodeStats) uint32 { return node.Diagnostics.TPDOSent }))
+	writeMetricFamily(w, "canopen_node_tpdo_errors_total", "TPDOs of a local node that could not be sent.",
+		local(func(node *network.NodeStats) uint32 { return node.Diagnostics.TPDOErrors }))
+	writeMetricFamily(w, "canopen_node_rpdo_received_total", "RPDOs received by a local node.",
+		local(func(node *network.NodeStats) uint32 { return node.Diagnostics.RPDOReceived }))
+	writeMetricFamily(w, "canopen_node_rpdo_errors_total", "Invalid RPDOs received by a local node.",
+		local(func(node *network.NodeStats) uint32 { return node.Diagnostics.RPDOErrors }))
+	writeMetricFamily(w, "canopen_node_rpdo_timeouts_total", "RPDO timeouts of a local node.",
+		local(func(node *network.NodeStats) uint32 { return node.Diagnostics.RPDOTimeouts }))
+}
+
+// Network counters in Prometheus text format, not part of CiA 309-5
+func (g *GatewayServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if g.auth != nil && !g.auth.PublicMetrics && !g.authorizeEndpoint(w, r, OperationMetrics, 0) {
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	var b strings.Builder
+	writeMetrics(&b, g.Network().Stats())
+	_, _ = io.WriteString(w, b.String())
+}
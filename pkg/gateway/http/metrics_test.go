@@ -0,0 +1,48 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	_, err = net.CreateLocalNode(0x67, od.Default())
+	assert.Nil(t, err)
+	_, err = net.ReadAll(0x67, 0x1018, 1)
+	assert.Nil(t, err)
+	_, err = net.ReadAll(0x67, 0x1234, 0)
+	assert.NotNil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw.serveMux)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	raw, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	metrics := string(raw)
+	assert.Contains(t, metrics, "# TYPE canopen_sdo_transfers_total counter\n")
+	assert.Contains(t, metrics, "canopen_sdo_transfers_total{direction=\"upload\"} 2\n")
+	assert.Contains(t, metrics, "canopen_sdo_bytes_total{direction=\"upload\"} 4\n")
+	assert.Contains(t, metrics, "canopen_sdo_aborts_total{code=\"0x06020000\"} 1\n")
+	assert.Contains(t, metrics, "canopen_node_tpdo_sent_total{node=\"103\"}")
+	assert.Contains(t, metrics, "canopen_bus_frames_sent_total ")
+	assert.Contains(t, metrics, "# TYPE canopen_bus_load_bits_per_second gauge\n")
+}
@@ -0,0 +1,278 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// A gateway operation described in the OpenAPI document.
+// Request & response are Go values whose types are converted to JSON schemas.
+type apiOperation struct {
+	route    string   // Registered route, see [GatewayServer.addRoute]
+	aliases  []string // Other routes handled identically
+	path     string   // Path after the node, may contain parameters e.g. {index}
+	method   string
+	summary  string
+	request  any // nil if no body
+	response any // nil for the default response
+	stream   bool
+	values   bool // Supports JSON values, see [ValueMediaType]
+}
+
+// Operations of the CiA 309-5 endpoint, every registered route should be listed
+var apiOperations = []apiOperation{
+	{route: "r", values: true, aliases: []string{"read"}, path: "r/{index}/{subindex}", method: http.MethodGet,
+		summary: "Read an object via SDO", response: SDOReadResponse{}},
+	{route: "w", values: true, aliases: []string{"write"}, path: "w/{index}/{subindex}", method: http.MethodPut,
+		summary: "Write an object via SDO", request: SDOWriteRequest{}},
+	{route: "rb", values: true, aliases: []string{"read-block"}, path: "rb/{index}/{subindex}", method: http.MethodGet,
+		summary: "Read an object via SDO block transfer", response: SDOReadResponse{}},
+	{route: "wb", values: true, aliases: []string{"write-block"}, path: "wb/{index}/{subindex}", method: http.MethodPut,
+		summary: "Write an object via SDO block transfer", request: SDOWriteRequest{}},
+	{route: "set/sdo-timeout", path: "set/sdo-timeout", method: http.MethodPut,
+		summary: "Set SDO timeout in ms", request: SDOSetTimeoutRequest{}},
+	{route: "set/rpdo", path: "set/rpdo/{pdo}", method: http.MethodPut,
+		summary: "Configure an RPDO (1-256)", request: PDOSetRequest{}},
+	{route: "set/tpdo", path: "set/tpdo/{pdo}", method: http.MethodPut,
+		summary: "Configure a TPDO (1-256)", request: PDOSetRequest{}},
+	{route: "start", path: "start", method: http.MethodPut, summary: "NMT start remote node"},
+	{route: "stop", path: "stop", method: http.MethodPut, summary: "NMT stop remote node"},
+	{route: "preop", aliases: []string{"preoperational"}, path: "preop", method: http.MethodPut,
+		summary: "NMT enter pre-operational"},
+	{route: "reset/node", path: "reset/node", method: http.MethodPut, summary: "NMT reset node"},
+	{route: "reset/comm", aliases: []string{"reset/communication"}, path: "reset/comm", method: http.MethodPut,
+		summary: "NMT reset communication"},
+	{route: "enable/guarding", path: "enable/guarding", method: http.MethodPut,
+		summary: "Start node guarding of the node by the gateway", request: GuardingRequest{}},
+	{route: "disable/guarding", path: "disable/guarding", method: http.MethodPut,
+		summary: "Stop node guarding of the node by the gateway"},
+	{route: "enable/heartbeat", path: "enable/heartbeat", method: http.MethodPut,
+		summary: "Set heartbeat producer period in ms", request: HeartbeatRequest{}},
+	{route: "disable/heartbeat", path: "disable/heartbeat", method: http.MethodPut,
+		summary: "Disable heartbeat producer"},
+	{route: "lss/switch/glob", path: "lss/switch/glob", method: http.MethodPut,
+		summary: "LSS switch all slaves to waiting (0) or configuration (1) state", request: LSSValueRequest{}},
+	{route: "lss/switch/sel", path: "lss/switch/sel", method: http.MethodPut,
+		summary: "LSS switch a single slave to configuration state", request: LSSAddress{}},
+	{route: "lss/set/node", path: "lss/set/node", method: http.MethodPut,
+		summary: "LSS configure node-id", request: LSSValueRequest{}},
+	{route: "lss/conf/bitrate", path: "lss/conf/bitrate", method: http.MethodPut,
+		summary: "LSS configure bit timing", request: LSSConfigureBitrateRequest{}},
+	{route: "lss/activate/bitrate", path: "lss/activate/bitrate", method: http.MethodPut,
+		summary: "LSS activate bit timing, switch delay in ms", request: LSSValueRequest{}},
+	{route: "lss/store", path: "lss/store", method: http.MethodPut, summary: "LSS store configuration"},
+	{route: "lss/get/node", path: "lss/get/node", method: http.MethodGet,
+		summary: "LSS inquire node-id", response: LSSGetNodeResponse{}},
+	{route: "lss/inquire/addr", path: "lss/inquire/addr", method: http.MethodGet,
+		summary: "LSS inquire address", response: LSSInquireAddressResponse{}},
+	{route: "set/network", path: "set/network", method: http.MethodPut,
+		summary: "Set default network", request: SetDefaultNetOrNode{}},
+	{route: "set/node", path: "set/node", method: http.MethodPut,
+		summary: "Set default node", request: SetDefaultNetOrNode{}},
+	{route: "info/version", path: "info/version", method: http.MethodGet,
+		summary: "Gateway version", response: VersionInfo{}},
+	{route: "stream", path: "stream/r/{index}/{subindex}", method: http.MethodGet,
+		summary: "Stream an object read via SDO as raw bytes", stream: true},
+	{route: "stream", path: "stream/w/{index}/{subindex}", method: http.MethodPut,
+		summary: "Stream raw bytes written to an object via SDO", stream: true, response: SDOStreamWriteResponse{}},
+	{route: "batch", path: "batch", method: http.MethodPut,
+		summary: "Ordered batch of SDO reads & writes, with a result per item", request: BatchRequest{}, response: BatchResponse{}},
+}
+
+// Convert a Go type to a JSON schema, following json tags.
+// Embedded structs are flattened as done by encoding/json.
+func jsonSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// Any JSON value
+	if t == reflect.TypeOf(json.RawMessage{}) {
+		return map[string]any{}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		addStructProperties(t, properties)
+		return map[string]any{"type": "object", "properties": properties}
+	default:
+		return map[string]any{}
+	}
+}
+
+func addStructProperties(t reflect.Type, properties map[string]any) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			addStructProperties(embedded, properties)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = jsonSchema(field.Type)
+	}
+}
+
+// Name of the schema of a Go value, registered in components
+func schemaRef(value any, schemas map[string]any) map[string]any {
+	t := reflect.TypeOf(value)
+	schemas[t.Name()] = jsonSchema(t)
+	return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+func pathParameter(name string, description string) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"in":          "path",
+		"required":    true,
+		"description": description,
+		"schema":      map[string]any{"type": "string"},
+	}
+}
+
+var apiParameterDescriptions = map[string]string{
+	"sequence": "Sequence number, echoed in the response",
+	"net":      "Network number, or default / none / all",
+	"node":     "Node id, or default / none / all",
+	"index":    "Object index, decimal or hexadecimal e.g. 0x2000",
+	"subindex": "Object sub-index, decimal or hexadecimal",
+	"pdo":      "PDO number",
+}
+
+// OpenAPI returns the OpenAPI 3 document describing the gateway endpoints, in JSON.
+// It is generated from the gateway request & response types.
+// The document is also served on /openapi.json.
+func OpenAPI() ([]byte, error) {
+	schemas := map[string]any{}
+	base := schemaRef(GatewayResponseBase{}, schemas)
+	paths := map[string]any{}
+	for _, op := range apiOperations {
+		path := "/cia309-5/" + API_VERSION + "/{sequence}/{net}/{node}/" + op.path
+		parameters := []any{}
+		for _, part := range strings.Split(path, "/") {
+			if strings.HasPrefix(part, "{") {
+				name := strings.Trim(part, "{}")
+				parameters = append(parameters, pathParameter(name, apiParameterDescriptions[name]))
+			}
+		}
+		if op.values {
+			parameters = append(parameters, map[string]any{
+				"name":        "format",
+				"in":          "query",
+				"description": "Set to json to use JSON values decoded with the node OD instead of hexadecimal data, sub-index can then be omitted to access a whole ARRAY or RECORD",
+				"schema":      map[string]any{"type": "string", "enum": []string{"json"}},
+			})
+		}
+		response := base
+		if op.response != nil {
+			response = schemaRef(op.response, schemas)
+		}
+		if op.values && op.response != nil {
+			response = map[string]any{"oneOf": []any{response, schemaRef(SDOValueResponse{}, schemas)}}
+		}
+		operation := map[string]any{
+			"summary":     op.summary,
+			"operationId": strings.ToLower(op.method) + "/" + op.path,
+			"parameters":  parameters,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "Gateway response, check the response field for errors",
+					"content":     jsonContent(response),
+				},
+			},
+		}
+		if op.stream && op.method == http.MethodGet {
+			operation["responses"] = map[string]any{
+				"200": map[string]any{
+					"description": "Raw object content, outcome is given in the " + StreamResponseTrailer + " trailer",
+					"content":     map[string]any{streamContentType: map[string]any{}},
+				},
+			}
+		}
+		if op.stream && op.method == http.MethodPut {
+			operation["requestBody"] = map[string]any{
+				"content": map[string]any{streamContentType: map[string]any{}},
+			}
+		} else if op.request != nil {
+			request := schemaRef(op.request, schemas)
+			if op.values {
+				request = map[string]any{"oneOf": []any{request, schemaRef(SDOValueWriteRequest{}, schemas)}}
+			}
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(request),
+			}
+		}
+		if len(op.aliases) > 0 {
+			operation["description"] = "Also available as " + strings.Join(op.aliases, ", ")
+		}
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = map[string]any{}
+			paths[path] = item
+		}
+		item[strings.ToLower(op.method)] = operation
+	}
+	// Endpoints that are not part of CiA 309-5
+	plain := func(summary string, contentType string) map[string]any {
+		return map[string]any{"get": map[string]any{
+			"summary": summary,
+			"responses": map[string]any{"200": map[string]any{
+				"description": summary,
+				"content":     map[string]any{contentType: map[string]any{}},
+			}},
+		}}
+	}
+	paths["/healthz"] = plain("Liveness of the gateway", "text/plain")
+	paths["/readyz"] = plain("Readiness of the gateway", "text/plain")
+	paths["/metrics"] = plain("Network counters in Prometheus text format", "text/plain")
+	paths["/events"] = plain("Live network events as server-sent events", "text/event-stream")
+	paths["/openapi.json"] = plain("This document", "application/json")
+
+	document := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "CANopen HTTP gateway (CiA 309-5)",
+			"version": API_VERSION,
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
+
+func (g *GatewayServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	document, err := OpenAPI()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(document)
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	net := network.NewNetwork(canBus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw)
+	defer ts.Close()
+
+	t.Run("every route documented", func(t *testing.T) {
+		documented := []string{}
+		for _, op := range apiOperations {
+			documented = append(documented, op.route)
+			documented = append(documented, op.aliases...)
+		}
+		for route := range gw.routes {
+			assert.True(t, slices.Contains(documented, route), "route %v is not documented", route)
+		}
+	})
+
+	t.Run("served", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/openapi.json")
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		var document struct {
+			OpenAPI    string                    `json:"openapi"`
+			Paths      map[string]map[string]any `json:"paths"`
+			Components struct {
+				Schemas map[string]map[string]any `json:"schemas"`
+			} `json:"components"`
+		}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&document))
+		assert.Equal(t, "3.0.3", document.OpenAPI)
+		assert.Contains(t, document.Paths["/cia309-5/1.0/{sequence}/{net}/{node}/r/{index}/{subindex}"], "get")
+		assert.Contains(t, document.Paths["/cia309-5/1.0/{sequence}/{net}/{node}/w/{index}/{subindex}"], "put")
+		// Embedded response base is flattened
+		properties := document.Components.Schemas["SDOReadResponse"]["properties"].(map[string]any)
+		assert.Contains(t, properties, "sequence")
+		assert.Contains(t, properties, "data")
+		// JSON values alternative
+		properties = document.Components.Schemas["SDOValueResponse"]["properties"].(map[string]any)
+		assert.Contains(t, properties, "value")
+		assert.Contains(t, document.Components.Schemas, "SDOValueWriteRequest")
+	})
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/pdo"
+)
+
+// Handle PDO configuration of a node, i.e. set/rpdo/<nr> or set/tpdo/<nr>
+// PDO number is 1-256 for both RPDOs and TPDOs. The configuration is written
+// to the communication & mapping parameters of the node via SDO.
+func (g *GatewayServer) handleSetPDO(w doneWriter, req *GatewayRequest) error {
+	parts := strings.Split(req.command, "/")
+	if len(parts) != 3 {
+		return ErrGwSyntaxError
+	}
+	nr, err := strconv.ParseUint(parts[2], 0, 16)
+	if err != nil || nr < 1 || nr > uint64(pdo.MaxRpdoNumber) {
+		return ErrGwSyntaxError
+	}
+	pdoNb := uint16(nr)
+	if parts[1] == "tpdo" {
+		pdoNb += pdo.MaxRpdoNumber
+	}
+	nodeId, err := targetNode(g.BaseGateway, req, false)
+	if err != nil {
+		return err
+	}
+	var pdoSet PDOSetRequest
+	err = json.Unmarshal(req.parameters, &pdoSet)
+	if err != nil {
+		return ErrGwSyntaxError
+	}
+	conf, enabled, err := parsePDOConfiguration(&pdoSet)
+	if err != nil {
+		return err
+	}
+	return g.ConfigurePDO(nodeId, pdoNb, conf, enabled)
+}
+
+// Parse & check a PDO configuration request
+func parsePDOConfiguration(pdoSet *PDOSetRequest) (conf config.PDOConfigurationParameter, enabled bool, err error) {
+	cobId, err := strconv.ParseUint(pdoSet.CobId, 0, 32)
+	if err != nil {
+		return conf, false, ErrGwSyntaxError
+	}
+	transType, err := strconv.ParseUint(pdoSet.TransmissionType, 0, 8)
+	if err != nil {
+		return conf, false, ErrGwSyntaxError
+	}
+	// Optional values
+	timers := []string{pdoSet.InhibitTime, pdoSet.EventTimer}
+	values := [2]uint64{}
+	for i, timer := range timers {
+		if timer == "" {
+			continue
+		}
+		values[i], err = strconv.ParseUint(timer, 0, 16)
+		if err != nil {
+			return conf, false, ErrGwSyntaxError
+		}
+	}
+	if len(pdoSet.Mappings) > int(od.MaxMappedEntriesPdo) {
+		return conf, false, ErrGwPDOLengthExceeded
+	}
+	totalBits := 0
+	for _, mappingStr := range pdoSet.Mappings {
+		mapping, err := strconv.ParseUint(mappingStr, 0, 32)
+		if err != nil {
+			return conf, false, ErrGwSyntaxError
+		}
+		param := config.PDOMappingParameter{
+			Index:      uint16(mapping >> 16),
+			Subindex:   uint8(mapping >> 8),
+			LengthBits: uint8(mapping),
+		}
+		totalBits += int(param.LengthBits)
+		conf.Mappings = append(conf.Mappings, param)
+	}
+	if totalBits > 64 {
+		return conf, false, ErrGwPDOLengthExceeded
+	}
+	conf.CanId = uint16(cobId & 0x7FF)
+	conf.TransmissionType = uint8(transType)
+	conf.InhibitTime = uint16(values[0])
+	conf.EventTimer = uint16(values[1])
+	return conf, cobId&(1<<31) == 0, nil
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type QuotaMode uint8
+
+const (
+	QuotaEnforce QuotaMode = iota // Requests exceeding a quota are rejected
+	QuotaLogOnly                  // Requests exceeding a quota are only logged
+)
+
+const quotaWindow = time.Minute
+
+// Per-client limits, a client is identified by its IP address.
+// A limit of 0 means unlimited.
+type QuotaOptions struct {
+	MaxConcurrent   int // Maximum number of requests processed at the same time
+	MaxSDOPerMinute int // Maximum number of SDO reads / writes per minute
+	MaxTransferSize int // Maximum size in bytes of SDO reads / writes
+	Mode            QuotaMode
+}
+
+// Returned when a client exceeds one of its quotas
+type QuotaError struct {
+	Reason     string
+	Limit      int
+	RetryAfter time.Duration // Time after which request can be retried, 0 if not applicable
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("quota exceeded : %v (limit %v)", e.Reason, e.Limit)
+}
+
+// Response sent when a quota is exceeded, in addition to
+// the usual fields, it contains the reason & the limit.
+type QuotaExceededResponse struct {
+	*GatewayResponseBase
+	Reason string `json:"reason"`
+	Limit  int    `json:"limit"`
+}
+
+type clientQuota struct {
+	active int
+	sdoOps []time.Time
+}
+
+// Keeps track of the usage of every client
+type quotaTracker struct {
+	mu      sync.Mutex
+	opts    QuotaOptions
+	clients map[string]*clientQuota
+	now     func() time.Time
+}
+
+func newQuotaTracker(opts QuotaOptions) *quotaTracker {
+	return &quotaTracker{opts: opts, clients: make(map[string]*clientQuota), now: time.Now}
+}
+
+func (q *quotaTracker) client(id string) *clientQuota {
+	c, ok := q.clients[id]
+	if !ok {
+		c = &clientQuota{}
+		q.clients[id] = c
+	}
+	return c
+}
+
+// Start a new request for client, should be followed by [quotaTracker.release]
+// even if an error is returned.
+func (q *quotaTracker) acquire(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	c := q.client(id)
+	c.active++
+	if q.opts.MaxConcurrent > 0 && c.active > q.opts.MaxConcurrent {
+		return &QuotaError{Reason: "too many concurrent requests", Limit: q.opts.MaxConcurrent}
+	}
+	return nil
+}
+
+func (q *quotaTracker) release(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	c := q.client(id)
+	c.active--
+	if c.active <= 0 && len(c.sdoOps) == 0 {
+		delete(q.clients, id)
+	}
+}
+
+// Account for a new SDO operation of client
+func (q *quotaTracker) sdo(id string) error {
+	if q.opts.MaxSDOPerMinute <= 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	c := q.client(id)
+	now := q.now()
+	// Drop operations outside of the sliding window
+	i := 0
+	for i < len(c.sdoOps) && now.Sub(c.sdoOps[i]) >= quotaWindow {
+		i++
+	}
+	c.sdoOps = c.sdoOps[i:]
+	if len(c.sdoOps) >= q.opts.MaxSDOPerMinute {
+		return &QuotaError{
+			Reason:     "too many sdo operations per minute",
+			Limit:      q.opts.MaxSDOPerMinute,
+			RetryAfter: quotaWindow - now.Sub(c.sdoOps[0]),
+		}
+	}
+	c.sdoOps = append(c.sdoOps, now)
+	return nil
+}
+
+// Check size of an SDO transfer
+func (q *quotaTracker) transfer(size int) error {
+	if q.opts.MaxTransferSize > 0 && size > q.opts.MaxTransferSize {
+		return &QuotaError{Reason: "transfer size too big", Limit: q.opts.MaxTransferSize}
+	}
+	return nil
+}
+
+// Identify a client from its remote address
+func clientId(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// SetQuotas enables per-client quotas. Passing nil disables them.
+// This should be called before serving requests.
+func (g *GatewayServer) SetQuotas(opts *QuotaOptions) {
+	if opts == nil {
+		g.quotas = nil
+		return
+	}
+	g.quotas = newQuotaTracker(*opts)
+}
+
+// Check the result of a quota check, returns true if request can proceed.
+// When enforcing, an informative response is sent to the client.
+func (g *GatewayServer) checkQuota(w http.ResponseWriter, req *GatewayRequest, err error) bool {
+	if err == nil {
+		return true
+	}
+	quotaErr, ok := err.(*QuotaError)
+	if !ok {
+		return true
+	}
+	g.logger.Warn("client exceeded quota",
+		"client", req.client,
+		"reason", quotaErr.Reason,
+		"limit", quotaErr.Limit,
+		"enforced", g.quotas.opts.Mode == QuotaEnforce,
+	)
+	if g.quotas.opts.Mode != QuotaEnforce {
+		return true
+	}
+	resp := QuotaExceededResponse{
+		GatewayResponseBase: NewResponseBase(int(req.sequence), ErrGwManufacturerSpecificError.Error()),
+		Reason:              quotaErr.Reason,
+		Limit:               quotaErr.Limit,
+	}
+	respRaw, _ := json.Marshal(resp)
+	w.Header().Set("Content-Type", "application/json")
+	if quotaErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(quotaErr.RetryAfter.Seconds())+1))
+	}
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write(respRaw)
+	return false
+}
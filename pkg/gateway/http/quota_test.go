@@ -0,0 +1,91 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaTracker(t *testing.T) {
+	q := newQuotaTracker(QuotaOptions{MaxConcurrent: 1, MaxSDOPerMinute: 2, MaxTransferSize: 4})
+	now := time.Now()
+	q.now = func() time.Time { return now }
+
+	t.Run("concurrent", func(t *testing.T) {
+		assert.Nil(t, q.acquire("a"))
+		assert.IsType(t, &QuotaError{}, q.acquire("a"))
+		assert.Nil(t, q.acquire("b"))
+		q.release("a")
+		q.release("a")
+		q.release("b")
+		assert.Nil(t, q.acquire("a"))
+		q.release("a")
+	})
+	t.Run("sdo per minute", func(t *testing.T) {
+		assert.Nil(t, q.sdo("a"))
+		now = now.Add(30 * time.Second)
+		assert.Nil(t, q.sdo("a"))
+		err := q.sdo("a")
+		assert.IsType(t, &QuotaError{}, err)
+		assert.Equal(t, 30*time.Second, err.(*QuotaError).RetryAfter)
+		assert.Nil(t, q.sdo("b"))
+		// First operation leaves the window
+		now = now.Add(30 * time.Second)
+		assert.Nil(t, q.sdo("a"))
+	})
+	t.Run("transfer size", func(t *testing.T) {
+		assert.Nil(t, q.transfer(4))
+		assert.IsType(t, &QuotaError{}, q.transfer(5))
+	})
+}
+
+func TestQuotaServer(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	_, err = net.CreateLocalNode(0x66, od.Default())
+	assert.Nil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw.serveMux)
+	defer ts.Close()
+	client := NewGatewayClient(ts.URL, API_VERSION, 1, nil)
+
+	t.Run("transfer size", func(t *testing.T) {
+		gw.SetQuotas(&QuotaOptions{MaxTransferSize: 1})
+		assert.Nil(t, client.WriteRaw(0x66, 0x2002, 0, "0x10", "i8"))
+		err := client.WriteRaw(0x66, 0x2003, 0, "0x5432", "i16")
+		assert.Equal(t, ErrGwManufacturerSpecificError, err)
+		_, _, err = client.ReadRaw(0x66, 0x2003, 0)
+		assert.Equal(t, ErrGwManufacturerSpecificError, err)
+	})
+	t.Run("sdo per minute", func(t *testing.T) {
+		gw.SetQuotas(&QuotaOptions{MaxSDOPerMinute: 2})
+		for range 2 {
+			_, _, err := client.ReadRaw(0x66, 0x2002, 0)
+			assert.Nil(t, err)
+		}
+		resp, err := http.Get(ts.URL + "/cia309-5/1.0/100/1/0x66/r/0x2002/0")
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+	})
+	t.Run("log only", func(t *testing.T) {
+		gw.SetQuotas(&QuotaOptions{MaxSDOPerMinute: 1, Mode: QuotaLogOnly})
+		for range 3 {
+			_, _, err := client.ReadRaw(0x66, 0x2002, 0)
+			assert.Nil(t, err)
+		}
+		gw.SetQuotas(nil)
+	})
+}
@@ -1,8 +1,10 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
@@ -30,11 +32,7 @@ func NewResponseBase(sequence int, response string) *GatewayResponseBase {
 }
 
 func NewResponseError(sequence int, error error) []byte {
-	gwErr, ok := error.(*GatewayError)
-	if !ok {
-		gwErr = ErrGwRequestNotProcessed // Apparently no "internal error"
-	}
-	jData, _ := json.Marshal(map[string]string{"sequence": strconv.Itoa(sequence), "response": gwErr.Error()})
+	jData, _ := json.Marshal(map[string]string{"sequence": strconv.Itoa(sequence), "response": toGatewayError(error).Error()})
 	return jData
 }
 
@@ -73,6 +71,12 @@ type GatewayRequest struct {
 	command    string // command can be composed of different parts
 	sequence   uint32 // sequence number
 	parameters json.RawMessage
+	client     string // client identifier, used for quotas
+	principal  string // authenticated user, used for authorization
+	ctx        context.Context
+	body       io.Reader // raw body for streams
+	size       int64     // raw body size, -1 if unknown
+	jsonValues bool      // SDO values are encoded as JSON using the OD, see [ValueMediaType]
 }
 type SDOSetTimeoutRequest struct {
 	Value string `json:"value"`
@@ -83,6 +87,12 @@ type SDOWriteRequest struct {
 	Datatype string `json:"datatype"`
 }
 
+// Response to a stream write, not part of CiA 309-5
+type SDOStreamWriteResponse struct {
+	*GatewayResponseBase
+	Length int64 `json:"length"`
+}
+
 type SDOReadResponse struct {
 	*GatewayResponseBase
 	Data   string `json:"data"`
@@ -97,3 +107,49 @@ type VersionInfo struct {
 type SetDefaultNetOrNode struct {
 	Value string `json:"value"`
 }
+
+type HeartbeatRequest struct {
+	Value string `json:"value"` // Heartbeat period in ms
+}
+
+type GuardingRequest struct {
+	GuardTime      string `json:"guardtime"` // Guard time in ms
+	LifeTimeFactor string `json:"ltf"`       // Node is lost after guard time x life time factor
+}
+
+// PDO configuration, COB-ID uses the CiA 301 format, i.e. bit 31 set means PDO is disabled.
+// Mappings are also in the CiA 301 format, e.g. "0x20010108" maps 0x2001 sub 1 with 8 bits.
+type PDOSetRequest struct {
+	CobId            string   `json:"cob-id"`
+	TransmissionType string   `json:"transmission-type"`
+	InhibitTime      string   `json:"inhibit-time,omitempty"`
+	EventTimer       string   `json:"event-timer,omitempty"`
+	Mappings         []string `json:"mappings"`
+}
+
+type LSSValueRequest struct {
+	Value string `json:"value"`
+}
+
+// LSS address, used for selective switch & inquire address
+type LSSAddress struct {
+	VendorId       string `json:"vendor-id"`
+	ProductCode    string `json:"product-code"`
+	RevisionNumber string `json:"revision-number"`
+	SerialNumber   string `json:"serial-number"`
+}
+
+type LSSConfigureBitrateRequest struct {
+	TableSelector string `json:"table-selector"`
+	TableIndex    string `json:"table-index"`
+}
+
+type LSSInquireAddressResponse struct {
+	*GatewayResponseBase
+	*LSSAddress
+}
+
+type LSSGetNodeResponse struct {
+	*GatewayResponseBase
+	Value string `json:"value"`
+}
@@ -1,9 +1,11 @@
 package http
 
 import (
+	"crypto/tls"
 	"log/slog"
 	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/samsamfire/gocanopen/pkg/gateway"
 	"github.com/samsamfire/gocanopen/pkg/network"
@@ -15,10 +17,12 @@ const API_VERSION = "1.0"
 const MAX_SEQUENCE_NB = 2<<31 - 1
 const URI_PATTERN = `/cia309-5/(\d+\.\d+)/(\d{1,10})/(0x[0-9a-f]{1,4}|\d{1,10}|default|none|all)/(0x[0-9a-f]{1,2}|\d{1,3}|default|none|all)/(.*)`
 const SDO_COMMAND_URI_PATTERN = `(r|read|w|write)/(all|0x[0-9a-f]{1,4}|\d{1,5})/?(0x[0-9a-f]{1,2}|\d{1,3})?`
+const SDO_BLOCK_COMMAND_URI_PATTERN = `(rb|read-block|wb|write-block)/(all|0x[0-9a-f]{1,4}|\d{1,5})/?(0x[0-9a-f]{1,2}|\d{1,3})?`
 const PDO_COMMAND_URI_PATTERN = `(r|read|w|write)/(p|pdo)/(0x[0-9a-f]{1,3}|\d{1,4})`
 
 var regURI = regexp.MustCompile(URI_PATTERN)
 var regSDO = regexp.MustCompile(SDO_COMMAND_URI_PATTERN)
+var regSDOBlock = regexp.MustCompile(SDO_BLOCK_COMMAND_URI_PATTERN)
 var regPDO = regexp.MustCompile(PDO_COMMAND_URI_PATTERN)
 
 var DATATYPE_MAP = map[string]uint8{
@@ -41,6 +45,15 @@ type GatewayServer struct {
 	logger   *slog.Logger
 	serveMux *http.ServeMux
 	routes   map[string]GatewayRequestHandler
+	// Readiness thresholds
+	healthMaxAge     time.Duration
+	healthMaxBacklog int
+	// Per-client quotas, nil if disabled
+	quotas *quotaTracker
+	// Authorization of operations, nil if disabled
+	auth *AuthOptions
+	// Live events pushed to clients
+	events *eventBroker
 }
 
 // Create a new gateway
@@ -51,9 +64,26 @@ func NewGatewayServer(network *network.Network, logger *slog.Logger, defaultNetw
 	}
 	logger = logger.With("service", "[HTTP]")
 	base := gateway.NewBaseGateway(network, logger, defaultNetworkId, defaultNodeId, sdoUploadBufferSize)
-	g := &GatewayServer{BaseGateway: base, logger: logger}
+	g := &GatewayServer{
+		BaseGateway:      base,
+		logger:           logger,
+		healthMaxAge:     DefaultHealthMaxAge,
+		healthMaxBacklog: DefaultHealthBacklog,
+		events:           newEventBroker(network, logger),
+	}
 	g.serveMux = http.NewServeMux()
 	g.serveMux.HandleFunc("/", g.handleRequest) // This base route handles all the requests
+	// Liveness & readiness, not part of CiA 309-5.
+	// Intentionally public, for probes : they only expose the gateway state
+	g.serveMux.HandleFunc("/healthz", g.handleHealthz)
+	g.serveMux.HandleFunc("/readyz", g.handleReadyz)
+	// Prometheus metrics, not part of CiA 309-5. Authorized unless public, see [AuthOptions]
+	g.serveMux.HandleFunc("/metrics", g.handleMetrics)
+	// Live events, not part of CiA 309-5. Authorized
+	g.serveMux.HandleFunc("/events", g.handleEvents)
+	// OpenAPI document, not part of CiA 309-5.
+	// Intentionally public, it only describes the API
+	g.serveMux.HandleFunc("/openapi.json", g.handleOpenAPI)
 	g.routes = make(map[string]GatewayRequestHandler)
 
 	g.logger.Info("initializing http gateway (CiA 309-5) endpoints")
@@ -63,6 +93,14 @@ func NewGatewayServer(network *network.Network, logger *slog.Logger, defaultNetw
 	g.addRoute("w", g.handleWrite)
 	g.addRoute("write", g.handleWrite)
 	g.addRoute("set/sdo-timeout", g.handleSDOTimeout)
+	g.addRoute("rb", g.handleSDOBlock)
+	g.addRoute("read-block", g.handleSDOBlock)
+	g.addRoute("wb", g.handleSDOBlock)
+	g.addRoute("write-block", g.handleSDOBlock)
+
+	// CiA 309-5 | 4.2
+	g.addRoute("set/rpdo", g.handleSetPDO)
+	g.addRoute("set/tpdo", g.handleSetPDO)
 
 	// CiA 309-5 | 4.3
 	g.addRoute("start", createNmtHandler(base, nmt.CommandEnterOperational))
@@ -72,16 +110,31 @@ func NewGatewayServer(network *network.Network, logger *slog.Logger, defaultNetw
 	g.addRoute("reset/node", createNmtHandler(base, nmt.CommandResetNode))
 	g.addRoute("reset/comm", createNmtHandler(base, nmt.CommandResetCommunication))
 	g.addRoute("reset/communication", createNmtHandler(base, nmt.CommandResetCommunication))
-	g.addRoute("enable/guarding", handlerNotSupported)
-	g.addRoute("disable/guarding", handlerNotSupported)
-	g.addRoute("enable/heartbeat", handlerNotSupported)
-	g.addRoute("disable/heartbeat", handlerNotSupported)
+	g.addRoute("enable/guarding", g.createGuardingHandler(true))
+	g.addRoute("disable/guarding", g.createGuardingHandler(false))
+	g.addRoute("enable/heartbeat", g.createHeartbeatHandler(true))
+	g.addRoute("disable/heartbeat", g.createHeartbeatHandler(false))
+
+	// CiA 309-5 | 4.5
+	g.addRoute("lss/switch/glob", g.handleLSSSwitchGlobal)
+	g.addRoute("lss/switch/sel", g.handleLSSSwitchSelective)
+	g.addRoute("lss/set/node", g.handleLSSSetNode)
+	g.addRoute("lss/conf/bitrate", g.handleLSSConfigureBitrate)
+	g.addRoute("lss/activate/bitrate", g.handleLSSActivateBitrate)
+	g.addRoute("lss/store", g.handleLSSStore)
+	g.addRoute("lss/get/node", g.handleLSSGetNode)
+	g.addRoute("lss/inquire/addr", g.handleLSSInquireAddress)
 
 	// CiA 309-5 | 4.6
 	g.addRoute("set/network", g.handleSetDefaultNetwork)
 	g.addRoute("set/node", g.handleSetDefaultNode)
 	g.addRoute("info/version", g.handleGetVersion)
 
+	// Streaming of big objects, not part of CiA 309-5
+	g.addRoute("stream", g.handleStream)
+	// Batches of SDO accesses, not part of CiA 309-5
+	g.addRoute("batch", g.handleBatch)
+
 	g.logger.Info("finished initializing")
 
 	return g
@@ -92,6 +145,22 @@ func (g *GatewayServer) ListenAndServe(addr string) error {
 	return http.ListenAndServe(addr, g.serveMux)
 }
 
+// ServeHTTP makes the gateway usable as an [http.Handler], e.g. with a custom [http.Server]
+func (g *GatewayServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.serveMux.ServeHTTP(w, r)
+}
+
+// Process server over HTTPS, blocking. certFile & keyFile are PEM encoded,
+// see [http.Server.ListenAndServeTLS]. config can be used for e.g. requiring
+// client certificates, TLS 1.2 is the minimum version if nil.
+func (g *GatewayServer) ListenAndServeTLS(addr string, certFile string, keyFile string, config *tls.Config) error {
+	if config == nil {
+		config = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	server := &http.Server{Addr: addr, Handler: g.serveMux, TLSConfig: config}
+	return server.ListenAndServeTLS(certFile, keyFile)
+}
+
 // Add a route to the server for handling a specific command
 func (g *GatewayServer) addRoute(command string, handler GatewayRequestHandler) {
 	g.logger.Debug("registering route", "command", command)
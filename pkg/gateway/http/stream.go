@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Streaming of big SDO objects (e.g. domains), this is not part of CiA 309-5.
+// Data is not buffered by the gateway and block transfer is used whenever
+// supported by the node. Routes are :
+//
+//   - GET .../<node>/stream/r/<index>/<subindex> : data is sent back as raw bytes,
+//     using chunked transfer-encoding
+//   - PUT .../<node>/stream/w/<index>/<subindex> : data is given as raw bytes in body,
+//     which can use chunked transfer-encoding
+//
+// As an error can occur after data has started being sent, the outcome of
+// a stream read is given in the [StreamResponseTrailer] trailer ("OK" or "ERROR:x").
+// Errors that occur before any data is sent are answered with a regular response.
+const StreamResponseTrailer = "X-Gateway-Response"
+
+const streamContentType = "application/octet-stream"
+
+// Writes stream data to client, headers are sent on first write
+// and every write is flushed so that data is not buffered.
+type streamWriter struct {
+	w       doneWriter
+	started bool
+}
+
+func (s *streamWriter) start() {
+	s.started = true
+	s.w.Header().Set("Content-Type", streamContentType)
+	s.w.Header().Set("Trailer", StreamResponseTrailer)
+	s.w.WriteHeader(http.StatusOK)
+}
+
+func (s *streamWriter) Write(b []byte) (int, error) {
+	if !s.started {
+		s.start()
+	}
+	n, err := s.w.Write(b)
+	if flusher, ok := s.w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+// Handle a stream read or write
+func (g *GatewayServer) handleStream(w doneWriter, req *GatewayRequest) error {
+	command := strings.TrimPrefix(req.command, "stream/")
+	matchSDO := regSDO.FindStringSubmatch(command)
+	if len(matchSDO) < 2 {
+		return ErrGwSyntaxError
+	}
+	index, subindex, err := parseSdoCommand(matchSDO[1:])
+	if err != nil {
+		g.logger.Error("unable to parse SDO command", "err", err)
+		return err
+	}
+	nodeId, err := targetNode(g.BaseGateway, req, false)
+	if err != nil {
+		return err
+	}
+	if !g.authorize(&w, req, uint16(index), uint8(subindex)) {
+		return nil
+	}
+	if g.quotas != nil {
+		if req.size > 0 && !g.checkQuota(&w, req, g.quotas.transfer(int(req.size))) {
+			return nil
+		}
+		if !g.checkQuota(&w, req, g.quotas.sdo(req.client)) {
+			return nil
+		}
+	}
+	switch matchSDO[1] {
+	case "r", "read":
+		return g.handleStreamRead(w, req, nodeId, uint16(index), uint8(subindex))
+	default:
+		return g.handleStreamWrite(w, req, nodeId, uint16(index), uint8(subindex))
+	}
+}
+
+func (g *GatewayServer) handleStreamRead(w doneWriter, req *GatewayRequest, nodeId uint8, index uint16, subindex uint8) error {
+	sw := &streamWriter{w: w}
+	n, err := g.ReadSDOStream(req.ctx, nodeId, index, subindex, sw)
+	if err != nil && !sw.started {
+		w.Write(NewResponseError(int(req.sequence), err))
+		return nil
+	}
+	if !sw.started {
+		// Empty object
+		sw.start()
+	}
+	response := "OK"
+	if err != nil {
+		g.logger.Warn("stream read failed", "node", nodeId, "index", index, "subindex", subindex, "sent", n, "err", err)
+		response = toGatewayError(err).Error()
+	}
+	w.Header().Set(StreamResponseTrailer, response)
+	return nil
+}
+
+func (g *GatewayServer) handleStreamWrite(w doneWriter, req *GatewayRequest, nodeId uint8, index uint16, subindex uint8) error {
+	size := uint32(0)
+	if req.size > 0 {
+		size = uint32(req.size)
+	}
+	n, err := g.WriteSDOStream(req.ctx, nodeId, index, subindex, req.body, size)
+	if err != nil {
+		w.Write(NewResponseError(int(req.sequence), err))
+		return nil
+	}
+	resp := SDOStreamWriteResponse{
+		GatewayResponseBase: NewResponseBase(int(req.sequence), "OK"),
+		Length:              n,
+	}
+	respRaw, err := json.Marshal(resp)
+	if err != nil {
+		return ErrGwRequestNotProcessed
+	}
+	w.Write(respRaw)
+	return nil
+}
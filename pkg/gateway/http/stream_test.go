@@ -0,0 +1,91 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	odict := od.Default()
+	path := filepath.Join(t.TempDir(), "domain.bin")
+	odict.AddFile(0x3000, "domain", path, os.O_RDONLY, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+	_, err = net.CreateLocalNode(0x66, odict)
+	assert.Nil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw.serveMux)
+	defer ts.Close()
+	client := NewGatewayClient(ts.URL, API_VERSION, 1, nil)
+
+	// Bigger than gateway buffer
+	data := make([]byte, 3000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	t.Run("write & read back", func(t *testing.T) {
+		// Unknown size, i.e. chunked
+		n, err := client.WriteStream(0x66, 0x3000, 0, io.MultiReader(bytes.NewReader(data[:1000]), bytes.NewReader(data[1000:])))
+		assert.Nil(t, err)
+		assert.EqualValues(t, len(data), n)
+		written, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.True(t, bytes.Equal(data, written))
+		buf := &bytes.Buffer{}
+		n, err = client.ReadStream(0x66, 0x3000, 0, buf)
+		assert.Nil(t, err)
+		assert.EqualValues(t, len(data), n)
+		assert.True(t, bytes.Equal(data, buf.Bytes()))
+		supported, known := net.BlockSupported(0x66)
+		assert.True(t, known)
+		assert.True(t, supported)
+	})
+
+	t.Run("small objects", func(t *testing.T) {
+		n, err := client.WriteStream(0x66, 0x2002, 0, bytes.NewReader([]byte{0x22}))
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, n)
+		buf := &bytes.Buffer{}
+		_, err = client.ReadStream(0x66, 0x2002, 0, buf)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0x22}, buf.Bytes())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := client.ReadStream(0x66, 0x4000, 0, io.Discard)
+		assert.Equal(t, NewGatewayError(int(sdo.AbortNotExist)), err)
+		_, err = client.WriteStream(0x66, 0x4000, 0, bytes.NewReader(data))
+		assert.Equal(t, NewGatewayError(int(sdo.AbortNotExist)), err)
+		resp, err := http.Get(ts.URL + "/cia309-5/1.0/10/1/0x66/stream/r/0x2002")
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), "ERROR:101")
+	})
+
+	t.Run("block threshold", func(t *testing.T) {
+		gw.SetSDOBlockThreshold(10)
+		defer gw.SetSDOBlockThreshold(0)
+		assert.Nil(t, client.WriteRaw(0x66, 0x2002, 0, "0x10", "i8"))
+		value, _, err := client.ReadRaw(0x66, 0x2002, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "0x10", value)
+	})
+}
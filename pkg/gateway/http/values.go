@@ -0,0 +1,369 @@
+package http
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// Media type selecting JSON values for SDO reads & writes, as an alternative
+// to the "format=json" query parameter. This is not part of CiA 309-5.
+const ValueMediaType = "application/vnd.cia309-5.value+json"
+
+// Read response in JSON value mode. Value is a number, string or boolean
+// for a single sub-index, or an array of values when a whole entry is read.
+type SDOValueResponse struct {
+	*GatewayResponseBase
+	Value    any    `json:"value"`
+	Datatype string `json:"datatype,omitempty"`
+}
+
+// Write request in JSON value mode, datatype is taken from the OD of the node
+type SDOValueWriteRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// Error response in JSON value mode, with a human readable explanation
+type ValueErrorResponse struct {
+	*GatewayResponseBase
+	Message string `json:"message"`
+}
+
+// Error of JSON value encoding, sent back with a message
+type valueError struct {
+	err     error
+	message string
+}
+
+func (e *valueError) Error() string {
+	return e.message
+}
+
+func (e *valueError) Unwrap() error {
+	return e.err
+}
+
+func newValueError(err error, format string, args ...any) error {
+	return &valueError{err: err, message: fmt.Sprintf(format, args...)}
+}
+
+// Returns true if the request asks for JSON values instead of raw hex data
+func wantsJSONValues(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	for _, accept := range r.Header.Values("Accept") {
+		if strings.Contains(accept, ValueMediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+func newResponseValueError(sequence int, err error) []byte {
+	resp := ValueErrorResponse{
+		GatewayResponseBase: NewResponseBase(sequence, toGatewayError(err).Error()),
+		Message:             err.Error(),
+	}
+	jData, _ := json.Marshal(resp)
+	return jData
+}
+
+// Gateway name of a CiA 301 datatype, e.g. "u8"
+func datatypeName(datatype uint8) string {
+	for name, value := range DATATYPE_MAP {
+		if value == datatype {
+			return name
+		}
+	}
+	switch datatype {
+	case od.OCTET_STRING:
+		return "os"
+	case od.DOMAIN:
+		return "d"
+	}
+	return ""
+}
+
+// Decode raw OD data to a JSON value.
+// Octet strings & domains are given as hexadecimal strings e.g. "0x0102".
+func decodeValue(data []byte, datatype uint8) (any, error) {
+	switch datatype {
+	case od.BOOLEAN:
+		if len(data) != 1 {
+			return nil, sdo.AbortTypeMismatch
+		}
+		return data[0] != 0, nil
+	case od.VISIBLE_STRING:
+		return string(bytes.TrimRight(data, "\x00")), nil
+	case od.OCTET_STRING, od.DOMAIN:
+		return "0x" + hex.EncodeToString(data), nil
+	}
+	value, err := od.DecodeToType(data, datatype)
+	if err == od.ErrTypeMismatch {
+		return nil, newValueError(ErrGwRequestNotSupported, "unsupported datatype x%x", datatype)
+	}
+	if err != nil {
+		return nil, sdo.AbortTypeMismatch
+	}
+	return value, nil
+}
+
+// Encode a JSON value to raw OD data
+func encodeValue(raw json.RawMessage, datatype uint8) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, newValueError(ErrGwSyntaxError, "invalid JSON value : %v", err)
+	}
+	name := datatypeName(datatype)
+	switch datatype {
+	case od.BOOLEAN:
+		boolean, ok := value.(bool)
+		if !ok {
+			return nil, newValueError(sdo.AbortTypeMismatch, "expected a boolean for datatype %v", name)
+		}
+		if boolean {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case od.VISIBLE_STRING:
+		str, ok := value.(string)
+		if !ok {
+			return nil, newValueError(sdo.AbortTypeMismatch, "expected a string for datatype %v", name)
+		}
+		return []byte(str), nil
+	case od.OCTET_STRING, od.DOMAIN:
+		str, ok := value.(string)
+		if !ok || !strings.HasPrefix(str, "0x") {
+			return nil, newValueError(sdo.AbortTypeMismatch, "expected a hexadecimal string e.g. \"0x0102\" for datatype %v", name)
+		}
+		data, err := hex.DecodeString(str[2:])
+		if err != nil {
+			return nil, newValueError(sdo.AbortTypeMismatch, "invalid hexadecimal string %q", str)
+		}
+		return data, nil
+	case od.REAL32, od.REAL64:
+		number, ok := value.(json.Number)
+		if !ok {
+			return nil, newValueError(sdo.AbortTypeMismatch, "expected a number for datatype %v", name)
+		}
+		float, err := number.Float64()
+		if err != nil {
+			return nil, newValueError(sdo.AbortTypeMismatch, "invalid number %v", number)
+		}
+		if datatype == od.REAL32 {
+			return binary.LittleEndian.AppendUint32(nil, math.Float32bits(float32(float))), nil
+		}
+		return binary.LittleEndian.AppendUint64(nil, math.Float64bits(float)), nil
+	}
+	if name == "" {
+		return nil, newValueError(ErrGwRequestNotSupported, "unsupported datatype x%x", datatype)
+	}
+	// Integers, given as numbers or as strings e.g. "0x10"
+	var str string
+	switch v := value.(type) {
+	case json.Number:
+		str = v.String()
+	case string:
+		str = v
+	default:
+		return nil, newValueError(sdo.AbortTypeMismatch, "expected an integer for datatype %v", name)
+	}
+	if str == "" {
+		return nil, newValueError(sdo.AbortTypeMismatch, "expected an integer for datatype %v", name)
+	}
+	data, err := od.EncodeFromString(str, datatype, 0)
+	if err != nil {
+		return nil, newValueError(sdo.AbortTypeMismatch, "invalid value %v for datatype %v", str, name)
+	}
+	return data, nil
+}
+
+// Find the OD entry of the node, used for encoding & decoding values
+func (g *GatewayServer) valueEntry(nodeId uint8, index uint16) (*od.Entry, error) {
+	odict, err := g.Network().GetOD(nodeId)
+	if err != nil {
+		return nil, newValueError(ErrGwRequestNotSupported,
+			"no object dictionary loaded for node x%x, datatype is unknown. Add the node with its EDS or use raw data", nodeId)
+	}
+	entry := odict.Index(index)
+	if entry == nil {
+		return nil, newValueError(sdo.AbortNotExist, "object x%x does not exist in the object dictionary of node x%x", index, nodeId)
+	}
+	return entry, nil
+}
+
+// Variables concerned by a JSON value access. If sub-index is not given,
+// this is every sub-index of an ARRAY or RECORD except sub-index 0,
+// or the variable itself.
+func valueVariables(entry *od.Entry, subindex string) ([]*od.Variable, error) {
+	if subindex != "" {
+		sub, err := strconv.ParseUint(subindex, 0, 8)
+		if err != nil {
+			return nil, ErrGwSyntaxError
+		}
+		variable, err := entry.SubIndex(int(sub))
+		if err != nil {
+			return nil, newValueError(sdo.AbortSubUnknown, "sub-index x%x of object x%x does not exist", sub, entry.Index)
+		}
+		return []*od.Variable{variable}, nil
+	}
+	if !wholeEntry(entry, subindex) {
+		variable, err := entry.SubIndex(0)
+		if err != nil {
+			return nil, err
+		}
+		return []*od.Variable{variable}, nil
+	}
+	variables := make([]*od.Variable, 0, entry.SubCount())
+	for sub := 1; sub <= 0xFF && len(variables) < entry.SubCount()-1; sub++ {
+		variable, err := entry.SubIndex(sub)
+		if err == nil {
+			variables = append(variables, variable)
+		}
+	}
+	return variables, nil
+}
+
+// Returns true if all the sub-indexes of an ARRAY or RECORD are accessed, as a JSON array
+func wholeEntry(entry *od.Entry, subindex string) bool {
+	return subindex == "" && (entry.ObjectType == od.ObjectTypeARRAY || entry.ObjectType == od.ObjectTypeRECORD)
+}
+
+// Parse a JSON value SDO command, sub-index may be omitted to access a whole entry
+func (g *GatewayServer) parseValueCommand(req *GatewayRequest, commands []string) (uint8, *od.Entry, []*od.Variable, error) {
+	index, err := strconv.ParseUint(commands[2], 0, 16)
+	if err != nil {
+		return 0, nil, nil, ErrGwRequestNotSupported
+	}
+	nodeId, err := targetNode(g.BaseGateway, req, false)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	entry, err := g.valueEntry(nodeId, uint16(index))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	variables, err := valueVariables(entry, commands[3])
+	return nodeId, entry, variables, err
+}
+
+// Handle an SDO read in JSON value mode
+func (g *GatewayServer) handlerSDOReadValue(w doneWriter, req *GatewayRequest, commands []string, block bool) error {
+	nodeId, entry, variables, err := g.parseValueCommand(req, commands)
+	if err != nil {
+		w.Write(newResponseValueError(int(req.sequence), err))
+		return nil
+	}
+	values := make([]any, 0, len(variables))
+	for _, variable := range variables {
+		if !g.authorize(&w, req, entry.Index, variable.SubIndex) {
+			return nil
+		}
+		if g.quotas != nil && !g.checkQuota(&w, req, g.quotas.sdo(req.client)) {
+			return nil
+		}
+		var n int
+		if block {
+			n, err = g.ReadSDOBlock(nodeId, entry.Index, variable.SubIndex)
+		} else {
+			n, err = g.ReadSDO(nodeId, entry.Index, variable.SubIndex)
+		}
+		if err != nil {
+			w.Write(NewResponseError(int(req.sequence), err))
+			return nil
+		}
+		if g.quotas != nil && !g.checkQuota(&w, req, g.quotas.transfer(n)) {
+			return nil
+		}
+		value, err := decodeValue(g.Buffer()[:n], variable.DataType)
+		if err != nil {
+			w.Write(newResponseValueError(int(req.sequence), newValueError(err,
+				"failed to decode x%x|x%x (%v) : %v", entry.Index, variable.SubIndex, variable.Name, err)))
+			return nil
+		}
+		values = append(values, value)
+	}
+	resp := SDOValueResponse{GatewayResponseBase: NewResponseBase(int(req.sequence), "OK")}
+	if wholeEntry(entry, commands[3]) {
+		resp.Value = values
+	} else {
+		resp.Value = values[0]
+		resp.Datatype = datatypeName(variables[0].DataType)
+	}
+	respRaw, err := json.Marshal(resp)
+	if err != nil {
+		return ErrGwRequestNotProcessed
+	}
+	w.Write(respRaw)
+	return nil
+}
+
+// Handle an SDO write in JSON value mode.
+// When writing a whole ARRAY or RECORD, value is an array written starting from sub-index 1.
+func (g *GatewayServer) handlerSDOWriteValue(w doneWriter, req *GatewayRequest, commands []string, block bool) error {
+	nodeId, entry, variables, err := g.parseValueCommand(req, commands)
+	if err != nil {
+		w.Write(newResponseValueError(int(req.sequence), err))
+		return nil
+	}
+	var sdoWrite SDOValueWriteRequest
+	err = json.Unmarshal(req.parameters, &sdoWrite)
+	if err != nil || sdoWrite.Value == nil {
+		return ErrGwSyntaxError
+	}
+	raws := []json.RawMessage{sdoWrite.Value}
+	if wholeEntry(entry, commands[3]) {
+		err = json.Unmarshal(sdoWrite.Value, &raws)
+		if err != nil {
+			w.Write(newResponseValueError(int(req.sequence), newValueError(ErrGwSyntaxError,
+				"expected an array of values for object x%x", entry.Index)))
+			return nil
+		}
+		if len(raws) > len(variables) {
+			w.Write(newResponseValueError(int(req.sequence), newValueError(sdo.AbortDataLong,
+				"too many values for object x%x, expected at most %v", entry.Index, len(variables))))
+			return nil
+		}
+	}
+	// Encode everything before writing anything
+	encoded := make([][]byte, len(raws))
+	for i, raw := range raws {
+		variable := variables[i]
+		encoded[i], err = encodeValue(raw, variable.DataType)
+		if err != nil {
+			w.Write(newResponseValueError(int(req.sequence), newValueError(err,
+				"x%x|x%x (%v) : %v", entry.Index, variable.SubIndex, variable.Name, err)))
+			return nil
+		}
+	}
+	for i, data := range encoded {
+		subindex := variables[i].SubIndex
+		if !g.authorize(&w, req, entry.Index, subindex) {
+			return nil
+		}
+		if g.quotas != nil {
+			if !g.checkQuota(&w, req, g.quotas.transfer(len(data))) || !g.checkQuota(&w, req, g.quotas.sdo(req.client)) {
+				return nil
+			}
+		}
+		err = g.WriteSDORaw(nodeId, entry.Index, subindex, data, block)
+		if err != nil {
+			w.Write(NewResponseError(int(req.sequence), err))
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONValues(t *testing.T) {
+	canBus, _ := network.NewBus("virtual", "localhost:18888", 0)
+	bus := canBus.(*virtual.Bus)
+	bus.SetReceiveOwn(true)
+	net := network.NewNetwork(bus)
+	err := net.Connect()
+	assert.Nil(t, err)
+	defer net.Disconnect()
+	odict := od.Default()
+	_, err = odict.AddObjectScannerList(0, 3)
+	assert.Nil(t, err)
+	local, err := net.CreateLocalNode(0x67, odict)
+	assert.Nil(t, err)
+	gw := NewGatewayServer(&net, nil, 1, 1, 100)
+	ts := httptest.NewServer(gw)
+	defer ts.Close()
+
+	sequence := 0
+	send := func(method string, node string, command string, body any, accept string) map[string]any {
+		sequence++
+		var encoded []byte
+		if body != nil {
+			encoded, _ = json.Marshal(body)
+		}
+		url := fmt.Sprintf("%s/cia309-5/1.0/%d/1/%s/%s", ts.URL, sequence, node, command)
+		req, _ := http.NewRequest(method, url, bytes.NewReader(encoded))
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+		decoded := map[string]any{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&decoded))
+		return decoded
+	}
+	read := func(command string) map[string]any {
+		return send(http.MethodGet, "0x67", command+"?format=json", nil, "")
+	}
+	write := func(command string, value any) map[string]any {
+		return send(http.MethodPut, "0x67", command+"?format=json", map[string]any{"value": value}, "")
+	}
+
+	t.Run("read", func(t *testing.T) {
+		resp := read("r/0x2001/0")
+		assert.Equal(t, "OK", resp["response"])
+		assert.Equal(t, true, resp["value"])
+		assert.Equal(t, "b", resp["datatype"])
+		assert.EqualValues(t, 0x4444, read("r/0x2003/0")["value"])
+		assert.InDelta(t, 0.1, read("r/0x2008/0")["value"], 1e-6)
+		assert.Equal(t, "AStringCannotBeLongerThanTheDefaultValue", read("r/0x2009/0")["value"])
+		// Whole entry without sub-index
+		assert.EqualValues(t, 0x4444, read("r/0x2003")["value"])
+		resp = read("r/0x1018")
+		assert.Equal(t, "OK", resp["response"])
+		assert.Len(t, resp["value"], 4)
+		assert.Nil(t, resp["datatype"])
+		// Accept header instead of query parameter
+		resp = send(http.MethodGet, "0x67", "rb/0x2005/0", nil, ValueMediaType)
+		assert.EqualValues(t, 0x10, resp["value"])
+		// Raw hex data is unchanged by default
+		resp = send(http.MethodGet, "0x67", "r/0x2005/0", nil, "")
+		assert.Equal(t, "0x10", resp["data"])
+	})
+
+	t.Run("write", func(t *testing.T) {
+		assert.Equal(t, "OK", write("w/0x2002/0", -5)["response"])
+		assert.EqualValues(t, -5, read("r/0x2002/0")["value"])
+		assert.Equal(t, "OK", write("w/0x2001/0", false)["response"])
+		assert.Equal(t, false, read("r/0x2001/0")["value"])
+		assert.Equal(t, "OK", write("wb/0x2007/0", "0x12345678")["response"])
+		assert.EqualValues(t, 0x12345678, read("r/0x2007/0")["value"])
+		assert.Equal(t, "OK", write("w/0x2011/0", 1.25)["response"])
+		assert.EqualValues(t, 1.25, read("r/0x2011/0")["value"])
+		assert.Equal(t, "OK", write("w/0x2009/0", "hello")["response"])
+		assert.Equal(t, "hello", read("r/0x2009/0")["value"])
+		// Arrays are written from sub-index 1
+		assert.Equal(t, "OK", write("w/0x1fa0", []any{1, 2})["response"])
+		assert.Equal(t, []any{1.0, 2.0, 0.0}, read("r/0x1fa0")["value"])
+		value, err := local.GetOD().Index(0x1FA0).Uint32(2)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, value)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		// Unknown OD
+		resp := send(http.MethodGet, "0x10", "r/0x2001/0?format=json", nil, "")
+		assert.Equal(t, "ERROR:100", resp["response"])
+		assert.Contains(t, resp["message"], "no object dictionary")
+		resp = read("r/0x4000/0")
+		assert.Equal(t, "ERROR:0x6020000", resp["response"])
+		resp = read("r/0x2001/5")
+		assert.Equal(t, "ERROR:0x6090011", resp["response"])
+		// Wrong JSON types
+		resp = write("w/0x2001/0", 1)
+		assert.Equal(t, "ERROR:0x6070010", resp["response"])
+		assert.Contains(t, resp["message"], "expected a boolean")
+		resp = write("w/0x2005/0", 300)
+		assert.Equal(t, "ERROR:0x6070010", resp["response"])
+		resp = write("w/0x2009/0", 12)
+		assert.Contains(t, resp["message"], "expected a string")
+		resp = write("w/0x1fa0", []any{1, 2, 3, 4})
+		assert.Equal(t, "ERROR:0x6070012", resp["response"])
+		resp = write("w/0x1fa0", 1)
+		assert.Equal(t, "ERROR:101", resp["response"])
+		// Nothing is written if an element is invalid
+		resp = write("w/0x1fa0", []any{7, "x"})
+		assert.Equal(t, "ERROR:0x6070010", resp["response"])
+		assert.EqualValues(t, 1.0, read("r/0x1fa0/1")["value"])
+	})
+}
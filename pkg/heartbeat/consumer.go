@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	canopen "github.com/samsamfire/gocanopen"
 	"github.com/samsamfire/gocanopen/pkg/emergency"
@@ -20,10 +21,12 @@ const (
 )
 
 const (
-	EventStarted = 0x01
-	EventTimeout = 0x02
-	EventChanged = 0x03
-	EventBoot    = 0x04
+	EventStarted   = 0x01
+	EventTimeout   = 0x02
+	EventChanged   = 0x03
+	EventBoot      = 0x04
+	EventRecovered = 0x05 // Heartbeat received again after a timeout
+	EventToggle    = 0x06 // Node guarding response with wrong toggle bit, response is ignored
 )
 
 // Node specific hearbeat consumer part
@@ -50,6 +53,7 @@ type HBConsumer struct {
 	allMonitoredOperational   bool
 	nmtIsPreOrOperationalPrev bool
 	eventCallback             HBEventCallback
+	listeners                 listeners
 }
 
 type HBEventCallback func(event uint8, index uint8, nodeId uint8, nmtState uint8)
@@ -111,26 +115,17 @@ func (consumer *HBConsumer) Process(nmtIsPreOrOperational bool, timeDifferenceUs
 					}
 					// Signal reboot
 					consumer.mu.Unlock()
-					if consumer.eventCallback != nil {
-						consumer.eventCallback(
-							EventBoot,
-							monitoredNode.nodeId,
-							uint8(i+1),
-							nmt.StateInitializing,
-						)
-					}
+					consumer.signal(EventBoot, i, monitoredNode)
 					consumer.mu.Lock()
 					monitoredNode.hbState = HeartbeatUnknown
 				} else {
-					// Signal Boot-up
+					// Signal Boot-up, or heartbeat back after timeout
 					consumer.mu.Unlock()
-					if monitoredNode.hbState != HeartbeatActive && consumer.eventCallback != nil {
-						consumer.eventCallback(
-							EventStarted,
-							monitoredNode.nodeId,
-							uint8(i+1),
-							nmt.StateInitializing,
-						)
+					switch monitoredNode.hbState {
+					case HeartbeatUnknown:
+						consumer.signal(EventStarted, i, monitoredNode)
+					case HeartbeatTimeout:
+						consumer.signal(EventRecovered, i, monitoredNode)
 					}
 					consumer.mu.Lock()
 					// Heartbeat message
@@ -150,14 +145,7 @@ func (consumer *HBConsumer) Process(nmtIsPreOrOperational bool, timeDifferenceUs
 					monitoredNode.hbState = HeartbeatTimeout
 					// Signal timeout
 					consumer.mu.Unlock()
-					if consumer.eventCallback != nil {
-						consumer.eventCallback(
-							EventTimeout,
-							monitoredNode.nodeId,
-							uint8(i+1),
-							nmt.StateUnknown,
-						)
-					}
+					consumer.signal(EventTimeout, i, monitoredNode)
 					consumer.mu.Lock()
 				} else if timerNextUs != nil {
 					// Calculate when to recheck
@@ -178,14 +166,7 @@ func (consumer *HBConsumer) Process(nmtIsPreOrOperational bool, timeDifferenceUs
 			if monitoredNode.nmtState != monitoredNode.nmtStatePrev {
 				// Signal NMT change
 				consumer.mu.Unlock()
-				if consumer.eventCallback != nil {
-					consumer.eventCallback(
-						EventChanged,
-						monitoredNode.nodeId,
-						uint8(i+1),
-						monitoredNode.nmtState,
-					)
-				}
+				consumer.signal(EventChanged, i, monitoredNode)
 				consumer.mu.Lock()
 				monitoredNode.nmtStatePrev = monitoredNode.nmtState
 			}
@@ -219,6 +200,21 @@ func (consumer *HBConsumer) Process(nmtIsPreOrOperational bool, timeDifferenceUs
 	consumer.nmtIsPreOrOperationalPrev = nmtIsPreOrOperational
 }
 
+// Signal an event of a monitored node to the callback & listeners.
+// Entry lock should be held, index is 0-based.
+func (consumer *HBConsumer) signal(event uint8, index int, entry *hbConsumerEntry) {
+	if consumer.eventCallback != nil {
+		consumer.eventCallback(event, uint8(index+1), entry.nodeId, entry.nmtState)
+	}
+	consumer.listeners.notify(Event{
+		Type:     event,
+		NodeId:   entry.nodeId,
+		Time:     time.Now(),
+		NmtState: entry.nmtState,
+		Previous: entry.nmtStatePrev,
+	})
+}
+
 // Add a consumer node, index is 0-based
 func (consumer *HBConsumer) updateConsumerEntry(index uint8, nodeId uint8, consumerTimeMs uint16) error {
 	if int(index) >= len(consumer.entries) {
@@ -253,6 +249,14 @@ func (consumer *HBConsumer) OnEvent(callback HBEventCallback) {
 	consumer.eventCallback = callback
 }
 
+// Detach stops receiving the heartbeats of the monitored nodes,
+// the consumer should no longer be used afterwards.
+func (consumer *HBConsumer) Detach() {
+	for _, entry := range consumer.entries {
+		consumer.Unsubscribe(entry)
+	}
+}
+
 func NewHBConsumer(bm *canopen.BusManager, logger *slog.Logger, emcy *emergency.EMCY, entry1016 *od.Entry) (*HBConsumer, error) {
 
 	if entry1016 == nil || bm == nil || emcy == nil {
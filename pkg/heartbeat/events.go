@@ -0,0 +1,106 @@
+package heartbeat
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Wildcard node id for receiving events of all monitored nodes
+const EventAllNodes uint8 = 0
+
+// Event of a monitored node
+type Event struct {
+	Type     uint8 // One of EventStarted, EventTimeout, EventChanged, EventBoot, EventRecovered
+	NodeId   uint8
+	Time     time.Time
+	NmtState uint8 // Current NMT state, nmt.StateUnknown on timeout
+	Previous uint8 // Previous NMT state, for EventChanged
+}
+
+type EventListener func(event Event)
+
+// Event listeners, keyed by node id
+type listeners struct {
+	mu        sync.Mutex
+	nextId    uint64
+	listeners map[uint8]map[uint64]EventListener
+}
+
+func (l *listeners) add(nodeId uint8, listener EventListener) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.listeners == nil {
+		l.listeners = make(map[uint8]map[uint64]EventListener)
+	}
+	if l.listeners[nodeId] == nil {
+		l.listeners[nodeId] = make(map[uint64]EventListener)
+	}
+	l.nextId++
+	l.listeners[nodeId][l.nextId] = listener
+	return l.nextId
+}
+
+func (l *listeners) remove(nodeId uint8, id uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.listeners[nodeId], id)
+}
+
+// Call listeners of the node & of all nodes, without holding the lock
+func (l *listeners) notify(event Event) {
+	l.mu.Lock()
+	called := make([]EventListener, 0)
+	for _, listener := range l.listeners[event.NodeId] {
+		called = append(called, listener)
+	}
+	for _, listener := range l.listeners[EventAllNodes] {
+		called = append(called, listener)
+	}
+	l.mu.Unlock()
+	for _, listener := range called {
+		listener(event)
+	}
+}
+
+// Returns a channel receiving the events of the node until ctx is cancelled.
+// Events are dropped if the channel is full.
+func (l *listeners) channel(ctx context.Context, nodeId uint8, size int) <-chan Event {
+	events := make(chan Event, size)
+	var mu sync.Mutex
+	closed := false
+	id := l.add(nodeId, func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case events <- event:
+		default:
+		}
+	})
+	go func() {
+		<-ctx.Done()
+		l.remove(nodeId, id)
+		mu.Lock()
+		defer mu.Unlock()
+		closed = true
+		close(events)
+	}()
+	return events
+}
+
+// OnNodeEvent registers a listener for the events of the monitored nodeId,
+// or of all monitored nodes with [EventAllNodes].
+// Listeners are called from the processing goroutine and should not block.
+func (consumer *HBConsumer) OnNodeEvent(nodeId uint8, listener EventListener) {
+	consumer.listeners.add(nodeId, listener)
+}
+
+// NodeEvents returns a channel receiving the events of the monitored nodeId,
+// or of all monitored nodes with [EventAllNodes]. The channel is closed when ctx is
+// cancelled. Events are dropped if the channel is full.
+func (consumer *HBConsumer) NodeEvents(ctx context.Context, nodeId uint8, size int) <-chan Event {
+	return consumer.listeners.channel(ctx, nodeId, size)
+}
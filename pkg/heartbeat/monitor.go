@@ -0,0 +1,287 @@
+package heartbeat
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+)
+
+// State of a node seen by a [Monitor]
+type monitoredNode struct {
+	hbState  uint8
+	nmtState uint8
+	timeout  time.Duration
+	timer    *time.Timer
+	// Node guarding
+	guarded     bool
+	guardStop   chan struct{}
+	toggle      uint8
+	toggleValid bool
+}
+
+// Monitor tracks the heartbeats of all the nodes on the network,
+// e.g. for NMT master use-cases. Unlike [HBConsumer], it does not need
+// any OD entry : every node sending a heartbeat is tracked.
+// Heartbeat loss is only detected for nodes with a timeout, see [Monitor.SetTimeout].
+// Legacy devices can also be monitored with node guarding, see [Monitor.Guard].
+type Monitor struct {
+	bm        *canopen.BusManager
+	mu        sync.Mutex
+	nodes     map[uint8]*monitoredNode
+	listeners listeners
+}
+
+func NewMonitor(bm *canopen.BusManager) (*Monitor, error) {
+	nodeIds := make([]uint8, 0, 127)
+	for nodeId := uint8(1); nodeId <= 127; nodeId++ {
+		nodeIds = append(nodeIds, nodeId)
+	}
+	return NewNodeMonitor(bm, nodeIds...)
+}
+
+// NewNodeMonitor creates a [Monitor] that only tracks the given nodes,
+// e.g. a single remote node.
+func NewNodeMonitor(bm *canopen.BusManager, nodeIds ...uint8) (*Monitor, error) {
+	if bm == nil {
+		return nil, canopen.ErrIllegalArgument
+	}
+	monitor := &Monitor{bm: bm, nodes: make(map[uint8]*monitoredNode)}
+	for _, nodeId := range nodeIds {
+		if nodeId == 0 || nodeId > 127 {
+			return nil, canopen.ErrIllegalArgument
+		}
+		err := bm.Subscribe(ServiceId+uint32(nodeId), 0x7FF, false, monitor)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return monitor, nil
+}
+
+func (monitor *Monitor) node(nodeId uint8) *monitoredNode {
+	node, ok := monitor.nodes[nodeId]
+	if !ok {
+		node = &monitoredNode{hbState: HeartbeatUnknown, nmtState: nmt.StateUnknown}
+		monitor.nodes[nodeId] = node
+	}
+	return node
+}
+
+// Handle heartbeats of all nodes
+func (monitor *Monitor) Handle(frame canopen.Frame) {
+	if frame.DLC != 1 {
+		return
+	}
+	nodeId := uint8(frame.ID - ServiceId)
+	nmtState := frame.Data[0]
+	now := time.Now()
+	events := make([]Event, 0, 2)
+
+	monitor.mu.Lock()
+	node := monitor.node(nodeId)
+	previous := node.nmtState
+	if node.guarded {
+		// Node guarding response, toggle bit should alternate
+		toggle := nmtState & 0x80
+		nmtState &= 0x7F
+		if node.toggleValid && toggle == node.toggle {
+			monitor.mu.Unlock()
+			monitor.listeners.notify(Event{Type: EventToggle, NodeId: nodeId, Time: now, NmtState: nmtState, Previous: previous})
+			return
+		}
+		node.toggle = toggle
+		node.toggleValid = true
+	}
+	switch {
+	case nmtState == nmt.StateInitializing:
+		events = append(events, Event{Type: EventBoot, NmtState: nmtState, Previous: previous})
+	case node.hbState == HeartbeatUnknown:
+		events = append(events, Event{Type: EventStarted, NmtState: nmtState, Previous: previous})
+	case node.hbState == HeartbeatTimeout:
+		events = append(events, Event{Type: EventRecovered, NmtState: nmtState, Previous: previous})
+	}
+	if nmtState != previous {
+		events = append(events, Event{Type: EventChanged, NmtState: nmtState, Previous: previous})
+	}
+	node.nmtState = nmtState
+	if nmtState == nmt.StateInitializing {
+		node.hbState = HeartbeatUnknown
+	} else {
+		node.hbState = HeartbeatActive
+	}
+	monitor.restartTimer(nodeId, node)
+	monitor.mu.Unlock()
+
+	for _, event := range events {
+		event.NodeId = nodeId
+		event.Time = now
+		monitor.listeners.notify(event)
+	}
+}
+
+// Restart timeout detection of an active node, monitor lock should be held
+func (monitor *Monitor) restartTimer(nodeId uint8, node *monitoredNode) {
+	if node.hbState != HeartbeatActive {
+		monitor.stopTimer(node)
+		return
+	}
+	monitor.startTimer(nodeId, node)
+}
+
+func (monitor *Monitor) stopTimer(node *monitoredNode) {
+	if node.timer != nil {
+		node.timer.Stop()
+		node.timer = nil
+	}
+}
+
+// Start timeout detection of node, monitor lock should be held
+func (monitor *Monitor) startTimer(nodeId uint8, node *monitoredNode) {
+	monitor.stopTimer(node)
+	if node.timeout == 0 {
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(node.timeout, func() {
+		monitor.mu.Lock()
+		// Timer was restarted or stopped in the meantime
+		if node.timer != timer {
+			monitor.mu.Unlock()
+			return
+		}
+		previous := node.nmtState
+		node.hbState = HeartbeatTimeout
+		node.nmtState = nmt.StateUnknown
+		node.timer = nil
+		// Responses may have been lost, accept next toggle
+		node.toggleValid = false
+		monitor.mu.Unlock()
+		monitor.listeners.notify(Event{
+			Type:     EventTimeout,
+			NodeId:   nodeId,
+			Time:     time.Now(),
+			NmtState: nmt.StateUnknown,
+			Previous: previous,
+		})
+	})
+	node.timer = timer
+}
+
+// SetTimeout sets the time after which the heartbeat of nodeId is considered lost,
+// typically a bit more than its heartbeat period. 0 disables timeout detection.
+func (monitor *Monitor) SetTimeout(nodeId uint8, timeout time.Duration) {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	node := monitor.node(nodeId)
+	node.timeout = timeout
+	monitor.restartTimer(nodeId, node)
+}
+
+// State returns the last NMT state received from nodeId,
+// nmt.StateUnknown if none or if heartbeat was lost.
+func (monitor *Monitor) State(nodeId uint8) uint8 {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	node, ok := monitor.nodes[nodeId]
+	if !ok {
+		return nmt.StateUnknown
+	}
+	return node.nmtState
+}
+
+// States returns the last NMT state of every node with a known state
+func (monitor *Monitor) States() map[uint8]uint8 {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	states := make(map[uint8]uint8)
+	for nodeId, node := range monitor.nodes {
+		if node.nmtState != nmt.StateUnknown {
+			states[nodeId] = node.nmtState
+		}
+	}
+	return states
+}
+
+// OnNodeEvent registers a listener for the events of nodeId,
+// or of all nodes with [EventAllNodes].
+// Listeners are called from the CAN reception and should not block.
+func (monitor *Monitor) OnNodeEvent(nodeId uint8, listener EventListener) {
+	monitor.listeners.add(nodeId, listener)
+}
+
+// NodeEvents returns a channel receiving the events of nodeId, or of all
+// nodes with [EventAllNodes]. The channel is closed when ctx is cancelled.
+// Events are dropped if the channel is full.
+func (monitor *Monitor) NodeEvents(ctx context.Context, nodeId uint8, size int) <-chan Event {
+	return monitor.listeners.channel(ctx, nodeId, size)
+}
+
+// Guard starts node guarding of nodeId : an RTR frame is sent every guardTime
+// and the node is considered lost if no valid response is received within
+// guardTime x lifeTimeFactor. This replaces any timeout set with [Monitor.SetTimeout].
+// It should only be used for nodes that do not produce heartbeats.
+func (monitor *Monitor) Guard(nodeId uint8, guardTime time.Duration, lifeTimeFactor uint8) error {
+	if guardTime <= 0 || lifeTimeFactor == 0 {
+		return canopen.ErrIllegalArgument
+	}
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	node := monitor.node(nodeId)
+	monitor.stopGuarding(node)
+	node.guarded = true
+	node.toggleValid = false
+	node.hbState = HeartbeatUnknown
+	node.timeout = guardTime * time.Duration(lifeTimeFactor)
+	node.guardStop = make(chan struct{})
+	// Lost if no response at all
+	monitor.startTimer(nodeId, node)
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(guardTime)
+		defer ticker.Stop()
+		rtr := canopen.NewFrame((ServiceId+uint32(nodeId))|canopen.CanRtrFlag, 0, 1)
+		for {
+			_ = monitor.bm.Send(rtr)
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}(node.guardStop)
+	return nil
+}
+
+// Unguard stops node guarding of nodeId
+func (monitor *Monitor) Unguard(nodeId uint8) {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	node, ok := monitor.nodes[nodeId]
+	if !ok || !node.guarded {
+		return
+	}
+	monitor.stopGuarding(node)
+	node.timeout = 0
+	monitor.stopTimer(node)
+}
+
+// Stop sending RTRs, monitor lock should be held
+func (monitor *Monitor) stopGuarding(node *monitoredNode) {
+	if node.guardStop != nil {
+		close(node.guardStop)
+		node.guardStop = nil
+	}
+	node.guarded = false
+}
+
+// Stop all timeout detections & node guarding
+func (monitor *Monitor) Stop() {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	for _, node := range monitor.nodes {
+		monitor.stopGuarding(node)
+		monitor.stopTimer(node)
+	}
+}
@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+const DefaultBusLogSize = 4096
+
+// Options for [BusHandler]
+type BusHandlerOptions struct {
+	// Minimum level of the records to store. Defaults to [slog.LevelInfo]
+	Level slog.Leveler
+	// Services to store e.g. "[EMCY]", "[NMT]".
+	// If empty, all services are stored. Errors are always stored.
+	Services []string
+}
+
+// BusHandler is a [slog.Handler] that stores log records as text lines inside of
+// a bounded buffer. When full, the oldest lines are discarded.
+// It implements [io.ReadSeeker] so that it can be added to the OD
+// with [BusHandler.AddToOD] and read over the bus by SDO.
+type BusHandler struct {
+	log  *busLog
+	text slog.Handler
+	opts BusHandlerOptions
+	// Service attribute of this handler if any
+	service string
+}
+
+// Lines shared by a handler and its derived handlers
+type busLog struct {
+	mu       sync.Mutex
+	lines    [][]byte
+	size     int
+	maxSize  int
+	snapshot *bytes.Reader
+}
+
+// Implements io.Writer, this is called by the underlying text handler once per record
+func (l *busLog) Write(p []byte) (n int, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	line := bytes.Clone(p)
+	if len(line) > l.maxSize {
+		line = line[len(line)-l.maxSize:]
+	}
+	l.lines = append(l.lines, line)
+	l.size += len(line)
+	for l.size > l.maxSize {
+		l.size -= len(l.lines[0])
+		l.lines = l.lines[1:]
+	}
+	return len(p), nil
+}
+
+// Create a new [BusHandler] storing at most size bytes of logs
+func NewBusHandler(size int, opts *BusHandlerOptions) *BusHandler {
+	if size <= 0 {
+		size = DefaultBusLogSize
+	}
+	if opts == nil {
+		opts = &BusHandlerOptions{}
+	}
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+	log := &busLog{maxSize: size, snapshot: bytes.NewReader(nil)}
+	return &BusHandler{
+		log:  log,
+		text: slog.NewTextHandler(log, &slog.HandlerOptions{Level: opts.Level}),
+		opts: *opts,
+	}
+}
+
+// Add the log buffer to the OD as a read only DOMAIN entry at the given index.
+// Typically a manufacturer specific index should be used e.g. 0x2FFF
+func (h *BusHandler) AddToOD(odict *od.ObjectDictionary, index uint16, name string) {
+	odict.AddReader(index, name, h)
+}
+
+// Remove all the stored log lines
+func (h *BusHandler) Clear() {
+	h.log.mu.Lock()
+	defer h.log.mu.Unlock()
+	h.log.lines = nil
+	h.log.size = 0
+}
+
+// Returns a copy of all the stored log lines
+func (h *BusHandler) Bytes() []byte {
+	h.log.mu.Lock()
+	defer h.log.mu.Unlock()
+	return bytes.Join(h.log.lines, nil)
+}
+
+// Implements io.Reader, reads from the last snapshot taken by [BusHandler.Seek]
+func (h *BusHandler) Read(p []byte) (n int, err error) {
+	h.log.mu.Lock()
+	defer h.log.mu.Unlock()
+	return h.log.snapshot.Read(p)
+}
+
+// Implements io.Seeker, seeking to start takes a new snapshot of the stored logs.
+// This is done by the OD on each new SDO upload.
+func (h *BusHandler) Seek(offset int64, whence int) (int64, error) {
+	h.log.mu.Lock()
+	defer h.log.mu.Unlock()
+	if offset == 0 && whence == io.SeekStart {
+		h.log.snapshot = bytes.NewReader(bytes.Join(h.log.lines, nil))
+	}
+	return h.log.snapshot.Seek(offset, whence)
+}
+
+func (h *BusHandler) selected(service string, level slog.Level) bool {
+	if level >= slog.LevelError || len(h.opts.Services) == 0 {
+		return true
+	}
+	return slices.Contains(h.opts.Services, service)
+}
+
+func (h *BusHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.text.Enabled(ctx, level)
+}
+
+func (h *BusHandler) Handle(ctx context.Context, record slog.Record) error {
+	service := h.service
+	record.Attrs(func(a slog.Attr) bool {
+		if a.Key == "service" {
+			service = a.Value.String()
+			return false
+		}
+		return true
+	})
+	if !h.selected(service, record.Level) {
+		return nil
+	}
+	return h.text.Handle(ctx, record)
+}
+
+func (h *BusHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *h
+	derived.text = h.text.WithAttrs(attrs)
+	for _, a := range attrs {
+		if a.Key == "service" {
+			derived.service = a.Value.String()
+		}
+	}
+	return &derived
+}
+
+func (h *BusHandler) WithGroup(name string) slog.Handler {
+	derived := *h
+	derived.text = h.text.WithGroup(name)
+	return &derived
+}
@@ -0,0 +1,119 @@
+// Package logging provides [slog.Handler] implementations for the CANopen stack.
+//
+// [Handler] allows switching log destinations at runtime, after the stack
+// has been created with a given logger.
+// [BusHandler] stores selected log events inside of a buffer that can be
+// added to the object dictionary as a DOMAIN entry, so that device side logs
+// can be retrieved over the bus with SDO.
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// Handler is a [slog.Handler] that forwards records to a set of destinations.
+// Destinations can be changed at any time with [Handler.SetDestinations], this also
+// applies to the loggers that have already been derived with [slog.Logger.With].
+type Handler struct {
+	router *router
+	ops    []op
+}
+
+// Shared destinations between a handler and its derived handlers
+type router struct {
+	mu    sync.RWMutex
+	dests []slog.Handler
+}
+
+// Operation applied on a destination by a derived handler
+type op struct {
+	group string
+	attrs []slog.Attr
+}
+
+// Create a new [Handler] with the given destinations
+func NewHandler(dests ...slog.Handler) *Handler {
+	h := &Handler{router: &router{}}
+	h.SetDestinations(dests...)
+	return h
+}
+
+// Replace all the current destinations with the given ones.
+// No destinations means that logs are discarded.
+func (h *Handler) SetDestinations(dests ...slog.Handler) {
+	h.router.mu.Lock()
+	defer h.router.mu.Unlock()
+	h.router.dests = append([]slog.Handler{}, dests...)
+}
+
+// Add a destination to the current destinations
+func (h *Handler) AddDestination(dest slog.Handler) {
+	h.router.mu.Lock()
+	defer h.router.mu.Unlock()
+	h.router.dests = append(h.router.dests, dest)
+}
+
+// Returns the current destinations
+func (h *Handler) Destinations() []slog.Handler {
+	h.router.mu.RLock()
+	defer h.router.mu.RUnlock()
+	return append([]slog.Handler{}, h.router.dests...)
+}
+
+// Apply attributes and groups of derived handler to destination
+func (h *Handler) derive(dest slog.Handler) slog.Handler {
+	for _, op := range h.ops {
+		if op.group != "" {
+			dest = dest.WithGroup(op.group)
+		} else {
+			dest = dest.WithAttrs(op.attrs)
+		}
+	}
+	return dest
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	h.router.mu.RLock()
+	defer h.router.mu.RUnlock()
+	for _, dest := range h.router.dests {
+		if dest.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	h.router.mu.RLock()
+	dests := h.router.dests
+	h.router.mu.RUnlock()
+
+	var errs []error
+	for _, dest := range dests {
+		if !dest.Enabled(ctx, record.Level) {
+			continue
+		}
+		err := h.derive(dest).Handle(ctx, record.Clone())
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &Handler{router: h.router, ops: append(h.ops[:len(h.ops):len(h.ops)], op{attrs: attrs})}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{router: h.router, ops: append(h.ops[:len(h.ops):len(h.ops)], op{group: name})}
+}
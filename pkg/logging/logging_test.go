@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerSwitch(t *testing.T) {
+	buf1 := &bytes.Buffer{}
+	buf2 := &bytes.Buffer{}
+	h := NewHandler(slog.NewTextHandler(buf1, nil))
+	logger := slog.New(h).With("service", "[NMT]")
+	logger.Info("first")
+	assert.Contains(t, buf1.String(), "first")
+	assert.Contains(t, buf1.String(), "service=[NMT]")
+	// Derived logger should follow new destinations
+	h.SetDestinations(slog.NewTextHandler(buf2, nil))
+	logger.Info("second")
+	assert.NotContains(t, buf1.String(), "second")
+	assert.Contains(t, buf2.String(), "second")
+	assert.Contains(t, buf2.String(), "service=[NMT]")
+	h.SetDestinations()
+	logger.Info("third")
+	assert.NotContains(t, buf2.String(), "third")
+}
+
+func TestBusHandler(t *testing.T) {
+	bus := NewBusHandler(1000, &BusHandlerOptions{Services: []string{"[EMCY]"}})
+	logger := slog.New(bus)
+	t.Run("filter", func(t *testing.T) {
+		logger.With("service", "[EMCY]").Info("emcy")
+		logger.With("service", "[SDO]").Info("sdo")
+		logger.With("service", "[SDO]").Error("sdo error")
+		logger.Debug("debug", "service", "[EMCY]")
+		logs := string(bus.Bytes())
+		assert.Contains(t, logs, "emcy")
+		assert.Contains(t, logs, "sdo error")
+		assert.NotContains(t, logs, "msg=sdo\n")
+		assert.NotContains(t, logs, "debug")
+	})
+	t.Run("bounded", func(t *testing.T) {
+		small := NewBusHandler(100, nil)
+		for range 20 {
+			slog.New(small).Error("x")
+		}
+		assert.LessOrEqual(t, len(small.Bytes()), 100)
+		assert.Greater(t, len(small.Bytes()), 0)
+	})
+	t.Run("read from od", func(t *testing.T) {
+		odict := od.Default()
+		bus.AddToOD(odict, 0x2FFF, "Device log")
+		entry := odict.Index(0x2FFF)
+		assert.NotNil(t, entry)
+		streamer, err := odict.Streamer(0x2FFF, 0, false)
+		assert.Nil(t, err)
+		data := make([]byte, 1000)
+		n, err := streamer.Read(data)
+		assert.Nil(t, err)
+		assert.Equal(t, bus.Bytes(), data[:n])
+		// Logs written after the start of a read are not part of it
+		logger.Error("new")
+		_, err = bus.Seek(0, io.SeekStart)
+		assert.Nil(t, err)
+		all, err := io.ReadAll(bus)
+		assert.Nil(t, err)
+		assert.Equal(t, bus.Bytes(), all)
+	})
+}
@@ -0,0 +1,290 @@
+// Package lss implements a CiA 305 Layer Setting Services (LSS) master.
+// LSS is used to configure the node-id and bit timing of a device
+// over the CAN bus, without the need of an SDO server.
+package lss
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+)
+
+const (
+	ServiceIdMaster = 0x7E5 // Master to slave
+	ServiceIdSlave  = 0x7E4 // Slave to master
+)
+
+const DefaultTimeout = 1000 * time.Millisecond
+
+// LSS command specifiers
+const (
+	csSwitchStateGlobal     uint8 = 0x04
+	csConfigureNodeId       uint8 = 0x11
+	csConfigureBitTiming    uint8 = 0x13
+	csActivateBitTiming     uint8 = 0x15
+	csStoreConfiguration    uint8 = 0x17
+	csSwitchStateSelVendor  uint8 = 0x40
+	csSwitchStateSelProduct uint8 = 0x41
+	csSwitchStateSelRev     uint8 = 0x42
+	csSwitchStateSelSerial  uint8 = 0x43
+	csSwitchStateSelResult  uint8 = 0x44
+	csInquireVendor         uint8 = 0x5A
+	csInquireProduct        uint8 = 0x5B
+	csInquireRev            uint8 = 0x5C
+	csInquireSerial         uint8 = 0x5D
+	csInquireNodeId         uint8 = 0x5E
+)
+
+// LSS states
+const (
+	StateWaiting       uint8 = 0
+	StateConfiguration uint8 = 1
+)
+
+const NodeIdUnconfigured uint8 = 0xFF
+
+var (
+	ErrTimeout          = errors.New("lss : no response from slave")
+	ErrNodeIdInvalid    = errors.New("lss : node-id is out of range")
+	ErrBitrateInvalid   = errors.New("lss : bitrate is not supported")
+	ErrStoreUnsupported = errors.New("lss : store configuration is not supported")
+	ErrStoreFailed      = errors.New("lss : storage media access error")
+)
+
+// CiA 305 standard bit timing table, index is the table index
+var BitrateTable = map[int]uint8{
+	1_000_000: 0,
+	800_000:   1,
+	500_000:   2,
+	250_000:   3,
+	125_000:   4,
+	50_000:    6,
+	20_000:    7,
+	10_000:    8,
+}
+
+// Address used to select a single slave (LSS address)
+// It corresponds to the identity object (0x1018) of the slave
+type Address struct {
+	VendorId       uint32
+	ProductCode    uint32
+	RevisionNumber uint32
+	SerialNumber   uint32
+}
+
+// Error returned by slave on configuration commands
+type ConfigurationError struct {
+	Command  uint8
+	Code     uint8
+	Specific uint8
+}
+
+func (e *ConfigurationError) Error() string {
+	return fmt.Sprintf("lss : configuration command x%x failed with code %v (specific %v)", e.Command, e.Code, e.Specific)
+}
+
+// LSSMaster is used to configure slaves supporting LSS
+type LSSMaster struct {
+	*canopen.BusManager
+	logger  *slog.Logger
+	mu      sync.Mutex
+	rx      chan canopen.Frame
+	timeout time.Duration
+}
+
+// Handle [LSSMaster] related RX CAN frames
+func (master *LSSMaster) Handle(frame canopen.Frame) {
+	if frame.DLC != 8 {
+		return
+	}
+	select {
+	case master.rx <- frame:
+	default:
+		master.logger.Warn("dropped LSS slave frame")
+	}
+}
+
+// Set timeout for waiting slave responses
+func (master *LSSMaster) SetTimeout(timeout time.Duration) {
+	master.mu.Lock()
+	defer master.mu.Unlock()
+	master.timeout = timeout
+}
+
+// Send a command and wait for the response with the given command specifier if any
+func (master *LSSMaster) request(data [8]byte, response uint8, expectResponse bool) (canopen.Frame, error) {
+	// Flush any old response
+	for len(master.rx) > 0 {
+		<-master.rx
+	}
+	frame := canopen.NewFrame(ServiceIdMaster, 0, 8)
+	frame.Data = data
+	err := master.Send(frame)
+	if err != nil || !expectResponse {
+		return canopen.Frame{}, err
+	}
+	timer := time.NewTimer(master.timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case rx := <-master.rx:
+			if rx.Data[0] == response {
+				return rx, nil
+			}
+		case <-timer.C:
+			return canopen.Frame{}, ErrTimeout
+		}
+	}
+}
+
+// Check the result of a configuration command
+func (master *LSSMaster) configure(data [8]byte) error {
+	resp, err := master.request(data, data[0], true)
+	if err != nil {
+		return err
+	}
+	if resp.Data[1] != 0 {
+		return &ConfigurationError{Command: data[0], Code: resp.Data[1], Specific: resp.Data[2]}
+	}
+	return nil
+}
+
+// Switch all slaves to given state (waiting or configuration).
+// There is no response from slaves for this command
+func (master *LSSMaster) SwitchStateGlobal(state uint8) error {
+	master.mu.Lock()
+	defer master.mu.Unlock()
+	master.logger.Info("[TX] switch state global", "state", state)
+	_, err := master.request([8]byte{csSwitchStateGlobal, state}, 0, false)
+	return err
+}
+
+// Switch a single slave, identified by its address, to configuration state
+func (master *LSSMaster) SwitchStateSelective(address Address) error {
+	master.mu.Lock()
+	defer master.mu.Unlock()
+	master.logger.Info("[TX] switch state selective", "address", address)
+	values := []uint32{address.VendorId, address.ProductCode, address.RevisionNumber, address.SerialNumber}
+	for i, value := range values {
+		cs := csSwitchStateSelVendor + uint8(i)
+		data := [8]byte{cs, uint8(value), uint8(value >> 8), uint8(value >> 16), uint8(value >> 24)}
+		// Only last command expects a response
+		_, err := master.request(data, csSwitchStateSelResult, cs == csSwitchStateSelSerial)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Configure node-id of slave in configuration state.
+// Node-id can be 1-127 or 0xFF for unconfigured
+func (master *LSSMaster) ConfigureNodeId(nodeId uint8) error {
+	if (nodeId < 1 || nodeId > 127) && nodeId != NodeIdUnconfigured {
+		return ErrNodeIdInvalid
+	}
+	master.mu.Lock()
+	defer master.mu.Unlock()
+	master.logger.Info("[TX] configure node-id", "nodeId", nodeId)
+	return master.configure([8]byte{csConfigureNodeId, nodeId})
+}
+
+// Configure bit timing of slave in configuration state, using CiA 305 standard table
+func (master *LSSMaster) ConfigureBitTiming(bitrate int) error {
+	index, ok := BitrateTable[bitrate]
+	if !ok {
+		return ErrBitrateInvalid
+	}
+	master.mu.Lock()
+	defer master.mu.Unlock()
+	master.logger.Info("[TX] configure bit timing", "bitrate", bitrate, "index", index)
+	return master.configure([8]byte{csConfigureBitTiming, 0, index})
+}
+
+// Activate new bit timing on all slaves in configuration state.
+// Slaves will wait for delay before switching and again delay after switching,
+// before transmitting any frame.
+func (master *LSSMaster) ActivateBitTiming(delay time.Duration) error {
+	master.mu.Lock()
+	defer master.mu.Unlock()
+	delayMs := uint16(delay.Milliseconds())
+	master.logger.Info("[TX] activate bit timing", "delayMs", delayMs)
+	_, err := master.request([8]byte{csActivateBitTiming, uint8(delayMs), uint8(delayMs >> 8)}, 0, false)
+	return err
+}
+
+// Store configured node-id and bit timing in slave's non volatile memory
+func (master *LSSMaster) StoreConfiguration() error {
+	master.mu.Lock()
+	defer master.mu.Unlock()
+	master.logger.Info("[TX] store configuration")
+	err := master.configure([8]byte{csStoreConfiguration})
+	if confErr, ok := err.(*ConfigurationError); ok {
+		switch confErr.Code {
+		case 1:
+			return ErrStoreUnsupported
+		case 2:
+			return ErrStoreFailed
+		}
+	}
+	return err
+}
+
+// Inquire node-id of slave in configuration state
+func (master *LSSMaster) InquireNodeId() (uint8, error) {
+	master.mu.Lock()
+	defer master.mu.Unlock()
+	resp, err := master.request([8]byte{csInquireNodeId}, csInquireNodeId, true)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Data[1], nil
+}
+
+// Inquire full LSS address of slave in configuration state
+func (master *LSSMaster) InquireAddress() (Address, error) {
+	master.mu.Lock()
+	defer master.mu.Unlock()
+	values := [4]uint32{}
+	for i := range values {
+		cs := csInquireVendor + uint8(i)
+		resp, err := master.request([8]byte{cs}, cs, true)
+		if err != nil {
+			return Address{}, err
+		}
+		values[i] = uint32(resp.Data[1]) | uint32(resp.Data[2])<<8 | uint32(resp.Data[3])<<16 | uint32(resp.Data[4])<<24
+	}
+	return Address{
+		VendorId:       values[0],
+		ProductCode:    values[1],
+		RevisionNumber: values[2],
+		SerialNumber:   values[3],
+	}, nil
+}
+
+func NewLSSMaster(bm *canopen.BusManager, logger *slog.Logger, timeout time.Duration) (*LSSMaster, error) {
+	if bm == nil {
+		return nil, canopen.ErrIllegalArgument
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	master := &LSSMaster{
+		BusManager: bm,
+		logger:     logger.With("service", "[LSS]"),
+		rx:         make(chan canopen.Frame, 10),
+		timeout:    timeout,
+	}
+	err := master.Subscribe(ServiceIdSlave, 0x7FF, false, master)
+	if err != nil {
+		return nil, err
+	}
+	return master, nil
+}
@@ -0,0 +1,117 @@
+package lss
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/stretchr/testify/assert"
+)
+
+// Minimal LSS slave, answering directly on send
+type slaveBus struct {
+	bm       *canopen.BusManager
+	address  Address
+	selected [4]bool
+	state    uint8
+	nodeId   uint8
+	bitIndex uint8
+	stored   bool
+}
+
+func (b *slaveBus) Connect(...any) error                           { return nil }
+func (b *slaveBus) Disconnect() error                              { return nil }
+func (b *slaveBus) Subscribe(callback canopen.FrameListener) error { return nil }
+
+func (b *slaveBus) respond(data [8]byte) {
+	frame := canopen.NewFrame(ServiceIdSlave, 0, 8)
+	frame.Data = data
+	go b.bm.Handle(frame)
+}
+
+func (b *slaveBus) Send(frame canopen.Frame) error {
+	if frame.ID != ServiceIdMaster {
+		return nil
+	}
+	cs := frame.Data[0]
+	values := []uint32{b.address.VendorId, b.address.ProductCode, b.address.RevisionNumber, b.address.SerialNumber}
+	switch {
+	case cs == csSwitchStateGlobal:
+		b.state = frame.Data[1]
+	case cs >= csSwitchStateSelVendor && cs <= csSwitchStateSelSerial:
+		i := cs - csSwitchStateSelVendor
+		b.selected[i] = binary.LittleEndian.Uint32(frame.Data[1:5]) == values[i]
+		if cs == csSwitchStateSelSerial && b.selected == [4]bool{true, true, true, true} {
+			b.state = StateConfiguration
+			b.respond([8]byte{csSwitchStateSelResult})
+		}
+	case b.state != StateConfiguration:
+		return nil
+	case cs == csConfigureNodeId:
+		b.nodeId = frame.Data[1]
+		b.respond([8]byte{cs})
+	case cs == csConfigureBitTiming:
+		b.bitIndex = frame.Data[2]
+		b.respond([8]byte{cs})
+	case cs == csStoreConfiguration:
+		b.stored = true
+		b.respond([8]byte{cs})
+	case cs == csInquireNodeId:
+		b.respond([8]byte{cs, b.nodeId})
+	case cs >= csInquireVendor && cs <= csInquireSerial:
+		data := [8]byte{cs}
+		binary.LittleEndian.PutUint32(data[1:5], values[cs-csInquireVendor])
+		b.respond(data)
+	}
+	return nil
+}
+
+func newMasterTest(t *testing.T) (*LSSMaster, *slaveBus) {
+	bus := &slaveBus{
+		address: Address{VendorId: 0x10, ProductCode: 0x20, RevisionNumber: 0x30, SerialNumber: 0x40},
+		nodeId:  NodeIdUnconfigured,
+	}
+	bus.bm = canopen.NewBusManager(bus)
+	master, err := NewLSSMaster(bus.bm, nil, 100*time.Millisecond)
+	assert.Nil(t, err)
+	return master, bus
+}
+
+func TestLSSMaster(t *testing.T) {
+	master, slave := newMasterTest(t)
+
+	t.Run("select wrong address", func(t *testing.T) {
+		err := master.SwitchStateSelective(Address{VendorId: 0x10, SerialNumber: 0x1})
+		assert.Equal(t, ErrTimeout, err)
+		_, err = master.InquireNodeId()
+		assert.Equal(t, ErrTimeout, err)
+	})
+
+	t.Run("configure node id", func(t *testing.T) {
+		assert.Nil(t, master.SwitchStateSelective(slave.address))
+		assert.Equal(t, ErrNodeIdInvalid, master.ConfigureNodeId(0))
+		assert.Equal(t, ErrNodeIdInvalid, master.ConfigureNodeId(128))
+		assert.Nil(t, master.ConfigureNodeId(0x22))
+		nodeId, err := master.InquireNodeId()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x22, nodeId)
+		assert.Nil(t, master.StoreConfiguration())
+		assert.True(t, slave.stored)
+	})
+
+	t.Run("configure bit timing", func(t *testing.T) {
+		assert.Equal(t, ErrBitrateInvalid, master.ConfigureBitTiming(12345))
+		assert.Nil(t, master.ConfigureBitTiming(125_000))
+		assert.EqualValues(t, 4, slave.bitIndex)
+	})
+
+	t.Run("inquire address", func(t *testing.T) {
+		address, err := master.InquireAddress()
+		assert.Nil(t, err)
+		assert.Equal(t, slave.address, address)
+		assert.Nil(t, master.SwitchStateGlobal(StateWaiting))
+		_, err = master.InquireAddress()
+		assert.Equal(t, ErrTimeout, err)
+	})
+}
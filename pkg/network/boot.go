@@ -0,0 +1,254 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// NMT startup (0x1F80) bits, see CiA 302-2
+const (
+	StartupNMTMaster     uint32 = 1 << 0 // Device is the NMT master
+	StartupStartAll      uint32 = 1 << 1 // Start all nodes with a single broadcast command
+	StartupNoSlavesStart uint32 = 1 << 3 // Slaves are started by the application
+)
+
+// NMT slave assignment (0x1F81) bits, see CiA 302-2
+const (
+	AssignmentSlave     uint32 = 1 << 0 // Node is an NMT slave of the network
+	AssignmentMandatory uint32 = 1 << 2 // Network is not started if node fails to boot
+)
+
+// Boot status of a slave, see [Network.Boot]
+const (
+	BootOK            uint8 = 0x00
+	BootNoResponse    uint8 = 0x01 // Device type (0x1000) could not be read
+	BootDeviceType    uint8 = 0x02 // Device type does not match 0x1F84
+	BootIdentity      uint8 = 0x03 // Identity (0x1018) does not match 0x1F85 - 0x1F88
+	BootConfiguration uint8 = 0x04 // Concise DCF download failed
+	BootNotStarted    uint8 = 0x05 // Booted but not started, e.g. a mandatory slave failed
+)
+
+var BootStatusDescription = map[uint8]string{
+	BootOK:            "OK",
+	BootNoResponse:    "NO RESPONSE",
+	BootDeviceType:    "DEVICE TYPE MISMATCH",
+	BootIdentity:      "IDENTITY MISMATCH",
+	BootConfiguration: "CONFIGURATION FAILED",
+	BootNotStarted:    "NOT STARTED",
+}
+
+var (
+	ErrBootNotMaster = errors.New("device is not configured as NMT master (0x1F80)")
+	ErrBootMandatory = errors.New("a mandatory slave failed to boot")
+)
+
+// BootSlave is the expected state & configuration of an NMT slave
+type BootSlave struct {
+	Assignment uint32 // NMT slave assignment (0x1F81)
+	DeviceType uint32 // Expected device type (0x1F84), 0 is not checked
+	// Expected identity (0x1F85 - 0x1F88), fields set to 0 are not checked
+	Identity config.Identity
+	// Downloaded to the slave before starting it, if not empty (0x1F22)
+	ConciseDCF []byte
+}
+
+// BootConfig holds the configuration of the boot-up procedure
+type BootConfig struct {
+	Startup uint32 // NMT startup (0x1F80)
+	// Max time to wait for mandatory slaves (0x1F89), 0 waits until context is cancelled
+	BootTime time.Duration
+	Slaves   map[uint8]BootSlave
+}
+
+// BootReport is the result of the boot-up procedure for a single slave
+type BootReport struct {
+	NodeId     uint8
+	Mandatory  bool
+	Status     uint8
+	DeviceType uint32
+	Identity   *config.Identity
+	Err        error
+}
+
+// Returns true if slave has booted & has been started
+func (report *BootReport) OK() bool {
+	return report.Status == BootOK
+}
+
+func readUint32OrZero(entry *od.Entry, subIndex uint8) uint32 {
+	if entry == nil {
+		return 0
+	}
+	value, _ := entry.Uint32(subIndex)
+	return value
+}
+
+// LocalBootConfig creates a boot configuration from the OD of a local node,
+// using the standard configuration manager objects (0x1F80, 0x1F81, 0x1F84 - 0x1F89)
+// & the concise DCFs stored by the local node, see LocalNode.ConciseDCF.
+func (network *Network) LocalBootConfig(nodeId uint8) (BootConfig, error) {
+	bootConfig := BootConfig{Slaves: make(map[uint8]BootSlave)}
+	local, err := network.Local(nodeId)
+	if err != nil {
+		return bootConfig, err
+	}
+	odict := local.GetOD()
+	startup := odict.Index(od.EntryNMTStartup)
+	if startup == nil {
+		return bootConfig, od.ErrIdxNotExist
+	}
+	bootConfig.Startup, err = startup.Uint32(0)
+	if err != nil {
+		return bootConfig, err
+	}
+	bootConfig.BootTime = time.Duration(readUint32OrZero(odict.Index(od.EntryBootTime), 0)) * time.Millisecond
+	assignments := odict.Index(od.EntryNMTSlaveAssignment)
+	if assignments == nil {
+		return bootConfig, nil
+	}
+	for slaveId := uint8(1); slaveId <= 127; slaveId++ {
+		assignment, err := assignments.Uint32(slaveId)
+		if err != nil || assignment&AssignmentSlave == 0 {
+			continue
+		}
+		bootConfig.Slaves[slaveId] = BootSlave{
+			Assignment: assignment,
+			DeviceType: readUint32OrZero(odict.Index(od.EntryDeviceTypeIdentification), slaveId),
+			Identity: config.Identity{
+				VendorId:       readUint32OrZero(odict.Index(od.EntryVendorIdentification), slaveId),
+				ProductCode:    readUint32OrZero(odict.Index(od.EntryProductCodeIdentification), slaveId),
+				RevisionNumber: readUint32OrZero(odict.Index(od.EntryRevisionNumberIdentification), slaveId),
+				SerialNumber:   readUint32OrZero(odict.Index(od.EntrySerialNumberIdentification), slaveId),
+			},
+			ConciseDCF: local.ConciseDCF(slaveId),
+		}
+	}
+	return bootConfig, nil
+}
+
+// Boot a single slave, without starting it.
+// Mandatory slaves are polled until deadline.
+func (network *Network) bootSlave(ctx context.Context, nodeId uint8, slave BootSlave) BootReport {
+	report := BootReport{NodeId: nodeId, Mandatory: slave.Assignment&AssignmentMandatory != 0}
+	conf := network.Configurator(nodeId)
+	for {
+		report.DeviceType, report.Err = network.ReadUint32(nodeId, od.EntryDeviceType, 0)
+		if report.Err == nil || !report.Mandatory {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			report.Status = BootNoResponse
+			return report
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	if report.Err != nil {
+		report.Status = BootNoResponse
+		return report
+	}
+	if slave.DeviceType != 0 && slave.DeviceType != report.DeviceType {
+		report.Status = BootDeviceType
+		report.Err = fmt.Errorf("expected device type x%x, got x%x", slave.DeviceType, report.DeviceType)
+		return report
+	}
+	expected := slave.Identity
+	if expected != (config.Identity{}) {
+		report.Identity, report.Err = conf.ReadIdentity()
+		if report.Err != nil {
+			report.Status = BootNoResponse
+			return report
+		}
+		identity := report.Identity
+		if (expected.VendorId != 0 && expected.VendorId != identity.VendorId) ||
+			(expected.ProductCode != 0 && expected.ProductCode != identity.ProductCode) ||
+			(expected.RevisionNumber != 0 && expected.RevisionNumber != identity.RevisionNumber) ||
+			(expected.SerialNumber != 0 && expected.SerialNumber != identity.SerialNumber) {
+			report.Status = BootIdentity
+			report.Err = fmt.Errorf("expected identity %+v, got %+v", expected, *identity)
+			return report
+		}
+	}
+	if len(slave.ConciseDCF) > 0 {
+		report.Err = conf.WriteConciseDCF(slave.ConciseDCF)
+		if report.Err != nil {
+			report.Status = BootConfiguration
+			return report
+		}
+	}
+	return report
+}
+
+// Boot runs the boot-up procedure of CiA 302-2 for every assigned slave :
+//   - check device type (0x1000) against expected value
+//   - verify identity (0x1018) against expected values
+//   - download concise DCF, if any
+//   - start the slaves according to NMT startup (0x1F80)
+//
+// Mandatory slaves are retried until boot time elapses or ctx is cancelled.
+// If a mandatory slave fails, no slave is started & [ErrBootMandatory] is returned.
+// Slaves that booted but were not started are reported with [BootNotStarted].
+// A report is returned for every assigned slave.
+func (network *Network) Boot(ctx context.Context, bootConfig BootConfig) (map[uint8]BootReport, error) {
+	reports := make(map[uint8]BootReport)
+	if bootConfig.Startup&StartupNMTMaster == 0 {
+		return reports, ErrBootNotMaster
+	}
+	if bootConfig.BootTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, bootConfig.BootTime)
+		defer cancel()
+	}
+	mandatoryFailed := false
+	for nodeId, slave := range bootConfig.Slaves {
+		if slave.Assignment&AssignmentSlave == 0 {
+			continue
+		}
+		report := network.bootSlave(ctx, nodeId, slave)
+		if !report.OK() {
+			mandatoryFailed = mandatoryFailed || report.Mandatory
+			network.logger.Warn("slave failed to boot",
+				"id", nodeId,
+				"mandatory", report.Mandatory,
+				"status", BootStatusDescription[report.Status],
+				"error", report.Err,
+			)
+		}
+		reports[nodeId] = report
+	}
+
+	startSlaves := !mandatoryFailed && bootConfig.Startup&StartupNoSlavesStart == 0
+	if startSlaves && bootConfig.Startup&StartupStartAll != 0 {
+		err := network.Command(0, nmt.CommandEnterOperational)
+		if err != nil {
+			return reports, err
+		}
+	}
+	for nodeId, report := range reports {
+		if !report.OK() {
+			continue
+		}
+		if !startSlaves {
+			report.Status = BootNotStarted
+			reports[nodeId] = report
+			continue
+		}
+		if bootConfig.Startup&StartupStartAll == 0 {
+			err := network.Command(nodeId, nmt.CommandEnterOperational)
+			if err != nil {
+				return reports, err
+			}
+		}
+		network.logger.Info("slave booted", "id", nodeId)
+	}
+	if mandatoryFailed {
+		return reports, ErrBootMandatory
+	}
+	return reports, nil
+}
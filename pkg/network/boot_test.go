@@ -0,0 +1,142 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoot(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	vendorId, err := local.GetOD().Index(od.EntryIdentityObject).Uint32(1)
+	assert.Nil(t, err)
+	dcf := od.EncodeConciseDCF([]od.ConciseEntry{{Index: 0x2005, Subindex: 0, Data: []byte{0x42}}})
+
+	enterPreOperational := func() {
+		assert.Nil(t, network.Command(NodeIdTest, nmt.CommandEnterPreOperational))
+		assert.Eventually(t, func() bool {
+			return local.NMT.GetInternalState() == nmt.StatePreOperational
+		}, 2*time.Second, 10*time.Millisecond)
+	}
+
+	t.Run("not master", func(t *testing.T) {
+		_, err := network.Boot(context.Background(), BootConfig{})
+		assert.Equal(t, ErrBootNotMaster, err)
+	})
+
+	t.Run("mandatory slave fails", func(t *testing.T) {
+		enterPreOperational()
+		reports, err := network.Boot(context.Background(), BootConfig{
+			Startup:  StartupNMTMaster,
+			BootTime: 500 * time.Millisecond,
+			Slaves: map[uint8]BootSlave{
+				NodeIdTest: {
+					Assignment: AssignmentSlave | AssignmentMandatory,
+					DeviceType: 0x1234,
+				},
+			},
+		})
+		assert.Equal(t, ErrBootMandatory, err)
+		assert.Equal(t, BootDeviceType, reports[NodeIdTest].Status)
+		assert.NotNil(t, reports[NodeIdTest].Err)
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, nmt.StatePreOperational, local.NMT.GetInternalState())
+	})
+
+	t.Run("identity mismatch", func(t *testing.T) {
+		reports, err := network.Boot(context.Background(), BootConfig{
+			Startup: StartupNMTMaster,
+			Slaves: map[uint8]BootSlave{
+				NodeIdTest: {
+					Assignment: AssignmentSlave,
+					Identity:   config.Identity{VendorId: vendorId + 1},
+				},
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, BootIdentity, reports[NodeIdTest].Status)
+	})
+
+	t.Run("boot and start", func(t *testing.T) {
+		enterPreOperational()
+		reports, err := network.Boot(context.Background(), BootConfig{
+			Startup: StartupNMTMaster,
+			Slaves: map[uint8]BootSlave{
+				NodeIdTest: {
+					Assignment: AssignmentSlave | AssignmentMandatory,
+					Identity:   config.Identity{VendorId: vendorId},
+					ConciseDCF: dcf,
+				},
+				// Optional & missing
+				NodeIdTest + 1: {Assignment: AssignmentSlave},
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, BootOK, reports[NodeIdTest].Status)
+		assert.Equal(t, BootNoResponse, reports[NodeIdTest+1].Status)
+		value, err := local.ReadUint(0x2005, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x42, value)
+		assert.Eventually(t, func() bool {
+			return local.NMT.GetInternalState() == nmt.StateOperational
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("slaves not started", func(t *testing.T) {
+		enterPreOperational()
+		reports, err := network.Boot(context.Background(), BootConfig{
+			Startup: StartupNMTMaster | StartupNoSlavesStart,
+			Slaves:  map[uint8]BootSlave{NodeIdTest: {Assignment: AssignmentSlave}},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, BootNotStarted, reports[NodeIdTest].Status)
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, nmt.StatePreOperational, local.NMT.GetInternalState())
+	})
+
+	t.Run("local boot config", func(t *testing.T) {
+		manager, err := network.CreateLocalNode(NodeIdTest+2, od.Default())
+		assert.Nil(t, err)
+		odict := manager.GetOD()
+		_, err = odict.AddVariableType(od.EntryNMTStartup, "NMT startup", od.UNSIGNED32, od.AttributeSdoRw, "0x3")
+		assert.Nil(t, err)
+		_, err = odict.AddVariableType(od.EntryBootTime, "Boot time", od.UNSIGNED32, od.AttributeSdoRw, "1000")
+		assert.Nil(t, err)
+		assignments := od.NewArray(128)
+		vendors := od.NewArray(128)
+		for i := uint8(0); i < 128; i++ {
+			assignments.AddSubObject(i, fmt.Sprintf("Node %d", i), od.UNSIGNED32, od.AttributeSdoRw, "0x0")
+			vendors.AddSubObject(i, fmt.Sprintf("Node %d", i), od.UNSIGNED32, od.AttributeSdoRw, "0x0")
+		}
+		odict.AddVariableList(od.EntryNMTSlaveAssignment, "NMT slave assignment", assignments)
+		odict.AddVariableList(od.EntryVendorIdentification, "Vendor identification", vendors)
+		assert.Nil(t, odict.Index(od.EntryNMTSlaveAssignment).PutUint32(NodeIdTest, AssignmentSlave|AssignmentMandatory, true))
+		assert.Nil(t, odict.Index(od.EntryVendorIdentification).PutUint32(NodeIdTest, vendorId, true))
+		_, err = manager.AddConciseDCFObject()
+		assert.Nil(t, err)
+		assert.Nil(t, manager.SetConciseDCF(NodeIdTest, dcf))
+
+		bootConfig, err := network.LocalBootConfig(NodeIdTest + 2)
+		assert.Nil(t, err)
+		assert.Equal(t, StartupNMTMaster|StartupStartAll, bootConfig.Startup)
+		assert.Equal(t, time.Second, bootConfig.BootTime)
+		assert.Equal(t, map[uint8]BootSlave{
+			NodeIdTest: {
+				Assignment: AssignmentSlave | AssignmentMandatory,
+				Identity:   config.Identity{VendorId: vendorId},
+				ConciseDCF: dcf,
+			},
+		}, bootConfig.Slaves)
+		_, err = network.LocalBootConfig(NodeIdTest)
+		assert.Equal(t, od.ErrIdxNotExist, err)
+	})
+}
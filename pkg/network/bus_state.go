@@ -0,0 +1,136 @@
+package network
+
+import (
+	"context"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+)
+
+const DefaultBusMonitorPeriod = 100 * time.Millisecond
+
+// Possible states of the CAN bus connection
+type BusState uint8
+
+const (
+	BusStateConnected    BusState = 0 // Bus is connected and receiving frames
+	BusStateDisconnected BusState = 1 // Bus has been disconnected or interface was lost
+	BusStateReconnecting BusState = 2 // Reconnection attempt failed, will be retried
+)
+
+var BusStateDescription = map[BusState]string{
+	BusStateConnected:    "CONNECTED",
+	BusStateDisconnected: "DISCONNECTED",
+	BusStateReconnecting: "RECONNECTING",
+}
+
+func (state BusState) String() string {
+	return BusStateDescription[state]
+}
+
+// BusEvent is emitted on every change of the bus connection
+type BusEvent struct {
+	State BusState
+	Time  time.Time
+	Err   error // Cause of the event if any
+}
+
+type BusEventCallback func(event BusEvent)
+
+// Add a callback that is called on bus connection events.
+// Callbacks are called from the bus monitor and should not block.
+func (network *Network) OnBusEvent(callback BusEventCallback) {
+	network.busMu.Lock()
+	defer network.busMu.Unlock()
+	network.busCallbacks = append(network.busCallbacks, callback)
+}
+
+// Set the period used for checking the CAN interface and
+// retrying reconnection. A period of 0 disables automatic reconnection.
+// This should be called before [Network.Connect].
+func (network *Network) SetBusMonitorPeriod(period time.Duration) {
+	network.busMu.Lock()
+	defer network.busMu.Unlock()
+	network.busPeriod = period
+}
+
+func (network *Network) emitBusEvent(state BusState, err error) {
+	event := BusEvent{State: state, Time: time.Now(), Err: err}
+	if err == nil {
+		network.logger.Info("bus state changed", "state", state)
+	} else {
+		network.logger.Warn("bus state changed", "state", state, "error", err)
+	}
+	network.busMu.Lock()
+	callbacks := append([]BusEventCallback{}, network.busCallbacks...)
+	network.busMu.Unlock()
+	for _, callback := range callbacks {
+		callback(event)
+	}
+}
+
+// Returns an error if the bus reports loss of the interface
+func busErr(bus canopen.Bus) error {
+	reporter, ok := bus.(canopen.BusErrorReporter)
+	if !ok {
+		return nil
+	}
+	return reporter.Err()
+}
+
+// Recreate and connect the bus. Subscriptions are kept by the
+// BusManager so only the bus reception needs to be restored.
+func (network *Network) reconnect() error {
+	bus := network.Bus()
+	_ = bus.Disconnect()
+	if network.ownBus {
+		newBus, err := NewBus(network.connectArgs[0].(string), network.connectArgs[1].(string), network.connectArgs[2].(int))
+		if err != nil {
+			return err
+		}
+		bus = newBus
+	}
+	err := bus.Connect(network.connectArgs)
+	if err != nil {
+		return err
+	}
+	err = bus.Subscribe(network.BusManager)
+	if err != nil {
+		return err
+	}
+	network.SetBus(bus)
+	return nil
+}
+
+// Periodically check the bus and reconnect on interface loss, until ctx is cancelled.
+func (network *Network) monitorBus(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := busErr(network.Bus())
+		if err == nil {
+			continue
+		}
+		network.connected.Store(false)
+		network.emitBusEvent(BusStateDisconnected, err)
+		for {
+			err = network.reconnect()
+			if err == nil {
+				break
+			}
+			network.emitBusEvent(BusStateReconnecting, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+		network.connected.Store(true)
+		network.emitBusEvent(BusStateConnected, nil)
+	}
+}
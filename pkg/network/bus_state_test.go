@@ -0,0 +1,66 @@
+package network
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/can/virtual"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+// Virtual bus which can simulate the loss of the interface
+type flakyBus struct {
+	*virtual.Bus
+	lost     atomic.Bool
+	connects atomic.Int32
+}
+
+func (b *flakyBus) Connect(args ...any) error {
+	b.connects.Add(1)
+	b.lost.Store(false)
+	return b.Bus.Connect(args...)
+}
+
+func (b *flakyBus) Err() error {
+	if b.lost.Load() {
+		return errors.New("interface lost")
+	}
+	return b.Bus.Err()
+}
+
+func TestBusReconnect(t *testing.T) {
+	canBus, _ := NewBus("virtual", "localhost:18888", 0)
+	bus := &flakyBus{Bus: canBus.(*virtual.Bus)}
+	bus.SetReceiveOwn(true)
+	network := NewNetwork(bus)
+	network.SetBusMonitorPeriod(10 * time.Millisecond)
+
+	mu := sync.Mutex{}
+	states := []BusState{}
+	network.OnBusEvent(func(event BusEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, event.State)
+	})
+	assert.Nil(t, network.Connect())
+	defer network.Disconnect()
+	assert.True(t, network.Connected())
+	_, err := network.CreateLocalNode(NodeIdTest, od.Default())
+	assert.Nil(t, err)
+	_, err = network.ReadUint32(NodeIdTest, od.EntryIdentityObject, 1)
+	assert.Nil(t, err)
+
+	bus.lost.Store(true)
+	assert.Eventually(t, func() bool { return bus.connects.Load() == 2 && network.Connected() }, time.Second, 10*time.Millisecond)
+
+	// Subscriptions and node processing are restored
+	_, err = network.ReadUint32(NodeIdTest, od.EntryIdentityObject, 1)
+	assert.Nil(t, err)
+	mu.Lock()
+	assert.Equal(t, []BusState{BusStateConnected, BusStateDisconnected, BusStateConnected}, states)
+	mu.Unlock()
+}
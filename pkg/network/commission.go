@@ -0,0 +1,319 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+var (
+	ErrCommissionRequired   = errors.New("a required node failed to commission")
+	ErrCommissionNotStarted = errors.New("not started because a required node failed")
+	ErrCommissionIdentity   = errors.New("identity mismatch")
+)
+
+// Step of the commissioning of a node, see [Network.Commission]
+type CommissionStep uint8
+
+const (
+	CommissionScan          CommissionStep = iota + 1 // Node answers SDO requests
+	CommissionIdentity                                // Identity (0x1018) matches expected values
+	CommissionConfiguration                           // Configuration & PDOs written to the node
+	CommissionHeartbeat                               // Heartbeat produced by the node & monitored
+	CommissionPDO                                     // PDOs of the node processed by the network
+	CommissionStart                                   // Node started with NMT
+)
+
+func (step CommissionStep) String() string {
+	switch step {
+	case CommissionScan:
+		return "scan"
+	case CommissionIdentity:
+		return "identity"
+	case CommissionConfiguration:
+		return "configuration"
+	case CommissionHeartbeat:
+		return "heartbeat"
+	case CommissionPDO:
+		return "pdo"
+	case CommissionStart:
+		return "start"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(step))
+	}
+}
+
+// NodeSpec declares a node expected by [Network.Commission]
+type NodeSpec struct {
+	NodeId uint8
+	// Failure of an optional node does not prevent starting the other nodes
+	Optional bool
+	// Expected identity (0x1018), fields set to 0 are not checked
+	Identity config.Identity
+	// OD of the node, EDS path or *od.ObjectDictionary. If set and the node
+	// is not on the network yet, it is added with [Network.AddRemoteNode].
+	OD any
+	// Values written to the node
+	Configuration []od.ConciseEntry
+	// PDOs written to the node, by PDO number, see [config.NodeConfigurator.WriteConfigurationPDO].
+	// PDOs with mapped objects are enabled.
+	PDOs map[uint16]config.PDOConfigurationParameter
+	// Producer heartbeat time (0x1017) written to the node, 0 is not written
+	HeartbeatPeriod time.Duration
+	// Heartbeat consumer timeout of the network, 0 disables loss detection
+	HeartbeatTimeout time.Duration
+	// Start processing the PDOs of the node on the network, see [node.RemoteNode.StartPDOs].
+	// The node should be a remote node, e.g. with OD set.
+	StartPDOs bool
+}
+
+// Result of a commissioning step
+type CommissionStepResult struct {
+	Step CommissionStep
+	Err  error
+}
+
+// CommissionReport is the result of [Network.Commission] for a single node.
+// Steps are given in order and stop at the first failed step.
+// Steps with nothing to do are omitted.
+type CommissionReport struct {
+	NodeId   uint8
+	Optional bool
+	Identity config.Identity // Identity read during the scan
+	Steps    []CommissionStepResult
+}
+
+// Returns true if every step succeeded & the node has been started
+func (report CommissionReport) OK() bool {
+	return report.Err() == nil && len(report.Steps) > 0 && report.Steps[len(report.Steps)-1].Step == CommissionStart
+}
+
+// Err returns the error of the failed step, if any
+func (report CommissionReport) Err() error {
+	for _, result := range report.Steps {
+		if result.Err != nil {
+			return fmt.Errorf("x%x %v : %w", report.NodeId, result.Step, result.Err)
+		}
+	}
+	return nil
+}
+
+func (report *CommissionReport) add(step CommissionStep, err error) error {
+	report.Steps = append(report.Steps, CommissionStepResult{Step: step, Err: err})
+	return err
+}
+
+// ExpectNode declares a node for [Network.Commission], replacing any previous
+// declaration with the same node id.
+func (network *Network) ExpectNode(spec NodeSpec) error {
+	if spec.NodeId < nodeIdMin || spec.NodeId > nodeIdMax {
+		return ErrIdRange
+	}
+	network.expectedMu.Lock()
+	defer network.expectedMu.Unlock()
+	if network.expected == nil {
+		network.expected = make(map[uint8]NodeSpec)
+	}
+	network.expected[spec.NodeId] = spec
+	return nil
+}
+
+// ExpectedNodes returns the nodes declared with [Network.ExpectNode], by node id
+func (network *Network) ExpectedNodes() []NodeSpec {
+	network.expectedMu.Lock()
+	defer network.expectedMu.Unlock()
+	specs := make([]NodeSpec, 0, len(network.expected))
+	for _, spec := range network.expected {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].NodeId < specs[j].NodeId })
+	return specs
+}
+
+// Commission brings up the nodes declared with [Network.ExpectNode] :
+//   - scan the network for the expected nodes
+//   - verify their identity
+//   - write their configuration & PDOs
+//   - configure heartbeat production & monitoring
+//   - start processing their PDOs
+//   - start them with NMT
+//
+// Nodes are handled one at a time, by ascending node id, and are only started
+// once all of them are configured. If a required node fails, no node is started and
+// [ErrCommissionRequired] is returned. Nodes producing heartbeats must confirm
+// the start within 3 heartbeat periods. Cancelling ctx fails the remaining steps
+// and ctx error is returned.
+// A report is returned for every node.
+func (network *Network) Commission(ctx context.Context) (map[uint8]CommissionReport, error) {
+	specs := network.ExpectedNodes()
+	reports := make(map[uint8]CommissionReport)
+	if len(specs) == 0 {
+		return reports, nil
+	}
+	nodeIds := make([]uint8, 0, len(specs))
+	for _, spec := range specs {
+		nodeIds = append(nodeIds, spec.NodeId)
+	}
+	found, scanErr := network.ScanWith(ctx, ScanOptions{NodeIds: nodeIds, SkipManufacturer: true})
+
+	requiredFailed := false
+	for _, spec := range specs {
+		report := CommissionReport{NodeId: spec.NodeId, Optional: spec.Optional}
+		info, ok := found[spec.NodeId]
+		switch {
+		case ok:
+			report.Identity = info.Identity
+			report.add(CommissionScan, nil)
+			network.commissionNode(ctx, spec, &report)
+		case scanErr != nil:
+			report.add(CommissionScan, scanErr)
+		default:
+			report.add(CommissionScan, ErrNotFound)
+		}
+		if err := report.Err(); err != nil {
+			requiredFailed = requiredFailed || !spec.Optional
+			network.logger.Warn("node failed to commission",
+				"id", spec.NodeId,
+				"optional", spec.Optional,
+				"error", err,
+			)
+		}
+		reports[spec.NodeId] = report
+	}
+
+	for _, spec := range specs {
+		report := reports[spec.NodeId]
+		if report.Err() != nil {
+			continue
+		}
+		if requiredFailed {
+			report.add(CommissionStart, ErrCommissionNotStarted)
+		} else if report.add(CommissionStart, network.commissionStart(ctx, spec)) == nil {
+			network.logger.Info("node commissioned", "id", spec.NodeId)
+		}
+		reports[spec.NodeId] = report
+	}
+	if ctx.Err() != nil {
+		return reports, ctx.Err()
+	}
+	if requiredFailed {
+		return reports, ErrCommissionRequired
+	}
+	return reports, nil
+}
+
+// Run the steps of a node that has been found, until the first failure
+func (network *Network) commissionNode(ctx context.Context, spec NodeSpec, report *CommissionReport) {
+	expected := spec.Identity
+	if expected != (config.Identity{}) {
+		identity := report.Identity
+		var err error
+		if (expected.VendorId != 0 && expected.VendorId != identity.VendorId) ||
+			(expected.ProductCode != 0 && expected.ProductCode != identity.ProductCode) ||
+			(expected.RevisionNumber != 0 && expected.RevisionNumber != identity.RevisionNumber) ||
+			(expected.SerialNumber != 0 && expected.SerialNumber != identity.SerialNumber) {
+			err = fmt.Errorf("expected %+v, got %+v : %w", expected, identity, ErrCommissionIdentity)
+		}
+		if report.add(CommissionIdentity, err) != nil {
+			return
+		}
+	}
+	if spec.OD != nil || len(spec.Configuration) > 0 || len(spec.PDOs) > 0 {
+		if report.add(CommissionConfiguration, network.commissionConfiguration(ctx, spec)) != nil {
+			return
+		}
+	}
+	if spec.HeartbeatPeriod > 0 || spec.HeartbeatTimeout > 0 {
+		if report.add(CommissionHeartbeat, network.commissionHeartbeat(ctx, spec)) != nil {
+			return
+		}
+	}
+	if spec.StartPDOs {
+		report.add(CommissionPDO, network.commissionPDOs(ctx, spec))
+	}
+}
+
+// Add the node with its OD if unknown, then write configuration & PDOs
+func (network *Network) commissionConfiguration(ctx context.Context, spec NodeSpec) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if _, known := network.controllers[spec.NodeId]; spec.OD != nil && !known {
+		_, err := network.AddRemoteNode(spec.NodeId, spec.OD)
+		if err != nil {
+			return err
+		}
+	}
+	conf := network.Configurator(spec.NodeId)
+	if len(spec.Configuration) > 0 {
+		err := conf.WriteConciseDCF(od.EncodeConciseDCF(spec.Configuration))
+		if err != nil {
+			return err
+		}
+	}
+	pdoNbs := make([]int, 0, len(spec.PDOs))
+	for pdoNb := range spec.PDOs {
+		pdoNbs = append(pdoNbs, int(pdoNb))
+	}
+	sort.Ints(pdoNbs)
+	for _, nb := range pdoNbs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		pdoNb := uint16(nb)
+		pdoConf := spec.PDOs[pdoNb]
+		err := conf.DisablePDO(pdoNb)
+		if err == nil {
+			err = conf.WriteConfigurationPDO(pdoNb, pdoConf)
+		}
+		if err == nil && len(pdoConf.Mappings) > 0 {
+			err = conf.EnablePDO(pdoNb)
+		}
+		if err != nil {
+			return fmt.Errorf("pdo %v : %w", pdoNb, err)
+		}
+	}
+	return nil
+}
+
+// Write producer heartbeat time & set the timeout of the heartbeat consumer
+func (network *Network) commissionHeartbeat(ctx context.Context, spec NodeSpec) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if spec.HeartbeatPeriod > 0 {
+		err := network.Configurator(spec.NodeId).WriteHeartbeatPeriod(uint16(spec.HeartbeatPeriod.Milliseconds()))
+		if err != nil {
+			return err
+		}
+	}
+	return network.SetHeartbeatTimeout(spec.NodeId, spec.HeartbeatTimeout)
+}
+
+func (network *Network) commissionPDOs(ctx context.Context, spec NodeSpec) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	remote, err := network.Remote(spec.NodeId)
+	if err != nil {
+		return err
+	}
+	return remote.StartPDOs(false)
+}
+
+// Start the node, confirmed by its heartbeat if it produces one
+func (network *Network) commissionStart(ctx context.Context, spec NodeSpec) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if spec.HeartbeatPeriod > 0 {
+		return network.CommandWait(spec.NodeId, nmt.CommandEnterOperational, 3*spec.HeartbeatPeriod)
+	}
+	return network.Command(spec.NodeId, nmt.CommandEnterOperational)
+}
@@ -0,0 +1,115 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/pdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommission(t *testing.T) {
+	devices := CreateNetworkTest()
+	master := CreateNetworkEmptyTest()
+	defer devices.Disconnect()
+	defer master.Disconnect()
+	local, err := devices.Local(NodeIdTest)
+	assert.Nil(t, err)
+	vendorId := uint32(0x1234)
+	assert.Nil(t, local.GetOD().Index(od.EntryIdentityObject).PutUint32(1, vendorId, true))
+
+	assert.Equal(t, ErrIdRange, master.ExpectNode(NodeSpec{NodeId: 0}))
+	reports, err := master.Commission(context.Background())
+	assert.Nil(t, err)
+	assert.Empty(t, reports)
+
+	spec := NodeSpec{
+		NodeId:        NodeIdTest,
+		Identity:      config.Identity{VendorId: vendorId},
+		OD:            od.Default(),
+		Configuration: []od.ConciseEntry{{Index: 0x2005, Subindex: 0, Data: []byte{0x42}}},
+		PDOs: map[uint16]config.PDOConfigurationParameter{
+			pdo.MinTpdoNumber: {
+				CanId:            0x180 + uint16(NodeIdTest),
+				TransmissionType: 0xFE,
+				EventTimer:       50,
+				Mappings:         []config.PDOMappingParameter{{Index: 0x2006, Subindex: 0, LengthBits: 16}},
+			},
+		},
+		HeartbeatPeriod:  50 * time.Millisecond,
+		HeartbeatTimeout: 200 * time.Millisecond,
+		StartPDOs:        true,
+	}
+
+	t.Run("required node fails", func(t *testing.T) {
+		assert.Nil(t, master.Command(NodeIdTest, nmt.CommandEnterPreOperational))
+		assert.Eventually(t, func() bool {
+			return local.NMT.GetInternalState() == nmt.StatePreOperational
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Nil(t, master.ExpectNode(NodeSpec{NodeId: NodeIdTest, Identity: config.Identity{VendorId: vendorId + 1}}))
+		assert.Nil(t, master.ExpectNode(NodeSpec{NodeId: NodeIdTest + 1, Optional: true}))
+		reports, err := master.Commission(context.Background())
+		assert.Equal(t, ErrCommissionRequired, err)
+		assert.Len(t, reports, 2)
+		report := reports[NodeIdTest]
+		assert.False(t, report.OK())
+		assert.ErrorIs(t, report.Err(), ErrCommissionIdentity)
+		assert.Equal(t, []CommissionStep{CommissionScan, CommissionIdentity}, steps(report))
+		report = reports[NodeIdTest+1]
+		assert.ErrorIs(t, report.Err(), ErrNotFound)
+		assert.True(t, report.Optional)
+		assert.Equal(t, nmt.StatePreOperational, local.NMT.GetInternalState())
+	})
+
+	t.Run("optional node fails", func(t *testing.T) {
+		assert.Nil(t, master.ExpectNode(spec))
+		reports, err := master.Commission(context.Background())
+		assert.Nil(t, err)
+		report := reports[NodeIdTest]
+		assert.Nil(t, report.Err())
+		assert.True(t, report.OK())
+		assert.Equal(t, vendorId, report.Identity.VendorId)
+		assert.Equal(t, []CommissionStep{
+			CommissionScan, CommissionIdentity, CommissionConfiguration,
+			CommissionHeartbeat, CommissionPDO, CommissionStart,
+		}, steps(report))
+		assert.False(t, reports[NodeIdTest+1].OK())
+		assert.Equal(t, nmt.StateOperational, local.NMT.GetInternalState())
+
+		value, err := local.ReadUint(0x2005, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x42, value)
+		heartbeat, err := local.ReadUint(od.EntryProducerHeartbeatTime, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 50, heartbeat)
+
+		// TPDO of the node is received by the master
+		remote, err := master.Remote(NodeIdTest)
+		assert.Nil(t, err)
+		assert.Nil(t, local.Write(0x2006, 0, uint16(0x1234)))
+		assert.Eventually(t, func() bool {
+			value, _ := remote.ReadUint16(0, 0x2006, 0)
+			return value == 0x1234
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		reports, err := master.Commission(ctx)
+		assert.Equal(t, context.Canceled, err)
+		assert.ErrorIs(t, reports[NodeIdTest].Err(), context.Canceled)
+	})
+}
+
+func steps(report CommissionReport) []CommissionStep {
+	steps := make([]CommissionStep, 0, len(report.Steps))
+	for _, result := range report.Steps {
+		steps = append(steps, result.Step)
+	}
+	return steps
+}
@@ -1,12 +1,14 @@
 package network
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/samsamfire/gocanopen/pkg/config"
 	"github.com/samsamfire/gocanopen/pkg/od"
 	"github.com/samsamfire/gocanopen/pkg/sdo"
+	"github.com/samsamfire/gocanopen/pkg/sync"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,11 +33,11 @@ func TestSyncConfigurator(t *testing.T) {
 	commPeriod, _ := conf.ReadCommunicationPeriod()
 	assert.EqualValues(t, 100_100, commPeriod)
 	err = conf.WriteCounterOverflow(100)
-	assert.Equal(t, sdo.AbortDataDeviceState, err)
+	assert.ErrorIs(t, err, sdo.AbortDataDeviceState)
 	err = conf.WriteCommunicationPeriod(0)
 	assert.Nil(t, err)
 	err = conf.WriteCounterOverflow(250)
-	assert.Equal(t, sdo.AbortInvalidValue, err)
+	assert.ErrorIs(t, err, sdo.AbortInvalidValue)
 	err = conf.WriteCounterOverflow(10)
 	assert.Nil(t, err)
 	counterOverflow, err := conf.ReadCounterOverflow()
@@ -45,6 +47,26 @@ func TestSyncConfigurator(t *testing.T) {
 	assert.Nil(t, err)
 	windowPdos, _ := conf.ReadWindowLengthPdos()
 	assert.EqualValues(t, 110, windowPdos)
+
+	// Validated producer configuration
+	err = conf.ConfigureSYNCProducer(time.Nanosecond, 0)
+	assert.Equal(t, sync.ErrInvalidPeriod, err)
+	err = conf.ConfigureSYNCProducer(10*time.Millisecond, 241)
+	assert.Equal(t, sync.ErrInvalidCounterOverflow, err)
+	err = conf.ConfigureSYNCProducer(10*time.Millisecond, 20)
+	assert.Nil(t, err)
+	counterOverflow, _ = conf.ReadCounterOverflow()
+	assert.EqualValues(t, 20, counterOverflow)
+	commPeriod, _ = conf.ReadCommunicationPeriod()
+	assert.EqualValues(t, 10_000, commPeriod)
+	cobId, _ := conf.ReadCobIdSYNC()
+	assert.EqualValues(t, 1<<30|0x81, cobId)
+	err = conf.WriteSYNCPeriod(20 * time.Millisecond)
+	assert.Nil(t, err)
+	commPeriod, _ = conf.ReadCommunicationPeriod()
+	assert.EqualValues(t, 20_000, commPeriod)
+	assert.Nil(t, conf.ProducerDisableSYNC())
+	assert.Nil(t, conf.WriteCommunicationPeriod(0))
 }
 
 var TEST_MAPPING = []config.PDOMappingParameter{
@@ -130,6 +152,38 @@ func TestPDOConfiguratorNotCommon(t *testing.T) {
 	assert.EqualValues(t, 2222, inhibitTime)
 }
 
+func TestRemapPDO(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	conf := network.Configurator(NodeIdTest)
+
+	for _, pdoNb := range []uint16{1, 257} {
+		err := conf.RemapPDO(pdoNb, TEST_MAPPING[1:], 0xFE, 100, 500)
+		assert.Nil(t, err)
+		readConfig, err := conf.ReadConfigurationPDO(pdoNb)
+		assert.Nil(t, err)
+		assert.Equal(t, TEST_MAPPING[1:], readConfig.Mappings)
+		assert.EqualValues(t, 0xFE, readConfig.TransmissionType)
+		assert.EqualValues(t, 500, readConfig.EventTimer)
+		enabled, _ := conf.ReadEnabledPDO(pdoNb)
+		assert.True(t, enabled)
+	}
+	inhibitTime, _ := conf.ReadInhibitTime(257)
+	assert.EqualValues(t, 100, inhibitTime)
+
+	// Validated against OD before writing anything
+	tooLong := append(TEST_MAPPING, config.PDOMappingParameter{Index: 0x2001, Subindex: 0, LengthBits: 8})
+	assert.ErrorIs(t, conf.RemapPDO(257, tooLong, 0xFE, 0, 0), od.ErrMapLen)
+	unknown := []config.PDOMappingParameter{{Index: 0x2FFF, Subindex: 0, LengthBits: 8}}
+	assert.ErrorIs(t, conf.RemapPDO(257, unknown, 0xFE, 0, 0), od.ErrIdxNotExist)
+	wrongLength := []config.PDOMappingParameter{{Index: 0x2002, Subindex: 0, LengthBits: 16}}
+	assert.ErrorIs(t, conf.RemapPDO(257, wrongLength, 0xFE, 0, 0), od.ErrNoMap)
+	notMappable := []config.PDOMappingParameter{{Index: 0x1017, Subindex: 0, LengthBits: 16}}
+	assert.ErrorIs(t, conf.RemapPDO(257, notMappable, 0xFE, 0, 0), od.ErrNoMap)
+	enabled, _ := conf.ReadEnabledPDO(257)
+	assert.True(t, enabled)
+}
+
 var receivedErrorCodes []uint16
 
 func emCallback(ident uint16, errorCode uint16, errorRegister byte, errorBit byte, infoCode uint32) {
@@ -146,7 +200,7 @@ func TestHBConfigurator(t *testing.T) {
 	assert.Nil(t, err)
 	// Test duplicate entry
 	err = config.WriteMonitoredNode(3, 0x25, 100)
-	assert.Equal(t, err, sdo.AbortParamIncompat)
+	assert.ErrorIs(t, err, sdo.AbortParamIncompat)
 	_, err = network.CreateLocalNode(0x25, od.Default())
 	assert.Nil(t, err)
 	max, _ := config.ReadMaxMonitorableNodes()
@@ -164,6 +218,19 @@ func TestHBConfigurator(t *testing.T) {
 	assert.Nil(t, err)
 	val, _ = config.ReadHeartbeatPeriod()
 	assert.EqualValues(t, val, 900)
+	// Monitor by node id without selecting the entry
+	err = config.AddMonitoredNode(0x26, 200)
+	assert.Nil(t, err)
+	monitoredNodes, _ = config.ReadMonitoredNodes()
+	assert.Equal(t, []uint16{0x26, 200}, monitoredNodes[1])
+	err = config.AddMonitoredNode(0x25, 300)
+	assert.Nil(t, err)
+	monitoredNodes, _ = config.ReadMonitoredNodes()
+	assert.Equal(t, []uint16{0x25, 300}, monitoredNodes[0])
+	err = config.RemoveMonitoredNode(0x26)
+	assert.Nil(t, err)
+	monitoredNodes, _ = config.ReadMonitoredNodes()
+	assert.Equal(t, []uint16{0, 0}, monitoredNodes[1])
 }
 
 func TestTimeConfigurator(t *testing.T) {
@@ -214,3 +281,147 @@ func TestGeneralObjects(t *testing.T) {
 		ManufacturerSoftwareVersion: "v1.1.2r",
 	}, manufInfo)
 }
+
+func TestBarrierConfigurator(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	conf := network.Configurator(NodeIdTest)
+	opts := &config.BarrierOptions{Timeout: 200 * time.Millisecond}
+
+	t.Run("read back", func(t *testing.T) {
+		err := conf.WriteBarrier(0x2002, 0, int8(22), nil, opts)
+		assert.Nil(t, err)
+	})
+	t.Run("handshake", func(t *testing.T) {
+		// Handshake object never reaches expected value
+		err := conf.WriteBarrier(0x2002, 0, int8(23), conf.Handshake(0x2003, 0, int16(-1)), opts)
+		assert.ErrorIs(t, err, config.ErrBarrierTimeout)
+		err = conf.WaitApplied(conf.Handshake(0x2002, 0, int8(23)), opts)
+		assert.Nil(t, err)
+	})
+	t.Run("confirm error", func(t *testing.T) {
+		err := conf.WaitApplied(conf.ReadBack(0x3333, 0, uint8(0)), opts)
+		assert.ErrorIs(t, err, config.ErrBarrierTimeout)
+		assert.ErrorIs(t, err, sdo.AbortNotExist)
+	})
+}
+
+func TestWriteCommitConfigurator(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	record := od.NewRecord()
+	record.AddSubObject(0, "count", od.UNSIGNED8, od.AttributeSdoR, "0x3")
+	record.AddSubObject(1, "position", od.UNSIGNED32, od.AttributeSdoRw, "0x0")
+	record.AddSubObject(2, "velocity", od.UNSIGNED32, od.AttributeSdoRw, "0x0")
+	record.AddSubObject(3, "commit", od.UNSIGNED8, od.AttributeSdoRw, "0x0")
+	local.GetOD().AddVariableList(0x3100, "target", record)
+	shadow, err := local.AddShadowRecord(0x3100, 3)
+	assert.Nil(t, err)
+	conf := network.Configurator(NodeIdTest)
+
+	t.Run("commit", func(t *testing.T) {
+		err := conf.WriteCommit(0x3100, []config.SubindexValue{
+			{Subindex: 1, Value: uint32(100)},
+			{Subindex: 2, Value: uint32(200)},
+		}, 3, uint8(1))
+		assert.Nil(t, err)
+		shadow.View(func(entry *od.Entry) {
+			position, _ := entry.Uint32(1)
+			velocity, _ := entry.Uint32(2)
+			assert.EqualValues(t, 100, position)
+			assert.EqualValues(t, 200, velocity)
+		})
+	})
+	t.Run("discard on error", func(t *testing.T) {
+		err := conf.WriteCommit(0x3100, []config.SubindexValue{
+			{Subindex: 1, Value: uint32(300)},
+			{Subindex: 2, Value: uint8(1)},
+		}, 3, uint8(1))
+		assert.ErrorIs(t, err, sdo.AbortDataShort)
+		assert.Equal(t, 0, shadow.Pending())
+		position, _ := local.GetOD().Index(0x3100).Uint32(1)
+		assert.EqualValues(t, 100, position)
+	})
+}
+
+func TestConciseDCFConfigurator(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	_, err = local.AddConciseDCFObject()
+	assert.Nil(t, err)
+	dcf := od.EncodeConciseDCF([]od.ConciseEntry{
+		{Index: 0x2005, Subindex: 0, Data: []byte{0x42}},
+		{Index: 0x2007, Subindex: 0, Data: []byte{0x01, 0x02, 0x03, 0x04}},
+	})
+
+	t.Run("store via 0x1F22", func(t *testing.T) {
+		err := network.WriteRaw(NodeIdTest, od.EntryConciseDCF, 0x10, dcf, false)
+		assert.Nil(t, err)
+		assert.Equal(t, dcf, local.ConciseDCF(0x10))
+		read, err := network.ReadAll(NodeIdTest, od.EntryConciseDCF, 0x10)
+		assert.Nil(t, err)
+		assert.Equal(t, dcf, read)
+		err = network.WriteRaw(NodeIdTest, od.EntryConciseDCF, 0x11, []byte{0x01, 0x00}, false)
+		assert.ErrorIs(t, err, sdo.AbortInvalidValue)
+		assert.Nil(t, local.ConciseDCF(0x11))
+	})
+
+	t.Run("download", func(t *testing.T) {
+		conf := network.Configurator(NodeIdTest)
+		err := conf.WriteConciseDCF(local.ConciseDCF(0x10))
+		assert.Nil(t, err)
+		value, err := local.ReadUint(0x2005, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x42, value)
+		value, err = local.ReadUint(0x2007, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x04030201, value)
+		err = conf.WriteConciseDCF(od.EncodeConciseDCF([]od.ConciseEntry{{Index: 0x5000, Subindex: 0, Data: []byte{0x01}}}))
+		assert.ErrorIs(t, err, sdo.AbortNotExist)
+	})
+}
+
+func TestBackupRestoreConfigurator(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	assert.Nil(t, local.SetParameterStorage(od.NewJSONFileStorage(filepath.Join(t.TempDir(), "params.json"))))
+	conf := network.Configurator(NodeIdTest)
+	path := filepath.Join(t.TempDir(), "backup.json")
+	assert.Nil(t, conf.RemapPDO(257, TEST_MAPPING[1:], 0xFE, 100, 500))
+	assert.Nil(t, conf.BackupToFile(path))
+
+	// Change parameters, PDO mapping is changed while PDO is enabled
+	assert.Nil(t, conf.WriteHeartbeatPeriod(1234))
+	assert.Nil(t, conf.RemapPDO(257, TEST_MAPPING[:1], 0xFE, 100, 500))
+	assert.Nil(t, network.WriteRaw(NodeIdTest, 0x2002, 0, int8(-5), false))
+	report, err := conf.RestoreFromFile(path)
+	assert.Nil(t, err)
+	assert.True(t, report.Saved)
+	changed := map[uint16]bool{}
+	for _, change := range report.Changes {
+		assert.Nil(t, change.Err)
+		changed[change.Index] = true
+	}
+	assert.Equal(t, map[uint16]bool{0x1017: true, 0x1A00: true, 0x2002: true}, changed)
+	period, err := conf.ReadHeartbeatPeriod()
+	assert.Nil(t, err)
+	assert.NotEqualValues(t, 1234, period)
+	readConfig, err := conf.ReadConfigurationPDO(257)
+	assert.Nil(t, err)
+	assert.Equal(t, TEST_MAPPING[1:], readConfig.Mappings)
+	enabled, _ := conf.ReadEnabledPDO(257)
+	assert.True(t, enabled)
+
+	// Backup of another device type
+	identity, err := conf.ReadIdentity()
+	assert.Nil(t, err)
+	identity.ProductCode++
+	_, err = conf.Restore(&config.Backup{Identity: identity})
+	assert.ErrorIs(t, err, config.ErrBackupMismatch)
+}
@@ -0,0 +1,220 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Transmitter used in DBC files for messages without known producer
+const dbcNoNode = "Vector__XXX"
+
+// Physical value of a signal : physical = raw * Factor + Offset
+type DBCScale struct {
+	Factor float64
+	Offset float64
+	Unit   string
+}
+
+// DBCOptions configures [PDODatabase.WriteDBC]
+type DBCOptions struct {
+	// Names of the nodes, defaults to "Node_<id>"
+	NodeNames map[uint8]string
+	// Scaling of the signals, e.g. looked up from a table of the application.
+	// Defaults to a factor of 1 without unit.
+	Scale func(signal Signal) DBCScale
+}
+
+// Convert to a valid DBC identifier, i.e. a C identifier
+func dbcIdentifier(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	identifier := strings.Trim(b.String(), "_")
+	if identifier == "" || unicode.IsDigit(rune(identifier[0])) {
+		identifier = "_" + identifier
+	}
+	return identifier
+}
+
+// Raw range of a signal, depending on data type & length
+func dbcRange(signal Signal) (min float64, max float64, signed bool) {
+	switch signal.DataType {
+	case od.INTEGER8, od.INTEGER16, od.INTEGER32, od.INTEGER64:
+		return -math.Pow(2, float64(signal.Length-1)), math.Pow(2, float64(signal.Length-1)) - 1, true
+	case od.REAL32:
+		return -math.MaxFloat32, math.MaxFloat32, true
+	case od.REAL64:
+		return -math.MaxFloat64, math.MaxFloat64, true
+	default:
+		return 0, math.Pow(2, float64(signal.Length)) - 1, false
+	}
+}
+
+func dbcFloat(value float64) string {
+	return fmt.Sprintf("%g", value)
+}
+
+// WriteDBC exports the PDOs of the database as a Vector DBC file, so that
+// CAN tools can decode the same traffic. There is one message per COB-ID,
+// decoded as done by [PDODatabase.Decode], nodes consuming a TPDO with an
+// RPDO are given as receivers.
+func (db *PDODatabase) WriteDBC(w io.Writer, opts DBCOptions) error {
+	db.mu.Lock()
+	nodes := make(map[uint8][]PDODefinition, len(db.nodes))
+	for id, defs := range db.nodes {
+		nodes[id] = slices.Clone(defs)
+	}
+	db.mu.Unlock()
+
+	nodeIds := make([]uint8, 0, len(nodes))
+	for id := range nodes {
+		nodeIds = append(nodeIds, id)
+	}
+	slices.Sort(nodeIds)
+	nodeName := func(id uint8) string {
+		if name, ok := opts.NodeNames[id]; ok {
+			return dbcIdentifier(name)
+		}
+		return fmt.Sprintf("Node_%d", id)
+	}
+	scale := func(signal Signal) DBCScale {
+		if opts.Scale == nil {
+			return DBCScale{Factor: 1}
+		}
+		return opts.Scale(signal)
+	}
+	// Same lookup as for decoding
+	temp := NewPDODatabase()
+	temp.nodes = nodes
+	temp.rebuild()
+	cobIds := make([]uint16, 0, len(temp.cobIds))
+	for cobId := range temp.cobIds {
+		cobIds = append(cobIds, cobId)
+	}
+	slices.Sort(cobIds)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "VERSION \"\"\n\nNS_ :\n\nBS_:\n\nBU_:")
+	for _, id := range nodeIds {
+		fmt.Fprintf(bw, " %s", nodeName(id))
+	}
+	fmt.Fprint(bw, "\n")
+
+	comments := make([]string, 0)
+	valueTypes := make([]string, 0)
+	for _, cobId := range cobIds {
+		def := temp.cobIds[cobId]
+		transmitter := dbcNoNode
+		if def.Transmit {
+			transmitter = nodeName(def.NodeId)
+		}
+		receivers := make([]string, 0)
+		for _, id := range nodeIds {
+			for _, other := range nodes[id] {
+				if !other.Transmit && other.CobId == cobId && !slices.Contains(receivers, nodeName(id)) {
+					receivers = append(receivers, nodeName(id))
+				}
+			}
+		}
+		if len(receivers) == 0 {
+			receivers = append(receivers, dbcNoNode)
+		}
+		length := 0
+		for _, signal := range def.Signals {
+			length = max(length, signal.Offset+signal.Length)
+		}
+		kind := "RPDO"
+		if def.Transmit {
+			kind = "TPDO"
+		}
+		messageName := fmt.Sprintf("%s_%s%d", nodeName(def.NodeId), kind, def.Number)
+		fmt.Fprintf(bw, "\nBO_ %d %s: %d %s\n", cobId, messageName, (length+7)/8, transmitter)
+		comments = append(comments, fmt.Sprintf("CM_ BO_ %d \"%v\";", cobId, def))
+
+		names := make([]string, 0, len(def.Signals))
+		for _, signal := range def.Signals {
+			name := dbcIdentifier(signal.Name)
+			for i := 2; slices.Contains(names, name); i++ {
+				name = fmt.Sprintf("%s_%d", dbcIdentifier(signal.Name), i)
+			}
+			names = append(names, name)
+			s := scale(signal)
+			min, max, signed := dbcRange(signal)
+			sign := "+"
+			if signed {
+				sign = "-"
+			}
+			fmt.Fprintf(bw, " SG_ %s : %d|%d@1%s (%s,%s) [%s|%s] \"%s\" %s\n",
+				name, signal.Offset, signal.Length, sign,
+				dbcFloat(s.Factor), dbcFloat(s.Offset),
+				dbcFloat(min*s.Factor+s.Offset), dbcFloat(max*s.Factor+s.Offset),
+				s.Unit, strings.Join(receivers, ","))
+			comments = append(comments, fmt.Sprintf("CM_ SG_ %d %s \"node x%x object x%x|x%x\";",
+				cobId, name, signal.NodeId, signal.Index, signal.Subindex))
+			switch signal.DataType {
+			case od.REAL32:
+				valueTypes = append(valueTypes, fmt.Sprintf("SIG_VALTYPE_ %d %s : 1;", cobId, name))
+			case od.REAL64:
+				valueTypes = append(valueTypes, fmt.Sprintf("SIG_VALTYPE_ %d %s : 2;", cobId, name))
+			}
+		}
+	}
+	fmt.Fprint(bw, "\n")
+	for _, line := range append(comments, valueTypes...) {
+		fmt.Fprintln(bw, line)
+	}
+	return bw.Flush()
+}
+
+// WriteDBC exports the PDOs of all the nodes with an OD known to the network
+// (local nodes & remote nodes added with their EDS or DCF) as a DBC file,
+// see [PDODatabase.WriteDBC]. Node names default to the device name (0x1008)
+// followed by the node id. The PDO database of the network is not modified.
+func (network *Network) WriteDBC(w io.Writer, opts DBCOptions) error {
+	db := NewPDODatabase()
+	nodeIds := make([]uint8, 0)
+	for id := range network.odMap {
+		nodeIds = append(nodeIds, id)
+	}
+	for id := range network.controllers {
+		if !slices.Contains(nodeIds, id) {
+			nodeIds = append(nodeIds, id)
+		}
+	}
+	names := make(map[uint8]string)
+	for _, id := range nodeIds {
+		odict, err := network.GetOD(id)
+		if err != nil {
+			continue
+		}
+		err = db.SetNode(id, PDODefinitionsFromOD(id, odict))
+		if err != nil {
+			return err
+		}
+		names[id] = fmt.Sprintf("Node_%d", id)
+		if entry := odict.Index(0x1008); entry != nil {
+			if variable, err := entry.SubIndex(0); err == nil {
+				if name, err := od.DecodeToString(variable.DefaultValue(), od.VISIBLE_STRING, 0); err == nil && name != "" {
+					names[id] = fmt.Sprintf("%s_%d", name, id)
+				}
+			}
+		}
+	}
+	for id, name := range opts.NodeNames {
+		names[id] = name
+	}
+	opts.NodeNames = names
+	return db.WriteDBC(w, opts)
+}
@@ -0,0 +1,73 @@
+package network
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/pdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteDBC(t *testing.T) {
+	t.Run("database", func(t *testing.T) {
+		db := NewPDODatabase()
+		err := db.SetNode(0x10, []PDODefinition{
+			{NodeId: 0x10, Transmit: true, Number: 1, CobId: 0x190, Signals: []Signal{
+				{NodeId: 0x10, Name: "INTEGER8 value", Index: 0x2002, DataType: od.INTEGER8, Offset: 0, Length: 8},
+				{NodeId: 0x10, Name: "REAL32 value", Index: 0x2008, DataType: od.REAL32, Offset: 8, Length: 32},
+			}},
+		})
+		assert.Nil(t, err)
+		err = db.SetNode(0x20, []PDODefinition{
+			{NodeId: 0x20, Transmit: false, Number: 1, CobId: 0x190, Signals: []Signal{
+				{NodeId: 0x20, Name: "x3000|x1", Index: 0x3000, Subindex: 1, DataType: od.INTEGER8, Offset: 0, Length: 8},
+			}},
+		})
+		assert.Nil(t, err)
+		buffer := &bytes.Buffer{}
+		err = db.WriteDBC(buffer, DBCOptions{
+			NodeNames: map[uint8]string{0x10: "my sensor"},
+			Scale: func(signal Signal) DBCScale {
+				if signal.Index == 0x2002 {
+					return DBCScale{Factor: 0.5, Offset: 10, Unit: "degC"}
+				}
+				return DBCScale{Factor: 1}
+			},
+		})
+		assert.Nil(t, err)
+		dbc := buffer.String()
+		assert.Contains(t, dbc, "BU_: my_sensor Node_32\n")
+		assert.Contains(t, dbc, "BO_ 400 my_sensor_TPDO1: 5 my_sensor\n")
+		assert.Contains(t, dbc, ` SG_ INTEGER8_value : 0|8@1- (0.5,10) [-54|73.5] "degC" Node_32`)
+		assert.Contains(t, dbc, ` SG_ REAL32_value : 8|32@1- (1,0)`)
+		assert.Contains(t, dbc, "SIG_VALTYPE_ 400 REAL32_value : 1;")
+		assert.Contains(t, dbc, `CM_ SG_ 400 INTEGER8_value "node x10 object x2002|x0";`)
+		assert.NotContains(t, dbc, "x3000")
+	})
+
+	t.Run("network", func(t *testing.T) {
+		network := CreateNetworkEmptyTest()
+		defer network.Disconnect()
+		local, err := network.CreateLocalNode(0x10, od.Default())
+		assert.Nil(t, err)
+		err = local.Configurator().WriteConfigurationPDO(pdo.MinTpdoNumber, config.PDOConfigurationParameter{
+			CanId:            0x190,
+			TransmissionType: 0xFE,
+			Mappings: []config.PDOMappingParameter{
+				{Index: 0x2006, Subindex: 0, LengthBits: 16},
+			},
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, local.Configurator().EnablePDO(pdo.MinTpdoNumber))
+		buffer := &bytes.Buffer{}
+		assert.Nil(t, network.WriteDBC(buffer, DBCOptions{}))
+		dbc := buffer.String()
+		assert.Contains(t, dbc, "BU_: DUT_16\n")
+		assert.Contains(t, dbc, "BO_ 400 DUT_16_TPDO1: 2 DUT_16\n")
+		assert.Contains(t, dbc, ` SG_ UNSIGNED16_value : 0|16@1+ (1,0) [0|65535] "" Vector__XXX`)
+		_, ok := network.PDODatabase().Lookup(0x190)
+		assert.False(t, ok)
+	})
+}
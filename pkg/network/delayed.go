@@ -0,0 +1,285 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+const DefaultDelayedWriteQueueSize = 64
+
+var ErrDelayedWriteCancelled = errors.New("delayed write cancelled before execution")
+
+// A write queued for execution once a node enters a given NMT state
+type DelayedWrite struct {
+	Id       uint64
+	NodeId   uint8
+	Index    uint16
+	Subindex uint8
+	Value    any
+	State    uint8 // NMT state required for executing the write, e.g. [nmt.StatePreOperational]
+	Queued   time.Time
+}
+
+// Result of a [DelayedWrite], Err is nil if write was successful
+type DelayedWriteResult struct {
+	DelayedWrite
+	Executed time.Time
+	Err      error
+}
+
+type DelayedWriteCallback func(result DelayedWriteResult)
+
+type pendingWrite struct {
+	DelayedWrite
+	callback DelayedWriteCallback
+}
+
+// Executes queued writes when the target node reports the expected
+// NMT state inside of its heartbeat.
+type delayedWriter struct {
+	logger     *slog.Logger
+	bm         *canopen.BusManager
+	client     *sdo.SDOClient
+	mu         sync.Mutex
+	nextId     uint64
+	pending    map[uint8][]*pendingWrite
+	subMu      sync.Mutex
+	subscribed map[uint8]bool
+	work       chan []*pendingWrite
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+func newDelayedWriter(bm *canopen.BusManager, logger *slog.Logger) (*delayedWriter, error) {
+	// Dedicated client so that execution does not interfere with the network's client
+	client, err := sdo.NewSDOClient(bm, logger, nil, 0, sdo.DefaultClientTimeout, nil)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	writer := &delayedWriter{
+		logger:     logger.With("service", "[DELAYED]"),
+		bm:         bm,
+		client:     client,
+		pending:    make(map[uint8][]*pendingWrite),
+		subscribed: make(map[uint8]bool),
+		work:       make(chan []*pendingWrite, DefaultDelayedWriteQueueSize),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go writer.run(ctx)
+	return writer, nil
+}
+
+// Handle heartbeats of nodes with pending writes
+func (writer *delayedWriter) Handle(frame canopen.Frame) {
+	if frame.DLC != 1 {
+		return
+	}
+	nodeId := uint8(frame.ID - heartbeat.ServiceId)
+	state := frame.Data[0]
+
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	ready := []*pendingWrite{}
+	remaining := []*pendingWrite{}
+	for _, write := range writer.pending[nodeId] {
+		if write.State == state {
+			ready = append(ready, write)
+		} else {
+			remaining = append(remaining, write)
+		}
+	}
+	if len(ready) == 0 {
+		return
+	}
+	select {
+	case writer.work <- ready:
+		writer.pending[nodeId] = remaining
+	default:
+		// Queue is full, retry on next heartbeat
+		writer.logger.Warn("execution queue full, postponing writes", "id", nodeId, "count", len(ready))
+	}
+}
+
+// Execute writes in order, one at a time
+func (writer *delayedWriter) run(ctx context.Context) {
+	defer close(writer.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case writes := <-writer.work:
+			for _, write := range writes {
+				err := writer.client.WriteRawCtx(ctx, write.NodeId, write.Index, write.Subindex, write.Value, false)
+				if ctx.Err() != nil {
+					err = ErrDelayedWriteCancelled
+				}
+				writer.logger.Info("executed write",
+					"id", write.NodeId,
+					"index", write.Index,
+					"subindex", write.Subindex,
+					"error", err,
+				)
+				writer.notify(write, err)
+			}
+		}
+	}
+}
+
+func (writer *delayedWriter) notify(write *pendingWrite, err error) {
+	if write.callback == nil {
+		return
+	}
+	write.callback(DelayedWriteResult{DelayedWrite: write.DelayedWrite, Executed: time.Now(), Err: err})
+}
+
+func (writer *delayedWriter) queue(write DelayedWrite, callback DelayedWriteCallback) (uint64, error) {
+	// Subscription is done without holding mu, as reception holds
+	// bus manager lock when calling Handle
+	writer.subMu.Lock()
+	if !writer.subscribed[write.NodeId] {
+		err := writer.bm.Subscribe(uint32(heartbeat.ServiceId)+uint32(write.NodeId), 0x7FF, false, writer)
+		if err != nil {
+			writer.subMu.Unlock()
+			return 0, err
+		}
+		writer.subscribed[write.NodeId] = true
+	}
+	writer.subMu.Unlock()
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	writer.nextId++
+	write.Id = writer.nextId
+	write.Queued = time.Now()
+	writer.pending[write.NodeId] = append(writer.pending[write.NodeId], &pendingWrite{DelayedWrite: write, callback: callback})
+	return write.Id, nil
+}
+
+// Stop execution, every write not yet executed is cancelled
+func (writer *delayedWriter) stop() {
+	writer.cancel()
+	<-writer.done
+	writer.mu.Lock()
+	cancelled := []*pendingWrite{}
+	for _, writes := range writer.pending {
+		cancelled = append(cancelled, writes...)
+	}
+	writer.pending = make(map[uint8][]*pendingWrite)
+	writer.mu.Unlock()
+	for {
+		select {
+		case writes := <-writer.work:
+			cancelled = append(cancelled, writes...)
+		default:
+			for _, write := range cancelled {
+				writer.notify(write, ErrDelayedWriteCancelled)
+			}
+			return
+		}
+	}
+}
+
+// QueueWrite queues an SDO write to nodeId that is executed automatically
+// the next time the node reports the given NMT state in its heartbeat.
+// This is useful for objects that are only writable in a specific state,
+// e.g. PDO mapping in [nmt.StatePreOperational]. The node must produce heartbeats.
+// Optional callback is called with the result of the write, it should not block.
+// It returns an id that can be used with [Network.CancelWrite].
+func (network *Network) QueueWrite(
+	nodeId uint8,
+	index uint16,
+	subindex uint8,
+	value any,
+	state uint8,
+	callback DelayedWriteCallback,
+) (uint64, error) {
+	if nodeId < nodeIdMin || nodeId > 127 {
+		return 0, ErrIdRange
+	}
+	if state != nmt.StatePreOperational && state != nmt.StateOperational && state != nmt.StateStopped {
+		return 0, canopen.ErrIllegalArgument
+	}
+	if !network.connected.Load() {
+		return 0, ErrNotConnected
+	}
+	network.delayedMu.Lock()
+	defer network.delayedMu.Unlock()
+	if network.delayed == nil {
+		writer, err := newDelayedWriter(network.BusManager, network.logger)
+		if err != nil {
+			return 0, err
+		}
+		network.delayed = writer
+	}
+	return network.delayed.queue(DelayedWrite{
+		NodeId:   nodeId,
+		Index:    index,
+		Subindex: subindex,
+		Value:    value,
+		State:    state,
+	}, callback)
+}
+
+// CancelWrite removes a queued write that has not been executed yet.
+// Its callback is called with [ErrDelayedWriteCancelled].
+// Returns false if the write was not found, e.g. already executed.
+func (network *Network) CancelWrite(id uint64) bool {
+	network.delayedMu.Lock()
+	writer := network.delayed
+	network.delayedMu.Unlock()
+	if writer == nil {
+		return false
+	}
+	writer.mu.Lock()
+	for nodeId, writes := range writer.pending {
+		for i, write := range writes {
+			if write.Id != id {
+				continue
+			}
+			writer.pending[nodeId] = append(writes[:i:i], writes[i+1:]...)
+			writer.mu.Unlock()
+			writer.notify(write, ErrDelayedWriteCancelled)
+			return true
+		}
+	}
+	writer.mu.Unlock()
+	return false
+}
+
+// QueuedWrites returns the writes still waiting for execution for nodeId,
+// oldest first.
+func (network *Network) QueuedWrites(nodeId uint8) []DelayedWrite {
+	network.delayedMu.Lock()
+	writer := network.delayed
+	network.delayedMu.Unlock()
+	if writer == nil {
+		return nil
+	}
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	writes := make([]DelayedWrite, 0, len(writer.pending[nodeId]))
+	for _, write := range writer.pending[nodeId] {
+		writes = append(writes, write.DelayedWrite)
+	}
+	return writes
+}
+
+// Stop execution of delayed writes, if any
+func (network *Network) stopDelayedWrites() {
+	network.delayedMu.Lock()
+	writer := network.delayed
+	network.delayed = nil
+	network.delayedMu.Unlock()
+	if writer != nil {
+		writer.stop()
+	}
+}
@@ -0,0 +1,65 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelayedWrite(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	assert.Nil(t, local.Configurator().WriteHeartbeatPeriod(20))
+
+	_, err = network.QueueWrite(NodeIdTest, 0x2002, 0, int8(0x10), nmt.StateInitializing, nil)
+	assert.Equal(t, canopen.ErrIllegalArgument, err)
+	_, err = network.QueueWrite(0, 0x2002, 0, int8(0x10), nmt.StatePreOperational, nil)
+	assert.Equal(t, ErrIdRange, err)
+
+	results := make(chan DelayedWriteResult, 10)
+	callback := func(result DelayedWriteResult) { results <- result }
+
+	t.Run("executed when entering state", func(t *testing.T) {
+		id, err := network.QueueWrite(NodeIdTest, 0x2002, 0, int8(0x10), nmt.StatePreOperational, callback)
+		assert.Nil(t, err)
+		// Node is operational, nothing should be executed
+		time.Sleep(100 * time.Millisecond)
+		assert.Len(t, network.QueuedWrites(NodeIdTest), 1)
+		assert.Empty(t, results)
+
+		assert.Nil(t, network.Command(NodeIdTest, nmt.CommandEnterPreOperational))
+		select {
+		case result := <-results:
+			assert.Nil(t, result.Err)
+			assert.Equal(t, id, result.Id)
+		case <-time.After(2 * time.Second):
+			t.Fatal("delayed write not executed")
+		}
+		assert.Empty(t, network.QueuedWrites(NodeIdTest))
+		val, _ := local.ReadInt("INTEGER8 value", "")
+		assert.EqualValues(t, 0x10, val)
+		assert.Nil(t, network.Command(NodeIdTest, nmt.CommandEnterOperational))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		id, err := network.QueueWrite(NodeIdTest, 0x2002, 0, int8(0x20), nmt.StateStopped, callback)
+		assert.Nil(t, err)
+		assert.True(t, network.CancelWrite(id))
+		assert.False(t, network.CancelWrite(id))
+		result := <-results
+		assert.Equal(t, ErrDelayedWriteCancelled, result.Err)
+	})
+
+	t.Run("cancelled on disconnect", func(t *testing.T) {
+		_, err := network.QueueWrite(NodeIdTest, 0x2002, 0, int8(0x20), nmt.StateStopped, callback)
+		assert.Nil(t, err)
+		network.Disconnect()
+		result := <-results
+		assert.Equal(t, ErrDelayedWriteCancelled, result.Err)
+	})
+}
@@ -0,0 +1,301 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+)
+
+var ErrDiscoveryRunning = errors.New("discovery is already running")
+
+// Kind of [DiscoveryEvent]
+type DiscoveryEventType uint8
+
+const (
+	NodeAppeared    DiscoveryEventType = 0 // Node was found by a scan or sent a heartbeat
+	NodeDisappeared DiscoveryEventType = 1 // Node was lost, see [DiscoveryOptions]
+)
+
+var DiscoveryEventDescription = map[DiscoveryEventType]string{
+	NodeAppeared:    "APPEARED",
+	NodeDisappeared: "DISAPPEARED",
+}
+
+func (t DiscoveryEventType) String() string {
+	return DiscoveryEventDescription[t]
+}
+
+// A node known by the discovery service
+type DiscoveredNode struct {
+	Id       uint8
+	Info     NodeInformation // Identity & manufacturer information are only set if found by a scan
+	Scanned  bool            // Node responded to a scan
+	LastSeen time.Time       // Last heartbeat or scan response
+}
+
+// DiscoveryEvent is emitted when a node appears or disappears
+type DiscoveryEvent struct {
+	Type DiscoveryEventType
+	Node DiscoveredNode
+	Time time.Time
+}
+
+type DiscoveryCallback func(event DiscoveryEvent)
+
+// DiscoveryOptions configures [Network.StartDiscovery]
+type DiscoveryOptions struct {
+	// Period between SDO scans of the network, see [Network.ScanWith].
+	// Nodes that do not respond to a scan and did not send a heartbeat since the scan
+	// started disappear. 0 disables scanning, nodes are then only learnt from
+	// heartbeats & boot-ups.
+	ScanPeriod time.Duration
+	// Options used for every scan, Progress is ignored
+	Scan ScanOptions
+	// Nodes not seen for this long disappear, 0 to disable.
+	// Without it, nodes that are only learnt from heartbeats disappear
+	// on heartbeat loss, which requires a timeout, see [Network.SetHeartbeatTimeout].
+	ExpireAfter time.Duration
+}
+
+// Background discovery of nodes, see [Network.StartDiscovery]
+type discovery struct {
+	network *Network
+	opts    DiscoveryOptions
+	nodes   map[uint8]*DiscoveredNode
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// Add a callback that is called when a node appears or disappears.
+// Callbacks are called from the discovery goroutine and should not block.
+func (network *Network) OnDiscoveryEvent(callback DiscoveryCallback) {
+	network.discoveryMu.Lock()
+	defer network.discoveryMu.Unlock()
+	network.discoveryCallbacks = append(network.discoveryCallbacks, callback)
+}
+
+// StartDiscovery starts keeping an up-to-date list of the nodes of the network in
+// the background. Nodes are learnt passively from their heartbeats & boot-ups,
+// and optionally by scanning the network periodically.
+// Discovery is stopped with [Network.StopDiscovery] or on disconnect.
+func (network *Network) StartDiscovery(opts DiscoveryOptions) error {
+	if !network.Connected() || network.heartbeats == nil {
+		return ErrNotConnected
+	}
+	network.discoveryMu.Lock()
+	defer network.discoveryMu.Unlock()
+	if network.discovery != nil {
+		return ErrDiscoveryRunning
+	}
+	opts.Scan.Progress = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &discovery{
+		network: network,
+		opts:    opts,
+		nodes:   make(map[uint8]*DiscoveredNode),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	network.discovery = d
+	events := network.heartbeats.NodeEvents(ctx, heartbeat.EventAllNodes, 100)
+	go func() {
+		defer close(d.done)
+		d.run(ctx, events)
+	}()
+	return nil
+}
+
+// StopDiscovery stops the discovery service and waits for it to finish.
+// Known nodes are forgotten, no disappearance events are emitted.
+func (network *Network) StopDiscovery() {
+	network.discoveryMu.Lock()
+	d := network.discovery
+	network.discovery = nil
+	network.discoveryMu.Unlock()
+	if d == nil {
+		return
+	}
+	d.cancel()
+	<-d.done
+}
+
+// DiscoveredNodes returns the nodes currently known by the discovery service
+func (network *Network) DiscoveredNodes() map[uint8]DiscoveredNode {
+	network.discoveryMu.Lock()
+	defer network.discoveryMu.Unlock()
+	nodes := make(map[uint8]DiscoveredNode)
+	if network.discovery == nil {
+		return nodes
+	}
+	for id, node := range network.discovery.nodes {
+		nodes[id] = *node
+	}
+	return nodes
+}
+
+func (network *Network) emitDiscoveryEvent(eventType DiscoveryEventType, node DiscoveredNode) {
+	network.logger.Info("discovery", "id", node.Id, "event", eventType)
+	network.discoveryMu.Lock()
+	callbacks := append([]DiscoveryCallback{}, network.discoveryCallbacks...)
+	network.discoveryMu.Unlock()
+	event := DiscoveryEvent{Type: eventType, Node: node, Time: time.Now()}
+	for _, callback := range callbacks {
+		callback(event)
+	}
+}
+
+func (d *discovery) run(ctx context.Context, events <-chan heartbeat.Event) {
+	var scanTicker, expireTicker <-chan time.Time
+	var scanDone chan scanResult
+	if d.opts.ScanPeriod > 0 {
+		ticker := time.NewTicker(d.opts.ScanPeriod)
+		defer ticker.Stop()
+		scanTicker = ticker.C
+		scanDone = make(chan scanResult, 1)
+		go d.scan(ctx, scanDone)
+	}
+	if d.opts.ExpireAfter > 0 {
+		ticker := time.NewTicker(d.opts.ExpireAfter / 4)
+		defer ticker.Stop()
+		expireTicker = ticker.C
+	}
+	scanning := scanDone != nil
+	for {
+		select {
+		case <-ctx.Done():
+			// Wait for the scan in progress, it stops early on cancel
+			if scanning {
+				<-scanDone
+			}
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			d.handleHeartbeat(event)
+		case <-scanTicker:
+			// Don't start a new scan until previous one is finished
+			if !scanning {
+				scanning = true
+				go d.scan(ctx, scanDone)
+			}
+		case result := <-scanDone:
+			scanning = false
+			if result.err == nil {
+				d.handleScan(result)
+			}
+		case now := <-expireTicker:
+			d.expire(now)
+		}
+	}
+}
+
+type scanResult struct {
+	start time.Time
+	nodes map[uint8]NodeInformation
+	err   error
+}
+
+func (d *discovery) scan(ctx context.Context, result chan<- scanResult) {
+	start := time.Now()
+	nodes, err := d.network.ScanWith(ctx, d.opts.Scan)
+	result <- scanResult{start: start, nodes: nodes, err: err}
+}
+
+// Update the node from a heartbeat & returns true if it just appeared
+func (d *discovery) seen(nodeId uint8, at time.Time, nmtState uint8) (DiscoveredNode, bool) {
+	d.network.discoveryMu.Lock()
+	defer d.network.discoveryMu.Unlock()
+	node, ok := d.nodes[nodeId]
+	if !ok {
+		node = &DiscoveredNode{Id: nodeId}
+		d.nodes[nodeId] = node
+	}
+	node.LastSeen = at
+	node.Info.NmtState = nmtState
+	return *node, !ok
+}
+
+// Remove the node & returns it, if it was known
+func (d *discovery) lost(nodeId uint8) (DiscoveredNode, bool) {
+	d.network.discoveryMu.Lock()
+	defer d.network.discoveryMu.Unlock()
+	node, ok := d.nodes[nodeId]
+	if !ok {
+		return DiscoveredNode{}, false
+	}
+	delete(d.nodes, nodeId)
+	return *node, true
+}
+
+func (d *discovery) handleHeartbeat(event heartbeat.Event) {
+	if event.Type == heartbeat.EventTimeout {
+		if node, ok := d.lost(event.NodeId); ok {
+			d.network.emitDiscoveryEvent(NodeDisappeared, node)
+		}
+		return
+	}
+	// Guarding toggle errors are not a sign of presence
+	if event.Type == heartbeat.EventToggle {
+		return
+	}
+	node, appeared := d.seen(event.NodeId, event.Time, event.NmtState)
+	if appeared {
+		d.network.emitDiscoveryEvent(NodeAppeared, node)
+	}
+}
+
+func (d *discovery) handleScan(result scanResult) {
+	now := time.Now()
+	appeared := make([]DiscoveredNode, 0)
+	disappeared := make([]DiscoveredNode, 0)
+	d.network.discoveryMu.Lock()
+	for id, info := range result.nodes {
+		node, ok := d.nodes[id]
+		if !ok {
+			node = &DiscoveredNode{Id: id}
+			d.nodes[id] = node
+		}
+		node.Info = info
+		node.Scanned = true
+		node.LastSeen = now
+		if !ok {
+			appeared = append(appeared, *node)
+		}
+	}
+	for id, node := range d.nodes {
+		if _, found := result.nodes[id]; found || !node.LastSeen.Before(result.start) {
+			continue
+		}
+		if d.opts.Scan.NodeIds != nil && !slices.Contains(d.opts.Scan.NodeIds, id) {
+			continue
+		}
+		delete(d.nodes, id)
+		disappeared = append(disappeared, *node)
+	}
+	d.network.discoveryMu.Unlock()
+	for _, node := range appeared {
+		d.network.emitDiscoveryEvent(NodeAppeared, node)
+	}
+	for _, node := range disappeared {
+		d.network.emitDiscoveryEvent(NodeDisappeared, node)
+	}
+}
+
+func (d *discovery) expire(now time.Time) {
+	expired := make([]DiscoveredNode, 0)
+	d.network.discoveryMu.Lock()
+	for id, node := range d.nodes {
+		if now.Sub(node.LastSeen) > d.opts.ExpireAfter {
+			delete(d.nodes, id)
+			expired = append(expired, *node)
+		}
+	}
+	d.network.discoveryMu.Unlock()
+	for _, node := range expired {
+		d.network.emitDiscoveryEvent(NodeDisappeared, node)
+	}
+}
@@ -0,0 +1,86 @@
+package network
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+type discoveryRecorder struct {
+	mu     sync.Mutex
+	events []DiscoveryEvent
+}
+
+func (r *discoveryRecorder) record(event DiscoveryEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *discoveryRecorder) has(eventType DiscoveryEventType, nodeId uint8) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, event := range r.events {
+		if event.Type == eventType && event.Node.Id == nodeId {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDiscoveryHeartbeat(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	network2 := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	defer network2.Disconnect()
+	recorder := discoveryRecorder{}
+	network2.OnDiscoveryEvent(recorder.record)
+	assert.Nil(t, network2.StartDiscovery(DiscoveryOptions{ExpireAfter: 200 * time.Millisecond}))
+	assert.Equal(t, ErrDiscoveryRunning, network2.StartDiscovery(DiscoveryOptions{}))
+
+	local, err := network.CreateLocalNode(0x30, od.Default())
+	assert.Nil(t, err)
+	assert.Nil(t, local.SetHeartbeatPeriod(20))
+	assert.Eventually(t, func() bool { return recorder.has(NodeAppeared, 0x30) }, time.Second, 10*time.Millisecond)
+	nodes := network2.DiscoveredNodes()
+	assert.Contains(t, nodes, uint8(0x30))
+	assert.False(t, nodes[0x30].Scanned)
+
+	// No more heartbeats, node expires
+	assert.Nil(t, local.SetHeartbeatPeriod(0))
+	assert.Eventually(t, func() bool { return recorder.has(NodeDisappeared, 0x30) }, time.Second, 10*time.Millisecond)
+	assert.Empty(t, network2.DiscoveredNodes())
+
+	network2.StopDiscovery()
+	assert.Empty(t, network2.DiscoveredNodes())
+}
+
+func TestDiscoveryScan(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	network2 := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	defer network2.Disconnect()
+	local, err := network.CreateLocalNode(0x31, od.Default())
+	assert.Nil(t, err)
+	assert.Nil(t, local.SetHeartbeatPeriod(0))
+
+	recorder := discoveryRecorder{}
+	network2.OnDiscoveryEvent(recorder.record)
+	err = network2.StartDiscovery(DiscoveryOptions{
+		ScanPeriod: 300 * time.Millisecond,
+		Scan:       ScanOptions{TimeoutMs: 50, NodeIds: []uint8{0x31, 0x32}},
+	})
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool { return recorder.has(NodeAppeared, 0x31) }, time.Second, 10*time.Millisecond)
+	// Node may first appear from its boot-up
+	assert.Eventually(t, func() bool { return network2.DiscoveredNodes()[0x31].Scanned }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "DUT", network2.DiscoveredNodes()[0x31].Info.ManufacturerDeviceName)
+
+	// Node does not respond to next scan
+	assert.Nil(t, network.RemoveNode(0x31))
+	assert.Eventually(t, func() bool { return recorder.has(NodeDisappeared, 0x31) }, 2*time.Second, 10*time.Millisecond)
+	assert.False(t, recorder.has(NodeAppeared, 0x32))
+}
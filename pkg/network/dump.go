@@ -0,0 +1,271 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// An index range scanned by [Network.DumpRemoteOD]
+type DumpRange struct {
+	Start uint16
+	End   uint16
+}
+
+// Communication, manufacturer & standardized profile areas
+var DefaultDumpRanges = []DumpRange{
+	{Start: 0x1000, End: 0x1FFF},
+	{Start: 0x2000, End: 0x5FFF},
+	{Start: 0x6000, End: 0x9FFF},
+}
+
+// DumpOptions configures [Network.DumpRemoteOD]
+type DumpOptions struct {
+	// Index ranges to scan, defaults to [DefaultDumpRanges]
+	Ranges []DumpRange
+	// Scan the node even if it stores its EDS (0x1021)
+	NoEDS bool
+	// OD used for names, data types & access of the objects found,
+	// e.g. a device profile. Defaults to [od.Default] for the communication area only.
+	Reference *od.ObjectDictionary
+}
+
+// DumpRemoteOD creates an approximation of the OD of a remote node, for
+// devices whose EDS is not available. If the node stores its EDS (0x1021),
+// it is used directly. Otherwise every index of the configured ranges is read
+// with SDO : objects that do not exist are detected with SDO aborts.
+// Objects that are not found in the reference OD are given generic names,
+// their data types are guessed from their size & content. The result can then be
+// exported with [od.ObjectDictionary.ExportEDS].
+//
+// Scanning all ranges takes a few thousand SDO transfers, ctx can be used to stop it.
+func (network *Network) DumpRemoteOD(ctx context.Context, nodeId uint8, opts DumpOptions) (*od.ObjectDictionary, error) {
+	if !opts.NoEDS {
+		odict, err := network.ReadEDS(nodeId, nil)
+		if err == nil {
+			return odict, nil
+		}
+		network.logger.Info("EDS not readable, scanning node", "id", nodeId, "error", err)
+	}
+	if opts.Ranges == nil {
+		opts.Ranges = DefaultDumpRanges
+	}
+	client, err := network.SDOClientFor(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	dumper := &odDumper{
+		ctx:       ctx,
+		client:    client,
+		nodeId:    nodeId,
+		reference: opts.Reference,
+		odict:     od.NewOD(),
+	}
+	if dumper.reference == nil {
+		dumper.reference = od.Default()
+		dumper.referenceEnd = 0x1FFF
+	}
+	for _, r := range opts.Ranges {
+		for index := uint32(r.Start); index <= uint32(r.End); index++ {
+			err = dumper.dumpIndex(uint16(index))
+			if err != nil {
+				return nil, fmt.Errorf("dump x%x : %w", index, err)
+			}
+		}
+	}
+	network.logger.Info("finished dumping OD", "id", nodeId, "entries", len(dumper.odict.Entries()))
+	return dumper.odict, nil
+}
+
+type odDumper struct {
+	ctx          context.Context
+	client       *sdo.SDOClient
+	nodeId       uint8
+	reference    *od.ObjectDictionary
+	referenceEnd uint16 // Last index described by reference, 0 for all
+	odict        *od.ObjectDictionary
+}
+
+// Result of reading a sub-index
+type dumpedValue struct {
+	data     []byte
+	readable bool
+}
+
+// Read a sub-index, exists is false if object or sub-index does not exist.
+// Other SDO aborts mean that the object exists but can not be read.
+func (dumper *odDumper) read(index uint16, subindex uint8) (value dumpedValue, exists bool, err error) {
+	data, err := dumper.client.ReadAllCtx(dumper.ctx, dumper.nodeId, index, subindex)
+	if err == nil {
+		return dumpedValue{data: data, readable: true}, true, nil
+	}
+	var abort sdo.Abort
+	if !errors.As(err, &abort) {
+		return value, false, err
+	}
+	switch abort {
+	case sdo.AbortNotExist, sdo.AbortSubUnknown:
+		return value, false, nil
+	case sdo.AbortTimeout:
+		return value, false, err
+	default:
+		return value, true, nil
+	}
+}
+
+func (dumper *odDumper) dumpIndex(index uint16) error {
+	sub0, exists, err := dumper.read(index, 0)
+	if err != nil || !exists {
+		return err
+	}
+	// A single variable has no sub-index 1, highest sub-index is always 1 byte
+	var sub1 dumpedValue
+	isList := false
+	if sub0.readable && len(sub0.data) == 1 {
+		sub1, isList, err = dumper.read(index, 1)
+		if err != nil {
+			return err
+		}
+	}
+	reference := dumper.referenceEntry(index)
+	name := fmt.Sprintf("Object %04X", index)
+	if reference != nil {
+		name = reference.Name
+	}
+	if !isList {
+		variable, err := dumper.newVariable(reference, 0, sub0)
+		if err != nil {
+			return err
+		}
+		entry, err := dumper.odict.AddVariableType(index, name, variable.DataType, variable.Attribute, stringValue(variable.DataType, sub0))
+		if err != nil {
+			return err
+		}
+		return dumper.writeValue(entry, 0, variable, sub0)
+	}
+
+	// ARRAY if all sub-indexes exist with the same size, RECORD otherwise.
+	// Sub-index 1 is kept even if highest sub-index is 0, e.g. empty error history
+	values := map[uint8]dumpedValue{0: sub0, 1: sub1}
+	highest := max(sub0.data[0], 1)
+	isArray := true
+	for subindex := 2; subindex <= int(highest); subindex++ {
+		value, exists, err := dumper.read(index, uint8(subindex))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			isArray = false
+			continue
+		}
+		isArray = isArray && len(value.data) == len(sub1.data)
+		values[uint8(subindex)] = value
+	}
+	// ARRAY length is limited to 255 sub-indexes
+	isArray = isArray && highest < 0xFF
+	list := od.NewRecord()
+	if isArray {
+		list = od.NewArray(highest + 1)
+	}
+	variables := map[uint8]*od.Variable{}
+	for subindex := 0; subindex <= int(highest); subindex++ {
+		value, ok := values[uint8(subindex)]
+		if !ok {
+			continue
+		}
+		variable, err := dumper.newVariable(reference, uint8(subindex), value)
+		if err != nil {
+			return err
+		}
+		variables[uint8(subindex)] = variable
+		if isArray {
+			list.Variables[subindex] = variable
+		} else {
+			list.Variables = append(list.Variables, variable)
+		}
+	}
+	entry := dumper.odict.AddVariableList(index, name, list)
+	for subindex, variable := range variables {
+		err = dumper.writeValue(entry, subindex, variable, values[subindex])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dumper *odDumper) referenceEntry(index uint16) *od.Entry {
+	if dumper.referenceEnd != 0 && index > dumper.referenceEnd {
+		return nil
+	}
+	return dumper.reference.Index(index)
+}
+
+// Create a variable for a read value, using reference if it matches.
+// Value of fixed size data types is written afterwards, see [odDumper.writeValue]
+func (dumper *odDumper) newVariable(reference *od.Entry, subindex uint8, value dumpedValue) (*od.Variable, error) {
+	name := fmt.Sprintf("Sub-index %d", subindex)
+	if subindex == 0 {
+		name = "Highest sub-index supported"
+	}
+	dataType := guessDataType(value.data)
+	attribute := od.AttributeSdoRw
+	if !value.readable {
+		dataType = od.DOMAIN
+		attribute = od.AttributeSdoW
+	}
+	if refVariable, err := reference.SubIndex(int(subindex)); err == nil &&
+		(!value.readable || od.CheckSize(len(value.data), refVariable.DataType) == nil) {
+		name = refVariable.Name
+		dataType = refVariable.DataType
+		attribute = refVariable.Attribute
+	}
+	return od.NewVariable(subindex, name, dataType, attribute, stringValue(dataType, value))
+}
+
+// Value of string data types, other values are written with [odDumper.writeValue]
+func stringValue(dataType uint8, value dumpedValue) string {
+	if dataType == od.VISIBLE_STRING || dataType == od.OCTET_STRING {
+		return string(value.data)
+	}
+	return ""
+}
+
+// Write read value of fixed size data types
+func (dumper *odDumper) writeValue(entry *od.Entry, subindex uint8, variable *od.Variable, value dumpedValue) error {
+	switch variable.DataType {
+	case od.VISIBLE_STRING, od.OCTET_STRING, od.DOMAIN:
+		return nil
+	}
+	if !value.readable {
+		return nil
+	}
+	return entry.WriteExactly(subindex, value.data, true)
+}
+
+// Guess the data type of a value from its size & content
+func guessDataType(data []byte) uint8 {
+	switch len(data) {
+	case 1:
+		return od.UNSIGNED8
+	case 2:
+		return od.UNSIGNED16
+	case 4:
+		return od.UNSIGNED32
+	case 8:
+		return od.UNSIGNED64
+	}
+	if len(data) == 0 {
+		return od.DOMAIN
+	}
+	for _, b := range data {
+		if b > unicode.MaxASCII || !unicode.IsPrint(rune(b)) {
+			return od.DOMAIN
+		}
+	}
+	return od.VISIBLE_STRING
+}
@@ -0,0 +1,68 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpRemoteOD(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+
+	t.Run("from stored EDS", func(t *testing.T) {
+		odict, err := network.DumpRemoteOD(context.Background(), NodeIdTest, DumpOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, len(od.Default().Entries()), len(odict.Entries()))
+	})
+
+	t.Run("scan", func(t *testing.T) {
+		odict, err := network.DumpRemoteOD(context.Background(), NodeIdTest, DumpOptions{
+			NoEDS:  true,
+			Ranges: []DumpRange{{Start: 0x1000, End: 0x101A}, {Start: 0x2000, End: 0x2011}},
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, odict.Index(0x1002))
+		assert.Nil(t, odict.Index(0x2000))
+
+		// Communication objects are described by reference
+		name, err := odict.Index(0x1008).SubIndex(0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, od.VISIBLE_STRING, name.DataType)
+		assert.Equal(t, "Manufacturer device name", odict.Index(0x1008).Name)
+		vendorId, err := odict.Index(od.EntryIdentityObject).Uint32(1)
+		assert.Nil(t, err)
+		expected, _ := od.Default().Index(od.EntryIdentityObject).Uint32(1)
+		assert.Equal(t, expected, vendorId)
+
+		// Other objects are guessed
+		assert.Equal(t, "Object 2007", odict.Index(0x2007).Name)
+		value, err := odict.Index(0x2007).Uint32(0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x22222222, value)
+		str, err := odict.Index(0x2009).SubIndex(0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, od.VISIBLE_STRING, str.DataType)
+		real64, err := odict.Index(0x2011).Uint64(0)
+		assert.Nil(t, err)
+		expected64, _ := od.Default().Index(0x2011).Uint64(0)
+		assert.Equal(t, expected64, real64)
+
+		// Exported as EDS
+		buf := &bytes.Buffer{}
+		assert.Nil(t, odict.ExportEDS(buf))
+		parsed, err := od.Parse(buf.Bytes(), NodeIdTest)
+		assert.Nil(t, err)
+		assert.NotNil(t, parsed.Index(0x2007))
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := network.DumpRemoteOD(ctx, NodeIdTest, DumpOptions{NoEDS: true})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,93 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	n "github.com/samsamfire/gocanopen/pkg/node"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// DeviceEDSOptions configures [Network.AddRemoteNodeFromDevice]
+type DeviceEDSOptions struct {
+	// Directory where EDS files read from devices are cached, by identity
+	// (vendor id, product code & revision number). No caching if empty.
+	CacheDir string
+	// Read EDS from device even if it is cached, cache is then updated
+	Refresh bool
+	// Manufacturer specific format handler, see [Network.ReadEDS]
+	FormatHandler od.EDSFormatHandler
+}
+
+// Add a [RemoteNode] whose OD is read from the device itself via
+// object 0x1021 (EDS storage), in any format supported by the format handler.
+// With a cache directory, the EDS is only read once per device type,
+// so that a master can be brought up without any local EDS file.
+func (network *Network) AddRemoteNodeFromDevice(nodeId uint8, opts DeviceEDSOptions) (*n.RemoteNode, error) {
+	odict, path, err := network.readDeviceEDS(nodeId, opts)
+	if err != nil {
+		return nil, err
+	}
+	node, err := network.AddRemoteNode(nodeId, odict)
+	if err != nil {
+		return nil, err
+	}
+	network.odMap[nodeId].edsPath = path
+	return node, nil
+}
+
+// Read OD from cache if available, from device otherwise.
+// Returns path of cached EDS, if any
+func (network *Network) readDeviceEDS(nodeId uint8, opts DeviceEDSOptions) (*od.ObjectDictionary, string, error) {
+	if opts.CacheDir == "" {
+		odict, err := network.ReadEDS(nodeId, opts.FormatHandler)
+		return odict, "", err
+	}
+	identity, err := network.Configurator(nodeId).ReadIdentity()
+	if err != nil {
+		return nil, "", fmt.Errorf("read identity : %w", err)
+	}
+	path := filepath.Join(opts.CacheDir, fmt.Sprintf("%08x_%08x_%08x.eds",
+		identity.VendorId, identity.ProductCode, identity.RevisionNumber))
+	edsFormat := network.readEDSFormat(nodeId)
+
+	if !opts.Refresh {
+		rawEds, err := os.ReadFile(path)
+		if err == nil {
+			network.logger.Info("using cached EDS", "id", nodeId, "path", path)
+			odict, err := parseRawEDS(nodeId, rawEds, edsFormat, opts.FormatHandler)
+			return odict, path, err
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", err
+		}
+	}
+
+	rawEds, err := network.ReadAll(nodeId, od.EntryStoreEDS, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	// Only cache valid EDS
+	odict, err := parseRawEDS(nodeId, rawEds, edsFormat, opts.FormatHandler)
+	if err != nil {
+		return nil, "", err
+	}
+	err = os.MkdirAll(opts.CacheDir, 0755)
+	if err != nil {
+		return nil, "", err
+	}
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, rawEds, 0644)
+	if err != nil {
+		return nil, "", err
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return nil, "", err
+	}
+	network.logger.Info("cached EDS read from device", "id", nodeId, "path", path)
+	return odict, path, nil
+}
@@ -0,0 +1,61 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddRemoteNodeFromDevice(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	_, err := network.CreateLocalNode(NodeIdTest+1, "../../testdata/test_zipped_format.eds")
+	assert.Nil(t, err)
+	network2 := CreateNetworkEmptyTest()
+	defer network2.Disconnect()
+
+	t.Run("without cache", func(t *testing.T) {
+		remote, err := network2.AddRemoteNodeFromDevice(NodeIdTest+1, DeviceEDSOptions{})
+		assert.Nil(t, err)
+		assert.NotNil(t, remote.GetOD().Index(od.EntryStoreEDS))
+		assert.Nil(t, network2.RemoveNode(NodeIdTest+1))
+	})
+
+	t.Run("with cache", func(t *testing.T) {
+		cacheDir := filepath.Join(t.TempDir(), "eds")
+		remote, err := network2.AddRemoteNodeFromDevice(NodeIdTest, DeviceEDSOptions{CacheDir: cacheDir})
+		assert.Nil(t, err)
+		assert.NotNil(t, remote.GetOD().Index(od.EntryStoreEDS))
+		assert.Nil(t, network2.RemoveNode(NodeIdTest))
+		files, err := filepath.Glob(filepath.Join(cacheDir, "*.eds"))
+		assert.Nil(t, err)
+		assert.Len(t, files, 1)
+
+		// Cached EDS is used instead of device
+		odict := od.Default()
+		_, err = odict.AddVariableType(0x2FFE, "Cached only", od.UNSIGNED8, od.AttributeSdoRw, "0x1")
+		assert.Nil(t, err)
+		f, err := os.Create(files[0])
+		assert.Nil(t, err)
+		assert.Nil(t, odict.ExportEDS(f))
+		assert.Nil(t, f.Close())
+		remote, err = network2.AddRemoteNodeFromDevice(NodeIdTest, DeviceEDSOptions{CacheDir: cacheDir})
+		assert.Nil(t, err)
+		assert.NotNil(t, remote.GetOD().Index(0x2FFE))
+		assert.Nil(t, network2.RemoveNode(NodeIdTest))
+
+		// Refresh from device
+		remote, err = network2.AddRemoteNodeFromDevice(NodeIdTest, DeviceEDSOptions{CacheDir: cacheDir, Refresh: true})
+		assert.Nil(t, err)
+		assert.Nil(t, remote.GetOD().Index(0x2FFE))
+		assert.Nil(t, network2.RemoveNode(NodeIdTest))
+	})
+
+	t.Run("no EDS on device", func(t *testing.T) {
+		_, err := network2.AddRemoteNodeFromDevice(0x60, DeviceEDSOptions{})
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,155 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/emergency"
+)
+
+const (
+	DefaultEmergencyHistorySize = 50
+	// Wildcard used with [Network.OnEmergency] for receiving EMCYs of all nodes
+	EmergencyAllNodes uint8 = 0
+)
+
+// An Emergency received from a node on the network
+type Emergency struct {
+	NodeId        uint8
+	Time          time.Time
+	ErrorCode     uint16
+	ErrorRegister byte
+	ErrorBit      byte
+	InfoCode      uint32
+}
+
+// Description of the error code
+func (e Emergency) Description() string {
+	return emergency.ErrorCodeDescription(e.ErrorCode)
+}
+
+func (e Emergency) String() string {
+	return fmt.Sprintf("node x%x : error code x%x (%v), register x%x, bit x%x, info x%x",
+		e.NodeId, e.ErrorCode, e.Description(), e.ErrorRegister, e.ErrorBit, e.InfoCode)
+}
+
+type EmergencyCallback func(emcy Emergency)
+
+// Consumer of all the EMCYs on the network
+type emergencyConsumer struct {
+	mu          sync.Mutex
+	historySize int
+	history     map[uint8][]Emergency
+	callbacks   map[uint8][]EmergencyCallback
+}
+
+func newEmergencyConsumer(bm *canopen.BusManager) (*emergencyConsumer, error) {
+	consumer := &emergencyConsumer{
+		historySize: DefaultEmergencyHistorySize,
+		history:     make(map[uint8][]Emergency),
+		callbacks:   make(map[uint8][]EmergencyCallback),
+	}
+	for nodeId := nodeIdMin; nodeId <= 127; nodeId++ {
+		err := bm.Subscribe(uint32(emergency.ServiceId)+uint32(nodeId), 0x7FF, false, consumer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return consumer, nil
+}
+
+// Handle EMCY frames of all nodes
+func (consumer *emergencyConsumer) Handle(frame canopen.Frame) {
+	if frame.DLC != 8 {
+		return
+	}
+	emcy := Emergency{
+		NodeId:        uint8(frame.ID - emergency.ServiceId),
+		Time:          time.Now(),
+		ErrorCode:     binary.LittleEndian.Uint16(frame.Data[0:2]),
+		ErrorRegister: frame.Data[2],
+		ErrorBit:      frame.Data[3],
+		InfoCode:      binary.LittleEndian.Uint32(frame.Data[4:8]),
+	}
+	consumer.mu.Lock()
+	history := append(consumer.history[emcy.NodeId], emcy)
+	if len(history) > consumer.historySize {
+		history = history[len(history)-consumer.historySize:]
+	}
+	consumer.history[emcy.NodeId] = history
+	callbacks := append([]EmergencyCallback{}, consumer.callbacks[emcy.NodeId]...)
+	callbacks = append(callbacks, consumer.callbacks[EmergencyAllNodes]...)
+	consumer.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(emcy)
+	}
+}
+
+// OnEmergency registers a callback called on every EMCY received from nodeId.
+// Use [EmergencyAllNodes] to receive EMCYs of every node.
+// Callbacks are called from the CAN reception and should not block.
+// This is available after [Network.Connect].
+func (network *Network) OnEmergency(nodeId uint8, callback EmergencyCallback) error {
+	if network.emergencies == nil {
+		return ErrNotConnected
+	}
+	if nodeId > 127 {
+		return ErrIdRange
+	}
+	consumer := network.emergencies
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	consumer.callbacks[nodeId] = append(consumer.callbacks[nodeId], callback)
+	return nil
+}
+
+// Emergencies returns the last EMCYs received from nodeId, oldest first.
+// Use [EmergencyAllNodes] to get the EMCYs of all the nodes, sorted by time.
+func (network *Network) Emergencies(nodeId uint8) []Emergency {
+	consumer := network.emergencies
+	if consumer == nil {
+		return nil
+	}
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	if nodeId != EmergencyAllNodes {
+		return append([]Emergency{}, consumer.history[nodeId]...)
+	}
+	all := []Emergency{}
+	for _, history := range consumer.history {
+		all = append(all, history...)
+	}
+	slices.SortStableFunc(all, func(a, b Emergency) int { return a.Time.Compare(b.Time) })
+	return all
+}
+
+// Clear the EMCY history of nodeId, or of all the nodes with [EmergencyAllNodes]
+func (network *Network) ClearEmergencies(nodeId uint8) {
+	consumer := network.emergencies
+	if consumer == nil {
+		return
+	}
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	if nodeId == EmergencyAllNodes {
+		consumer.history = make(map[uint8][]Emergency)
+		return
+	}
+	delete(consumer.history, nodeId)
+}
+
+// Set the maximum number of EMCYs kept per node
+func (network *Network) SetEmergencyHistorySize(size int) {
+	consumer := network.emergencies
+	if consumer == nil || size <= 0 {
+		return
+	}
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	consumer.historySize = size
+}
@@ -0,0 +1,177 @@
+package network
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/emergency"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmergencyRegistry(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+
+	mu := sync.Mutex{}
+	received := map[uint8]int{}
+	assert.Nil(t, network.OnEmergency(NodeIdTest, func(emcy Emergency) {
+		mu.Lock()
+		defer mu.Unlock()
+		received[NodeIdTest]++
+	}))
+	assert.Nil(t, network.OnEmergency(NodeIdTest+1, func(emcy Emergency) {
+		mu.Lock()
+		defer mu.Unlock()
+		received[NodeIdTest+1]++
+	}))
+	assert.Nil(t, network.OnEmergency(EmergencyAllNodes, func(emcy Emergency) {
+		mu.Lock()
+		defer mu.Unlock()
+		received[EmergencyAllNodes]++
+	}))
+	assert.Equal(t, ErrIdRange, network.OnEmergency(128, nil))
+
+	local.EMCY.ErrorReport(emergency.EmGenericError, emergency.ErrGeneric, 0x1234)
+	assert.Eventually(t, func() bool { return len(network.Emergencies(NodeIdTest)) == 1 }, time.Second, 10*time.Millisecond)
+
+	emcy := network.Emergencies(NodeIdTest)[0]
+	assert.EqualValues(t, emergency.ErrGeneric, emcy.ErrorCode)
+	assert.EqualValues(t, emergency.EmGenericError, emcy.ErrorBit)
+	assert.EqualValues(t, 0x1234, emcy.InfoCode)
+	assert.Equal(t, emergency.ErrorCodeDescription(emergency.ErrGeneric), emcy.Description())
+	assert.Len(t, network.Emergencies(EmergencyAllNodes), 1)
+	mu.Lock()
+	assert.Equal(t, map[uint8]int{NodeIdTest: 1, EmergencyAllNodes: 1}, received)
+	mu.Unlock()
+
+	network.ClearEmergencies(EmergencyAllNodes)
+	assert.Empty(t, network.Emergencies(NodeIdTest))
+}
+
+func TestEmergencyErrorStatus(t *testing.T) {
+	sim := NewSimulation()
+	defer sim.Close()
+	master, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	devices, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	local, err := devices.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+
+	// Error is only sent once while active, then reset
+	local.EMCY.ErrorReport(emergency.EmGenericError, emergency.ErrGeneric, 0)
+	local.EMCY.ErrorReport(emergency.EmGenericError, emergency.ErrGeneric, 0)
+	assert.True(t, local.EMCY.IsError(emergency.EmGenericError))
+	sim.Advance(10 * time.Millisecond)
+	local.EMCY.ErrorReset(emergency.EmGenericError, 0)
+	local.EMCY.ErrorReset(emergency.EmGenericError, 0)
+	assert.False(t, local.EMCY.IsError(emergency.EmGenericError))
+	sim.Advance(10 * time.Millisecond)
+	emcys := master.Emergencies(0x10)
+	assert.Len(t, emcys, 2)
+	assert.EqualValues(t, emergency.ErrGeneric, emcys[0].ErrorCode)
+	assert.EqualValues(t, emergency.ErrNoError, emcys[1].ErrorCode)
+}
+
+func TestEmergencyHistory(t *testing.T) {
+	sim := NewSimulation()
+	defer sim.Close()
+	master, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	devices, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	local, err := devices.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+
+	// History is larger than the 16 entries of 0x1003
+	local.EMCY.SetHistorySize(20)
+	for i := range byte(20) {
+		local.EMCY.ErrorReport(emergency.EmManufacturerStart+i, emergency.ErrDeviceSpecific, uint32(i))
+		sim.Advance(10 * time.Millisecond)
+	}
+	history := local.EMCY.History()
+	assert.Len(t, history, 20)
+	assert.Equal(t, emergency.HistoryEntry{
+		Time:      history[0].Time,
+		ErrorCode: emergency.ErrDeviceSpecific,
+		ErrorBit:  emergency.EmManufacturerStart + 19,
+		InfoCode:  19,
+	}, history[0])
+	assert.False(t, history[0].Time.IsZero())
+	assert.EqualValues(t, 0, history[19].InfoCode)
+
+	count, err := master.ReadUint8(0x10, od.EntryPreDefinedErrorField, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 16, count)
+	value, err := master.ReadUint32(0x10, od.EntryPreDefinedErrorField, 1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, uint32(emergency.EmManufacturerStart+19)<<24|emergency.ErrDeviceSpecific, value)
+
+	// Most recent entries are kept
+	local.EMCY.SetHistorySize(3)
+	history = local.EMCY.History()
+	assert.Len(t, history, 3)
+	assert.EqualValues(t, 17, history[2].InfoCode)
+	count, err = master.ReadUint8(0x10, od.EntryPreDefinedErrorField, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, count)
+	_, err = master.ReadUint32(0x10, od.EntryPreDefinedErrorField, 4)
+	assert.ErrorIs(t, err, sdo.AbortNoData)
+
+	// Clearing 0x1003 clears the whole history
+	assert.Nil(t, master.WriteRaw(0x10, od.EntryPreDefinedErrorField, 0, uint8(0), false))
+	assert.Empty(t, local.EMCY.History())
+	assert.ErrorIs(t, master.WriteRaw(0x10, od.EntryPreDefinedErrorField, 0, uint8(1), false), sdo.AbortInvalidValue)
+}
+
+func TestEmergencyFlush(t *testing.T) {
+	sim := NewSimulation()
+	defer sim.Close()
+	master, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	devices, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	local, err := devices.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+	// 1s inhibit time
+	assert.Nil(t, local.GetOD().Index(od.EntryInhibitTimeEMCY).PutUint16(0, 10_000, false))
+	infoCodes := func() []uint32 {
+		codes := []uint32{}
+		for _, emcy := range master.Emergencies(0x10) {
+			codes = append(codes, emcy.InfoCode)
+		}
+		return codes
+	}
+
+	t.Run("flush", func(t *testing.T) {
+		for i := range byte(3) {
+			local.EMCY.ErrorReport(emergency.EmManufacturerStart+i, emergency.ErrDeviceSpecific, uint32(i))
+		}
+		sim.Advance(100 * time.Millisecond)
+		assert.Empty(t, master.Emergencies(0x10))
+		assert.Equal(t, 3, local.EMCY.Flush())
+		sim.Advance(10 * time.Millisecond)
+		assert.Equal(t, []uint32{0, 1, 2}, infoCodes())
+		assert.Equal(t, 0, local.EMCY.Flush())
+		master.ClearEmergencies(0x10)
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		local.EMCY.SetOverflowPolicy(emergency.OverflowDropOldest)
+		// Fifo holds 16 pending emergencies
+		for i := range byte(20) {
+			local.EMCY.ErrorReport(emergency.EmManufacturerStart+10+i, emergency.ErrDeviceSpecific, uint32(i))
+		}
+		local.EMCY.Flush()
+		sim.Advance(10 * time.Millisecond)
+		codes := infoCodes()
+		assert.Equal(t, []uint32{4, 5, 6}, codes[:3])
+		assert.Contains(t, codes, uint32(19))
+		assert.False(t, local.EMCY.IsError(emergency.EmEmergencyBufferFull))
+	})
+}
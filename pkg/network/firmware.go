@@ -0,0 +1,184 @@
+package network
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// Program control commands (0x1F51), see CiA 302-3
+const (
+	ProgramStop  uint8 = 0
+	ProgramStart uint8 = 1
+	ProgramReset uint8 = 2
+	ProgramClear uint8 = 3
+)
+
+// Flash status identification (0x1F57) bits, see CiA 302-3
+const (
+	FlashInProgress uint32 = 1 << 0 // Programming is in progress
+	FlashErrorMask  uint32 = 0xFE   // Error code (bits 1 - 7), 0 if no error
+)
+
+const (
+	DefaultFlashTimeout = 10 * time.Second
+	flashPollPeriod     = 50 * time.Millisecond
+)
+
+var (
+	ErrFirmwareFlash    = errors.New("flash status reports an error")
+	ErrFirmwareSoftware = errors.New("program software identification mismatch")
+	ErrFirmwareBoot     = errors.New("no boot-up received after program start")
+)
+
+// FirmwareOptions configures a firmware update, see [Network.UpdateFirmware]
+type FirmwareOptions struct {
+	// Program number, i.e. sub-index of 0x1F50, 0x1F51, 0x1F56 & 0x1F57. Defaults to 1
+	Program uint8
+	// Size of the program data if known, 0 otherwise
+	Size uint32
+	// Expected program software identification (0x1F56) after download, 0 is not checked
+	SoftwareId uint32
+	// Max time to wait for programming to finish (0x1F57), defaults to [DefaultFlashTimeout]
+	FlashTimeout time.Duration
+	// Max time to wait for boot-up after program start, 0 does not wait
+	BootTimeout time.Duration
+	// Do not start the program after download
+	NoStart bool
+	// Called during program data download, see [sdo.TransferOptions]
+	Progress func(progress sdo.Progress)
+}
+
+// UpdateFirmware downloads a program to a node, following CiA 302-3 :
+//   - stop & clear the program (0x1F51)
+//   - download program data from r with block transfer if supported (0x1F50)
+//   - wait for programming to finish & check flash status (0x1F57)
+//   - check program software identification (0x1F56), if expected value is given
+//   - start the program (0x1F51) & wait for boot-up, if configured
+//
+// The node's dedicated SDO client is used, see [Network.SDOClientFor], so that
+// several nodes can be updated in parallel.
+func (network *Network) UpdateFirmware(ctx context.Context, nodeId uint8, r io.Reader, opts FirmwareOptions) error {
+	if opts.Program == 0 {
+		opts.Program = 1
+	}
+	if opts.FlashTimeout == 0 {
+		opts.FlashTimeout = DefaultFlashTimeout
+	}
+	client, err := network.SDOClientFor(nodeId)
+	if err != nil {
+		return err
+	}
+	logger := network.logger.With("id", nodeId, "program", opts.Program)
+
+	logger.Info("stopping & clearing program")
+	err = client.WriteRawCtx(ctx, nodeId, od.EntryProgramControl, opts.Program, ProgramStop, false)
+	if err != nil {
+		return fmt.Errorf("stop program : %w", err)
+	}
+	err = client.WriteRawCtx(ctx, nodeId, od.EntryProgramControl, opts.Program, ProgramClear, false)
+	if err != nil {
+		return fmt.Errorf("clear program : %w", err)
+	}
+
+	logger.Info("downloading program data", "size", opts.Size)
+	tr, err := client.NewRawWriterWith(ctx, nodeId, od.EntryProgramData, opts.Program, opts.Size,
+		sdo.TransferOptions{Block: sdo.BlockAuto, Progress: opts.Progress})
+	if err != nil {
+		return fmt.Errorf("download program : %w", err)
+	}
+	defer tr.Close()
+	_, err = tr.ReadFrom(r)
+	if err != nil {
+		return fmt.Errorf("download program : %w", err)
+	}
+
+	err = network.waitFlash(ctx, client, nodeId, opts)
+	if err != nil {
+		return err
+	}
+	if opts.SoftwareId != 0 {
+		softwareId, err := readUint32Ctx(ctx, client, nodeId, od.EntryProgramSoftwareId, opts.Program)
+		if err != nil {
+			return fmt.Errorf("read software identification : %w", err)
+		}
+		if softwareId != opts.SoftwareId {
+			return fmt.Errorf("%w : expected x%x, got x%x", ErrFirmwareSoftware, opts.SoftwareId, softwareId)
+		}
+	}
+	if opts.NoStart {
+		logger.Info("program downloaded")
+		return nil
+	}
+
+	// Subscribe before starting, boot-up could be received before start response
+	var events <-chan heartbeat.Event
+	if opts.BootTimeout > 0 {
+		bootCtx, cancel := context.WithTimeout(ctx, opts.BootTimeout)
+		defer cancel()
+		events, err = network.HeartbeatEvents(bootCtx, nodeId, 10)
+		if err != nil {
+			return err
+		}
+	}
+	logger.Info("starting program")
+	err = client.WriteRawCtx(ctx, nodeId, od.EntryProgramControl, opts.Program, ProgramStart, false)
+	if err != nil {
+		return fmt.Errorf("start program : %w", err)
+	}
+	if events == nil {
+		return nil
+	}
+	for event := range events {
+		if event.Type == heartbeat.EventBoot {
+			logger.Info("program started")
+			return nil
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return ErrFirmwareBoot
+}
+
+// Poll flash status identification until programming is finished
+func (network *Network) waitFlash(ctx context.Context, client *sdo.SDOClient, nodeId uint8, opts FirmwareOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, opts.FlashTimeout)
+	defer cancel()
+	for {
+		status, err := readUint32Ctx(ctx, client, nodeId, od.EntryFlashStatusId, opts.Program)
+		if err != nil {
+			return fmt.Errorf("read flash status : %w", err)
+		}
+		if status&FlashErrorMask != 0 {
+			return fmt.Errorf("%w : x%x", ErrFirmwareFlash, (status&FlashErrorMask)>>1)
+		}
+		if status&FlashInProgress == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait flash : %w", ctx.Err())
+		case <-time.After(flashPollPeriod):
+		}
+	}
+}
+
+func readUint32Ctx(ctx context.Context, client *sdo.SDOClient, nodeId uint8, index uint16, subindex uint8) (uint32, error) {
+	buf := make([]byte, 4)
+	n, err := client.ReadRawCtx(ctx, nodeId, index, subindex, buf)
+	if err != nil {
+		return 0, err
+	}
+	if n != 4 {
+		return 0, od.ErrTypeMismatch
+	}
+	return binary.LittleEndian.Uint32(buf), nil
+}
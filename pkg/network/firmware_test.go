@@ -0,0 +1,142 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+	"github.com/stretchr/testify/assert"
+)
+
+const nodeIdBootloader = 0x30
+
+// Minimal CiA 302-3 bootloader, storing program data & received commands
+type fakeBootloader struct {
+	mu       sync.Mutex
+	data     bytes.Buffer
+	commands []uint8
+}
+
+func (bootloader *fakeBootloader) program() []byte {
+	bootloader.mu.Lock()
+	defer bootloader.mu.Unlock()
+	return bytes.Clone(bootloader.data.Bytes())
+}
+
+func (bootloader *fakeBootloader) history() []uint8 {
+	bootloader.mu.Lock()
+	defer bootloader.mu.Unlock()
+	return append([]uint8{}, bootloader.commands...)
+}
+
+func addProgramArray(odict *od.ObjectDictionary, index uint16, name string, datatype uint8, value string) *od.Entry {
+	array := od.NewArray(2)
+	array.AddSubObject(0, "Number of entries", od.UNSIGNED8, od.AttributeSdoR, "0x1")
+	array.AddSubObject(1, name, datatype, od.AttributeSdoRw, value)
+	return odict.AddVariableList(index, name, array)
+}
+
+func newFakeBootloader(t *testing.T, network *Network) (*fakeBootloader, *od.ObjectDictionary) {
+	bootloader := &fakeBootloader{}
+	odict := od.Default()
+	programData := addProgramArray(odict, od.EntryProgramData, "Program data", od.DOMAIN, "")
+	programData.AddExtension(bootloader, od.ReadEntryDefault,
+		func(stream *od.Stream, data []byte, countWritten *uint16) error {
+			bootloader.mu.Lock()
+			defer bootloader.mu.Unlock()
+			bootloader.data.Write(data)
+			*countWritten = uint16(len(data))
+			stream.DataOffset += uint32(len(data))
+			if stream.DataLength == stream.DataOffset {
+				return nil
+			}
+			return od.ErrPartial
+		})
+	programControl := addProgramArray(odict, od.EntryProgramControl, "Program control", od.UNSIGNED8, "0x0")
+	programControl.AddExtension(bootloader, od.ReadEntryDefault,
+		func(stream *od.Stream, data []byte, countWritten *uint16) error {
+			bootloader.mu.Lock()
+			bootloader.commands = append(bootloader.commands, data[0])
+			switch data[0] {
+			case ProgramClear:
+				bootloader.data.Reset()
+			case ProgramStart:
+				// Application boot-up
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					_ = network.Send(canopen.NewFrame(0x700+nodeIdBootloader, 0, 1))
+				}()
+			}
+			bootloader.mu.Unlock()
+			return od.WriteEntryDefault(stream, data, countWritten)
+		})
+	addProgramArray(odict, od.EntryProgramSoftwareId, "Program software identification", od.UNSIGNED32, "0x12345678")
+	addProgramArray(odict, od.EntryFlashStatusId, "Flash status identification", od.UNSIGNED32, "0x0")
+	_, err := network.CreateLocalNode(nodeIdBootloader, odict)
+	assert.Nil(t, err)
+	return bootloader, odict
+}
+
+func TestUpdateFirmware(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	bootloader, odict := newFakeBootloader(t, network)
+	image := make([]byte, 2000)
+	for i := range image {
+		image[i] = byte(i * 7)
+	}
+
+	t.Run("download & start", func(t *testing.T) {
+		var progress []sdo.Progress
+		err := network.UpdateFirmware(context.Background(), nodeIdBootloader, bytes.NewReader(image), FirmwareOptions{
+			Size:        uint32(len(image)),
+			SoftwareId:  0x12345678,
+			BootTimeout: time.Second,
+			Progress:    func(p sdo.Progress) { progress = append(progress, p) },
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, image, bootloader.program())
+		assert.Equal(t, []uint8{ProgramStop, ProgramClear, ProgramStart}, bootloader.history())
+		assert.NotEmpty(t, progress)
+		assert.EqualValues(t, len(image), progress[len(progress)-1].Transferred)
+		assert.EqualValues(t, len(image), progress[len(progress)-1].Size)
+	})
+
+	t.Run("software identification mismatch", func(t *testing.T) {
+		err := network.UpdateFirmware(context.Background(), nodeIdBootloader, bytes.NewReader(image), FirmwareOptions{
+			SoftwareId: 0x1,
+		})
+		assert.ErrorIs(t, err, ErrFirmwareSoftware)
+	})
+
+	t.Run("flash error", func(t *testing.T) {
+		assert.Nil(t, odict.Index(od.EntryFlashStatusId).PutUint32(1, 3<<1, true))
+		defer odict.Index(od.EntryFlashStatusId).PutUint32(1, 0, true)
+		err := network.UpdateFirmware(context.Background(), nodeIdBootloader, bytes.NewReader(image), FirmwareOptions{})
+		assert.ErrorIs(t, err, ErrFirmwareFlash)
+	})
+
+	t.Run("flash timeout", func(t *testing.T) {
+		assert.Nil(t, odict.Index(od.EntryFlashStatusId).PutUint32(1, FlashInProgress, true))
+		defer odict.Index(od.EntryFlashStatusId).PutUint32(1, 0, true)
+		err := network.UpdateFirmware(context.Background(), nodeIdBootloader, bytes.NewReader(image), FirmwareOptions{
+			FlashTimeout: 200 * time.Millisecond,
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("no start", func(t *testing.T) {
+		before := len(bootloader.history())
+		err := network.UpdateFirmware(context.Background(), nodeIdBootloader, bytes.NewReader(image[:10]), FirmwareOptions{
+			NoStart: true,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []uint8{ProgramStop, ProgramClear}, bootloader.history()[before:])
+		assert.Equal(t, image[:10], bootloader.program())
+	})
+}
@@ -0,0 +1,162 @@
+package network
+
+import (
+	"context"
+	"hash/crc32"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// Possible drifts detected during a fleet check
+const (
+	DriftNone        uint8 = 0x00
+	DriftUnreachable uint8 = 0x01 // Node did not respond to identity read
+	DriftIdentity    uint8 = 0x02 // Identity object (0x1018) does not match baseline
+	DriftEDS         uint8 = 0x04 // EDS stored in 0x1021 does not match baseline checksum
+)
+
+// NodeBaseline is the expected state of a node in the fleet.
+// Identity fields set to 0 are not checked, e.g. SerialNumber
+// is typically different for every device.
+type NodeBaseline struct {
+	config.Identity
+	// CRC32 (IEEE) of the EDS file stored in 0x1021, 0 is not checked.
+	// See [EDSChecksum].
+	EDSChecksum uint32
+}
+
+// FleetReport is the result of a fleet check for a single node
+type FleetReport struct {
+	NodeId      uint8
+	Time        time.Time
+	Drift       uint8 // Bitfield of detected drifts
+	Expected    NodeBaseline
+	Identity    *config.Identity
+	EDSChecksum uint32
+	Err         error
+}
+
+// Returns true if node does not match its baseline
+func (report *FleetReport) HasDrift() bool {
+	return report.Drift != DriftNone
+}
+
+type FleetDriftCallback func(report FleetReport)
+
+// Compute the checksum of a raw EDS file, as used in [NodeBaseline]
+func EDSChecksum(rawEds []byte) uint32 {
+	return crc32.ChecksumIEEE(rawEds)
+}
+
+// Check a single node against baseline with the given client
+func (network *Network) checkNode(client *sdo.SDOClient, nodeId uint8, baseline NodeBaseline) FleetReport {
+	report := FleetReport{NodeId: nodeId, Time: time.Now(), Expected: baseline}
+	identity, err := config.NewNodeConfigurator(nodeId, network.logger, client).ReadIdentity()
+	if err != nil {
+		report.Drift |= DriftUnreachable
+		report.Err = err
+		return report
+	}
+	report.Identity = identity
+	expected := baseline.Identity
+	if (expected.VendorId != 0 && expected.VendorId != identity.VendorId) ||
+		(expected.ProductCode != 0 && expected.ProductCode != identity.ProductCode) ||
+		(expected.RevisionNumber != 0 && expected.RevisionNumber != identity.RevisionNumber) ||
+		(expected.SerialNumber != 0 && expected.SerialNumber != identity.SerialNumber) {
+		report.Drift |= DriftIdentity
+	}
+	if baseline.EDSChecksum == 0 {
+		return report
+	}
+	rawEds, err := client.ReadAll(nodeId, od.EntryStoreEDS, 0)
+	if err != nil {
+		report.Drift |= DriftEDS
+		report.Err = err
+		return report
+	}
+	report.EDSChecksum = EDSChecksum(rawEds)
+	if report.EDSChecksum != baseline.EDSChecksum {
+		report.Drift |= DriftEDS
+	}
+	return report
+}
+
+func (network *Network) checkFleet(client *sdo.SDOClient, baselines map[uint8]NodeBaseline) map[uint8]FleetReport {
+	reports := make(map[uint8]FleetReport)
+	for nodeId, baseline := range baselines {
+		report := network.checkNode(client, nodeId, baseline)
+		if report.HasDrift() {
+			network.logger.Warn("fleet drift detected",
+				"id", nodeId,
+				"drift", report.Drift,
+				"identity", report.Identity,
+				"edsChecksum", report.EDSChecksum,
+				"error", report.Err,
+			)
+		}
+		reports[nodeId] = report
+	}
+	return reports
+}
+
+// ReadBaseline reads the current identity & EDS checksum of a node.
+// This can be used to create the baseline from a known good device.
+func (network *Network) ReadBaseline(nodeId uint8) (NodeBaseline, error) {
+	baseline := NodeBaseline{}
+	identity, err := network.Configurator(nodeId).ReadIdentity()
+	if err != nil {
+		return baseline, err
+	}
+	baseline.Identity = *identity
+	rawEds, err := network.ReadAll(nodeId, od.EntryStoreEDS, 0)
+	if err != nil {
+		return baseline, err
+	}
+	baseline.EDSChecksum = EDSChecksum(rawEds)
+	return baseline, nil
+}
+
+// CheckFleet verifies every node of the baselines map (indexed by node id)
+// against the identity (0x1018) & EDS (0x1021) reported by the node.
+// It returns a report for every node.
+func (network *Network) CheckFleet(baselines map[uint8]NodeBaseline) map[uint8]FleetReport {
+	return network.checkFleet(network.SDOClient, baselines)
+}
+
+// StartFleetCheck periodically checks the fleet against the given baselines
+// until ctx is cancelled. callback is called for every node that does not
+// match its baseline. The network's SDO client is used, so the checks are
+// queued with the other transfers made with it, see [sdo.Transfer].
+// period should be strictly positive.
+func (network *Network) StartFleetCheck(
+	ctx context.Context,
+	period time.Duration,
+	baselines map[uint8]NodeBaseline,
+	callback FleetDriftCallback,
+) error {
+	if period <= 0 {
+		return canopen.ErrIllegalArgument
+	}
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			for _, report := range network.CheckFleet(baselines) {
+				if report.HasDrift() && callback != nil {
+					callback(report)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				network.logger.Info("exiting fleet check")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,77 @@
+package network
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFleetCheck(t *testing.T) {
+	network := CreateNetworkTest()
+	network2 := CreateNetworkEmptyTest()
+	defer network2.Disconnect()
+	defer network.Disconnect()
+	baseline, err := network2.ReadBaseline(NodeIdTest)
+	assert.Nil(t, err)
+	assert.NotZero(t, baseline.EDSChecksum)
+
+	t.Run("no drift", func(t *testing.T) {
+		reports := network2.CheckFleet(map[uint8]NodeBaseline{NodeIdTest: baseline})
+		report := reports[NodeIdTest]
+		assert.False(t, report.HasDrift())
+	})
+	t.Run("drift", func(t *testing.T) {
+		wrong := baseline
+		wrong.ProductCode++
+		wrong.EDSChecksum++
+		reports := network2.CheckFleet(map[uint8]NodeBaseline{NodeIdTest: wrong, NodeIdTest + 1: baseline})
+		report := reports[NodeIdTest]
+		assert.Equal(t, DriftIdentity|DriftEDS, report.Drift)
+		report = reports[NodeIdTest+1]
+		assert.Equal(t, DriftUnreachable, report.Drift)
+	})
+	t.Run("periodic", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		drifts := make(chan FleetReport, 10)
+		wrong := baseline
+		wrong.VendorId++
+		err := network2.StartFleetCheck(ctx, 100*time.Millisecond, map[uint8]NodeBaseline{NodeIdTest: wrong},
+			func(report FleetReport) { drifts <- report })
+		assert.Nil(t, err)
+		for range 2 {
+			select {
+			case report := <-drifts:
+				assert.Equal(t, DriftIdentity, report.Drift)
+			case <-time.After(3 * time.Second):
+				t.Fatal("expected drift report")
+			}
+		}
+	})
+	t.Run("invalid period", func(t *testing.T) {
+		err := network2.StartFleetCheck(context.Background(), 0, map[uint8]NodeBaseline{NodeIdTest: baseline}, nil)
+		assert.Equal(t, canopen.ErrIllegalArgument, err)
+	})
+	t.Run("concurrent transfers", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		expected, err := network2.ReadAll(NodeIdTest, od.EntryStoreEDS, 0)
+		assert.Nil(t, err)
+		var drifts atomic.Int32
+		err = network2.StartFleetCheck(ctx, time.Millisecond, map[uint8]NodeBaseline{NodeIdTest: baseline},
+			func(report FleetReport) { drifts.Add(1) })
+		assert.Nil(t, err)
+		// Checks & user transfers share the client, so they do not corrupt each other
+		for range 5 {
+			rawEds, err := network2.ReadAll(NodeIdTest, od.EntryStoreEDS, 0)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, rawEds)
+		}
+		assert.Zero(t, drifts.Load())
+	})
+}
@@ -0,0 +1,45 @@
+package network
+
+import (
+	"time"
+)
+
+// Health of a node running on the network
+type NodeHealth struct {
+	Id             uint8
+	Running        bool      // Node processing is started
+	LastMain       time.Time // Last time main processing was run
+	LastBackground time.Time // Last time background processing (SYNC, PDO) was run
+	SDOBacklog     int       // Number of frames waiting to be processed by SDO server(s)
+}
+
+// Returns true if node is running and processing was run within maxAge
+func (h *NodeHealth) Healthy(maxAge time.Duration) bool {
+	now := time.Now()
+	return h.Running && now.Sub(h.LastMain) <= maxAge && now.Sub(h.LastBackground) <= maxAge
+}
+
+// Returns true if connected to CAN bus
+func (network *Network) Connected() bool {
+	return network.connected.Load()
+}
+
+// Returns health information of every node of the network
+func (network *Network) NodesHealth() []NodeHealth {
+	health := make([]NodeHealth, 0, len(network.controllers))
+	for id, controller := range network.controllers {
+		lastMain, lastBackground := controller.LastProcessed()
+		backlog := 0
+		for _, server := range controller.GetNode().Servers() {
+			backlog += server.Backlog()
+		}
+		health = append(health, NodeHealth{
+			Id:             id,
+			Running:        controller.Running(),
+			LastMain:       lastMain,
+			LastBackground: lastBackground,
+			SDOBacklog:     backlog,
+		})
+	}
+	return health
+}
@@ -0,0 +1,81 @@
+package network
+
+import (
+	"context"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+)
+
+// OnHeartbeatEvent registers a listener for the heartbeat events of nodeId
+// e.g. boot-up, heartbeat started, lost or recovered & NMT state changes.
+// Use [heartbeat.EventAllNodes] to receive events of every node.
+// Heartbeat loss is only reported for nodes with a timeout, see [Network.SetHeartbeatTimeout].
+// Listeners are called from the CAN reception and should not block.
+// This is available after [Network.Connect].
+func (network *Network) OnHeartbeatEvent(nodeId uint8, listener heartbeat.EventListener) error {
+	if network.heartbeats == nil {
+		return ErrNotConnected
+	}
+	if nodeId > 127 {
+		return ErrIdRange
+	}
+	network.heartbeats.OnNodeEvent(nodeId, listener)
+	return nil
+}
+
+// HeartbeatEvents is like [Network.OnHeartbeatEvent] but returns a channel of the given size,
+// closed when ctx is cancelled. Events are dropped if the channel is full.
+func (network *Network) HeartbeatEvents(ctx context.Context, nodeId uint8, size int) (<-chan heartbeat.Event, error) {
+	if network.heartbeats == nil {
+		return nil, ErrNotConnected
+	}
+	if nodeId > 127 {
+		return nil, ErrIdRange
+	}
+	return network.heartbeats.NodeEvents(ctx, nodeId, size), nil
+}
+
+// SetHeartbeatTimeout sets the time after which the heartbeat of nodeId is considered lost,
+// typically a bit more than the heartbeat period of the node. 0 disables loss detection.
+func (network *Network) SetHeartbeatTimeout(nodeId uint8, timeout time.Duration) error {
+	if network.heartbeats == nil {
+		return ErrNotConnected
+	}
+	if nodeId < nodeIdMin || nodeId > 127 {
+		return ErrIdRange
+	}
+	network.heartbeats.SetTimeout(nodeId, timeout)
+	return nil
+}
+
+// StartNodeGuarding starts node guarding of a legacy device that does not produce
+// heartbeats. Guard time (0x100C) & life time factor (0x100D) are read from the node.
+// Guarding events are reported like heartbeat events, see [Network.OnHeartbeatEvent].
+func (network *Network) StartNodeGuarding(nodeId uint8) error {
+	guardTimeMs, lifeTimeFactor, err := network.Configurator(nodeId).ReadNodeGuarding()
+	if err != nil {
+		return err
+	}
+	return network.StartNodeGuardingWith(nodeId, time.Duration(guardTimeMs)*time.Millisecond, lifeTimeFactor)
+}
+
+// StartNodeGuardingWith is like [Network.StartNodeGuarding] with the given parameters.
+// The node is considered lost if it does not respond within guardTime x lifeTimeFactor.
+func (network *Network) StartNodeGuardingWith(nodeId uint8, guardTime time.Duration, lifeTimeFactor uint8) error {
+	if network.heartbeats == nil {
+		return ErrNotConnected
+	}
+	if nodeId < nodeIdMin || nodeId > 127 {
+		return ErrIdRange
+	}
+	return network.heartbeats.Guard(nodeId, guardTime, lifeTimeFactor)
+}
+
+// StopNodeGuarding stops node guarding of nodeId
+func (network *Network) StopNodeGuarding(nodeId uint8) {
+	if network.heartbeats == nil {
+		return
+	}
+	network.heartbeats.Unguard(nodeId)
+}
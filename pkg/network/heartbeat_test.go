@@ -1,10 +1,13 @@
 package network
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
 
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/config"
 	"github.com/samsamfire/gocanopen/pkg/heartbeat"
 	"github.com/samsamfire/gocanopen/pkg/nmt"
 	"github.com/samsamfire/gocanopen/pkg/od"
@@ -141,3 +144,226 @@ func TestHeartbeatEventCallback(t *testing.T) {
 		assert.Equal(t, 1, eventHandler.NbEventChanged())
 	})
 }
+
+// Wait for an event of the given type, skipping other events
+func waitHeartbeatEvent(t *testing.T, events <-chan heartbeat.Event, eventType uint8) heartbeat.Event {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for heartbeat event %v", eventType)
+			return heartbeat.Event{}
+		}
+	}
+}
+
+func TestHeartbeatListeners(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	networkEvents, err := network.HeartbeatEvents(ctx, 0x24, 50)
+	assert.Nil(t, err)
+	_, err = network.HeartbeatEvents(ctx, 200, 50)
+	assert.Equal(t, ErrIdRange, err)
+	allEvents := make(chan heartbeat.Event, 50)
+	err = network.OnHeartbeatEvent(heartbeat.EventAllNodes, func(event heartbeat.Event) {
+		select {
+		case allEvents <- event:
+		default:
+		}
+	})
+	assert.Nil(t, err)
+
+	producer, err := network.CreateLocalNode(0x24, od.Default())
+	assert.Nil(t, err)
+	configProducer := producer.Configurator()
+	consumer, err := network.CreateLocalNode(0x25, od.Default())
+	assert.Nil(t, err)
+	err = consumer.Configurator().WriteMonitoredNode(1, 0x24, 100)
+	assert.Nil(t, err)
+	consumerEvents := consumer.HeartbeatEvents(ctx, 0x24, 50)
+
+	event := waitHeartbeatEvent(t, networkEvents, heartbeat.EventBoot)
+	assert.EqualValues(t, 0x24, event.NodeId)
+	assert.Equal(t, nmt.StateInitializing, event.NmtState)
+	waitHeartbeatEvent(t, allEvents, heartbeat.EventBoot)
+
+	t.Run("started", func(t *testing.T) {
+		assert.Nil(t, network.SetHeartbeatTimeout(0x24, 100*time.Millisecond))
+		assert.Nil(t, configProducer.WriteHeartbeatPeriod(20))
+		event := waitHeartbeatEvent(t, networkEvents, heartbeat.EventStarted)
+		assert.EqualValues(t, 0x24, event.NodeId)
+		event = waitHeartbeatEvent(t, consumerEvents, heartbeat.EventStarted)
+		assert.EqualValues(t, 0x24, event.NodeId)
+	})
+
+	t.Run("lost and recovered", func(t *testing.T) {
+		assert.Nil(t, configProducer.WriteHeartbeatPeriod(0))
+		event := waitHeartbeatEvent(t, networkEvents, heartbeat.EventTimeout)
+		assert.Equal(t, nmt.StateUnknown, event.NmtState)
+		waitHeartbeatEvent(t, consumerEvents, heartbeat.EventTimeout)
+		assert.Nil(t, configProducer.WriteHeartbeatPeriod(20))
+		waitHeartbeatEvent(t, networkEvents, heartbeat.EventRecovered)
+		waitHeartbeatEvent(t, consumerEvents, heartbeat.EventRecovered)
+	})
+
+	t.Run("nmt state changed", func(t *testing.T) {
+		assert.Nil(t, network.Command(0x24, nmt.CommandEnterStopped))
+		// Skip changes from recovery
+		event := waitHeartbeatEvent(t, networkEvents, heartbeat.EventChanged)
+		for event.NmtState != nmt.StateStopped {
+			event = waitHeartbeatEvent(t, networkEvents, heartbeat.EventChanged)
+		}
+		assert.Equal(t, nmt.StateOperational, event.Previous)
+		event = waitHeartbeatEvent(t, consumerEvents, heartbeat.EventChanged)
+		for event.NmtState != nmt.StateStopped {
+			event = waitHeartbeatEvent(t, consumerEvents, heartbeat.EventChanged)
+		}
+		assert.Equal(t, nmt.StateOperational, event.Previous)
+	})
+
+	t.Run("channel closed on cancel", func(t *testing.T) {
+		cancel()
+		assert.Eventually(t, func() bool {
+			for {
+				select {
+				case _, ok := <-networkEvents:
+					if !ok {
+						return true
+					}
+				default:
+					return false
+				}
+			}
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestHeartbeatLocalControl(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	producer, err := network.CreateLocalNode(0x28, od.Default())
+	assert.Nil(t, err)
+	consumer, err := network.CreateLocalNode(0x29, od.Default())
+	assert.Nil(t, err)
+	events := consumer.HeartbeatEvents(ctx, 0x28, 50)
+
+	t.Run("producer period", func(t *testing.T) {
+		assert.Nil(t, producer.SetHeartbeatPeriod(20))
+		period, err := producer.HeartbeatPeriod()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 20, period)
+		// Visible remotely
+		period, err = producer.Configurator().ReadHeartbeatPeriod()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 20, period)
+	})
+
+	t.Run("monitor nodes", func(t *testing.T) {
+		assert.Nil(t, consumer.AddMonitoredNode(0x28, 100))
+		waitHeartbeatEvent(t, events, heartbeat.EventStarted)
+		// Same node updates the existing entry, monitoring restarts
+		assert.Nil(t, consumer.AddMonitoredNode(0x28, 150))
+		waitHeartbeatEvent(t, events, heartbeat.EventStarted)
+		monitored, err := consumer.MonitoredNodes()
+		assert.Nil(t, err)
+		assert.Equal(t, map[uint8]uint16{0x28: 150}, monitored)
+		assert.Nil(t, producer.SetHeartbeatPeriod(0))
+		waitHeartbeatEvent(t, events, heartbeat.EventTimeout)
+		assert.Nil(t, consumer.RemoveMonitoredNode(0x28))
+		monitored, err = consumer.MonitoredNodes()
+		assert.Nil(t, err)
+		assert.Empty(t, monitored)
+		assert.Nil(t, consumer.RemoveMonitoredNode(0x28))
+	})
+
+	t.Run("no free entry", func(t *testing.T) {
+		for i := range uint8(8) {
+			assert.Nil(t, consumer.AddMonitoredNode(0x40+i, 1000))
+		}
+		assert.ErrorIs(t, consumer.AddMonitoredNode(0x50, 1000), config.ErrNoFreeMonitorEntry)
+	})
+}
+
+func TestNodeGuarding(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	odict := od.Default()
+	_, err := odict.AddVariableType(od.EntryGuardTime, "Guard time", od.UNSIGNED16, od.AttributeSdoRw, "50")
+	assert.Nil(t, err)
+	_, err = odict.AddVariableType(od.EntryLifeTimeFactor, "Life time factor", od.UNSIGNED8, od.AttributeSdoRw, "3")
+	assert.Nil(t, err)
+	slave, err := network.CreateLocalNode(0x26, odict)
+	assert.Nil(t, err)
+	// Node guarding is only used without heartbeat
+	assert.Nil(t, slave.Configurator().WriteHeartbeatPeriod(0))
+	events, err := network.HeartbeatEvents(ctx, 0x26, 50)
+	assert.Nil(t, err)
+
+	t.Run("guarding", func(t *testing.T) {
+		assert.Nil(t, network.StartNodeGuarding(0x26))
+		event := waitHeartbeatEvent(t, events, heartbeat.EventStarted)
+		assert.Equal(t, nmt.StateOperational, event.NmtState)
+		time.Sleep(200 * time.Millisecond)
+		assert.Greater(t, slave.NMT.Stats().GuardingRequests, uint32(2))
+		for range len(events) {
+			event := <-events
+			assert.NotEqual(t, heartbeat.EventToggle, event.Type)
+			assert.NotEqual(t, heartbeat.EventTimeout, event.Type)
+		}
+	})
+
+	t.Run("life guarding event on slave", func(t *testing.T) {
+		network.StopNodeGuarding(0x26)
+		assert.Eventually(t, func() bool {
+			for _, emcy := range network.Emergencies(0x26) {
+				if emcy.ErrorCode == 0x8130 {
+					return true
+				}
+			}
+			return false
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("node lost", func(t *testing.T) {
+		assert.Nil(t, network.StartNodeGuardingWith(0x26, 20*time.Millisecond, 3))
+		waitHeartbeatEvent(t, events, heartbeat.EventStarted)
+		assert.Nil(t, network.RemoveNode(0x26))
+		event := waitHeartbeatEvent(t, events, heartbeat.EventTimeout)
+		assert.Equal(t, nmt.StateUnknown, event.NmtState)
+		network.StopNodeGuarding(0x26)
+	})
+
+	t.Run("toggle error", func(t *testing.T) {
+		events, err := network.HeartbeatEvents(ctx, 0x27, 50)
+		assert.Nil(t, err)
+		assert.Nil(t, network.StartNodeGuardingWith(0x27, 50*time.Millisecond, 10))
+		defer network.StopNodeGuarding(0x27)
+		send := func(data byte) {
+			frame := canopen.NewFrame(0x727, 0, 1)
+			frame.Data[0] = data
+			assert.Nil(t, network.Send(frame))
+		}
+		send(nmt.StatePreOperational)
+		event := waitHeartbeatEvent(t, events, heartbeat.EventStarted)
+		assert.Equal(t, nmt.StatePreOperational, event.NmtState)
+		send(nmt.StatePreOperational)
+		waitHeartbeatEvent(t, events, heartbeat.EventToggle)
+		send(nmt.StateOperational | 0x80)
+		event = waitHeartbeatEvent(t, events, heartbeat.EventChanged)
+		assert.Equal(t, nmt.StateOperational, event.NmtState)
+	})
+}
@@ -9,11 +9,15 @@ import (
 	"log/slog"
 	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	canopen "github.com/samsamfire/gocanopen"
 	can "github.com/samsamfire/gocanopen/pkg/can"
 	_ "github.com/samsamfire/gocanopen/pkg/can/all"
 	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+	"github.com/samsamfire/gocanopen/pkg/lss"
 	"github.com/samsamfire/gocanopen/pkg/nmt"
 	n "github.com/samsamfire/gocanopen/pkg/node"
 	"github.com/samsamfire/gocanopen/pkg/od"
@@ -26,6 +30,7 @@ var (
 	ErrNotFound        = errors.New("node id not found on network, add or create it first")
 	ErrInvalidNodeType = errors.New("invalid node type")
 	ErrNoNodesFound    = errors.New("no nodes found on network when performing SDO scan")
+	ErrNotConnected    = errors.New("network is not connected, call Connect first")
 )
 
 const (
@@ -42,9 +47,45 @@ type Network struct {
 	*sdo.SDOClient
 	controllers map[uint8]*n.NodeProcessor
 	// Network has an its own SDOClient
-	odMap    map[uint8]*ObjectDictionaryInformation
-	odParser od.Parser
-	logger   *slog.Logger
+	// and a pool of SDO clients, one per remote node
+	sdoPool   sdoClientPool
+	odMap     map[uint8]*ObjectDictionaryInformation
+	odParser  od.Parser
+	logger    *slog.Logger
+	clock     n.Clock
+	connected atomic.Bool
+	lssMaster *lss.LSSMaster
+	// Consumer of all the EMCYs on the network
+	emergencies *emergencyConsumer
+	// Monitor of all the heartbeats on the network
+	heartbeats *heartbeat.Monitor
+	// Nodes started again after a boot-up, see [Network.SetAutoStart]
+	autoStartMu       sync.Mutex
+	autoStart         map[uint8]bool
+	autoStartListener bool
+	// Writes waiting for a node to enter a given NMT state
+	delayedMu sync.Mutex
+	delayed   *delayedWriter
+	// Bus monitoring & reconnection
+	busMu        sync.Mutex
+	busCallbacks []BusEventCallback
+	busPeriod    time.Duration
+	busCancel    context.CancelFunc
+	busDone      chan struct{}
+	connectArgs  []any
+	ownBus       bool
+	// Background discovery of nodes
+	discoveryMu        sync.Mutex
+	discovery          *discovery
+	discoveryCallbacks []DiscoveryCallback
+	// PDO database, see [Network.PDODatabase]
+	pdoMu sync.Mutex
+	pdos  *PDODatabase
+	// Simulation driving the nodes, see [Simulation]
+	simulation *Simulation
+	// Nodes expected by [Network.Commission]
+	expectedMu sync.Mutex
+	expected   map[uint8]NodeSpec
 }
 
 type ObjectDictionaryInformation struct {
@@ -54,16 +95,16 @@ type ObjectDictionaryInformation struct {
 }
 
 // Create a new CAN bus with given interface
+// Any driver registered with [can.RegisterDriver] can be used.
 func NewBus(canInterfaceName string, channel string, bitrate int) (canopen.Bus, error) {
-	createInterface, ok := can.AvailableInterfaces[canInterfaceName]
-	if !ok {
+	if !slices.Contains(can.Drivers(), canInterfaceName) {
 		if slices.Contains(can.ImplementedInterfaces, canInterfaceName) {
 			return nil, fmt.Errorf("not enabled : %v, check build flags for project", canInterfaceName)
 		} else {
-			return nil, fmt.Errorf("not supported : %v", canInterfaceName)
+			return nil, fmt.Errorf("not supported : %v, available : %v", canInterfaceName, can.Drivers())
 		}
 	}
-	return createInterface(channel)
+	return can.NewBus(canInterfaceName, channel, bitrate)
 }
 
 // Create a new Network using the given CAN bus
@@ -74,6 +115,7 @@ func NewNetwork(bus canopen.Bus) Network {
 		odMap:       map[uint8]*ObjectDictionaryInformation{},
 		odParser:    od.Parse,
 		logger:      slog.Default(),
+		busPeriod:   DefaultBusMonitorPeriod,
 	}
 }
 
@@ -104,7 +146,9 @@ func (network *Network) Connect(args ...any) error {
 		if err != nil {
 			return err
 		}
+		network.SetBitrate(bitrate)
 		network.SetBus(bus)
+		network.ownBus = true
 	} else {
 		bus = network.Bus()
 	}
@@ -119,12 +163,69 @@ func (network *Network) Connect(args ...any) error {
 	}
 	// Add SDO client to network by default
 	client, err := sdo.NewSDOClient(network.BusManager, network.logger, nil, 0, sdo.DefaultClientTimeout, nil)
+	if err != nil {
+		return err
+	}
+	network.sdoPool.mu.Lock()
+	client.SetTransferHook(network.sdoPool.hook)
+	client.SetStepper(network.sdoPool.stepper)
 	network.SDOClient = client
-	return err
+	network.sdoPool.mu.Unlock()
+	// Add LSS master to network by default
+	if network.lssMaster == nil {
+		network.lssMaster, err = lss.NewLSSMaster(network.BusManager, network.logger, lss.DefaultTimeout)
+	}
+	// Add EMCY consumer to network by default
+	if err == nil && network.emergencies == nil {
+		network.emergencies, err = newEmergencyConsumer(network.BusManager)
+	}
+	// Add heartbeat monitor to network by default
+	if err == nil && network.heartbeats == nil {
+		network.heartbeats, err = heartbeat.NewMonitor(network.BusManager)
+	}
+	if err != nil {
+		return err
+	}
+	network.connectArgs = args
+	network.connected.Store(true)
+	network.startBusMonitor()
+	network.emitBusEvent(BusStateConnected, nil)
+	return nil
+}
+
+// Start monitoring of the bus, if enabled and not already running
+func (network *Network) startBusMonitor() {
+	network.busMu.Lock()
+	defer network.busMu.Unlock()
+	if network.busPeriod == 0 || network.busCancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	network.busCancel = cancel
+	network.busDone = make(chan struct{})
+	go func(period time.Duration, done chan struct{}) {
+		defer close(done)
+		network.monitorBus(ctx, period)
+	}(network.busPeriod, network.busDone)
+}
+
+// Stop monitoring of the bus and wait for it to finish
+func (network *Network) stopBusMonitor() {
+	network.busMu.Lock()
+	cancel, done := network.busCancel, network.busDone
+	network.busCancel = nil
+	network.busMu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
 }
 
 // Disconnects from the CAN bus and stops processing
-// of CANopen stack
+// of CANopen stack. This returns once processing of the nodes has stopped,
+// frames still queued by the TX scheduler may be lost and listeners are kept
+// so that the network can be connected again. See [Network.Close] for a graceful stop.
 func (network *Network) Disconnect() {
 	// Stop processing for everyone then wait for everyone
 	// This is done in two steps because there can be a delay
@@ -135,7 +236,24 @@ func (network *Network) Disconnect() {
 	for _, controller := range network.controllers {
 		controller.Wait()
 	}
+	network.stopBackground()
+	network.disconnectBus()
+}
+
+// Stop background tasks of the network
+func (network *Network) stopBackground() {
+	network.stopBusMonitor()
+	network.StopDiscovery()
+	network.stopDelayedWrites()
+	if network.heartbeats != nil {
+		network.heartbeats.Stop()
+	}
+}
+
+func (network *Network) disconnectBus() {
 	_ = network.BusManager.Bus().Disconnect()
+	network.connected.Store(false)
+	network.emitBusEvent(BusStateDisconnected, nil)
 }
 
 // Get OD for a specific node id
@@ -157,17 +275,33 @@ func (network *Network) GetOD(nodeId uint8) (*od.ObjectDictionary, error) {
 // in case a custom format is used (format type != 0).
 // By default, regular uncompressed ASCII will be used (format type of 0).
 func (network *Network) ReadEDS(nodeId uint8, edsFormatHandler od.EDSFormatHandler) (*od.ObjectDictionary, error) {
-	// Read EDS and format in memory
-	rawEds, err := network.ReadAll(nodeId, 0x1021, 0)
+	rawEds, edsFormat, err := network.readRawEDS(nodeId)
 	if err != nil {
 		return nil, err
 	}
-	edsFormat, err := network.ReadUint8(nodeId, 0x1022, 0)
+	return parseRawEDS(nodeId, rawEds, edsFormat, edsFormatHandler)
+}
+
+// Read EDS (0x1021) and its format (0x1022) in memory
+func (network *Network) readRawEDS(nodeId uint8) ([]byte, uint8, error) {
+	rawEds, err := network.ReadAll(nodeId, od.EntryStoreEDS, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rawEds, network.readEDSFormat(nodeId), nil
+}
+
+func (network *Network) readEDSFormat(nodeId uint8) uint8 {
+	edsFormat, err := network.ReadUint8(nodeId, od.EntryStorageFormat, 0)
 	if err != nil {
 		// Don't fail if format is not specified, consider it to be ASCII
 		network.logger.Warn("read EDS format failed, defaulting to ASCII", "id", nodeId, "error", err)
-		edsFormat = 0
+		return od.FormatEDSAscii
 	}
+	return edsFormat
+}
+
+func parseRawEDS(nodeId uint8, rawEds []byte, edsFormat uint8, edsFormatHandler od.EDSFormatHandler) (*od.ObjectDictionary, error) {
 	// Use ascii format handler as default if non given
 	if edsFormatHandler == nil {
 		edsFormatHandler = od.DefaultEDSFormatHandler
@@ -203,26 +337,56 @@ func (network *Network) Command(nodeId uint8, nmtCommand nmt.Command) error {
 // By default, node automatically goes to operational state if no errors are detected.
 // First heartbeat, if enabled is started after 500ms
 func (network *Network) CreateLocalNode(nodeId uint8, odict any) (*n.LocalNode, error) {
-	var odNode *od.ObjectDictionary
-	var err error
+	odNode, err := network.localOD(nodeId, odict)
+	if err != nil {
+		return nil, err
+	}
+	return network.createLocalNode(nodeId, odNode)
+}
 
+// Create a [LocalNode] like [Network.CreateLocalNode], with parameters persisted in storage.
+// Stored parameters are loaded into OD before creating the node, then
+// store parameters (0x1010) & restore default parameters (0x1011) use storage,
+// see [n.LocalNode.SetParameterStore].
+func (network *Network) CreateLocalNodeWithStorage(nodeId uint8, odict any, storage od.ParameterStorage) (*n.LocalNode, error) {
+	odNode, err := network.localOD(nodeId, odict)
+	if err != nil {
+		return nil, err
+	}
+	store := od.NewParameterStore(odNode, storage)
+	err = store.Load(od.ParametersAll)
+	if err != nil {
+		return nil, err
+	}
+	node, err := network.createLocalNode(nodeId, odNode)
+	if err != nil {
+		return nil, err
+	}
+	err = node.SetParameterStore(store)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// Get the OD of a local node, either parsed from a path or given directly
+func (network *Network) localOD(nodeId uint8, odict any) (*od.ObjectDictionary, error) {
 	if nodeId < nodeIdMin || nodeId > nodeIdMax {
 		return nil, ErrIdRange
 	}
-
 	switch odType := odict.(type) {
 	case string:
-		odNode, err = network.odParser(odType, nodeId)
-		if err != nil {
-			return nil, err
-		}
+		return network.odParser(odType, nodeId)
 	case od.ObjectDictionary:
-		odNode = &odType
+		return &odType, nil
 	case *od.ObjectDictionary:
-		odNode = odType
+		return odType, nil
 	default:
 		return nil, fmt.Errorf("expecting string or ObjectDictionary got : %T", odict)
 	}
+}
+
+func (network *Network) createLocalNode(nodeId uint8, odNode *od.ObjectDictionary) (*n.LocalNode, error) {
 	// Create and initialize a "local" CANopen node
 	node, err := n.NewLocalNode(
 		network.BusManager,
@@ -247,7 +411,7 @@ func (network *Network) CreateLocalNode(nodeId uint8, odict any) (*n.LocalNode,
 	if err != nil {
 		return nil, err
 	}
-	err = controller.Start(context.Background())
+	err = network.startController(controller)
 	if err != nil {
 		return nil, err
 	}
@@ -299,7 +463,7 @@ func (network *Network) AddRemoteNode(nodeId uint8, odict any) (*n.RemoteNode, e
 	if err != nil {
 		return nil, err
 	}
-	err = controller.Start(context.Background())
+	err = network.startController(controller)
 	if err != nil {
 		return nil, err
 	}
@@ -310,6 +474,7 @@ func (network *Network) AddRemoteNode(nodeId uint8, odict any) (*n.RemoteNode, e
 // To control high level node behaviour (starting, stopping the node)
 func (network *Network) AddNode(node n.Node) (*n.NodeProcessor, error) {
 	controller := n.NewNodeProcessor(node, network.logger)
+	controller.SetClock(network.clock)
 	_, ok := network.controllers[node.GetID()]
 	if ok {
 		return nil, ErrIdConflict
@@ -318,6 +483,14 @@ func (network *Network) AddNode(node n.Node) (*n.NodeProcessor, error) {
 	return controller, nil
 }
 
+// Start processing of a node, unless it is driven by a [Simulation]
+func (network *Network) startController(controller *n.NodeProcessor) error {
+	if network.simulation != nil {
+		return nil
+	}
+	return controller.Start(context.Background())
+}
+
 // RemoveNode gracefully exits any running go routine for this node
 // It also removes any object associated with the node, including OD
 func (network *Network) RemoveNode(nodeId uint8) error {
@@ -330,6 +503,9 @@ func (network *Network) RemoveNode(nodeId uint8) error {
 		return err
 	}
 	node.Wait()
+	if remote, ok := node.GetNode().(*n.RemoteNode); ok {
+		remote.Stop()
+	}
 	delete(network.controllers, nodeId)
 	return nil
 }
@@ -360,68 +536,80 @@ func (network *Network) Local(nodeId uint8) (*n.LocalNode, error) {
 	return remote, nil
 }
 
+// Get the [n.NodeProcessor] handling a node of the network, e.g. for detecting processing overruns
+func (network *Network) Processor(nodeId uint8) (*n.NodeProcessor, error) {
+	ctrl, ok := network.controllers[nodeId]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return ctrl, nil
+}
+
+// ReloadLocalNode replaces the OD of a local node without recreating it, see [n.LocalNode.ReloadOD].
+// OD can be either a string : path to OD or an OD object.
+// Node processing is stopped during the reload and started again afterwards.
+func (network *Network) ReloadLocalNode(nodeId uint8, odict any) error {
+	ctrl, ok := network.controllers[nodeId]
+	if !ok {
+		return ErrNotFound
+	}
+	local, ok := ctrl.GetNode().(*n.LocalNode)
+	if !ok {
+		return ErrInvalidNodeType
+	}
+	odNode, err := network.localOD(nodeId, odict)
+	if err != nil {
+		return err
+	}
+	running := ctrl.Running()
+	if running {
+		_ = ctrl.Stop()
+		_ = ctrl.Wait()
+	}
+	err = local.ReloadOD(odNode)
+	if running {
+		return errors.Join(err, ctrl.Start(context.Background()))
+	}
+	return err
+}
+
 // Configurator creates a [NodeConfigurator] object for a given id
 // using the networks internal sdo client
 func (network *Network) Configurator(nodeId uint8) *config.NodeConfigurator {
-	return config.NewNodeConfigurator(nodeId, network.logger, network.SDOClient)
+	configurator := config.NewNodeConfigurator(nodeId, network.logger, network.SDOClient)
+	if network.lssMaster != nil {
+		configurator.SetLSSMaster(network.lssMaster)
+	}
+	// Known OD is used for validating PDO mappings
+	if odict, err := network.GetOD(nodeId); err == nil {
+		configurator.SetOD(odict)
+	}
+	return configurator
 }
 
-// NodeInformation contains manufacturer information and identity object
-type NodeInformation struct {
-	config.ManufacturerInformation
-	config.Identity
+// LSSMaster returns the network's LSS master, available after [Network.Connect]
+func (network *Network) LSSMaster() *lss.LSSMaster {
+	return network.lssMaster
 }
 
 // Scan network for nodes via SDO, and return map of found node ids and respective
 // node information. Scanning is done in parallel and requires that the scanned
 // nodes have an SDO server and that the identity object is implemented (0x1018) which
-// is mandatory per CiA standard.
+// is mandatory per CiA standard. See [Network.ScanWith] for more options.
 func (network *Network) Scan(timeoutMs uint32) (map[uint8]NodeInformation, error) {
-	// Create multiple sdo clients to speed up discovery
-	clients := make([]*sdo.SDOClient, 0)
-	for i := nodeIdMin; i <= nodeIdMax; i++ {
-		client, err := sdo.NewSDOClient(network.BusManager, network.logger, nil, i, timeoutMs, nil)
-		if err != nil {
-			return nil, err
-		}
-		clients = append(clients, client)
-	}
-	wg := sync.WaitGroup{}
-	mu := sync.Mutex{}
-	scan := make(map[uint8]NodeInformation)
-	wg.Add(len(clients))
-	// Scanning is done in parallel to speed up discovery
-	// As the limiting factor is the SDO round-trip time which
-	// can take up to timeoutMs to complete
-	for i, client := range clients {
-		nodeId := uint8(i + 1)
-		go func(client *sdo.SDOClient) {
-			defer wg.Done()
-			config := config.NewNodeConfigurator(nodeId, network.logger, client)
-			identity, err := config.ReadIdentity()
-			if err != nil {
-				// Failure to respond to ReadIdentity means node doesn't exist
-				// Or that it does not implement a mandatory object so it will
-				// be considered as not found
-				return
-			}
-			manufacturerInfo := config.ReadManufacturerInformation()
-			mu.Lock()
-			defer mu.Unlock()
-			scan[nodeId] = NodeInformation{
-				ManufacturerInformation: manufacturerInfo,
-				Identity:                *identity,
-			}
-		}(client)
-	}
-	wg.Wait()
-	return scan, nil
+	return network.ScanWith(context.Background(), ScanOptions{TimeoutMs: timeoutMs})
 }
 
 func (network *Network) SetLogger(logger *slog.Logger) {
 	network.logger = logger
 }
 
+// Set the [n.Clock] used for processing of nodes added afterwards,
+// by default the system clock is used
+func (network *Network) SetClock(clock n.Clock) {
+	network.clock = clock
+}
+
 func (network *Network) SetParser(parser od.Parser) {
 	network.odParser = parser
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	can "github.com/samsamfire/gocanopen/pkg/can"
 	"github.com/samsamfire/gocanopen/pkg/can/virtual"
 	"github.com/samsamfire/gocanopen/pkg/od"
 	"github.com/stretchr/testify/assert"
@@ -101,3 +102,20 @@ func TestAddRemoveNodes(t *testing.T) {
 	})
 
 }
+
+func TestLoopbackNetworks(t *testing.T) {
+	bus1 := can.NewLoopbackBus(t.Name())
+	bus1.SetReceiveOwn(true)
+	network1 := NewNetwork(bus1)
+	assert.Nil(t, network1.Connect())
+	defer network1.Disconnect()
+	network2 := NewNetwork(can.NewLoopbackBus(t.Name()))
+	assert.Nil(t, network2.Connect())
+	defer network2.Disconnect()
+
+	_, err := network1.CreateLocalNode(NodeIdTest, od.Default())
+	assert.Nil(t, err)
+	value, err := network2.ReadUint8(NodeIdTest, 0x2005, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x10, value)
+}
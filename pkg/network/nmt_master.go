@@ -0,0 +1,185 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+)
+
+var ErrNMTTimeout = errors.New("node(s) did not reach expected NMT state before timeout")
+
+// NodeState returns the last NMT state reported by nodeId in its heartbeat,
+// or node guarding response. nmt.StateUnknown if none or if heartbeat was lost.
+func (network *Network) NodeState(nodeId uint8) uint8 {
+	if network.heartbeats == nil {
+		return nmt.StateUnknown
+	}
+	return network.heartbeats.State(nodeId)
+}
+
+// NodeStates returns the last NMT state of every node with a known state
+func (network *Network) NodeStates() map[uint8]uint8 {
+	if network.heartbeats == nil {
+		return map[uint8]uint8{}
+	}
+	return network.heartbeats.States()
+}
+
+// WaitForState blocks until nodeId reports the given NMT state, or until timeout.
+// It returns immediately if the node is already in that state.
+// Waiting for nmt.StateInitializing waits for the next boot-up.
+func (network *Network) WaitForState(nodeId uint8, state uint8, timeout time.Duration) error {
+	if network.heartbeats == nil {
+		return ErrNotConnected
+	}
+	if nodeId < nodeIdMin || nodeId > 127 {
+		return ErrIdRange
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	// Subscribe before checking state, so that no change is missed
+	events := network.heartbeats.NodeEvents(ctx, nodeId, 10)
+	if state != nmt.StateInitializing && network.heartbeats.State(nodeId) == state {
+		return nil
+	}
+	for event := range events {
+		if event.Type != heartbeat.EventToggle && event.NmtState == state {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w : x%x, expected %v, got %v", ErrNMTTimeout, nodeId,
+		nmt.StateDescription(state), nmt.StateDescription(network.heartbeats.State(nodeId)))
+}
+
+// State expected after a command, reset commands are confirmed by a boot-up
+func commandState(command nmt.Command) (uint8, error) {
+	switch command {
+	case nmt.CommandEnterOperational:
+		return nmt.StateOperational, nil
+	case nmt.CommandEnterPreOperational:
+		return nmt.StatePreOperational, nil
+	case nmt.CommandEnterStopped:
+		return nmt.StateStopped, nil
+	case nmt.CommandResetNode, nmt.CommandResetCommunication:
+		return nmt.StateInitializing, nil
+	}
+	return nmt.StateUnknown, canopen.ErrIllegalArgument
+}
+
+// CommandWait sends an NMT command like [Network.Command] and waits for its confirmation
+// via heartbeat monitoring : the expected state for state commands, or a boot-up for
+// reset commands. When broadcasting (nodeId 0), every node with a known state
+// (see [Network.NodeStates]) should confirm. Nodes must produce heartbeats or be guarded.
+func (network *Network) CommandWait(nodeId uint8, command nmt.Command, timeout time.Duration) error {
+	if network.heartbeats == nil {
+		return ErrNotConnected
+	}
+	if nodeId > 127 {
+		return ErrIdRange
+	}
+	expected, err := commandState(command)
+	if err != nil {
+		return err
+	}
+	pending := map[uint8]bool{nodeId: true}
+	if nodeId == 0 {
+		pending = map[uint8]bool{}
+		for id := range network.NodeStates() {
+			pending[id] = true
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	events := network.heartbeats.NodeEvents(ctx, heartbeat.EventAllNodes, 128)
+	err = network.Command(nodeId, command)
+	if err != nil {
+		return err
+	}
+	// State may not change if node was already in expected state
+	confirmed := func() bool {
+		if expected != nmt.StateInitializing {
+			for id := range pending {
+				if network.heartbeats.State(id) == expected {
+					delete(pending, id)
+				}
+			}
+		}
+		return len(pending) == 0
+	}
+	if confirmed() {
+		return nil
+	}
+	for event := range events {
+		switch {
+		case !pending[event.NodeId]:
+		case expected == nmt.StateInitializing && event.Type == heartbeat.EventBoot:
+			delete(pending, event.NodeId)
+		case expected != nmt.StateInitializing && event.Type != heartbeat.EventToggle && event.NmtState == expected:
+			delete(pending, event.NodeId)
+		}
+		if confirmed() {
+			return nil
+		}
+	}
+	remaining := make([]uint8, 0, len(pending))
+	for id := range pending {
+		remaining = append(remaining, id)
+	}
+	slices.Sort(remaining)
+	return fmt.Errorf("%w : %v, expected %v", ErrNMTTimeout, remaining, nmt.StateDescription(expected))
+}
+
+// SetAutoStart enables or disables automatic start of nodeId : an NMT start command
+// is sent every time the node reports a boot-up, e.g. after an unexpected reboot.
+// nodeId 0 sets the default for all nodes, node specific settings take precedence.
+// This is available after [Network.Connect].
+func (network *Network) SetAutoStart(nodeId uint8, enabled bool) error {
+	if network.heartbeats == nil {
+		return ErrNotConnected
+	}
+	if nodeId > 127 {
+		return ErrIdRange
+	}
+	network.autoStartMu.Lock()
+	defer network.autoStartMu.Unlock()
+	if network.autoStart == nil {
+		network.autoStart = make(map[uint8]bool)
+	}
+	network.autoStart[nodeId] = enabled
+	if !network.autoStartListener {
+		network.heartbeats.OnNodeEvent(heartbeat.EventAllNodes, network.handleAutoStart)
+		network.autoStartListener = true
+	}
+	return nil
+}
+
+// Returns true if nodeId should be started after a boot-up
+func (network *Network) autoStarted(nodeId uint8) bool {
+	network.autoStartMu.Lock()
+	defer network.autoStartMu.Unlock()
+	enabled, ok := network.autoStart[nodeId]
+	if !ok {
+		return network.autoStart[0]
+	}
+	return enabled
+}
+
+func (network *Network) handleAutoStart(event heartbeat.Event) {
+	if event.Type != heartbeat.EventBoot || !network.autoStarted(event.NodeId) {
+		return
+	}
+	network.logger.Info("node booted, restarting", "id", event.NodeId)
+	// Not sent from CAN reception
+	go func() {
+		err := network.Command(event.NodeId, nmt.CommandEnterOperational)
+		if err != nil {
+			network.logger.Warn("failed to restart node", "id", event.NodeId, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/stretchr/testify/assert"
+)
+
+// Minimal slave answering NMT commands with a heartbeat, or a boot-up on reset
+type fakeSlave struct {
+	bm       *canopen.BusManager
+	nodeId   uint8
+	commands chan nmt.Command
+}
+
+func (slave *fakeSlave) Handle(frame canopen.Frame) {
+	if frame.Data[1] != 0 && frame.Data[1] != slave.nodeId {
+		return
+	}
+	command := nmt.Command(frame.Data[0])
+	slave.commands <- command
+	state := nmt.StateInitializing
+	switch command {
+	case nmt.CommandEnterOperational:
+		state = nmt.StateOperational
+	case nmt.CommandEnterStopped:
+		state = nmt.StateStopped
+	case nmt.CommandEnterPreOperational:
+		state = nmt.StatePreOperational
+	}
+	heartbeat := canopen.NewFrame(0x700+uint32(slave.nodeId), 0, 1)
+	heartbeat.Data[0] = state
+	go slave.bm.Send(heartbeat)
+}
+
+func TestNMTMaster(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	assert.Nil(t, local.Configurator().WriteHeartbeatPeriod(20))
+	slave := &fakeSlave{bm: network.BusManager, nodeId: 0x50, commands: make(chan nmt.Command, 10)}
+	assert.Nil(t, network.Subscribe(uint32(nmt.ServiceId), 0x7FF, false, slave))
+
+	assert.Nil(t, network.WaitForState(NodeIdTest, nmt.StateOperational, 2*time.Second))
+	assert.Equal(t, nmt.StateOperational, network.NodeState(NodeIdTest))
+	assert.Equal(t, nmt.StateUnknown, network.NodeState(0x50))
+
+	t.Run("wait for state timeout", func(t *testing.T) {
+		err := network.WaitForState(NodeIdTest, nmt.StateStopped, 100*time.Millisecond)
+		assert.ErrorIs(t, err, ErrNMTTimeout)
+		assert.Equal(t, ErrIdRange, network.WaitForState(0, nmt.StateStopped, time.Second))
+	})
+
+	t.Run("command with confirmation", func(t *testing.T) {
+		assert.Nil(t, network.CommandWait(NodeIdTest, nmt.CommandEnterPreOperational, 2*time.Second))
+		assert.Equal(t, nmt.StatePreOperational, local.NMT.GetInternalState())
+		assert.Nil(t, network.CommandWait(NodeIdTest, nmt.CommandEnterOperational, 2*time.Second))
+		assert.Equal(t, nmt.StateOperational, network.NodeState(NodeIdTest))
+		assert.Equal(t, canopen.ErrIllegalArgument, network.CommandWait(NodeIdTest, nmt.CommandEmpty, time.Second))
+	})
+
+	t.Run("reset confirmed by boot-up", func(t *testing.T) {
+		assert.Nil(t, network.CommandWait(0x50, nmt.CommandResetCommunication, 2*time.Second))
+		assert.Equal(t, nmt.CommandResetCommunication, <-slave.commands)
+		assert.Equal(t, nmt.StateInitializing, network.NodeState(0x50))
+	})
+
+	t.Run("broadcast waits for all known nodes", func(t *testing.T) {
+		states := network.NodeStates()
+		assert.Contains(t, states, NodeIdTest)
+		assert.Contains(t, states, uint8(0x50))
+		assert.Nil(t, network.CommandWait(0, nmt.CommandEnterStopped, 2*time.Second))
+		<-slave.commands
+		assert.Equal(t, nmt.StateStopped, network.NodeState(0x50))
+		assert.Equal(t, nmt.StateStopped, network.NodeState(NodeIdTest))
+		assert.Nil(t, network.CommandWait(0, nmt.CommandEnterOperational, 2*time.Second))
+		<-slave.commands
+	})
+
+	t.Run("timeout if not confirmed", func(t *testing.T) {
+		err := network.CommandWait(0x51, nmt.CommandEnterOperational, 100*time.Millisecond)
+		assert.ErrorIs(t, err, ErrNMTTimeout)
+	})
+
+	t.Run("auto start on boot-up", func(t *testing.T) {
+		assert.Nil(t, network.SetAutoStart(0x50, true))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events, err := network.HeartbeatEvents(ctx, 0x50, 10)
+		assert.Nil(t, err)
+		bootUp := canopen.NewFrame(0x750, 0, 1)
+		assert.Nil(t, network.Send(bootUp))
+		select {
+		case command := <-slave.commands:
+			assert.Equal(t, nmt.CommandEnterOperational, command)
+		case <-time.After(2 * time.Second):
+			t.Fatal("node not started after boot-up")
+		}
+		assert.Nil(t, network.WaitForState(0x50, nmt.StateOperational, 2*time.Second))
+		event := <-events
+		assert.EqualValues(t, heartbeat.EventBoot, event.Type)
+
+		// Disabled for node, even if enabled for all
+		assert.Nil(t, network.SetAutoStart(0, true))
+		assert.Nil(t, network.SetAutoStart(0x50, false))
+		assert.Nil(t, network.Send(bootUp))
+		select {
+		case command := <-slave.commands:
+			t.Fatalf("unexpected command %v", command)
+		case <-time.After(200 * time.Millisecond):
+		}
+		assert.Equal(t, ErrIdRange, network.SetAutoStart(128, true))
+	})
+}
@@ -1,11 +1,21 @@
 package network
 
 import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/emergency"
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
 	"github.com/samsamfire/gocanopen/pkg/node"
 	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -188,6 +198,120 @@ func TestReadRemote(t *testing.T) {
 
 }
 
+func TestReadWriteBits(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	network2 := CreateNetworkEmptyTest()
+	defer network2.Disconnect()
+	remote, err := network2.AddRemoteNode(NodeIdTest, od.Default())
+	assert.Nil(t, err)
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+
+	// UNSIGNED16 value is 0x1111
+	bits, err := remote.ReadBits(0x2006, 0, od.BitMask(8, 8))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x1100, bits)
+	err = remote.WriteBits(0x2006, 0, od.BitMask(0, 4), 0xA)
+	assert.Nil(t, err)
+	value, err := local.ReadUint(0x2006, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x111A, value)
+	err = remote.WriteBits(0x2006, 0, 0x10000, 0)
+	assert.Equal(t, od.ErrValueHigh, err)
+	err = remote.WriteBits(0x2009, 0, 0x1, 0x1)
+	assert.Equal(t, od.ErrTypeMismatch, err)
+}
+
+func TestReadWriteValue(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	network2 := CreateNetworkEmptyTest()
+	defer network2.Disconnect()
+	remote, err := network2.AddRemoteNode(NodeIdTest, od.Default())
+	assert.Nil(t, err)
+
+	u16, err := node.ReadValue[uint16](remote, 0x2006, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x1111, u16)
+	i8, err := node.ReadValue[int8](remote, 0x2002, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x33, i8)
+	f32, err := node.ReadValue[float32](remote, 0x2008, 0)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.1, f32, 1e-6)
+
+	assert.Nil(t, node.WriteValue(remote, 0x2003, 0, int16(-10)))
+	i16, err := node.ReadValue[int16](remote, 0x2003, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, -10, i16)
+	assert.Nil(t, node.WriteValue(remote, 0x2001, 0, true))
+	b, err := node.ReadValue[bool](remote, 0x2001, 0)
+	assert.Nil(t, err)
+	assert.True(t, b)
+	assert.Nil(t, node.WriteValue(remote, 0x2009, 0, "typed"))
+	s, err := node.ReadValue[string](remote, 0x2009, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "typed", s)
+
+	// Wrong size
+	_, err = node.ReadValue[uint32](remote, 0x2006, 0)
+	assert.Equal(t, od.ErrDataShort, err)
+	_, err = node.ReadValue[uint8](remote, 0x2006, 0)
+	assert.Equal(t, od.ErrDataLong, err)
+	err = node.WriteValue(remote, 0x2006, 0, uint32(0))
+	assert.ErrorIs(t, err, sdo.AbortDataLong)
+}
+
+func TestReadWriteUnicodeAndTime(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	network2 := CreateNetworkEmptyTest()
+	defer network2.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	odict := od.Default()
+	for _, dict := range []*od.ObjectDictionary{local.GetOD(), odict} {
+		_, err = dict.AddVariableType(0x3100, "UNICODE_STRING value", od.UNICODE_STRING, od.AttributeSdoRw, "héllo")
+		assert.Nil(t, err)
+		_, err = dict.AddVariableType(0x3101, "TIME_OF_DAY value", od.TIME_OF_DAY, od.AttributeSdoRw, "")
+		assert.Nil(t, err)
+		_, err = dict.AddVariableType(0x3102, "TIME_DIFFERENCE value", od.TIME_DIFFERENCE, od.AttributeSdoRw, "")
+		assert.Nil(t, err)
+	}
+	remote, err := network2.AddRemoteNode(NodeIdTest, odict)
+	assert.Nil(t, err)
+
+	unicode, err := node.ReadValue[node.UnicodeString](remote, 0x3100, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "héllo", unicode)
+	str, err := remote.ReadString("UNICODE_STRING value", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "héllo", str)
+	assert.Nil(t, remote.Write("UNICODE_STRING value", 0, "wörld"))
+	unicode, err = node.ReadValue[node.UnicodeString](remote, 0x3100, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "wörld", unicode)
+	assert.Nil(t, node.WriteValue(remote, 0x3100, 0, node.UnicodeString("abcde")))
+	str, err = local.ReadString(0x3100, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "abcde", str)
+
+	date := time.Date(2024, time.March, 10, 13, 14, 15, 16_000_000, time.Local)
+	assert.Nil(t, node.WriteValue(remote, 0x3101, 0, date))
+	readDate, err := node.ReadValue[time.Time](remote, 0x3101, 0)
+	assert.Nil(t, err)
+	assert.True(t, date.Equal(readDate), "%v != %v", date, readDate)
+	assert.Nil(t, remote.Write("TIME_DIFFERENCE value", 0, 50*time.Hour+time.Second))
+	value, err := remote.Read(0x3102, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 50*time.Hour+time.Second, value)
+	// Time difference is not a date
+	assert.Equal(t, od.ErrTypeMismatch, remote.Write(0x3101, 0, time.Second))
+	_, err = node.ReadValue[time.Time](remote, 0x2006, 0)
+	assert.Equal(t, od.ErrDataShort, err)
+}
+
 func TestRemoteNodeRPDO(t *testing.T) {
 	network := CreateNetworkTest()
 	networkRemote := CreateNetworkEmptyTest()
@@ -233,6 +357,52 @@ func TestRemoteNodeRPDOUsingRemote(t *testing.T) {
 	assert.Equal(t, []byte{10}, read)
 }
 
+func TestRemoteNodeLifecycle(t *testing.T) {
+	network := CreateNetworkTest()
+	networkRemote := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	defer networkRemote.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	assert.Nil(t, local.SetHeartbeatPeriod(50))
+	remoteNode, err := networkRemote.AddRemoteNode(NodeIdTest, od.Default())
+	assert.Nil(t, err)
+
+	// Nothing started, nothing to stop
+	assert.Equal(t, nmt.StateUnknown, remoteNode.HeartbeatState())
+	remoteNode.StopPDOs()
+	remoteNode.StopHeartbeatMonitor()
+	remoteNode.Stop()
+
+	events := atomic.Int32{}
+	assert.Nil(t, remoteNode.OnHeartbeatEvent(func(event heartbeat.Event) {
+		events.Add(1)
+	}))
+	assert.Nil(t, remoteNode.StartHeartbeatMonitor(200*time.Millisecond))
+	assert.Eventually(t, func() bool {
+		return remoteNode.HeartbeatState() == nmt.StateOperational
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.NotZero(t, events.Load())
+
+	// PDOs can be restarted
+	assert.Nil(t, remoteNode.StartPDOs(false))
+	assert.Nil(t, remoteNode.StartPDOs(false))
+	assert.Nil(t, network.Configurator(NodeIdTest).EnablePDO(1+256))
+	assert.Nil(t, network.WriteRaw(NodeIdTest, 0x2002, 0, []byte{10}, false))
+	assert.Eventually(t, func() bool {
+		value, _ := remoteNode.SDOClient.ReadUint8(0, 0x2002, 0)
+		return value == 10
+	}, 2*time.Second, 10*time.Millisecond)
+
+	remoteNode.Stop()
+	assert.Equal(t, nmt.StateUnknown, remoteNode.HeartbeatState())
+	assert.Nil(t, network.WriteRaw(NodeIdTest, 0x2002, 0, []byte{20}, false))
+	time.Sleep(200 * time.Millisecond)
+	value, err := remoteNode.SDOClient.ReadUint8(0, 0x2002, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, value)
+}
+
 func TestTimeSynchronization(t *testing.T) {
 	const slaveId = 0x66
 	network := CreateNetworkTest()
@@ -301,6 +471,60 @@ func TestScan(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestScanWith(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	network2 := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	defer network2.Disconnect()
+	for i := range 3 {
+		local, err := network.CreateLocalNode(uint8(i)+1, od.Default())
+		assert.Nil(t, err)
+		assert.Nil(t, local.SetHeartbeatPeriod(50))
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	t.Run("inventory with progress", func(t *testing.T) {
+		progress := make([]ScanProgress, 0)
+		scan, err := network2.ScanWith(context.Background(), ScanOptions{
+			TimeoutMs:     100,
+			NodeIds:       []uint8{1, 2, 3, 4, 5},
+			MaxConcurrent: 2,
+			Progress: func(p ScanProgress) {
+				progress = append(progress, p)
+			},
+		})
+		assert.Nil(t, err)
+		assert.Len(t, scan, 3)
+		assert.Len(t, progress, 5)
+		assert.Equal(t, 5, progress[4].Done)
+		assert.Equal(t, 5, progress[4].Total)
+		assert.NotEqual(t, nmt.StateUnknown, scan[2].NmtState)
+		assert.Equal(t, "DUT", scan[3].ManufacturerDeviceName)
+	})
+
+	t.Run("skip identity & manufacturer", func(t *testing.T) {
+		scan, err := network2.ScanWith(context.Background(), ScanOptions{
+			TimeoutMs:        100,
+			NodeIds:          []uint8{1},
+			SkipIdentity:     true,
+			SkipManufacturer: true,
+		})
+		assert.Nil(t, err)
+		assert.Len(t, scan, 1)
+		assert.Empty(t, scan[1].ManufacturerDeviceName)
+	})
+
+	t.Run("invalid id & cancel", func(t *testing.T) {
+		_, err := network2.ScanWith(context.Background(), ScanOptions{NodeIds: []uint8{0}})
+		assert.Equal(t, ErrIdRange, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		scan, err := network2.ScanWith(ctx, ScanOptions{TimeoutMs: 100, MaxConcurrent: 1})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, scan)
+	})
+}
+
 func TestExport(t *testing.T) {
 	network := CreateNetworkEmptyTest()
 	network2 := CreateNetworkEmptyTest()
@@ -322,3 +546,345 @@ func TestExport(t *testing.T) {
 	})
 
 }
+
+func TestLocalNodeInjectCapturePDO(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	t.Run("inject rpdo", func(t *testing.T) {
+		configurator := local.Configurator()
+		err := configurator.DisablePDO(1)
+		assert.Nil(t, err)
+		err = configurator.WriteMappings(1, []config.PDOMappingParameter{
+			{Index: 0x2002, Subindex: 0, LengthBits: 8},
+		})
+		assert.Nil(t, err)
+		err = configurator.EnablePDO(1)
+		assert.Nil(t, err)
+		assert.Equal(t, canopen.ErrIllegalArgument, local.InjectRPDO(0, []byte{0x10}))
+		assert.Equal(t, canopen.ErrIllegalArgument, local.InjectRPDO(1, make([]byte, 9)))
+		err = local.InjectRPDO(1, []byte{0x42})
+		assert.Nil(t, err)
+		assert.Eventually(t, func() bool {
+			val, _ := local.ReadInt("INTEGER8 value", "")
+			return val == 0x42
+		}, 500*time.Millisecond, 10*time.Millisecond)
+	})
+	t.Run("capture tpdo", func(t *testing.T) {
+		captured := make(chan []byte, 10)
+		local.CaptureTPDO(func(pdoNb uint16, data []byte) {
+			if pdoNb == 257 {
+				captured <- data
+			}
+		})
+		defer local.CaptureTPDO(nil)
+		err := local.Configurator().EnablePDO(257)
+		assert.Nil(t, err)
+		select {
+		case data := <-captured:
+			assert.NotEmpty(t, data)
+		case <-time.After(2 * time.Second):
+			t.Fatal("no tpdo captured")
+		}
+	})
+}
+
+func TestLocalNodePDOBindings(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+
+	t.Run("invalid bindings", func(t *testing.T) {
+		var value int8
+		var name string
+		assert.Equal(t, od.ErrIdxNotExist, local.BindTPDOVariable(0x3333, 0, &value))
+		assert.Equal(t, od.ErrTypeMismatch, local.BindTPDOVariable("INTEGER8 value", 0, value))
+		assert.Equal(t, od.ErrTypeMismatch, local.BindRPDOVariable("INTEGER8 value", 0, &name))
+		assert.Equal(t, od.ErrNoMap, local.BindRPDOVariable(0x1000, 0, &value))
+	})
+
+	t.Run("rpdo", func(t *testing.T) {
+		configurator := local.Configurator()
+		assert.Nil(t, configurator.DisablePDO(1))
+		assert.Nil(t, configurator.WriteMappings(1, []config.PDOMappingParameter{
+			{Index: 0x2002, Subindex: 0, LengthBits: 8},
+		}))
+		assert.Nil(t, configurator.EnablePDO(1))
+		received := make(chan any, 10)
+		assert.Nil(t, local.BindRPDOFunc("INTEGER8 value", 0, func(value any) {
+			received <- value
+		}))
+		assert.Nil(t, local.InjectRPDO(1, []byte{0x42}))
+		select {
+		case value := <-received:
+			assert.EqualValues(t, 0x42, value)
+		case <-time.After(2 * time.Second):
+			t.Fatal("rpdo binding not called")
+		}
+	})
+
+	t.Run("tpdo", func(t *testing.T) {
+		// Event driven transmission requires an extension on the mapped entry
+		entry := local.GetOD().Index("UNSIGNED32 value")
+		entry.AddExtension(nil, od.ReadEntryDefault, od.WriteEntryDefault)
+		var value atomic.Uint32
+		assert.Nil(t, local.BindTPDOFunc("UNSIGNED32 value", 0, func() any { return value.Load() }))
+		captured := make(chan []byte, 10)
+		local.CaptureTPDO(func(pdoNb uint16, data []byte) {
+			if pdoNb == 257 {
+				captured <- data
+			}
+		})
+		defer local.CaptureTPDO(nil)
+		err := local.Configurator().RemapPDO(257, []config.PDOMappingParameter{
+			{Index: 0x2007, Subindex: 0, LengthBits: 32},
+		}, 0xFE, 0, 0)
+		assert.Nil(t, err)
+		value.Store(0x12345678)
+		assert.Eventually(t, func() bool {
+			for {
+				select {
+				case data := <-captured:
+					if bytes.Equal(data, []byte{0x78, 0x56, 0x34, 0x12}) {
+						return true
+					}
+				default:
+					return false
+				}
+			}
+		}, 2*time.Second, 10*time.Millisecond)
+		read, err := local.ReadUint("UNSIGNED32 value", "")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x12345678, read)
+	})
+}
+
+func TestNodeProcessorClock(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	clock := node.NewManualClock(time.Unix(1000, 0))
+	network.SetClock(clock)
+	local, err := network.CreateLocalNode(NodeIdTest, od.Default())
+	assert.Nil(t, err)
+	controller, err := network.Processor(NodeIdTest)
+	assert.Nil(t, err)
+
+	t.Run("manual ticks", func(t *testing.T) {
+		clock.Advance(time.Millisecond)
+		assert.Eventually(t, func() bool {
+			main, _ := controller.LastProcessed()
+			return main.Equal(clock.Now())
+		}, time.Second, time.Millisecond)
+		clock.Advance(9 * time.Millisecond)
+		assert.Eventually(t, func() bool {
+			_, background := controller.LastProcessed()
+			return background.Equal(clock.Now())
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("driven processing", func(t *testing.T) {
+		assert.Nil(t, controller.Stop())
+		assert.Nil(t, controller.Wait())
+		controller.TickMain()
+		controller.TickBackground()
+		local.SYNC.ResetStats()
+		// SYNC period is 100ms in default OD
+		for range 20 {
+			clock.Advance(10 * time.Millisecond)
+			controller.TickMain()
+			controller.TickBackground()
+		}
+		assert.EqualValues(t, 2, local.SYNC.Stats().Sent)
+	})
+
+	t.Run("overruns", func(t *testing.T) {
+		// Every processing cycle takes 2ms
+		controller.SetClock(&steppingClock{ManualClock: clock, step: 2 * time.Millisecond})
+		controller.ResetStats()
+		controller.SetOverrunEMCY(true)
+		overruns := []node.Overrun{}
+		controller.SetOverrunHook(func(overrun node.Overrun) {
+			overruns = append(overruns, overrun)
+		})
+		for range 3 {
+			controller.TickMain()
+			controller.TickBackground()
+		}
+		stats := controller.Stats()
+		assert.EqualValues(t, 3, stats.MainOverruns)
+		assert.EqualValues(t, 0, stats.BackgroundOverruns)
+		assert.Equal(t, 2*time.Millisecond, stats.MainMaxDuration)
+		assert.Equal(t, 2*time.Millisecond, stats.MainAvgDuration)
+		assert.Equal(t, 2*time.Millisecond, stats.BackgroundAvgDuration)
+		assert.Len(t, overruns, 3)
+		assert.Equal(t, node.Overrun{Loop: node.LoopMain, Duration: 2 * time.Millisecond, Period: time.Millisecond}, overruns[0])
+		assert.True(t, local.EMCY.IsError(emergency.EmGenericSoftwareError))
+
+		controller.ResetStats()
+		assert.Equal(t, node.ProcessorStats{}, controller.Stats())
+		assert.False(t, local.EMCY.IsError(emergency.EmGenericSoftwareError))
+	})
+}
+
+// Clock moving forward every time it is read
+type steppingClock struct {
+	*node.ManualClock
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.Advance(c.step)
+	return c.ManualClock.Now()
+}
+
+func TestLocalNodeDiagnostics(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	_, err = local.AddDiagnosticsObject(node.DefaultDiagnosticsIndex)
+	assert.Nil(t, err)
+	_, err = local.AddDiagnosticsObject(node.DefaultDiagnosticsIndex)
+	assert.ErrorIs(t, err, canopen.ErrIllegalArgument)
+
+	t.Run("nmt & heartbeat", func(t *testing.T) {
+		local.ResetDiagnostics()
+		assert.Nil(t, network.Command(0, nmt.CommandEnterPreOperational))
+		assert.Nil(t, network.Command(NodeIdTest, nmt.CommandEnterOperational))
+		assert.Eventually(t, func() bool {
+			return local.Diagnostics().NMTCommandsReceived == 2
+		}, 500*time.Millisecond, 10*time.Millisecond)
+		// Read remotely
+		commands, err := network.ReadUint32(NodeIdTest, node.DefaultDiagnosticsIndex, 10)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, commands)
+		heartbeats, err := network.ReadUint32(NodeIdTest, node.DefaultDiagnosticsIndex, 9)
+		assert.Nil(t, err)
+		assert.NotZero(t, heartbeats)
+		nbSubs, err := network.ReadUint8(NodeIdTest, node.DefaultDiagnosticsIndex, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 10, nbSubs)
+		err = network.WriteRaw(NodeIdTest, node.DefaultDiagnosticsIndex, 1, uint32(0), false)
+		assert.ErrorIs(t, err, sdo.AbortReadOnly)
+	})
+
+	t.Run("pdo", func(t *testing.T) {
+		configurator := local.Configurator()
+		assert.Nil(t, configurator.DisablePDO(1))
+		assert.Nil(t, configurator.WriteMappings(1, []config.PDOMappingParameter{
+			{Index: 0x2002, Subindex: 0, LengthBits: 8},
+		}))
+		assert.Nil(t, configurator.EnablePDO(1))
+		local.ResetDiagnostics()
+		assert.Nil(t, local.InjectRPDO(1, []byte{0x42}))
+		assert.Nil(t, local.InjectRPDO(1, []byte{}))
+		diagnostics := local.Diagnostics()
+		assert.EqualValues(t, 1, diagnostics.RPDOReceived)
+		assert.EqualValues(t, 1, diagnostics.RPDOErrors)
+		received, err := network.ReadUint32(NodeIdTest, node.DefaultDiagnosticsIndex, 3)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, received)
+		assert.Nil(t, configurator.EnablePDO(257))
+		assert.Eventually(t, func() bool {
+			return local.Diagnostics().TPDOSent > 0
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestLocalNodeParameterStorage(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	storage := od.NewJSONFileStorage(filepath.Join(t.TempDir(), "params.json"))
+	local, err := network.CreateLocalNode(NodeIdTest, od.Default())
+	assert.Nil(t, err)
+	assert.Nil(t, local.SetParameterStorage(storage))
+
+	// Wrong signature
+	err = network.WriteRaw(NodeIdTest, od.EntryStoreParameters, od.ParametersAll, uint32(0x1234), false)
+	assert.ErrorIs(t, err, sdo.AbortDataTransfer)
+	err = network.WriteRaw(NodeIdTest, od.EntryRestoreDefaultParameters, od.ParametersAll, od.SignatureSave, false)
+	assert.ErrorIs(t, err, sdo.AbortDataTransfer)
+
+	// Store & load on a new node
+	assert.Nil(t, network.WriteRaw(NodeIdTest, od.EntryProducerHeartbeatTime, 0, uint16(1500), false))
+	assert.Nil(t, network.WriteRaw(NodeIdTest, od.EntryStoreParameters, od.ParametersAll, od.SignatureSave, false))
+	odict := od.Default()
+	assert.Nil(t, odict.LoadParameters(storage))
+	period, err := odict.Index(od.EntryProducerHeartbeatTime).Uint16(0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1500, period)
+
+	// Restore defaults
+	assert.Nil(t, network.WriteRaw(NodeIdTest, od.EntryRestoreDefaultParameters, od.ParametersCommunication, od.SignatureLoad, false))
+	values, err := storage.Load()
+	assert.Nil(t, err)
+	for _, value := range values {
+		assert.False(t, value.Index < 0x2000, "x%x should not be stored", value.Index)
+	}
+}
+
+func TestLocalNodeParameterStore(t *testing.T) {
+	storage := od.NewJSONFileStorage(filepath.Join(t.TempDir(), "params.json"))
+	network := CreateNetworkEmptyTest()
+	local, err := network.CreateLocalNodeWithStorage(NodeIdTest, od.Default(), storage)
+	assert.Nil(t, err)
+	assert.NotNil(t, local.ParameterStore())
+	assert.False(t, local.SaveOnShutdown())
+	assert.Nil(t, local.SetSaveOnShutdown(true))
+
+	// Stored on shutdown
+	assert.Nil(t, local.GetOD().Index(od.EntryProducerHeartbeatTime).PutUint16(0, 1700, false))
+	assert.Nil(t, network.Shutdown())
+
+	// Loaded at creation
+	network = CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	local, err = network.CreateLocalNodeWithStorage(NodeIdTest, od.Default(), storage)
+	assert.Nil(t, err)
+	period, err := local.GetOD().Index(od.EntryProducerHeartbeatTime).Uint16(0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1700, period)
+
+	// No store
+	other, err := network.CreateLocalNode(NodeIdTest+1, od.Default())
+	assert.Nil(t, err)
+	assert.ErrorIs(t, other.SaveParameters(od.ParametersAll), node.ErrNoParameterStore)
+	assert.ErrorIs(t, other.SetSaveOnShutdown(true), node.ErrNoParameterStore)
+}
+
+func TestLocalNodeReloadOD(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	local, err := network.CreateLocalNode(NodeIdTest, od.Default())
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool {
+		return local.NMT.GetInternalState() == nmt.StateOperational
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, ErrNotFound, network.ReloadLocalNode(NodeIdTest+1, od.Default()))
+	assert.ErrorIs(t, network.ReloadLocalNode(NodeIdTest, od.Default()), canopen.ErrWrongNMTState)
+
+	assert.Nil(t, network.Command(NodeIdTest, nmt.CommandEnterPreOperational))
+	assert.Eventually(t, func() bool {
+		return local.NMT.GetInternalState() == nmt.StatePreOperational
+	}, time.Second, 10*time.Millisecond)
+	odict := od.Default()
+	assert.Nil(t, odict.Index(0x2005).PutUint8(0, 0x42, true))
+	assert.Nil(t, network.ReloadLocalNode(NodeIdTest, odict))
+	assert.Same(t, odict, local.GetOD())
+	assert.Equal(t, nmt.StatePreOperational, local.NMT.GetInternalState())
+
+	// New SDO server is the only one answering
+	value, err := network.ReadUint8(NodeIdTest, 0x2005, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x42, value)
+	assert.Nil(t, network.WriteRaw(NodeIdTest, od.EntryProducerHeartbeatTime, 0, uint16(1200), false))
+	period, err := odict.Index(od.EntryProducerHeartbeatTime).Uint16(0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1200, period)
+	assert.Nil(t, network.Command(NodeIdTest, nmt.CommandEnterOperational))
+	assert.Eventually(t, func() bool {
+		return local.NMT.GetInternalState() == nmt.StateOperational
+	}, time.Second, 10*time.Millisecond)
+}
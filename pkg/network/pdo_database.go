@@ -0,0 +1,394 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/pdo"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+var (
+	ErrPDOConflict = errors.New("COB-ID is already used by another TPDO")
+	ErrPDOUnknown  = errors.New("no PDO registered for this COB-ID")
+	ErrPDOLength   = errors.New("PDO frame is shorter than its mapping")
+)
+
+// Highest index of the CiA 301 dummy mapping entries (data types),
+// they only reserve space inside of a PDO
+const pdoDummyIndexMax = 0x1F
+
+// An object mapped into a PDO, i.e. a named value sent on the bus
+type Signal struct {
+	NodeId   uint8  // Node owning the object
+	Name     string // OD name, "<entry>.<sub-entry>" for ARRAY & RECORD members
+	Index    uint16
+	Subindex uint8
+	DataType uint8 // CiA 301 data type, 0 if unknown
+	Offset   int   // Offset in bits inside of the PDO
+	Length   int   // Length in bits
+}
+
+// Configuration of a single PDO of a node.
+// Signals of a TPDO are produced by the node, signals of an RPDO are consumed by it.
+type PDODefinition struct {
+	NodeId   uint8
+	Transmit bool   // TPDO if true, RPDO otherwise
+	Number   uint16 // 1-based, e.g. 1 for TPDO1
+	CobId    uint16
+	Signals  []Signal
+}
+
+func (def PDODefinition) String() string {
+	kind := "RPDO"
+	if def.Transmit {
+		kind = "TPDO"
+	}
+	return fmt.Sprintf("node x%x %v%d (x%x)", def.NodeId, kind, def.Number, def.CobId)
+}
+
+// A decoded signal value. Value is a uint64, int64, float64 or string as
+// given by [od.DecodeToType], or nil if the data type is unknown.
+type SignalValue struct {
+	Signal
+	Raw   []byte
+	Value any
+}
+
+// PDOEvent is emitted for every PDO frame registered in a [PDODatabase]
+type PDOEvent struct {
+	PDO    PDODefinition
+	Values []SignalValue
+	Time   time.Time
+}
+
+type PDOCallback func(event PDOEvent)
+
+// PDODatabase holds the PDO configurations of the nodes of a network,
+// for decoding PDO frames into signal values, much like a DBC file.
+// When a COB-ID is both a TPDO & an RPDO, frames are decoded with the TPDO,
+// i.e. with the objects of the producer, see [PDODatabase.SetNode].
+type PDODatabase struct {
+	mu        sync.Mutex
+	nodes     map[uint8][]PDODefinition
+	cobIds    map[uint16]PDODefinition
+	callbacks []PDOCallback
+}
+
+// Create an empty [PDODatabase]
+func NewPDODatabase() *PDODatabase {
+	return &PDODatabase{
+		nodes:  make(map[uint8][]PDODefinition),
+		cobIds: make(map[uint16]PDODefinition),
+	}
+}
+
+// Set the PDOs of a node, replacing any previous ones.
+// Two TPDOs can not use the same COB-ID, as they would be sent by two producers.
+func (db *PDODatabase) SetNode(nodeId uint8, defs []PDODefinition) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	conflict := func(def PDODefinition, other PDODefinition) bool {
+		return def.Transmit && other.Transmit && def.CobId == other.CobId
+	}
+	for i, def := range defs {
+		for _, other := range defs[i+1:] {
+			if conflict(def, other) {
+				return fmt.Errorf("%v and %v : %w", def, other, ErrPDOConflict)
+			}
+		}
+		for id, others := range db.nodes {
+			if id == nodeId {
+				continue
+			}
+			for _, other := range others {
+				if conflict(def, other) {
+					return fmt.Errorf("%v and %v : %w", def, other, ErrPDOConflict)
+				}
+			}
+		}
+	}
+	db.nodes[nodeId] = slices.Clone(defs)
+	db.rebuild()
+	return nil
+}
+
+// Remove the PDOs of a node
+func (db *PDODatabase) RemoveNode(nodeId uint8) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	delete(db.nodes, nodeId)
+	db.rebuild()
+}
+
+// Rebuild COB-ID lookup. TPDOs take precedence over RPDOs, and RPDOs
+// consumed by several nodes are decoded with the lowest node id.
+func (db *PDODatabase) rebuild() {
+	db.cobIds = make(map[uint16]PDODefinition)
+	nodeIds := make([]uint8, 0, len(db.nodes))
+	for nodeId := range db.nodes {
+		nodeIds = append(nodeIds, nodeId)
+	}
+	slices.Sort(nodeIds)
+	for _, nodeId := range nodeIds {
+		for _, def := range db.nodes[nodeId] {
+			if existing, ok := db.cobIds[def.CobId]; ok && (existing.Transmit || !def.Transmit) {
+				continue
+			}
+			db.cobIds[def.CobId] = def
+		}
+	}
+}
+
+// PDOs of a node
+func (db *PDODatabase) Node(nodeId uint8) []PDODefinition {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return slices.Clone(db.nodes[nodeId])
+}
+
+// Lookup the PDO used for decoding frames with a given COB-ID
+func (db *PDODatabase) Lookup(cobId uint16) (PDODefinition, bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	def, ok := db.cobIds[cobId]
+	return def, ok
+}
+
+// All the COB-IDs that can be decoded, in increasing order
+func (db *PDODatabase) CobIds() []uint16 {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	cobIds := make([]uint16, 0, len(db.cobIds))
+	for cobId := range db.cobIds {
+		cobIds = append(cobIds, cobId)
+	}
+	slices.Sort(cobIds)
+	return cobIds
+}
+
+// Add a callback that is called for every decoded PDO, see [Network.PDODatabase].
+// Callbacks are called from the CAN reception and should not block.
+func (db *PDODatabase) OnPDO(callback PDOCallback) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.callbacks = append(db.callbacks, callback)
+}
+
+// Decode a PDO frame into signal values
+func (db *PDODatabase) Decode(frame canopen.Frame) (PDOEvent, error) {
+	def, ok := db.Lookup(uint16(frame.ID & canopen.CanSffMask))
+	if !ok {
+		return PDOEvent{}, ErrPDOUnknown
+	}
+	data := frame.Data[:min(frame.DLC, 8)]
+	event := PDOEvent{PDO: def, Values: make([]SignalValue, 0, len(def.Signals)), Time: time.Now()}
+	for _, signal := range def.Signals {
+		if signal.Offset+signal.Length > len(data)*8 {
+			return event, ErrPDOLength
+		}
+		raw := extractBits(data, signal.Offset, signal.Length)
+		value := SignalValue{Signal: signal, Raw: raw}
+		if signal.DataType != 0 {
+			decoded, err := od.DecodeToType(raw, signal.DataType)
+			if err == nil {
+				value.Value = decoded
+			}
+		}
+		event.Values = append(event.Values, value)
+	}
+	return event, nil
+}
+
+// Handle PDO frames, decoded PDOs are sent to callbacks
+func (db *PDODatabase) Handle(frame canopen.Frame) {
+	event, err := db.Decode(frame)
+	if err != nil {
+		return
+	}
+	db.mu.Lock()
+	callbacks := slices.Clone(db.callbacks)
+	db.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(event)
+	}
+}
+
+// Extract length bits starting from offset (little endian), into a byte slice
+func extractBits(data []byte, offset int, length int) []byte {
+	if offset%8 == 0 && length%8 == 0 {
+		return slices.Clone(data[offset/8 : (offset+length)/8])
+	}
+	out := make([]byte, (length+7)/8)
+	for i := range length {
+		bit := (data[(offset+i)/8] >> ((offset + i) % 8)) & 1
+		out[i/8] |= bit << (i % 8)
+	}
+	return out
+}
+
+// Create the signals of a mapping. The OD is used for names & data types, it can be nil.
+func pdoSignals(nodeId uint8, odict *od.ObjectDictionary, mappings []uint32) []Signal {
+	signals := make([]Signal, 0, len(mappings))
+	offset := 0
+	for _, mapping := range mappings {
+		index := uint16(mapping >> 16)
+		subindex := uint8(mapping >> 8)
+		length := int(mapping & 0xFF)
+		if index > pdoDummyIndexMax {
+			signal := Signal{
+				NodeId:   nodeId,
+				Name:     fmt.Sprintf("x%x|x%x", index, subindex),
+				Index:    index,
+				Subindex: subindex,
+				Offset:   offset,
+				Length:   length,
+			}
+			if odict != nil {
+				entry := odict.Index(index)
+				if variable, err := entry.SubIndex(int(subindex)); err == nil {
+					signal.DataType = variable.DataType
+					signal.Name = entry.Name
+					if entry.ObjectType == od.ObjectTypeARRAY || entry.ObjectType == od.ObjectTypeRECORD {
+						signal.Name += "." + variable.Name
+					}
+				}
+			}
+			signals = append(signals, signal)
+		}
+		offset += length
+	}
+	return signals
+}
+
+// Read the PDOs of a node from an OD, e.g. loaded from an EDS or a DCF.
+// Disabled PDOs are ignored.
+func PDODefinitionsFromOD(nodeId uint8, odict *od.ObjectDictionary) []PDODefinition {
+	defs := make([]PDODefinition, 0)
+	for pdoNb := pdo.MinPdoNumber; pdoNb <= pdo.MaxPdoNumber; pdoNb++ {
+		def := PDODefinition{NodeId: nodeId, Transmit: pdoNb > pdo.MaxRpdoNumber, Number: pdoNb}
+		commIndex := od.EntryRPDOCommunicationStart + pdoNb - 1
+		mapIndex := od.EntryRPDOMappingStart + pdoNb - 1
+		if def.Transmit {
+			def.Number = pdoNb - pdo.MaxRpdoNumber
+			commIndex = od.EntryTPDOCommunicationStart + def.Number - 1
+			mapIndex = od.EntryTPDOMappingStart + def.Number - 1
+		}
+		comm := odict.Index(commIndex)
+		mapping := odict.Index(mapIndex)
+		if comm == nil || mapping == nil {
+			continue
+		}
+		cobId, err := comm.Uint32(1)
+		if err != nil || cobId&(1<<31) != 0 {
+			continue
+		}
+		nbMappings, err := mapping.Uint8(0)
+		if err != nil {
+			continue
+		}
+		mappings := make([]uint32, 0, nbMappings)
+		for i := uint8(1); i <= nbMappings; i++ {
+			raw, err := mapping.Uint32(i)
+			if err != nil {
+				break
+			}
+			mappings = append(mappings, raw)
+		}
+		def.CobId = uint16(cobId & 0x7FF)
+		// OD holds the predefined COB-ID without node id, as done by the PDO objects
+		base := uint16(0x200)
+		if def.Transmit {
+			base = 0x180
+		}
+		predefined := base + (def.Number-1)%4*0x100 + uint16(nodeId) + (def.Number-1)/4
+		if def.CobId == predefined&0xFF80 {
+			def.CobId = predefined
+		}
+		def.Signals = pdoSignals(nodeId, odict, mappings)
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+// PDODatabase returns the PDO database of the network. PDOs registered with
+// [Network.RegisterPDOs], [Network.ReadPDOs] or [Network.SetPDOs] are decoded
+// when received and sent to the database callbacks, see [PDODatabase.OnPDO].
+func (network *Network) PDODatabase() *PDODatabase {
+	network.pdoMu.Lock()
+	defer network.pdoMu.Unlock()
+	if network.pdos == nil {
+		network.pdos = NewPDODatabase()
+	}
+	return network.pdos
+}
+
+// SetPDOs sets the PDOs of a node in the database & starts decoding them
+func (network *Network) SetPDOs(nodeId uint8, defs []PDODefinition) error {
+	db := network.PDODatabase()
+	err := db.SetNode(nodeId, defs)
+	if err != nil {
+		return err
+	}
+	for _, def := range defs {
+		// Already subscribed COB-IDs are ignored by the bus manager
+		err = network.Subscribe(uint32(def.CobId), 0x7FF, false, db)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterPDOs registers the PDOs of a node from its OD known to the network,
+// i.e. a local node or a remote node added with its EDS or DCF.
+func (network *Network) RegisterPDOs(nodeId uint8) ([]PDODefinition, error) {
+	odict, err := network.GetOD(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	defs := PDODefinitionsFromOD(nodeId, odict)
+	return defs, network.SetPDOs(nodeId, defs)
+}
+
+// ReadPDOs reads the current PDO configuration from the node via SDO & registers it.
+// If the OD of the node is known to the network, it is used for signal names & data types.
+func (network *Network) ReadPDOs(nodeId uint8) ([]PDODefinition, error) {
+	odict, _ := network.GetOD(nodeId)
+	configurator := network.Configurator(nodeId)
+	defs := make([]PDODefinition, 0)
+	for _, bounds := range [][2]uint16{{pdo.MinRpdoNumber, pdo.MaxRpdoNumber}, {pdo.MinTpdoNumber, pdo.MaxTpdoNumber}} {
+		for pdoNb := bounds[0]; pdoNb <= bounds[1]; pdoNb++ {
+			cobId, err := configurator.ReadCobIdPDO(pdoNb)
+			if errors.Is(err, sdo.AbortNotExist) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if cobId&(1<<31) != 0 {
+				continue
+			}
+			mappings, err := configurator.ReadMappings(pdoNb)
+			if err != nil {
+				return nil, err
+			}
+			raw := make([]uint32, 0, len(mappings))
+			for _, mapping := range mappings {
+				raw = append(raw, uint32(mapping.Index)<<16|uint32(mapping.Subindex)<<8|uint32(mapping.LengthBits))
+			}
+			def := PDODefinition{NodeId: nodeId, Transmit: pdoNb > pdo.MaxRpdoNumber, Number: pdoNb, CobId: uint16(cobId & 0x7FF)}
+			if def.Transmit {
+				def.Number -= pdo.MaxRpdoNumber
+			}
+			def.Signals = pdoSignals(nodeId, odict, raw)
+			defs = append(defs, def)
+		}
+	}
+	return defs, network.SetPDOs(nodeId, defs)
+}
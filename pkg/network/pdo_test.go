@@ -4,6 +4,9 @@ import (
 	"testing"
 	"time"
 
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/emergency"
 	"github.com/samsamfire/gocanopen/pkg/od"
 	"github.com/samsamfire/gocanopen/pkg/pdo"
 	"github.com/stretchr/testify/assert"
@@ -55,3 +58,259 @@ func TestTpdo(t *testing.T) {
 
 	})
 }
+
+func TestPDOConfiguration(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	config := local.Configurator()
+	err = config.DisablePDO(pdo.MinTpdoNumber)
+	assert.Nil(t, err)
+	err = config.WriteEventTimer(pdo.MinTpdoNumber, 1234)
+	assert.Nil(t, err)
+
+	rpdos, tpdos, err := local.PDOConfiguration()
+	assert.Nil(t, err)
+	rpdosSdo, tpdosSdo, err := config.ReadConfigurationAllPDO()
+	assert.Nil(t, err)
+	assert.Len(t, rpdos, len(rpdosSdo))
+	assert.Len(t, tpdos, len(tpdosSdo))
+	for i, tpdo := range tpdos {
+		assert.Equal(t, tpdosSdo[i], tpdo.PDOConfigurationParameter)
+		assert.Len(t, tpdo.Entries, len(tpdo.Mappings))
+	}
+	assert.EqualValues(t, 1234, tpdos[0].EventTimer)
+	assert.False(t, tpdos[0].Enabled)
+	assert.Equal(t, pdo.MinTpdoNumber, tpdos[0].Nb)
+	for _, entry := range tpdos[0].Entries {
+		assert.NotEmpty(t, entry.Name)
+	}
+}
+
+func TestRPDOErrors(t *testing.T) {
+	sim := NewSimulation()
+	defer sim.Close()
+	master, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	devices, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	local, err := devices.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+	err = local.Configurator().WriteConfigurationPDO(pdo.MinRpdoNumber, config.PDOConfigurationParameter{
+		CanId:            0x210,
+		TransmissionType: 0xFE,
+		EventTimer:       100,
+		Mappings: []config.PDOMappingParameter{
+			{Index: 0x2006, Subindex: 0, LengthBits: 16},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, local.Configurator().EnablePDO(pdo.MinRpdoNumber))
+	events := []pdo.RPDOEvent{}
+	local.SetRPDOEventHook(func(event pdo.RPDOEvent) {
+		events = append(events, event)
+	})
+	send := func(dlc uint8) {
+		frame := canopen.NewFrame(0x210, 0, dlc)
+		frame.Data = [8]byte{0x34, 0x12}
+		assert.Nil(t, master.Send(frame))
+		// RPDOs are processed in background, EMCYs on next main processing
+		sim.Advance(20 * time.Millisecond)
+	}
+	emergencyCodes := func() []uint16 {
+		codes := []uint16{}
+		for _, emcy := range master.Emergencies(0x10) {
+			codes = append(codes, emcy.ErrorCode)
+		}
+		return codes
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		send(2)
+		value, err := local.ReadUint(0x2006, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x1234, value)
+		sim.Advance(60 * time.Millisecond)
+		assert.Len(t, events, 0)
+		sim.Advance(40 * time.Millisecond)
+		assert.Equal(t, []pdo.RPDOEvent{{Type: pdo.RPDOTimeout, CobId: 0x210, ErrorCode: emergency.ErrRpdoTimeout}}, events)
+		send(2)
+		assert.Equal(t, pdo.RPDOEvent{Type: pdo.RPDORecovered, CobId: 0x210}, events[1])
+		assert.Equal(t, []uint16{emergency.ErrRpdoTimeout, emergency.ErrNoError}, emergencyCodes())
+	})
+
+	t.Run("length errors", func(t *testing.T) {
+		events = events[:0]
+		send(1)
+		assert.Equal(t, []pdo.RPDOEvent{{Type: pdo.RPDOLengthShort, CobId: 0x210, ErrorCode: emergency.ErrPdoLength}}, events)
+		send(2)
+		assert.Equal(t, pdo.RPDOEvent{Type: pdo.RPDOLengthOk, CobId: 0x210}, events[1])
+		send(3)
+		assert.Equal(t, pdo.RPDOEvent{Type: pdo.RPDOLengthLong, CobId: 0x210, ErrorCode: emergency.ErrPdoLengthExc}, events[2])
+		assert.Equal(t, []uint16{emergency.ErrPdoLength, emergency.ErrNoError, emergency.ErrPdoLengthExc}, emergencyCodes()[2:])
+		assert.Len(t, events, 3)
+	})
+}
+
+func TestTPDOTrigger(t *testing.T) {
+	sim := NewSimulation()
+	defer sim.Close()
+	master, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	devices, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	local, err := devices.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+	err = local.Configurator().WriteConfigurationPDO(pdo.MinTpdoNumber, config.PDOConfigurationParameter{
+		CanId:            0x190,
+		TransmissionType: 0xFE,
+		Mappings: []config.PDOMappingParameter{
+			{Index: 0x2006, Subindex: 0, LengthBits: 16},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, local.Configurator().EnablePDO(pdo.MinTpdoNumber))
+	sent := 0
+	local.TPDOs[0].SetSendHook(func(frame canopen.Frame) {
+		sent++
+	})
+	// Number of TPDOs sent since last call, SDO transfers also process the simulation
+	process := func() int {
+		sim.Advance(10 * time.Millisecond)
+		count := sent
+		sent = 0
+		return count
+	}
+	// Initial transmission after configuration
+	process()
+	assert.Equal(t, 0, process())
+
+	t.Run("application request", func(t *testing.T) {
+		assert.Nil(t, local.TriggerTPDO(pdo.MinTpdoNumber))
+		assert.Equal(t, 1, process())
+		assert.Equal(t, canopen.ErrIllegalArgument, local.TriggerTPDO(pdo.MinRpdoNumber))
+		assert.Equal(t, canopen.ErrIllegalArgument, local.TriggerTPDO(pdo.MaxTpdoNumber))
+		local.GetOD().Index(0x2006).RequestTPDO(0)
+		assert.Equal(t, 1, process())
+	})
+
+	t.Run("on change", func(t *testing.T) {
+		assert.Nil(t, master.WriteRaw(0x10, 0x2006, 0, uint16(5), false))
+		assert.Equal(t, 0, process())
+		assert.Nil(t, local.SetTPDOOnChange(0x2006, 0, true))
+		assert.Nil(t, master.WriteRaw(0x10, 0x2006, 0, uint16(6), false))
+		assert.Equal(t, 1, process())
+		assert.Nil(t, master.WriteRaw(0x10, 0x2006, 0, uint16(6), false))
+		assert.Equal(t, 0, process())
+		assert.Nil(t, local.SetTPDOOnChange(0x2006, 0, false))
+		assert.Nil(t, master.WriteRaw(0x10, 0x2006, 0, uint16(7), false))
+		assert.Equal(t, 0, process())
+		assert.Equal(t, od.ErrSubNotExist, local.SetTPDOOnChange(0x2006, 1, true))
+	})
+}
+
+func TestPDODatabase(t *testing.T) {
+	networkLocal := CreateNetworkEmptyTest()
+	networkMaster := CreateNetworkEmptyTest()
+	defer networkLocal.Disconnect()
+	defer networkMaster.Disconnect()
+
+	local, err := networkLocal.CreateLocalNode(0x52, od.Default())
+	assert.Nil(t, err)
+	err = local.Configurator().WriteConfigurationPDO(pdo.MinTpdoNumber, config.PDOConfigurationParameter{
+		CanId:            0x1D2,
+		TransmissionType: 0xFE,
+		EventTimer:       20,
+		Mappings: []config.PDOMappingParameter{
+			{Index: 0x2002, Subindex: 0, LengthBits: 8},
+			{Index: 0x2006, Subindex: 0, LengthBits: 16},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, local.Configurator().EnablePDO(pdo.MinTpdoNumber))
+	assert.Nil(t, local.Write(0x2002, 0, int8(-3)))
+
+	t.Run("read from device & decode", func(t *testing.T) {
+		_, err := networkMaster.AddRemoteNode(0x52, od.Default())
+		assert.Nil(t, err)
+		defs, err := networkMaster.ReadPDOs(0x52)
+		assert.Nil(t, err)
+		var tpdo PDODefinition
+		for _, def := range defs {
+			if def.Transmit && def.Number == 1 {
+				tpdo = def
+			}
+		}
+		assert.EqualValues(t, 0x1D2, tpdo.CobId)
+		assert.Len(t, tpdo.Signals, 2)
+		assert.Equal(t, "INTEGER8 value", tpdo.Signals[0].Name)
+		assert.Equal(t, Signal{NodeId: 0x52, Name: "UNSIGNED16 value", Index: 0x2006, DataType: od.UNSIGNED16, Offset: 8, Length: 16}, tpdo.Signals[1])
+
+		events := make(chan PDOEvent, 10)
+		networkMaster.PDODatabase().OnPDO(func(event PDOEvent) {
+			if event.PDO.CobId == 0x1D2 {
+				select {
+				case events <- event:
+				default:
+				}
+			}
+		})
+		select {
+		case event := <-events:
+			assert.Equal(t, tpdo, event.PDO)
+			assert.Len(t, event.Values, 2)
+			assert.EqualValues(t, int64(-3), event.Values[0].Value)
+			assert.EqualValues(t, uint64(0x1111), event.Values[1].Value)
+		case <-time.After(time.Second):
+			t.Fatal("no PDO decoded")
+		}
+	})
+
+	t.Run("register from OD", func(t *testing.T) {
+		defs, err := networkLocal.RegisterPDOs(0x52)
+		assert.Nil(t, err)
+		def, ok := networkLocal.PDODatabase().Lookup(0x1D2)
+		assert.True(t, ok)
+		assert.True(t, def.Transmit)
+		assert.Contains(t, defs, def)
+		_, err = networkLocal.RegisterPDOs(0x60)
+		assert.Equal(t, od.ErrOdMissing, err)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		db := NewPDODatabase()
+		err := db.SetNode(0x10, []PDODefinition{{NodeId: 0x10, Transmit: true, Number: 1, CobId: 0x190, Signals: []Signal{
+			{NodeId: 0x10, Name: "flag", Index: 0x2001, DataType: od.BOOLEAN, Offset: 0, Length: 1},
+			{NodeId: 0x10, Name: "raw", Index: 0x2100, Offset: 1, Length: 7},
+			{NodeId: 0x10, Name: "value", Index: 0x2003, DataType: od.INTEGER16, Offset: 8, Length: 16},
+		}}})
+		assert.Nil(t, err)
+		event, err := db.Decode(canopen.NewFrame(0x190, 0, 3))
+		assert.Nil(t, err)
+		assert.Len(t, event.Values, 3)
+		frame := canopen.NewFrame(0x190, 0, 3)
+		frame.Data = [8]byte{0x05, 0xFE, 0xFF}
+		event, err = db.Decode(frame)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, event.Values[0].Value)
+		assert.Equal(t, []byte{0x02}, event.Values[1].Raw)
+		assert.Nil(t, event.Values[1].Value)
+		assert.EqualValues(t, -2, event.Values[2].Value)
+		_, err = db.Decode(canopen.NewFrame(0x190, 0, 2))
+		assert.Equal(t, ErrPDOLength, err)
+		_, err = db.Decode(canopen.NewFrame(0x191, 0, 3))
+		assert.Equal(t, ErrPDOUnknown, err)
+		// Only a single producer per COB-ID, RPDOs can share it
+		err = db.SetNode(0x11, []PDODefinition{{NodeId: 0x11, Transmit: true, Number: 1, CobId: 0x190}})
+		assert.ErrorIs(t, err, ErrPDOConflict)
+		err = db.SetNode(0x11, []PDODefinition{{NodeId: 0x11, Number: 1, CobId: 0x190}})
+		assert.Nil(t, err)
+		def, _ := db.Lookup(0x190)
+		assert.EqualValues(t, 0x10, def.NodeId)
+		db.RemoveNode(0x10)
+		def, _ = db.Lookup(0x190)
+		assert.EqualValues(t, 0x11, def.NodeId)
+		assert.Equal(t, []uint16{0x190}, db.CobIds())
+	})
+}
@@ -0,0 +1,46 @@
+package network
+
+import (
+	canopen "github.com/samsamfire/gocanopen"
+)
+
+// Handler of frames received with [Network.SubscribeRaw]
+type RawFrameHandler func(frame canopen.Frame)
+
+type rawSubscription struct {
+	handler RawFrameHandler
+}
+
+func (s *rawSubscription) Handle(frame canopen.Frame) {
+	s.handler(frame)
+}
+
+// SendRaw sends a frame which is not handled by the CANopen stack, e.g. for devices
+// using a custom protocol on the same bus. id is an 11 bit CAN ID and data at most 8 bytes.
+func (network *Network) SendRaw(id uint32, data []byte) error {
+	if !network.connected.Load() {
+		return ErrNotConnected
+	}
+	if id > canopen.CanSffMask || len(data) > 8 {
+		return canopen.ErrIllegalArgument
+	}
+	frame := canopen.NewFrame(id, 0, uint8(len(data)))
+	copy(frame.Data[:], data)
+	return network.Send(frame)
+}
+
+// SubscribeRaw calls handler with the received data frames matching id on the bits set in mask,
+// e.g. 0x7FF for a single CAN ID, see [canopen.BusManager.Subscribe]. CANopen frames can also be received.
+// handler is called from the receive goroutine and should not block.
+// The returned function removes the subscription.
+func (network *Network) SubscribeRaw(id uint32, mask uint32, handler RawFrameHandler) (func(), error) {
+	if handler == nil {
+		return nil, canopen.ErrIllegalArgument
+	}
+	subscription := &rawSubscription{handler: handler}
+	err := network.Subscribe(id, mask, false, subscription)
+	if err != nil {
+		return nil, err
+	}
+	return func() { network.Unsubscribe(subscription) }, nil
+}
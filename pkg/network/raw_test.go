@@ -0,0 +1,41 @@
+package network
+
+import (
+	"testing"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawFrames(t *testing.T) {
+	sim := NewSimulation()
+	defer sim.Close()
+	a, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	b, err := sim.NewNetwork()
+	assert.Nil(t, err)
+
+	received := []canopen.Frame{}
+	unsubscribe, err := b.SubscribeRaw(0x7E0, 0x7F0, func(frame canopen.Frame) {
+		received = append(received, frame)
+	})
+	assert.Nil(t, err)
+	_, err = b.SubscribeRaw(0x7E0, 0x7F0, nil)
+	assert.Equal(t, canopen.ErrIllegalArgument, err)
+
+	assert.Nil(t, a.SendRaw(0x7E5, []byte{1, 2, 3}))
+	assert.Nil(t, a.SendRaw(0x7D0, []byte{4}))
+	assert.Equal(t, canopen.ErrIllegalArgument, a.SendRaw(0x800, nil))
+	assert.Equal(t, canopen.ErrIllegalArgument, a.SendRaw(0x7E0, make([]byte, 9)))
+	assert.Len(t, received, 1)
+	assert.EqualValues(t, 0x7E5, received[0].ID)
+	assert.EqualValues(t, 3, received[0].DLC)
+	assert.Equal(t, []byte{1, 2, 3}, received[0].Data[:3])
+
+	unsubscribe()
+	assert.Nil(t, a.SendRaw(0x7E5, nil))
+	assert.Len(t, received, 1)
+
+	a.Disconnect()
+	assert.Equal(t, ErrNotConnected, a.SendRaw(0x7E5, nil))
+}
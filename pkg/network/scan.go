@@ -0,0 +1,143 @@
+package network
+
+import (
+	"context"
+	"sync"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// NodeInformation contains manufacturer information and identity object
+type NodeInformation struct {
+	config.ManufacturerInformation
+	config.Identity
+	// Last NMT state known from heartbeats, nmt.StateUnknown if none
+	NmtState uint8
+}
+
+// ScanProgress is given to [ScanOptions.Progress] after each scanned node
+type ScanProgress struct {
+	NodeId uint8
+	Found  bool
+	Done   int // Number of nodes scanned so far
+	Total  int // Number of nodes to scan
+}
+
+// ScanOptions configures [Network.ScanWith]
+type ScanOptions struct {
+	// SDO timeout used for detecting a node, defaults to [sdo.DefaultClientTimeout]
+	TimeoutMs uint32
+	// Node ids to scan, defaults to all node ids
+	NodeIds []uint8
+	// Only read the vendor id (0x1018:1) used for detecting the node,
+	// instead of the whole identity object
+	SkipIdentity bool
+	// Don't read manufacturer device name, hardware & software versions (0x1008 - 0x100A)
+	SkipManufacturer bool
+	// Maximum number of nodes scanned at the same time, 0 for no limit.
+	// This limits the bus load created by the scan.
+	MaxConcurrent int
+	// Called after each scanned node. Calls are serialized.
+	Progress func(progress ScanProgress)
+}
+
+// ScanWith scans the network for nodes via SDO like [Network.Scan], with
+// the given options. Nodes are detected by reading their vendor id (0x1018:1),
+// then the rest of the inventory is read. Cancelling ctx stops the scan of the
+// nodes not yet started, and the nodes found so far are returned with ctx error.
+func (network *Network) ScanWith(ctx context.Context, opts ScanOptions) (map[uint8]NodeInformation, error) {
+	if opts.TimeoutMs == 0 {
+		opts.TimeoutMs = sdo.DefaultClientTimeout
+	}
+	nodeIds := opts.NodeIds
+	if nodeIds == nil {
+		for id := nodeIdMin; id <= nodeIdMax; id++ {
+			nodeIds = append(nodeIds, id)
+		}
+	}
+	for _, id := range nodeIds {
+		if id < nodeIdMin || id > nodeIdMax {
+			return nil, ErrIdRange
+		}
+	}
+	// Create multiple sdo clients to speed up discovery
+	clients := make([]*sdo.SDOClient, 0, len(nodeIds))
+	for _, id := range nodeIds {
+		client, err := sdo.NewSDOClient(network.BusManager, network.logger, nil, id, opts.TimeoutMs, nil)
+		if err != nil {
+			return nil, err
+		}
+		client.SetTimeout(opts.TimeoutMs)
+		clients = append(clients, client)
+	}
+	var limit chan struct{}
+	if opts.MaxConcurrent > 0 {
+		limit = make(chan struct{}, opts.MaxConcurrent)
+	}
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	scan := make(map[uint8]NodeInformation)
+	done := 0
+	// Scanning is done in parallel to speed up discovery
+	// As the limiting factor is the SDO round-trip time which
+	// can take up to timeoutMs to complete
+	for i, client := range clients {
+		if limit != nil {
+			select {
+			case limit <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		nodeId := nodeIds[i]
+		wg.Add(1)
+		go func(client *sdo.SDOClient) {
+			defer wg.Done()
+			info, found := network.scanNode(nodeId, client, opts)
+			if limit != nil {
+				<-limit
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if found {
+				scan[nodeId] = info
+			}
+			done++
+			if opts.Progress != nil {
+				opts.Progress(ScanProgress{NodeId: nodeId, Found: found, Done: done, Total: len(nodeIds)})
+			}
+		}(client)
+	}
+	wg.Wait()
+	return scan, ctx.Err()
+}
+
+func (network *Network) scanNode(nodeId uint8, client *sdo.SDOClient, opts ScanOptions) (NodeInformation, bool) {
+	info := NodeInformation{}
+	conf := config.NewNodeConfigurator(nodeId, network.logger, client)
+	if opts.SkipIdentity {
+		vendorId, err := client.ReadUint32(nodeId, od.EntryIdentityObject, 1)
+		if err != nil {
+			return info, false
+		}
+		info.VendorId = vendorId
+	} else {
+		identity, err := conf.ReadIdentity()
+		if err != nil {
+			// Failure to respond to ReadIdentity means node doesn't exist
+			// Or that it does not implement a mandatory object so it will
+			// be considered as not found
+			return info, false
+		}
+		info.Identity = *identity
+	}
+	if !opts.SkipManufacturer {
+		info.ManufacturerInformation = conf.ReadManufacturerInformation()
+	}
+	info.NmtState = network.NodeState(nodeId)
+	return info, true
+}
@@ -0,0 +1,84 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// Pool of SDO clients, one per remote node id. Each client communicates with
+// the default SDO server channel of its node (0x600 + id / 0x580 + id), so that
+// transfers to different nodes can run in parallel.
+type sdoClientPool struct {
+	mu      sync.Mutex
+	clients map[uint8]*pooledClient
+	hook    func(result sdo.TransferResult) // See [Network.OnSDOTransfer]
+	stepper func(d time.Duration)           // See [Simulation]
+}
+
+type pooledClient struct {
+	mu     sync.Mutex // Held during [Network.WithSDOClient]
+	client *sdo.SDOClient
+}
+
+// Get the pooled client of a node, it is created on first use
+func (network *Network) pooledClient(nodeId uint8) (*pooledClient, error) {
+	if nodeId < nodeIdMin || nodeId > nodeIdMax {
+		return nil, ErrIdRange
+	}
+	pool := &network.sdoPool
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pooled, ok := pool.clients[nodeId]
+	if ok {
+		return pooled, nil
+	}
+	client, err := sdo.NewSDOClient(network.BusManager, network.logger, nil, 0, sdo.DefaultClientTimeout, nil)
+	if err != nil {
+		return nil, err
+	}
+	if pool.clients == nil {
+		pool.clients = make(map[uint8]*pooledClient)
+	}
+	client.SetTransferHook(pool.hook)
+	client.SetStepper(pool.stepper)
+	pooled = &pooledClient{client: client}
+	pool.clients[nodeId] = pooled
+	return pooled, nil
+}
+
+// All clients of the pool, by node id
+func (pool *sdoClientPool) all() map[uint8]*sdo.SDOClient {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	clients := make(map[uint8]*sdo.SDOClient, len(pool.clients))
+	for id, pooled := range pool.clients {
+		clients[id] = pooled.client
+	}
+	return clients
+}
+
+// SDOClientFor returns the SDO client dedicated to the given node, from the network's
+// client pool. Transfers made with clients of different nodes run in parallel, whereas
+// concurrent transfers on the same client are queued, see [sdo.Transfer].
+func (network *Network) SDOClientFor(nodeId uint8) (*sdo.SDOClient, error) {
+	pooled, err := network.pooledClient(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	return pooled.client, nil
+}
+
+// WithSDOClient calls fn with the SDO client dedicated to the given node.
+// Calls for the same node are serialized, calls for different nodes run in parallel.
+// This can be used for making several transfers to a node without interleaving.
+func (network *Network) WithSDOClient(nodeId uint8, fn func(client *sdo.SDOClient) error) error {
+	pooled, err := network.pooledClient(nodeId)
+	if err != nil {
+		return err
+	}
+	pooled.mu.Lock()
+	defer pooled.mu.Unlock()
+	return fn(pooled.client)
+}
@@ -1,10 +1,22 @@
 package network
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
 	"io"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/can/cantest"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -48,6 +60,194 @@ func TestReaderWriter(t *testing.T) {
 	assert.Equal(t, 2, n)
 }
 
+func TestReadWriteCtx(t *testing.T) {
+	network := CreateNetworkTest()
+	network2 := CreateNetworkEmptyTest()
+	defer network2.Disconnect()
+	defer network.Disconnect()
+	client := network2.SDOClient
+
+	t.Run("timeout on missing node", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := client.ReadRawCtx(ctx, NodeIdTest+1, 0x2001, 0, make([]byte, 1))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		err = client.WriteRawCtx(ctx, NodeIdTest+1, 0x2001, 0, uint8(1), false)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("cancel block transfer", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			cancel()
+		}()
+		_, err := client.ReadAllCtx(ctx, NodeIdTest, 0x1021, 0)
+		assert.ErrorIs(t, err, context.Canceled)
+		// Next transfers are not affected
+		eds, err := client.ReadAllCtx(context.Background(), NodeIdTest, 0x1021, 0)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, eds)
+		_, err = client.ReadUint8(NodeIdTest, 0x2001, 0)
+		assert.Nil(t, err)
+	})
+
+	t.Run("read after timeout", func(t *testing.T) {
+		_, err := client.ReadUint8(NodeIdTest+1, 0x2001, 0)
+		assert.ErrorIs(t, err, sdo.AbortTimeout)
+		_, err = client.ReadUint8(NodeIdTest, 0x2001, 0)
+		assert.Nil(t, err)
+	})
+
+	t.Run("abort context", func(t *testing.T) {
+		_, err := client.ReadAll(NodeIdTest, 0x2001, 5)
+		var trErr *sdo.TransferError
+		assert.ErrorAs(t, err, &trErr)
+		assert.Equal(t, sdo.TransferError{
+			Abort: sdo.AbortSubUnknown, NodeId: NodeIdTest, Index: 0x2001, Subindex: 5, Upload: true,
+		}, *trErr)
+		assert.Equal(t, "sdo upload node x30 x2001:05 failed after 0 bytes : x6090011 : Sub index does not exist", err.Error())
+
+		err = client.WriteRaw(NodeIdTest, 0x1000, 0, uint32(0), false)
+		assert.ErrorAs(t, err, &trErr)
+		assert.ErrorIs(t, err, sdo.AbortReadOnly)
+		assert.False(t, trErr.Upload)
+	})
+}
+
+func TestWriterReadFrom(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	odict := od.Default()
+	path := filepath.Join(t.TempDir(), "domain.bin")
+	odict.AddFile(0x3000, "domain", path, os.O_RDONLY, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+	_, err := network.CreateLocalNode(0x66, odict)
+	assert.Nil(t, err)
+	// Several sub-blocks, streamed with unknown size
+	data := make([]byte, 3000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	w, err := network.NewRawWriterWith(context.Background(), 0x66, 0x3000, 0, 0, sdo.TransferOptions{Block: sdo.BlockAuto})
+	assert.Nil(t, err)
+	n, err := w.ReadFrom(io.MultiReader(bytes.NewReader(data[:1000]), bytes.NewReader(data[1000:])))
+	assert.Nil(t, err)
+	assert.EqualValues(t, len(data), n)
+	written, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.True(t, bytes.Equal(data, written))
+}
+
+func TestBlockTransferOptions(t *testing.T) {
+	bus := cantest.NewMockBus(true)
+	network := NewNetwork(bus)
+	assert.Nil(t, network.Connect())
+	defer network.Disconnect()
+	client := network.SDOClient
+	opts := sdo.TransferOptions{Block: sdo.BlockAuto}
+
+	t.Run("fallback when block is not supported", func(t *testing.T) {
+		bus.Reset()
+		bus.Expect("block upload", cantest.MatchPrefix(0x610, 0xA4, 0x00, 0x20, 0x00)).
+			Respond(cantest.MustParseFrame("590#8000200001000405"))
+		bus.ExpectFrame(cantest.MustParseFrame("610#4000200000000000")).
+			Respond(cantest.MustParseFrame("590#4F002000AB000000"))
+		data, err := client.ReadAllWith(context.Background(), 0x10, 0x2000, 0, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0xAB}, data)
+		bus.AssertExpectations(t)
+		// Default threshold is sent as protocol switch threshold
+		assert.EqualValues(t, sdo.ClientProtocolSwitchThreshold, bus.Sent()[0].Data[5])
+		supported, known := client.BlockSupported(0x10)
+		assert.True(t, known)
+		assert.False(t, supported)
+	})
+
+	t.Run("known unsupported server", func(t *testing.T) {
+		bus.Reset()
+		bus.ExpectFrame(cantest.MustParseFrame("610#4000200000000000")).
+			Respond(cantest.MustParseFrame("590#4F002000AB000000"))
+		_, err := client.ReadAllWith(context.Background(), 0x10, 0x2000, 0, opts)
+		assert.Nil(t, err)
+		bus.AssertExpectations(t)
+		_, err = client.ReadAllWith(context.Background(), 0x10, 0x2000, 0, sdo.TransferOptions{Block: sdo.BlockAlways})
+		assert.ErrorIs(t, err, sdo.ErrBlockUnsupported)
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		bus.Reset()
+		client.ResetBlockSupport(0x10)
+		client.SetBlockThreshold(100)
+		defer client.SetBlockThreshold(sdo.ClientProtocolSwitchThreshold)
+		// Below threshold : segmented download
+		bus.Expect("segmented download", cantest.MatchPrefix(0x610, 0x21, 0x00, 0x20, 0x00, 50)).
+			Respond(cantest.MustParseFrame("590#8000200000000008"))
+		err := client.WriteRawWith(context.Background(), 0x10, 0x2000, 0, make([]byte, 50), opts)
+		assert.ErrorIs(t, err, sdo.AbortGeneral)
+		bus.AssertExpectations(t)
+		// Per call threshold : block download
+		bus.Reset()
+		bus.Expect("block download", cantest.MatchPrefix(0x610, 0xC6, 0x00, 0x20, 0x00, 50)).
+			Respond(cantest.MustParseFrame("590#8000200000000008"))
+		err = client.WriteRawWith(context.Background(), 0x10, 0x2000, 0, make([]byte, 50),
+			sdo.TransferOptions{Block: sdo.BlockAuto, BlockThreshold: 10})
+		assert.ErrorIs(t, err, sdo.AbortGeneral)
+		bus.AssertExpectations(t)
+		// Client threshold is sent as protocol switch threshold
+		bus.Reset()
+		bus.Expect("block upload", cantest.MatchPrefix(0x610, 0xA4, 0x00, 0x20, 0x00)).
+			Respond(cantest.MustParseFrame("590#8000200000000008"))
+		_, err = client.ReadAllWith(context.Background(), 0x10, 0x2000, 0, opts)
+		assert.ErrorIs(t, err, sdo.AbortGeneral)
+		bus.AssertExpectations(t)
+		assert.EqualValues(t, 100, bus.Sent()[0].Data[5])
+	})
+
+	t.Run("block size & protocol switch threshold", func(t *testing.T) {
+		bus.Reset()
+		client.ResetBlockSupport(0x10)
+		bus.Expect("block upload", cantest.MatchPrefix(0x610, 0xA4, 0x00, 0x20, 0x00)).
+			Respond(cantest.MustParseFrame("590#8000200000000008"))
+		_, err := client.ReadAllWith(context.Background(), 0x10, 0x2000, 0,
+			sdo.TransferOptions{Block: sdo.BlockAuto, BlockSize: 16, ProtocolSwitchThreshold: 42})
+		assert.ErrorIs(t, err, sdo.AbortGeneral)
+		bus.AssertExpectations(t)
+		assert.EqualValues(t, 16, bus.Sent()[0].Data[4])
+		assert.EqualValues(t, 42, bus.Sent()[0].Data[5])
+	})
+
+	t.Run("server changes block size", func(t *testing.T) {
+		bus.Reset()
+		always := sdo.TransferOptions{Block: sdo.BlockAlways}
+		bus.Expect("block download", cantest.MatchPrefix(0x610, 0xC6, 0x00, 0x20, 0x00, 20)).
+			Respond(cantest.MustParseFrame("590#A400200002000000"))
+		bus.Expect("segment 1", cantest.MatchPrefix(0x610, 0x01))
+		bus.Expect("segment 2", cantest.MatchPrefix(0x610, 0x02)).
+			Respond(cantest.MustParseFrame("590#A202010000000000"))
+		bus.Expect("last segment", cantest.MatchPrefix(0x610, 0x81)).
+			Respond(cantest.MustParseFrame("590#A201020000000000"))
+		bus.Expect("block end", cantest.MatchPrefix(0x610, 0xC5)).
+			Respond(cantest.MustParseFrame("590#A100000000000000"))
+		err := client.WriteRawWith(context.Background(), 0x10, 0x2000, 0, make([]byte, 20), always)
+		assert.Nil(t, err)
+		bus.AssertExpectations(t)
+
+		// Invalid block size aborts the transfer
+		bus.Reset()
+		bus.Expect("block download", cantest.MatchPrefix(0x610, 0xC6, 0x00, 0x20, 0x00, 20)).
+			Respond(cantest.MustParseFrame("590#A400200002000000"))
+		bus.Expect("segment 1", cantest.MatchPrefix(0x610, 0x01))
+		bus.Expect("segment 2", cantest.MatchPrefix(0x610, 0x02)).
+			Respond(cantest.MustParseFrame("590#A202000000000000"))
+		bus.Expect("abort", cantest.MatchPrefix(0x610, 0x80, 0x00, 0x20, 0x00, 0x02, 0x00, 0x04, 0x05))
+		err = client.WriteRawWith(context.Background(), 0x10, 0x2000, 0, make([]byte, 20), always)
+		assert.ErrorIs(t, err, sdo.AbortBlockSize)
+		bus.AssertExpectations(t)
+	})
+}
+
 func BenchmarkNodeStreamerWriter(b *testing.B) {
 	b.StopTimer()
 	network := CreateNetworkTest()
@@ -61,3 +261,530 @@ func BenchmarkNodeStreamerWriter(b *testing.B) {
 		assert.NotEqual(b, 0, value)
 	}
 }
+
+func TestServerExpeditedFastPath(t *testing.T) {
+	bus := cantest.NewMockBus(false)
+	network := NewNetwork(bus)
+	assert.Nil(t, network.Connect())
+	defer network.Disconnect()
+	local, err := network.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool {
+		state := local.NMT.GetInternalState()
+		return state == nmt.StatePreOperational || state == nmt.StateOperational
+	}, time.Second, 10*time.Millisecond)
+	// Let NMT state propagate to server
+	time.Sleep(50 * time.Millisecond)
+	local.SDOServers[0].SetExpeditedFastPath(true)
+
+	// Responses are sent before Inject returns
+	respond := func(request string) []string {
+		bus.Reset()
+		bus.Inject(cantest.MustParseFrame(request))
+		responses := []string{}
+		for _, frame := range bus.Sent() {
+			if frame.ID == 0x590 {
+				responses = append(responses, cantest.FormatFrame(frame))
+			}
+		}
+		return responses
+	}
+
+	t.Run("expedited upload", func(t *testing.T) {
+		assert.Equal(t, []string{"590#4F02200033000000"}, respond("610#4002200000000000"))
+	})
+	t.Run("expedited download", func(t *testing.T) {
+		assert.Equal(t, []string{"590#6002200000000000"}, respond("610#2F02200044000000"))
+		value, err := local.ReadInt("INTEGER8 value", "")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x44, value)
+	})
+	t.Run("abort", func(t *testing.T) {
+		assert.Equal(t, []string{"590#8000400000000206"}, respond("610#4000400000000000"))
+	})
+	t.Run("segmented upload continues in process", func(t *testing.T) {
+		assert.Len(t, respond("610#401B200000000000"), 1)
+		frame := bus.WaitFor(t, cantest.MatchId(0x590), 0)
+		assert.EqualValues(t, 0x40, frame.Data[0]&0xE0)
+		assert.Equal(t, []byte{0x1B, 0x20, 0x00, 0x08}, frame.Data[1:5])
+		bus.Reset()
+		bus.Inject(cantest.MustParseFrame("610#6000000000000000"))
+		frame = bus.WaitFor(t, cantest.MatchId(0x590), time.Second)
+		assert.EqualValues(t, 0x00, frame.Data[0]&0xF0)
+		assert.Equal(t, []byte{0x55, 0x55, 0x55, 0x55, 0x00, 0x00, 0x00}, frame.Data[1:])
+		bus.Reset()
+		bus.Inject(cantest.MustParseFrame("610#7000000000000000"))
+		frame = bus.WaitFor(t, cantest.MatchId(0x590), time.Second)
+		assert.EqualValues(t, 0x1D, frame.Data[0])
+	})
+}
+
+func TestServerMultipleChannels(t *testing.T) {
+	bus := cantest.NewMockBus(false)
+	network := NewNetwork(bus)
+	assert.Nil(t, network.Connect())
+	defer network.Disconnect()
+	odict := od.Default()
+	entry, err := odict.AddSDOServer(1)
+	assert.Nil(t, err)
+	assert.Nil(t, entry.PutUint32(1, 0x690, true))
+	assert.Nil(t, entry.PutUint32(2, 0x691, true))
+	disabled, err := odict.AddSDOServer(2)
+	assert.Nil(t, err)
+	_, err = odict.AddSDOServer(0x80)
+	assert.Equal(t, od.ErrDevIncompat, err)
+
+	local, err := network.CreateLocalNode(0x10, odict)
+	assert.Nil(t, err)
+	assert.Len(t, local.SDOServers, 3)
+	assert.Eventually(t, func() bool {
+		state := local.NMT.GetInternalState()
+		return state == nmt.StatePreOperational || state == nmt.StateOperational
+	}, time.Second, 10*time.Millisecond)
+
+	t.Run("default channel", func(t *testing.T) {
+		bus.AssertExchange(t, cantest.MustParseFrame("610#4002200000000000"),
+			cantest.MatchFrame(cantest.MustParseFrame("590#4F02200033000000")), time.Second)
+	})
+	t.Run("additional channel", func(t *testing.T) {
+		bus.AssertExchange(t, cantest.MustParseFrame("690#4002200000000000"),
+			cantest.MatchFrame(cantest.MustParseFrame("691#4F02200033000000")), time.Second)
+	})
+	t.Run("channel enabled at runtime", func(t *testing.T) {
+		assert.Nil(t, disabled.PutUint32(1, 0x6A0, false))
+		assert.Nil(t, disabled.PutUint32(2, 0x6A1, false))
+		bus.AssertExchange(t, cantest.MustParseFrame("6A0#4002200000000000"),
+			cantest.MatchFrame(cantest.MustParseFrame("6A1#4F02200033000000")), time.Second)
+	})
+}
+
+func TestServerAccessCheck(t *testing.T) {
+	bus := cantest.NewMockBus(false)
+	network := NewNetwork(bus)
+	assert.Nil(t, network.Connect())
+	defer network.Disconnect()
+	local, err := network.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+	accesses := make(chan sdo.Access, 10)
+	// Configuration is locked in operational
+	local.SetSDOAccessCheck(func(access sdo.Access) error {
+		accesses <- access
+		switch {
+		case access.Index == 0x2003:
+			return errors.New("not allowed")
+		case access.Write && access.Index < 0x2000 && access.NMTState == nmt.StateOperational:
+			return sdo.AbortDataDeviceState
+		case access.Write && access.Index == 0x2002:
+			return sdo.AbortReadOnly
+		}
+		return nil
+	})
+	bus.Inject(cantest.MustParseFrame("000#0110"))
+	assert.Eventually(t, func() bool {
+		return local.NMT.GetInternalState() == nmt.StateOperational
+	}, time.Second, 10*time.Millisecond)
+	// Let NMT state propagate to server
+	time.Sleep(50 * time.Millisecond)
+
+	t.Run("read allowed", func(t *testing.T) {
+		bus.AssertExchange(t, cantest.MustParseFrame("610#4002200000000000"),
+			cantest.MatchFrame(cantest.MustParseFrame("590#4F02200033000000")), time.Second)
+		access := <-accesses
+		assert.Equal(t, sdo.Access{CobId: 0x610, Index: 0x2002, Subindex: 0, Write: false, NMTState: nmt.StateOperational}, access)
+	})
+	t.Run("write refused in operational", func(t *testing.T) {
+		bus.AssertExchange(t, cantest.MustParseFrame("610#2B17100064000000"),
+			cantest.MatchFrame(cantest.MustParseFrame("590#8017100022000008")), time.Second)
+		assert.True(t, (<-accesses).Write)
+	})
+	t.Run("abort returned by check", func(t *testing.T) {
+		bus.AssertExchange(t, cantest.MustParseFrame("610#2F02200044000000"),
+			cantest.MatchFrame(cantest.MustParseFrame("590#8002200002000106")), time.Second)
+		value, err := local.ReadInt(0x2002, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x33, value)
+	})
+	t.Run("other error", func(t *testing.T) {
+		bus.AssertExchange(t, cantest.MustParseFrame("610#4003200000000000"),
+			cantest.MatchFrame(cantest.MustParseFrame("590#8003200022000008")), time.Second)
+	})
+	t.Run("check removed", func(t *testing.T) {
+		local.SetSDOAccessCheck(nil)
+		bus.AssertExchange(t, cantest.MustParseFrame("610#2B17100064000000"),
+			cantest.MatchFrame(cantest.MustParseFrame("590#6017100000000000")), time.Second)
+	})
+}
+
+func TestServerEvents(t *testing.T) {
+	sim := NewSimulation()
+	defer sim.Close()
+	master, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	devices, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	local, err := devices.CreateLocalNode(0x10, od.Default())
+	assert.Nil(t, err)
+	events := []sdo.ServerEvent{}
+	local.SetSDOEventHook(func(event sdo.ServerEvent) {
+		events = append(events, event)
+	})
+	types := func() []sdo.ServerEventType {
+		result := []sdo.ServerEventType{}
+		for _, event := range events {
+			result = append(result, event.Type)
+		}
+		return result
+	}
+
+	t.Run("expedited", func(t *testing.T) {
+		events = events[:0]
+		_, err := master.ReadUint32(0x10, 0x2007, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []sdo.ServerEventType{sdo.ServerTransferStarted, sdo.ServerTransferFinished}, types())
+		assert.Equal(t, sdo.ServerEvent{
+			Type: sdo.ServerTransferFinished, CobId: 0x610, Index: 0x2007, Upload: true,
+			Transferred: 4, Size: 4, Elapsed: events[1].Elapsed,
+		}, events[1])
+		events = events[:0]
+		assert.Nil(t, master.WriteRaw(0x10, 0x2007, 0, uint32(0x1234), false))
+		assert.Equal(t, []sdo.ServerEventType{sdo.ServerTransferStarted, sdo.ServerTransferFinished}, types())
+		assert.False(t, events[1].Upload)
+		assert.EqualValues(t, 4, events[1].Transferred)
+	})
+
+	t.Run("block download progress", func(t *testing.T) {
+		events = events[:0]
+		path := filepath.Join(t.TempDir(), "domain.bin")
+		local.GetOD().AddFile(0x3000, "domain", path, os.O_RDONLY, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+		w, err := master.NewRawWriterWith(context.Background(), 0x10, 0x3000, 0, 3000, sdo.TransferOptions{Block: sdo.BlockAuto})
+		assert.Nil(t, err)
+		_, err = w.ReadFrom(bytes.NewReader(make([]byte, 3000)))
+		assert.Nil(t, err)
+		assert.Equal(t, sdo.ServerTransferStarted, events[0].Type)
+		last := events[len(events)-1]
+		assert.Equal(t, sdo.ServerTransferFinished, last.Type)
+		assert.EqualValues(t, 3000, last.Transferred)
+		assert.EqualValues(t, 3000, last.Size)
+		progress := events[1 : len(events)-1]
+		assert.Greater(t, len(progress), 1)
+		for i, event := range progress {
+			assert.Equal(t, sdo.ServerTransferProgress, event.Type)
+			if i > 0 {
+				assert.Greater(t, event.Transferred, progress[i-1].Transferred)
+			}
+		}
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		events = events[:0]
+		err := master.WriteRaw(0x10, 0x2007, 0, uint64(1), false)
+		assert.ErrorIs(t, err, sdo.AbortDataLong)
+		assert.Equal(t, []sdo.ServerEventType{sdo.ServerTransferStarted, sdo.ServerTransferAborted}, types())
+		assert.Equal(t, sdo.AbortDataLong, events[1].Abort)
+		// Refused before start
+		events = events[:0]
+		_, err = master.ReadUint32(0x10, 0x5555, 0)
+		assert.NotNil(t, err)
+		assert.Len(t, events, 0)
+	})
+}
+
+func TestSDOClientPool(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	odict := od.Default()
+	_, err := odict.AddSDOClient(1)
+	assert.Nil(t, err)
+	local, err := network.CreateLocalNode(NodeIdTest+1, odict)
+	assert.Nil(t, err)
+	assert.Len(t, local.SDOclients, 2)
+
+	t.Run("dedicated clients", func(t *testing.T) {
+		client1, err := network.SDOClientFor(NodeIdTest)
+		assert.Nil(t, err)
+		client2, err := network.SDOClientFor(NodeIdTest + 1)
+		assert.Nil(t, err)
+		assert.NotSame(t, client1, client2)
+		client, err := network.SDOClientFor(NodeIdTest)
+		assert.Nil(t, err)
+		assert.Same(t, client1, client)
+		_, err = network.SDOClientFor(0)
+		assert.Equal(t, ErrIdRange, err)
+	})
+
+	t.Run("parallel transfers", func(t *testing.T) {
+		wg := sync.WaitGroup{}
+		errs := make(chan error, 100)
+		for _, nodeId := range []uint8{NodeIdTest, NodeIdTest + 1, NodeIdTest, NodeIdTest + 1} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 10 {
+					errs <- network.WithSDOClient(nodeId, func(client *sdo.SDOClient) error {
+						value, err := client.ReadUint32(nodeId, 0x2007, 0)
+						if err == nil && value != 0x22222222 {
+							err = sdo.AbortGeneral
+						}
+						return err
+					})
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.Nil(t, err)
+		}
+	})
+}
+
+func TestSDOConcurrentTransfers(t *testing.T) {
+	network := CreateNetworkTest()
+	network2 := CreateNetworkEmptyTest()
+	defer network2.Disconnect()
+	defer network.Disconnect()
+	client := network2.SDOClient
+
+	t.Run("transfers are queued", func(t *testing.T) {
+		wg := sync.WaitGroup{}
+		errs := make(chan error, 100)
+		for i := range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 10 {
+					if i == 0 {
+						_, err := client.ReadAll(NodeIdTest, 0x1021, 0)
+						errs <- err
+						continue
+					}
+					value, err := client.ReadUint32(NodeIdTest, 0x2007, 0)
+					if err == nil && value != 0x22222222 {
+						err = sdo.AbortGeneral
+					}
+					errs <- err
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.Nil(t, err)
+		}
+	})
+
+	t.Run("unfinished transfer holds client", func(t *testing.T) {
+		tr, err := client.NewRawReaderWith(context.Background(), NodeIdTest, 0x1021, 0, 0, sdo.TransferOptions{Block: sdo.BlockAuto})
+		assert.Nil(t, err)
+		_, err = tr.Read(make([]byte, 10))
+		assert.Nil(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = client.ReadRawCtx(ctx, NodeIdTest, 0x2001, 0, make([]byte, 1))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		// Closing aborts transfer and releases client
+		assert.Nil(t, tr.Close())
+		assert.Nil(t, tr.Close())
+		_, err = tr.Read(make([]byte, 10))
+		assert.Equal(t, sdo.ErrTransferFinished, err)
+		_, err = client.ReadUint8(NodeIdTest, 0x2001, 0)
+		assert.Nil(t, err)
+	})
+}
+
+func TestSDOStreamingProgress(t *testing.T) {
+	network := CreateNetworkTest()
+	network2 := CreateNetworkEmptyTest()
+	defer network2.Disconnect()
+	defer network.Disconnect()
+	client := network2.SDOClient
+
+	t.Run("upload with writer to", func(t *testing.T) {
+		eds, err := client.ReadAll(NodeIdTest, 0x1021, 0)
+		assert.Nil(t, err)
+		var progress []sdo.Progress
+		opts := sdo.TransferOptions{Block: sdo.BlockAuto, Progress: func(p sdo.Progress) {
+			progress = append(progress, p)
+		}}
+		tr, err := client.NewRawReaderWith(context.Background(), NodeIdTest, 0x1021, 0, 0, opts)
+		assert.Nil(t, err)
+		buffer := &bytes.Buffer{}
+		n, err := io.Copy(buffer, tr)
+		assert.Nil(t, err)
+		assert.EqualValues(t, len(eds), n)
+		assert.Equal(t, eds, buffer.Bytes())
+		assert.EqualValues(t, len(eds), tr.Transferred())
+		assert.Greater(t, len(progress), 1)
+		last := progress[len(progress)-1]
+		assert.EqualValues(t, len(eds), last.Transferred)
+		assert.Greater(t, last.Rate(), 0.0)
+		for i := 1; i < len(progress); i++ {
+			assert.GreaterOrEqual(t, progress[i].Transferred, progress[i-1].Transferred)
+		}
+	})
+
+	t.Run("download", func(t *testing.T) {
+		var progress []sdo.Progress
+		opts := sdo.TransferOptions{Progress: func(p sdo.Progress) {
+			progress = append(progress, p)
+		}}
+		err := client.WriteRawWith(context.Background(), NodeIdTest, 0x2003, 0, uint16(0x1234), opts)
+		assert.Nil(t, err)
+		assert.Len(t, progress, 1)
+		assert.EqualValues(t, 2, progress[0].Transferred)
+		assert.EqualValues(t, 2, progress[0].Size)
+	})
+}
+
+// Expect a segmented upload of data from node 0x10, 0x2000:00. Only the given number
+// of segments is answered (-1 for all), the next one is answered with abort if not 0.
+func expectSegmentedUpload(bus *cantest.MockBus, data []byte, segments int, abort sdo.Abort) {
+	initiate := canopen.NewFrame(0x590, 0, 8)
+	initiate.Data = [8]byte{0x41, 0x00, 0x20, 0x00}
+	binary.LittleEndian.PutUint32(initiate.Data[4:], uint32(len(data)))
+	bus.ExpectFrame(cantest.MustParseFrame("610#4000200000000000")).Respond(initiate)
+	for i := 0; i*7 < len(data); i++ {
+		toggle := byte(i%2) << 4
+		request := canopen.NewFrame(0x610, 0, 8)
+		request.Data[0] = 0x60 | toggle
+		response := canopen.NewFrame(0x590, 0, 8)
+		if i == segments {
+			if abort != 0 {
+				response.Data = [8]byte{0x80, 0x00, 0x20, 0x00}
+				binary.LittleEndian.PutUint32(response.Data[4:], uint32(abort))
+				bus.ExpectFrame(request).Respond(response)
+			}
+			return
+		}
+		n := copy(response.Data[1:], data[i*7:])
+		response.Data[0] = toggle
+		if (i+1)*7 >= len(data) {
+			response.Data[0] |= byte(7-n)<<1 | 1
+		}
+		bus.ExpectFrame(request).Respond(response)
+	}
+}
+
+func TestSDOReadRawShortBuffer(t *testing.T) {
+	bus := cantest.NewMockBus(true)
+	network := NewNetwork(bus)
+	assert.Nil(t, network.Connect())
+	defer network.Disconnect()
+	client := network.SDOClient
+	client.SetProcessingPeriod(1000)
+	defer client.SetProcessingPeriod(sdo.DefaultClientProcessPeriodUs)
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	t.Run("object received entirely", func(t *testing.T) {
+		bus.Reset()
+		expectSegmentedUpload(bus, data[:100], -1, 0)
+		buffer := make([]byte, 10)
+		n, err := client.ReadRaw(0x10, 0x2000, 0, buffer)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, n)
+		assert.Equal(t, data[:10], buffer)
+		bus.AssertExpectations(t)
+	})
+
+	t.Run("rest of transfer aborted", func(t *testing.T) {
+		bus.Reset()
+		// Client buffer is full after 127 segments
+		expectSegmentedUpload(bus, data, 127, 0)
+		bus.ExpectFrame(cantest.MustParseFrame("610#8000200000000008"))
+		buffer := make([]byte, 10)
+		n, err := client.ReadRaw(0x10, 0x2000, 0, buffer)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, n)
+		assert.Equal(t, data[:10], buffer)
+		bus.AssertExpectations(t)
+		// Client is released for the next transfer
+		bus.Reset()
+		bus.ExpectFrame(cantest.MustParseFrame("610#4000200000000000")).
+			Respond(cantest.MustParseFrame("590#4F002000AB000000"))
+		value, err := client.ReadUint8(0x10, 0x2000, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0xAB, value)
+		bus.AssertExpectations(t)
+	})
+}
+
+func TestSDOResume(t *testing.T) {
+	bus := cantest.NewMockBus(true)
+	network := NewNetwork(bus)
+	assert.Nil(t, network.Connect())
+	defer network.Disconnect()
+	client := network.SDOClient
+	client.SetProcessingPeriod(1000)
+	defer client.SetProcessingPeriod(sdo.DefaultClientProcessPeriodUs)
+	opts := sdo.TransferOptions{Block: sdo.BlockNever, Resume: 1}
+
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	t.Run("upload skips bytes already written", func(t *testing.T) {
+		bus.Reset()
+		expectSegmentedUpload(bus, data, 130, sdo.AbortTimeout)
+		expectSegmentedUpload(bus, data, -1, 0)
+		buffer := &bytes.Buffer{}
+		n, err := client.UploadTo(context.Background(), 0x10, 0x2000, 0, buffer, opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, len(data), n)
+		assert.Equal(t, data, buffer.Bytes())
+		bus.AssertExpectations(t)
+	})
+
+	t.Run("upload object changed", func(t *testing.T) {
+		bus.Reset()
+		expectSegmentedUpload(bus, data, 130, sdo.AbortCRC)
+		expectSegmentedUpload(bus, data[:999], -1, 0)
+		buffer := &bytes.Buffer{}
+		n, err := client.UploadTo(context.Background(), 0x10, 0x2000, 0, buffer, opts)
+		assert.ErrorIs(t, err, sdo.ErrObjectChanged)
+		assert.EqualValues(t, buffer.Len(), n)
+		assert.Less(t, n, int64(len(data)))
+		assert.Equal(t, data[:n], buffer.Bytes())
+	})
+
+	t.Run("download seeks back", func(t *testing.T) {
+		bus.Reset()
+		bus.ExpectFrame(cantest.MustParseFrame("610#210020000A000000")).
+			Respond(cantest.MustParseFrame("590#6000200000000000"))
+		bus.ExpectFrame(cantest.MustParseFrame("610#0001020304050607")).
+			Respond(cantest.MustParseFrame("590#8000200000000305"))
+		bus.ExpectFrame(cantest.MustParseFrame("610#210020000A000000")).
+			Respond(cantest.MustParseFrame("590#6000200000000000"))
+		bus.ExpectFrame(cantest.MustParseFrame("610#0001020304050607")).
+			Respond(cantest.MustParseFrame("590#2000000000000000"))
+		bus.ExpectFrame(cantest.MustParseFrame("610#1908091000000000")).
+			Respond(cantest.MustParseFrame("590#3000000000000000"))
+		data := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 0x10}
+		n, err := client.DownloadFrom(context.Background(), 0x10, 0x2000, 0, bytes.NewReader(data), uint32(len(data)), opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 10, n)
+		bus.AssertExpectations(t)
+	})
+
+	t.Run("not resumable", func(t *testing.T) {
+		bus.Reset()
+		bus.ExpectFrame(cantest.MustParseFrame("610#210020000A000000")).
+			Respond(cantest.MustParseFrame("590#8000200000000206"))
+		_, err := client.DownloadFrom(context.Background(), 0x10, 0x2000, 0, bytes.NewReader(make([]byte, 10)), 10, opts)
+		assert.ErrorIs(t, err, sdo.AbortNotExist)
+		assert.False(t, sdo.IsResumable(err))
+		bus.AssertExpectations(t)
+		// Resume count exhausted
+		bus.Reset()
+		bus.ExpectFrame(cantest.MustParseFrame("610#210020000A000000")).
+			Respond(cantest.MustParseFrame("590#8000200000000405")).Times(2)
+		_, err = client.DownloadFrom(context.Background(), 0x10, 0x2000, 0, bytes.NewReader(make([]byte, 10)), 10, opts)
+		assert.ErrorIs(t, err, sdo.AbortTimeout)
+		assert.True(t, sdo.IsResumable(err))
+		assert.False(t, sdo.IsResumable(context.Canceled))
+		bus.AssertExpectations(t)
+	})
+}
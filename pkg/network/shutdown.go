@@ -0,0 +1,145 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	n "github.com/samsamfire/gocanopen/pkg/node"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+const DefaultShutdownTimeout = 500 * time.Millisecond
+
+var ErrShutdownTimeout = errors.New("node did not enter pre-operational before timeout")
+
+// Shutdown gracefully stops the network :
+//   - PDOs & heartbeat monitors of remote nodes are stopped
+//   - local nodes enter pre-operational state, stopping their PDOs
+//   - parameters of local nodes are stored, if configured with [n.LocalNode.SetSaveOnShutdown]
+//   - pending emergencies of local nodes are sent, ignoring their inhibit time
+//   - node processing is stopped
+//   - bus is disconnected
+//
+// All errors encountered are returned, shutdown is always done completely.
+func (network *Network) Shutdown() error {
+	var errs []error
+	for id, controller := range network.controllers {
+		switch node := controller.GetNode().(type) {
+		case *n.RemoteNode:
+			node.Stop()
+		case *n.LocalNode:
+			node.NMT.SendInternalCommand(uint8(nmt.CommandEnterPreOperational))
+			if node.SaveOnShutdown() {
+				err := node.SaveParameters(od.ParametersAll)
+				if err != nil {
+					network.logger.Warn("failed to store parameters", "id", id, "error", err)
+					errs = append(errs, err)
+				}
+			}
+			node.EMCY.Flush()
+			if !controller.Running() {
+				continue
+			}
+			// Wait for node processing to apply command
+			deadline := time.Now().Add(DefaultShutdownTimeout)
+			for node.NMT.GetInternalState() != nmt.StatePreOperational {
+				if time.Now().After(deadline) {
+					network.logger.Warn("failed to enter pre-operational", "id", id)
+					errs = append(errs, ErrShutdownTimeout)
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+	network.Disconnect()
+	network.logger.Info("network shutdown complete")
+	return errors.Join(errs...)
+}
+
+// Options for [Network.Close]
+type CloseOptions struct {
+	// NMT command broadcast to all the nodes before closing, e.g. [nmt.CommandEnterStopped]
+	// or [nmt.CommandEnterPreOperational]. 0 does not send any command.
+	Command nmt.Command
+}
+
+// Close gracefully stops the network and releases its resources, in order :
+//   - NMT command is broadcast, if configured
+//   - pending emergencies of local nodes are sent
+//   - node processing is stopped
+//   - background tasks are stopped (bus monitoring, discovery, delayed writes, heartbeat monitoring)
+//   - frames queued by the TX scheduler are sent
+//   - frames queued by the RX dispatcher are handled
+//   - all the frame listeners are unsubscribed
+//   - bus is disconnected
+//
+// It returns once everything has terminated. If ctx expires before, remaining steps are
+// still done without waiting and ctx error is returned. opts can be nil.
+// The network should not be used anymore after closing, unlike after [Network.Disconnect].
+func (network *Network) Close(ctx context.Context, opts *CloseOptions) error {
+	if opts == nil {
+		opts = &CloseOptions{}
+	}
+	var errs []error
+	if opts.Command != 0 {
+		err := network.Command(0, opts.Command)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, controller := range network.controllers {
+		if node, ok := controller.GetNode().(*n.LocalNode); ok {
+			node.EMCY.Flush()
+		}
+		controller.Stop()
+	}
+	stopped := make(chan struct{})
+	go func() {
+		for _, controller := range network.controllers {
+			controller.Wait()
+		}
+		close(stopped)
+	}()
+	expired := false
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		network.logger.Warn("node processing did not stop before closing")
+		expired = true
+	}
+	network.stopBackground()
+	if network.StopTxScheduler(ctx) != nil {
+		network.logger.Warn("queued frames were not sent before closing")
+		expired = true
+	}
+	if network.StopRxDispatch(ctx) != nil {
+		network.logger.Warn("received frames were not handled before closing")
+		expired = true
+	}
+	network.UnsubscribeAll()
+	network.disconnectBus()
+	network.logger.Info("network closed")
+	if expired {
+		errs = append(errs, ctx.Err())
+	}
+	return errors.Join(errs...)
+}
+
+// RunUntilSignal blocks until SIGINT or SIGTERM is received or until ctx
+// is cancelled, then performs a graceful [Network.Shutdown].
+// This is typically called at the end of main :
+//
+//	err := network.RunUntilSignal(context.Background())
+func (network *Network) RunUntilSignal(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	network.logger.Info("shutting down network", "cause", context.Cause(ctx))
+	return network.Shutdown()
+}
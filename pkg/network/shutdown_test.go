@@ -0,0 +1,54 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunUntilSignal(t *testing.T) {
+	network := CreateNetworkTest()
+	local, err := network.Local(NodeIdTest)
+	assert.Nil(t, err)
+	_, err = network.AddRemoteNode(NodeIdTest+1, od.Default())
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool {
+		return local.NMT.GetInternalState() == nmt.StateOperational
+	}, 2*time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = network.RunUntilSignal(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, nmt.StatePreOperational, local.NMT.GetInternalState())
+	assert.False(t, network.Connected())
+	assert.False(t, network.controllers[NodeIdTest].Running())
+}
+
+func TestClose(t *testing.T) {
+	network := CreateNetworkTest()
+	other := CreateNetworkEmptyTest()
+	defer other.Disconnect()
+	remote, err := other.CreateLocalNode(NodeIdTest+1, od.Default())
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool {
+		return remote.NMT.GetInternalState() == nmt.StateOperational
+	}, 2*time.Second, 10*time.Millisecond)
+	network.StartTxScheduler(context.Background(), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = network.Close(ctx, &CloseOptions{Command: nmt.CommandEnterStopped})
+	assert.Nil(t, err)
+	assert.False(t, network.Connected())
+	assert.False(t, network.controllers[NodeIdTest].Running())
+	assert.Eventually(t, func() bool {
+		return remote.NMT.GetInternalState() == nmt.StateStopped
+	}, time.Second, 10*time.Millisecond)
+	// Nothing left to stop
+	assert.Nil(t, network.StopTxScheduler(ctx))
+}
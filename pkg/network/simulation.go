@@ -0,0 +1,144 @@
+package network
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/can"
+	n "github.com/samsamfire/gocanopen/pkg/node"
+)
+
+const (
+	// Resolution of a [Simulation], i.e. the main processing period of the nodes
+	SimulationStep = time.Millisecond
+	// Background processing (SYNC, PDO) is run every SimulationBackgroundSteps steps
+	SimulationBackgroundSteps = 10
+)
+
+// Start time of the clock of a [Simulation]
+var SimulationStart = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var simulationCount atomic.Uint32
+
+// A Simulation runs networks and their nodes against a virtual clock, for
+// deterministic integration tests that do not depend on the system time.
+//
+// All the networks of a simulation share an isolated in-process loopback bus,
+// frames are delivered synchronously. Nodes are not processed in the background :
+// time only moves forward with [Simulation.Advance], which runs the processing of
+// every node (main, SDO servers, SYNC & PDO) for each step of [SimulationStep],
+// in order of creation of the networks and of node ids.
+//
+// Blocking SDO transfers of the networks advance the simulation by the SDO client
+// processing period while waiting for a response, so that SDO timeouts elapse
+// in virtual time. Consequently, blocking transfers should not be made from
+// the callbacks of the nodes, as these are run inside of [Simulation.Advance].
+type Simulation struct {
+	mu       sync.Mutex
+	clock    *n.ManualClock
+	channel  string
+	networks []*Network
+	steps    uint64
+}
+
+// Create a new [Simulation], its clock starts at [SimulationStart]
+func NewSimulation() *Simulation {
+	return &Simulation{
+		clock:   n.NewManualClock(SimulationStart),
+		channel: fmt.Sprintf("simulation-%d", simulationCount.Add(1)),
+	}
+}
+
+// Clock of the simulation, used by all the nodes
+func (s *Simulation) Clock() *n.ManualClock {
+	return s.clock
+}
+
+// Current virtual time
+func (s *Simulation) Now() time.Time {
+	return s.clock.Now()
+}
+
+// Time elapsed since start of the simulation
+func (s *Simulation) Elapsed() time.Duration {
+	return s.clock.Now().Sub(SimulationStart)
+}
+
+// Create a new connected [Network] on the bus of the simulation.
+// Nodes created or added to the network are driven by the simulation.
+func (s *Simulation) NewNetwork() (*Network, error) {
+	bus := can.NewLoopbackBus(s.channel)
+	bus.SetReceiveOwn(true)
+	network := NewNetwork(bus)
+	network.SetClock(s.clock)
+	network.SetBusMonitorPeriod(0)
+	network.simulation = s
+	network.sdoPool.stepper = s.Advance
+	err := network.Connect()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.networks = append(s.networks, &network)
+	s.mu.Unlock()
+	return &network, nil
+}
+
+// Run a single step of the simulation
+func (s *Simulation) step() {
+	s.clock.Advance(SimulationStep)
+	s.steps++
+	background := s.steps%SimulationBackgroundSteps == 0
+	for _, network := range s.networks {
+		nodeIds := make([]uint8, 0, len(network.controllers))
+		for id := range network.controllers {
+			nodeIds = append(nodeIds, id)
+		}
+		slices.Sort(nodeIds)
+		for _, id := range nodeIds {
+			controller := network.controllers[id]
+			controller.TickMain()
+			controller.TickServers()
+			if background {
+				controller.TickBackground()
+			}
+		}
+	}
+}
+
+// Advance the simulation by d, rounded up to [SimulationStep]
+func (s *Simulation) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for elapsed := time.Duration(0); elapsed < d; elapsed += SimulationStep {
+		s.step()
+	}
+}
+
+// Advance the simulation until condition is true, or until timeout has elapsed.
+// Condition is checked before each step. Returns true if condition was met.
+func (s *Simulation) AdvanceUntil(condition func() bool, timeout time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for elapsed := time.Duration(0); elapsed < timeout; elapsed += SimulationStep {
+		if condition() {
+			return true
+		}
+		s.step()
+	}
+	return condition()
+}
+
+// Disconnect all the networks of the simulation
+func (s *Simulation) Close() {
+	s.mu.Lock()
+	networks := s.networks
+	s.networks = nil
+	s.mu.Unlock()
+	for _, network := range networks {
+		network.Disconnect()
+	}
+}
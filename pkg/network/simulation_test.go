@@ -0,0 +1,105 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/pdo"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulation(t *testing.T) {
+	sim := NewSimulation()
+	defer sim.Close()
+	master, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	devices, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	producer, err := devices.CreateLocalNode(0x20, od.Default())
+	assert.Nil(t, err)
+	consumer, err := devices.CreateLocalNode(0x21, od.Default())
+	assert.Nil(t, err)
+	start := time.Now()
+
+	t.Run("sdo", func(t *testing.T) {
+		value, err := master.ReadUint32(0x20, 0x2007, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x22222222, value)
+		// Segmented upload & block download
+		name, err := master.ReadAll(0x21, 0x1008, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "DUT", string(name))
+		path := filepath.Join(t.TempDir(), "domain.bin")
+		producer.GetOD().AddFile(0x3000, "domain", path, os.O_RDONLY, os.O_CREATE|os.O_TRUNC|os.O_WRONLY)
+		data := make([]byte, 3000)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		w, err := master.NewRawWriterWith(context.Background(), 0x20, 0x3000, 0, 0, sdo.TransferOptions{Block: sdo.BlockAuto})
+		assert.Nil(t, err)
+		n, err := w.ReadFrom(bytes.NewReader(data))
+		assert.Nil(t, err)
+		assert.EqualValues(t, len(data), n)
+		written, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Equal(t, data, written)
+		// Only one SYNC producer
+		assert.Nil(t, master.WriteRaw(0x21, 0x1005, 0, uint32(0x80), false))
+	})
+
+	t.Run("sdo timeout", func(t *testing.T) {
+		before := sim.Elapsed()
+		_, err := master.ReadUint32(0x30, 0x2007, 0)
+		assert.ErrorIs(t, err, sdo.AbortTimeout)
+		assert.GreaterOrEqual(t, sim.Elapsed()-before, time.Duration(sdo.DefaultClientTimeout)*time.Millisecond)
+	})
+
+	t.Run("sync pdo", func(t *testing.T) {
+		err := producer.Configurator().WriteConfigurationPDO(pdo.MinTpdoNumber, config.PDOConfigurationParameter{
+			CanId:            0x1A0,
+			TransmissionType: 1,
+			Mappings: []config.PDOMappingParameter{
+				{Index: 0x2007, Subindex: 0, LengthBits: 32},
+			},
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, producer.Configurator().EnablePDO(pdo.MinTpdoNumber))
+		_, err = master.ReadPDOs(0x20)
+		assert.Nil(t, err)
+		times := make([]time.Time, 0)
+		master.PDODatabase().OnPDO(func(event PDOEvent) {
+			if event.PDO.CobId == 0x1A0 {
+				times = append(times, sim.Now())
+			}
+		})
+		sim.Advance(time.Second)
+		// SYNC period is 100ms in default OD
+		assert.Len(t, times, 10)
+		for i := 1; i < len(times); i++ {
+			assert.Equal(t, 100*time.Millisecond, times[i].Sub(times[i-1]))
+		}
+	})
+
+	t.Run("heartbeat timeout", func(t *testing.T) {
+		eventHandler := EventHandler{}
+		consumer.HBConsumer.OnEvent(eventHandler.OnEvent)
+		assert.Nil(t, consumer.Configurator().WriteMonitoredNode(1, 0x20, 100))
+		assert.Nil(t, producer.Configurator().WriteHeartbeatPeriod(20))
+		sim.Advance(time.Second)
+		assert.Equal(t, 0, eventHandler.NbEventTimeout())
+		assert.Nil(t, producer.Configurator().WriteHeartbeatPeriod(0))
+		sim.Advance(80 * time.Millisecond)
+		assert.Equal(t, 0, eventHandler.NbEventTimeout())
+		assert.True(t, sim.AdvanceUntil(func() bool { return eventHandler.NbEventTimeout() == 1 }, 100*time.Millisecond))
+	})
+
+	// Several seconds of virtual time, without waiting for them
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,152 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// Reference to a sub-entry of an OD, see [Network.ReadMany]
+type ObjectRef struct {
+	Index    uint16
+	Subindex uint8
+}
+
+func (ref ObjectRef) String() string {
+	return fmt.Sprintf("x%x:%02x", ref.Index, ref.Subindex)
+}
+
+// ReadMany reads the given entries of a node via SDO and returns the decoded values,
+// see [od.DecodeToType]. The OD of the node should be known to the network, e.g. with
+// [Network.AddRemoteNode], otherwise [od.ErrOdMissing] is returned.
+//
+// Reads are spread over the default SDO server channel of the node and its additional
+// server channels (0x1201 - 0x127F) that have valid COB-IDs, so that they run in parallel.
+// Additional channels should therefore not be used by another client at the same time.
+//
+// Entries that cannot be read are missing from the returned values, their errors are
+// joined in the returned error.
+func (network *Network) ReadMany(nodeId uint8, refs []ObjectRef) (map[ObjectRef]any, error) {
+	odict, err := network.GetOD(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	pooled, err := network.pooledClient(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	pooled.mu.Lock()
+	defer pooled.mu.Unlock()
+	clients := append([]*sdo.SDOClient{pooled.client}, network.channelClients(pooled.client, nodeId, odict)...)
+	defer func() {
+		for _, client := range clients[1:] {
+			client.Unsubscribe(client)
+		}
+	}()
+
+	values := make(map[ObjectRef]any, len(refs))
+	errs := []error{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan ObjectRef)
+	for _, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ref := range queue {
+				value, err := readRef(client, nodeId, odict, ref)
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, err)
+				} else {
+					values[ref] = value
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, ref := range refs {
+		queue <- ref
+	}
+	close(queue)
+	wg.Wait()
+	if len(clients) > 1 {
+		network.logger.Debug("read entries in parallel", "id", nodeId, "channels", len(clients), "entries", len(refs))
+	}
+	return values, errors.Join(errs...)
+}
+
+// SnapshotConfiguration reads all the readable entries of the OD of a node, except domains,
+// e.g. for a backup or a diagnostic dump. See [Network.ReadMany].
+func (network *Network) SnapshotConfiguration(nodeId uint8) (map[ObjectRef]any, error) {
+	odict, err := network.GetOD(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	refs := []ObjectRef{}
+	for index, entry := range odict.Entries() {
+		if entry.ObjectType == od.ObjectTypeDOMAIN {
+			continue
+		}
+		// Sub-indexes are not necessarily contiguous
+		found := 0
+		for subindex := 0; subindex <= 0xFF && found < entry.SubCount(); subindex++ {
+			variable, err := entry.SubIndex(uint8(subindex))
+			if err != nil {
+				continue
+			}
+			found++
+			if variable.Attribute&od.AttributeSdoR != 0 && variable.DataType != od.DOMAIN {
+				refs = append(refs, ObjectRef{Index: index, Subindex: uint8(subindex)})
+			}
+		}
+	}
+	return network.ReadMany(nodeId, refs)
+}
+
+// Read & decode a single entry
+func readRef(client *sdo.SDOClient, nodeId uint8, odict *od.ObjectDictionary, ref ObjectRef) (any, error) {
+	variable, err := odict.Index(ref.Index).SubIndex(ref.Subindex)
+	if err != nil {
+		return nil, fmt.Errorf("%v : %w", ref, err)
+	}
+	data, err := client.ReadAll(nodeId, ref.Index, ref.Subindex)
+	if err != nil {
+		return nil, err
+	}
+	value, err := od.DecodeToType(data, variable.DataType)
+	if err != nil {
+		return nil, fmt.Errorf("%v : %w", ref, err)
+	}
+	return value, nil
+}
+
+// Create a client for every additional SDO server channel of the node that is enabled.
+// COB-IDs are read with client, channels that cannot be read are skipped.
+func (network *Network) channelClients(client *sdo.SDOClient, nodeId uint8, odict *od.ObjectDictionary) []*sdo.SDOClient {
+	clients := []*sdo.SDOClient{}
+	for index := od.EntrySDOServerParameter + 1; index <= od.EntrySDOServerParameterEnd; index++ {
+		if odict.Index(index) == nil {
+			continue
+		}
+		cobIdClientToServer, err1 := client.ReadUint32(nodeId, index, 1)
+		cobIdServerToClient, err2 := client.ReadUint32(nodeId, index, 2)
+		if err1 != nil || err2 != nil || cobIdClientToServer&0x80000000 != 0 || cobIdServerToClient&0x80000000 != 0 {
+			continue
+		}
+		channel, err := sdo.NewSDOClient(network.BusManager, network.logger, nil, 0, sdo.DefaultClientTimeout, nil)
+		if err != nil {
+			continue
+		}
+		channel.SetServerChannel(cobIdClientToServer&0x7FF, cobIdServerToClient&0x7FF)
+		network.sdoPool.mu.Lock()
+		channel.SetTransferHook(network.sdoPool.hook)
+		channel.SetStepper(network.sdoPool.stepper)
+		network.sdoPool.mu.Unlock()
+		clients = append(clients, channel)
+	}
+	return clients
+}
@@ -0,0 +1,73 @@
+package network
+
+import (
+	"sync/atomic"
+	"testing"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadMany(t *testing.T) {
+	network := CreateNetworkEmptyTest()
+	defer network.Disconnect()
+	odict := od.Default()
+	channel, err := odict.AddSDOServer(1)
+	assert.Nil(t, err)
+	assert.Nil(t, channel.PutUint32(1, 0x6E0, true))
+	assert.Nil(t, channel.PutUint32(2, 0x5E0, true))
+	_, err = network.CreateLocalNode(NodeIdTest, odict)
+	assert.Nil(t, err)
+
+	// Count requests on the additional server channel
+	var requests atomic.Uint32
+	unsubscribe, err := network.SubscribeRaw(0x6E0, 0x7FF, func(frame canopen.Frame) {
+		requests.Add(1)
+	})
+	assert.Nil(t, err)
+	defer unsubscribe()
+
+	t.Run("read many", func(t *testing.T) {
+		refs := []ObjectRef{}
+		for range 10 {
+			refs = append(refs, ObjectRef{Index: 0x2001, Subindex: 0})
+			refs = append(refs, ObjectRef{Index: 0x1017, Subindex: 0})
+		}
+		refs = append(refs, ObjectRef{Index: 0x1018, Subindex: 1})
+		values, err := network.ReadMany(NodeIdTest, refs)
+		assert.Nil(t, err)
+		assert.Len(t, values, 3)
+		assert.Equal(t, uint64(0x1), values[ObjectRef{Index: 0x2001, Subindex: 0}])
+		assert.Greater(t, requests.Load(), uint32(0))
+	})
+
+	t.Run("missing entries", func(t *testing.T) {
+		values, err := network.ReadMany(NodeIdTest, []ObjectRef{
+			{Index: 0x1017, Subindex: 0},
+			{Index: 0x2001, Subindex: 5},
+			{Index: 0x4567, Subindex: 0},
+		})
+		assert.ErrorIs(t, err, od.ErrSubNotExist)
+		assert.ErrorIs(t, err, od.ErrIdxNotExist)
+		assert.Len(t, values, 1)
+		_, err = network.ReadMany(NodeIdTest+1, []ObjectRef{{Index: 0x1017, Subindex: 0}})
+		assert.ErrorIs(t, err, od.ErrOdMissing)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		values, err := network.SnapshotConfiguration(NodeIdTest)
+		// Empty error history
+		assert.ErrorIs(t, err, sdo.AbortNoData)
+		assert.Contains(t, values, ObjectRef{Index: 0x1017, Subindex: 0})
+		assert.Equal(t, uint64(0x6E0), values[ObjectRef{Index: 0x1201, Subindex: 1}])
+		// Domains are skipped
+		assert.NotContains(t, values, ObjectRef{Index: 0x1021, Subindex: 0})
+		// Default channel is still usable
+		client, err := network.SDOClientFor(NodeIdTest)
+		assert.Nil(t, err)
+		_, err = client.ReadUint8(NodeIdTest, 0x2001, 0)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,111 @@
+package network
+
+import (
+	canopen "github.com/samsamfire/gocanopen"
+	n "github.com/samsamfire/gocanopen/pkg/node"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// Counters of a node of the network
+type NodeStats struct {
+	// SDO transfers made with the client dedicated to this node, see [Network.SDOClientFor]
+	SDO sdo.Stats
+	// Processing loops of the node, if it is added to the network
+	Processor n.ProcessorStats
+	// PDO, SYNC & NMT counters, for local nodes only
+	Diagnostics *n.Diagnostics
+}
+
+// Counters of the network, counters are 32 bits and wrap around.
+type Stats struct {
+	Bus canopen.BusStats
+	// Current bus load, see [canopen.BusManager.SetBitrate] for percentage
+	Load canopen.BusLoad
+	// All SDO transfers made by the network, i.e. with its own client & pooled clients
+	SDO   sdo.Stats
+	Nodes map[uint8]*NodeStats
+}
+
+// Get counters of the bus, SDO clients & nodes of the network.
+// This can be used for monitoring, e.g. exported via expvar or the HTTP gateway.
+func (network *Network) Stats() Stats {
+	stats := Stats{
+		Bus:   network.BusStats(),
+		Load:  network.BusLoad(),
+		Nodes: map[uint8]*NodeStats{},
+	}
+	if network.SDOClient != nil {
+		addSDOStats(&stats.SDO, network.SDOClient.Stats())
+	}
+	node := func(id uint8) *NodeStats {
+		nodeStats, ok := stats.Nodes[id]
+		if !ok {
+			nodeStats = &NodeStats{}
+			stats.Nodes[id] = nodeStats
+		}
+		return nodeStats
+	}
+	for id, client := range network.sdoPool.all() {
+		clientStats := client.Stats()
+		node(id).SDO = clientStats
+		addSDOStats(&stats.SDO, clientStats)
+	}
+	for id, controller := range network.controllers {
+		nodeStats := node(id)
+		nodeStats.Processor = controller.Stats()
+		if local, ok := controller.GetNode().(*n.LocalNode); ok {
+			diagnostics := local.Diagnostics()
+			nodeStats.Diagnostics = &diagnostics
+		}
+	}
+	return stats
+}
+
+// Reset counters of the bus, SDO clients & nodes of the network
+func (network *Network) ResetStats() {
+	network.ResetBusStats()
+	if network.SDOClient != nil {
+		network.SDOClient.ResetStats()
+	}
+	for _, client := range network.sdoPool.all() {
+		client.ResetStats()
+	}
+	for _, controller := range network.controllers {
+		controller.ResetStats()
+		if local, ok := controller.GetNode().(*n.LocalNode); ok {
+			local.ResetDiagnostics()
+		}
+	}
+}
+
+// OnSDOTransfer sets a hook called every time an SDO transfer made by the network
+// finishes, with its own client or with pooled clients, see [sdo.SDOClient.SetTransferHook].
+// This can be used for instrumentation, e.g. for latency histograms.
+// nil removes the hook.
+func (network *Network) OnSDOTransfer(hook func(result sdo.TransferResult)) {
+	pool := &network.sdoPool
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.hook = hook
+	if network.SDOClient != nil {
+		network.SDOClient.SetTransferHook(hook)
+	}
+	for _, pooled := range pool.clients {
+		pooled.client.SetTransferHook(hook)
+	}
+}
+
+func addSDOStats(total *sdo.Stats, stats sdo.Stats) {
+	total.Uploads += stats.Uploads
+	total.Downloads += stats.Downloads
+	total.Failed += stats.Failed
+	total.BytesUploaded += stats.BytesUploaded
+	total.BytesDownloaded += stats.BytesDownloaded
+	total.Retries += stats.Retries
+	for abort, count := range stats.Aborts {
+		if total.Aborts == nil {
+			total.Aborts = map[sdo.Abort]uint32{}
+		}
+		total.Aborts[abort] += count
+	}
+}
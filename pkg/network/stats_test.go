@@ -0,0 +1,70 @@
+package network
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	network := CreateNetworkTest()
+	network2 := CreateNetworkEmptyTest()
+	defer network2.Disconnect()
+	defer network.Disconnect()
+
+	var mu sync.Mutex
+	var results []sdo.TransferResult
+	network2.OnSDOTransfer(func(result sdo.TransferResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+	})
+	client, err := network2.SDOClientFor(NodeIdTest)
+	assert.Nil(t, err)
+	_, err = client.ReadUint8(NodeIdTest, 0x2001, 0)
+	assert.Nil(t, err)
+	_, err = client.ReadAll(NodeIdTest, 0x1234, 0)
+	assert.ErrorIs(t, err, sdo.AbortNotExist)
+	assert.Nil(t, client.WriteRaw(NodeIdTest, 0x2003, 0, uint16(0x1234), false))
+	_, err = network2.ReadAll(NodeIdTest, 0x1018, 1)
+	assert.Nil(t, err)
+
+	t.Run("transfer hook", func(t *testing.T) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Len(t, results, 4)
+		assert.Equal(t, sdo.TransferResult{
+			NodeId: NodeIdTest, Index: 0x2001, Subindex: 0, Upload: true, Transferred: 1, Duration: results[0].Duration,
+		}, results[0])
+		assert.Equal(t, sdo.AbortNotExist, results[1].Err)
+		assert.False(t, results[2].Upload)
+		assert.EqualValues(t, 2, results[2].Transferred)
+	})
+
+	t.Run("network stats", func(t *testing.T) {
+		stats := network2.Stats()
+		assert.EqualValues(t, 3, stats.SDO.Uploads)
+		assert.EqualValues(t, 1, stats.SDO.Downloads)
+		assert.EqualValues(t, 1, stats.SDO.Failed)
+		assert.EqualValues(t, 5, stats.SDO.BytesUploaded)
+		assert.EqualValues(t, 2, stats.SDO.BytesDownloaded)
+		assert.Equal(t, map[sdo.Abort]uint32{sdo.AbortNotExist: 1}, stats.SDO.Aborts)
+		assert.NotZero(t, stats.Bus.FramesSent)
+		assert.NotZero(t, stats.Bus.FramesReceived)
+		// Transfer made with network client is not counted per node
+		assert.EqualValues(t, 2, stats.Nodes[NodeIdTest].SDO.Uploads)
+		assert.Nil(t, stats.Nodes[NodeIdTest].Diagnostics)
+
+		network2.ResetStats()
+		stats = network2.Stats()
+		assert.Zero(t, stats.SDO.Uploads)
+		assert.Zero(t, stats.Bus.FramesSent)
+	})
+
+	t.Run("local node stats", func(t *testing.T) {
+		stats := network.Stats()
+		assert.NotNil(t, stats.Nodes[NodeIdTest].Diagnostics)
+	})
+}
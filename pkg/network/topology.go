@@ -0,0 +1,211 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/pdo"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// Kind of relation between two nodes of a [Topology]
+const (
+	LinkPDO       = "pdo"       // TPDO of producer is received by an RPDO of consumer
+	LinkHeartbeat = "heartbeat" // Consumer monitors heartbeat of producer
+)
+
+// An enabled PDO of a node
+type TopologyPDO struct {
+	Number           uint16                       `json:"number"` // 1-based, for RPDOs and TPDOs
+	CobId            uint16                       `json:"cob_id"`
+	TransmissionType uint8                        `json:"transmission_type"`
+	Mappings         []config.PDOMappingParameter `json:"mappings,omitempty"`
+}
+
+type TopologyNode struct {
+	Id                uint8            `json:"id"`
+	Identity          *config.Identity `json:"identity,omitempty"`
+	HeartbeatPeriodMs uint16           `json:"heartbeat_period_ms"`
+	RPDOs             []TopologyPDO    `json:"rpdos,omitempty"`
+	TPDOs             []TopologyPDO    `json:"tpdos,omitempty"`
+	Err               string           `json:"error,omitempty"` // Set if node could not be read
+}
+
+// A directed relation from a producer to a consumer node
+type TopologyLink struct {
+	Kind     string `json:"kind"` // [LinkPDO] or [LinkHeartbeat]
+	Producer uint8  `json:"producer"`
+	Consumer uint8  `json:"consumer"`
+	CobId    uint16 `json:"cob_id,omitempty"`    // PDO only
+	TPDO     uint16 `json:"tpdo,omitempty"`      // PDO only
+	RPDO     uint16 `json:"rpdo,omitempty"`      // PDO only
+	PeriodMs uint16 `json:"period_ms,omitempty"` // Heartbeat only, expected period
+}
+
+// Topology describes nodes of the network & the data flows between them
+type Topology struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Links []TopologyLink `json:"links"`
+}
+
+// Read enabled PDOs in the given range, stops at first missing PDO
+func readEnabledPDOs(conf *config.NodeConfigurator, start uint16, end uint16) ([]TopologyPDO, error) {
+	pdos := []TopologyPDO{}
+	for pdoNb := start; pdoNb <= end; pdoNb++ {
+		cobId, err := conf.ReadCobIdPDO(pdoNb)
+		if errors.Is(err, sdo.AbortNotExist) {
+			break
+		}
+		if err != nil {
+			return pdos, err
+		}
+		if (cobId>>31)&0b1 == 1 {
+			continue
+		}
+		param, err := conf.ReadConfigurationPDO(pdoNb)
+		if err != nil {
+			return pdos, err
+		}
+		pdos = append(pdos, TopologyPDO{
+			Number:           pdoNb - start + 1,
+			CobId:            param.CanId,
+			TransmissionType: param.TransmissionType,
+			Mappings:         param.Mappings,
+		})
+	}
+	return pdos, nil
+}
+
+// Read a single node of the topology & its heartbeat consumers
+func (network *Network) readTopologyNode(nodeId uint8) (TopologyNode, [][]uint16) {
+	node := TopologyNode{Id: nodeId}
+	conf := network.Configurator(nodeId)
+	identity, err := conf.ReadIdentity()
+	if err != nil {
+		node.Err = err.Error()
+		return node, nil
+	}
+	node.Identity = identity
+	// Optional
+	node.HeartbeatPeriodMs, _ = conf.ReadHeartbeatPeriod()
+	// Optional
+	monitored, _ := conf.ReadMonitoredNodes()
+	node.RPDOs, err = readEnabledPDOs(conf, pdo.MinRpdoNumber, pdo.MaxRpdoNumber)
+	if err == nil {
+		node.TPDOs, err = readEnabledPDOs(conf, pdo.MinTpdoNumber, pdo.MaxTpdoNumber)
+	}
+	if err != nil {
+		node.Err = err.Error()
+	}
+	return node, monitored
+}
+
+// ReadTopology reads the identity, heartbeat & PDO configuration of the given nodes
+// via SDO and derives the relations between them :
+//   - PDO links, for every enabled TPDO and RPDO sharing the same COB-ID
+//   - heartbeat links, from the heartbeat consumer object (0x1016)
+//
+// If no node is given, the network is scanned first, see [Network.Scan].
+// Nodes that cannot be read are reported with an error.
+func (network *Network) ReadTopology(nodeIds ...uint8) (*Topology, error) {
+	if len(nodeIds) == 0 {
+		scan, err := network.Scan(sdo.DefaultClientTimeout)
+		if err != nil {
+			return nil, err
+		}
+		for nodeId := range scan {
+			nodeIds = append(nodeIds, nodeId)
+		}
+	}
+	slices.Sort(nodeIds)
+	topology := &Topology{Nodes: []TopologyNode{}, Links: []TopologyLink{}}
+	consumers := make(map[uint8][][]uint16)
+	for _, nodeId := range nodeIds {
+		node, monitored := network.readTopologyNode(nodeId)
+		topology.Nodes = append(topology.Nodes, node)
+		consumers[nodeId] = monitored
+	}
+	for _, producer := range topology.Nodes {
+		for _, tpdo := range producer.TPDOs {
+			for _, consumer := range topology.Nodes {
+				for _, rpdo := range consumer.RPDOs {
+					if consumer.Id == producer.Id || rpdo.CobId != tpdo.CobId {
+						continue
+					}
+					topology.Links = append(topology.Links, TopologyLink{
+						Kind:     LinkPDO,
+						Producer: producer.Id,
+						Consumer: consumer.Id,
+						CobId:    tpdo.CobId,
+						TPDO:     tpdo.Number,
+						RPDO:     rpdo.Number,
+					})
+				}
+			}
+		}
+	}
+	for _, consumer := range topology.Nodes {
+		for _, monitored := range consumers[consumer.Id] {
+			producerId, period := monitored[0], monitored[1]
+			if producerId == 0 || producerId > 127 || period == 0 {
+				continue
+			}
+			topology.Links = append(topology.Links, TopologyLink{
+				Kind:     LinkHeartbeat,
+				Producer: uint8(producerId),
+				Consumer: consumer.Id,
+				PeriodMs: period,
+			})
+		}
+	}
+	return topology, nil
+}
+
+// WriteJSON writes the topology as JSON
+func (topology *Topology) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(topology)
+}
+
+// WriteDOT writes the topology as a Graphviz DOT graph,
+// e.g. to be rendered with "dot -Tsvg topology.dot"
+func (topology *Topology) WriteDOT(w io.Writer) error {
+	_, err := fmt.Fprint(w, "digraph canopen {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	if err != nil {
+		return err
+	}
+	for _, node := range topology.Nodes {
+		label := fmt.Sprintf("node x%x", node.Id)
+		if node.Identity != nil {
+			label += fmt.Sprintf("\\nvendor x%x\\nproduct x%x", node.Identity.VendorId, node.Identity.ProductCode)
+		}
+		style := ""
+		if node.Err != "" {
+			style = ", style=dashed, color=red"
+		}
+		_, err = fmt.Fprintf(w, "\tn%d [label=\"%s\"%s];\n", node.Id, label, style)
+		if err != nil {
+			return err
+		}
+	}
+	for _, link := range topology.Links {
+		var attributes string
+		switch link.Kind {
+		case LinkPDO:
+			attributes = fmt.Sprintf("label=\"TPDO%d -> RPDO%d\\nx%x\"", link.TPDO, link.RPDO, link.CobId)
+		case LinkHeartbeat:
+			attributes = fmt.Sprintf("label=\"heartbeat %d ms\", style=dashed", link.PeriodMs)
+		}
+		_, err = fmt.Fprintf(w, "\tn%d -> n%d [%s];\n", link.Producer, link.Consumer, attributes)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprint(w, "}\n")
+	return err
+}
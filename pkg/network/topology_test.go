@@ -0,0 +1,51 @@
+package network
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/pdo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopology(t *testing.T) {
+	network := CreateNetworkTest()
+	defer network.Disconnect()
+	_, err := network.CreateLocalNode(NodeIdTest+1, od.Default())
+	assert.Nil(t, err)
+
+	// TPDO1 of first node is consumed by RPDO1 of second node
+	producer := network.Configurator(NodeIdTest)
+	assert.Nil(t, producer.WriteCanIdPDO(pdo.MinTpdoNumber, 0x1B0))
+	assert.Nil(t, producer.EnablePDO(pdo.MinTpdoNumber))
+	consumer := network.Configurator(NodeIdTest + 1)
+	assert.Nil(t, consumer.WriteMappings(pdo.MinRpdoNumber, []config.PDOMappingParameter{{Index: 0x2002, Subindex: 0, LengthBits: 8}}))
+	assert.Nil(t, consumer.WriteCanIdPDO(pdo.MinRpdoNumber, 0x1B0))
+	assert.Nil(t, consumer.EnablePDO(pdo.MinRpdoNumber))
+	assert.Nil(t, consumer.WriteMonitoredNode(1, NodeIdTest, 1500))
+
+	topology, err := network.ReadTopology(NodeIdTest+1, NodeIdTest, 0x7F)
+	assert.Nil(t, err)
+	assert.Len(t, topology.Nodes, 3)
+	assert.EqualValues(t, NodeIdTest, topology.Nodes[0].Id)
+	assert.NotNil(t, topology.Nodes[0].Identity)
+	assert.Len(t, topology.Nodes[0].TPDOs, 1)
+	assert.EqualValues(t, 0x1B0, topology.Nodes[0].TPDOs[0].CobId)
+	assert.NotEmpty(t, topology.Nodes[2].Err)
+	assert.Equal(t, []TopologyLink{
+		{Kind: LinkPDO, Producer: NodeIdTest, Consumer: NodeIdTest + 1, CobId: 0x1B0, TPDO: 1, RPDO: 1},
+		{Kind: LinkHeartbeat, Producer: NodeIdTest, Consumer: NodeIdTest + 1, PeriodMs: 1500},
+	}, topology.Links)
+
+	t.Run("export", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		assert.Nil(t, topology.WriteJSON(buf))
+		assert.Contains(t, buf.String(), `"kind": "pdo"`)
+		buf.Reset()
+		assert.Nil(t, topology.WriteDOT(buf))
+		assert.Contains(t, buf.String(), "n48 -> n49 [label=\"TPDO1 -> RPDO1\\nx1b0\"];")
+		assert.Contains(t, buf.String(), "n48 -> n49 [label=\"heartbeat 1500 ms\", style=dashed];")
+	})
+}
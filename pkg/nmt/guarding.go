@@ -0,0 +1,156 @@
+package nmt
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/emergency"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Node guarding slave (legacy, CiA 301).
+// The master periodically sends an RTR frame on 0x700+id, which is answered
+// with the NMT state & a toggle bit, if heartbeat producer is disabled.
+// Once the first RTR is received, life guarding is active : if no RTR is
+// received within guard time x life time factor, an EMCY is sent.
+type guardingSlave struct {
+	nmt            *NMT
+	toggle         uint8
+	guardTimeUs    uint32
+	lifeTimeFactor uint8
+	lifeTimer      uint32
+	active         bool // Life guarding started, i.e. first RTR received
+	rxNew          bool
+	lost           bool
+}
+
+// Handle node guarding RTR frames
+func (guarding *guardingSlave) Handle(frame canopen.Frame) {
+	nmt := guarding.nmt
+	nmt.mu.Lock()
+	defer nmt.mu.Unlock()
+	// Heartbeat has priority over node guarding
+	if nmt.hearbeatProducerTimeUs != 0 {
+		return
+	}
+	guarding.rxNew = true
+}
+
+// Process node guarding & life guarding, nmt lock should be held.
+// Returns true if response should be sent.
+func (guarding *guardingSlave) process(timeDifferenceUs uint32, timerNextUs *uint32) (canopen.Frame, bool) {
+	nmt := guarding.nmt
+	if guarding.rxNew {
+		guarding.rxNew = false
+		guarding.active = true
+		guarding.lifeTimer = 0
+		if guarding.lost {
+			guarding.lost = false
+			nmt.logger.Info("life guarding resumed")
+			nmt.emcy.ErrorReset(emergency.EmHeartbeatConsumer, 0)
+		}
+		response := canopen.NewFrame(nmt.hbTxBuff.ID, 0, 1)
+		response.Data[0] = nmt.operatingState | guarding.toggle
+		guarding.toggle ^= 0x80
+		nmt.stats.GuardingRequests++
+		return response, true
+	}
+	lifeTimeUs := guarding.guardTimeUs * uint32(guarding.lifeTimeFactor)
+	if !guarding.active || guarding.lost || lifeTimeUs == 0 {
+		return canopen.Frame{}, false
+	}
+	guarding.lifeTimer += timeDifferenceUs
+	if guarding.lifeTimer >= lifeTimeUs {
+		guarding.lost = true
+		nmt.logger.Warn("life guarding event, no guarding request received", "lifeTimeMs", lifeTimeUs/1000)
+		nmt.emcy.ErrorReport(emergency.EmHeartbeatConsumer, emergency.ErrHeartbeat, 0)
+	} else if timerNextUs != nil && *timerNextUs > lifeTimeUs-guarding.lifeTimer {
+		*timerNextUs = lifeTimeUs - guarding.lifeTimer
+	}
+	return canopen.Frame{}, false
+}
+
+// EnableGuarding enables node guarding responses & life guarding with guard time (0x100C)
+// and life time factor (0x100D). Both entries are updated on write.
+func (nmt *NMT) EnableGuarding(entry100C *od.Entry, entry100D *od.Entry) error {
+	if entry100C == nil || entry100D == nil {
+		return canopen.ErrIllegalArgument
+	}
+	guardTimeMs, err := entry100C.Uint16(0)
+	if err != nil {
+		nmt.logger.Error("reading guard time failed",
+			"index", fmt.Sprintf("x%x", entry100C.Index),
+			"error", err,
+		)
+		return canopen.ErrOdParameters
+	}
+	lifeTimeFactor, err := entry100D.Uint8(0)
+	if err != nil {
+		nmt.logger.Error("reading life time factor failed",
+			"index", fmt.Sprintf("x%x", entry100D.Index),
+			"error", err,
+		)
+		return canopen.ErrOdParameters
+	}
+	nmt.DisableGuarding()
+	nmt.mu.Lock()
+	guarding := &guardingSlave{
+		nmt:            nmt,
+		guardTimeUs:    uint32(guardTimeMs) * 1000,
+		lifeTimeFactor: lifeTimeFactor,
+	}
+	nmt.guarding = guarding
+	canId := nmt.hbTxBuff.ID
+	nmt.mu.Unlock()
+	entry100C.AddExtension(nmt, od.ReadEntryDefault, writeEntry100C)
+	entry100D.AddExtension(nmt, od.ReadEntryDefault, writeEntry100D)
+	return nmt.Subscribe(canId, 0x7FF, true, guarding)
+}
+
+// DisableGuarding stops answering node guarding requests & life guarding
+func (nmt *NMT) DisableGuarding() {
+	nmt.mu.Lock()
+	guarding := nmt.guarding
+	nmt.guarding = nil
+	nmt.mu.Unlock()
+	if guarding != nil {
+		nmt.Unsubscribe(guarding)
+	}
+}
+
+// [NMT] update guard time
+func writeEntry100C(stream *od.Stream, data []byte, countWritten *uint16) error {
+	if stream == nil || stream.Subindex != 0 || len(data) != 2 || countWritten == nil {
+		return od.ErrDevIncompat
+	}
+	nmt, ok := stream.Object.(*NMT)
+	if !ok {
+		return od.ErrDevIncompat
+	}
+	nmt.mu.Lock()
+	defer nmt.mu.Unlock()
+	if nmt.guarding != nil {
+		nmt.guarding.guardTimeUs = uint32(binary.LittleEndian.Uint16(data)) * 1000
+		nmt.guarding.lifeTimer = 0
+	}
+	return od.WriteEntryDefault(stream, data, countWritten)
+}
+
+// [NMT] update life time factor
+func writeEntry100D(stream *od.Stream, data []byte, countWritten *uint16) error {
+	if stream == nil || stream.Subindex != 0 || len(data) != 1 || countWritten == nil {
+		return od.ErrDevIncompat
+	}
+	nmt, ok := stream.Object.(*NMT)
+	if !ok {
+		return od.ErrDevIncompat
+	}
+	nmt.mu.Lock()
+	defer nmt.mu.Unlock()
+	if nmt.guarding != nil {
+		nmt.guarding.lifeTimeFactor = data[0]
+		nmt.guarding.lifeTimer = 0
+	}
+	return od.WriteEntryDefault(stream, data, countWritten)
+}
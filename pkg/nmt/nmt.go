@@ -38,6 +38,15 @@ var stateMap = map[uint8]string{
 	StateUnknown:        "UNKNOWN",
 }
 
+// Description of an NMT state, e.g. "OPERATIONAL"
+func StateDescription(state uint8) string {
+	description, ok := stateMap[state]
+	if !ok {
+		return stateMap[StateUnknown]
+	}
+	return description
+}
+
 // Global node state to be used
 const (
 	ResetNot  uint8 = 0
@@ -83,6 +92,15 @@ type NMT struct {
 	nmtTxBuff              canopen.Frame
 	hbTxBuff               canopen.Frame
 	callback               func(nmtState uint8)
+	stats                  Stats
+	guarding               *guardingSlave
+}
+
+// Diagnostic counters of [NMT], counters are 32 bits and wrap around.
+type Stats struct {
+	HeartbeatsSent   uint32 // Heartbeats (and boot-up) sent
+	CommandsReceived uint32 // NMT commands received for this node, including broadcasts
+	GuardingRequests uint32 // Node guarding requests answered
 }
 
 // Handle [NMT] related RX CAN frames
@@ -98,6 +116,7 @@ func (nmt *NMT) Handle(frame canopen.Frame) {
 	nodeId := data[1]
 	if nodeId == 0 || nodeId == nmt.nodeId {
 		nmt.internalCommand = command
+		nmt.stats.CommandsReceived++
 	}
 }
 
@@ -123,8 +142,11 @@ func (nmt *NMT) Process(internalState *uint8, timeDifferenceUs uint32, timerNext
 	if nmtInit || (nmt.hearbeatProducerTimeUs != 0 && (nmt.hearbeatProducerTimer == 0 || nmtStateCopy != nmt.operatingStatePrev)) {
 		nmt.hbTxBuff.Data[0] = nmtStateCopy
 		nmt.mu.Unlock()
-		_ = nmt.Send(nmt.hbTxBuff)
+		err := nmt.Send(nmt.hbTxBuff)
 		nmt.mu.Lock()
+		if err == nil {
+			nmt.stats.HeartbeatsSent++
+		}
 		if nmtStateCopy == StateInitializing {
 			if nmt.control&StartupToOperational != 0 {
 				nmtStateCopy = StateOperational
@@ -138,6 +160,15 @@ func (nmt *NMT) Process(internalState *uint8, timeDifferenceUs uint32, timerNext
 	}
 	nmt.operatingStatePrev = nmtStateCopy
 
+	if nmt.guarding != nil {
+		response, ok := nmt.guarding.process(timeDifferenceUs, timerNextUs)
+		if ok {
+			nmt.mu.Unlock()
+			_ = nmt.Send(response)
+			nmt.mu.Lock()
+		}
+	}
+
 	// Process internal NMT commands either from RX buffer or nmt send command
 	if nmt.internalCommand != CommandEmpty {
 		switch nmt.internalCommand {
@@ -225,6 +256,20 @@ func (nmt *NMT) GetInternalState() uint8 {
 	return nmt.operatingState
 }
 
+// Get diagnostic counters of [NMT]
+func (nmt *NMT) Stats() Stats {
+	nmt.mu.Lock()
+	defer nmt.mu.Unlock()
+	return nmt.stats
+}
+
+// Reset diagnostic counters of [NMT]
+func (nmt *NMT) ResetStats() {
+	nmt.mu.Lock()
+	defer nmt.mu.Unlock()
+	nmt.stats = Stats{}
+}
+
 // Send NMT command to self, don't send on network
 func (nmt *NMT) SendInternalCommand(command uint8) {
 	nmt.mu.Lock()
@@ -303,3 +348,27 @@ func NewNMT(
 	nmt.hbTxBuff = canopen.NewFrame(uint32(canIdHbTx), 0, 1)
 	return nmt, nil
 }
+
+// Rebind uses a new EMCY & heartbeat producer time entry (0x1017), e.g. after the OD
+// of the node has been replaced. NMT state is kept and reception is not interrupted.
+func (nmt *NMT) Rebind(emergency *emergency.EMCY, entry1017 *od.Entry) error {
+	if emergency == nil || entry1017 == nil {
+		return canopen.ErrIllegalArgument
+	}
+	hbProdTimeMs, err := entry1017.Uint16(0)
+	if err != nil {
+		nmt.logger.Error("reading producer heartbeat failed",
+			"index", fmt.Sprintf("x%x", 0x1017),
+			"subindex", 0,
+			"error", err,
+		)
+		return canopen.ErrOdParameters
+	}
+	entry1017.AddExtension(nmt, od.ReadEntryDefault, writeEntry1017)
+	nmt.mu.Lock()
+	defer nmt.mu.Unlock()
+	nmt.emcy = emergency
+	nmt.hearbeatProducerTimeUs = uint32(hbProdTimeMs) * 1000
+	nmt.hearbeatProducerTimer = 0
+	return nil
+}
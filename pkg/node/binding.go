@@ -0,0 +1,258 @@
+package node
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Go value bound to an OD entry mapped inside of PDOs
+type pdoBinding struct {
+	entry    *od.Entry
+	subIndex uint8
+	dataType uint8
+	get      func() any      // TPDO, value to write to OD before TPDO processing
+	set      func(value any) // RPDO, called with new OD value after RPDO processing
+	last     []byte          // Last value written (TPDO) or seen (RPDO) in OD
+}
+
+type pdoBindings struct {
+	mu   sync.Mutex
+	tpdo []*pdoBinding
+	rpdo []*pdoBinding
+}
+
+// Encode a Go numeric, bool or string value to the given OD datatype.
+// Integers are truncated to the OD length, like a C cast.
+func encodeToType(value any, dataType uint8, length uint32) ([]byte, error) {
+	v := reflect.ValueOf(value)
+	var raw uint64
+	var f float64
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			raw, f = 1, 1
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		raw, f = uint64(v.Int()), float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		raw, f = v.Uint(), float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		raw, f = uint64(int64(v.Float())), v.Float()
+	case reflect.String:
+		if dataType != od.VISIBLE_STRING && dataType != od.OCTET_STRING {
+			return nil, od.ErrTypeMismatch
+		}
+		data := make([]byte, length)
+		copy(data, v.String())
+		return data, nil
+	default:
+		return nil, od.ErrTypeMismatch
+	}
+	if length > 8 {
+		return nil, od.ErrTypeMismatch
+	}
+	data := make([]byte, 8)
+	switch dataType {
+	case od.BOOLEAN, od.UNSIGNED8, od.UNSIGNED16, od.UNSIGNED32, od.UNSIGNED64,
+		od.INTEGER8, od.INTEGER16, od.INTEGER32, od.INTEGER64:
+		binary.LittleEndian.PutUint64(data, raw)
+	case od.REAL32:
+		binary.LittleEndian.PutUint32(data, math.Float32bits(float32(f)))
+	case od.REAL64:
+		binary.LittleEndian.PutUint64(data, math.Float64bits(f))
+	default:
+		return nil, od.ErrTypeMismatch
+	}
+	return data[:length], nil
+}
+
+// Find the variable to bind & check that it can be mapped
+func (node *LocalNode) bindingVariable(index any, subIndex any, attribute uint8) (*od.Entry, *od.Variable, error) {
+	entry := node.od.Index(index)
+	if entry == nil {
+		return nil, nil, od.ErrIdxNotExist
+	}
+	variable, err := entry.SubIndex(subIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	if variable.Attribute&attribute == 0 {
+		return nil, nil, od.ErrNoMap
+	}
+	return entry, variable, nil
+}
+
+// Check that a pointer to a Go variable can be used for an OD datatype
+func checkBindingPointer(variable any, dataType uint8) (reflect.Value, error) {
+	ptr := reflect.ValueOf(variable)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return reflect.Value{}, od.ErrTypeMismatch
+	}
+	switch ptr.Elem().Kind() {
+	case reflect.String:
+		if dataType != od.VISIBLE_STRING && dataType != od.OCTET_STRING {
+			return reflect.Value{}, od.ErrTypeMismatch
+		}
+	case reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if dataType == od.VISIBLE_STRING || dataType == od.OCTET_STRING || dataType == od.DOMAIN {
+			return reflect.Value{}, od.ErrTypeMismatch
+		}
+	default:
+		return reflect.Value{}, od.ErrTypeMismatch
+	}
+	return ptr.Elem(), nil
+}
+
+// BindTPDOFunc binds a getter to an OD entry that can be mapped to a TPDO.
+// Before each TPDO processing, the returned value is converted to the OD datatype
+// and written to the OD. If the value changed, transmission of event driven
+// TPDOs mapping the entry is requested.
+// The getter is called from the node's goroutine and should not block.
+func (node *LocalNode) BindTPDOFunc(index any, subIndex any, get func() any) error {
+	entry, variable, err := node.bindingVariable(index, subIndex, od.AttributeTpdo)
+	if err != nil {
+		return err
+	}
+	if get == nil {
+		return od.ErrDevIncompat
+	}
+	node.bindings.mu.Lock()
+	defer node.bindings.mu.Unlock()
+	node.bindings.tpdo = append(node.bindings.tpdo, &pdoBinding{
+		entry:    entry,
+		subIndex: variable.SubIndex,
+		dataType: variable.DataType,
+		get:      get,
+	})
+	return nil
+}
+
+// BindTPDOVariable is like [LocalNode.BindTPDOFunc] but reads the value
+// of a Go variable e.g.
+//
+//	node.BindTPDOVariable("Velocity actual value", 0, &velocity)
+//
+// Variable is read from the node's goroutine, so the application
+// should synchronize accesses, or use [LocalNode.BindTPDOFunc] instead.
+func (node *LocalNode) BindTPDOVariable(index any, subIndex any, variable any) error {
+	_, odVar, err := node.bindingVariable(index, subIndex, od.AttributeTpdo)
+	if err != nil {
+		return err
+	}
+	value, err := checkBindingPointer(variable, odVar.DataType)
+	if err != nil {
+		return err
+	}
+	return node.BindTPDOFunc(index, subIndex, func() any { return value.Interface() })
+}
+
+// BindRPDOFunc binds a callback to an OD entry that can be mapped to an RPDO.
+// After each RPDO processing, the callback is called with the decoded value
+// (e.g. uint64, int64, float64 or string, see [od.DecodeToType]) if it changed.
+// The callback is called from the node's goroutine and should not block.
+func (node *LocalNode) BindRPDOFunc(index any, subIndex any, callback func(value any)) error {
+	entry, variable, err := node.bindingVariable(index, subIndex, od.AttributeRpdo)
+	if err != nil {
+		return err
+	}
+	if callback == nil {
+		return od.ErrDevIncompat
+	}
+	node.bindings.mu.Lock()
+	defer node.bindings.mu.Unlock()
+	node.bindings.rpdo = append(node.bindings.rpdo, &pdoBinding{
+		entry:    entry,
+		subIndex: variable.SubIndex,
+		dataType: variable.DataType,
+		set:      callback,
+	})
+	return nil
+}
+
+// BindRPDOVariable is like [LocalNode.BindRPDOFunc] but updates
+// a Go variable, converted from the OD datatype.
+// Variable is written from the node's goroutine, so the application
+// should synchronize accesses, or use [LocalNode.BindRPDOFunc] instead.
+func (node *LocalNode) BindRPDOVariable(index any, subIndex any, variable any) error {
+	_, odVar, err := node.bindingVariable(index, subIndex, od.AttributeRpdo)
+	if err != nil {
+		return err
+	}
+	value, err := checkBindingPointer(variable, odVar.DataType)
+	if err != nil {
+		return err
+	}
+	return node.BindRPDOFunc(index, subIndex, func(decoded any) {
+		if value.Kind() == reflect.Bool {
+			value.SetBool(decoded != uint64(0))
+			return
+		}
+		value.Set(reflect.ValueOf(decoded).Convert(value.Type()))
+	})
+}
+
+// Write TPDO bound values to OD
+func (node *LocalNode) updateTPDOBindings() {
+	node.bindings.mu.Lock()
+	defer node.bindings.mu.Unlock()
+	for _, binding := range node.bindings.tpdo {
+		streamer, err := od.NewStreamer(binding.entry, binding.subIndex, false)
+		if err != nil {
+			continue
+		}
+		data, err := encodeToType(binding.get(), binding.dataType, streamer.DataLength)
+		if err != nil {
+			node.logger.Warn("failed to convert bound value",
+				"index", binding.entry.Index,
+				"subindex", binding.subIndex,
+				"error", err,
+			)
+			continue
+		}
+		if bytes.Equal(data, binding.last) {
+			continue
+		}
+		err = binding.entry.WriteExactly(binding.subIndex, data, false)
+		if err != nil {
+			node.logger.Warn("failed to write bound value",
+				"index", binding.entry.Index,
+				"subindex", binding.subIndex,
+				"error", err,
+			)
+			continue
+		}
+		binding.last = data
+		// Request transmission of event driven TPDOs
+		binding.entry.RequestTPDO(binding.subIndex)
+	}
+}
+
+// Notify RPDO bindings of changed values in OD
+func (node *LocalNode) updateRPDOBindings() {
+	node.bindings.mu.Lock()
+	defer node.bindings.mu.Unlock()
+	for _, binding := range node.bindings.rpdo {
+		streamer, err := od.NewStreamer(binding.entry, binding.subIndex, false)
+		if err != nil {
+			continue
+		}
+		data := make([]byte, streamer.DataLength)
+		err = binding.entry.ReadExactly(binding.subIndex, data, false)
+		if err != nil || bytes.Equal(data, binding.last) {
+			continue
+		}
+		binding.last = data
+		value, err := od.DecodeToType(data, binding.dataType)
+		if err != nil {
+			continue
+		}
+		binding.set(value)
+	}
+}
@@ -0,0 +1,120 @@
+package node
+
+import (
+	"sync"
+	"time"
+)
+
+// A Ticker delivers the ticks driving node processing.
+// It has the same semantics as [time.Ticker] : ticks are dropped
+// if the previous one has not been consumed yet.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// A Clock is the time source of a [NodeProcessor]. It is used for creating
+// the processing tickers and for computing the time elapsed between two processing.
+// A custom clock can be used for driving processing from another timer
+// (e.g. a timerfd), or for deterministic tests, see [ManualClock].
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+type systemClock struct{}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+// SystemClock is the default [Clock], based on the system time
+var SystemClock Clock = systemClock{}
+
+// ManualClock is a [Clock] that only moves forward when
+// [ManualClock.Advance] is called. Tickers created by this clock
+// fire according to the advanced time.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*manualTicker
+}
+
+type manualTicker struct {
+	clock   *ManualClock
+	c       chan time.Time
+	period  time.Duration
+	next    time.Time
+	stopped bool
+}
+
+func (t *manualTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stopped = true
+}
+
+// Create a new [ManualClock] starting at the given time
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Returns the current time of the clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Create a new ticker firing every d of advanced time
+func (c *ManualClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for ManualClock.NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ticker := &manualTicker{clock: c, c: make(chan time.Time, 1), period: d, next: c.now.Add(d)}
+	c.tickers = append(c.tickers, ticker)
+	return ticker
+}
+
+// Advance the clock by d and fire the tickers that expired
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	tickers := c.tickers[:0]
+	for _, ticker := range c.tickers {
+		if ticker.stopped {
+			continue
+		}
+		tickers = append(tickers, ticker)
+		if ticker.next.After(c.now) {
+			continue
+		}
+		for !ticker.next.After(c.now) {
+			ticker.next = ticker.next.Add(ticker.period)
+		}
+		select {
+		case ticker.c <- c.now:
+		default:
+		}
+	}
+	c.tickers = tickers
+}
@@ -0,0 +1,146 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Concise DCFs of the configuration manager (CiA 302), indexed by node id
+type conciseDCFStore struct {
+	mu      sync.Mutex
+	dcfs    map[uint8][]byte
+	pending map[uint8]*bytes.Buffer
+}
+
+func newConciseDCFStore() *conciseDCFStore {
+	return &conciseDCFStore{
+		dcfs:    make(map[uint8][]byte),
+		pending: make(map[uint8]*bytes.Buffer),
+	}
+}
+
+// ConciseDCF returns the concise DCF stored for the given node id,
+// e.g. written by a configuration tool to 0x1F22. nil if none.
+func (node *LocalNode) ConciseDCF(nodeId uint8) []byte {
+	store := node.conciseDCF
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return bytes.Clone(store.dcfs[nodeId])
+}
+
+// SetConciseDCF stores the concise DCF of the given node id, see [od.EncodeConciseDCF].
+// An empty DCF removes it. This is also readable via 0x1F22 if it exists.
+func (node *LocalNode) SetConciseDCF(nodeId uint8, dcf []byte) error {
+	if nodeId < 1 || nodeId > 127 {
+		return od.ErrSubNotExist
+	}
+	if len(dcf) > 0 {
+		_, err := od.DecodeConciseDCF(dcf)
+		if err != nil {
+			return err
+		}
+	}
+	store := node.conciseDCF
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if len(dcf) == 0 {
+		delete(store.dcfs, nodeId)
+	} else {
+		store.dcfs[nodeId] = bytes.Clone(dcf)
+	}
+	return nil
+}
+
+// AddConciseDCFObject adds the configuration manager object 0x1F22 (concise DCF)
+// to the OD, with one DOMAIN per node id. If the OD already has it, e.g. from
+// the EDS, it is used as is.
+func (node *LocalNode) AddConciseDCFObject() (*od.Entry, error) {
+	entry := node.od.Index(od.EntryConciseDCF)
+	if entry != nil {
+		return entry, nil
+	}
+	array := od.NewArray(128)
+	_, err := array.AddSubObject(0, "Highest sub-index supported", od.UNSIGNED8, od.AttributeSdoR, "0x7F")
+	if err != nil {
+		return nil, err
+	}
+	for nodeId := uint8(1); nodeId <= 127; nodeId++ {
+		_, err = array.AddSubObject(nodeId, fmt.Sprintf("Node %d", nodeId), od.DOMAIN, od.AttributeSdoRw, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	entry = node.od.AddVariableList(od.EntryConciseDCF, "Concise DCF", array)
+	entry.AddExtension(node.conciseDCF, readEntry1F22, writeEntry1F22)
+	return entry, nil
+}
+
+// [SDO] Read the concise DCF of a node
+func readEntry1F22(stream *od.Stream, data []byte, countRead *uint16) error {
+	if stream == nil || data == nil || countRead == nil {
+		return od.ErrDevIncompat
+	}
+	if stream.Subindex == 0 {
+		return od.ReadEntryDefault(stream, data, countRead)
+	}
+	store, ok := stream.Object.(*conciseDCFStore)
+	if !ok {
+		return od.ErrDevIncompat
+	}
+	store.mu.Lock()
+	dcf := store.dcfs[stream.Subindex]
+	store.mu.Unlock()
+	if stream.DataOffset > uint32(len(dcf)) {
+		return od.ErrDevIncompat
+	}
+	n := copy(data, dcf[stream.DataOffset:])
+	*countRead = uint16(n)
+	if stream.DataOffset+uint32(n) < uint32(len(dcf)) {
+		stream.DataOffset += uint32(n)
+		return od.ErrPartial
+	}
+	stream.DataOffset = 0
+	return nil
+}
+
+// [SDO] Write the concise DCF of a node, an empty write removes it
+func writeEntry1F22(stream *od.Stream, data []byte, countWritten *uint16) error {
+	if stream == nil || data == nil || countWritten == nil {
+		return od.ErrDevIncompat
+	}
+	if stream.Subindex == 0 {
+		return od.ErrReadonly
+	}
+	store, ok := stream.Object.(*conciseDCFStore)
+	if !ok {
+		return od.ErrDevIncompat
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	buf, ok := store.pending[stream.Subindex]
+	if stream.DataOffset == 0 || !ok {
+		buf = &bytes.Buffer{}
+		store.pending[stream.Subindex] = buf
+	}
+	buf.Write(data)
+	*countWritten = uint16(len(data))
+	stream.DataOffset += uint32(len(data))
+	if stream.DataLength != stream.DataOffset {
+		return od.ErrPartial
+	}
+	stream.DataOffset = 0
+	delete(store.pending, stream.Subindex)
+	if buf.Len() == 0 {
+		delete(store.dcfs, stream.Subindex)
+		return nil
+	}
+	_, err := od.DecodeConciseDCF(buf.Bytes())
+	if err != nil {
+		return od.ErrInvalidValue
+	}
+	store.dcfs[stream.Subindex] = buf.Bytes()
+	return nil
+}
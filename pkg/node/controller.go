@@ -3,20 +3,51 @@ package node
 import (
 	"context"
 	"log/slog"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/samsamfire/gocanopen/pkg/nmt"
 )
 
+const (
+	mainPeriod       = 1 * time.Millisecond  // Main processing period (NMT, HB, EMCY, ...)
+	backgroundPeriod = 10 * time.Millisecond // Background processing period (SYNC, PDO)
+)
+
 // [NodeProcessor] is responsible for handling the node
 // internal CANopen stack processing.
 type NodeProcessor struct {
 	logger       *slog.Logger
 	node         Node
+	clock        Clock
 	cancel       context.CancelFunc
 	resetHandler func(node Node, cmd uint8) error
 	wg           *sync.WaitGroup
+	running      atomic.Bool
+	lastMain     atomic.Int64 // Unix nano timestamp of last main processing
+	lastBg       atomic.Int64 // Unix nano timestamp of last background processing
+	lastServers  atomic.Int64 // Unix nano timestamp of last servers processing
+	overrunsMain atomic.Uint32
+	overrunsBg   atomic.Uint32
+	timingMain   loopTiming
+	timingBg     loopTiming
+	mu           sync.Mutex
+	overrunHook  func(overrun Overrun)
+	overrunEMCY  atomic.Bool
+}
+
+// Counters of a [NodeProcessor], counters are 32 bits and wrap around.
+// An overrun is a processing cycle that took longer than its period.
+type ProcessorStats struct {
+	MainOverruns       uint32
+	BackgroundOverruns uint32
+	// Execution time of the processing cycles, since start or last reset
+	MainMaxDuration       time.Duration
+	MainAvgDuration       time.Duration
+	BackgroundMaxDuration time.Duration
+	BackgroundAvgDuration time.Duration
 }
 
 func NewNodeProcessor(n Node, logger *slog.Logger) *NodeProcessor {
@@ -25,14 +56,88 @@ func NewNodeProcessor(n Node, logger *slog.Logger) *NodeProcessor {
 		logger = slog.Default()
 	}
 
-	return &NodeProcessor{logger: logger.With("service", "[CTRLR]", "id", n.GetID()), node: n, wg: &sync.WaitGroup{}}
+	return &NodeProcessor{
+		logger: logger.With("service", "[CTRLR]", "id", n.GetID()),
+		node:   n,
+		clock:  SystemClock,
+		wg:     &sync.WaitGroup{},
+	}
+}
+
+// Set the [Clock] used for processing ticks & elapsed time calculations.
+// This should be called before [NodeProcessor.Start].
+func (c *NodeProcessor) SetClock(clock Clock) {
+	if clock == nil {
+		clock = SystemClock
+	}
+	c.clock = clock
+}
+
+// Time elapsed since last processing in microseconds, defaults to
+// the nominal period on first processing
+func (c *NodeProcessor) elapsedUs(last *atomic.Int64, now time.Time, period time.Duration) uint32 {
+	previous := last.Swap(now.UnixNano())
+	if previous == 0 {
+		return uint32(period.Microseconds())
+	}
+	elapsed := now.UnixNano() - previous
+	if elapsed < 0 {
+		return 0
+	}
+	return uint32(min(elapsed/int64(time.Microsecond), math.MaxUint32))
+}
+
+// Run a single background processing cycle ([SYNC],[TPDO],[RPDO]).
+// Elapsed time is computed from the processor's [Clock].
+// This can be used instead of [NodeProcessor.Start] for driving
+// processing from a custom timer.
+func (c *NodeProcessor) TickBackground() {
+	now := c.clock.Now()
+	timeDifferenceUs := c.elapsedUs(&c.lastBg, now, backgroundPeriod)
+	syncWas := c.node.ProcessSYNC(timeDifferenceUs, nil)
+	c.node.ProcessTPDO(syncWas, timeDifferenceUs, nil)
+	c.node.ProcessRPDO(syncWas, timeDifferenceUs, nil)
+	c.measure(LoopBackground, now)
+}
+
+// Run a single main processing cycle, handling reset requests.
+// Elapsed time is computed from the processor's [Clock].
+// This can be used instead of [NodeProcessor.Start] for driving
+// processing from a custom timer.
+func (c *NodeProcessor) TickMain() {
+	now := c.clock.Now()
+	timeDifferenceUs := c.elapsedUs(&c.lastMain, now, mainPeriod)
+	state := c.node.ProcessMain(false, timeDifferenceUs, nil)
+	c.measure(LoopMain, now)
+	if state == nmt.ResetApp || state == nmt.ResetComm {
+		c.logger.Info("node reset requested")
+		if c.resetHandler != nil {
+			err := c.resetHandler(c.node, state)
+			if err != nil {
+				c.logger.Info("failed to reset node", "error", err)
+			}
+		} else {
+			c.logger.Warn("no reset handler registered")
+		}
+	}
+}
+
+// Run a single processing cycle of the SDO servers, handling the
+// received requests without blocking. Elapsed time is computed from the
+// processor's [Clock] and used for the transfer timeouts.
+// This can be used instead of [NodeProcessor.Start] for driving
+// processing from a custom timer.
+func (c *NodeProcessor) TickServers() {
+	now := c.clock.Now()
+	timeDifferenceUs := c.elapsedUs(&c.lastServers, now, mainPeriod)
+	for _, server := range c.node.Servers() {
+		server.ProcessPending(timeDifferenceUs)
+	}
 }
 
 // background processing for [SYNC],[TPDO],[RPDO] services
-func (c *NodeProcessor) background(ctx context.Context) {
+func (c *NodeProcessor) background(ctx context.Context, ticker Ticker) {
 
-	const PeriodUs = 10_000
-	ticker := time.NewTicker(PeriodUs * time.Microsecond)
 	c.logger.Info("starting node background process")
 	for {
 		select {
@@ -40,19 +145,15 @@ func (c *NodeProcessor) background(ctx context.Context) {
 			c.logger.Info("exited node background process")
 			ticker.Stop()
 			return
-		case <-ticker.C:
-			syncWas := c.node.ProcessSYNC(PeriodUs, nil)
-			c.node.ProcessTPDO(syncWas, PeriodUs, nil)
-			c.node.ProcessRPDO(syncWas, PeriodUs, nil)
+		case <-ticker.C():
+			c.TickBackground()
 		}
 	}
 }
 
 // Main node processing
-func (c *NodeProcessor) main(ctx context.Context) {
+func (c *NodeProcessor) main(ctx context.Context, ticker Ticker) {
 
-	const PeriodUs = 1_000
-	ticker := time.NewTicker(PeriodUs * time.Microsecond)
 	c.logger.Info("starting node main process")
 	for {
 		select {
@@ -60,20 +161,8 @@ func (c *NodeProcessor) main(ctx context.Context) {
 			c.logger.Info("exited node main process")
 			ticker.Stop()
 			return
-		case <-ticker.C:
-			// Process main
-			state := c.node.ProcessMain(false, PeriodUs, nil)
-			if state == nmt.ResetApp || state == nmt.ResetComm {
-				c.logger.Info("node reset requested")
-				if c.resetHandler != nil {
-					err := c.resetHandler(c.node, state)
-					if err != nil {
-						c.logger.Info("failed to reset node", "error", err)
-					}
-				} else {
-					c.logger.Warn("no reset handler registered")
-				}
-			}
+		case <-ticker.C():
+			c.TickMain()
 		}
 	}
 
@@ -86,17 +175,23 @@ func (c *NodeProcessor) Start(ctx context.Context) error {
 
 	ctx, cancel := context.WithCancel(ctx)
 	c.cancel = cancel
+	c.running.Store(true)
+	// Tickers are created before returning, so that the clock
+	// can be advanced right after starting
+	backgroundTicker := c.clock.NewTicker(backgroundPeriod)
+	mainTicker := c.clock.NewTicker(mainPeriod)
 
 	c.wg.Add(1)
 	go func() {
 		defer c.wg.Done()
-		c.background(ctx)
+		defer c.running.Store(false)
+		c.background(ctx, backgroundTicker)
 	}()
 
 	c.wg.Add(1)
 	go func() {
 		defer c.wg.Done()
-		c.main(ctx)
+		c.main(ctx, mainTicker)
 	}()
 
 	for _, server := range c.node.Servers() {
@@ -135,3 +230,36 @@ func (c *NodeProcessor) AddResetHandler(handler func(node Node, cmd uint8) error
 func (c *NodeProcessor) GetNode() Node {
 	return c.node
 }
+
+// Returns true if node processing has been started and not stopped
+func (c *NodeProcessor) Running() bool {
+	return c.running.Load()
+}
+
+// Returns the last time the main and background processing were run.
+// This can be used as a watchdog for the processing loops.
+func (c *NodeProcessor) LastProcessed() (main time.Time, background time.Time) {
+	return time.Unix(0, c.lastMain.Load()), time.Unix(0, c.lastBg.Load())
+}
+
+// Get counters of the processing loops
+func (c *NodeProcessor) Stats() ProcessorStats {
+	return ProcessorStats{
+		MainOverruns:          c.overrunsMain.Load(),
+		BackgroundOverruns:    c.overrunsBg.Load(),
+		MainMaxDuration:       c.timingMain.max(),
+		MainAvgDuration:       c.timingMain.avg(),
+		BackgroundMaxDuration: c.timingBg.max(),
+		BackgroundAvgDuration: c.timingBg.avg(),
+	}
+}
+
+// Reset counters of the processing loops, this also
+// clears the overrun EMCY, see [NodeProcessor.SetOverrunEMCY]
+func (c *NodeProcessor) ResetStats() {
+	c.overrunsMain.Store(0)
+	c.overrunsBg.Store(0)
+	c.timingMain.reset()
+	c.timingBg.reset()
+	c.clearOverrunEMCY()
+}
@@ -0,0 +1,125 @@
+package node
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Default index of the diagnostics object, in the manufacturer specific area
+const DefaultDiagnosticsIndex uint16 = 0x5FF0
+
+// Diagnostic counters of a [LocalNode], aggregated over all of its PDOs.
+// Counters are 32 bits and wrap around. See [pdo.TPDO.Stats], [pdo.RPDO.Stats],
+// [sync.SYNC.Stats] & [nmt.NMT.Stats] for individual counters.
+type Diagnostics struct {
+	TPDOSent            uint32
+	TPDOErrors          uint32
+	RPDOReceived        uint32
+	RPDOErrors          uint32
+	RPDOTimeouts        uint32
+	SYNCReceived        uint32
+	SYNCSent            uint32
+	SYNCErrors          uint32
+	HeartbeatsSent      uint32
+	NMTCommandsReceived uint32
+}
+
+// Sub-indexes of the diagnostics object, in order
+var diagnosticsFields = []struct {
+	name  string
+	value func(d *Diagnostics) uint32
+}{
+	{"TPDO sent", func(d *Diagnostics) uint32 { return d.TPDOSent }},
+	{"TPDO errors", func(d *Diagnostics) uint32 { return d.TPDOErrors }},
+	{"RPDO received", func(d *Diagnostics) uint32 { return d.RPDOReceived }},
+	{"RPDO errors", func(d *Diagnostics) uint32 { return d.RPDOErrors }},
+	{"RPDO timeouts", func(d *Diagnostics) uint32 { return d.RPDOTimeouts }},
+	{"SYNC received", func(d *Diagnostics) uint32 { return d.SYNCReceived }},
+	{"SYNC sent", func(d *Diagnostics) uint32 { return d.SYNCSent }},
+	{"SYNC errors", func(d *Diagnostics) uint32 { return d.SYNCErrors }},
+	{"Heartbeats sent", func(d *Diagnostics) uint32 { return d.HeartbeatsSent }},
+	{"NMT commands received", func(d *Diagnostics) uint32 { return d.NMTCommandsReceived }},
+}
+
+// Get the current diagnostic counters of the node
+func (node *LocalNode) Diagnostics() Diagnostics {
+	d := Diagnostics{}
+	for _, tpdo := range node.TPDOs {
+		stats := tpdo.Stats()
+		d.TPDOSent += stats.Frames
+		d.TPDOErrors += stats.Errors
+	}
+	for _, rpdo := range node.RPDOs {
+		stats := rpdo.Stats()
+		d.RPDOReceived += stats.Frames
+		d.RPDOErrors += stats.Errors
+		d.RPDOTimeouts += stats.Timeouts
+	}
+	if node.SYNC != nil {
+		stats := node.SYNC.Stats()
+		d.SYNCReceived = stats.Received
+		d.SYNCSent = stats.Sent
+		d.SYNCErrors = stats.Errors
+	}
+	if node.NMT != nil {
+		stats := node.NMT.Stats()
+		d.HeartbeatsSent = stats.HeartbeatsSent
+		d.NMTCommandsReceived = stats.CommandsReceived
+	}
+	return d
+}
+
+// Reset all diagnostic counters of the node
+func (node *LocalNode) ResetDiagnostics() {
+	for _, tpdo := range node.TPDOs {
+		tpdo.ResetStats()
+	}
+	for _, rpdo := range node.RPDOs {
+		rpdo.ResetStats()
+	}
+	if node.SYNC != nil {
+		node.SYNC.ResetStats()
+	}
+	if node.NMT != nil {
+		node.NMT.ResetStats()
+	}
+}
+
+// AddDiagnosticsObject exposes the diagnostic counters inside of the OD, so that they
+// can be read by a remote master. A read only RECORD is created at the given index
+// (e.g. [DefaultDiagnosticsIndex]), with one UNSIGNED32 counter per sub-index,
+// in the same order as [Diagnostics]. Counters can also be mapped to TPDOs.
+func (node *LocalNode) AddDiagnosticsObject(index uint16) (*od.Entry, error) {
+	if node.od.Index(index) != nil {
+		return nil, fmt.Errorf("%w : entry x%x already exists", canopen.ErrIllegalArgument, index)
+	}
+	record := od.NewRecord()
+	record.AddSubObject(0, "Highest sub-index supported", od.UNSIGNED8, od.AttributeSdoR, fmt.Sprintf("0x%x", len(diagnosticsFields)))
+	for i, field := range diagnosticsFields {
+		record.AddSubObject(uint8(i)+1, field.name, od.UNSIGNED32, od.AttributeSdoR|od.AttributeTpdo, "0x0")
+	}
+	entry := node.od.AddVariableList(index, "Diagnostics", record)
+	entry.AddExtension(node, readEntryDiagnostics, od.WriteEntryDisabled)
+	return entry, nil
+}
+
+// Read live diagnostic counters
+func readEntryDiagnostics(stream *od.Stream, data []byte, countRead *uint16) error {
+	if stream == nil || data == nil || countRead == nil {
+		return od.ErrDevIncompat
+	}
+	if stream.Subindex == 0 {
+		return od.ReadEntryDefault(stream, data, countRead)
+	}
+	node, ok := stream.Object.(*LocalNode)
+	if !ok || int(stream.Subindex) > len(diagnosticsFields) || len(data) < 4 {
+		return od.ErrDevIncompat
+	}
+	d := node.Diagnostics()
+	binary.LittleEndian.PutUint32(data, diagnosticsFields[stream.Subindex-1].value(&d))
+	*countRead = 4
+	return nil
+}
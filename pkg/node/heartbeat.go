@@ -0,0 +1,89 @@
+package node
+
+import (
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// HeartbeatPeriod returns the heartbeat producer period (0x1017) in milliseconds.
+func (node *LocalNode) HeartbeatPeriod() (uint16, error) {
+	return node.od.Index(od.EntryProducerHeartbeatTime).Uint16(0)
+}
+
+// SetHeartbeatPeriod updates the heartbeat producer period (0x1017) at runtime.
+// The new period is applied immediately, 0 disables the heartbeat producer.
+func (node *LocalNode) SetHeartbeatPeriod(periodMs uint16) error {
+	return node.od.Index(od.EntryProducerHeartbeatTime).PutUint16(0, periodMs, false)
+}
+
+// MonitoredNodes returns the nodes monitored by the heartbeat consumer (0x1016),
+// as a map of node id to expected heartbeat period in milliseconds.
+func (node *LocalNode) MonitoredNodes() (map[uint8]uint16, error) {
+	entry := node.od.Index(od.EntryConsumerHeartbeatTime)
+	monitored := make(map[uint8]uint16)
+	for i := 1; i < entry.SubCount(); i++ {
+		value, err := entry.Uint32(uint8(i))
+		if err != nil {
+			return nil, err
+		}
+		nodeId, periodMs := uint8(value>>16), uint16(value)
+		if nodeId != 0 && periodMs != 0 {
+			monitored[nodeId] = periodMs
+		}
+	}
+	return monitored, nil
+}
+
+// AddMonitoredNode makes the heartbeat consumer (0x1016) monitor nodeId with
+// the expected heartbeat period. The entry already monitoring nodeId is updated,
+// otherwise the first unused entry is taken.
+// Returns [config.ErrNoFreeMonitorEntry] if every entry is in use.
+func (node *LocalNode) AddMonitoredNode(nodeId uint8, periodMs uint16) error {
+	subIndex, err := node.monitorSubIndex(nodeId, true)
+	if err != nil {
+		return err
+	}
+	return node.writeMonitoredNode(subIndex, nodeId, periodMs)
+}
+
+// RemoveMonitoredNode stops monitoring nodeId, nothing is done if it is not monitored.
+func (node *LocalNode) RemoveMonitoredNode(nodeId uint8) error {
+	subIndex, err := node.monitorSubIndex(nodeId, false)
+	if err != nil || subIndex == 0 {
+		return err
+	}
+	return node.writeMonitoredNode(subIndex, 0, 0)
+}
+
+// Write through the 0x1016 extension so that the consumer is reconfigured
+func (node *LocalNode) writeMonitoredNode(subIndex uint8, nodeId uint8, periodMs uint16) error {
+	value := uint32(nodeId)<<16 | uint32(periodMs)
+	return node.od.Index(od.EntryConsumerHeartbeatTime).PutUint32(subIndex, value, false)
+}
+
+// Find the 0x1016 sub-index monitoring nodeId, or the first free one if allowed.
+// Returns 0 if nodeId is not monitored and no free sub-index is requested.
+func (node *LocalNode) monitorSubIndex(nodeId uint8, allowFree bool) (uint8, error) {
+	entry := node.od.Index(od.EntryConsumerHeartbeatTime)
+	free := uint8(0)
+	for i := 1; i < entry.SubCount(); i++ {
+		value, err := entry.Uint32(uint8(i))
+		if err != nil {
+			return 0, err
+		}
+		id, periodMs := uint8(value>>16), uint16(value)
+		if id == nodeId && periodMs != 0 {
+			return uint8(i), nil
+		}
+		if free == 0 && (id == 0 || periodMs == 0) {
+			free = uint8(i)
+		}
+	}
+	if !allowFree {
+		return 0, nil
+	}
+	if free == 0 {
+		return 0, config.ErrNoFreeMonitorEntry
+	}
+	return free, nil
+}
@@ -3,10 +3,12 @@ package node
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"sync/atomic"
 
 	canopen "github.com/samsamfire/gocanopen"
 	"github.com/samsamfire/gocanopen/pkg/emergency"
@@ -35,6 +37,11 @@ type LocalNode struct {
 	SYNC               *s.SYNC
 	EMCY               *emergency.EMCY
 	TIME               *t.TIME
+	conciseDCF         *conciseDCFStore
+	bindings           pdoBindings
+	parameters         atomic.Pointer[parameterStorage]
+	sdoServerTimeoutMs uint32
+	sdoClientTimeoutMs uint32
 }
 
 func (node *LocalNode) ProcessTPDO(syncWas bool, timeDifferenceUs uint32, timerNextUs *uint32) {
@@ -42,6 +49,7 @@ func (node *LocalNode) ProcessTPDO(syncWas bool, timeDifferenceUs uint32, timerN
 		return
 	}
 	nmtIsOperational := node.NMT.GetInternalState() == nmt.StateOperational
+	node.updateTPDOBindings()
 	for _, tpdo := range node.TPDOs {
 		tpdo.Process(timeDifferenceUs, timerNextUs, nmtIsOperational, syncWas)
 	}
@@ -55,6 +63,7 @@ func (node *LocalNode) ProcessRPDO(syncWas bool, timeDifferenceUs uint32, timerN
 	for _, rpdo := range node.RPDOs {
 		rpdo.Process(timeDifferenceUs, timerNextUs, nmtIsOperational, syncWas)
 	}
+	node.updateRPDOBindings()
 }
 
 func (node *LocalNode) ProcessSYNC(timeDifferenceUs uint32, timerNextUs *uint32) bool {
@@ -109,7 +118,71 @@ func (node *LocalNode) Servers() []*sdo.SDOServer {
 	return node.SDOServers
 }
 
+// SetSDOAccessCheck authorizes the uploads & downloads of all the SDO servers,
+// see [sdo.SDOServer.SetAccessCheck]
+func (node *LocalNode) SetSDOAccessCheck(check func(access sdo.Access) error) {
+	for _, server := range node.SDOServers {
+		server.SetAccessCheck(check)
+	}
+}
+
+// SetRPDOEventHook reports the timeouts & length errors of all the RPDOs,
+// see [pdo.RPDO.SetEventHook]
+func (node *LocalNode) SetRPDOEventHook(hook func(event pdo.RPDOEvent)) {
+	for _, rpdo := range node.RPDOs {
+		rpdo.SetEventHook(hook)
+	}
+}
+
+// TriggerTPDO requests transmission of an event driven TPDO, pdoNb is between
+// [pdo.MinTpdoNumber] & [pdo.MaxTpdoNumber] like for the configurator. See [pdo.TPDO.Request]
+func (node *LocalNode) TriggerTPDO(pdoNb uint16) error {
+	if pdoNb < pdo.MinTpdoNumber || int(pdoNb-pdo.MinTpdoNumber) >= len(node.TPDOs) {
+		return canopen.ErrIllegalArgument
+	}
+	node.TPDOs[pdoNb-pdo.MinTpdoNumber].Request()
+	return nil
+}
+
+// SetTPDOOnChange enables or disables the transmission of the event driven TPDOs
+// mapping an entry whenever its value changes, see [od.Entry.SetTPDOOnChange]
+func (node *LocalNode) SetTPDOOnChange(index any, subindex any, enabled bool) error {
+	entry := node.od.Index(index)
+	variable, err := entry.SubIndex(subindex)
+	if err != nil {
+		return err
+	}
+	return entry.SetTPDOOnChange(variable.SubIndex, enabled)
+}
+
+// SetSDOEventHook reports the transfer events of all the SDO servers,
+// see [sdo.SDOServer.SetEventHook]
+func (node *LocalNode) SetSDOEventHook(hook func(event sdo.ServerEvent)) {
+	for _, server := range node.SDOServers {
+		server.SetEventHook(hook)
+	}
+}
+
 // Initialize all PDOs
+// AddShadowRecord guards the given RECORD or ARRAY entry against partial updates.
+// SDO writes are buffered until commitSubindex is written, see [od.ShadowRecord].
+func (node *LocalNode) AddShadowRecord(index any, commitSubindex uint8) (*od.ShadowRecord, error) {
+	return od.NewShadowRecord(node.od.Index(index), commitSubindex, node.logger)
+}
+
+// OnHeartbeatEvent registers a listener for the heartbeat consumer (0x1016) events
+// of a monitored node, e.g. boot-up, heartbeat lost, NMT state change.
+// Use [heartbeat.EventAllNodes] for all the monitored nodes.
+func (node *LocalNode) OnHeartbeatEvent(nodeId uint8, listener heartbeat.EventListener) {
+	node.HBConsumer.OnNodeEvent(nodeId, listener)
+}
+
+// HeartbeatEvents is like [LocalNode.OnHeartbeatEvent] but returns a channel,
+// closed when ctx is cancelled.
+func (node *LocalNode) HeartbeatEvents(ctx context.Context, nodeId uint8, size int) <-chan heartbeat.Event {
+	return node.HBConsumer.NodeEvents(ctx, nodeId, size)
+}
+
 func (node *LocalNode) initPDO() error {
 	if node.id < 1 || node.id > 127 || node.NodeIdUnconfigured {
 		if node.NodeIdUnconfigured {
@@ -141,6 +214,7 @@ func (node *LocalNode) initPDO() error {
 			node.logger.Warn("no more RPDO after", "nb", i-1)
 			break
 		} else {
+			rpdo.SetNodeId(node.id)
 			node.RPDOs = append(node.RPDOs, rpdo)
 		}
 	}
@@ -166,6 +240,7 @@ func (node *LocalNode) initPDO() error {
 			node.logger.Warn("no more TPDO after", "nb", i-1)
 			break
 		} else {
+			tpdo.SetNodeId(node.id)
 			node.TPDOs = append(node.TPDOs, tpdo)
 		}
 
@@ -202,31 +277,23 @@ func NewLocalNode(
 	if err != nil {
 		return nil, err
 	}
-	node := &LocalNode{BaseNode: base}
+	node := &LocalNode{
+		BaseNode:           base,
+		conciseDCF:         newConciseDCFStore(),
+		sdoServerTimeoutMs: sdoServerTimeoutMs,
+		sdoClientTimeoutMs: sdoClientTimeoutMs,
+	}
 	node.NodeIdUnconfigured = false
 	node.od = odict
 	node.id = nodeId
 
 	if emcy == nil {
-		emergency, err := emergency.NewEMCY(
-			bm,
-			logger,
-			nodeId,
-			odict.Index(od.EntryErrorRegister),
-			odict.Index(od.EntryCobIdEMCY),
-			odict.Index(od.EntryInhibitTimeEMCY),
-			odict.Index(od.EntryManufacturerStatusRegister),
-			nil,
-		)
+		emcy, err = newEMCY(bm, logger, nodeId, odict)
 		if err != nil {
-			logger.Error("init failed [EMCY] producer", "error", err)
-			return nil, canopen.ErrOdParameters
+			return nil, err
 		}
-		node.EMCY = emergency
-	} else {
-		node.EMCY = emcy
 	}
-	emcy = node.EMCY
+	node.EMCY = emcy
 
 	// NMT object can either be supplied or created with automatically with an OD entry
 	if nm == nil {
@@ -254,51 +321,105 @@ func NewLocalNode(
 		logger.Info("[NMT] initialized from parameters")
 	}
 
+	err = node.initGuarding(odict)
+	if err != nil {
+		return nil, err
+	}
+	err = node.initServices(odict)
+	return node, err
+}
+
+// Create the EMCY producer & consumer from the OD
+func newEMCY(bm *canopen.BusManager, logger *slog.Logger, nodeId uint8, odict *od.ObjectDictionary) (*emergency.EMCY, error) {
+	emcy, err := emergency.NewEMCY(
+		bm,
+		logger,
+		nodeId,
+		odict.Index(od.EntryErrorRegister),
+		odict.Index(od.EntryCobIdEMCY),
+		odict.Index(od.EntryInhibitTimeEMCY),
+		odict.Index(od.EntryPreDefinedErrorField),
+		nil,
+	)
+	if err != nil {
+		logger.Error("init failed [EMCY] producer", "error", err)
+		return nil, canopen.ErrOdParameters
+	}
+	return emcy, nil
+}
+
+// Enable node guarding if supported by the OD, disable it otherwise
+func (node *LocalNode) initGuarding(odict *od.ObjectDictionary) error {
+	entry100C, entry100D := odict.Index(od.EntryGuardTime), odict.Index(od.EntryLifeTimeFactor)
+	if entry100C == nil || entry100D == nil {
+		node.NMT.DisableGuarding()
+		return nil
+	}
+	err := node.NMT.EnableGuarding(entry100C, entry100D)
+	if err != nil {
+		node.logger.Error("init failed [NMT] node guarding", "error", err)
+		return err
+	}
+	node.logger.Info("[NMT] node guarding enabled")
+	return nil
+}
+
+// Initialize all the services from the OD, except NMT & EMCY
+func (node *LocalNode) initServices(odict *od.ObjectDictionary) error {
+	bm := node.BusManager
+	logger := node.logger
+	nodeId := node.id
+	emcy := node.EMCY
+
 	// Initialize HB consumer
 	hbCons, err := heartbeat.NewHBConsumer(bm, logger, emcy, odict.Index(od.EntryConsumerHeartbeatTime))
 	if err != nil {
 		logger.Error("init failed [HBConsumer]", "error", err)
-		return nil, err
+		return err
 	} else {
 		node.HBConsumer = hbCons
 	}
 	logger.Info("[HBConsumer] initialized")
 
-	// Initialize SDO server
-	// For now only one server
-	entry1200 := odict.Index(od.EntrySDOServerParameter)
+	// Initialize SDO servers, one per server parameter entry (0x1200 - 0x127F)
 	sdoServers := make([]*sdo.SDOServer, 0)
-	if entry1200 == nil {
-		logger.Warn("no [SDOServer] initialized")
-	} else {
-		server, err := sdo.NewSDOServer(bm, logger, odict, nodeId, sdoServerTimeoutMs, entry1200)
+	for index := od.EntrySDOServerParameter; index <= od.EntrySDOServerParameterEnd; index++ {
+		entry12xx := odict.Index(index)
+		if entry12xx == nil {
+			continue
+		}
+		server, err := sdo.NewSDOServer(bm, logger, odict, nodeId, node.sdoServerTimeoutMs, entry12xx)
 		if err != nil {
-			logger.Error("init failed [SDOServer]", "error", err)
-			return nil, err
-		} else {
-			sdoServers = append(sdoServers, server)
-			node.SDOServers = sdoServers
-			logger.Info("[SDOServer] initialized")
+			logger.Error("init failed [SDOServer]", "index", fmt.Sprintf("x%x", index), "error", err)
+			return err
 		}
+		sdoServers = append(sdoServers, server)
+		logger.Info("[SDOServer] initialized", "index", fmt.Sprintf("x%x", index))
+	}
+	if len(sdoServers) == 0 {
+		logger.Warn("no [SDOServer] initialized")
 	}
+	node.SDOServers = sdoServers
 
-	// Initialize SDO clients if any
-	// For now only one client
-	entry1280 := odict.Index(od.EntrySDOClientParameter)
+	// Initialize SDO clients, one per client parameter entry (0x1280 - 0x12FF)
 	sdoClients := make([]*sdo.SDOClient, 0)
-	if entry1280 == nil {
-		logger.Warn("no [SDOClient] initialized")
-	} else {
-
-		client, err := sdo.NewSDOClient(bm, logger, odict, nodeId, sdoClientTimeoutMs, entry1280)
+	for index := od.EntrySDOClientParameter; index <= od.EntrySDOClientParameterEnd; index++ {
+		entry128x := odict.Index(index)
+		if entry128x == nil {
+			continue
+		}
+		client, err := sdo.NewSDOClient(bm, logger, odict, nodeId, node.sdoClientTimeoutMs, entry128x)
 		if err != nil {
-			logger.Error("init failed [SDOClient]", "error", err)
-		} else {
-			sdoClients = append(sdoClients, client)
-			logger.Info("[SDOClient] initialized")
+			logger.Error("init failed [SDOClient]", "index", fmt.Sprintf("x%x", index), "error", err)
+			continue
 		}
-		node.SDOclients = sdoClients
+		sdoClients = append(sdoClients, client)
+		logger.Info("[SDOClient] initialized", "index", fmt.Sprintf("x%x", index))
 	}
+	if len(sdoClients) == 0 {
+		logger.Warn("no [SDOClient] initialized")
+	}
+	node.SDOclients = sdoClients
 
 	// Initialize TIME
 	time, err := t.NewTIME(bm, logger, odict.Index(od.EntryCobIdTIME), 1000) // hardcoded for now
@@ -348,15 +469,20 @@ func NewLocalNode(
 			compressed, err := createInMemoryZip("compressed.eds", odict.NewReaderSeeker())
 			if err != nil {
 				node.logger.Error("failed to compress EDS", "error", err)
-				return nil, err
+				return err
 			}
 			odict.AddReader(edsStore.Index, edsStore.Name, bytes.NewReader(compressed))
 		default:
-			return nil, fmt.Errorf("invalid EDS storage format %v", format)
+			return fmt.Errorf("invalid EDS storage format %v", format)
 		}
 	}
-	err = node.initPDO()
-	return node, err
+	// Configuration manager concise DCFs (CiA 302), if supported
+	entry1F22 := odict.Index(od.EntryConciseDCF)
+	if entry1F22 != nil {
+		entry1F22.AddExtension(node.conciseDCF, readEntry1F22, writeEntry1F22)
+		node.logger.Info("concise DCFs are writable via object 0x1F22")
+	}
+	return node.initPDO()
 }
 
 // Create an in memory zip representation of an io.Reader.
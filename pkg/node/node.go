@@ -41,6 +41,8 @@ type BaseNode struct {
 	mainCallback func(node Node)
 	id           uint8
 	rxBuffer     []byte
+	// Serializes read-modify-write accesses
+	bitsMu sync.Mutex
 }
 
 func newBaseNode(
@@ -76,7 +78,9 @@ func (node *BaseNode) GetID() uint8 {
 }
 
 func (node *BaseNode) Configurator() *config.NodeConfigurator {
-	return config.NewNodeConfigurator(node.id, node.logger, node.SDOClient)
+	conf := config.NewNodeConfigurator(node.id, node.logger, node.SDOClient)
+	conf.SetOD(node.od)
+	return conf
 }
 
 // Export EDS file with current state
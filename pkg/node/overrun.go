@@ -0,0 +1,127 @@
+package node
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/emergency"
+)
+
+// Processing loop of a [NodeProcessor]
+type ProcessingLoop uint8
+
+const (
+	LoopMain       ProcessingLoop = 1 // NMT, HB, EMCY, ... every 1ms
+	LoopBackground ProcessingLoop = 2 // SYNC & PDOs every 10ms
+)
+
+func (l ProcessingLoop) String() string {
+	switch l {
+	case LoopMain:
+		return "main"
+	case LoopBackground:
+		return "background"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint8(l))
+	}
+}
+
+// A processing cycle that took longer than its period, see [NodeProcessor.SetOverrunHook]
+type Overrun struct {
+	Loop     ProcessingLoop
+	Duration time.Duration // Execution time of the cycle
+	Period   time.Duration
+}
+
+// Execution times of a processing loop
+type loopTiming struct {
+	cycles  atomic.Uint64
+	totalNs atomic.Int64
+	maxNs   atomic.Int64
+}
+
+func (t *loopTiming) record(d time.Duration) {
+	t.cycles.Add(1)
+	t.totalNs.Add(int64(d))
+	for {
+		current := t.maxNs.Load()
+		if int64(d) <= current || t.maxNs.CompareAndSwap(current, int64(d)) {
+			return
+		}
+	}
+}
+
+func (t *loopTiming) max() time.Duration {
+	return time.Duration(t.maxNs.Load())
+}
+
+func (t *loopTiming) avg() time.Duration {
+	cycles := t.cycles.Load()
+	if cycles == 0 {
+		return 0
+	}
+	return time.Duration(t.totalNs.Load() / int64(cycles))
+}
+
+func (t *loopTiming) reset() {
+	t.cycles.Store(0)
+	t.totalNs.Store(0)
+	t.maxNs.Store(0)
+}
+
+// SetOverrunHook sets a hook called every time a processing cycle takes longer than
+// its period, e.g. for detecting that the host is too slow. It is called from the
+// processing goroutine so it should not block. nil removes the hook.
+func (c *NodeProcessor) SetOverrunHook(hook func(overrun Overrun)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrunHook = hook
+}
+
+// SetOverrunEMCY enables sending a software error EMCY ([emergency.EmGenericSoftwareError])
+// on the first overrun, for local nodes only. Error is cleared by [NodeProcessor.ResetStats].
+func (c *NodeProcessor) SetOverrunEMCY(enabled bool) {
+	c.overrunEMCY.Store(enabled)
+}
+
+// EMCY of the processed node, if it is a local node
+func (c *NodeProcessor) emcy() *emergency.EMCY {
+	local, ok := c.node.(*LocalNode)
+	if !ok {
+		return nil
+	}
+	return local.EMCY
+}
+
+// Record execution time of a processing cycle started at start
+func (c *NodeProcessor) measure(loop ProcessingLoop, start time.Time) {
+	duration := c.clock.Now().Sub(start)
+	timing, overruns, period := &c.timingMain, &c.overrunsMain, mainPeriod
+	if loop == LoopBackground {
+		timing, overruns, period = &c.timingBg, &c.overrunsBg, backgroundPeriod
+	}
+	timing.record(duration)
+	if duration <= period {
+		return
+	}
+	overruns.Add(1)
+	emcy := c.emcy()
+	if c.overrunEMCY.Load() && emcy != nil && !emcy.IsError(emergency.EmGenericSoftwareError) {
+		emcy.ErrorReport(emergency.EmGenericSoftwareError, emergency.ErrSoftwareDevice, uint32(duration.Microseconds()))
+	}
+	c.mu.Lock()
+	hook := c.overrunHook
+	c.mu.Unlock()
+	if hook != nil {
+		hook(Overrun{Loop: loop, Duration: duration, Period: period})
+	}
+}
+
+// Clear overrun EMCY, if it was sent
+func (c *NodeProcessor) clearOverrunEMCY() {
+	emcy := c.emcy()
+	if c.overrunEMCY.Load() && emcy != nil && emcy.IsError(emergency.EmGenericSoftwareError) {
+		emcy.ErrorReset(emergency.EmGenericSoftwareError, 0)
+	}
+}
@@ -0,0 +1,174 @@
+package node
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/samsamfire/gocanopen/pkg/pdo"
+)
+
+// Mapped entry of a PDO with the corresponding OD information
+type PDOMappedEntry struct {
+	config.PDOMappingParameter
+	Name string // Name of the mapped entry, empty if not found in OD (e.g. dummy entries)
+}
+
+// Live configuration of a PDO as read inside of OD
+type PDOConfiguration struct {
+	config.PDOConfigurationParameter
+	Nb      uint16 // PDO number, RPDOs are 1 - 256 and TPDOs 257 - 512
+	IsRPDO  bool
+	Enabled bool
+	CobId   uint32 // Raw COB-ID value, including control bits
+	Entries []PDOMappedEntry
+}
+
+// Length of the mapped data in bytes
+func (conf *PDOConfiguration) Length() int {
+	length := 0
+	for _, entry := range conf.Entries {
+		length += int(entry.LengthBits) / 8
+	}
+	return length
+}
+
+// Read value from OD, going through extension if any
+func readUint(entry *od.Entry, subIndex uint8, b []byte) (uint64, error) {
+	err := entry.ReadExactly(subIndex, b, false)
+	if err != nil {
+		return 0, err
+	}
+	switch len(b) {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.LittleEndian.Uint16(b)), nil
+	default:
+		return uint64(binary.LittleEndian.Uint32(b)), nil
+	}
+}
+
+// Read a single PDO configuration from OD
+func (node *BaseNode) readPDOConfiguration(pdoNb uint16, entryComm *od.Entry, entryMap *od.Entry) (PDOConfiguration, error) {
+	conf := PDOConfiguration{Nb: pdoNb, IsRPDO: pdoNb <= pdo.MaxRpdoNumber}
+	cobId, err := readUint(entryComm, 1, make([]byte, 4))
+	if err != nil {
+		return conf, err
+	}
+	conf.CobId = uint32(cobId)
+	conf.CanId = uint16(cobId & 0x7FF)
+	conf.Enabled = (cobId>>31)&0b1 == 0
+	transType, err := readUint(entryComm, 2, make([]byte, 1))
+	if err != nil {
+		return conf, err
+	}
+	conf.TransmissionType = uint8(transType)
+	// Optional
+	inhibitTime, _ := readUint(entryComm, 3, make([]byte, 2))
+	conf.InhibitTime = uint16(inhibitTime)
+	// Optional
+	eventTimer, _ := readUint(entryComm, 5, make([]byte, 2))
+	conf.EventTimer = uint16(eventTimer)
+
+	nbMapped, err := readUint(entryMap, 0, make([]byte, 1))
+	if err != nil {
+		return conf, err
+	}
+	// MPDOs have no mapping (SAM) or a single one (DAM)
+	switch uint8(nbMapped) {
+	case pdo.MPDOModeSAM:
+		nbMapped = 0
+	case pdo.MPDOModeDAM:
+		nbMapped = 1
+	}
+	conf.Mappings = make([]config.PDOMappingParameter, 0, nbMapped)
+	conf.Entries = make([]PDOMappedEntry, 0, nbMapped)
+	for i := range uint8(nbMapped) {
+		rawMap, err := readUint(entryMap, i+1, make([]byte, 4))
+		if err != nil {
+			return conf, err
+		}
+		mapping := config.PDOMappingParameter{
+			Index:      uint16(rawMap >> 16),
+			Subindex:   uint8(rawMap >> 8),
+			LengthBits: uint8(rawMap),
+		}
+		mapped := PDOMappedEntry{PDOMappingParameter: mapping}
+		entry := node.od.Index(mapping.Index)
+		if entry != nil {
+			mapped.Name = entry.Name
+			variable, err := entry.SubIndex(mapping.Subindex)
+			if err == nil && entry.ObjectType != od.ObjectTypeVAR && entry.ObjectType != od.ObjectTypeDOMAIN {
+				mapped.Name = fmt.Sprintf("%s.%s", entry.Name, variable.Name)
+			}
+		}
+		conf.Mappings = append(conf.Mappings, mapping)
+		conf.Entries = append(conf.Entries, mapped)
+	}
+	return conf, nil
+}
+
+// PDOConfiguration returns the current RPDO and TPDO configurations
+// as found inside of the node's OD. Contrary to the EDS default values,
+// this reflects any change made at runtime e.g. via SDO.
+func (node *BaseNode) PDOConfiguration() (rpdos []PDOConfiguration, tpdos []PDOConfiguration, err error) {
+	for i := range pdo.MaxRpdoNumber {
+		entryComm := node.od.Index(od.EntryRPDOCommunicationStart + i)
+		entryMap := node.od.Index(od.EntryRPDOMappingStart + i)
+		if entryComm == nil || entryMap == nil {
+			break
+		}
+		conf, err := node.readPDOConfiguration(pdo.MinRpdoNumber+i, entryComm, entryMap)
+		if err != nil {
+			return rpdos, tpdos, err
+		}
+		rpdos = append(rpdos, conf)
+	}
+	for i := range pdo.MaxTpdoNumber - pdo.MaxRpdoNumber {
+		entryComm := node.od.Index(od.EntryTPDOCommunicationStart + i)
+		entryMap := node.od.Index(od.EntryTPDOMappingStart + i)
+		if entryComm == nil || entryMap == nil {
+			break
+		}
+		conf, err := node.readPDOConfiguration(pdo.MinTpdoNumber+i, entryComm, entryMap)
+		if err != nil {
+			return rpdos, tpdos, err
+		}
+		tpdos = append(tpdos, conf)
+	}
+	return rpdos, tpdos, nil
+}
+
+// Callback called with TPDO number (257 - 512) and transmitted data
+type TPDOCaptureCallback func(pdoNb uint16, data []byte)
+
+// InjectRPDO feeds data to an RPDO (1 - 256) as if it had been received on the bus.
+// It goes through the same path as bus reception, including length validation
+// and OD write, which happens on next RPDO processing when node is operational.
+// This is mainly useful for unit testing a device application without a bus.
+func (node *LocalNode) InjectRPDO(pdoNb uint16, data []byte) error {
+	if pdoNb < pdo.MinRpdoNumber || int(pdoNb) > len(node.RPDOs) {
+		return canopen.ErrIllegalArgument
+	}
+	return node.RPDOs[pdoNb-pdo.MinRpdoNumber].Inject(data)
+}
+
+// CaptureTPDO registers a callback called every time a TPDO is transmitted.
+// The callback should not block. Passing nil removes the capture.
+func (node *LocalNode) CaptureTPDO(callback TPDOCaptureCallback) {
+	for i, tpdo := range node.TPDOs {
+		if callback == nil {
+			tpdo.SetSendHook(nil)
+			continue
+		}
+		pdoNb := pdo.MinTpdoNumber + uint16(i)
+		tpdo.SetSendHook(func(frame canopen.Frame) {
+			data := make([]byte, frame.DLC)
+			copy(data, frame.Data[:])
+			callback(pdoNb, data)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package node
+
+import (
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// ReloadOD replaces the OD of the node at runtime, e.g. for updating the configuration
+// of a gateway node. EMCY, heartbeat consumer, SDO servers & clients, SYNC, TIME & PDOs
+// are created again from the new OD, NMT state is kept and the bus is not disconnected.
+// The node should be in pre-operational or stopped state, otherwise [canopen.ErrWrongNMTState]
+// is returned. If the new OD is invalid, the node keeps using the previous one.
+//
+// Node processing should be stopped while reloading, this is done by ReloadLocalNode
+// of the network package. Hooks set on the previous objects, PDO bindings & parameter
+// store are not kept and should be set again.
+func (node *LocalNode) ReloadOD(odict *od.ObjectDictionary) error {
+	if odict == nil {
+		return canopen.ErrIllegalArgument
+	}
+	state := node.NMT.GetInternalState()
+	if state != nmt.StatePreOperational && state != nmt.StateStopped {
+		return canopen.ErrWrongNMTState
+	}
+	base, err := newBaseNode(node.BusManager, node.logger, odict, node.id)
+	if err != nil {
+		return err
+	}
+	base.mainCallback = node.mainCallback
+	fresh := &LocalNode{
+		BaseNode:           base,
+		NodeIdUnconfigured: node.NodeIdUnconfigured,
+		NMT:                node.NMT,
+		conciseDCF:         newConciseDCFStore(),
+		sdoServerTimeoutMs: node.sdoServerTimeoutMs,
+		sdoClientTimeoutMs: node.sdoClientTimeoutMs,
+	}
+	// Previous objects are kept until the new ones are successfully created
+	fresh.EMCY, err = newEMCY(node.BusManager, node.logger, node.id, odict)
+	if err == nil {
+		err = fresh.initServices(odict)
+	}
+	if err == nil {
+		err = node.NMT.Rebind(fresh.EMCY, odict.Index(od.EntryProducerHeartbeatTime))
+	}
+	if err != nil {
+		fresh.unsubscribe()
+		return err
+	}
+	err = fresh.initGuarding(odict)
+	if err != nil {
+		node.logger.Warn("node guarding disabled after reload", "error", err)
+		node.NMT.DisableGuarding()
+	}
+	node.unsubscribe()
+
+	node.BaseNode = fresh.BaseNode
+	node.EMCY = fresh.EMCY
+	node.HBConsumer = fresh.HBConsumer
+	node.SDOServers = fresh.SDOServers
+	node.SDOclients = fresh.SDOclients
+	node.TPDOs = fresh.TPDOs
+	node.RPDOs = fresh.RPDOs
+	node.SYNC = fresh.SYNC
+	node.TIME = fresh.TIME
+	node.conciseDCF = fresh.conciseDCF
+	node.bindings.mu.Lock()
+	node.bindings.tpdo = nil
+	node.bindings.rpdo = nil
+	node.bindings.mu.Unlock()
+	node.parameters.Store(nil)
+	node.logger.Info("OD reloaded")
+	return nil
+}
+
+// Stop receiving frames for all the objects of the node, except NMT
+func (node *LocalNode) unsubscribe() {
+	listeners := []canopen.FrameListener{node.SDOClient}
+	if node.EMCY != nil {
+		listeners = append(listeners, node.EMCY)
+	}
+	for _, server := range node.SDOServers {
+		listeners = append(listeners, server)
+	}
+	for _, client := range node.SDOclients {
+		listeners = append(listeners, client)
+	}
+	for _, rpdo := range node.RPDOs {
+		listeners = append(listeners, rpdo)
+	}
+	if node.SYNC != nil {
+		listeners = append(listeners, node.SYNC)
+	}
+	if node.TIME != nil {
+		listeners = append(listeners, node.TIME)
+	}
+	for _, listener := range listeners {
+		node.Unsubscribe(listener)
+	}
+	if node.HBConsumer != nil {
+		node.HBConsumer.Detach()
+	}
+}
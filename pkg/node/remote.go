@@ -3,10 +3,12 @@ package node
 import (
 	"errors"
 	"log/slog"
+	"time"
 
 	canopen "github.com/samsamfire/gocanopen"
 	"github.com/samsamfire/gocanopen/pkg/config"
 	"github.com/samsamfire/gocanopen/pkg/emergency"
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
 	"github.com/samsamfire/gocanopen/pkg/nmt"
 	"github.com/samsamfire/gocanopen/pkg/od"
 	"github.com/samsamfire/gocanopen/pkg/pdo"
@@ -22,18 +24,24 @@ import (
 //   - SDOClient for reading / writing to remote node with given EDS
 //   - RPDO for updating a local OD with the TPDOs from the remote node
 //   - SYNC consumer
+//   - heartbeat monitoring
+//
+// Only the SDO client is created with the node, the other subsystems are
+// optional & created when started : [RemoteNode.StartPDOs] (with the SYNC consumer)
+// and [RemoteNode.StartHeartbeatMonitor]. [RemoteNode.Stop] stops all of them.
 //
 // A RemoteNode has the same id as the remote node that it controls
 // however, being a direct local representation it may only be accessed
 // locally.
 type RemoteNode struct {
 	*BaseNode
-	remoteOd *od.ObjectDictionary // Remote node od, this does not change
-	client   *sdo.SDOClient       // A unique sdoClient shared between localCtrl & remoteCtrl
-	rpdos    []*pdo.RPDO          // Local RPDOs (corresponds to remote TPDOs)
-	tpdos    []*pdo.TPDO          // Local TPDOs (corresponds to remote RPDOs)
-	sync     *sync.SYNC           // Sync consumer (for synchronous PDOs)
-	emcy     *emergency.EMCY      // Emergency consumer (fake producer for logging internal errors)
+	remoteOd  *od.ObjectDictionary // Remote node od, this does not change
+	client    *sdo.SDOClient       // A unique sdoClient shared between localCtrl & remoteCtrl
+	rpdos     []*pdo.RPDO          // Local RPDOs (corresponds to remote TPDOs)
+	tpdos     []*pdo.TPDO          // Local TPDOs (corresponds to remote RPDOs)
+	sync      *sync.SYNC           // Sync consumer (for synchronous PDOs)
+	emcy      *emergency.EMCY      // Emergency consumer (fake producer for logging internal errors)
+	heartbeat *heartbeat.Monitor   // Heartbeat monitor of the remote node
 }
 
 func (node *RemoteNode) ProcessTPDO(syncWas bool, timeDifferenceUs uint32, timerNextUs *uint32) {
@@ -55,6 +63,8 @@ func (node *RemoteNode) ProcessRPDO(syncWas bool, timeDifferenceUs uint32, timer
 }
 
 func (node *RemoteNode) ProcessSYNC(timeDifferenceUs uint32, timerNextUs *uint32) bool {
+	node.mu.Lock()
+	defer node.mu.Unlock()
 	syncWas := false
 	if node.sync != nil {
 		event := node.sync.Process(true, timeDifferenceUs, timerNextUs)
@@ -108,36 +118,51 @@ func NewRemoteNode(
 		return nil, err
 	}
 	node.client = client
-	// Create a new SYNC object
+	return node, nil
+}
+
+// Create the SYNC consumer & the EMCY used by PDOs, if not already done.
+// Lock should be held.
+func (node *RemoteNode) startSYNC() error {
+	if node.emcy == nil {
+		// Empty EMCY, only used for logging for now
+		node.emcy = &emergency.EMCY{}
+	}
+	if node.sync != nil {
+		return nil
+	}
+	// SYNC producer of the remote OD is disabled
 	node.od.AddSYNC()
-	// Initialize SYNC
 	sync, err := sync.NewSYNC(
-		bm,
-		logger,
+		node.BusManager,
+		node.logger,
 		nil,
-		node.od.Index(0x1005),
-		node.od.Index(0x1006),
-		node.od.Index(0x1007),
-		node.od.Index(0x1019),
+		node.od.Index(od.EntryCobIdSYNC),
+		node.od.Index(od.EntryCommunicationCyclePeriod),
+		node.od.Index(od.EntrySynchronousWindowLength),
+		node.od.Index(od.EntrySynchronousCounterOverflow),
 	)
 	if err != nil {
-		logger.Error("error when initialising SYNC object", "error", err)
-		return nil, err
+		node.logger.Error("error when initialising SYNC object", "error", err)
+		return err
 	}
 	node.sync = sync
-
-	// Add empty EMCY, only used for logging for now
-	node.emcy = &emergency.EMCY{}
-
-	return node, nil
+	return nil
 }
 
 // Initialize PDOs according to either local OD mapping or remote OD mapping
-// A TPDO from the distant node corresponds to an RPDO on this node and vice-versa
+// A TPDO from the distant node corresponds to an RPDO on this node and vice-versa.
+// The SYNC consumer is started as well, PDOs already started are replaced.
 func (node *RemoteNode) StartPDOs(useLocal bool) error {
 	node.mu.Lock()
 	defer node.mu.Unlock()
 
+	node.stopPDOs()
+	err := node.startSYNC()
+	if err != nil {
+		return err
+	}
+
 	var conf *config.NodeConfigurator
 
 	localConf := config.NewNodeConfigurator(0, node.logger, node.client)
@@ -174,8 +199,8 @@ func (node *RemoteNode) StartPDOs(useLocal bool) error {
 			node.od,
 			node.emcy, // Empty emergency object used for logging
 			node.sync,
-			node.GetOD().Index(0x1400+i),
-			node.GetOD().Index(0x1600+i),
+			node.GetOD().Index(od.RPDOCommParamIndex(uint16(i)+1)),
+			node.GetOD().Index(od.RPDOMappingParamIndex(uint16(i)+1)),
 			0,
 		)
 		if err != nil {
@@ -209,8 +234,8 @@ func (node *RemoteNode) StartPDOs(useLocal bool) error {
 			node.od,
 			node.emcy, // Empty emergency object used for logging
 			node.sync,
-			node.GetOD().Index(0x1800+i),
-			node.GetOD().Index(0x1A00+i),
+			node.GetOD().Index(od.TPDOCommParamIndex(uint16(i)+1)),
+			node.GetOD().Index(od.TPDOMappingParamIndex(uint16(i)+1)),
 			0,
 		)
 		if err != nil {
@@ -225,3 +250,103 @@ func (node *RemoteNode) StartPDOs(useLocal bool) error {
 
 	return nil
 }
+
+// Stop processing of PDOs started with [RemoteNode.StartPDOs]
+func (node *RemoteNode) StopPDOs() {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.stopPDOs()
+}
+
+// Lock should be held
+func (node *RemoteNode) stopPDOs() {
+	for _, rpdo := range node.rpdos {
+		node.BusManager.Unsubscribe(rpdo)
+	}
+	node.rpdos = nil
+	node.tpdos = nil
+}
+
+// StartHeartbeatMonitor starts monitoring the heartbeats of the remote node.
+// The heartbeat is considered lost after timeout, 0 disables loss detection.
+// If already started, only the timeout is updated.
+func (node *RemoteNode) StartHeartbeatMonitor(timeout time.Duration) error {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	monitor, err := node.heartbeatMonitor()
+	if err != nil {
+		return err
+	}
+	monitor.SetTimeout(node.id, timeout)
+	return nil
+}
+
+// StopHeartbeatMonitor stops monitoring the heartbeats of the remote node.
+// Registered listeners are removed.
+func (node *RemoteNode) StopHeartbeatMonitor() {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.stopHeartbeatMonitor()
+}
+
+// Lock should be held
+func (node *RemoteNode) stopHeartbeatMonitor() {
+	if node.heartbeat == nil {
+		return
+	}
+	node.heartbeat.Stop()
+	node.BusManager.Unsubscribe(node.heartbeat)
+	node.heartbeat = nil
+}
+
+// Heartbeat monitor, created if not started yet. Lock should be held.
+func (node *RemoteNode) heartbeatMonitor() (*heartbeat.Monitor, error) {
+	if node.heartbeat != nil {
+		return node.heartbeat, nil
+	}
+	monitor, err := heartbeat.NewNodeMonitor(node.BusManager, node.id)
+	if err != nil {
+		return nil, err
+	}
+	node.heartbeat = monitor
+	return monitor, nil
+}
+
+// OnHeartbeatEvent registers a listener for the heartbeat events of the remote node,
+// e.g. boot-up or NMT state changes. The heartbeat monitor is started if needed,
+// without loss detection, see [RemoteNode.StartHeartbeatMonitor].
+// Listeners are called from the CAN reception and should not block.
+func (node *RemoteNode) OnHeartbeatEvent(listener heartbeat.EventListener) error {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	monitor, err := node.heartbeatMonitor()
+	if err != nil {
+		return err
+	}
+	monitor.OnNodeEvent(node.id, listener)
+	return nil
+}
+
+// HeartbeatState returns the last NMT state reported in the heartbeat of the remote node,
+// nmt.StateUnknown if none, if heartbeat was lost or if the monitor is not started.
+func (node *RemoteNode) HeartbeatState() uint8 {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if node.heartbeat == nil {
+		return nmt.StateUnknown
+	}
+	return node.heartbeat.State(node.id)
+}
+
+// Stop stops every subsystem of the node : PDOs, SYNC consumer & heartbeat monitor.
+// The SDO client can still be used, and subsystems can be started again.
+func (node *RemoteNode) Stop() {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.stopPDOs()
+	if node.sync != nil {
+		node.BusManager.Unsubscribe(node.sync)
+		node.sync = nil
+	}
+	node.stopHeartbeatMonitor()
+}
@@ -121,8 +121,10 @@ func (node *BaseNode) ReadString(index any, subindex any) (value string, e error
 	}
 	// Cast to correct type
 	switch dataType {
-	case od.OCTET_STRING, od.VISIBLE_STRING, od.UNICODE_STRING:
+	case od.OCTET_STRING, od.VISIBLE_STRING:
 		return string(data), nil
+	case od.UNICODE_STRING:
+		return od.DecodeUnicodeString(data)
 	default:
 		return "", od.ErrTypeMismatch
 	}
@@ -139,7 +141,7 @@ func (node *BaseNode) ReadRaw(index uint16, subIndex uint8, data []byte) (int, e
 // Write an entry to a remote node
 // index and subindex can either be strings or integers
 // this method requires the corresponding node OD to be loaded
-// value should correspond to the expected datatype
+// value should correspond to the expected datatype, see [od.EncodeFromType]
 func (node *BaseNode) Write(index any, subindex any, value any) error {
 	// Find corresponding Variable inside OD
 	// This will be used to determine information on the expected value
@@ -148,7 +150,11 @@ func (node *BaseNode) Write(index any, subindex any, value any) error {
 	if err != nil {
 		return err
 	}
-	return node.SDOClient.WriteRaw(node.id, entry.Index, odVar.SubIndex, value, false)
+	encoded, err := od.EncodeFromType(value, odVar.DataType)
+	if err != nil {
+		return err
+	}
+	return node.SDOClient.WriteRaw(node.id, entry.Index, odVar.SubIndex, encoded, false)
 }
 
 // Write an entry to a remote node
@@ -158,3 +164,38 @@ func (node *BaseNode) Write(index any, subindex any, value any) error {
 func (node *BaseNode) WriteRaw(index uint16, subIndex uint8, data []byte) error {
 	return node.SDOClient.WriteRaw(node.id, index, subIndex, data, false)
 }
+
+// Read the bits selected by mask of an integer entry,
+// e.g. DS402 statusword bits. Returned value is not shifted.
+// this method does not require corresponding OD to be loaded
+func (node *BaseNode) ReadBits(index uint16, subIndex uint8, mask uint64) (uint64, error) {
+	// One extra byte to detect entries that are too long
+	data := make([]byte, 9)
+	n, err := node.ReadRaw(index, subIndex, data)
+	if err != nil {
+		return 0, err
+	}
+	return od.GetBits(data[:n], mask)
+}
+
+// Update the bits selected by mask of an integer entry,
+// e.g. DS402 controlword bits, other bits are left untouched.
+// This does a read-modify-write, accesses to the same node from this
+// process are serialized but concurrent accesses from other
+// CANopen masters are not detected.
+// this method does not require corresponding OD to be loaded
+func (node *BaseNode) WriteBits(index uint16, subIndex uint8, mask uint64, value uint64) error {
+	node.bitsMu.Lock()
+	defer node.bitsMu.Unlock()
+	// One extra byte to detect entries that are too long
+	data := make([]byte, 9)
+	n, err := node.ReadRaw(index, subIndex, data)
+	if err != nil {
+		return err
+	}
+	err = od.PutBits(data[:n], mask, value)
+	if err != nil {
+		return err
+	}
+	return node.WriteRaw(index, subIndex, data[:n])
+}
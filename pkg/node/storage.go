@@ -0,0 +1,143 @@
+package node
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync/atomic"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+var ErrNoParameterStore = errors.New("no parameter store configured")
+
+// Store & restore default parameters of a local node
+type parameterStorage struct {
+	node     *LocalNode
+	store    od.ParameterStore
+	autoSave atomic.Bool
+}
+
+// SetParameterStorage enables store parameters (0x1010) & restore default
+// parameters (0x1011) with the given persistence backend, e.g. [od.NewJSONFileStorage].
+// This is the same as [LocalNode.SetParameterStore] with [od.NewParameterStore].
+func (node *LocalNode) SetParameterStorage(storage od.ParameterStorage) error {
+	return node.SetParameterStore(od.NewParameterStore(node.od, storage))
+}
+
+// SetParameterStore enables store parameters (0x1010) & restore default
+// parameters (0x1011) with the given store.
+// Writing "save" to a sub-index of 0x1010 stores the corresponding parameter group.
+// Writing "load" to a sub-index of 0x1011 removes it from storage, so that
+// default values are used after next restart.
+// Stored values should be loaded with [od.ParameterStore.Load] before
+// creating the node, this is done by CreateLocalNodeWithStorage of the network package.
+func (node *LocalNode) SetParameterStore(store od.ParameterStore) error {
+	entry1010 := node.od.Index(od.EntryStoreParameters)
+	if entry1010 == nil {
+		return od.ErrIdxNotExist
+	}
+	params := &parameterStorage{node: node, store: store}
+	entry1010.AddExtension(params, od.ReadEntryDefault, writeEntry1010)
+	entry1011 := node.od.Index(od.EntryRestoreDefaultParameters)
+	if entry1011 != nil {
+		entry1011.AddExtension(params, od.ReadEntryDefault, writeEntry1011)
+	}
+	node.parameters.Store(params)
+	node.logger.Info("parameters can be stored via object 0x1010")
+	return nil
+}
+
+// ParameterStore returns the store set with [LocalNode.SetParameterStore], nil if none
+func (node *LocalNode) ParameterStore() od.ParameterStore {
+	params := node.parameters.Load()
+	if params == nil {
+		return nil
+	}
+	return params.store
+}
+
+// SaveParameters stores the current values of a parameter group,
+// same as writing "save" to 0x1010 over SDO
+func (node *LocalNode) SaveParameters(group uint8) error {
+	params := node.parameters.Load()
+	if params == nil {
+		return ErrNoParameterStore
+	}
+	return params.store.Save(group)
+}
+
+// SetSaveOnShutdown configures whether all parameters are stored
+// when the network is shutdown gracefully.
+// This requires a parameter store.
+func (node *LocalNode) SetSaveOnShutdown(enabled bool) error {
+	params := node.parameters.Load()
+	if params == nil {
+		return ErrNoParameterStore
+	}
+	params.autoSave.Store(enabled)
+	return nil
+}
+
+// SaveOnShutdown returns true if parameters should be stored on shutdown
+func (node *LocalNode) SaveOnShutdown() bool {
+	params := node.parameters.Load()
+	return params != nil && params.autoSave.Load()
+}
+
+// Decode the signature written to 0x1010 or 0x1011
+func parameterSignature(stream *od.Stream, data []byte, countWritten *uint16) (*parameterStorage, uint32, error) {
+	if stream == nil || data == nil || countWritten == nil {
+		return nil, 0, od.ErrDevIncompat
+	}
+	if stream.Subindex == 0 {
+		return nil, 0, od.ErrReadonly
+	}
+	if stream.Subindex > od.ParametersManufacturer {
+		return nil, 0, od.ErrSubNotExist
+	}
+	params, ok := stream.Object.(*parameterStorage)
+	if !ok {
+		return nil, 0, od.ErrDevIncompat
+	}
+	if len(data) != 4 {
+		return nil, 0, od.ErrTypeMismatch
+	}
+	*countWritten = 4
+	return params, binary.LittleEndian.Uint32(data), nil
+}
+
+// [SDO] Store parameters, signature is "save"
+func writeEntry1010(stream *od.Stream, data []byte, countWritten *uint16) error {
+	params, signature, err := parameterSignature(stream, data, countWritten)
+	if err != nil {
+		return err
+	}
+	if signature != od.SignatureSave {
+		return od.ErrDataTransf
+	}
+	err = params.store.Save(stream.Subindex)
+	if err != nil {
+		params.node.logger.Warn("failed to store parameters", "group", stream.Subindex, "error", err)
+		return od.ErrHw
+	}
+	params.node.logger.Info("stored parameters", "group", stream.Subindex)
+	return nil
+}
+
+// [SDO] Restore default parameters, signature is "load"
+func writeEntry1011(stream *od.Stream, data []byte, countWritten *uint16) error {
+	params, signature, err := parameterSignature(stream, data, countWritten)
+	if err != nil {
+		return err
+	}
+	if signature != od.SignatureLoad {
+		return od.ErrDataTransf
+	}
+	err = params.store.Restore(stream.Subindex)
+	if err != nil {
+		params.node.logger.Warn("failed to restore default parameters", "group", stream.Subindex, "error", err)
+		return od.ErrHw
+	}
+	params.node.logger.Info("default parameters restored on next restart", "group", stream.Subindex)
+	return nil
+}
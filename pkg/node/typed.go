@@ -0,0 +1,160 @@
+package node
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Maximum size of strings & octet strings read with [ReadValue]
+const MaxValueSize = 1000
+
+// An ObjectAccessor reads & writes raw OD values of a node,
+// it is implemented by [LocalNode] and [RemoteNode].
+// It backs the typed accessors generated by cmd/canopen-gen.
+type ObjectAccessor interface {
+	ReadRaw(index uint16, subIndex uint8, data []byte) (int, error)
+	WriteRaw(index uint16, subIndex uint8, data []byte) error
+}
+
+// Go types of the CiA 301 basic data types.
+// TIME_OF_DAY is a time.Time & TIME_DIFFERENCE a time.Duration.
+type Value interface {
+	bool | uint8 | uint16 | uint32 | uint64 | int8 | int16 | int32 | int64 |
+		float32 | float64 | string | []byte | UnicodeString | time.Time | time.Duration
+}
+
+// A UNICODE_STRING value, encoded in UTF-16 on the node
+type UnicodeString string
+
+// Check that a fixed size value has the expected length
+func checkLength(data []byte, length int) error {
+	if len(data) > length {
+		return od.ErrDataLong
+	}
+	if len(data) < length {
+		return od.ErrDataShort
+	}
+	return nil
+}
+
+// ReadValue reads an entry of a node and decodes it as T.
+// This method does not require corresponding OD to be loaded,
+// T should match the data type of the entry.
+func ReadValue[T Value](node ObjectAccessor, index uint16, subIndex uint8) (T, error) {
+	var value T
+	var data []byte
+	switch any(value).(type) {
+	case string, []byte, UnicodeString:
+		data = make([]byte, MaxValueSize+1)
+	default:
+		// One extra byte to detect entries that are too long
+		data = make([]byte, 9)
+	}
+	n, err := node.ReadRaw(index, subIndex, data)
+	if err != nil {
+		return value, err
+	}
+	data = data[:n]
+	switch v := any(&value).(type) {
+	case *bool:
+		err = checkLength(data, 1)
+		*v = err == nil && data[0] != 0
+	case *uint8:
+		err = checkLength(data, 1)
+		if err == nil {
+			*v = data[0]
+		}
+	case *int8:
+		err = checkLength(data, 1)
+		if err == nil {
+			*v = int8(data[0])
+		}
+	case *uint16:
+		err = checkLength(data, 2)
+		if err == nil {
+			*v = binary.LittleEndian.Uint16(data)
+		}
+	case *int16:
+		err = checkLength(data, 2)
+		if err == nil {
+			*v = int16(binary.LittleEndian.Uint16(data))
+		}
+	case *uint32:
+		err = checkLength(data, 4)
+		if err == nil {
+			*v = binary.LittleEndian.Uint32(data)
+		}
+	case *int32:
+		err = checkLength(data, 4)
+		if err == nil {
+			*v = int32(binary.LittleEndian.Uint32(data))
+		}
+	case *float32:
+		err = checkLength(data, 4)
+		if err == nil {
+			*v = math.Float32frombits(binary.LittleEndian.Uint32(data))
+		}
+	case *uint64:
+		err = checkLength(data, 8)
+		if err == nil {
+			*v = binary.LittleEndian.Uint64(data)
+		}
+	case *int64:
+		err = checkLength(data, 8)
+		if err == nil {
+			*v = int64(binary.LittleEndian.Uint64(data))
+		}
+	case *float64:
+		err = checkLength(data, 8)
+		if err == nil {
+			*v = math.Float64frombits(binary.LittleEndian.Uint64(data))
+		}
+	case *string:
+		if len(data) > MaxValueSize {
+			return value, od.ErrDataLong
+		}
+		*v = string(data)
+	case *[]byte:
+		if len(data) > MaxValueSize {
+			return value, od.ErrDataLong
+		}
+		*v = data
+	case *UnicodeString:
+		if len(data) > MaxValueSize {
+			return value, od.ErrDataLong
+		}
+		var decoded string
+		decoded, err = od.DecodeUnicodeString(data)
+		*v = UnicodeString(decoded)
+	case *time.Time:
+		*v, err = od.DecodeTimeOfDay(data)
+	case *time.Duration:
+		*v, err = od.DecodeTimeDifference(data)
+	}
+	return value, err
+}
+
+// WriteValue encodes value & writes it to an entry of a node.
+// This method does not require corresponding OD to be loaded,
+// T should match the data type of the entry.
+func WriteValue[T Value](node ObjectAccessor, index uint16, subIndex uint8, value T) error {
+	var data []byte
+	if v, ok := any(value).(bool); ok {
+		data = []byte{0}
+		if v {
+			data[0] = 1
+		}
+	} else if v, ok := any(value).(UnicodeString); ok {
+		data = od.EncodeUnicodeString(string(v))
+	} else {
+		encoded, err := od.EncodeFromGeneric(value)
+		if err != nil {
+			return err
+		}
+		data = encoded
+	}
+	return node.WriteRaw(index, subIndex, data)
+}
@@ -0,0 +1,89 @@
+package od
+
+// BitMask returns a mask of length bits starting at bit offset,
+// e.g. BitMask(4, 3) returns 0b1110000.
+func BitMask(offset uint8, length uint8) uint64 {
+	if length >= 64 {
+		return ^uint64(0) << offset
+	}
+	return ((1 << length) - 1) << offset
+}
+
+// GetBits returns the bits selected by mask of a little endian
+// encoded value, e.g. an UNSIGNED16 controlword.
+// Returned value is not shifted.
+func GetBits(data []byte, mask uint64) (uint64, error) {
+	err := checkBitsAccess(data, mask)
+	if err != nil {
+		return 0, err
+	}
+	return decodeBits(data) & mask, nil
+}
+
+// PutBits updates the bits selected by mask of a little endian
+// encoded value, other bits are left untouched.
+// value is not shifted, bits of value outside of mask are ignored.
+func PutBits(data []byte, mask uint64, value uint64) error {
+	err := checkBitsAccess(data, mask)
+	if err != nil {
+		return err
+	}
+	current := decodeBits(data)
+	current = (current &^ mask) | (value & mask)
+	for i := range data {
+		data[i] = byte(current >> (8 * i))
+	}
+	return nil
+}
+
+func checkBitsAccess(data []byte, mask uint64) error {
+	if len(data) == 0 || len(data) > 8 {
+		return ErrTypeMismatch
+	}
+	if len(data) < 8 && mask>>(8*len(data)) != 0 {
+		return ErrValueHigh
+	}
+	return nil
+}
+
+func decodeBits(data []byte) uint64 {
+	value := uint64(0)
+	for i, b := range data {
+		value |= uint64(b) << (8 * i)
+	}
+	return value
+}
+
+func isBitsAccessible(dataType uint8) bool {
+	switch dataType {
+	case BOOLEAN, UNSIGNED8, UNSIGNED16, UNSIGNED32, UNSIGNED64,
+		INTEGER8, INTEGER16, INTEGER32, INTEGER64:
+		return true
+	default:
+		return false
+	}
+}
+
+// Bits returns the bits selected by mask, e.g. DS402 statusword bits.
+// Only integer and boolean variables are supported.
+func (variable *Variable) Bits(mask uint64) (uint64, error) {
+	if !isBitsAccessible(variable.DataType) {
+		return 0, ErrTypeMismatch
+	}
+	variable.mu.RLock()
+	defer variable.mu.RUnlock()
+	return GetBits(variable.value, mask)
+}
+
+// SetBits atomically updates the bits selected by mask, e.g. DS402 controlword bits.
+// value is not shifted, other bits are left untouched.
+// Like [ShadowRecord], this writes the value directly & bypasses any extension.
+// Only integer and boolean variables are supported.
+func (variable *Variable) SetBits(mask uint64, value uint64) error {
+	if !isBitsAccessible(variable.DataType) {
+		return ErrTypeMismatch
+	}
+	variable.mu.Lock()
+	defer variable.mu.Unlock()
+	return PutBits(variable.value, mask, value)
+}
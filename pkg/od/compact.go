@@ -0,0 +1,142 @@
+package od
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CiA 306 allows describing objects in a compact way :
+//
+//   - an ARRAY with CompactSubObj=N has no sub-object sections. Sub-objects 1 to N share
+//     the data type, access type, PDO mapping & default value of the object section.
+//     Sub-object 0 holds N. Names can be given in a [xxxxName] section and the values
+//     of a DCF in a [xxxxValue] section, e.g. 1=value of sub-object 1.
+//   - with CompactPDO in [DeviceInfo], PDOs counted by NrOfRXPDO & NrOfTXPDO
+//     may not be described at all.
+
+// Maximum value of CompactSubObj, sub-object 0 is added
+const compactSubObjMax = 0xFE
+
+// Sections holding names & values of compact ARRAY sub-objects, e.g. [1003Name]
+const (
+	compactNameSuffix  = "name"
+	compactValueSuffix = "value"
+)
+
+// Create an ARRAY with sub-objects 1 to nbSubs using the attributes & values of template.
+// Sub-objects are named after the object, e.g. "Pre-defined error field1".
+func newCompactArray(name string, nbSubs uint8, template *Variable) *VariableList {
+	nbSubs = min(nbSubs, compactSubObjMax)
+	array := NewArray(nbSubs + 1)
+	array.Variables[0] = &Variable{
+		Name:         "NrOfObjects",
+		DataType:     UNSIGNED8,
+		Attribute:    AttributeSdoR,
+		valueDefault: []byte{nbSubs},
+		value:        []byte{nbSubs},
+	}
+	for subindex := uint8(1); subindex <= nbSubs; subindex++ {
+		variable := &Variable{
+			Name:            fmt.Sprintf("%s%d", name, subindex),
+			SubIndex:        subindex,
+			DataType:        template.DataType,
+			Attribute:       template.Attribute,
+			StorageLocation: template.StorageLocation,
+			valueDefault:    make([]byte, len(template.valueDefault)),
+			value:           make([]byte, len(template.value)),
+			lowLimit:        template.lowLimit,
+			highLimit:       template.highLimit,
+		}
+		copy(variable.valueDefault, template.valueDefault)
+		copy(variable.value, template.value)
+		array.Variables[subindex] = variable
+	}
+	return array
+}
+
+// Replace the object of entry by a compact ARRAY, entry object should be the template
+func (entry *Entry) expandCompact(nbSubs uint8) error {
+	template, ok := entry.object.(*Variable)
+	if !ok {
+		return fmt.Errorf("x%x CompactSubObj is only valid for ARRAY objects", entry.Index)
+	}
+	array := newCompactArray(entry.Name, nbSubs, template)
+	entry.object = array
+	entry.ObjectType = ObjectTypeARRAY
+	for _, variable := range array.Variables {
+		entry.subEntriesNameMap[variable.Name] = variable.SubIndex
+	}
+	return nil
+}
+
+// Sub-object of a compact ARRAY corresponding to a key of a [xxxxName] or [xxxxValue] section.
+// Returns nil without error for the number of entries.
+func (entry *Entry) compactVariable(key string) (*Variable, error) {
+	if strings.EqualFold(key, "NrOfEntries") {
+		return nil, nil
+	}
+	array, ok := entry.object.(*VariableList)
+	if !ok || entry.ObjectType != ObjectTypeARRAY {
+		return nil, fmt.Errorf("x%x is not an ARRAY", entry.Index)
+	}
+	subindex, err := strconv.ParseUint(key, 10, 8)
+	if err != nil || subindex == 0 || int(subindex) >= len(array.Variables) || array.Variables[subindex] == nil {
+		return nil, fmt.Errorf("x%x invalid sub-object %q : %w", entry.Index, key, ErrSubNotExist)
+	}
+	return array.Variables[subindex], nil
+}
+
+// Set the name of a compact ARRAY sub-object
+func (entry *Entry) setCompactName(key string, name string) error {
+	variable, err := entry.compactVariable(key)
+	if err != nil || variable == nil {
+		return err
+	}
+	delete(entry.subEntriesNameMap, variable.Name)
+	variable.Name = name
+	entry.subEntriesNameMap[name] = variable.SubIndex
+	return nil
+}
+
+// Set the current value of a compact ARRAY sub-object
+func (entry *Entry) setCompactValue(key string, value string, nodeId uint8) error {
+	variable, err := entry.compactVariable(key)
+	if err != nil || variable == nil {
+		return err
+	}
+	encoded, err := encodeWithNodeId(value, variable.DataType, nodeId)
+	if err != nil {
+		return fmt.Errorf("failed to parse value for x%x|x%x, because %v (datatype :x%x)", entry.Index, variable.SubIndex, err, variable.DataType)
+	}
+	variable.value = encoded
+	return nil
+}
+
+// Index of the compact ARRAY described by a [xxxxName] or [xxxxValue] section
+func compactSectionIndex(section []byte) (index uint16, names bool, ok bool) {
+	if len(section) < 5 || !isValidHex4(section[:4]) {
+		return 0, false, false
+	}
+	suffix := strings.ToLower(string(section[4:]))
+	if suffix != compactNameSuffix && suffix != compactValueSuffix {
+		return 0, false, false
+	}
+	idx, _ := hexAsciiToUint(section[:4])
+	return uint16(idx), suffix == compactNameSuffix, true
+}
+
+// Generate the PDOs that are not described in a file using CompactPDO,
+// with the default parameters of [ObjectDictionary.AddRPDO] & [ObjectDictionary.AddTPDO]
+func (od *ObjectDictionary) addCompactPDOs(nbRPDO uint16, nbTPDO uint16) {
+	for pdoNb := uint16(1); pdoNb <= min(nbRPDO, 512); pdoNb++ {
+		if od.Index(EntryRPDOCommunicationStart+pdoNb-1) == nil && od.Index(EntryRPDOMappingStart+pdoNb-1) == nil {
+			_ = od.addPDO(pdoNb, true)
+		}
+	}
+	for pdoNb := uint16(1); pdoNb <= min(nbTPDO, 512); pdoNb++ {
+		if od.Index(EntryTPDOCommunicationStart+pdoNb-1) == nil && od.Index(EntryTPDOMappingStart+pdoNb-1) == nil {
+			_ = od.addPDO(pdoNb, false)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package od
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind of a [Difference] between two ODs
+type DiffKind uint8
+
+const (
+	DiffOnlyInA    DiffKind = iota + 1 // Entry or sub-entry only in the first OD
+	DiffOnlyInB                        // Entry or sub-entry only in the second OD
+	DiffObjectType                     // e.g. VAR and RECORD
+	DiffDataType
+	DiffAttribute // Access type or PDO mapping
+	DiffDefault   // Default value
+)
+
+func (kind DiffKind) String() string {
+	switch kind {
+	case DiffOnlyInA:
+		return "only in a"
+	case DiffOnlyInB:
+		return "only in b"
+	case DiffObjectType:
+		return "object type"
+	case DiffDataType:
+		return "data type"
+	case DiffAttribute:
+		return "attribute"
+	case DiffDefault:
+		return "default value"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(kind))
+	}
+}
+
+// A difference found by [Compare]
+type Difference struct {
+	Kind     DiffKind
+	Index    uint16
+	Subindex uint8
+	Entry    bool   // Difference concerns the whole entry, Subindex is not relevant
+	A        string // Value in the first OD, e.g. the name for entries only in one OD
+	B        string // Value in the second OD
+}
+
+func (diff Difference) String() string {
+	location := fmt.Sprintf("x%x|x%x", diff.Index, diff.Subindex)
+	if diff.Entry {
+		location = fmt.Sprintf("x%x", diff.Index)
+	}
+	return fmt.Sprintf("%v %v : %q != %q", location, diff.Kind, diff.A, diff.B)
+}
+
+// Compare returns the differences between two ODs, ordered by index & subindex :
+// entries or sub-entries only in one OD, and sub-entries with different
+// data types, attributes or default values. Names & current values are not compared.
+func Compare(a *ObjectDictionary, b *ObjectDictionary) []Difference {
+	indexes := make([]int, 0)
+	for index := range a.entriesByIndexValue {
+		indexes = append(indexes, int(index))
+	}
+	for index := range b.entriesByIndexValue {
+		if _, ok := a.entriesByIndexValue[index]; !ok {
+			indexes = append(indexes, int(index))
+		}
+	}
+	sort.Ints(indexes)
+
+	diffs := make([]Difference, 0)
+	for _, i := range indexes {
+		index := uint16(i)
+		entryA, entryB := a.entriesByIndexValue[index], b.entriesByIndexValue[index]
+		switch {
+		case entryB == nil:
+			diffs = append(diffs, Difference{Kind: DiffOnlyInA, Index: index, Entry: true, A: entryA.Name})
+			continue
+		case entryA == nil:
+			diffs = append(diffs, Difference{Kind: DiffOnlyInB, Index: index, Entry: true, B: entryB.Name})
+			continue
+		case entryA.ObjectType != entryB.ObjectType:
+			diffs = append(diffs, Difference{
+				Kind: DiffObjectType, Index: index, Entry: true,
+				A: strings.TrimSpace(OBJ_NAME_MAP[entryA.ObjectType]), B: strings.TrimSpace(OBJ_NAME_MAP[entryB.ObjectType]),
+			})
+			continue
+		}
+		diffs = append(diffs, compareVariables(index, entryA.variables(), entryB.variables())...)
+	}
+	return diffs
+}
+
+// Compare the sub-entries of an entry present in both ODs
+func compareVariables(index uint16, a []*Variable, b []*Variable) []Difference {
+	bySubindex := make(map[uint8]*Variable, len(b))
+	for _, variable := range b {
+		bySubindex[variable.SubIndex] = variable
+	}
+	diffs := make([]Difference, 0)
+	for _, varA := range a {
+		varB, ok := bySubindex[varA.SubIndex]
+		if !ok {
+			diffs = append(diffs, Difference{Kind: DiffOnlyInA, Index: index, Subindex: varA.SubIndex, A: varA.Name})
+			continue
+		}
+		delete(bySubindex, varA.SubIndex)
+		diff := Difference{Index: index, Subindex: varA.SubIndex}
+		switch {
+		case varA.DataType != varB.DataType:
+			diff.Kind, diff.A, diff.B = DiffDataType, fmt.Sprintf("x%x", varA.DataType), fmt.Sprintf("x%x", varB.DataType)
+		case varA.Attribute != varB.Attribute:
+			diff.Kind, diff.A, diff.B = DiffAttribute, fmt.Sprintf("x%x", varA.Attribute), fmt.Sprintf("x%x", varB.Attribute)
+		case varA.DataType != DOMAIN && !bytes.Equal(varA.valueDefault, varB.valueDefault):
+			diff.Kind, diff.A, diff.B = DiffDefault, formatDefault(varA), formatDefault(varB)
+		default:
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	for _, varB := range b {
+		if _, ok := bySubindex[varB.SubIndex]; ok {
+			diffs = append(diffs, Difference{Kind: DiffOnlyInB, Index: index, Subindex: varB.SubIndex, B: varB.Name})
+		}
+	}
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Subindex < diffs[j].Subindex })
+	return diffs
+}
+
+func formatDefault(variable *Variable) string {
+	value, err := DecodeToString(variable.valueDefault, variable.DataType, 10)
+	if err != nil {
+		return fmt.Sprintf("%x", variable.valueDefault)
+	}
+	return value
+}
+
+// All the sub-entries of entry, a single one for VAR & DOMAIN
+func (entry *Entry) variables() []*Variable {
+	switch object := entry.object.(type) {
+	case *Variable:
+		return []*Variable{object}
+	case *VariableList:
+		variables := make([]*Variable, 0, len(object.Variables))
+		for _, variable := range object.Variables {
+			if variable != nil {
+				variables = append(variables, variable)
+			}
+		}
+		return variables
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,31 @@
+package od
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	a := Default()
+	b := Default()
+	assert.Empty(t, Compare(a, b))
+
+	b.AddVariableType(0x3000, "new entry", UNSIGNED8, AttributeSdoRw, "0x1")
+	delete(b.entriesByIndexValue, 0x2001)
+	identity := b.Index(EntryIdentityObject).object.(*VariableList)
+	identity.Variables[1].valueDefault = []byte{0x12, 0x34, 0x00, 0x00}
+	heartbeat := b.Index(EntryProducerHeartbeatTime).object.(*Variable)
+	heartbeat.Attribute = AttributeSdoR
+	b.AddVariableType(0x2002, "INTEGER8 value", UNSIGNED8, AttributeSdoRw, "0x0")
+
+	diffs := Compare(a, b)
+	assert.Equal(t, []Difference{
+		{Kind: DiffAttribute, Index: 0x1017, A: "x3", B: "x1"},
+		{Kind: DiffDefault, Index: 0x1018, Subindex: 1, A: "0", B: "13330"},
+		{Kind: DiffOnlyInA, Index: 0x2001, Entry: true, A: "BOOLEAN value"},
+		{Kind: DiffDataType, Index: 0x2002, A: "x2", B: "x5"},
+		{Kind: DiffOnlyInB, Index: 0x3000, Entry: true, B: "new entry"},
+	}, diffs)
+	assert.Equal(t, `x2001 only in a : "BOOLEAN value" != ""`, diffs[2].String())
+}
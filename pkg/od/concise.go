@@ -0,0 +1,108 @@
+package od
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+var ErrConciseDCFFormat = errors.New("invalid concise DCF format")
+
+// A single value of a concise DCF (CiA 302)
+type ConciseEntry struct {
+	Index    uint16
+	Subindex uint8
+	Data     []byte
+}
+
+// EncodeConciseDCF creates a concise DCF binary as described in CiA 302 :
+// number of entries (UNSIGNED32) followed by, for every entry,
+// index (UNSIGNED16), subindex (UNSIGNED8), size (UNSIGNED32) and data.
+// All values are little endian.
+func EncodeConciseDCF(entries []ConciseEntry) []byte {
+	buf := &bytes.Buffer{}
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(entries)))
+	for _, entry := range entries {
+		_ = binary.Write(buf, binary.LittleEndian, entry.Index)
+		buf.WriteByte(entry.Subindex)
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(entry.Data)))
+		buf.Write(entry.Data)
+	}
+	return buf.Bytes()
+}
+
+// DecodeConciseDCF decodes a concise DCF binary, see [EncodeConciseDCF]
+func DecodeConciseDCF(data []byte) ([]ConciseEntry, error) {
+	if len(data) < 4 {
+		return nil, ErrConciseDCFFormat
+	}
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+	entries := make([]ConciseEntry, 0)
+	for range count {
+		if len(data) < 7 {
+			return nil, ErrConciseDCFFormat
+		}
+		size := binary.LittleEndian.Uint32(data[3:7])
+		if uint64(len(data)-7) < uint64(size) {
+			return nil, ErrConciseDCFFormat
+		}
+		entries = append(entries, ConciseEntry{
+			Index:    binary.LittleEndian.Uint16(data[0:2]),
+			Subindex: data[2],
+			Data:     bytes.Clone(data[7 : 7+size]),
+		})
+		data = data[7+size:]
+	}
+	if len(data) != 0 {
+		return nil, ErrConciseDCFFormat
+	}
+	return entries, nil
+}
+
+// ConciseDCF creates a concise DCF with the writable entries of OD
+// that differ from their default values, ordered by index and subindex.
+// e.g. when OD is parsed from a DCF, these are the parameter values.
+// DOMAIN entries are not included.
+func (od *ObjectDictionary) ConciseDCF() []byte {
+	return EncodeConciseDCF(od.writableValues(func(index uint16, variable *Variable) bool {
+		return !bytes.Equal(variable.value, variable.valueDefault)
+	}))
+}
+
+// Collect the values of writable entries (DOMAIN excepted) accepted by filter,
+// ordered by index and subindex. filter is called with the variable locked.
+func (od *ObjectDictionary) writableValues(filter func(index uint16, variable *Variable) bool) []ConciseEntry {
+	indexes := make([]int, 0)
+	for index := range od.entriesByIndexValue {
+		indexes = append(indexes, int(index))
+	}
+	sort.Ints(indexes)
+
+	entries := make([]ConciseEntry, 0)
+	for _, index := range indexes {
+		var variables []*Variable
+		switch object := od.entriesByIndexValue[uint16(index)].object.(type) {
+		case *Variable:
+			variables = []*Variable{object}
+		case *VariableList:
+			variables = object.Variables
+		}
+		for _, variable := range variables {
+			if variable == nil || variable.DataType == DOMAIN || variable.Attribute&AttributeSdoW == 0 {
+				continue
+			}
+			variable.mu.RLock()
+			if filter(uint16(index), variable) {
+				entries = append(entries, ConciseEntry{
+					Index:    uint16(index),
+					Subindex: variable.SubIndex,
+					Data:     bytes.Clone(variable.value),
+				})
+			}
+			variable.mu.RUnlock()
+		}
+	}
+	return entries
+}
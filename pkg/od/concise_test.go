@@ -0,0 +1,65 @@
+package od
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConciseDCF(t *testing.T) {
+	t.Run("encode decode", func(t *testing.T) {
+		entries := []ConciseEntry{
+			{Index: 0x1017, Subindex: 0, Data: []byte{0xE8, 0x03}},
+			{Index: 0x2007, Subindex: 0, Data: []byte{0x01, 0x02, 0x03, 0x04}},
+		}
+		data := EncodeConciseDCF(entries)
+		assert.Equal(t, []byte{
+			0x02, 0x00, 0x00, 0x00,
+			0x17, 0x10, 0x00, 0x02, 0x00, 0x00, 0x00, 0xE8, 0x03,
+			0x07, 0x20, 0x00, 0x04, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03, 0x04,
+		}, data)
+		decoded, err := DecodeConciseDCF(data)
+		assert.Nil(t, err)
+		assert.Equal(t, entries, decoded)
+		// Truncated or trailing data
+		_, err = DecodeConciseDCF(data[:len(data)-1])
+		assert.Equal(t, ErrConciseDCFFormat, err)
+		_, err = DecodeConciseDCF(append(data, 0x00))
+		assert.Equal(t, ErrConciseDCFFormat, err)
+		_, err = DecodeConciseDCF([]byte{0x00})
+		assert.Equal(t, ErrConciseDCFFormat, err)
+	})
+
+	t.Run("from OD", func(t *testing.T) {
+		odict := Default()
+		entries, err := DecodeConciseDCF(odict.ConciseDCF())
+		assert.Nil(t, err)
+		assert.Len(t, entries, 0)
+		err = odict.Index(0x2005).PutUint8(0, 0x42, true)
+		assert.Nil(t, err)
+		entries, err = DecodeConciseDCF(odict.ConciseDCF())
+		assert.Nil(t, err)
+		assert.Equal(t, []ConciseEntry{{Index: 0x2005, Subindex: 0, Data: []byte{0x42}}}, entries)
+	})
+
+	t.Run("from DCF", func(t *testing.T) {
+		odict := Default()
+		err := odict.Index(0x2006).PutUint16(0, 0x1234, true)
+		assert.Nil(t, err)
+		buf := &bytes.Buffer{}
+		err = odict.ExportDCF(buf, 0x10)
+		assert.Nil(t, err)
+		expected := []ConciseEntry{{Index: 0x2006, Subindex: 0, Data: []byte{0x34, 0x12}}}
+		for _, parse := range []func(file any, nodeId uint8) (*ObjectDictionary, error){Parse, ParseV2} {
+			odictNew, err := parse(buf.Bytes(), 0x10)
+			assert.Nil(t, err)
+			value, err := odictNew.Index(0x2006).Uint16(0)
+			assert.Nil(t, err)
+			assert.EqualValues(t, 0x1234, value)
+			entries, err := DecodeConciseDCF(odictNew.ConciseDCF())
+			assert.Nil(t, err)
+			assert.Equal(t, expected, entries)
+		}
+	})
+}
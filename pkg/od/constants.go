@@ -25,21 +25,23 @@ var OBJ_NAME_MAP = map[byte]string{
 
 // CANopen supported datatypes
 const (
-	BOOLEAN        uint8 = 0x01
-	INTEGER8       uint8 = 0x02
-	INTEGER16      uint8 = 0x03
-	INTEGER32      uint8 = 0x04
-	UNSIGNED8      uint8 = 0x05
-	UNSIGNED16     uint8 = 0x06
-	UNSIGNED32     uint8 = 0x07
-	REAL32         uint8 = 0x08
-	VISIBLE_STRING uint8 = 0x09
-	OCTET_STRING   uint8 = 0x0A
-	UNICODE_STRING uint8 = 0x0B
-	DOMAIN         uint8 = 0x0F
-	REAL64         uint8 = 0x11
-	INTEGER64      uint8 = 0x15
-	UNSIGNED64     uint8 = 0x1B
+	BOOLEAN         uint8 = 0x01
+	INTEGER8        uint8 = 0x02
+	INTEGER16       uint8 = 0x03
+	INTEGER32       uint8 = 0x04
+	UNSIGNED8       uint8 = 0x05
+	UNSIGNED16      uint8 = 0x06
+	UNSIGNED32      uint8 = 0x07
+	REAL32          uint8 = 0x08
+	VISIBLE_STRING  uint8 = 0x09
+	OCTET_STRING    uint8 = 0x0A
+	UNICODE_STRING  uint8 = 0x0B
+	TIME_OF_DAY     uint8 = 0x0C
+	TIME_DIFFERENCE uint8 = 0x0D
+	DOMAIN          uint8 = 0x0F
+	REAL64          uint8 = 0x11
+	INTEGER64       uint8 = 0x15
+	UNSIGNED64      uint8 = 0x1B
 )
 
 type ODR int8
@@ -136,39 +138,131 @@ const (
 	AttributeStr uint8 = 0x80
 )
 
-// Standard CANopen object entries index
+// Standard CANopen object entries index (CiA 301, CiA 302 & CiA 304)
 const (
-	EntryDeviceType                  uint16 = 0x1000
-	EntryErrorRegister               uint16 = 0x1001
-	EntryManufacturerStatusRegister  uint16 = 0x1003
-	EntryCobIdSYNC                   uint16 = 0x1005
-	EntryCommunicationCyclePeriod    uint16 = 0x1006
-	EntrySynchronousWindowLength     uint16 = 0x1007
-	EntryManufacturerDeviceName      uint16 = 0x1008
-	EntryManufacturerHardwareVersion uint16 = 0x1009
-	EntryManufacturerSoftwareVersion uint16 = 0x100A
-	EntryStoreParameters             uint16 = 0x1010
-	EntryRestoreDefaultParameters    uint16 = 0x1011
-	EntryCobIdTIME                   uint16 = 0x1012
-	EntryHighResTimestamp            uint16 = 0x1013
-	EntryCobIdEMCY                   uint16 = 0x1014
-	EntryInhibitTimeEMCY             uint16 = 0x1015
-	EntryConsumerHeartbeatTime       uint16 = 0x1016
-	EntryProducerHeartbeatTime       uint16 = 0x1017
-	EntryIdentityObject              uint16 = 0x1018
-	EntrySynchronousCounterOverflow  uint16 = 0x1019
-	EntryStoreEDS                    uint16 = 0x1021
-	EntryStorageFormat               uint16 = 0x1022
-	EntrySDOServerParameter          uint16 = 0x1200
-	EntrySDOClientParameter          uint16 = 0x1280
-	EntryRPDOCommunicationStart      uint16 = 0x1400
-	EntryRPDOCommunicationEnd        uint16 = 0x15FF
-	EntryRPDOMappingStart            uint16 = 0x1600
-	EntryRPDOMappingEnd              uint16 = 0x17FF
-	EntryTPDOCommunicationStart      uint16 = 0x1800
-	EntryTPDOCommunicationEnd        uint16 = 0x19FF
-	EntryTPDOMappingStart            uint16 = 0x1A00
-	EntryTPDOMappingEnd              uint16 = 0x1BFF
+	EntryDeviceType                      uint16 = 0x1000
+	EntryErrorRegister                   uint16 = 0x1001
+	EntryManufacturerStatus              uint16 = 0x1002
+	EntryPreDefinedErrorField            uint16 = 0x1003
+	EntryCobIdSYNC                       uint16 = 0x1005
+	EntryCommunicationCyclePeriod        uint16 = 0x1006
+	EntrySynchronousWindowLength         uint16 = 0x1007
+	EntryManufacturerDeviceName          uint16 = 0x1008
+	EntryManufacturerHardwareVersion     uint16 = 0x1009
+	EntryManufacturerSoftwareVersion     uint16 = 0x100A
+	EntryGuardTime                       uint16 = 0x100C
+	EntryLifeTimeFactor                  uint16 = 0x100D
+	EntryStoreParameters                 uint16 = 0x1010
+	EntryRestoreDefaultParameters        uint16 = 0x1011
+	EntryCobIdTIME                       uint16 = 0x1012
+	EntryHighResTimestamp                uint16 = 0x1013
+	EntryCobIdEMCY                       uint16 = 0x1014
+	EntryInhibitTimeEMCY                 uint16 = 0x1015
+	EntryConsumerHeartbeatTime           uint16 = 0x1016
+	EntryProducerHeartbeatTime           uint16 = 0x1017
+	EntryIdentityObject                  uint16 = 0x1018
+	EntrySynchronousCounterOverflow      uint16 = 0x1019
+	EntryVerifyConfiguration             uint16 = 0x1020
+	EntryStoreEDS                        uint16 = 0x1021
+	EntryStorageFormat                   uint16 = 0x1022
+	EntryOSCommand                       uint16 = 0x1023
+	EntryOSCommandMode                   uint16 = 0x1024
+	EntryOSDebuggerInterface             uint16 = 0x1025
+	EntryOSPrompt                        uint16 = 0x1026
+	EntryModuleList                      uint16 = 0x1027
+	EntryEmergencyConsumer               uint16 = 0x1028
+	EntryErrorBehavior                   uint16 = 0x1029
+	EntrySDOServerParameter              uint16 = 0x1200
+	EntrySDOServerParameterEnd           uint16 = 0x127F
+	EntrySDOClientParameter              uint16 = 0x1280
+	EntrySDOClientParameterEnd           uint16 = 0x12FF
+	EntryGFCParameter                    uint16 = 0x1300
+	EntrySRDOCommunicationStart          uint16 = 0x1301
+	EntrySRDOCommunicationEnd            uint16 = 0x1340
+	EntrySRDOMappingStart                uint16 = 0x1381
+	EntrySRDOMappingEnd                  uint16 = 0x13C0
+	EntrySRDOConfigurationValid          uint16 = 0x13FE
+	EntrySRDOConfigurationChecksum       uint16 = 0x13FF
+	EntryRPDOCommunicationStart          uint16 = 0x1400
+	EntryRPDOCommunicationEnd            uint16 = 0x15FF
+	EntryRPDOMappingStart                uint16 = 0x1600
+	EntryRPDOMappingEnd                  uint16 = 0x17FF
+	EntryTPDOCommunicationStart          uint16 = 0x1800
+	EntryTPDOCommunicationEnd            uint16 = 0x19FF
+	EntryTPDOMappingStart                uint16 = 0x1A00
+	EntryTPDOMappingEnd                  uint16 = 0x1BFF
+	EntryStoreDCF                        uint16 = 0x1F20
+	EntryStorageFormatDCF                uint16 = 0x1F21
+	EntryConciseDCF                      uint16 = 0x1F22
+	EntryConfigureSlave                  uint16 = 0x1F25
+	EntryExpectedConfigurationDate       uint16 = 0x1F26
+	EntryExpectedConfigurationTime       uint16 = 0x1F27
+	EntryProgramData                     uint16 = 0x1F50
+	EntryProgramControl                  uint16 = 0x1F51
+	EntryVerifyApplicationSoftware       uint16 = 0x1F52
+	EntryExpectedApplicationSoftwareDate uint16 = 0x1F53
+	EntryExpectedApplicationSoftwareTime uint16 = 0x1F54
+	EntryProgramSoftwareId               uint16 = 0x1F56
+	EntryFlashStatusId                   uint16 = 0x1F57
+	EntryNMTStartup                      uint16 = 0x1F80
+	EntryNMTSlaveAssignment              uint16 = 0x1F81
+	EntryRequestNMT                      uint16 = 0x1F82
+	EntryRequestNodeGuarding             uint16 = 0x1F83
+	EntryDeviceTypeIdentification        uint16 = 0x1F84
+	EntryVendorIdentification            uint16 = 0x1F85
+	EntryProductCodeIdentification       uint16 = 0x1F86
+	EntryRevisionNumberIdentification    uint16 = 0x1F87
+	EntrySerialNumberIdentification      uint16 = 0x1F88
+	EntryBootTime                        uint16 = 0x1F89
+	EntryObjectScannerListStart          uint16 = 0x1FA0
+	EntryObjectScannerListEnd            uint16 = 0x1FCF
+	EntryObjectDispatchingListStart      uint16 = 0x1FD0
+	EntryObjectDispatchingListEnd        uint16 = 0x1FFF
+
+	// Deprecated: 0x1003 is the pre-defined error field, use [EntryPreDefinedErrorField].
+	EntryManufacturerStatusRegister = EntryPreDefinedErrorField
+)
+
+// Sub index 0 of records & arrays, holding the highest sub index supported
+const SubHighestSubIndex uint8 = 0
+
+// Identity object (0x1018) sub indexes
+const (
+	SubIdentityVendorId       uint8 = 1
+	SubIdentityProductCode    uint8 = 2
+	SubIdentityRevisionNumber uint8 = 3
+	SubIdentitySerialNumber   uint8 = 4
+)
+
+// Store parameters (0x1010) & restore default parameters (0x1011) sub indexes
+const (
+	SubStorageAll           uint8 = 1
+	SubStorageCommunication uint8 = 2
+	SubStorageApplication   uint8 = 3
+	SubStorageManufacturer  uint8 = 4 // First manufacturer specific parameter group
+)
+
+// SDO server (0x1200 - 0x127F) & SDO client (0x1280 - 0x12FF) parameter sub indexes
+const (
+	SubSDOCobIdClientToServer uint8 = 1
+	SubSDOCobIdServerToClient uint8 = 2
+	SubSDONodeId              uint8 = 3
+)
+
+// PDO communication parameter (0x1400 - 0x15FF & 0x1800 - 0x19FF) sub indexes
+const (
+	SubPDOCobId            uint8 = 1
+	SubPDOTransmissionType uint8 = 2
+	SubPDOInhibitTime      uint8 = 3
+	SubPDOEventTimer       uint8 = 5
+	SubPDOSyncStart        uint8 = 6
+)
+
+// PDO mapping parameter (0x1600 - 0x17FF & 0x1A00 - 0x1BFF) sub indexes.
+// Sub index 0 holds the number of mapped objects, the mapped objects start at 1.
+const (
+	SubPDONbMapped      uint8 = 0
+	SubPDOMappingObject uint8 = 1
 )
 
 // Standard CANopen object areas
@@ -0,0 +1,147 @@
+package od
+
+import (
+	"encoding/binary"
+	"time"
+	"unicode/utf16"
+)
+
+// TIME_OF_DAY & TIME_DIFFERENCE are 6 bytes long : milliseconds (28 bits)
+// followed by days (16 bits). TIME_OF_DAY days are counted since 1st of january 1984.
+const timeLength = 6
+
+const (
+	maxDays   = 0xFFFF
+	maskMs    = 0x0FFFFFFF
+	dayLength = 24 * time.Hour
+)
+
+// Origin of TIME_OF_DAY, for counting calendar days
+var timeOfDayOrigin = time.Date(1984, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// EncodeUnicodeString encodes a string as a UNICODE_STRING, i.e. UTF-16 little endian
+func EncodeUnicodeString(value string) []byte {
+	encoded := utf16.Encode([]rune(value))
+	data := make([]byte, 2*len(encoded))
+	for i, unit := range encoded {
+		binary.LittleEndian.PutUint16(data[2*i:], unit)
+	}
+	return data
+}
+
+// DecodeUnicodeString decodes a UNICODE_STRING, data should hold complete 16 bit units.
+// Decoding stops at the first null character, if any.
+func DecodeUnicodeString(data []byte) (string, error) {
+	if len(data)%2 != 0 {
+		return "", ErrDataShort
+	}
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i < len(data); i += 2 {
+		unit := binary.LittleEndian.Uint16(data[i:])
+		if unit == 0 {
+			break
+		}
+		units = append(units, unit)
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// EncodeTimeOfDay encodes a date as a TIME_OF_DAY, in the location of t
+func EncodeTimeOfDay(t time.Time) ([]byte, error) {
+	year, month, day := t.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if date.Before(timeOfDayOrigin) {
+		return nil, ErrValueLow
+	}
+	days := int64(date.Sub(timeOfDayOrigin) / dayLength)
+	if days > maxDays {
+		return nil, ErrValueHigh
+	}
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	return encodeTime(uint32(t.Sub(midnight).Milliseconds()), uint16(days)), nil
+}
+
+// DecodeTimeOfDay decodes a TIME_OF_DAY, the date is given in local time
+func DecodeTimeOfDay(data []byte) (time.Time, error) {
+	ms, days, err := decodeTime(data)
+	if err != nil {
+		return time.Time{}, err
+	}
+	date := time.Date(1984, time.January, 1+int(days), 0, 0, 0, 0, time.Local)
+	return date.Add(time.Duration(ms) * time.Millisecond), nil
+}
+
+// EncodeTimeDifference encodes a positive duration as a TIME_DIFFERENCE,
+// with a millisecond resolution
+func EncodeTimeDifference(d time.Duration) ([]byte, error) {
+	if d < 0 {
+		return nil, ErrValueLow
+	}
+	days := d / dayLength
+	if days > maxDays {
+		return nil, ErrValueHigh
+	}
+	return encodeTime(uint32((d % dayLength).Milliseconds()), uint16(days)), nil
+}
+
+// DecodeTimeDifference decodes a TIME_DIFFERENCE
+func DecodeTimeDifference(data []byte) (time.Duration, error) {
+	ms, days, err := decodeTime(data)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(days)*dayLength + time.Duration(ms)*time.Millisecond, nil
+}
+
+func encodeTime(ms uint32, days uint16) []byte {
+	data := make([]byte, timeLength)
+	binary.LittleEndian.PutUint32(data, ms&maskMs)
+	binary.LittleEndian.PutUint16(data[4:], days)
+	return data
+}
+
+func decodeTime(data []byte) (uint32, uint16, error) {
+	if err := CheckSize(len(data), TIME_OF_DAY); err != nil {
+		return 0, 0, err
+	}
+	return binary.LittleEndian.Uint32(data) & maskMs, binary.LittleEndian.Uint16(data[4:]), nil
+}
+
+// EncodeFromType encodes value for the given CANopen data type, contrary to
+// [EncodeFromGeneric] the data type is also used to check the length of value
+// and to select the encoding, e.g. a string is encoded in UTF-16 for UNICODE_STRING.
+// value can be any type supported by [EncodeFromGeneric] or a bool.
+func EncodeFromType(value any, dataType uint8) ([]byte, error) {
+	if boolean, ok := value.(bool); ok {
+		if dataType != BOOLEAN {
+			return nil, ErrTypeMismatch
+		}
+		if boolean {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	}
+	switch dataType {
+	case UNICODE_STRING:
+		if str, ok := value.(string); ok {
+			return EncodeUnicodeString(str), nil
+		}
+	case TIME_OF_DAY:
+		if _, ok := value.(time.Duration); ok {
+			return nil, ErrTypeMismatch
+		}
+	case TIME_DIFFERENCE:
+		if _, ok := value.(time.Time); ok {
+			return nil, ErrTypeMismatch
+		}
+	}
+	encoded, err := EncodeFromGeneric(value)
+	if err != nil {
+		return nil, err
+	}
+	err = CheckSize(len(encoded), dataType)
+	if err != nil {
+		return nil, err
+	}
+	return encoded, nil
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync/atomic"
 
 	"gopkg.in/ini.v1"
 )
@@ -31,7 +32,9 @@ type Entry struct {
 	ObjectType uint8
 	// Either a [Variable] or a [VariableList] object
 	object            any
-	extension         *extension
+	extension         *extension                      // First link of the extension chain, if any
+	flagsPDO          [FlagsPdoSize / 4]atomic.Uint32 // One bit per sub index, cleared on TPDO request
+	onChange          *tpdoOnChange                   // Transmission on change, see [Entry.SetTPDOOnChange]
 	subEntriesNameMap map[string]uint8
 }
 
@@ -56,7 +59,7 @@ func (entry *Entry) SubIndex(subIndex any) (v *Variable, e error) {
 	}
 	switch object := entry.object.(type) {
 	case *Variable:
-		if subIndex != 0 && subIndex != "" {
+		if subIndex != 0 && subIndex != uint8(0) && subIndex != "" {
 			return nil, ErrSubNotExist
 		}
 		return object, nil
@@ -117,6 +120,7 @@ func (entry *Entry) addSectionMember(section *ini.Section, name string, nodeId u
 // e.g. objects x1005, x1006, etc.
 // Implementation of the default StreamReader & StreamWriter for a regular OD entry
 // can be found here [ReadEntryDefault] & [WriteEntryDefault].
+// This replaces any existing extension, see [Entry.PushExtension] for composing extensions.
 func (entry *Entry) AddExtension(object any, read StreamReader, write StreamWriter) {
 	entry.logger.Debug("added extension",
 		"read", getFunctionName(read),
@@ -126,6 +130,43 @@ func (entry *Entry) AddExtension(object any, read StreamReader, write StreamWrit
 	entry.extension = extension
 }
 
+// Push an extension in front of the existing ones, this allows composing
+// behaviours e.g. logging + validation + file storage.
+// The pushed extension is called first on read / write. It can either handle
+// the access itself, or pass it through to the next extension of the chain with
+// [ReadNext] & [WriteNext]. The last extension passes through to the OD value.
+// The returned link can be used with [Entry.RemoveExtension].
+func (entry *Entry) PushExtension(object any, read StreamReader, write StreamWriter) *extension {
+	entry.logger.Debug("pushed extension",
+		"read", getFunctionName(read),
+		"write", getFunctionName(write),
+	)
+	extension := &extension{object: object, read: read, write: write, next: entry.extension}
+	entry.extension = extension
+	return extension
+}
+
+// Remove a single extension from the chain, the other ones are kept in order.
+// Returns false if extension is not part of the chain.
+func (entry *Entry) RemoveExtension(ext *extension) bool {
+	for link := &entry.extension; *link != nil; link = &(*link).next {
+		if *link == ext {
+			*link = ext.next
+			return true
+		}
+	}
+	return false
+}
+
+// Number of extensions in the chain
+func (entry *Entry) ExtensionCount() int {
+	count := 0
+	for link := entry.extension; link != nil; link = link.next {
+		count++
+	}
+	return count
+}
+
 // SubCount returns the number of sub entries inside entry.
 // If entry is of VAR type it will return 1
 func (entry *Entry) SubCount() int {
@@ -146,8 +187,59 @@ func (entry *Entry) Extension() *extension {
 	return entry.extension
 }
 
-func (entry *Entry) FlagPDOByte(subIndex byte) *uint8 {
-	return &entry.extension.flagsPDO[subIndex>>3]
+// PDOFlag returns the TPDO request flag of subIndex, used by TPDOs mapping it.
+func (entry *Entry) PDOFlag(subIndex uint8) PDOFlag {
+	return PDOFlag{
+		word: &entry.flagsPDO[subIndex>>5],
+		mask: 1 << (subIndex & 0x1F),
+	}
+}
+
+// RequestTPDO requests transmission of the event driven TPDOs (transmission
+// types 0, 254 & 255) mapping subIndex, e.g. after the application updated the value.
+// TPDOs are sent on next processing, inhibit time permitting.
+// It is safe to call concurrently with PDO processing.
+func (entry *Entry) RequestTPDO(subIndex uint8) {
+	entry.PDOFlag(subIndex).Request()
+}
+
+// PDOFlag is the TPDO request flag of a sub index. The flag is
+// cleared by the application on request, and set back by the TPDO once sent.
+// The zero value is a flag that is never requested.
+type PDOFlag struct {
+	word *atomic.Uint32
+	mask uint32
+}
+
+// Request clears the flag, requesting transmission
+func (flag PDOFlag) Request() {
+	if flag.word == nil {
+		return
+	}
+	for {
+		old := flag.word.Load()
+		if old&flag.mask == 0 || flag.word.CompareAndSwap(old, old&^flag.mask) {
+			return
+		}
+	}
+}
+
+// Acknowledge sets the flag, after transmission
+func (flag PDOFlag) Acknowledge() {
+	if flag.word == nil {
+		return
+	}
+	for {
+		old := flag.word.Load()
+		if old&flag.mask != 0 || flag.word.CompareAndSwap(old, old|flag.mask) {
+			return
+		}
+	}
+}
+
+// Requested returns true if transmission has been requested
+func (flag PDOFlag) Requested() bool {
+	return flag.word != nil && flag.word.Load()&flag.mask == 0
 }
 
 // Uint8 reads data inside of OD as if it were and UNSIGNED8.
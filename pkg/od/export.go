@@ -27,65 +27,144 @@ func ExportEDS(odict *ObjectDictionary, defaultValues bool, filename string) err
 		}
 		return i.SaveTo(filename)
 	}
+	eds, err := odict.newIni(false)
+	if err != nil {
+		return err
+	}
+	return eds.SaveTo(filename)
+}
+
+// ExportEDS writes the OD as an EDS file with the current values
+// as default values. This can be used to share dynamically built
+// or modified dictionaries with other tools.
+func (od *ObjectDictionary) ExportEDS(w io.Writer) error {
+	eds, err := od.newIni(false)
+	if err != nil {
+		return err
+	}
+	_, err = eds.WriteTo(w)
+	return err
+}
+
+// ExportDCF writes the OD as a DCF file for the given node id.
+// Default values are kept and current values are written as
+// parameter values.
+func (od *ObjectDictionary) ExportDCF(w io.Writer, nodeId uint8) error {
+	dcf, err := od.newIni(true)
+	if err != nil {
+		return err
+	}
+	section, err := dcf.NewSection("DeviceComissioning")
+	if err != nil {
+		return err
+	}
+	_, err = section.NewKey("NodeID", "0x"+strconv.FormatUint(uint64(nodeId), 16))
+	if err != nil {
+		return err
+	}
+	_, err = dcf.WriteTo(w)
+	return err
+}
+
+// Create the .INI representation of OD, with parameter values for DCF
+func (od *ObjectDictionary) newIni(dcf bool) (*ini.File, error) {
 	eds := ini.Empty()
 
 	// Sort map keys to export indexes, for lowest to highest
 	indexes := make([]int, 0)
-	for index := range odict.entriesByIndexValue {
+	for index := range od.entriesByIndexValue {
 		indexes = append(indexes, int(index))
 	}
 	sort.Ints(indexes)
 
+	err := populateObjectLists(eds, indexes)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, index := range indexes {
-		entry := odict.entriesByIndexValue[uint16(index)]
+		entry := od.entriesByIndexValue[uint16(index)]
 
 		if entry.SubCount() == 1 {
 			// Add entry for single objects (VAR,DOMAIN,...)
 			variable, ok := entry.object.(*Variable)
 			if !ok {
-				return fmt.Errorf("[OD] expecting a variable type at %x", index)
+				return nil, fmt.Errorf("[OD] expecting a variable type at %x", index)
 			}
 			section, err := eds.NewSection(strconv.FormatUint(uint64(index), 16))
 			if err != nil {
-				return err
+				return nil, err
 			}
-			err = populateSection(section, uint16(index), variable, entry.ObjectType)
+			err = populateSection(section, uint16(index), variable, entry.ObjectType, dcf)
 			if err != nil {
-				return fmt.Errorf("[OD] error populating section index at %x : %v", index, err)
+				return nil, fmt.Errorf("[OD] error populating section index at %x : %v", index, err)
 			}
 		} else {
 			// Add entry for multi objects (RECORD,ARRAY,...)
 			variables, ok := entry.object.(*VariableList)
 			if !ok {
-				return fmt.Errorf("[OD] expecting a variable list type at %x", index)
+				return nil, fmt.Errorf("[OD] expecting a variable list type at %x", index)
 			}
 			// Create header section
 			section, err := eds.NewSection(strconv.FormatUint(uint64(index), 16))
 			if err != nil {
-				return err
+				return nil, err
 			}
 			err = populateHeaderSection(section, entry.Name, variables.objectType, uint8(entry.SubCount()))
 			if err != nil {
-				return err
+				return nil, err
 			}
 			// Add all subsections, ordered
 			for i, variable := range variables.Variables {
 				section, err = eds.NewSection(strconv.FormatUint(uint64(index), 16) + "sub" + strconv.FormatUint(uint64(i), 16))
 				if err != nil {
-					return err
+					return nil, err
 				}
-				err = populateSection(section, uint16(index), variable, ObjectTypeVAR)
+				err = populateSection(section, uint16(index), variable, ObjectTypeVAR, dcf)
 				if err != nil {
-					return fmt.Errorf("[OD] error populating section index at %x|%x : %v", index, i, err)
+					return nil, fmt.Errorf("[OD] error populating section index at %x|%x : %v", index, i, err)
 				}
 			}
 		}
 	}
-	return eds.SaveTo(filename)
+	return eds, nil
 }
 
-// Populate section with relevant information for a variable type
-func populateSection(section *ini.Section, index uint16, variable *Variable, objectType uint8) error {
+// Populate the object lists sections, as expected by CiA 306
+func populateObjectLists(eds *ini.File, indexes []int) error {
+	lists := map[string][]int{}
+	for _, index := range indexes {
+		switch {
+		case index == 0x1000 || index == 0x1001 || index == 0x1018:
+			lists["MandatoryObjects"] = append(lists["MandatoryObjects"], index)
+		case index >= 0x2000 && index <= 0x5FFF:
+			lists["ManufacturerObjects"] = append(lists["ManufacturerObjects"], index)
+		default:
+			lists["OptionalObjects"] = append(lists["OptionalObjects"], index)
+		}
+	}
+	for _, name := range []string{"MandatoryObjects", "OptionalObjects", "ManufacturerObjects"} {
+		section, err := eds.NewSection(name)
+		if err != nil {
+			return err
+		}
+		_, err = section.NewKey("SupportedObjects", strconv.Itoa(len(lists[name])))
+		if err != nil {
+			return err
+		}
+		for i, index := range lists[name] {
+			_, err = section.NewKey(strconv.Itoa(i+1), "0x"+strconv.FormatUint(uint64(index), 16))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Populate section with relevant information for a variable type.
+// For DCF, current value is written as parameter value.
+func populateSection(section *ini.Section, index uint16, variable *Variable, objectType uint8, dcf bool) error {
 	_, err := section.NewKey("ParameterName", variable.Name)
 	if err != nil {
 		return err
@@ -102,21 +181,50 @@ func populateSection(section *ini.Section, index uint16, variable *Variable, obj
 	if err != nil {
 		return err
 	}
+	pdoMapping := "0"
+	if variable.Attribute&(AttributeRpdo|AttributeTpdo) != 0 {
+		pdoMapping = "1"
+	}
+	_, err = section.NewKey("PDOMapping", pdoMapping)
+	if err != nil {
+		return err
+	}
+	if variable.StorageLocation != "" {
+		_, err = section.NewKey("StorageLocation", variable.StorageLocation)
+		if err != nil {
+			return err
+		}
+	}
+	variable.mu.RLock()
+	defer variable.mu.RUnlock()
+	if !dcf {
+		return populateValue(section, "DefaultValue", index, variable, variable.value)
+	}
+	err = populateValue(section, "DefaultValue", index, variable, variable.valueDefault)
+	if err != nil {
+		return err
+	}
+	return populateValue(section, "ParameterValue", index, variable, variable.value)
+}
+
+// Write value under the given key, communication profile values are written as hex
+func populateValue(section *ini.Section, key string, index uint16, variable *Variable, value []byte) error {
 	var decoded string
+	var err error
 	if index >= AreaCommunicationProfileStart && index <= AreaCommunicationProfileEnd {
 		// Write values as hex strings, facilitates reading
-		decoded, err = DecodeToString(variable.value, variable.DataType, 16)
+		decoded, err = DecodeToString(value, variable.DataType, 16)
 		if variable.DataType != VISIBLE_STRING && variable.DataType != UNICODE_STRING {
 			decoded = "0x" + decoded
 		}
 
 	} else {
-		decoded, err = DecodeToString(variable.value, variable.DataType, 10)
+		decoded, err = DecodeToString(value, variable.DataType, 10)
 	}
 	if err != nil {
 		return err
 	}
-	_, err = section.NewKey("DefaultValue", decoded)
+	_, err = section.NewKey(key, decoded)
 	return err
 }
 
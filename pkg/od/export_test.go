@@ -1,9 +1,11 @@
 package od
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
 )
 
 func TestExportDefaultEds(t *testing.T) {
@@ -35,3 +37,37 @@ func TestExportDefaultEds(t *testing.T) {
 		assert.NotNil(t, entry)
 	})
 }
+
+func TestExportWriter(t *testing.T) {
+	odict := Default()
+	err := odict.Index(0x2005).PutUint8(0, 0x42, true)
+	assert.Nil(t, err)
+
+	t.Run("export EDS", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := odict.ExportEDS(buf)
+		assert.Nil(t, err)
+		assert.Contains(t, buf.String(), "[MandatoryObjects]")
+		odictNew, err := Parse(buf.Bytes(), 0x10)
+		assert.Nil(t, err)
+		value, err := odictNew.Index(0x2005).Uint8(0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x42, value)
+		// Attributes are kept
+		variable, err := odictNew.Index(0x1000).SubIndex(0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, variable.Attribute&(AttributeRpdo|AttributeTpdo))
+	})
+
+	t.Run("export DCF", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := odict.ExportDCF(buf, 0x10)
+		assert.Nil(t, err)
+		dcf, err := ini.Load(buf.Bytes())
+		assert.Nil(t, err)
+		assert.Equal(t, "0x10", dcf.Section("DeviceComissioning").Key("NodeID").String())
+		section := dcf.Section("2005")
+		assert.Equal(t, "16", section.Key("DefaultValue").String())
+		assert.Equal(t, "66", section.Key("ParameterValue").String())
+	})
+}
@@ -0,0 +1,119 @@
+package od
+
+// IsRPDOCommParam returns true if index is an RPDO communication parameter (0x1400 - 0x15FF)
+func IsRPDOCommParam(index uint16) bool {
+	return index >= EntryRPDOCommunicationStart && index <= EntryRPDOCommunicationEnd
+}
+
+// IsRPDOMappingParam returns true if index is an RPDO mapping parameter (0x1600 - 0x17FF)
+func IsRPDOMappingParam(index uint16) bool {
+	return index >= EntryRPDOMappingStart && index <= EntryRPDOMappingEnd
+}
+
+// IsTPDOCommParam returns true if index is a TPDO communication parameter (0x1800 - 0x19FF)
+func IsTPDOCommParam(index uint16) bool {
+	return index >= EntryTPDOCommunicationStart && index <= EntryTPDOCommunicationEnd
+}
+
+// IsTPDOMappingParam returns true if index is a TPDO mapping parameter (0x1A00 - 0x1BFF)
+func IsTPDOMappingParam(index uint16) bool {
+	return index >= EntryTPDOMappingStart && index <= EntryTPDOMappingEnd
+}
+
+// IsPDOCommParam returns true if index is an RPDO or TPDO communication parameter
+func IsPDOCommParam(index uint16) bool {
+	return IsRPDOCommParam(index) || IsTPDOCommParam(index)
+}
+
+// IsPDOMappingParam returns true if index is an RPDO or TPDO mapping parameter
+func IsPDOMappingParam(index uint16) bool {
+	return IsRPDOMappingParam(index) || IsTPDOMappingParam(index)
+}
+
+// IsSDOServerParam returns true if index is an SDO server parameter (0x1200 - 0x127F)
+func IsSDOServerParam(index uint16) bool {
+	return index >= EntrySDOServerParameter && index <= EntrySDOServerParameterEnd
+}
+
+// IsSDOClientParam returns true if index is an SDO client parameter (0x1280 - 0x12FF)
+func IsSDOClientParam(index uint16) bool {
+	return index >= EntrySDOClientParameter && index <= EntrySDOClientParameterEnd
+}
+
+// IsSRDOCommParam returns true if index is an SRDO communication parameter (0x1301 - 0x1340)
+func IsSRDOCommParam(index uint16) bool {
+	return index >= EntrySRDOCommunicationStart && index <= EntrySRDOCommunicationEnd
+}
+
+// IsSRDOMappingParam returns true if index is an SRDO mapping parameter (0x1381 - 0x13C0)
+func IsSRDOMappingParam(index uint16) bool {
+	return index >= EntrySRDOMappingStart && index <= EntrySRDOMappingEnd
+}
+
+// IsObjectScannerList returns true if index is an MPDO object scanner list (0x1FA0 - 0x1FCF)
+func IsObjectScannerList(index uint16) bool {
+	return index >= EntryObjectScannerListStart && index <= EntryObjectScannerListEnd
+}
+
+// IsObjectDispatchingList returns true if index is an MPDO object dispatching list (0x1FD0 - 0x1FFF)
+func IsObjectDispatchingList(index uint16) bool {
+	return index >= EntryObjectDispatchingListStart && index <= EntryObjectDispatchingListEnd
+}
+
+// IsCommunicationProfile returns true if index is in the communication profile area (0x1000 - 0x1FFF)
+func IsCommunicationProfile(index uint16) bool {
+	return index >= AreaCommunicationProfileStart && index <= AreaCommunicationProfileEnd
+}
+
+// IsManufacturerSpecific returns true if index is in the manufacturer specific area (0x2000 - 0x5FFF)
+func IsManufacturerSpecific(index uint16) bool {
+	return index >= AreaManufacturerSpecificProfileStart && index <= AreaManufacturerSpecificProfileEnd
+}
+
+// IsDeviceProfile returns true if index is in the standardized device profile area (0x6000 - 0x9FFF)
+func IsDeviceProfile(index uint16) bool {
+	return index >= AreaDeviceProfileStart && index <= AreaDeviceProfileEnd
+}
+
+// IsInterfaceProfile returns true if index is in the standardized interface profile area (0xA000 - 0xBFFF)
+func IsInterfaceProfile(index uint16) bool {
+	return index >= AreaInterfaceProfileStart && index <= AreaInterfaceProfileEnd
+}
+
+// RPDOCommParamIndex returns the communication parameter index of RPDO pdoNb (1 - 512)
+func RPDOCommParamIndex(pdoNb uint16) uint16 {
+	return EntryRPDOCommunicationStart + pdoNb - 1
+}
+
+// RPDOMappingParamIndex returns the mapping parameter index of RPDO pdoNb (1 - 512)
+func RPDOMappingParamIndex(pdoNb uint16) uint16 {
+	return EntryRPDOMappingStart + pdoNb - 1
+}
+
+// TPDOCommParamIndex returns the communication parameter index of TPDO pdoNb (1 - 512)
+func TPDOCommParamIndex(pdoNb uint16) uint16 {
+	return EntryTPDOCommunicationStart + pdoNb - 1
+}
+
+// TPDOMappingParamIndex returns the mapping parameter index of TPDO pdoNb (1 - 512)
+func TPDOMappingParamIndex(pdoNb uint16) uint16 {
+	return EntryTPDOMappingStart + pdoNb - 1
+}
+
+// PDONumber returns the RPDO or TPDO number (1 - 512) of a PDO communication
+// or mapping parameter index, and whether it is an RPDO.
+// ok is false if index is not a PDO parameter.
+func PDONumber(index uint16) (pdoNb uint16, isRPDO bool, ok bool) {
+	switch {
+	case IsRPDOCommParam(index):
+		return index - EntryRPDOCommunicationStart + 1, true, true
+	case IsRPDOMappingParam(index):
+		return index - EntryRPDOMappingStart + 1, true, true
+	case IsTPDOCommParam(index):
+		return index - EntryTPDOCommunicationStart + 1, false, true
+	case IsTPDOMappingParam(index):
+		return index - EntryTPDOMappingStart + 1, false, true
+	default:
+		return 0, false, false
+	}
+}
@@ -136,6 +136,42 @@ func (od *ObjectDictionary) AddTPDO(tpdoNb uint16) error {
 	return od.addPDO(tpdoNb, false)
 }
 
+// AddSDOServer adds an additional SDO server parameter entry to the OD,
+// at 0x1200 + serverNb. The channel is disabled by default, COB-IDs should
+// be written before use. This however does not create the corresponding CANopen objects
+func (od *ObjectDictionary) AddSDOServer(serverNb uint8) (*Entry, error) {
+	index := EntrySDOServerParameter + uint16(serverNb)
+	if serverNb == 0 || index > EntrySDOServerParameterEnd {
+		return nil, ErrDevIncompat
+	}
+	record := NewRecord()
+	record.AddSubObject(0, "Highest sub-index supported", UNSIGNED8, AttributeSdoR, "0x3")
+	record.AddSubObject(1, "COB-ID client to server", UNSIGNED32, AttributeSdoRw, "0x80000000")
+	record.AddSubObject(2, "COB-ID server to client", UNSIGNED32, AttributeSdoRw, "0x80000000")
+	record.AddSubObject(3, "Node-ID of the SDO client", UNSIGNED8, AttributeSdoRw, "0x1")
+	entry := od.AddVariableList(index, fmt.Sprintf("SDO server parameter %d", serverNb), record)
+	od.logger.Info("added new SDO server object to OD", "nb", serverNb)
+	return entry, nil
+}
+
+// AddSDOClient adds an SDO client parameter entry to the OD,
+// at 0x1280 + clientNb. The channel is disabled by default, COB-IDs should
+// be written before use. This however does not create the corresponding CANopen objects
+func (od *ObjectDictionary) AddSDOClient(clientNb uint8) (*Entry, error) {
+	index := EntrySDOClientParameter + uint16(clientNb)
+	if index > EntrySDOClientParameterEnd {
+		return nil, ErrDevIncompat
+	}
+	record := NewRecord()
+	record.AddSubObject(0, "Highest sub-index supported", UNSIGNED8, AttributeSdoR, "0x3")
+	record.AddSubObject(1, "COB-ID client to server", UNSIGNED32, AttributeSdoRw, "0x80000000")
+	record.AddSubObject(2, "COB-ID server to client", UNSIGNED32, AttributeSdoRw, "0x80000000")
+	record.AddSubObject(3, "Node-ID of the SDO server", UNSIGNED8, AttributeSdoRw, "0x1")
+	entry := od.AddVariableList(index, fmt.Sprintf("SDO client parameter %d", clientNb), record)
+	od.logger.Info("added new SDO client object to OD", "nb", clientNb)
+	return entry, nil
+}
+
 // AddSYNC adds a SYNC entry to the OD.
 // This adds objects 0x1005, 0x1006, 0x1007 & 0x1019 to the OD.
 // By default, SYNC is added with producer disabled and can id of 0x80
@@ -147,6 +183,43 @@ func (od *ObjectDictionary) AddSYNC() {
 	od.logger.Info("added new SYNC object to OD")
 }
 
+// AddObjectScannerList adds an MPDO object scanner list to the OD.
+// The list is an ARRAY at 0x1FA0 + listNb with nbEntries UNSIGNED32 values,
+// each being block size (bits 24-31), sub-index (bits 16-23) & index (bits 0-15)
+// of objects that can be sent with a SAM MPDO.
+func (od *ObjectDictionary) AddObjectScannerList(listNb uint8, nbEntries uint8) (*Entry, error) {
+	return od.addMPDOList(EntryObjectScannerListStart, EntryObjectScannerListEnd, "Object scanner list", UNSIGNED32, listNb, nbEntries)
+}
+
+// AddObjectDispatchingList adds an MPDO object dispatching list to the OD.
+// The list is an ARRAY at 0x1FD0 + listNb with nbEntries UNSIGNED64 values,
+// each being block size (bits 56-63), local index (bits 40-55), local sub-index (bits 32-39),
+// producer index (bits 16-31), producer sub-index (bits 8-15) & producer node id (bits 0-7)
+// of objects that are received with a SAM MPDO.
+func (od *ObjectDictionary) AddObjectDispatchingList(listNb uint8, nbEntries uint8) (*Entry, error) {
+	return od.addMPDOList(EntryObjectDispatchingListStart, EntryObjectDispatchingListEnd, "Object dispatching list", UNSIGNED64, listNb, nbEntries)
+}
+
+func (od *ObjectDictionary) addMPDOList(start uint16, end uint16, name string, datatype uint8, listNb uint8, nbEntries uint8) (*Entry, error) {
+	index := start + uint16(listNb)
+	if index > end || nbEntries == 0 || nbEntries > 254 {
+		return nil, ErrDevIncompat
+	}
+	array := NewArray(nbEntries + 1)
+	_, err := array.AddSubObject(0, "Number of entries", UNSIGNED8, AttributeSdoRw, fmt.Sprintf("0x%x", nbEntries))
+	if err != nil {
+		return nil, err
+	}
+	for i := uint8(1); i <= nbEntries; i++ {
+		_, err = array.AddSubObject(i, fmt.Sprintf("Entry %d", i), datatype, AttributeSdoRw, "0x0")
+		if err != nil {
+			return nil, err
+		}
+	}
+	od.logger.Info("added new MPDO list to OD", "name", name, "index", fmt.Sprintf("x%x", index))
+	return od.AddVariableList(index, name, array), nil
+}
+
 // Index returns an OD entry at the specified index.
 // index can either be a string, int or uint16.
 // This method does not return an error (for chaining with Subindex()) but instead returns
@@ -27,6 +27,10 @@ func TestFind(t *testing.T) {
 	variable, err := od.Index(0x3016).SubIndex(0)
 	assert.Nil(t, err)
 	assert.NotNil(t, variable)
+	_, err = od.Index(0x3016).SubIndex(uint8(0))
+	assert.Nil(t, err)
+	_, err = od.Index(0x3016).SubIndex(uint8(1))
+	assert.Equal(t, ErrSubNotExist, err)
 }
 
 // Test reading OD variables
@@ -57,7 +61,7 @@ func TestReadWriteDisabled(t *testing.T) {
 	od := Default()
 	entry := od.Index(0x2001)
 	assert.NotNil(t, entry)
-	extension := extension{object: nil, read: ReadEntryDisabled, write: WriteEntryDisabled, flagsPDO: [32]uint8{0}}
+	extension := extension{object: nil, read: ReadEntryDisabled, write: WriteEntryDisabled}
 	entry.extension = &extension
 	streamer, err := NewStreamer(entry, 0, false)
 	assert.Nil(t, err)
@@ -81,3 +85,42 @@ func TestAddReader(t *testing.T) {
 	buffer := bytes.NewReader(make([]byte, 10))
 	od.AddReader(0x1, "hello", buffer)
 }
+
+func TestStandardIndexHelpers(t *testing.T) {
+	assert.True(t, IsRPDOCommParam(0x1400))
+	assert.True(t, IsRPDOCommParam(0x15FF))
+	assert.False(t, IsRPDOCommParam(0x1600))
+	assert.True(t, IsRPDOMappingParam(0x1600))
+	assert.True(t, IsTPDOCommParam(0x1805))
+	assert.True(t, IsTPDOMappingParam(0x1BFF))
+	assert.False(t, IsPDOMappingParam(0x1C00))
+	assert.True(t, IsSDOServerParam(0x1200))
+	assert.True(t, IsSDOClientParam(0x1280))
+	assert.True(t, IsManufacturerSpecific(0x2000))
+	assert.True(t, IsDeviceProfile(0x6000))
+	assert.False(t, IsCommunicationProfile(0x2000))
+
+	assert.EqualValues(t, 0x1401, RPDOCommParamIndex(2))
+	assert.EqualValues(t, 0x1601, RPDOMappingParamIndex(2))
+	assert.EqualValues(t, 0x1803, TPDOCommParamIndex(4))
+	assert.EqualValues(t, 0x1A03, TPDOMappingParamIndex(4))
+
+	pdoNb, isRPDO, ok := PDONumber(0x1A03)
+	assert.True(t, ok)
+	assert.False(t, isRPDO)
+	assert.EqualValues(t, 4, pdoNb)
+	pdoNb, isRPDO, ok = PDONumber(0x1601)
+	assert.True(t, ok)
+	assert.True(t, isRPDO)
+	assert.EqualValues(t, 2, pdoNb)
+	_, _, ok = PDONumber(0x1000)
+	assert.False(t, ok)
+
+	od := Default()
+	vendorId, err := od.Index(EntryIdentityObject).Uint32(SubIdentityVendorId)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, vendorId)
+	cobId, err := od.Index(TPDOCommParamIndex(1)).Uint32(SubPDOCobId)
+	assert.Nil(t, err)
+	assert.NotZero(t, cobId)
+}
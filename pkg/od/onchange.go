@@ -0,0 +1,73 @@
+package od
+
+import (
+	"bytes"
+	"sync"
+)
+
+// Subindexes of an entry transmitted on change, see [Entry.SetTPDOOnChange]
+type tpdoOnChange struct {
+	mu      sync.Mutex
+	entry   *Entry
+	enabled [FlagsPdoSize]uint8
+}
+
+func (onChange *tpdoOnChange) isEnabled(subIndex uint8) bool {
+	onChange.mu.Lock()
+	defer onChange.mu.Unlock()
+	return onChange.enabled[subIndex>>3]&(1<<(subIndex&0x07)) != 0
+}
+
+// SetTPDOOnChange enables or disables the transmission of the event driven TPDOs
+// mapping subIndex whenever a write (e.g. SDO download, RPDO) changes its value.
+// Writes of the same value do not trigger any transmission.
+// Values are compared on the last segment of a write, which is the whole value
+// for PDO mappable entries.
+// This pushes an extension on the entry, see [Entry.PushExtension], so it should be
+// called after [Entry.AddExtension] which replaces the whole chain.
+func (entry *Entry) SetTPDOOnChange(subIndex uint8, enabled bool) error {
+	if _, err := entry.SubIndex(subIndex); err != nil {
+		return err
+	}
+	if entry.onChange == nil {
+		if !enabled {
+			return nil
+		}
+		entry.onChange = &tpdoOnChange{entry: entry}
+		entry.PushExtension(entry.onChange, ReadNext, WriteEntryTPDOOnChange)
+	}
+	onChange := entry.onChange
+	onChange.mu.Lock()
+	defer onChange.mu.Unlock()
+	if enabled {
+		onChange.enabled[subIndex>>3] |= 1 << (subIndex & 0x07)
+	} else {
+		onChange.enabled[subIndex>>3] &^= 1 << (subIndex & 0x07)
+	}
+	return nil
+}
+
+// [StreamWriter] added by [Entry.SetTPDOOnChange], it requests TPDO
+// transmission if the written value differs from the previous one.
+func WriteEntryTPDOOnChange(stream *Stream, data []byte, countWritten *uint16) error {
+	onChange, ok := stream.Object.(*tpdoOnChange)
+	if !ok || stream.mu == nil || !onChange.isEnabled(stream.Subindex) {
+		return WriteNext(stream, data, countWritten)
+	}
+	var buffer [8]byte
+	stream.mu.RLock()
+	previous := append(buffer[:0], stream.Data[:min(stream.DataLength, uint32(len(stream.Data)))]...)
+	stream.mu.RUnlock()
+
+	err := WriteNext(stream, data, countWritten)
+	if err != nil {
+		return err
+	}
+	stream.mu.RLock()
+	changed := !bytes.Equal(previous, stream.Data[:min(stream.DataLength, uint32(len(stream.Data)))])
+	stream.mu.RUnlock()
+	if changed {
+		onChange.entry.RequestTPDO(stream.Subindex)
+	}
+	return nil
+}
@@ -0,0 +1,491 @@
+package od
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Mode of [ParseWithDiagnostics]
+type ParseMode uint8
+
+const (
+	// Any spec violation fails parsing, all of them are returned
+	ParseModeStrict ParseMode = iota + 1
+	// Spec violations are returned as warnings, faulty objects are either
+	// skipped or completed with defaults and the rest of the file is loaded
+	ParseModeLenient
+)
+
+// A spec violation found in an EDS file
+type Diagnostic struct {
+	Line    int    // Line number, starting at 1
+	Section string // Section name, e.g. "1018sub1", empty if outside of a section
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Section == "" {
+		return fmt.Sprintf("line %d : %v", d.Line, d.Message)
+	}
+	return fmt.Sprintf("line %d [%v] : %v", d.Line, d.Section, d.Message)
+}
+
+// Sections listing the objects of the file
+var objectListSections = []string{"mandatoryobjects", "optionalobjects", "manufacturerobjects"}
+
+// ParseWithDiagnostics parses an EDS file like [ParseV2], but also checks it against CiA 306
+// and reports every violation with its line number & section, e.g. to debug vendor files
+// that fail to load. file can be either a path, an io.Reader or []byte.
+// In [ParseModeStrict], any violation fails parsing and the error joins all of them,
+// each wrapping [ErrEdsFormat]. In [ParseModeLenient], the OD is loaded anyway and the
+// violations are only returned.
+func ParseWithDiagnostics(file any, nodeId uint8, mode ParseMode) (*ObjectDictionary, []Diagnostic, error) {
+	data, err := readEDS(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	diag := newEdsDiagnostics()
+	od, err := parseV2(data, nodeId, diag)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(diag.diagnostics, func(i, j int) bool {
+		return diag.diagnostics[i].Line < diag.diagnostics[j].Line
+	})
+	if mode == ParseModeStrict && len(diag.diagnostics) > 0 {
+		errs := make([]error, 0, len(diag.diagnostics))
+		for _, diagnostic := range diag.diagnostics {
+			errs = append(errs, fmt.Errorf("%v : %w", diagnostic, ErrEdsFormat))
+		}
+		return nil, diag.diagnostics, errors.Join(errs...)
+	}
+	return od, diag.diagnostics, nil
+}
+
+// ParseStrict is a [Parser] that fails on any spec violation, see [ParseWithDiagnostics]
+func ParseStrict(file any, nodeId uint8) (*ObjectDictionary, error) {
+	od, _, err := ParseWithDiagnostics(file, nodeId, ParseModeStrict)
+	return od, err
+}
+
+// ParseLenient is a [Parser] that loads as much as possible and logs
+// spec violations as warnings, see [ParseWithDiagnostics]
+func ParseLenient(file any, nodeId uint8) (*ObjectDictionary, error) {
+	od, diagnostics, err := ParseWithDiagnostics(file, nodeId, ParseModeLenient)
+	for _, diagnostic := range diagnostics {
+		_logger.Warn("EDS format",
+			"line", diagnostic.Line,
+			"section", diagnostic.Section,
+			"message", diagnostic.Message,
+		)
+	}
+	return od, err
+}
+
+func readEDS(file any) ([]byte, error) {
+	var data []byte
+	var err error
+	switch fType := file.(type) {
+	case string:
+		data, err = os.ReadFile(fType)
+	case []byte:
+		data = fType
+	case io.Reader:
+		data, err = io.ReadAll(fType)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Some editors add a UTF-8 byte order mark
+	return bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), nil
+}
+
+// Error about an object section, reported on the line of key if any
+type edsKeyError struct {
+	key     string
+	message string
+}
+
+func (e *edsKeyError) Error() string {
+	return e.message
+}
+
+func keyErrorf(key string, format string, args ...any) error {
+	return &edsKeyError{key: key, message: fmt.Sprintf(format, args...)}
+}
+
+type edsObjectList struct {
+	name string
+	line int
+	keys []compactKey
+}
+
+type edsSectionRef struct {
+	name string
+	line int
+}
+
+// Spec violations found while parsing, see [parseV2].
+// All the methods do nothing on a nil collector, i.e. when parsing without
+// diagnostics, or return what the parser would use without diagnostics.
+type edsDiagnostics struct {
+	diagnostics []Diagnostic
+	// Current section
+	section  string
+	line     int
+	keyLines map[string]int // Lines of the keys, key names are case insensitive
+	sections map[string]int // First line of each section, by lower case name
+	lists    []*edsObjectList
+	entries  map[uint16]edsSectionRef // Object sections, even if faulty
+	subLines map[uint32]int           // First line of each index & subindex
+	orphans  []orphanSubSection
+	// Current object & its sub-objects
+	index         uint16
+	hasIndex      bool
+	entry         *Entry // nil if object is faulty
+	entryRef      edsSectionRef
+	compact       bool
+	subNumber     int // -1 if unknown
+	subNumberLine int
+	subCount      int
+}
+
+// Sub-object section that does not follow its object section
+type orphanSubSection struct {
+	edsSectionRef
+	index uint16
+}
+
+func newEdsDiagnostics() *edsDiagnostics {
+	return &edsDiagnostics{
+		diagnostics: []Diagnostic{},
+		keyLines:    map[string]int{},
+		sections:    map[string]int{},
+		entries:     map[uint16]edsSectionRef{},
+		subLines:    map[uint32]int{},
+	}
+}
+
+func (d *edsDiagnostics) reportAt(line int, section string, format string, args ...any) {
+	if d == nil {
+		return
+	}
+	d.diagnostics = append(d.diagnostics, Diagnostic{Line: line, Section: section, Message: fmt.Sprintf(format, args...)})
+}
+
+// Report a violation in current section
+func (d *edsDiagnostics) report(line int, format string, args ...any) {
+	if d == nil {
+		return
+	}
+	d.reportAt(line, d.section, format, args...)
+}
+
+// Report a violation on the line of key in current section
+func (d *edsDiagnostics) reportKey(key string, format string, args ...any) {
+	if d == nil {
+		return
+	}
+	d.report(d.keyLine(key), format, args...)
+}
+
+func (d *edsDiagnostics) reportError(err error) {
+	var keyErr *edsKeyError
+	if errors.As(err, &keyErr) {
+		d.reportKey(keyErr.key, "%v", keyErr.message)
+		return
+	}
+	d.report(d.line, "%v", err)
+}
+
+// Line of key in current section, or of the section if key is missing
+func (d *edsDiagnostics) keyLine(key string) int {
+	if line, ok := d.keyLines[strings.ToLower(key)]; ok {
+		return line
+	}
+	return d.line
+}
+
+// Start a new section, returns true if it is a duplicate
+func (d *edsDiagnostics) beginSection(name string, line int) bool {
+	if d == nil {
+		return false
+	}
+	d.section = name
+	d.line = line
+	d.keyLines = map[string]int{}
+	lowerName := strings.ToLower(name)
+	if first, ok := d.sections[lowerName]; ok {
+		d.report(line, "duplicate section, first defined line %d", first)
+		return true
+	}
+	d.sections[lowerName] = line
+	for _, list := range objectListSections {
+		if lowerName == list {
+			d.lists = append(d.lists, &edsObjectList{name: name, line: line})
+		}
+	}
+	return false
+}
+
+func (d *edsDiagnostics) malformedSection(line []byte, lineNb int) {
+	d.reportAt(lineNb, "", "malformed section header %q", line)
+	d.section = ""
+	d.line = 0
+}
+
+// Check a key-value line of current section, returns false if it should be skipped
+func (d *edsDiagnostics) checkKey(line []byte, equalsIdx int, lineNb int) bool {
+	if d == nil {
+		return true
+	}
+	if d.line == 0 {
+		d.reportAt(lineNb, "", "%q outside of a section", line)
+		return false
+	}
+	if equalsIdx <= 0 {
+		d.report(lineNb, "expecting key=value, got %q", line)
+		return false
+	}
+	key := string(trimSpaces(line[:equalsIdx]))
+	lowerKey := strings.ToLower(key)
+	if first, ok := d.keyLines[lowerKey]; ok {
+		d.report(lineNb, "duplicate key %q, first defined line %d", key, first)
+	}
+	d.keyLines[lowerKey] = lineNb
+	if len(d.lists) > 0 && d.lists[len(d.lists)-1].line == d.line {
+		list := d.lists[len(d.lists)-1]
+		list.keys = append(list.keys, compactKey{key: lowerKey, value: string(trimSpaces(line[equalsIdx+1:])), line: lineNb})
+	}
+	return true
+}
+
+func (d *edsDiagnostics) checkUint(key string, value string, max uint64) {
+	if d == nil {
+		return
+	}
+	if nb, err := strconv.ParseUint(value, 0, 16); err != nil || nb > max {
+		d.reportKey(key, "invalid %v %q", key, value)
+	}
+}
+
+// Access type to use, rw if invalid
+func (d *edsDiagnostics) checkAccessType(accessType string) string {
+	if d == nil {
+		return accessType
+	}
+	switch accessType {
+	case "ro", "wo", "rw", "rwr", "rww", "const":
+		return accessType
+	case "":
+		d.report(d.line, "missing AccessType, using rw")
+	default:
+		d.reportKey("AccessType", "invalid AccessType %q, using rw", accessType)
+	}
+	return "rw"
+}
+
+// PDO mapping to use, 0 if invalid
+func (d *edsDiagnostics) checkPDOMapping(pdoMapping string) string {
+	if d == nil {
+		return pdoMapping
+	}
+	switch pdoMapping {
+	case "", "0", "1":
+		return pdoMapping
+	default:
+		d.reportKey("PDOMapping", "invalid PDOMapping %q, using 0", pdoMapping)
+		return "0"
+	}
+}
+
+func (d *edsDiagnostics) checkSubNumber(subNumber string, value uint64, err error) {
+	if d == nil {
+		return
+	}
+	d.subNumber = -1
+	switch {
+	case subNumber == "":
+		d.report(d.line, "missing SubNumber")
+	case err != nil:
+		d.reportKey("SubNumber", "invalid SubNumber %q", subNumber)
+	default:
+		d.subNumber = int(value)
+		d.subNumberLine = d.keyLine("SubNumber")
+	}
+}
+
+// New object section
+func (d *edsDiagnostics) addEntrySection(index uint16) {
+	if d == nil {
+		return
+	}
+	d.index = index
+	d.hasIndex = true
+	d.entry = nil
+	d.entryRef = edsSectionRef{name: d.section, line: d.line}
+	d.compact = false
+	d.subNumber = -1
+	d.subCount = 0
+	d.entries[index] = d.entryRef
+}
+
+// Object of current object section was created
+func (d *edsDiagnostics) beginEntry(entry *Entry, compactSubObj string) {
+	if d == nil {
+		return
+	}
+	d.entry = entry
+	compact, _ := strconv.ParseUint(compactSubObj, 0, 8)
+	d.compact = compact > 0
+}
+
+// Check a new sub-object section, returns false if it should be skipped
+func (d *edsDiagnostics) checkSubSection(index uint16, subindex uint64) bool {
+	if d == nil {
+		return true
+	}
+	if subindex > 0xFF {
+		d.report(d.line, "subindex out of range")
+		return false
+	}
+	if !d.hasIndex || d.index != index {
+		d.orphans = append(d.orphans, orphanSubSection{edsSectionRef{name: d.section, line: d.line}, index})
+		return false
+	}
+	if d.entry == nil {
+		// Object is faulty & was already reported
+		return false
+	}
+	if d.compact {
+		d.report(d.line, "sub-object of an ARRAY using CompactSubObj")
+		return false
+	}
+	return true
+}
+
+// Sub-object of current section is about to be created, returns false if duplicate
+func (d *edsDiagnostics) addSubEntry(index uint16, subindex uint8) bool {
+	if d == nil {
+		return true
+	}
+	key := uint32(index)<<8 | uint32(subindex)
+	if first, ok := d.subLines[key]; ok {
+		d.report(d.line, "duplicate sub-object, first defined line %d", first)
+		return false
+	}
+	d.subLines[key] = d.line
+	d.subCount++
+	return true
+}
+
+// All the sub-objects of current object were created
+func (d *edsDiagnostics) endEntry() {
+	if d == nil || !d.hasIndex {
+		return
+	}
+	d.hasIndex = false
+	entry := d.entry
+	d.entry = nil
+	if entry == nil || d.compact {
+		return
+	}
+	list, ok := entry.object.(*VariableList)
+	if !ok {
+		return
+	}
+	if d.subNumber >= 0 && d.subNumber != d.subCount {
+		d.reportAt(d.subNumberLine, d.entryRef.name, "SubNumber is %d but %d sub-objects are defined", d.subNumber, d.subCount)
+	}
+	if d.subCount == 0 {
+		d.reportAt(d.entryRef.line, d.entryRef.name, "missing sub-object 0")
+	}
+	if entry.ObjectType != ObjectTypeARRAY {
+		return
+	}
+	// Array sub-objects are accessed by position, so drop everything after a hole
+	for len(list.Variables) > 0 && list.Variables[len(list.Variables)-1] == nil {
+		list.Variables = list.Variables[:len(list.Variables)-1]
+	}
+	for subindex, variable := range list.Variables {
+		if variable == nil {
+			d.reportAt(d.entryRef.line, d.entryRef.name, "missing sub-object %d of ARRAY", subindex)
+			list.Variables = list.Variables[:subindex]
+			break
+		}
+	}
+}
+
+// Report a section referring to an object without section
+func (d *edsDiagnostics) missingObject(index uint16, line int, section string) {
+	if d == nil {
+		return
+	}
+	if _, ok := d.entries[index]; !ok {
+		d.reportAt(line, section, "no section for object x%x", index)
+	}
+}
+
+// Check the sub-objects out of their object & that object lists match the object sections
+func (d *edsDiagnostics) checkObjectLists() {
+	if d == nil {
+		return
+	}
+	for _, orphan := range d.orphans {
+		if _, ok := d.entries[orphan.index]; ok {
+			d.reportAt(orphan.line, orphan.name, "sub-object does not follow the section of object x%x", orphan.index)
+		} else {
+			d.reportAt(orphan.line, orphan.name, "no section for object x%x", orphan.index)
+		}
+	}
+	if len(d.lists) == 0 {
+		return
+	}
+	listed := map[uint16]bool{}
+	for _, list := range d.lists {
+		count := 0
+		var supportedObjects *compactKey
+		for i, key := range list.keys {
+			if key.key == "supportedobjects" {
+				supportedObjects = &list.keys[i]
+				continue
+			}
+			count++
+			if _, err := strconv.ParseUint(key.key, 10, 16); err != nil {
+				d.reportAt(key.line, list.name, "invalid object number %q", key.key)
+			}
+			index, err := strconv.ParseUint(key.value, 0, 16)
+			if err != nil {
+				d.reportAt(key.line, list.name, "invalid index %q", key.value)
+				continue
+			}
+			if listed[uint16(index)] {
+				d.reportAt(key.line, list.name, "object x%x is listed more than once", index)
+			}
+			listed[uint16(index)] = true
+			if _, ok := d.entries[uint16(index)]; !ok {
+				d.reportAt(key.line, list.name, "no section for listed object x%x", index)
+			}
+		}
+		if supportedObjects == nil {
+			d.reportAt(list.line, list.name, "missing SupportedObjects")
+		} else if supported, err := strconv.ParseUint(supportedObjects.value, 0, 16); err != nil {
+			d.reportAt(supportedObjects.line, list.name, "invalid SupportedObjects %q", supportedObjects.value)
+		} else if int(supported) != count {
+			d.reportAt(supportedObjects.line, list.name, "SupportedObjects is %d but %d objects are listed", supported, count)
+		}
+	}
+	for index, section := range d.entries {
+		if !listed[index] {
+			d.reportAt(section.line, section.name, "object is not listed in any object list")
+		}
+	}
+}
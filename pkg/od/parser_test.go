@@ -1,17 +1,213 @@
 package od
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
+const faultyEds = `[MandatoryObjects]
+SupportedObjects=2
+1=0x1000
+
+[1000]
+ParameterName=Device type
+DataType=0x0007
+AccessType=ro
+DefaultValue=0x1234
+
+[1001]
+ParameterName=Error register
+DataType=0x0005
+AccessType=read
+PDOMapping=yes
+DefaultValue=0x1FF
+
+[1018]
+ParameterName=Identity
+ObjectType=0x8
+SubNumber=3
+
+[1018sub0]
+ParameterName=Highest sub-index supported
+DataType=0x0005
+AccessType=ro
+DefaultValue=2
+
+[1018sub2]
+ParameterName=Product code
+DataType=0x0007
+AccessType=ro
+AccessType=rw
+
+[2000sub1]
+ParameterName=Orphan
+DataType=0x0005
+AccessType=rw
+
+[2001]
+ParameterName=Unknown
+DataType=0x0005
+AccessType=rw
+missing equals
+`
+
+const compactEds = `[DeviceInfo]
+CompactPDO=0x1
+NrOfRXPDO=2
+NrOfTXPDO=1
+
+[1003]
+ParameterName=Pre-defined error field
+ObjectType=0x8
+DataType=0x0007
+AccessType=ro
+DefaultValue=0
+PDOMapping=0
+CompactSubObj=4
+
+[1003Name]
+NrOfEntries=1
+2=Second error
+
+[1003Value]
+NrOfEntries=1
+3=$NODEID+0x10
+`
+
 func TestParseDefault(t *testing.T) {
 
 	od := Default()
 	assert.NotNil(t, od)
 }
 
+func TestParseWithDiagnostics(t *testing.T) {
+	t.Run("default od is valid", func(t *testing.T) {
+		odict, diagnostics, err := ParseWithDiagnostics(rawDefaultOd, 0, ParseModeStrict)
+		assert.Nil(t, err)
+		assert.Empty(t, diagnostics)
+		assert.Empty(t, Compare(Default(), odict))
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		odict, diagnostics, err := ParseWithDiagnostics([]byte(faultyEds), 0, ParseModeStrict)
+		assert.Nil(t, odict)
+		assert.ErrorIs(t, err, ErrEdsFormat)
+		assert.Equal(t, []Diagnostic{
+			{Line: 2, Section: "MandatoryObjects", Message: "SupportedObjects is 2 but 1 objects are listed"},
+			{Line: 11, Section: "1001", Message: "object is not listed in any object list"},
+			{Line: 14, Section: "1001", Message: `invalid AccessType "read", using rw`},
+			{Line: 15, Section: "1001", Message: `invalid PDOMapping "yes", using 0`},
+			{Line: 16, Section: "1001", Message: `invalid DefaultValue "0x1FF" for data type x5`},
+			{Line: 18, Section: "1018", Message: "missing sub-object 1 of ARRAY"},
+			{Line: 18, Section: "1018", Message: "object is not listed in any object list"},
+			{Line: 21, Section: "1018", Message: "SubNumber is 3 but 2 sub-objects are defined"},
+			{Line: 33, Section: "1018sub2", Message: `duplicate key "AccessType", first defined line 32`},
+			{Line: 35, Section: "2000sub1", Message: "no section for object x2000"},
+			{Line: 40, Section: "2001", Message: "object is not listed in any object list"},
+			{Line: 44, Section: "2001", Message: `expecting key=value, got "missing equals"`},
+		}, diagnostics)
+		assert.Contains(t, err.Error(), `line 44 [2001] : expecting key=value, got "missing equals" : invalid EDS format`)
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		odict, diagnostics, err := ParseWithDiagnostics([]byte(faultyEds), 0, ParseModeLenient)
+		assert.Nil(t, err)
+		assert.Len(t, diagnostics, 12)
+		deviceType, err := odict.Index(0x1000).Uint32(0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x1234, deviceType)
+		errorRegister, err := odict.Index(0x1001).SubIndex(0)
+		assert.Nil(t, err)
+		assert.Equal(t, AttributeSdoRw, errorRegister.Attribute)
+		// Sub-objects after the hole are dropped
+		assert.Equal(t, 1, odict.Index(0x1018).SubCount())
+		assert.Nil(t, odict.Index(0x2000))
+		assert.NotNil(t, odict.Index(0x2001))
+	})
+}
+
+// Variables of an entry, by subindex
+func entryVariables(entry *Entry) []*Variable {
+	switch object := entry.object.(type) {
+	case *Variable:
+		return []*Variable{object}
+	case *VariableList:
+		return object.Variables
+	}
+	return nil
+}
+
+func TestParseWithDiagnosticsMatchesV2(t *testing.T) {
+	zipped, err := os.ReadFile("../../testdata/test_zipped_format.eds")
+	assert.Nil(t, err)
+	fixtures := map[string][]byte{
+		"base":    rawDefaultOd,
+		"zipped":  zipped,
+		"compact": []byte(compactEds),
+	}
+	for name, fixture := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			expected, err := ParseV2(fixture, 0x10)
+			assert.Nil(t, err)
+			odict, _, err := ParseWithDiagnostics(fixture, 0x10, ParseModeLenient)
+			assert.Nil(t, err)
+			assert.Len(t, odict.entriesByIndexValue, len(expected.entriesByIndexValue))
+			for index, expectedEntry := range expected.entriesByIndexValue {
+				entry := odict.Index(index)
+				if !assert.NotNil(t, entry, "x%x", index) {
+					continue
+				}
+				assert.Equal(t, expectedEntry.Name, entry.Name)
+				assert.Equal(t, expectedEntry.ObjectType, entry.ObjectType)
+				assert.Equal(t, expectedEntry.subEntriesNameMap, entry.subEntriesNameMap)
+				assert.Equal(t, entryVariables(expectedEntry), entryVariables(entry), "x%x", index)
+			}
+		})
+	}
+}
+
+func TestParseCompact(t *testing.T) {
+	parsers := map[string]Parser{"v1": Parse, "v2": ParseV2, "strict": ParseStrict}
+	for name, parser := range parsers {
+		t.Run(name, func(t *testing.T) {
+			odict, err := parser([]byte(compactEds), 0x10)
+			assert.Nil(t, err)
+			entry := odict.Index(0x1003)
+			assert.Equal(t, ObjectTypeARRAY, entry.ObjectType)
+			assert.Equal(t, 5, entry.SubCount())
+			nbSubs, err := entry.SubIndex(0)
+			assert.Nil(t, err)
+			assert.Equal(t, AttributeSdoR, nbSubs.Attribute)
+			assert.Equal(t, []byte{4}, nbSubs.valueDefault)
+
+			sub1, err := entry.SubIndex(1)
+			assert.Nil(t, err)
+			assert.Equal(t, "Pre-defined error field1", sub1.Name)
+			assert.Equal(t, uint8(UNSIGNED32), sub1.DataType)
+			sub2, err := entry.SubIndex("Second error")
+			assert.Nil(t, err)
+			assert.EqualValues(t, 2, sub2.SubIndex)
+			sub3, err := entry.Uint32(3)
+			assert.Nil(t, err)
+			assert.EqualValues(t, 0x20, sub3)
+			sub4, err := entry.Uint32(4)
+			assert.Nil(t, err)
+			assert.EqualValues(t, 0, sub4)
+			_, err = entry.SubIndex(5)
+			assert.ErrorIs(t, err, ErrSubNotExist)
+
+			// Undescribed PDOs
+			for _, index := range []uint16{0x1400, 0x1401, 0x1600, 0x1601, 0x1800, 0x1A00} {
+				assert.NotNil(t, odict.Index(index), "x%x", index)
+			}
+			assert.Nil(t, odict.Index(0x1402))
+			assert.Nil(t, odict.Index(0x1801))
+		})
+	}
+}
+
 func BenchmarkParser(b *testing.B) {
 	b.Run("od default parse", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
@@ -41,10 +41,18 @@ func Parse(file any, nodeId uint8) (*ObjectDictionary, error) {
 	matchIdxRegExp := regexp.MustCompile(`^[0-9A-Fa-f]{4}$`)
 	matchSubidxRegExp := regexp.MustCompile(`^([0-9A-Fa-f]{4})sub([0-9A-Fa-f]+)$`)
 
+	// Names & values of compact arrays, handled once all entries exist
+	compactSections := []*ini.Section{}
+
 	// Iterate over all the sections
 	for _, section := range sections {
 		sectionName := section.Name()
 
+		if _, _, ok := compactSectionIndex([]byte(sectionName)); ok {
+			compactSections = append(compactSections, section)
+			continue
+		}
+
 		// Match indexes : This adds new entries to the dictionary
 		if matchIdxRegExp.MatchString(sectionName) {
 			// Add a new entry inside object dictionary
@@ -62,6 +70,20 @@ func Parse(file any, nodeId uint8) (*ObjectDictionary, error) {
 				objectType = 7
 			}
 
+			// Compact array, sub-objects are generated from the object section
+			compact, err := strconv.ParseUint(section.Key("CompactSubObj").Value(), 0, 8)
+			if err == nil && compact > 0 {
+				variable, err := NewVariableFromSection(section, name, nodeId, index, 0)
+				if err != nil {
+					return nil, err
+				}
+				err = od.addVariable(index, variable).expandCompact(uint8(compact))
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+
 			// objectType determines what type of entry we should add to dictionary : Variable, Array or Record
 			switch objectType {
 			case ObjectTypeVAR, ObjectTypeDOMAIN:
@@ -118,6 +140,34 @@ func Parse(file any, nodeId uint8) (*ObjectDictionary, error) {
 		}
 	}
 
+	for _, section := range compactSections {
+		index, names, _ := compactSectionIndex([]byte(section.Name()))
+		entry := od.Index(index)
+		if entry == nil {
+			return nil, fmt.Errorf("[OD] index with id %d not found", index)
+		}
+		for _, key := range section.Keys() {
+			if names {
+				err = entry.setCompactName(key.Name(), key.Value())
+			} else {
+				err = entry.setCompactValue(key.Name(), key.Value(), nodeId)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Undescribed PDOs
+	if deviceInfo, err := edsFile.GetSection("DeviceInfo"); err == nil {
+		compactPDO, _ := strconv.ParseUint(deviceInfo.Key("CompactPDO").Value(), 0, 8)
+		nbRPDO, _ := strconv.ParseUint(deviceInfo.Key("NrOfRXPDO").Value(), 0, 16)
+		nbTPDO, _ := strconv.ParseUint(deviceInfo.Key("NrOfTXPDO").Value(), 0, 16)
+		if compactPDO != 0 {
+			od.addCompactPDOs(uint16(nbRPDO), uint16(nbTPDO))
+		}
+	}
+
 	return od, nil
 }
 
@@ -207,6 +257,7 @@ func NewVariableFromSection(
 	}
 	variable.DataType = byte(dataType)
 	variable.Attribute = EncodeAttribute(accessType.String(), pdoMapping, variable.DataType)
+	variable.StorageLocation = section.Key("StorageLocation").String()
 
 	if highLimit, err := section.GetKey("HighLimit"); err == nil {
 		variable.highLimit, err = EncodeFromString(highLimit.Value(), variable.DataType, 0)
@@ -231,15 +282,7 @@ func NewVariableFromSection(
 	}
 
 	if defaultValue, err := section.GetKey("DefaultValue"); err == nil {
-		defaultValueStr := defaultValue.Value()
-		// If $NODEID is in default value then remove it, and add it afterwards
-		if strings.Contains(defaultValueStr, "$NODEID") {
-			re := regexp.MustCompile(`\+?\$NODEID\+?`)
-			defaultValueStr = re.ReplaceAllString(defaultValueStr, "")
-		} else {
-			nodeId = 0
-		}
-		variable.valueDefault, err = EncodeFromString(defaultValueStr, variable.DataType, nodeId)
+		variable.valueDefault, err = encodeWithNodeId(defaultValue.Value(), variable.DataType, nodeId)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse 'DefaultValue' for x%x|x%x, because %v (datatype :x%x)", index, subindex, err, variable.DataType)
 		}
@@ -247,5 +290,24 @@ func NewVariableFromSection(
 		copy(variable.value, variable.valueDefault)
 	}
 
+	// DCF files hold the actual value as parameter value
+	if parameterValue, err := section.GetKey("ParameterValue"); err == nil {
+		variable.value, err = encodeWithNodeId(parameterValue.Value(), variable.DataType, nodeId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'ParameterValue' for x%x|x%x, because %v (datatype :x%x)", index, subindex, err, variable.DataType)
+		}
+	}
+
 	return variable, nil
 }
+
+// Encode value, if $NODEID is in value then remove it, and add it afterwards
+func encodeWithNodeId(value string, dataType uint8, nodeId uint8) ([]byte, error) {
+	if strings.Contains(value, "$NODEID") {
+		re := regexp.MustCompile(`\+?\$NODEID\+?`)
+		value = re.ReplaceAllString(value, "")
+	} else {
+		nodeId = 0
+	}
+	return EncodeFromString(value, dataType, nodeId)
+}
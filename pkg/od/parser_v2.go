@@ -34,7 +34,6 @@ import (
 //   - bufio.Scanner() ==> more performant implementation ?
 func ParseV2(file any, nodeId uint8) (*ObjectDictionary, error) {
 
-	var err error
 	bu := &bytes.Buffer{}
 
 	switch fType := file.(type) {
@@ -52,32 +51,102 @@ func ParseV2(file any, nodeId uint8) (*ObjectDictionary, error) {
 	default:
 		return nil, fmt.Errorf("unsupported type")
 	}
+	return parseV2(bu.Bytes(), nodeId, nil)
+}
+
+// Values of the keys of an object or sub-object section
+type edsValues struct {
+	parameterName   string
+	defaultValue    string
+	parameterValue  string
+	objectType      string
+	pdoMapping      string
+	subNumber       string
+	accessType      string
+	dataType        string
+	storageLocation string
+	compactSubObj   string
+	lowLimit        string
+	highLimit       string
+}
 
+// Parse data, spec violations are reported to diag if not nil.
+// Without diag, parsing stops on the first faulty object. With diag,
+// faulty objects are reported and skipped or completed with defaults.
+func parseV2(data []byte, nodeId uint8, diag *edsDiagnostics) (*ObjectDictionary, error) {
+
+	var err error
 	od := NewOD()
-	od.rawOd = bu.Bytes()
+	od.rawOd = data
 	entry := &Entry{}
 	vList := &VariableList{}
 	isEntry := false
 	isSubEntry := false
 	subindex := uint8(0)
+	values := edsValues{}
 
-	var defaultValue string
-	var parameterName string
-	var objectType string
-	var pdoMapping string
-	var subNumber string
-	var accessType string
-	var dataType string
+	// Names & values of compact arrays, handled once all entries exist
+	compactSections := []*compactSection{}
+	var compact *compactSection
+	// Undescribed PDOs
+	var compactPDO, nbRPDO, nbTPDO string
 
-	scanner := bufio.NewScanner(bu)
+	// Take all the values of the previous section and build it
+	populateSection := func() error {
+		if isEntry {
+			if values.parameterName == "" {
+				if diag == nil {
+					return nil
+				}
+				diag.report(diag.line, "missing ParameterName")
+				values.parameterName = diag.section
+			}
+			entry.Name = values.parameterName
+			od.entriesByIndexName[values.parameterName] = entry
+			vList, err = populateEntry(entry, nodeId, &values, diag)
+			if err != nil {
+				if diag == nil {
+					return fmt.Errorf("failed to create new entry %v", err)
+				}
+				diag.reportError(err)
+				delete(od.entriesByIndexValue, entry.Index)
+				delete(od.entriesByIndexName, values.parameterName)
+				return nil
+			}
+			diag.beginEntry(entry, values.compactSubObj)
+		} else if isSubEntry {
+			if values.parameterName == "" {
+				if diag == nil {
+					return nil
+				}
+				diag.report(diag.line, "missing ParameterName")
+				values.parameterName = diag.section
+			}
+			if !diag.addSubEntry(entry.Index, subindex) {
+				return nil
+			}
+			err = populateSubEntry(entry, vList, nodeId, &values, subindex, diag)
+			if err != nil {
+				if diag == nil {
+					return fmt.Errorf("failed to create sub entry %v", err)
+				}
+				diag.reportError(err)
+			}
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNb := 0
 
 	for scanner.Scan() {
+		lineNb++
 
 		// New line detected
 		lineRaw := scanner.Bytes()
 
 		// Skip if less than 2 chars
-		if len(lineRaw) < 2 {
+		if len(lineRaw) < 2 && diag == nil {
 			continue
 		}
 
@@ -91,62 +160,38 @@ func ParseV2(file any, nodeId uint8) (*ObjectDictionary, error) {
 		// Handle section headers: [section]
 		if line[0] == '[' && line[len(line)-1] == ']' {
 			// A section should be of length 4 at least
-			if len(line) < 4 {
+			if len(line) < 4 && diag == nil {
 				continue
 			}
 
 			// New section, this means we have finished building
 			// Previous one, so take all the values and update the section
-			if parameterName != "" {
-				if isEntry {
-					entry.Name = parameterName
-					od.entriesByIndexName[parameterName] = entry
-					vList, err = populateEntry(
-						entry,
-						nodeId,
-						parameterName,
-						defaultValue,
-						objectType,
-						pdoMapping,
-						accessType,
-						dataType,
-						subNumber,
-					)
-
-					if err != nil {
-						return nil, fmt.Errorf("failed to create new entry %v", err)
-					}
-				} else if isSubEntry {
-					err = populateSubEntry(
-						entry,
-						vList,
-						nodeId,
-						parameterName,
-						defaultValue,
-						pdoMapping,
-						accessType,
-						dataType,
-						subindex,
-					)
-
-					if err != nil {
-						return nil, fmt.Errorf("failed to create sub entry %v", err)
-					}
-				}
+			err = populateSection()
+			if err != nil {
+				return nil, err
 			}
 
 			isEntry = false
 			isSubEntry = false
+			compact = nil
 			sectionBytes := line[1 : len(line)-1]
+			duplicate := false
+			if diag != nil {
+				duplicate = diag.beginSection(string(trimSpaces(sectionBytes)), lineNb)
+			}
 
 			// Check if a sub entry or the actual entry
 			// A subentry should be more than 4 bytes long
-			if isValidHex4(sectionBytes) {
+			if duplicate {
+				// Keys are still checked but the section is dropped
+			} else if isValidHex4(sectionBytes) {
 
 				idx, err := hexAsciiToUint(sectionBytes)
 				if err != nil {
 					return nil, err
 				}
+				diag.endEntry()
+				diag.addEntrySection(uint16(idx))
 				isEntry = true
 				entry = &Entry{}
 				entry.Index = uint16(idx)
@@ -160,165 +205,202 @@ func ParseV2(file any, nodeId uint8) (*ObjectDictionary, error) {
 				if err != nil {
 					return nil, err
 				}
+				idx, _ := hexAsciiToUint(sectionBytes[:4])
 				// TODO we could get entry to double check if ever something is out of order
-				isSubEntry = true
+				isSubEntry = diag.checkSubSection(uint16(idx), sidx)
 				subindex = uint8(sidx)
+			} else if idx, names, ok := compactSectionIndex(sectionBytes); ok {
+				diag.endEntry()
+				compact = &compactSection{index: idx, names: names, name: string(sectionBytes), line: lineNb}
+				compactSections = append(compactSections, compact)
+			} else {
+				diag.endEntry()
 			}
 
 			// Reset all values
-			defaultValue = ""
-			parameterName = ""
-			objectType = ""
-			pdoMapping = ""
-			subNumber = ""
-			accessType = ""
-			dataType = ""
+			values = edsValues{}
 
 			continue
 		}
+		if diag != nil && line[0] == '[' {
+			// Following keys are not added to previous section
+			err = populateSection()
+			if err != nil {
+				return nil, err
+			}
+			isEntry, isSubEntry, compact = false, false, nil
+			diag.endEntry()
+			diag.malformedSection(line, lineNb)
+			continue
+		}
 
 		// We are in a section so we need to populate the given entry
 		// Parse key-value pairs: key = value
 		// We will create variables for storing intermediate values
 		// Once we are at the end of the section
 
-		if equalsIdx := bytes.IndexByte(line, '='); equalsIdx != -1 {
+		equalsIdx := bytes.IndexByte(line, '=')
+		if !diag.checkKey(line, equalsIdx, lineNb) {
+			continue
+		}
+		if equalsIdx != -1 {
 			key := string(trimSpaces(line[:equalsIdx]))
 			value := string(trimSpaces(line[equalsIdx+1:]))
 
+			if compact != nil {
+				compact.keys = append(compact.keys, compactKey{key: key, value: value, line: lineNb})
+				continue
+			}
+
 			// We will get the different elements of the entry
 			switch key {
 			case "ParameterName":
-				parameterName = string(value)
+				values.parameterName = value
 			case "ObjectType":
-				objectType = string(value)
+				values.objectType = value
 			case "SubNumber":
-				subNumber = string(value)
+				values.subNumber = value
 			case "AccessType":
-				accessType = string(value)
+				values.accessType = value
 			case "DataType":
-				dataType = string(value)
+				values.dataType = value
 			case "DefaultValue":
-				defaultValue = string(value)
+				values.defaultValue = value
+			case "ParameterValue":
+				values.parameterValue = value
 			case "PDOMapping":
-				pdoMapping = string(value)
+				values.pdoMapping = value
+			case "StorageLocation":
+				values.storageLocation = value
+			case "CompactSubObj":
+				values.compactSubObj = value
+			case "LowLimit":
+				values.lowLimit = value
+			case "HighLimit":
+				values.highLimit = value
+			case "CompactPDO":
+				compactPDO = value
+				diag.checkUint(key, value, 0xFF)
+			case "NrOfRXPDO":
+				nbRPDO = value
+				diag.checkUint(key, value, 512)
+			case "NrOfTXPDO":
+				nbTPDO = value
+				diag.checkUint(key, value, 512)
 			}
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
 
 	// Last index or subindex part
 	// New section, this means we have finished building
 	// Previous one, so take all the values and update the section
-	if parameterName != "" {
-		if isEntry {
-			entry.Name = parameterName
-			od.entriesByIndexName[parameterName] = entry
-			_, err = populateEntry(
-				entry,
-				nodeId,
-				parameterName,
-				defaultValue,
-				objectType,
-				pdoMapping,
-				accessType,
-				dataType,
-				subNumber,
-			)
+	err = populateSection()
+	if err != nil {
+		return nil, err
+	}
+	diag.endEntry()
 
-			if err != nil {
-				return nil, fmt.Errorf("failed to create new entry %v", err)
+	for _, compact := range compactSections {
+		entry := od.Index(compact.index)
+		if entry == nil {
+			if diag == nil {
+				return nil, fmt.Errorf("no entry x%x for compact section", compact.index)
+			}
+			diag.missingObject(compact.index, compact.line, compact.name)
+			continue
+		}
+		for _, kv := range compact.keys {
+			if compact.names {
+				err = entry.setCompactName(kv.key, kv.value)
+			} else {
+				err = entry.setCompactValue(kv.key, kv.value, nodeId)
 			}
-		} else if isSubEntry {
-			err = populateSubEntry(
-				entry,
-				vList,
-				nodeId,
-				parameterName,
-				defaultValue,
-				pdoMapping,
-				accessType,
-				dataType,
-				subindex,
-			)
-
 			if err != nil {
-				return nil, fmt.Errorf("failed to create sub entry %v", err)
+				if diag == nil {
+					return nil, err
+				}
+				diag.reportAt(kv.line, compact.name, "%v", err)
 			}
 		}
 	}
 
+	if pdo, _ := strconv.ParseUint(compactPDO, 0, 8); pdo != 0 {
+		rx, _ := strconv.ParseUint(nbRPDO, 0, 16)
+		tx, _ := strconv.ParseUint(nbTPDO, 0, 16)
+		od.addCompactPDOs(uint16(rx), uint16(tx))
+	}
+	diag.checkObjectLists()
+
 	return od, nil
 }
 
-func populateEntry(
-	entry *Entry,
-	nodeId uint8,
-	parameterName string,
-	defaultValue string,
-	objectType string,
-	pdoMapping string,
-	accessType string,
-	dataType string,
-	subNumber string,
-) (*VariableList, error) {
+// Keys of a [xxxxName] or [xxxxValue] section
+type compactSection struct {
+	index uint16
+	names bool
+	name  string
+	line  int
+	keys  []compactKey
+}
+
+type compactKey struct {
+	key   string
+	value string
+	line  int
+}
+
+func populateEntry(entry *Entry, nodeId uint8, values *edsValues, diag *edsDiagnostics) (*VariableList, error) {
 
 	oType := uint8(0)
 	// Determine object type
 	// If no object type, default to 7 (CiA spec)
-	if objectType == "" {
+	if values.objectType == "" {
 		oType = 7
 	} else {
-		oTypeUint, err := strconv.ParseUint(objectType, 0, 8)
+		oTypeUint, err := strconv.ParseUint(values.objectType, 0, 8)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse object type %v", err)
+			return nil, keyErrorf("ObjectType", "invalid ObjectType %q", values.objectType)
 		}
 		oType = uint8(oTypeUint)
 	}
+	compact, err := strconv.ParseUint(values.compactSubObj, 0, 8)
+	if diag != nil && values.compactSubObj != "" && (err != nil || compact > compactSubObjMax) {
+		return nil, keyErrorf("CompactSubObj", "invalid CompactSubObj %q", values.compactSubObj)
+	}
+	if compact > 0 {
+		if oType != ObjectTypeARRAY {
+			diag.reportKey("CompactSubObj", "CompactSubObj for a %v", strings.TrimSpace(OBJ_NAME_MAP[oType]))
+		}
+		// Compact array, sub-objects are generated from the object section
+		oType = ObjectTypeVAR
+	}
 	entry.ObjectType = oType
 
 	// Add necessary stuff depending on oType
 	switch oType {
 
 	case ObjectTypeVAR, ObjectTypeDOMAIN:
-		variable := &Variable{}
-		if dataType == "" {
-			return nil, fmt.Errorf("need data type")
-		}
-		dataTypeUint, err := strconv.ParseUint(dataType, 0, 8)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse object type %v", err)
-		}
-
-		// Get Attribute
-		dType := uint8(dataTypeUint)
-		attribute := EncodeAttribute(accessType, pdoMapping == "1", dType)
-
-		variable.Name = parameterName
-		variable.DataType = dType
-		variable.Attribute = attribute
-		variable.SubIndex = 0
-
-		if strings.Index(defaultValue, "$NODEID") != -1 {
-			defaultValue = fastRemoveNodeID(defaultValue)
-		} else {
-			nodeId = 0
-		}
-		variable.valueDefault, err = EncodeFromString(defaultValue, variable.DataType, nodeId)
+		variable, err := newVariable(values, 0, nodeId, diag)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse 'DefaultValue' for x%x|x%x, because %v (datatype :x%x)", "", 0, err, variable.DataType)
+			return nil, err
 		}
-		variable.value = make([]byte, len(variable.valueDefault))
-		copy(variable.value, variable.valueDefault)
 		entry.object = variable
+		if compact > 0 {
+			return nil, entry.expandCompact(uint8(compact))
+		}
 		return nil, nil
 
 	case ObjectTypeARRAY:
 		// Array objects do not allow holes in subindex numbers
 		// So pre-init slice up to subnumber
-		sub, err := strconv.ParseUint(subNumber, 0, 8)
-		if err != nil {
+		sub, err := strconv.ParseUint(values.subNumber, 0, 8)
+		if err != nil && diag == nil {
 			return nil, fmt.Errorf("failed to parse subnumber %v", err)
 		}
+		diag.checkSubNumber(values.subNumber, sub, err)
 		vList := NewArray(uint8(sub))
 		entry.object = vList
 		return vList, nil
@@ -326,12 +408,14 @@ func populateEntry(
 	case ObjectTypeRECORD:
 		// Record objects allow holes in mapping
 		// Sub-objects will be added with "append"
+		sub, err := strconv.ParseUint(values.subNumber, 0, 8)
+		diag.checkSubNumber(values.subNumber, sub, err)
 		vList := NewRecord()
 		entry.object = vList
 		return vList, nil
 
 	default:
-		return nil, fmt.Errorf("unknown object type %v", oType)
+		return nil, keyErrorf("ObjectType", "unsupported ObjectType x%x", oType)
 	}
 }
 
@@ -339,58 +423,122 @@ func populateSubEntry(
 	entry *Entry,
 	vlist *VariableList,
 	nodeId uint8,
-	parameterName string,
-	defaultValue string,
-	pdoMapping string,
-	accessType string,
-	dataType string,
+	values *edsValues,
 	subIndex uint8,
+	diag *edsDiagnostics,
 ) error {
+	if vlist == nil {
+		return keyErrorf("", "sub-object of a %v", strings.TrimSpace(OBJ_NAME_MAP[entry.ObjectType]))
+	}
+	variable, err := newVariable(values, subIndex, nodeId, diag)
+	if err != nil {
+		return err
+	}
 
-	if dataType == "" {
-		return fmt.Errorf("need data type")
+	switch entry.ObjectType {
+	case ObjectTypeARRAY:
+		// Sub-objects beyond SubNumber, which may be wrong
+		for int(subIndex) >= len(vlist.Variables) {
+			vlist.Variables = append(vlist.Variables, nil)
+		}
+		vlist.Variables[subIndex] = variable
+		entry.subEntriesNameMap[values.parameterName] = subIndex
+	case ObjectTypeRECORD:
+		vlist.Variables = append(vlist.Variables, variable)
+		entry.subEntriesNameMap[values.parameterName] = subIndex
+	default:
+		return fmt.Errorf("add member not supported for ObjectType : %v", entry.ObjectType)
+	}
+
+	return nil
+}
+
+// Create a VAR or DOMAIN object or sub-object
+func newVariable(values *edsValues, subIndex uint8, nodeId uint8, diag *edsDiagnostics) (*Variable, error) {
+	if values.dataType == "" {
+		return nil, keyErrorf("", "missing DataType")
 	}
-	dataTypeUint, err := strconv.ParseUint(dataType, 0, 8)
+	dataTypeUint, err := strconv.ParseUint(values.dataType, 0, 8)
 	if err != nil {
-		return fmt.Errorf("failed to parse object type %v", err)
+		return nil, keyErrorf("DataType", "invalid DataType %q", values.dataType)
+	}
+	dType := uint8(dataTypeUint)
+	if diag != nil {
+		if _, err := EncodeFromString("", dType, 0); err != nil {
+			return nil, keyErrorf("DataType", "unsupported DataType x%x", dType)
+		}
 	}
 
 	// Get Attribute
-	dType := uint8(dataTypeUint)
+	accessType := diag.checkAccessType(values.accessType)
+	pdoMapping := diag.checkPDOMapping(values.pdoMapping)
 	attribute := EncodeAttribute(accessType, pdoMapping == "1", dType)
 
 	variable := &Variable{
-		Name:      parameterName,
-		DataType:  byte(dataTypeUint),
-		Attribute: attribute,
-		SubIndex:  subIndex,
-	}
-	if strings.Index(defaultValue, "$NODEID") != -1 {
-		defaultValue = fastRemoveNodeID(defaultValue)
-	} else {
-		nodeId = 0
+		Name:            values.parameterName,
+		DataType:        dType,
+		Attribute:       attribute,
+		StorageLocation: values.storageLocation,
+		SubIndex:        subIndex,
 	}
-	variable.valueDefault, err = EncodeFromString(defaultValue, variable.DataType, nodeId)
+	variable.valueDefault, err = encodeValue(values.defaultValue, dType, nodeId)
 	if err != nil {
-		return fmt.Errorf("failed to parse 'DefaultValue' %v %v %v", err, defaultValue, variable.DataType)
+		if diag == nil {
+			return nil, fmt.Errorf("failed to parse 'DefaultValue' %v %v %v", err, values.defaultValue, dType)
+		}
+		diag.reportKey("DefaultValue", "invalid DefaultValue %q for data type x%x", values.defaultValue, dType)
+		variable.valueDefault, _ = EncodeFromString("", dType, 0)
 	}
 	variable.value = make([]byte, len(variable.valueDefault))
 	copy(variable.value, variable.valueDefault)
+	err = populateParameterValue(variable, values.parameterValue, nodeId)
+	if err != nil {
+		if diag == nil {
+			return nil, err
+		}
+		diag.reportKey("ParameterValue", "invalid ParameterValue %q for data type x%x", values.parameterValue, dType)
+	}
+	variable.lowLimit = populateLimit("LowLimit", values.lowLimit, dType, diag)
+	variable.highLimit = populateLimit("HighLimit", values.highLimit, dType, diag)
+	return variable, nil
+}
 
-	switch entry.ObjectType {
-	case ObjectTypeARRAY:
-		vlist.Variables[subIndex] = variable
-		entry.subEntriesNameMap[parameterName] = subIndex
-	case ObjectTypeRECORD:
-		vlist.Variables = append(vlist.Variables, variable)
-		entry.subEntriesNameMap[parameterName] = subIndex
-	default:
-		return fmt.Errorf("add member not supported for ObjectType : %v", entry.ObjectType)
+// Encode a value that may be relative to the node id
+func encodeValue(value string, dataType uint8, nodeId uint8) ([]byte, error) {
+	if strings.Contains(value, "$NODEID") {
+		value = fastRemoveNodeID(value)
+	} else {
+		nodeId = 0
 	}
+	return EncodeFromString(value, dataType, nodeId)
+}
 
+// DCF files hold the actual value as parameter value, if any
+func populateParameterValue(variable *Variable, parameterValue string, nodeId uint8) error {
+	if parameterValue == "" {
+		return nil
+	}
+	value, err := encodeValue(parameterValue, variable.DataType, nodeId)
+	if err != nil {
+		return fmt.Errorf("failed to parse 'ParameterValue' %v %v %v", err, parameterValue, variable.DataType)
+	}
+	variable.value = value
 	return nil
 }
 
+// Encoded low or high limit, nil if none or invalid
+func populateLimit(key string, limit string, dataType uint8, diag *edsDiagnostics) []byte {
+	if limit == "" {
+		return nil
+	}
+	value, err := EncodeFromString(limit, dataType, 0)
+	if err != nil {
+		diag.reportKey(key, "invalid %v %q for data type x%x", key, limit, dataType)
+		return nil
+	}
+	return value
+}
+
 // Remove '\t' and ' ' characters at beginning
 // and beginning of line
 func trimSpaces(b []byte) []byte {
@@ -0,0 +1,143 @@
+package od
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// ShadowRecord guarantees data consistency of a RECORD or ARRAY entry
+// whose subindexes are written by multiple SDO writes, e.g. position + velocity targets.
+// Writes to the entry are stored in a shadow buffer and are only applied to the OD
+// once the commit subindex is written. Writing 0 to the commit subindex discards
+// any pending write instead.
+// The application should read the entry inside of [ShadowRecord.View] so that
+// partial updates are never observed.
+type ShadowRecord struct {
+	logger    *slog.Logger
+	mu        sync.Mutex
+	entry     *Entry
+	commitSub uint8
+	pending   map[uint8][]byte
+	onCommit  func(entry *Entry)
+}
+
+// Create a new [ShadowRecord] for the given entry and add it as an extension.
+// commitSubindex is the subindex used by the master to commit or discard the pending writes.
+func NewShadowRecord(entry *Entry, commitSubindex uint8, logger *slog.Logger) (*ShadowRecord, error) {
+	if entry == nil {
+		return nil, ErrIdxNotExist
+	}
+	if entry.ObjectType != ObjectTypeRECORD && entry.ObjectType != ObjectTypeARRAY {
+		return nil, ErrDevIncompat
+	}
+	if _, err := entry.SubIndex(commitSubindex); err != nil || commitSubindex == 0 {
+		return nil, ErrSubNotExist
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	shadow := &ShadowRecord{
+		logger:    logger.With("extension", "[SHADOW]", "index", entry.Index),
+		entry:     entry,
+		commitSub: commitSubindex,
+		pending:   make(map[uint8][]byte),
+	}
+	entry.AddExtension(shadow, ReadEntryDefault, WriteEntryShadow)
+	return shadow, nil
+}
+
+// Set a callback called after every commit, while values are still consistent.
+// The callback should not block nor call [ShadowRecord.View].
+func (shadow *ShadowRecord) OnCommit(callback func(entry *Entry)) {
+	shadow.mu.Lock()
+	defer shadow.mu.Unlock()
+	shadow.onCommit = callback
+}
+
+// View calls f while no commit can happen, reading the entry inside of f
+// always gives consistent values.
+func (shadow *ShadowRecord) View(f func(entry *Entry)) {
+	shadow.mu.Lock()
+	defer shadow.mu.Unlock()
+	f(shadow.entry)
+}
+
+// Returns the number of subindexes written but not yet committed
+func (shadow *ShadowRecord) Pending() int {
+	shadow.mu.Lock()
+	defer shadow.mu.Unlock()
+	return len(shadow.pending)
+}
+
+// Apply all pending writes to the OD
+func (shadow *ShadowRecord) Commit() {
+	shadow.mu.Lock()
+	defer shadow.mu.Unlock()
+	for subIndex, data := range shadow.pending {
+		variable, err := shadow.entry.SubIndex(subIndex)
+		if err != nil {
+			continue
+		}
+		variable.mu.Lock()
+		copy(variable.value, data)
+		variable.mu.Unlock()
+	}
+	shadow.logger.Debug("committed pending writes", "count", len(shadow.pending))
+	shadow.pending = make(map[uint8][]byte)
+	if shadow.onCommit != nil {
+		shadow.onCommit(shadow.entry)
+	}
+}
+
+// Drop all pending writes
+func (shadow *ShadowRecord) Discard() {
+	shadow.mu.Lock()
+	defer shadow.mu.Unlock()
+	shadow.logger.Debug("discarded pending writes", "count", len(shadow.pending))
+	shadow.pending = make(map[uint8][]byte)
+}
+
+// [SDO] Custom function for writing to a [ShadowRecord] entry
+func WriteEntryShadow(stream *Stream, data []byte, countWritten *uint16) error {
+	if stream == nil || data == nil || countWritten == nil || stream.Object == nil {
+		return ErrDevIncompat
+	}
+	shadow, ok := stream.Object.(*ShadowRecord)
+	if !ok {
+		return ErrDevIncompat
+	}
+	// Number of subindexes is not buffered
+	if stream.Subindex == 0 {
+		return WriteEntryDefault(stream, data, countWritten)
+	}
+	if stream.Subindex == shadow.commitSub {
+		err := WriteEntryDefault(stream, data, countWritten)
+		if err != nil {
+			return err
+		}
+		for _, b := range data {
+			if b != 0 {
+				shadow.Commit()
+				return nil
+			}
+		}
+		shadow.Discard()
+		return nil
+	}
+	shadow.mu.Lock()
+	defer shadow.mu.Unlock()
+	buffer, ok := shadow.pending[stream.Subindex]
+	if !ok {
+		buffer = make([]byte, len(stream.Data))
+		copy(buffer, stream.Data)
+	}
+	shadowStream := *stream
+	shadowStream.Data = buffer
+	err := WriteEntryDefault(&shadowStream, data, countWritten)
+	stream.DataOffset = shadowStream.DataOffset
+	if err != nil && err != ErrPartial {
+		return err
+	}
+	shadow.pending[stream.Subindex] = buffer
+	return err
+}
@@ -0,0 +1,58 @@
+package od
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShadowRecord(t *testing.T) {
+	odict := NewOD()
+	record := NewRecord()
+	record.AddSubObject(0, "count", UNSIGNED8, AttributeSdoR, "0x3")
+	record.AddSubObject(1, "position", UNSIGNED32, AttributeSdoRw, "0x0")
+	record.AddSubObject(2, "velocity", UNSIGNED32, AttributeSdoRw, "0x0")
+	record.AddSubObject(3, "commit", UNSIGNED8, AttributeSdoRw, "0x0")
+	entry := odict.AddVariableList(0x3100, "target", record)
+
+	_, err := NewShadowRecord(odict.Index(0x3016), 1, nil)
+	assert.Equal(t, ErrIdxNotExist, err)
+	_, err = NewShadowRecord(entry, 4, nil)
+	assert.Equal(t, ErrSubNotExist, err)
+
+	shadow, err := NewShadowRecord(entry, 3, nil)
+	assert.Nil(t, err)
+	commits := 0
+	shadow.OnCommit(func(entry *Entry) { commits++ })
+
+	t.Run("commit", func(t *testing.T) {
+		assert.Nil(t, entry.PutUint32(1, 100, false))
+		assert.Nil(t, entry.PutUint32(2, 200, false))
+		assert.Equal(t, 2, shadow.Pending())
+		shadow.View(func(entry *Entry) {
+			position, _ := entry.Uint32(1)
+			velocity, _ := entry.Uint32(2)
+			assert.EqualValues(t, 0, position)
+			assert.EqualValues(t, 0, velocity)
+		})
+		assert.Nil(t, entry.PutUint8(3, 1, false))
+		assert.Equal(t, 0, shadow.Pending())
+		assert.Equal(t, 1, commits)
+		shadow.View(func(entry *Entry) {
+			position, _ := entry.Uint32(1)
+			velocity, _ := entry.Uint32(2)
+			assert.EqualValues(t, 100, position)
+			assert.EqualValues(t, 200, velocity)
+		})
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		assert.Nil(t, entry.PutUint32(1, 300, false))
+		assert.Equal(t, 1, shadow.Pending())
+		assert.Nil(t, entry.PutUint8(3, 0, false))
+		assert.Equal(t, 0, shadow.Pending())
+		assert.Equal(t, 1, commits)
+		position, _ := entry.Uint32(1)
+		assert.EqualValues(t, 100, position)
+	})
+}
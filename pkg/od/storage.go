@@ -0,0 +1,324 @@
+package od
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Parameter groups of store parameters (0x1010) & restore default
+// parameters (0x1011), i.e. their sub-indexes, see CiA 301
+const (
+	ParametersAll           uint8 = 1
+	ParametersCommunication uint8 = 2 // 0x1000 - 0x1FFF
+	ParametersApplication   uint8 = 3 // 0x6000 - 0x9FFF
+	ParametersManufacturer  uint8 = 4 // 0x2000 - 0x5FFF
+)
+
+// Signatures written to 0x1010 & 0x1011, see CiA 301
+const (
+	SignatureSave uint32 = 0x65766173 // "save"
+	SignatureLoad uint32 = 0x64616F6C // "load"
+)
+
+// Storage locations of a variable (StorageLocation in EDS) selecting its
+// parameter group, whatever its index. Variables located in [StorageRAM]
+// are never stored. Other or no location uses the group of the index.
+const (
+	StorageRAM                  = "RAM"
+	StoragePersistCommunication = "PERSIST_COMM"
+	StoragePersistApplication   = "PERSIST_APP"
+	StoragePersistManufacturer  = "PERSIST_MFR"
+)
+
+// A ParameterStorage persists parameter values across restarts.
+// Load returns no values if nothing has been stored yet.
+type ParameterStorage interface {
+	Load() ([]ConciseEntry, error)
+	Save(values []ConciseEntry) error
+}
+
+// A ParameterStore loads, saves & restores the parameter groups of an OD,
+// as used by store parameters (0x1010) & restore default parameters (0x1011).
+// [NewParameterStore] returns one based on a [ParameterStorage], custom
+// implementations can e.g. store each group in a different memory.
+type ParameterStore interface {
+	// Load stored values of group into OD
+	Load(group uint8) error
+	// Save current OD values of group
+	Save(group uint8) error
+	// Remove stored values of group, so that defaults are used on next load
+	Restore(group uint8) error
+}
+
+// DefaultParameterStore is a [ParameterStore] keeping all groups
+// of an OD in a single [ParameterStorage]
+type DefaultParameterStore struct {
+	mu      sync.Mutex
+	od      *ObjectDictionary
+	storage ParameterStorage
+}
+
+// Create a [ParameterStore] for odict, persisting values in storage
+func NewParameterStore(odict *ObjectDictionary, storage ParameterStorage) *DefaultParameterStore {
+	return &DefaultParameterStore{od: odict, storage: storage}
+}
+
+func (store *DefaultParameterStore) Load(group uint8) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.od.loadParameters(store.storage, group)
+}
+
+func (store *DefaultParameterStore) Save(group uint8) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.od.SaveParameters(store.storage, group)
+}
+
+func (store *DefaultParameterStore) Restore(group uint8) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.od.RestoreParameters(store.storage, group)
+}
+
+// Returns the parameter group of a variable (communication, application
+// or manufacturer), 0 if it is not stored. variable can be nil if unknown.
+// Store & restore parameters themselves are never part of a group.
+func parameterGroup(index uint16, variable *Variable) uint8 {
+	if index == EntryStoreParameters || index == EntryRestoreDefaultParameters {
+		return 0
+	}
+	if variable != nil {
+		switch strings.ToUpper(variable.StorageLocation) {
+		case StorageRAM:
+			return 0
+		case StoragePersistCommunication:
+			return ParametersCommunication
+		case StoragePersistApplication:
+			return ParametersApplication
+		case StoragePersistManufacturer:
+			return ParametersManufacturer
+		}
+	}
+	switch {
+	case index >= 0x1000 && index <= 0x1FFF:
+		return ParametersCommunication
+	case index >= 0x2000 && index <= 0x5FFF:
+		return ParametersManufacturer
+	case index >= 0x6000 && index <= 0x9FFF:
+		return ParametersApplication
+	default:
+		return 0
+	}
+}
+
+// Returns true if a variable of the given parameter group (see [parameterGroup])
+// belongs to group, which can also be [ParametersAll]
+func inParameterGroup(variableGroup uint8, group uint8) bool {
+	return variableGroup != 0 && (group == ParametersAll || group == variableGroup)
+}
+
+// Group of a stored value, based on the corresponding variable in OD if any
+func (od *ObjectDictionary) storedGroup(value ConciseEntry) uint8 {
+	variable, err := od.Index(value.Index).SubIndex(int(value.Subindex))
+	if err != nil {
+		variable = nil
+	}
+	return parameterGroup(value.Index, variable)
+}
+
+// Parameters returns the current values of the writable entries of a
+// parameter group, ordered by index and subindex. DOMAIN entries
+// and entries located in RAM (see [StorageRAM]) are not included.
+func (od *ObjectDictionary) Parameters(group uint8) []ConciseEntry {
+	return od.writableValues(func(index uint16, variable *Variable) bool {
+		return inParameterGroup(parameterGroup(index, variable), group)
+	})
+}
+
+// LoadParameters writes the values found in storage to the OD.
+// This should be done before creating the corresponding node, so that
+// CANopen objects are initialized with the stored values.
+// Values of entries that do not exist in OD are ignored.
+func (od *ObjectDictionary) LoadParameters(storage ParameterStorage) error {
+	return od.loadParameters(storage, ParametersAll)
+}
+
+// Write the stored values of a parameter group to the OD
+func (od *ObjectDictionary) loadParameters(storage ParameterStorage, group uint8) error {
+	if group < ParametersAll || group > ParametersManufacturer {
+		return ErrSubNotExist
+	}
+	values, err := storage.Load()
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, value := range values {
+		entry := od.Index(value.Index)
+		if entry == nil {
+			od.logger.Warn("ignoring stored parameter, entry does not exist",
+				"index", fmt.Sprintf("x%x", value.Index),
+			)
+			continue
+		}
+		if !inParameterGroup(od.storedGroup(value), group) {
+			continue
+		}
+		err = entry.WriteExactly(value.Subindex, value.Data, true)
+		if err != nil {
+			od.logger.Warn("ignoring stored parameter",
+				"index", fmt.Sprintf("x%x", value.Index),
+				"subindex", value.Subindex,
+				"error", err,
+			)
+			continue
+		}
+		count++
+	}
+	od.logger.Info("loaded parameters", "group", group, "count", count)
+	return nil
+}
+
+// SaveParameters stores the current values of a parameter group.
+// Values of the other groups already stored are kept.
+func (od *ObjectDictionary) SaveParameters(storage ParameterStorage, group uint8) error {
+	if group < ParametersAll || group > ParametersManufacturer {
+		return ErrSubNotExist
+	}
+	values, err := od.keepOtherGroups(storage, group)
+	if err != nil {
+		return err
+	}
+	values = append(values, od.Parameters(group)...)
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Index != values[j].Index {
+			return values[i].Index < values[j].Index
+		}
+		return values[i].Subindex < values[j].Subindex
+	})
+	return storage.Save(values)
+}
+
+// RestoreParameters removes the stored values of a parameter group,
+// default values are then used on next [ObjectDictionary.LoadParameters].
+// Current OD values are not modified.
+func (od *ObjectDictionary) RestoreParameters(storage ParameterStorage, group uint8) error {
+	if group < ParametersAll || group > ParametersManufacturer {
+		return ErrSubNotExist
+	}
+	values, err := od.keepOtherGroups(storage, group)
+	if err != nil {
+		return err
+	}
+	return storage.Save(values)
+}
+
+// Load stored values that belong to another group than group.
+// Values that are no longer stored, e.g. now located in RAM, are dropped.
+func (od *ObjectDictionary) keepOtherGroups(storage ParameterStorage, group uint8) ([]ConciseEntry, error) {
+	stored, err := storage.Load()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]ConciseEntry, 0, len(stored))
+	for _, value := range stored {
+		storedGroup := od.storedGroup(value)
+		if storedGroup != 0 && !inParameterGroup(storedGroup, group) {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// FileStorage is a [ParameterStorage] backed by a file, either in
+// JSON or in binary format (concise DCF, see [EncodeConciseDCF]).
+// The file is replaced atomically on save.
+type FileStorage struct {
+	path   string
+	binary bool
+}
+
+type jsonParameter struct {
+	Index    string `json:"index"`
+	Subindex uint8  `json:"subindex"`
+	Data     string `json:"data"`
+}
+
+// Create a [FileStorage] in JSON format
+func NewJSONFileStorage(path string) *FileStorage {
+	return &FileStorage{path: path}
+}
+
+// Create a [FileStorage] in binary format
+func NewBinaryFileStorage(path string) *FileStorage {
+	return &FileStorage{path: path, binary: true}
+}
+
+// Load stored values, no values are returned if file does not exist
+func (storage *FileStorage) Load() ([]ConciseEntry, error) {
+	raw, err := os.ReadFile(storage.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if storage.binary {
+		return DecodeConciseDCF(raw)
+	}
+	var parameters []jsonParameter
+	err = json.Unmarshal(raw, &parameters)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]ConciseEntry, 0, len(parameters))
+	for _, parameter := range parameters {
+		index, err := strconv.ParseUint(parameter.Index, 0, 16)
+		if err != nil {
+			return nil, err
+		}
+		data, err := hex.DecodeString(parameter.Data)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, ConciseEntry{Index: uint16(index), Subindex: parameter.Subindex, Data: data})
+	}
+	return values, nil
+}
+
+// Save values, replacing the previous ones
+func (storage *FileStorage) Save(values []ConciseEntry) error {
+	var raw []byte
+	if storage.binary {
+		raw = EncodeConciseDCF(values)
+	} else {
+		parameters := make([]jsonParameter, 0, len(values))
+		for _, value := range values {
+			parameters = append(parameters, jsonParameter{
+				Index:    fmt.Sprintf("0x%04X", value.Index),
+				Subindex: value.Subindex,
+				Data:     hex.EncodeToString(value.Data),
+			})
+		}
+		var err error
+		raw, err = json.MarshalIndent(parameters, "", "  ")
+		if err != nil {
+			return err
+		}
+	}
+	tmp := storage.path + ".tmp"
+	err := os.WriteFile(tmp, raw, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, storage.path)
+}
@@ -0,0 +1,124 @@
+package od
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterStorage(t *testing.T) {
+	for _, storage := range []*FileStorage{
+		NewJSONFileStorage(filepath.Join(t.TempDir(), "params.json")),
+		NewBinaryFileStorage(filepath.Join(t.TempDir(), "params.bin")),
+	} {
+		odict := Default()
+		values, err := storage.Load()
+		assert.Nil(t, err)
+		assert.Empty(t, values)
+
+		assert.Nil(t, odict.Index(EntryProducerHeartbeatTime).PutUint16(0, 1234, true))
+		assert.Nil(t, odict.Index(0x2007).PutUint32(0, 0x55, true))
+		assert.Nil(t, odict.SaveParameters(storage, ParametersCommunication))
+
+		// Only communication parameters are stored
+		odict2 := Default()
+		assert.Nil(t, odict2.LoadParameters(storage))
+		value, _ := odict2.Index(EntryProducerHeartbeatTime).Uint16(0)
+		assert.EqualValues(t, 1234, value)
+		value32, _ := odict2.Index(0x2007).Uint32(0)
+		assert.NotEqualValues(t, 0x55, value32)
+
+		// Saving another group keeps the previous ones
+		assert.Nil(t, odict.SaveParameters(storage, ParametersManufacturer))
+		odict2 = Default()
+		assert.Nil(t, odict2.LoadParameters(storage))
+		value, _ = odict2.Index(EntryProducerHeartbeatTime).Uint16(0)
+		assert.EqualValues(t, 1234, value)
+		value32, _ = odict2.Index(0x2007).Uint32(0)
+		assert.EqualValues(t, 0x55, value32)
+
+		// Restore communication defaults
+		assert.Nil(t, odict.RestoreParameters(storage, ParametersCommunication))
+		odict2 = Default()
+		assert.Nil(t, odict2.LoadParameters(storage))
+		value, _ = odict2.Index(EntryProducerHeartbeatTime).Uint16(0)
+		assert.NotEqualValues(t, 1234, value)
+		value32, _ = odict2.Index(0x2007).Uint32(0)
+		assert.EqualValues(t, 0x55, value32)
+
+		assert.Equal(t, ErrSubNotExist, odict.SaveParameters(storage, 5))
+		for _, value := range odict.Parameters(ParametersAll) {
+			assert.NotEqual(t, EntryStoreParameters, value.Index)
+			assert.NotEqual(t, EntryRestoreDefaultParameters, value.Index)
+		}
+	}
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "params.json")
+		assert.Nil(t, os.WriteFile(path, []byte("{"), 0644))
+		assert.NotNil(t, Default().LoadParameters(NewJSONFileStorage(path)))
+	})
+}
+
+func TestParameterStoreLocation(t *testing.T) {
+	odict := Default()
+	heartbeat, err := odict.Index(EntryProducerHeartbeatTime).SubIndex(0)
+	assert.Nil(t, err)
+	heartbeat.StorageLocation = StorageRAM
+	manufacturer, err := odict.Index(0x2007).SubIndex(0)
+	assert.Nil(t, err)
+	manufacturer.StorageLocation = StoragePersistCommunication
+
+	// Storage location is kept in EDS
+	buf := &bytes.Buffer{}
+	assert.Nil(t, odict.ExportEDS(buf))
+	for _, parse := range []func(file any, nodeId uint8) (*ObjectDictionary, error){Parse, ParseV2} {
+		parsed, err := parse(buf.Bytes(), 0x10)
+		assert.Nil(t, err)
+		variable, err := parsed.Index(0x2007).SubIndex(0)
+		assert.Nil(t, err)
+		assert.Equal(t, StoragePersistCommunication, variable.StorageLocation)
+	}
+
+	// RAM entries are not stored, location overrides index group
+	assert.Nil(t, odict.Index(EntryProducerHeartbeatTime).PutUint16(0, 1234, true))
+	assert.Nil(t, odict.Index(0x2007).PutUint32(0, 0x55, true))
+	var communication []uint16
+	for _, value := range odict.Parameters(ParametersCommunication) {
+		communication = append(communication, value.Index)
+	}
+	assert.NotContains(t, communication, EntryProducerHeartbeatTime)
+	assert.Contains(t, communication, uint16(0x2007))
+	for _, value := range odict.Parameters(ParametersManufacturer) {
+		assert.NotEqual(t, uint16(0x2007), value.Index)
+	}
+
+	// Groups are loaded independently
+	storage := NewJSONFileStorage(filepath.Join(t.TempDir(), "params.json"))
+	store := NewParameterStore(odict, storage)
+	assert.Nil(t, store.Save(ParametersAll))
+	// Same EDS, i.e. same storage locations
+	odict2, err := ParseV2(buf.Bytes(), 0x10)
+	assert.Nil(t, err)
+	store2 := NewParameterStore(odict2, storage)
+	assert.Nil(t, store2.Load(ParametersApplication))
+	value32, _ := odict2.Index(0x2007).Uint32(0)
+	assert.NotEqualValues(t, 0x55, value32)
+	assert.Nil(t, store2.Load(ParametersCommunication))
+	value32, _ = odict2.Index(0x2007).Uint32(0)
+	assert.EqualValues(t, 0x55, value32)
+	value, _ := odict2.Index(EntryProducerHeartbeatTime).Uint16(0)
+	assert.NotEqualValues(t, 1234, value)
+	assert.Equal(t, ErrSubNotExist, store2.Load(0))
+
+	// Restore only removes the given group
+	assert.Nil(t, store.Restore(ParametersCommunication))
+	values, err := storage.Load()
+	assert.Nil(t, err)
+	for _, value := range values {
+		assert.NotEqual(t, uint16(0x2007), value.Index)
+	}
+}
@@ -28,6 +28,8 @@ type Stream struct {
 	Attribute uint8
 	// The subindex of this OD entry. For a VAR type this is always 0.
 	Subindex uint8
+	// Next extension of the chain, used by [ReadNext] & [WriteNext]
+	next *extension
 }
 
 // A StreamReader is a function that reads from a [Stream] object and
@@ -41,10 +43,10 @@ type StreamWriter func(stream *Stream, to_write []byte, countWritten *uint16) er
 // extension object, is used for extending functionnality of an OD entry
 // This package has some pre-made extensions for CiA defined entries
 type extension struct {
-	object   any          // Any object to link with extension
-	read     StreamReader // A [StreamReader] that will be called when reading entry
-	write    StreamWriter // A [StreamWriter] that will be called when writing to entry
-	flagsPDO [FlagsPdoSize]uint8
+	object any          // Any object to link with extension
+	read   StreamReader // A [StreamReader] that will be called when reading entry
+	write  StreamWriter // A [StreamWriter] that will be called when writing to entry
+	next   *extension   // Next extension of the chain, nil if last
 }
 
 // Streamer is created before accessing an OD entry
@@ -54,20 +56,23 @@ type Streamer struct {
 	Stream
 	reader StreamReader
 	writer StreamWriter
+	// Read / written count, kept inside of streamer so that
+	// it does not escape to heap on every call
+	count uint16
 }
 
 // Implements io.Reader
 func (s *Streamer) Read(b []byte) (n int, err error) {
-	countRead := uint16(0)
-	err = s.reader(&s.Stream, b, &countRead)
-	return int(countRead), err
+	s.count = 0
+	err = s.reader(&s.Stream, b, &s.count)
+	return int(s.count), err
 }
 
 // Implements io.Writer
 func (s *Streamer) Write(b []byte) (n int, err error) {
-	countWritten := uint16(0)
-	err = s.writer(&s.Stream, b, &countWritten)
-	return int(countWritten), err
+	s.count = 0
+	err = s.writer(&s.Stream, b, &s.count)
+	return int(s.count), err
 }
 
 // Return streamer writer
@@ -170,11 +175,46 @@ func NewStreamer(entry *Entry, subIndex uint8, origin bool) (Streamer, error) {
 		streamer.writer = entry.extension.write
 	}
 	streamer.Object = entry.extension.object
+	streamer.next = entry.extension.next
 	streamer.DataOffset = 0
 	streamer.Subindex = subIndex
 	return streamer, nil
 }
 
+// ReadNext passes a read through to the next extension of the chain,
+// or to [ReadEntryDefault] if this is the last one.
+// It is meant to be called from inside of a [StreamReader].
+func ReadNext(stream *Stream, data []byte, countRead *uint16) error {
+	link := stream.next
+	if link == nil {
+		return ReadEntryDefault(stream, data, countRead)
+	}
+	object := stream.Object
+	stream.Object, stream.next = link.object, link.next
+	defer func() { stream.Object, stream.next = object, link }()
+	if link.read == nil {
+		return ReadEntryDisabled(stream, data, countRead)
+	}
+	return link.read(stream, data, countRead)
+}
+
+// WriteNext passes a write through to the next extension of the chain,
+// or to [WriteEntryDefault] if this is the last one.
+// It is meant to be called from inside of a [StreamWriter].
+func WriteNext(stream *Stream, data []byte, countWritten *uint16) error {
+	link := stream.next
+	if link == nil {
+		return WriteEntryDefault(stream, data, countWritten)
+	}
+	object := stream.Object
+	stream.Object, stream.next = link.object, link.next
+	defer func() { stream.Object, stream.next = object, link }()
+	if link.write == nil {
+		return WriteEntryDisabled(stream, data, countWritten)
+	}
+	return link.write(stream, data, countWritten)
+}
+
 // This is the default "StreamReader" type for every OD entry
 // It Reads a value from the original OD location i.e. [Stream] object
 // And writes it inside data. It also updates the actual read count, countRead
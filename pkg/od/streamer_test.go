@@ -39,3 +39,56 @@ func TestStreamerCopy(t *testing.T) {
 	assert.Nil(t, err)
 	assert.EqualValues(t, 1, n)
 }
+
+func TestExtensionChain(t *testing.T) {
+	od := Default()
+	entry := od.Index("UNSIGNED8 value")
+	assert.NotNil(t, entry)
+
+	// Counts accesses then passes them through
+	counter := map[string]int{}
+	countRead := func(stream *Stream, data []byte, countRead *uint16) error {
+		counter[stream.Object.(string)+"r"]++
+		return ReadNext(stream, data, countRead)
+	}
+	countWrite := func(stream *Stream, data []byte, countWritten *uint16) error {
+		counter[stream.Object.(string)+"w"]++
+		return WriteNext(stream, data, countWritten)
+	}
+	// Refuses values above 100
+	validate := func(stream *Stream, data []byte, countWritten *uint16) error {
+		if data[0] > 100 {
+			return ErrValueHigh
+		}
+		return WriteNext(stream, data, countWritten)
+	}
+
+	entry.AddExtension("inner", countRead, countWrite)
+	entry.PushExtension(nil, ReadNext, validate)
+	outer := entry.PushExtension("outer", countRead, countWrite)
+	assert.Equal(t, 3, entry.ExtensionCount())
+
+	assert.Nil(t, entry.PutUint8(0, 50, false))
+	val, err := entry.Uint8(0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 50, val)
+	assert.Equal(t, ErrValueHigh, entry.PutUint8(0, 150, false))
+	b := []byte{0}
+	assert.Nil(t, entry.ReadExactly(0, b, false))
+	assert.EqualValues(t, 50, b[0])
+	// Rejected write stops at validation
+	assert.Equal(t, map[string]int{"outerw": 2, "innerw": 1, "outerr": 1, "innerr": 1}, counter)
+
+	// Remove a single link
+	assert.True(t, entry.RemoveExtension(outer))
+	assert.False(t, entry.RemoveExtension(outer))
+	assert.Equal(t, 2, entry.ExtensionCount())
+	assert.Nil(t, entry.PutUint8(0, 60, false))
+	assert.Equal(t, 2, counter["outerw"])
+	assert.Equal(t, 2, counter["innerw"])
+
+	// Adding an extension replaces the chain
+	entry.AddExtension(nil, ReadEntryDefault, WriteEntryDisabled)
+	assert.Equal(t, 1, entry.ExtensionCount())
+	assert.Equal(t, ErrUnsuppAccess, entry.PutUint8(0, 10, false))
+}
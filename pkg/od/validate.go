@@ -0,0 +1,141 @@
+package od
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrMandatoryMissing = errors.New("mandatory object is missing")
+
+// Objects that every CiA 301 device should have
+var mandatoryEntries = []uint16{EntryDeviceType, EntryErrorRegister, EntryIdentityObject}
+
+// Validate checks that the OD is usable by a CiA 301 device :
+//   - mandatory objects are present : device type (0x1000), error register (0x1001),
+//     identity (0x1018) with vendor id, and heartbeat producer (0x1017) or node guarding (0x100C & 0x100D)
+//   - every PDO mapping has the corresponding communication parameter
+//   - mapped entries exist, are mappable into the PDO, are long enough and fit into the PDO
+//
+// All the problems found are returned joined, each wrapping [ErrMandatoryMissing],
+// [ErrIdxNotExist], [ErrSubNotExist], [ErrNoMap] or [ErrMapLen].
+func (od *ObjectDictionary) Validate() error {
+	errs := make([]error, 0)
+	for _, index := range mandatoryEntries {
+		if od.Index(index) == nil {
+			errs = append(errs, fmt.Errorf("x%x : %w", index, ErrMandatoryMissing))
+		}
+	}
+	if identity := od.Index(EntryIdentityObject); identity != nil {
+		if _, err := identity.SubIndex(uint8(1)); err != nil {
+			errs = append(errs, fmt.Errorf("x%x|x1 vendor id : %w", EntryIdentityObject, ErrMandatoryMissing))
+		}
+	}
+	guarding := od.Index(EntryGuardTime) != nil && od.Index(EntryLifeTimeFactor) != nil
+	if od.Index(EntryProducerHeartbeatTime) == nil && !guarding {
+		errs = append(errs, fmt.Errorf("x%x heartbeat or node guarding : %w", EntryProducerHeartbeatTime, ErrMandatoryMissing))
+	}
+
+	indexes := make([]int, 0)
+	for index := range od.entriesByIndexValue {
+		indexes = append(indexes, int(index))
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		index := uint16(i)
+		var attribute uint8
+		switch {
+		case IsRPDOMappingParam(index):
+			attribute = AttributeRpdo
+		case IsTPDOMappingParam(index):
+			attribute = AttributeTpdo
+		default:
+			continue
+		}
+		if od.Index(index-(EntryRPDOMappingStart-EntryRPDOCommunicationStart)) == nil {
+			errs = append(errs, fmt.Errorf("x%x communication parameter : %w", index, ErrIdxNotExist))
+		}
+		errs = append(errs, od.validateMapping(od.entriesByIndexValue[index], attribute)...)
+	}
+	return errors.Join(errs...)
+}
+
+// Check the mapped entries of a PDO mapping entry
+func (od *ObjectDictionary) validateMapping(entry *Entry, attribute uint8) []error {
+	errs := make([]error, 0)
+	nbMapped, err := entry.Uint8(0)
+	if err != nil {
+		return append(errs, fmt.Errorf("x%x|x0 : %w", entry.Index, err))
+	}
+	if nbMapped > MaxMappedEntriesPdo {
+		return append(errs, fmt.Errorf("x%x %v mapped objects : %w", entry.Index, nbMapped, ErrMapLen))
+	}
+	totalBits := 0
+	for subindex := uint8(1); subindex <= nbMapped; subindex++ {
+		variable, err := entry.SubIndex(subindex)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("x%x|x%x : %w", entry.Index, subindex, err))
+			continue
+		}
+		if len(variable.value) != 4 {
+			errs = append(errs, fmt.Errorf("x%x|x%x : %w", entry.Index, subindex, ErrTypeMismatch))
+			continue
+		}
+		mapping := binary.LittleEndian.Uint32(variable.value)
+		mappedIndex, mappedSubindex, lengthBits := uint16(mapping>>16), uint8(mapping>>8), uint8(mapping)
+		totalBits += int(lengthBits)
+		if lengthBits == 0 {
+			errs = append(errs, fmt.Errorf("x%x|x%x length of %v bits : %w", entry.Index, subindex, lengthBits, ErrNoMap))
+			continue
+		}
+		// Dummy entries
+		if mappedIndex < 0x20 && mappedSubindex == 0 {
+			if err := CheckMappingLength(uint8(mappedIndex), 8, lengthBits); err != nil {
+				errs = append(errs, fmt.Errorf("x%x|x%x maps dummy x%x, %w", entry.Index, subindex, mappedIndex, err))
+			}
+			continue
+		}
+		mapped, err := od.Index(mappedIndex).SubIndex(mappedSubindex)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("x%x|x%x maps x%x|x%x : %w", entry.Index, subindex, mappedIndex, mappedSubindex, err))
+			continue
+		}
+		if mapped.Attribute&attribute == 0 {
+			errs = append(errs, fmt.Errorf("x%x|x%x maps x%x|x%x, not mappable : %w", entry.Index, subindex, mappedIndex, mappedSubindex, ErrNoMap))
+		} else if err := CheckMappingLength(mapped.DataType, mapped.DataLength(), lengthBits); err != nil {
+			errs = append(errs, fmt.Errorf("x%x|x%x maps x%x|x%x, %w", entry.Index, subindex, mappedIndex, mappedSubindex, err))
+		}
+	}
+	if totalBits > 64 {
+		errs = append(errs, fmt.Errorf("x%x total length of %v bits : %w", entry.Index, totalBits, ErrMapLen))
+	}
+	return errs
+}
+
+// CheckMappingLength checks that an object of the given data type & length in bytes
+// can be mapped into a PDO with lengthBits bits. Objects are mapped starting from
+// their least significant bit. Lengths that are not a multiple of 8 bits are only
+// allowed for BOOLEAN & integer types, e.g. a BOOLEAN mapped with 1 bit, or a dummy
+// entry 0x0001 to 0x0007 used for padding. Returns [ErrNoMap] or [ErrMapLen] otherwise.
+func CheckMappingLength(dataType uint8, dataLength uint32, lengthBits uint8) error {
+	switch {
+	case lengthBits > 64:
+		return fmt.Errorf("length of %v bits exceeds PDO length : %w", lengthBits, ErrMapLen)
+	case lengthBits%8 != 0 && !isBitMappable(dataType):
+		return fmt.Errorf("length of %v bits not a multiple of 8 for data type x%x : %w", lengthBits, dataType, ErrNoMap)
+	case dataLength*8 < uint32(lengthBits):
+		return fmt.Errorf("shorter than %v bits : %w", lengthBits, ErrNoMap)
+	}
+	return nil
+}
+
+// Data types that can be mapped bit by bit
+func isBitMappable(dataType uint8) bool {
+	switch dataType {
+	case BOOLEAN, INTEGER8, INTEGER16, INTEGER32, INTEGER64, UNSIGNED8, UNSIGNED16, UNSIGNED32, UNSIGNED64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,66 @@
+package od
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Default().Validate())
+
+	t.Run("mandatory objects", func(t *testing.T) {
+		odict := Default()
+		delete(odict.entriesByIndexValue, EntryDeviceType)
+		delete(odict.entriesByIndexValue, EntryProducerHeartbeatTime)
+		err := odict.Validate()
+		assert.ErrorIs(t, err, ErrMandatoryMissing)
+		assert.ErrorContains(t, err, "x1000")
+		assert.ErrorContains(t, err, "x1017")
+		// Node guarding instead of heartbeat
+		odict.AddVariableType(EntryDeviceType, "Device type", UNSIGNED32, AttributeSdoR, "0x0")
+		odict.AddVariableType(EntryGuardTime, "Guard time", UNSIGNED16, AttributeSdoRw, "0x0")
+		odict.AddVariableType(EntryLifeTimeFactor, "Life time factor", UNSIGNED8, AttributeSdoRw, "0x0")
+		assert.Nil(t, odict.Validate())
+	})
+
+	t.Run("pdo mappings", func(t *testing.T) {
+		odict := Default()
+		tpdo := odict.Index(EntryTPDOMappingStart)
+		assert.Nil(t, tpdo.PutUint8(0, 3, true))
+		assert.Nil(t, tpdo.PutUint32(1, 0x20010008, true)) // Ok
+		assert.Nil(t, tpdo.PutUint32(2, 0x10170010, true)) // Not mappable
+		assert.Nil(t, tpdo.PutUint32(3, 0x4FFF0008, true)) // Missing
+		err := odict.Validate()
+		assert.ErrorIs(t, err, ErrNoMap)
+		assert.ErrorIs(t, err, ErrIdxNotExist)
+		assert.NotErrorIs(t, err, ErrMapLen)
+
+		assert.Nil(t, tpdo.PutUint8(0, 2, true))
+		assert.Nil(t, tpdo.PutUint32(2, 0x20010040, true)) // Too long
+		assert.ErrorIs(t, odict.Validate(), ErrNoMap)
+
+		assert.Nil(t, tpdo.PutUint8(0, 3, true))
+		assert.Nil(t, tpdo.PutUint32(1, 0x20070020, true))
+		assert.Nil(t, tpdo.PutUint32(2, 0x20080020, true))
+		assert.Nil(t, tpdo.PutUint32(3, 0x20070020, true))
+		err = odict.Validate()
+		assert.ErrorIs(t, err, ErrMapLen)
+		assert.NotErrorIs(t, err, ErrNoMap)
+
+		assert.Nil(t, tpdo.PutUint8(0, 4, true))
+		assert.Nil(t, tpdo.PutUint32(1, 0x20010001, true)) // BOOLEAN
+		assert.Nil(t, tpdo.PutUint32(2, 0x00010007, true)) // Padding
+		assert.Nil(t, tpdo.PutUint32(3, 0x2006000C, true)) // 12 bits of UNSIGNED16
+		assert.Nil(t, tpdo.PutUint32(4, 0x201B0020, true))
+		assert.Nil(t, odict.Validate())
+		assert.Nil(t, tpdo.PutUint32(4, 0x2008000C, true)) // REAL32, not aligned
+		assert.ErrorIs(t, odict.Validate(), ErrNoMap)
+		assert.Nil(t, tpdo.PutUint32(4, 0x00080004, true)) // REAL32 dummy, not aligned
+		assert.ErrorIs(t, odict.Validate(), ErrNoMap)
+
+		delete(odict.entriesByIndexValue, EntryTPDOCommunicationStart)
+		assert.Nil(t, tpdo.PutUint8(0, 0, true))
+		assert.ErrorIs(t, odict.Validate(), ErrIdxNotExist)
+	})
+}
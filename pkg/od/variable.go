@@ -5,6 +5,7 @@ import (
 	"math"
 	"strconv"
 	"sync"
+	"time"
 )
 
 // Variable is the main data representation for a value stored inside of OD
@@ -22,7 +23,8 @@ type Variable struct {
 	// information. e.g. AttributeSdoRw | AttributeRpdo
 	Attribute uint8
 	// StorageLocation has information on which medium is the data
-	// stored. Currently this is unused, everything is stored in RAM
+	// stored, e.g. [StorageRAM]. This selects whether & in which group
+	// the variable is stored, see [ParameterStore]
 	StorageLocation string
 	// The minimum value for this variable
 	lowLimit []byte
@@ -135,6 +137,16 @@ func EncodeFromString(value string, datatype uint8, offset uint8) ([]byte, error
 	case VISIBLE_STRING, OCTET_STRING:
 		return []byte(value), nil
 
+	case UNICODE_STRING:
+		return EncodeUnicodeString(value), nil
+
+	case TIME_OF_DAY, TIME_DIFFERENCE:
+		// Raw value, i.e. milliseconds + days << 32
+		parsedUint, err = strconv.ParseUint(value, 0, 48)
+		data = make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, parsedUint)
+		data = data[:timeLength]
+
 	case DOMAIN:
 		return []byte{}, nil
 
@@ -181,6 +193,10 @@ func EncodeFromGeneric(data any) ([]byte, error) {
 		binary.LittleEndian.PutUint64(encoded, math.Float64bits(val))
 	case []byte:
 		encoded = val
+	case time.Time:
+		return EncodeTimeOfDay(val)
+	case time.Duration:
+		return EncodeTimeDifference(val)
 	default:
 		return nil, ErrTypeMismatch
 	}
@@ -215,6 +231,17 @@ func CheckSize(length int, dataType uint8) error {
 		} else if length > 8 {
 			return ErrDataLong
 		}
+	case TIME_OF_DAY, TIME_DIFFERENCE:
+		if length < timeLength {
+			return ErrDataShort
+		} else if length > timeLength {
+			return ErrDataLong
+		}
+	// UTF-16 code units
+	case UNICODE_STRING:
+		if length%2 != 0 {
+			return ErrDataShort
+		}
 	// All other datatypes, no size check
 	default:
 		return nil
@@ -224,7 +251,8 @@ func CheckSize(length int, dataType uint8) error {
 }
 
 // Decode byte array given the CANopen data type
-// Function will return either string, int64, uint64, or float64
+// Function will return either string, []byte (OCTET_STRING), int64, uint64, float64,
+// time.Time (TIME_OF_DAY) or time.Duration (TIME_DIFFERENCE)
 func DecodeToType(data []byte, dataType uint8) (v any, e error) {
 	e = CheckSize(len(data), dataType)
 	if e != nil {
@@ -235,7 +263,7 @@ func DecodeToType(data []byte, dataType uint8) (v any, e error) {
 	case BOOLEAN, UNSIGNED8:
 		return uint64(data[0]), nil
 	case INTEGER8:
-		return int64(data[0]), nil
+		return int64(int8(data[0])), nil
 	case UNSIGNED16:
 		return uint64(binary.LittleEndian.Uint16(data)), nil
 	case INTEGER16:
@@ -254,8 +282,16 @@ func DecodeToType(data []byte, dataType uint8) (v any, e error) {
 	case REAL64:
 		parsed := binary.LittleEndian.Uint64(data)
 		return math.Float64frombits(parsed), nil
-	case VISIBLE_STRING, OCTET_STRING:
+	case VISIBLE_STRING:
 		return string(data), nil
+	case OCTET_STRING:
+		return append([]byte{}, data...), nil
+	case UNICODE_STRING:
+		return DecodeUnicodeString(data)
+	case TIME_OF_DAY:
+		return DecodeTimeOfDay(data)
+	case TIME_DIFFERENCE:
+		return DecodeTimeDifference(data)
 	case DOMAIN:
 		return int64(0), nil
 	default:
@@ -263,8 +299,8 @@ func DecodeToType(data []byte, dataType uint8) (v any, e error) {
 	}
 }
 
-// Decode byte array given the CANopen data type
-// Function will return either string, int64, uint64, or float64
+// Decode byte array given the CANopen data type as a string,
+// TIME_OF_DAY & TIME_DIFFERENCE are given as raw values like in EDS files
 func DecodeToString(data []byte, dataType uint8, base int) (v string, e error) {
 	e = CheckSize(len(data), dataType)
 	if e != nil {
@@ -275,7 +311,7 @@ func DecodeToString(data []byte, dataType uint8, base int) (v string, e error) {
 	case BOOLEAN, UNSIGNED8:
 		return strconv.FormatUint(uint64(data[0]), base), nil
 	case INTEGER8:
-		return strconv.FormatInt(int64(data[0]), base), nil
+		return strconv.FormatInt(int64(int8(data[0])), base), nil
 	case UNSIGNED16:
 		return strconv.FormatUint(uint64(binary.LittleEndian.Uint16(data)), base), nil
 	case INTEGER16:
@@ -296,6 +332,12 @@ func DecodeToString(data []byte, dataType uint8, base int) (v string, e error) {
 		return strconv.FormatFloat(math.Float64frombits(parsed), 'f', -1, 64), nil
 	case VISIBLE_STRING, OCTET_STRING:
 		return string(data), nil
+	case UNICODE_STRING:
+		return DecodeUnicodeString(data)
+	case TIME_OF_DAY, TIME_DIFFERENCE:
+		raw := make([]byte, 8)
+		copy(raw, data)
+		return strconv.FormatUint(binary.LittleEndian.Uint64(raw), base), nil
 	case DOMAIN:
 		return "0", nil
 	default:
@@ -2,6 +2,7 @@ package od
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -85,3 +86,109 @@ func TestEncodeEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestEncodeDecodeDatatypes(t *testing.T) {
+	t.Run("unicode string", func(t *testing.T) {
+		data, err := EncodeFromString("aé€", UNICODE_STRING, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{'a', 0, 0xE9, 0, 0xAC, 0x20}, data)
+		// Surrogate pair
+		data = EncodeUnicodeString("𝄞")
+		assert.Len(t, data, 4)
+		decoded, err := DecodeToType(append(data, 0, 0, 'x', 0), UNICODE_STRING)
+		assert.Nil(t, err)
+		assert.Equal(t, "𝄞", decoded)
+		_, err = DecodeToType([]byte{'a', 0, 'b'}, UNICODE_STRING)
+		assert.Equal(t, ErrDataShort, err)
+		str, err := DecodeToString(EncodeUnicodeString("abc"), UNICODE_STRING, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", str)
+	})
+
+	t.Run("octet string", func(t *testing.T) {
+		decoded, err := DecodeToType([]byte{0x00, 0xFF}, OCTET_STRING)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0x00, 0xFF}, decoded)
+	})
+
+	t.Run("time of day", func(t *testing.T) {
+		date := time.Date(1984, time.January, 3, 0, 0, 1, 500_000_000, time.Local)
+		data, err := EncodeFromGeneric(date)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0xDC, 0x05, 0, 0, 0x02, 0}, data)
+		decoded, err := DecodeToType(data, TIME_OF_DAY)
+		assert.Nil(t, err)
+		assert.True(t, date.Equal(decoded.(time.Time)))
+		str, err := DecodeToString(data, TIME_OF_DAY, 16)
+		assert.Nil(t, err)
+		assert.Equal(t, "2000005dc", str)
+		encoded, err := EncodeFromString("0x2000005dc", TIME_OF_DAY, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, data, encoded)
+		_, err = EncodeTimeOfDay(time.Date(1983, time.December, 31, 0, 0, 0, 0, time.Local))
+		assert.Equal(t, ErrValueLow, err)
+		_, err = DecodeToType(data[:4], TIME_OF_DAY)
+		assert.Equal(t, ErrDataShort, err)
+	})
+
+	t.Run("time difference", func(t *testing.T) {
+		data, err := EncodeFromGeneric(49*time.Hour + 10*time.Millisecond)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0x8A, 0xEE, 0x36, 0, 0x02, 0}, data)
+		decoded, err := DecodeToType(data, TIME_DIFFERENCE)
+		assert.Nil(t, err)
+		assert.Equal(t, 49*time.Hour+10*time.Millisecond, decoded)
+		_, err = EncodeTimeDifference(-time.Second)
+		assert.Equal(t, ErrValueLow, err)
+	})
+
+	t.Run("encode from type", func(t *testing.T) {
+		data, err := EncodeFromType("ab", UNICODE_STRING)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{'a', 0, 'b', 0}, data)
+		data, err = EncodeFromType("ab", VISIBLE_STRING)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("ab"), data)
+		data, err = EncodeFromType(true, BOOLEAN)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{1}, data)
+		_, err = EncodeFromType(true, UNSIGNED8)
+		assert.Equal(t, ErrTypeMismatch, err)
+		_, err = EncodeFromType(uint8(1), UNSIGNED16)
+		assert.Equal(t, ErrDataShort, err)
+		_, err = EncodeFromType(time.Second, TIME_OF_DAY)
+		assert.Equal(t, ErrTypeMismatch, err)
+		_, err = EncodeFromType(time.Now(), TIME_DIFFERENCE)
+		assert.Equal(t, ErrTypeMismatch, err)
+	})
+}
+
+func TestBits(t *testing.T) {
+	assert.EqualValues(t, 0b1110000, BitMask(4, 3))
+	assert.EqualValues(t, ^uint64(0), BitMask(0, 64))
+
+	variable, err := NewVariable(0, "controlword", UNSIGNED16, AttributeSdoRw, "0x1111")
+	assert.Nil(t, err)
+	err = variable.SetBits(BitMask(0, 4), 0xF)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []byte{0x1F, 0x11}, variable.value)
+	bits, err := variable.Bits(0xFF00)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x1100, bits)
+	// Bits of value outside of mask are ignored
+	err = variable.SetBits(0x0100, 0xFFFF)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []byte{0x1F, 0x11}, variable.value)
+	err = variable.SetBits(0x1000, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []byte{0x1F, 0x01}, variable.value)
+	// Mask larger than variable
+	err = variable.SetBits(0x10000, 0)
+	assert.Equal(t, ErrValueHigh, err)
+
+	variable, err = NewVariable(0, "string", VISIBLE_STRING, AttributeSdoRw, "ab")
+	assert.Nil(t, err)
+	_, err = variable.Bits(0x1)
+	assert.Equal(t, ErrTypeMismatch, err)
+	assert.Equal(t, ErrTypeMismatch, variable.SetBits(0x1, 0x1))
+}
@@ -30,18 +30,22 @@ const (
 
 // Common to TPDO & RPDO
 type PDOCommon struct {
-	od             *od.ObjectDictionary
-	logger         *slog.Logger
-	emcy           *emergency.EMCY
-	streamers      [od.MaxMappedEntriesPdo]od.Streamer
-	Valid          bool
-	dataLength     uint32
-	nbMapped       uint8
-	flagPDOByte    [od.FlagsPdoSize]*byte
-	flagPDOBitmask [od.FlagsPdoSize]byte
-	IsRPDO         bool
-	predefinedId   uint16
-	configuredId   uint16
+	od           *od.ObjectDictionary
+	logger       *slog.Logger
+	emcy         *emergency.EMCY
+	streamers    [od.MaxMappedEntriesPdo]od.Streamer
+	mappedBits   [od.MaxMappedEntriesPdo]uint8 // Mapped length in bits, streamer DataOffset holds it in bytes
+	bitBuffer    [MaxPdoLength]byte            // Scratch buffer for objects that are not byte aligned
+	Valid        bool
+	dataLength   uint32
+	nbMapped     uint8
+	flagPDO      [od.FlagsPdoSize]od.PDOFlag
+	IsRPDO       bool
+	predefinedId uint16
+	configuredId uint16
+	mpdoMode     uint8  // One of MPDOModeNone, MPDOModeSAM, MPDOModeDAM
+	damMapping   uint32 // First mapping parameter, multiplexer of DAM MPDOs
+	nodeId       uint8
 }
 
 func (base *PDOCommon) attribute() uint8 {
@@ -63,8 +67,11 @@ func (pdo *PDOCommon) configureMap(mapParam uint32, mapIndex uint32, isRPDO bool
 	index := uint16(mapParam >> 16)
 	subIndex := byte(mapParam >> 8)
 	mappedLengthBits := byte(mapParam)
-	mappedLength := mappedLengthBits >> 3
+	mappedLength := uint8((uint16(mappedLengthBits) + 7) >> 3)
 	streamer := &pdo.streamers[mapIndex]
+	if mapIndex == 0 {
+		pdo.damMapping = mapParam
+	}
 
 	// Total PDO length should be smaller than the max possible size
 	if mappedLength > MaxPdoLength {
@@ -75,11 +82,20 @@ func (pdo *PDOCommon) configureMap(mapParam uint32, mapIndex uint32, isRPDO bool
 		)
 		return od.ErrMapLen
 	}
-	// Dummy entries map to "fake" entries
+	// Dummy entries map to "fake" entries, the index is the data type
 	if index < 0x20 && subIndex == 0 {
+		if err := od.CheckMappingLength(uint8(index), uint32(MaxPdoLength), mappedLengthBits); err != nil {
+			pdo.logger.Warn("mapping failed",
+				"index", fmt.Sprintf("x%x", index),
+				"subindex", fmt.Sprintf("x%x", subIndex),
+				"error", err,
+			)
+			return od.ErrNoMap
+		}
 		streamer.ResetData(uint32(mappedLength), uint32(mappedLength))
 		streamer.SetWriter(WriteDummy)
 		streamer.SetReader(ReadDummy)
+		pdo.mappedBits[mapIndex] = mappedLengthBits
 		return nil
 	}
 	// Get entry in OD
@@ -93,43 +109,37 @@ func (pdo *PDOCommon) configureMap(mapParam uint32, mapIndex uint32, isRPDO bool
 		)
 		return err
 	}
+	variable, err := entry.SubIndex(subIndex)
+	if err != nil {
+		return err
+	}
 
-	// Check correct attribute, length, and alignment
-	switch {
-	case !streamerCopy.HasAttribute(pdo.attribute()):
+	// Check correct attribute, then length & alignment
+	if !streamerCopy.HasAttribute(pdo.attribute()) {
 		pdo.logger.Warn("mapping failed : attribute error",
 			"index", fmt.Sprintf("x%x", index),
 			"subindex", fmt.Sprintf("x%x", subIndex),
 		)
 		return od.ErrNoMap
-	case (mappedLengthBits & 0x07) != 0:
-		pdo.logger.Warn("mapping failed : alignment error",
-			"index", fmt.Sprintf("x%x", index),
-			"subindex", fmt.Sprintf("x%x", subIndex),
-		)
-		return od.ErrNoMap
-	case streamerCopy.DataLength < uint32(mappedLength):
-		pdo.logger.Warn("mapping failed : length error",
+	}
+	if err := od.CheckMappingLength(variable.DataType, streamerCopy.DataLength, mappedLengthBits); err != nil {
+		pdo.logger.Warn("mapping failed",
 			"index", fmt.Sprintf("x%x", index),
 			"subindex", fmt.Sprintf("x%x", subIndex),
+			"error", err,
 		)
 		return od.ErrNoMap
-	default:
 	}
 	streamer.SetStream(streamerCopy.Stream)
 	streamer.SetReader(streamerCopy.Reader())
 	streamer.SetWriter(streamerCopy.Writer())
 	streamer.DataOffset = uint32(mappedLength)
+	pdo.mappedBits[mapIndex] = mappedLengthBits
 
 	if isRPDO {
 		return nil
 	}
-	if uint32(subIndex) < (uint32(od.FlagsPdoSize)*8) && entry.Extension() != nil {
-		pdo.flagPDOByte[mapIndex] = entry.FlagPDOByte(subIndex)
-		pdo.flagPDOBitmask[mapIndex] = 1 << (subIndex & 0x07)
-	} else {
-		pdo.flagPDOByte[mapIndex] = nil
-	}
+	pdo.flagPDO[mapIndex] = entry.PDOFlag(subIndex)
 	pdo.logger.Info("update mapping successful",
 		"index", fmt.Sprintf("x%x", index),
 		"subindex", fmt.Sprintf("x%x", subIndex),
@@ -163,7 +173,7 @@ func NewPDO(
 		pdo.logger = logger.With("service", "TPDO")
 	}
 
-	pdoDataLength := uint32(0)
+	pdoDataBits := uint32(0)
 
 	// Get number of mapped objects
 	mappedObjectsCount, err := entry.Uint8(0)
@@ -195,14 +205,23 @@ func NewPDO(
 		if err != nil {
 			// Init failed, but not critical
 			streamer.ResetData(0, 0xFF)
+			pdo.mappedBits[i] = 0
 			if *erroneoursMap == 0 {
 				*erroneoursMap = mapParam
 			}
 		}
 		if i < int(mappedObjectsCount) {
-			pdoDataLength += streamer.DataOffset
+			pdoDataBits += uint32(pdo.mappedBits[i])
 		}
 	}
+	pdoDataLength := (pdoDataBits + 7) >> 3
+
+	if isMPDOMode(mappedObjectsCount) {
+		if pdo.configureMPDO(mappedObjectsCount) != nil && *erroneoursMap == 0 {
+			*erroneoursMap = 1
+		}
+		return pdo, nil
+	}
 
 	if pdoDataLength > uint32(MaxPdoLength) || (pdoDataLength == 0 && mappedObjectsCount > 0) {
 		if *erroneoursMap == 0 {
@@ -215,3 +234,28 @@ func NewPDO(
 	}
 	return pdo, nil
 }
+
+// Copy nbBits of src into dst, starting at bit offset of dst.
+// Bits are ordered from the least significant bit of the first byte.
+func putBits(dst []byte, offset int, src []byte, nbBits int) {
+	for i := range nbBits {
+		pos := offset + i
+		if src[i>>3]&(1<<(i&0x07)) != 0 {
+			dst[pos>>3] |= 1 << (pos & 0x07)
+		} else {
+			dst[pos>>3] &^= 1 << (pos & 0x07)
+		}
+	}
+}
+
+// Copy nbBits of src, starting at bit offset of src, into dst.
+// Remaining bits of dst are cleared.
+func getBits(dst []byte, src []byte, offset int, nbBits int) {
+	clear(dst)
+	for i := range nbBits {
+		pos := offset + i
+		if src[pos>>3]&(1<<(pos&0x07)) != 0 {
+			dst[i>>3] |= 1 << (i & 0x07)
+		}
+	}
+}
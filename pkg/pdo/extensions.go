@@ -39,7 +39,7 @@ func writeEntry14xx(stream *od.Stream, data []byte, countWritten *uint16) error
 		if (cobId&0x3FFFF800) != 0 ||
 			valid && pdo.Valid && canId != uint32(pdo.configuredId) ||
 			valid && canopen.IsIDRestricted(uint16(canId)) ||
-			valid && pdo.nbMapped == 0 {
+			valid && !pdo.isMapped() {
 			return od.ErrInvalidValue
 		}
 
@@ -150,12 +150,18 @@ func writeEntry16xxOr1Axx(stream *od.Stream, data []byte, countWritten *uint16)
 	}
 	pdo.logger.Debug("updating mapping parameter")
 	// PDO must be disabled in order to allow mapping
-	if pdo.Valid || pdo.nbMapped != 0 && stream.Subindex > 0 {
+	if pdo.Valid || pdo.isMapped() && stream.Subindex > 0 {
 		return od.ErrUnsuppAccess
 	}
-	if stream.Subindex == 0 {
+	if stream.Subindex == 0 && isMPDOMode(data[0]) {
+		err := pdo.configureMPDO(data[0])
+		if err != nil {
+			return err
+		}
+		pdo.logger.Debug("updated to MPDO", "mode", data[0])
+	} else if stream.Subindex == 0 {
 		mappedObjectsCount := data[0]
-		pdoDataLength := uint32(0)
+		pdoDataBits := uint32(0)
 		// Don't allow number greater than possible mapped objects
 		if mappedObjectsCount > od.MaxMappedEntriesPdo {
 			return od.ErrMapLen
@@ -167,8 +173,9 @@ func writeEntry16xxOr1Axx(stream *od.Stream, data []byte, countWritten *uint16)
 			if mappedLength > dataLength {
 				return od.ErrNoMap
 			}
-			pdoDataLength += mappedLength
+			pdoDataBits += uint32(pdo.mappedBits[i])
 		}
+		pdoDataLength := (pdoDataBits + 7) >> 3
 		if pdoDataLength > uint32(MaxPdoLength) {
 			return od.ErrMapLen
 		}
@@ -177,6 +184,7 @@ func writeEntry16xxOr1Axx(stream *od.Stream, data []byte, countWritten *uint16)
 		}
 		pdo.dataLength = pdoDataLength
 		pdo.nbMapped = mappedObjectsCount
+		pdo.mpdoMode = MPDOModeNone
 		pdo.logger.Debug("updated number of mapped objects to", "count", mappedObjectsCount)
 	} else {
 		err := pdo.configureMap(binary.LittleEndian.Uint32(data), uint32(stream.Subindex)-1, pdo.IsRPDO)
@@ -217,7 +225,7 @@ func writeEntry18xx(stream *od.Stream, data []byte, countWritten *uint16) error
 		if (cobId&0x3FFFF800) != 0 ||
 			(valid && pdo.Valid && canId != uint32(pdo.configuredId)) ||
 			(valid && canopen.IsIDRestricted(uint16(canId))) ||
-			(valid && pdo.nbMapped == 0) {
+			(valid && !pdo.isMapped()) {
 			return od.ErrInvalidValue
 		}
 
@@ -0,0 +1,269 @@
+package pdo
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Multiplexed PDO modes, selected by writing the number of
+// mapped objects (sub-index 0 of mapping parameter)
+const (
+	MPDOModeNone uint8 = 0
+	MPDOModeSAM  uint8 = 0xFE // Source address mode, objects from scanner / dispatching lists
+	MPDOModeDAM  uint8 = 0xFF // Destination address mode, objects addressed directly in consumer OD
+)
+
+const (
+	mpdoAddressDAM   = 0x80 // Address type bit of first byte, set for DAM
+	mpdoMaxData      = 4    // Max data bytes of a single multiplexed object
+	mpdoRxBufferSize = 16   // Max received MPDOs between two RPDO processing
+)
+
+// An MPDO frame is always 8 bytes :
+// byte 0 : address type (bit 7) & node id (producer for SAM, consumer or 0 for DAM)
+// byte 1-2 : index
+// byte 3 : sub-index
+// byte 4-7 : data
+type mpdoFrame [MaxPdoLength]byte
+
+func (f *mpdoFrame) isDAM() bool {
+	return f[0]&mpdoAddressDAM != 0
+}
+
+func (f *mpdoFrame) nodeId() uint8 {
+	return f[0] & 0x7F
+}
+
+func (f *mpdoFrame) index() uint16 {
+	return binary.LittleEndian.Uint16(f[1:3])
+}
+
+func (f *mpdoFrame) subIndex() uint8 {
+	return f[3]
+}
+
+func isMPDOMode(mappedObjectsCount uint8) bool {
+	return mappedObjectsCount == MPDOModeSAM || mappedObjectsCount == MPDOModeDAM
+}
+
+// Configure PDO as MPDO, for DAM TPDOs the first mapped entry
+// is the transmitted object and should fit inside of an MPDO
+func (pdo *PDOCommon) configureMPDO(mode uint8) error {
+	if mode == MPDOModeDAM && !pdo.IsRPDO {
+		mappedLength := pdo.streamers[0].DataOffset
+		if mappedLength == 0 || mappedLength > mpdoMaxData {
+			return od.ErrMapLen
+		}
+		if pdo.mappedBits[0]%8 != 0 {
+			return od.ErrNoMap
+		}
+	}
+	pdo.mpdoMode = mode
+	pdo.nbMapped = 0
+	pdo.dataLength = uint32(MaxPdoLength)
+	return nil
+}
+
+// Check if PDO has something mapped, either regular entries or MPDO
+func (pdo *PDOCommon) isMapped() bool {
+	return pdo.nbMapped != 0 || pdo.mpdoMode != MPDOModeNone
+}
+
+// Check if object is listed in the object scanner list (0x1FA0 - 0x1FCF)
+// Each entry is block size (bits 24-31), sub-index (bits 16-23), index (bits 0-15)
+func (pdo *PDOCommon) inScannerList(index uint16, subIndex uint8) bool {
+	for listIndex := od.EntryObjectScannerListStart; listIndex <= od.EntryObjectScannerListEnd; listIndex++ {
+		entry := pdo.od.Index(listIndex)
+		if entry == nil {
+			continue
+		}
+		count, err := entry.Uint8(0)
+		if err != nil {
+			continue
+		}
+		for sub := uint8(1); sub <= count && sub != 0; sub++ {
+			scan, err := entry.Uint32(sub)
+			if err != nil {
+				break
+			}
+			scanIndex := uint16(scan)
+			scanSubIndex := uint8(scan >> 16)
+			blockSize := uint8(scan >> 24)
+			if blockSize == 0 {
+				blockSize = 1
+			}
+			if scanIndex == index && subIndex >= scanSubIndex && int(subIndex) < int(scanSubIndex)+int(blockSize) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Find the local object of a SAM MPDO inside of the object dispatching list (0x1FD0 - 0x1FFF)
+// Each entry is block size (bits 56-63), local index (bits 40-55), local sub-index (bits 32-39),
+// producer index (bits 16-31), producer sub-index (bits 8-15), producer node id (bits 0-7)
+func (pdo *PDOCommon) dispatch(nodeId uint8, index uint16, subIndex uint8) (uint16, uint8, bool) {
+	for listIndex := od.EntryObjectDispatchingListStart; listIndex <= od.EntryObjectDispatchingListEnd; listIndex++ {
+		entry := pdo.od.Index(listIndex)
+		if entry == nil {
+			continue
+		}
+		count, err := entry.Uint8(0)
+		if err != nil {
+			continue
+		}
+		for sub := uint8(1); sub <= count && sub != 0; sub++ {
+			dispatch, err := entry.Uint64(sub)
+			if err != nil {
+				break
+			}
+			producerNodeId := uint8(dispatch)
+			producerSubIndex := uint8(dispatch >> 8)
+			producerIndex := uint16(dispatch >> 16)
+			localSubIndex := uint8(dispatch >> 32)
+			localIndex := uint16(dispatch >> 40)
+			blockSize := uint8(dispatch >> 56)
+			if blockSize == 0 {
+				blockSize = 1
+			}
+			if producerNodeId != nodeId || producerIndex != index ||
+				subIndex < producerSubIndex || int(subIndex) >= int(producerSubIndex)+int(blockSize) {
+				continue
+			}
+			return localIndex, localSubIndex + (subIndex - producerSubIndex), true
+		}
+	}
+	return 0, 0, false
+}
+
+// Write received MPDO to OD, called with rpdo.mu held
+func (rpdo *RPDO) processMPDO(frame mpdoFrame) {
+	pdo := rpdo.pdo
+	index, subIndex := frame.index(), frame.subIndex()
+	switch {
+	case pdo.mpdoMode == MPDOModeDAM && frame.isDAM():
+		if frame.nodeId() != 0 && frame.nodeId() != pdo.nodeId {
+			return
+		}
+	case pdo.mpdoMode == MPDOModeSAM && !frame.isDAM():
+		var ok bool
+		index, subIndex, ok = pdo.dispatch(frame.nodeId(), index, subIndex)
+		if !ok {
+			return
+		}
+	default:
+		return
+	}
+	streamer, err := pdo.od.Streamer(index, subIndex, false)
+	if err == nil && !streamer.HasAttribute(od.AttributeRpdo) {
+		err = od.ErrNoMap
+	}
+	if err == nil && (streamer.DataLength == 0 || streamer.DataLength > mpdoMaxData) {
+		err = od.ErrMapLen
+	}
+	if err == nil {
+		_, err = streamer.Write(frame[4 : 4+streamer.DataLength])
+	}
+	if err != nil {
+		pdo.logger.Warn("failed to write to OD on MPDO reception",
+			"configured id", pdo.configuredId,
+			"index", fmt.Sprintf("x%x", index),
+			"subindex", fmt.Sprintf("x%x", subIndex),
+			"error", err,
+		)
+	}
+}
+
+// Send MPDO frame, called with tpdo.mu held
+func (tpdo *TPDO) sendMPDO() error {
+	if tpdo.sendHook != nil {
+		tpdo.sendHook(tpdo.txBuffer)
+	}
+	err := tpdo.Send(tpdo.txBuffer)
+	if err != nil {
+		tpdo.stats.Errors++
+	} else {
+		tpdo.stats.Frames++
+	}
+	return err
+}
+
+// Send a destination address mode MPDO with the value of the first
+// mapped object. The index & sub-index of the mapped object are those
+// written inside of the consumer's OD. Use node id 0 for all nodes.
+func (tpdo *TPDO) SendDAM(nodeId uint8) error {
+	tpdo.mu.Lock()
+	defer tpdo.mu.Unlock()
+
+	pdo := tpdo.pdo
+	if !pdo.Valid || pdo.mpdoMode != MPDOModeDAM || nodeId > 127 {
+		return canopen.ErrIllegalArgument
+	}
+	streamer := &pdo.streamers[0]
+	mappedLength := streamer.DataOffset
+	clear(tpdo.txBuffer.Data[:])
+	tpdo.txBuffer.Data[0] = mpdoAddressDAM | nodeId
+	binary.LittleEndian.PutUint16(tpdo.txBuffer.Data[1:3], uint16(pdo.damMapping>>16))
+	tpdo.txBuffer.Data[3] = uint8(pdo.damMapping >> 8)
+	streamer.DataOffset = 0
+	_, err := streamer.Read(tpdo.txBuffer.Data[4:])
+	streamer.DataOffset = mappedLength
+	if err != nil {
+		tpdo.stats.Errors++
+		return err
+	}
+	clear(tpdo.txBuffer.Data[4+mappedLength:])
+	return tpdo.sendMPDO()
+}
+
+// Send a source address mode MPDO with the value of an object
+// of the local OD. The object must be listed in the object scanner list.
+func (tpdo *TPDO) SendSAM(index uint16, subIndex uint8) error {
+	tpdo.mu.Lock()
+	defer tpdo.mu.Unlock()
+
+	pdo := tpdo.pdo
+	if !pdo.Valid || pdo.mpdoMode != MPDOModeSAM {
+		return canopen.ErrIllegalArgument
+	}
+	if !pdo.inScannerList(index, subIndex) {
+		return od.ErrNoMap
+	}
+	streamer, err := pdo.od.Streamer(index, subIndex, false)
+	if err != nil {
+		return err
+	}
+	if streamer.DataLength == 0 || streamer.DataLength > mpdoMaxData {
+		return od.ErrMapLen
+	}
+	clear(tpdo.txBuffer.Data[:])
+	tpdo.txBuffer.Data[0] = pdo.nodeId
+	binary.LittleEndian.PutUint16(tpdo.txBuffer.Data[1:3], index)
+	tpdo.txBuffer.Data[3] = subIndex
+	_, err = streamer.Read(tpdo.txBuffer.Data[4 : 4+streamer.DataLength])
+	if err != nil {
+		tpdo.stats.Errors++
+		return err
+	}
+	return tpdo.sendMPDO()
+}
+
+// Set the node id used for MPDOs, i.e. the source of SAM MPDOs
+// and the destination of received DAM MPDOs
+func (tpdo *TPDO) SetNodeId(nodeId uint8) {
+	tpdo.mu.Lock()
+	defer tpdo.mu.Unlock()
+	tpdo.pdo.nodeId = nodeId
+}
+
+// Set the node id used for MPDOs, i.e. the source of SAM MPDOs
+// and the destination of received DAM MPDOs
+func (rpdo *RPDO) SetNodeId(nodeId uint8) {
+	rpdo.mu.Lock()
+	defer rpdo.mu.Unlock()
+	rpdo.pdo.nodeId = nodeId
+}
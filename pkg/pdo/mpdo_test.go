@@ -0,0 +1,105 @@
+package pdo
+
+import (
+	"encoding/binary"
+	"testing"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/can"
+	"github.com/samsamfire/gocanopen/pkg/emergency"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeUint(t *testing.T, odict *od.ObjectDictionary, index uint16, subIndex uint8, value any) {
+	var data []byte
+	switch v := value.(type) {
+	case uint8:
+		data = []byte{v}
+	case uint32:
+		data = binary.LittleEndian.AppendUint32(nil, v)
+	case uint64:
+		data = binary.LittleEndian.AppendUint64(nil, v)
+	}
+	assert.Nil(t, odict.Index(index).WriteExactly(subIndex, data, false))
+}
+
+func TestMPDO(t *testing.T) {
+	busProducer, busConsumer := can.NewLoopbackBus("mpdo"), can.NewLoopbackBus("mpdo")
+	bmProducer, bmConsumer := canopen.NewBusManager(busProducer), canopen.NewBusManager(busConsumer)
+	assert.Nil(t, busConsumer.Subscribe(bmConsumer))
+	for _, bus := range []*can.LoopbackBus{busProducer, busConsumer} {
+		assert.Nil(t, bus.Connect())
+		defer bus.Disconnect()
+	}
+	odProducer, odConsumer := od.Default(), od.Default()
+	newTPDO := func(nb uint16) *TPDO {
+		tpdo, err := NewTPDO(bmProducer, nil, odProducer, &emergency.EMCY{}, nil,
+			odProducer.Index(od.EntryTPDOCommunicationStart+nb), odProducer.Index(od.EntryTPDOMappingStart+nb), 0x180+nb*0x100+0x10)
+		assert.Nil(t, err)
+		tpdo.SetNodeId(0x10)
+		return tpdo
+	}
+	newRPDO := func(nb uint16) *RPDO {
+		rpdo, err := NewRPDO(bmConsumer, nil, odConsumer, &emergency.EMCY{}, nil,
+			odConsumer.Index(od.EntryRPDOCommunicationStart+nb), odConsumer.Index(od.EntryRPDOMappingStart+nb), 0x200+nb*0x100+0x20)
+		assert.Nil(t, err)
+		rpdo.SetNodeId(0x20)
+		return rpdo
+	}
+
+	t.Run("DAM", func(t *testing.T) {
+		tpdo, rpdo := newTPDO(0), newRPDO(0)
+		// Producer sends 0x2003 (16 bits) to consumer's 0x2003
+		writeUint(t, odProducer, od.EntryTPDOMappingStart, 0, uint8(0))
+		writeUint(t, odProducer, od.EntryTPDOMappingStart, 1, uint32(0x20030010))
+		writeUint(t, odProducer, od.EntryTPDOMappingStart, 0, MPDOModeDAM)
+		writeUint(t, odProducer, od.EntryTPDOCommunicationStart, 1, uint32(0x190))
+		writeUint(t, odConsumer, od.EntryRPDOMappingStart, 0, MPDOModeDAM)
+		writeUint(t, odConsumer, od.EntryRPDOCommunicationStart, 1, uint32(0x190))
+		assert.Nil(t, odProducer.Index(0x2003).PutUint16(0, 0x1234, true))
+
+		// Other node is ignored
+		assert.Nil(t, tpdo.SendDAM(0x21))
+		rpdo.Process(0, nil, true, false)
+		value, _ := odConsumer.Index(0x2003).Uint16(0)
+		assert.EqualValues(t, 0x4444, value)
+
+		assert.Nil(t, tpdo.SendDAM(0x20))
+		rpdo.Process(0, nil, true, false)
+		value, _ = odConsumer.Index(0x2003).Uint16(0)
+		assert.EqualValues(t, 0x1234, value)
+
+		// Broadcast
+		assert.Nil(t, odProducer.Index(0x2003).PutUint16(0, 0x5678, true))
+		assert.Nil(t, tpdo.SendDAM(0))
+		rpdo.Process(0, nil, true, false)
+		value, _ = odConsumer.Index(0x2003).Uint16(0)
+		assert.EqualValues(t, 0x5678, value)
+		assert.Equal(t, canopen.ErrIllegalArgument, tpdo.SendSAM(0x2003, 0))
+	})
+
+	t.Run("SAM", func(t *testing.T) {
+		tpdo, rpdo := newTPDO(1), newRPDO(1)
+		_, err := odProducer.AddObjectScannerList(0, 1)
+		assert.Nil(t, err)
+		_, err = odConsumer.AddObjectDispatchingList(0, 1)
+		assert.Nil(t, err)
+		// Producer 0x2004 is dispatched to consumer 0x2004
+		writeUint(t, odProducer, od.EntryObjectScannerListStart, 1, uint32(0x01002004))
+		writeUint(t, odConsumer, od.EntryObjectDispatchingListStart, 1, uint64(0x01_2004_00_2004_00_10))
+		writeUint(t, odProducer, od.EntryTPDOMappingStart+1, 0, uint8(0))
+		writeUint(t, odProducer, od.EntryTPDOMappingStart+1, 0, MPDOModeSAM)
+		writeUint(t, odProducer, od.EntryTPDOCommunicationStart+1, 1, uint32(0x291))
+		writeUint(t, odConsumer, od.EntryRPDOMappingStart+1, 0, MPDOModeSAM)
+		writeUint(t, odConsumer, od.EntryRPDOCommunicationStart+1, 1, uint32(0x291))
+		assert.Nil(t, odProducer.Index(0x2004).PutUint32(0, 0xCAFEBABE, true))
+
+		assert.Equal(t, od.ErrNoMap, tpdo.SendSAM(0x2003, 0))
+		assert.Nil(t, tpdo.SendSAM(0x2004, 0))
+		rpdo.Process(0, nil, true, false)
+		value, _ := odConsumer.Index(0x2004).Uint32(0)
+		assert.EqualValues(t, 0xCAFEBABE, value)
+		assert.EqualValues(t, 1, rpdo.Stats().Frames)
+	})
+}
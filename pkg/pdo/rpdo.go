@@ -26,11 +26,59 @@ type RPDO struct {
 	pdo           *PDOCommon
 	rxNew         [BufferCountRpdo]bool
 	rxData        [BufferCountRpdo][MaxPdoLength]byte
+	rxBuffer      [MaxPdoLength]byte // Copy of rxData being written to OD, avoids allocations
 	receiveError  uint8
 	sync          *sync.SYNC
 	synchronous   bool
 	timeoutTimeUs uint32
 	timeoutTimer  uint32
+	stats         Stats
+	mpdoRx        []mpdoFrame
+	eventHook     func(event RPDOEvent)
+}
+
+// Type of an [RPDOEvent]
+type RPDOEventType uint8
+
+const (
+	RPDOTimeout     RPDOEventType = 1 // No RPDO received within event timer, EMCY [emergency.ErrRpdoTimeout]
+	RPDORecovered   RPDOEventType = 2 // RPDO received again after a timeout
+	RPDOLengthShort RPDOEventType = 3 // RPDO shorter than mapping, EMCY [emergency.ErrPdoLength]
+	RPDOLengthLong  RPDOEventType = 4 // RPDO longer than mapping, EMCY [emergency.ErrPdoLengthExc]
+	RPDOLengthOk    RPDOEventType = 5 // RPDO of correct length received after a length error
+)
+
+func (t RPDOEventType) String() string {
+	switch t {
+	case RPDOTimeout:
+		return "timeout"
+	case RPDORecovered:
+		return "recovered"
+	case RPDOLengthShort:
+		return "length short"
+	case RPDOLengthLong:
+		return "length long"
+	case RPDOLengthOk:
+		return "length ok"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint8(t))
+	}
+}
+
+// Error of an [RPDO], reported with an EMCY and to the event hook, see [RPDO.SetEventHook]
+type RPDOEvent struct {
+	Type      RPDOEventType
+	CobId     uint16 // CAN ID of the RPDO
+	ErrorCode uint16 // EMCY error code, 0 when the error is cleared
+}
+
+// Set a hook called on RPDO timeouts & length errors, and when they are cleared.
+// Hook is called from the processing goroutine and should not block.
+// Setting it to nil removes the hook.
+func (rpdo *RPDO) SetEventHook(hook func(event RPDOEvent)) {
+	rpdo.mu.Lock()
+	defer rpdo.mu.Unlock()
+	rpdo.eventHook = hook
 }
 
 // Handle [RPDO] related RX CAN frames
@@ -42,6 +90,16 @@ func (rpdo *RPDO) Handle(frame canopen.Frame) {
 	if !pdo.Valid {
 		return
 	}
+	// MPDOs are queued as several objects can be received between two processing
+	if pdo.mpdoMode != MPDOModeNone {
+		if frame.DLC != MaxPdoLength || len(rpdo.mpdoRx) >= mpdoRxBufferSize {
+			rpdo.stats.Errors++
+			return
+		}
+		rpdo.mpdoRx = append(rpdo.mpdoRx, frame.Data)
+		rpdo.stats.Frames++
+		return
+	}
 	if frame.DLC >= uint8(pdo.dataLength) {
 		// Indicate if errors in PDO length
 		if frame.DLC == uint8(pdo.dataLength) {
@@ -60,20 +118,54 @@ func (rpdo *RPDO) Handle(frame canopen.Frame) {
 		}
 		rpdo.rxData[bufNo] = frame.Data
 		rpdo.rxNew[bufNo] = true
+		rpdo.stats.Frames++
+		if frame.DLC != uint8(pdo.dataLength) {
+			rpdo.stats.Errors++
+		}
 
-	} else if err == rpdoRxAckNoError {
-		err = rpdoRxShort
+	} else {
+		rpdo.stats.Errors++
+		if err == rpdoRxAckNoError {
+			err = rpdoRxShort
+		}
 	}
 	rpdo.receiveError = err
 }
 
+// Inject data as if it had been received on the bus.
+// The frame goes through the same validation as a received frame
+// and the OD is updated on next [RPDO.Process] call.
+func (rpdo *RPDO) Inject(data []byte) error {
+	if len(data) > int(MaxPdoLength) {
+		return canopen.ErrIllegalArgument
+	}
+	rpdo.mu.Lock()
+	frame := canopen.NewFrame(uint32(rpdo.pdo.configuredId), 0, uint8(len(data)))
+	rpdo.mu.Unlock()
+	copy(frame.Data[:], data)
+	rpdo.Handle(frame)
+	return nil
+}
+
 // Process [RPDO] state machine and TX CAN frames
 // This should be called periodically
 func (rpdo *RPDO) Process(timeDifferenceUs uint32, timerNext *uint32, nmtIsOperational bool, syncWas bool) {
+	// Events are reported once unlocked
+	var events []RPDOEvent
+	var hook func(event RPDOEvent)
+	defer func() {
+		for _, event := range events {
+			hook(event)
+		}
+	}()
 	rpdo.mu.Lock()
 	defer rpdo.mu.Unlock()
-
-	var buffer = make([]byte, 0, MaxPdoLength)
+	hook = rpdo.eventHook
+	report := func(eventType RPDOEventType, errorCode uint16) {
+		if hook != nil {
+			events = append(events, RPDOEvent{Type: eventType, CobId: rpdo.pdo.configuredId, ErrorCode: errorCode})
+		}
+	}
 
 	pdo := rpdo.pdo
 	if !pdo.Valid || !nmtIsOperational || (!syncWas && rpdo.synchronous) {
@@ -82,9 +174,17 @@ func (rpdo *RPDO) Process(timeDifferenceUs uint32, timerNext *uint32, nmtIsOpera
 			rpdo.rxNew[0] = false
 			rpdo.rxNew[1] = false
 			rpdo.timeoutTimer = 0
+			rpdo.mpdoRx = rpdo.mpdoRx[:0]
 		}
 		return
 	}
+	if pdo.mpdoMode != MPDOModeNone {
+		for _, frame := range rpdo.mpdoRx {
+			rpdo.processMPDO(frame)
+		}
+		rpdo.mpdoRx = rpdo.mpdoRx[:0]
+		return
+	}
 	// Check errors in length of received messages
 	if rpdo.receiveError > rpdoRxAck {
 		setError := rpdo.receiveError != rpdoRxOk
@@ -95,8 +195,14 @@ func (rpdo *RPDO) Process(timeDifferenceUs uint32, timerNext *uint32, nmtIsOpera
 		pdo.emcy.Error(setError, emergency.EmRPDOWrongLength, uint16(errorCode), pdo.dataLength)
 		if setError {
 			rpdo.receiveError = rpdoRxAckError
+			if errorCode == emergency.ErrPdoLength {
+				report(RPDOLengthShort, uint16(errorCode))
+			} else {
+				report(RPDOLengthLong, uint16(errorCode))
+			}
 		} else {
 			rpdo.receiveError = rpdoRxAckNoError
+			report(RPDOLengthOk, 0)
 		}
 	}
 	// Get the correct rx buffer
@@ -106,22 +212,31 @@ func (rpdo *RPDO) Process(timeDifferenceUs uint32, timerNext *uint32, nmtIsOpera
 	}
 	// Copy RPDO into OD variables
 	rpdoReceived := false
-	totalNbWritten := uint32(0)
+	totalBitsWritten := 0
 
 	for rpdo.rxNew[bufNo] {
 		rpdoReceived = true
-		dataRPDO := rpdo.rxData[bufNo]
+		rpdo.rxBuffer = rpdo.rxData[bufNo]
 		rpdo.rxNew[bufNo] = false
 		for i := range pdo.nbMapped {
 			streamer := &pdo.streamers[i]
 			mappedLength := streamer.DataOffset
+			mappedBits := int(pdo.mappedBits[i])
 			dataLength := streamer.DataLength
 			if dataLength > uint32(MaxPdoLength) {
 				dataLength = uint32(MaxPdoLength)
 			}
-			buffer = dataRPDO[totalNbWritten : totalNbWritten+mappedLength]
-			if dataLength > uint32(mappedLength) {
-				buffer = buffer[:cap(buffer)]
+			var buffer []byte
+			if totalBitsWritten%8 == 0 && mappedBits%8 == 0 {
+				offset := uint32(totalBitsWritten / 8)
+				buffer = rpdo.rxBuffer[offset : offset+mappedLength]
+				if dataLength > uint32(mappedLength) {
+					buffer = buffer[:cap(buffer)]
+				}
+			} else {
+				// Not byte aligned, unmapped bits of the object are written as 0
+				getBits(pdo.bitBuffer[:], rpdo.rxBuffer[:], totalBitsWritten, mappedBits)
+				buffer = pdo.bitBuffer[:max(mappedLength, dataLength)]
 			}
 			streamer.DataOffset = 0
 			_, err := streamer.Write(buffer)
@@ -132,7 +247,7 @@ func (rpdo *RPDO) Process(timeDifferenceUs uint32, timerNext *uint32, nmtIsOpera
 				)
 			}
 			streamer.DataOffset = mappedLength
-			totalNbWritten += mappedLength
+			totalBitsWritten += mappedBits
 		}
 	}
 	if rpdo.timeoutTimeUs <= 0 {
@@ -142,12 +257,15 @@ func (rpdo *RPDO) Process(timeDifferenceUs uint32, timerNext *uint32, nmtIsOpera
 	if rpdoReceived {
 		if rpdo.timeoutTimer > rpdo.timeoutTimeUs {
 			pdo.emcy.ErrorReset(emergency.EmRPDOTimeOut, rpdo.timeoutTimer)
+			report(RPDORecovered, 0)
 		}
 		rpdo.timeoutTimer = 1
 	} else if rpdo.timeoutTimer > 0 && rpdo.timeoutTimer < rpdo.timeoutTimeUs {
 		rpdo.timeoutTimer += timeDifferenceUs
 		if rpdo.timeoutTimer > rpdo.timeoutTimeUs {
+			rpdo.stats.Timeouts++
 			pdo.emcy.ErrorReport(emergency.EmRPDOTimeOut, emergency.ErrRpdoTimeout, rpdo.timeoutTimer)
+			report(RPDOTimeout, emergency.ErrRpdoTimeout)
 		}
 	}
 	if timerNext != nil && rpdo.timeoutTimer < rpdo.timeoutTimeUs {
@@ -174,7 +292,7 @@ func (rpdo *RPDO) configureCOBID(entry14xx *od.Entry, predefinedIdent uint32, er
 	}
 	valid := (cobId & 0x80000000) == 0
 	canId = cobId & 0x7FF
-	if valid && (pdo.nbMapped == 0 || canId == 0) {
+	if valid && (!pdo.isMapped() || canId == 0) {
 		valid = false
 		if erroneousMap == 0 {
 			erroneousMap = 1
@@ -215,7 +333,7 @@ func NewRPDO(
 	if odict == nil || entry14xx == nil || entry16xx == nil || bm == nil || emcy == nil {
 		return nil, canopen.ErrIllegalArgument
 	}
-	rpdo := &RPDO{BusManager: bm}
+	rpdo := &RPDO{BusManager: bm, mpdoRx: make([]mpdoFrame, 0, mpdoRxBufferSize)}
 	// Configure mapping parameters
 	erroneousMap := uint32(0)
 	pdo, err := NewPDO(odict, logger, entry16xx, true, emcy, &erroneousMap)
@@ -0,0 +1,36 @@
+package pdo
+
+// Diagnostic counters of a PDO, counters are 32 bits and wrap around.
+type Stats struct {
+	Frames   uint32 // Frames sent (TPDO) or accepted (RPDO)
+	Errors   uint32 // Send failures (TPDO) or frames received with a wrong length (RPDO)
+	Timeouts uint32 // RPDO timeouts, always 0 for TPDOs
+}
+
+// Get diagnostic counters of [TPDO]
+func (tpdo *TPDO) Stats() Stats {
+	tpdo.mu.Lock()
+	defer tpdo.mu.Unlock()
+	return tpdo.stats
+}
+
+// Reset diagnostic counters of [TPDO]
+func (tpdo *TPDO) ResetStats() {
+	tpdo.mu.Lock()
+	defer tpdo.mu.Unlock()
+	tpdo.stats = Stats{}
+}
+
+// Get diagnostic counters of [RPDO]
+func (rpdo *RPDO) Stats() Stats {
+	rpdo.mu.Lock()
+	defer rpdo.mu.Unlock()
+	return rpdo.stats
+}
+
+// Reset diagnostic counters of [RPDO]
+func (rpdo *RPDO) ResetStats() {
+	rpdo.mu.Lock()
+	defer rpdo.mu.Unlock()
+	rpdo.stats = Stats{}
+}
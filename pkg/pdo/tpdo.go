@@ -25,6 +25,26 @@ type TPDO struct {
 	eventTimeUs      uint32
 	inhibitTimer     uint32
 	eventTimer       uint32
+	sendHook         func(frame canopen.Frame)
+	stats            Stats
+}
+
+// Set a hook called with every frame sent by this [TPDO].
+// Hook is called from the processing goroutine and should not block.
+// Setting it to nil removes the hook.
+func (tpdo *TPDO) SetSendHook(hook func(frame canopen.Frame)) {
+	tpdo.mu.Lock()
+	defer tpdo.mu.Unlock()
+	tpdo.sendHook = hook
+}
+
+// Request transmission of this [TPDO] if it is event driven (transmission types 0, 254 & 255).
+// It is sent on next processing (or next SYNC for type 0), inhibit time permitting.
+// See also [od.Entry.RequestTPDO] for requesting the TPDOs mapping a given entry.
+func (tpdo *TPDO) Request() {
+	tpdo.mu.Lock()
+	defer tpdo.mu.Unlock()
+	tpdo.sendRequest = true
 }
 
 // Process [TPDO] state machine and TX CAN frames
@@ -41,6 +61,11 @@ func (tpdo *TPDO) Process(timeDifferenceUs uint32, timerNextUs *uint32, nmtIsOpe
 		tpdo.mu.Unlock()
 		return nil
 	}
+	// MPDOs are only sent on application request, see [TPDO.SendDAM] & [TPDO.SendSAM]
+	if pdo.mpdoMode != MPDOModeNone {
+		tpdo.mu.Unlock()
+		return nil
+	}
 
 	if tpdo.transmissionType == TransmissionTypeSyncAcyclic || tpdo.transmissionType >= TransmissionTypeSyncEventLo {
 		if tpdo.eventTimeUs != 0 {
@@ -59,11 +84,8 @@ func (tpdo *TPDO) Process(timeDifferenceUs uint32, timerNextUs *uint32, nmtIsOpe
 		// Check for tpdo send requests
 		if !tpdo.sendRequest {
 			for i := range pdo.nbMapped {
-				flagPDOByte := pdo.flagPDOByte[i]
-				if flagPDOByte != nil {
-					if (*flagPDOByte & pdo.flagPDOBitmask[i]) == 0 {
-						tpdo.sendRequest = true
-					}
+				if pdo.flagPDO[i].Requested() {
+					tpdo.sendRequest = true
 				}
 			}
 		}
@@ -160,7 +182,7 @@ func (tpdo *TPDO) configureCOBID(entry18xx *od.Entry, predefinedIdent uint16, er
 	}
 	valid := (cobId & 0x80000000) == 0
 	canId = uint16(cobId & 0x7FF)
-	if valid && (pdo.nbMapped == 0 || canId == 0) {
+	if valid && (!pdo.isMapped() || canId == 0) {
 		valid = false
 		if erroneousMap == 0 {
 			erroneousMap = 1
@@ -193,35 +215,52 @@ func (tpdo *TPDO) send() error {
 	pdo := tpdo.pdo
 	eventDriven := tpdo.transmissionType == TransmissionTypeSyncAcyclic || tpdo.transmissionType >= uint8(TransmissionTypeSyncEventLo)
 
-	totalNbRead := 0
+	totalBitsRead := 0
 	var err error
 
 	for i := range pdo.nbMapped {
 		streamer := &pdo.streamers[i]
 		mappedLength := streamer.DataOffset
-		dataLength := int(streamer.DataLength)
-		if dataLength > int(MaxPdoLength) {
-			dataLength = int(MaxPdoLength)
-		}
+		mappedBits := int(pdo.mappedBits[i])
 
 		streamer.DataOffset = 0
-		_, err = streamer.Read(tpdo.txBuffer.Data[totalNbRead:])
+		if totalBitsRead%8 == 0 && mappedBits%8 == 0 {
+			_, err = streamer.Read(tpdo.txBuffer.Data[totalBitsRead/8:])
+		} else {
+			// Not byte aligned, object is read entirely then only mapped bits are copied
+			clear(pdo.bitBuffer[:])
+			_, err = streamer.Read(pdo.bitBuffer[:])
+			putBits(tpdo.txBuffer.Data[:], totalBitsRead, pdo.bitBuffer[:], mappedBits)
+		}
+		streamer.DataOffset = mappedLength
 		if err != nil {
 			tpdo.pdo.logger.Warn("failed to send", "cobId", pdo.configuredId, "error", err)
+			tpdo.stats.Errors++
 			return err
 		}
-		streamer.DataOffset = mappedLength
 		// Add to tpdo frame only up to mapped length
-		flagPDOByte := pdo.flagPDOByte[i]
-		if flagPDOByte != nil && eventDriven {
-			*flagPDOByte |= pdo.flagPDOBitmask[i]
+		if eventDriven {
+			pdo.flagPDO[i].Acknowledge()
 		}
-		totalNbRead += int(mappedLength)
+		totalBitsRead += mappedBits
+	}
+	// Clear the unused bits of the last byte
+	if unused := totalBitsRead % 8; unused != 0 {
+		tpdo.txBuffer.Data[totalBitsRead/8] &= 1<<unused - 1
 	}
 	tpdo.sendRequest = false
 	tpdo.eventTimer = tpdo.eventTimeUs
 	tpdo.inhibitTimer = tpdo.inhibitTimeUs
-	return tpdo.Send(tpdo.txBuffer)
+	if tpdo.sendHook != nil {
+		tpdo.sendHook(tpdo.txBuffer)
+	}
+	err = tpdo.Send(tpdo.txBuffer)
+	if err != nil {
+		tpdo.stats.Errors++
+	} else {
+		tpdo.stats.Frames++
+	}
+	return err
 }
 
 // Create a new TPDO
@@ -26,3 +26,176 @@ func BenchmarkXxx(b *testing.B) {
 	}
 
 }
+
+// Bus discarding sent frames, for measuring the PDO path only
+type nopBus struct{}
+
+func (b *nopBus) Connect(...any) error                           { return nil }
+func (b *nopBus) Disconnect() error                              { return nil }
+func (b *nopBus) Send(frame canopen.Frame) error                 { return nil }
+func (b *nopBus) Subscribe(callback canopen.FrameListener) error { return nil }
+
+// Map a full 8 bytes PDO with 4 objects
+func mapFullPDO(t testing.TB, odict *od.ObjectDictionary, mappingIndex uint16) {
+	mapping := odict.Index(mappingIndex)
+	assert.Nil(t, mapping.PutUint8(0, 0, false))
+	for i, param := range []uint32{0x20020008, 0x20030010, 0x20040020, 0x20050008} {
+		assert.Nil(t, mapping.PutUint32(uint8(i+1), param, false))
+	}
+	assert.Nil(t, mapping.PutUint8(0, 4, false))
+}
+
+func newFullTPDO(t testing.TB) *TPDO {
+	odict := od.Default()
+	tpdo, err := NewTPDO(canopen.NewBusManager(&nopBus{}), nil, odict, &emergency.EMCY{}, nil, odict.Index(0x1800), odict.Index(0x1A00), 0x181)
+	assert.Nil(t, err)
+	mapFullPDO(t, odict, 0x1A00)
+	assert.Nil(t, odict.Index(0x1800).PutUint8(2, TransmissionTypeSyncEventLo, false))
+	assert.Nil(t, odict.Index(0x1800).PutUint32(1, 0x181, false))
+	return tpdo
+}
+
+func newFullRPDO(t testing.TB) *RPDO {
+	odict := od.Default()
+	rpdo, err := NewRPDO(canopen.NewBusManager(&nopBus{}), nil, odict, &emergency.EMCY{}, nil, odict.Index(0x1400), odict.Index(0x1600), 0x201)
+	assert.Nil(t, err)
+	mapFullPDO(t, odict, 0x1600)
+	assert.Nil(t, odict.Index(0x1400).PutUint32(1, 0x201, false))
+	return rpdo
+}
+
+func TestPDOProcessNoAllocs(t *testing.T) {
+	tpdo, rpdo := newFullTPDO(t), newFullRPDO(t)
+	frame := canopen.NewFrame(0x201, 0, MaxPdoLength)
+	allocs := testing.AllocsPerRun(100, func() {
+		tpdo.sendRequest = true
+		_ = tpdo.Process(1000, nil, true, false)
+		rpdo.Handle(frame)
+		rpdo.Process(1000, nil, true, false)
+	})
+	assert.Zero(t, allocs)
+	assert.NotZero(t, tpdo.Stats().Frames)
+	assert.NotZero(t, rpdo.Stats().Frames)
+}
+
+func BenchmarkTPDOProcess(b *testing.B) {
+	tpdo := newFullTPDO(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tpdo.sendRequest = true
+		err := tpdo.Process(1000, nil, true, false)
+		assert.Nil(b, err)
+	}
+	b.StopTimer()
+	assert.EqualValues(b, b.N, tpdo.Stats().Frames)
+}
+
+func BenchmarkRPDOProcess(b *testing.B) {
+	rpdo := newFullRPDO(b)
+	frame := canopen.NewFrame(0x201, 0, MaxPdoLength)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		rpdo.Handle(frame)
+		rpdo.Process(1000, nil, true, false)
+	}
+	b.StopTimer()
+	assert.EqualValues(b, b.N, rpdo.Stats().Frames)
+}
+
+// Write a mapping, returns the error of the first refused write
+func writeMapping(odict *od.ObjectDictionary, mappingIndex uint16, params ...uint32) error {
+	mapping := odict.Index(mappingIndex)
+	err := mapping.PutUint8(0, 0, false)
+	if err != nil {
+		return err
+	}
+	for i, param := range params {
+		err = mapping.PutUint32(uint8(i+1), param, false)
+		if err != nil {
+			return err
+		}
+	}
+	return mapping.PutUint8(0, uint8(len(params)), false)
+}
+
+func TestPDOBitMapping(t *testing.T) {
+	tpdo, rpdo := newFullTPDO(t), newFullRPDO(t)
+	odictTx, odictRx := tpdo.pdo.od, rpdo.pdo.od
+	assert.Nil(t, odictTx.Index(0x1800).PutUint32(1, 0x80000181, false))
+	assert.Nil(t, odictRx.Index(0x1400).PutUint32(1, 0x80000201, false))
+
+	t.Run("64 bits", func(t *testing.T) {
+		assert.Nil(t, writeMapping(odictTx, 0x1A00, 0x201B0040))
+		assert.EqualValues(t, 8, tpdo.pdo.dataLength)
+		assert.Nil(t, odictTx.Index(0x201B).PutUint64(0, 0x0102030405060708, true))
+		assert.Nil(t, tpdo.send())
+		assert.Equal(t, [8]byte{8, 7, 6, 5, 4, 3, 2, 1}, tpdo.txBuffer.Data)
+		assert.Equal(t, od.ErrMapLen, writeMapping(odictTx, 0x1A00, 0x201B0040, 0x20010001))
+	})
+
+	t.Run("bits", func(t *testing.T) {
+		// BOOLEAN (1 bit), UNSIGNED8 (4 bits), padding (3 bits), UNSIGNED16 (12 bits)
+		mapping := []uint32{0x20010001, 0x20050004, 0x00010003, 0x2006000C}
+		assert.Nil(t, writeMapping(odictTx, 0x1A00, mapping...))
+		assert.EqualValues(t, 3, tpdo.pdo.dataLength)
+		assert.Nil(t, odictTx.Index(0x2001).PutUint8(0, 1, true))
+		assert.Nil(t, odictTx.Index(0x2005).PutUint8(0, 0xF5, true))
+		assert.Nil(t, odictTx.Index(0x2006).PutUint16(0, 0xF123, true))
+		tpdo.txBuffer.Data = [8]byte{0xFF, 0xFF, 0xFF}
+		assert.Nil(t, tpdo.send())
+		assert.Equal(t, [8]byte{0x0B, 0x23, 0x01}, tpdo.txBuffer.Data)
+
+		assert.Nil(t, writeMapping(odictRx, 0x1600, mapping...))
+		assert.EqualValues(t, 3, rpdo.pdo.dataLength)
+		assert.Nil(t, odictRx.Index(0x1400).PutUint32(1, 0x201, false))
+		frame := canopen.NewFrame(0x201, 0, 3)
+		frame.Data = [8]byte{0xF7, 0x23, 0xF1}
+		rpdo.Handle(frame)
+		rpdo.Process(1000, nil, true, false)
+		boolean, _ := odictRx.Index(0x2001).Uint8(0)
+		unsigned8, _ := odictRx.Index(0x2005).Uint8(0)
+		unsigned16, _ := odictRx.Index(0x2006).Uint16(0)
+		assert.EqualValues(t, 1, boolean)
+		assert.EqualValues(t, 0xB, unsigned8)
+		assert.EqualValues(t, 0x123, unsigned16)
+	})
+
+	t.Run("not representable", func(t *testing.T) {
+		assert.Equal(t, od.ErrNoMap, writeMapping(odictTx, 0x1A00, 0x20080004)) // REAL32
+		assert.Equal(t, od.ErrNoMap, writeMapping(odictTx, 0x1A00, 0x20090004)) // VISIBLE_STRING
+		assert.Equal(t, od.ErrNoMap, writeMapping(odictTx, 0x1A00, 0x00080004)) // REAL32 dummy
+		assert.Equal(t, od.ErrNoMap, writeMapping(odictTx, 0x1A00, 0x20010009)) // Longer than BOOLEAN
+		assert.Equal(t, od.ErrMapLen, writeMapping(odictTx, 0x1A00, 0x00070048))
+		assert.Nil(t, writeMapping(odictTx, 0x1A00, 0x20070020, 0x20070020))
+		assert.Equal(t, od.ErrMapLen, writeMapping(odictTx, 0x1A00, 0x20070020, 0x20070020, 0x20010001))
+	})
+}
+
+func TestTPDORequestConcurrent(t *testing.T) {
+	odict := od.Default()
+	tpdo, err := NewTPDO(canopen.NewBusManager(&nopBus{}), nil, odict, &emergency.EMCY{}, nil, odict.Index(0x1800), odict.Index(0x1A00), 0x181)
+	assert.Nil(t, err)
+	mapFullPDO(t, odict, 0x1A00)
+	assert.Nil(t, odict.Index(0x1800).PutUint8(2, TransmissionTypeSyncEventLo, false))
+	assert.Nil(t, odict.Index(0x1800).PutUint32(1, 0x181, false))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 1000 {
+			odict.Index(0x2002).RequestTPDO(0)
+			odict.Index(0x2004).RequestTPDO(0)
+		}
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		assert.Nil(t, tpdo.Process(1000, nil, true, false))
+	}
+	assert.NotZero(t, tpdo.Stats().Frames)
+}
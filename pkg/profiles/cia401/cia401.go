@@ -0,0 +1,295 @@
+// Package cia401 provides helpers for generic I/O modules implementing the
+// CiA 401 device profile : 8-bit digital inputs & outputs, and 16-bit
+// analog inputs & outputs. Process data can be accessed over SDO, or over
+// PDO using the OD of a node updated by PDOs.
+package cia401
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/samsamfire/gocanopen/pkg/node"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// CiA 401 objects, all of them are arrays where subindex 1 is the first
+// group of 8 digital lines or the first analog channel
+const (
+	EntryReadInput8          uint16 = 0x6000
+	EntryPolarityInput8      uint16 = 0x6002
+	EntryFilterInput8        uint16 = 0x6003
+	EntryWriteOutput8        uint16 = 0x6200
+	EntryPolarityOutput8     uint16 = 0x6202
+	EntryFilterOutput8       uint16 = 0x6208
+	EntryReadAnalogInput16   uint16 = 0x6401
+	EntryWriteAnalogOutput16 uint16 = 0x6411
+)
+
+var ErrLineRange = errors.New("digital line out of range")
+
+// Transport used for accessing process data, i.e. digital & analog values.
+// Configuration objects (polarity, filters) are always accessed over SDO.
+type Transport uint8
+
+const (
+	// Every access is an SDO transfer
+	TransportSDO Transport = 0
+	// Inputs are read from the OD of the node, updated by RPDOs. Outputs are written
+	// to the OD of the node and sent by event driven TPDOs. For a [node.RemoteNode], PDOs
+	// should be mapped by the I/O module and started with [node.RemoteNode.StartPDOs]
+	// after creating the [IO].
+	TransportPDO Transport = 1
+)
+
+// A Node gives access to the OD of an I/O module,
+// it is implemented by [node.LocalNode] and [node.RemoteNode]
+type Node interface {
+	node.ObjectAccessor
+	GetOD() *od.ObjectDictionary
+}
+
+// IO gives access to a CiA 401 I/O module
+type IO struct {
+	node      Node
+	transport Transport
+	// Serializes read-modify-write accesses of outputs
+	mu sync.Mutex
+}
+
+// Create a new [IO] for accessing an I/O module
+func New(n Node, transport Transport) *IO {
+	if transport == TransportPDO {
+		// TPDOs only detect changes of entries with an extension
+		for _, index := range []uint16{EntryWriteOutput8, EntryWriteAnalogOutput16} {
+			entry := n.GetOD().Index(index)
+			if entry != nil && entry.Extension() == nil {
+				entry.AddExtension(nil, od.ReadEntryDefault, od.WriteEntryDefault)
+			}
+		}
+	}
+	return &IO{node: n, transport: transport}
+}
+
+// Group (subindex) & bit of a digital line, lines start at 0
+func lineBit(line uint16) (uint8, uint8, error) {
+	if line >= 254*8 {
+		return 0, 0, ErrLineRange
+	}
+	return uint8(line/8) + 1, uint8(line % 8), nil
+}
+
+// Read process data over the configured transport
+func (io *IO) read(index uint16, subIndex uint8, data []byte) error {
+	if io.transport == TransportPDO {
+		return io.node.GetOD().Index(index).ReadExactly(subIndex, data, false)
+	}
+	n, err := io.node.ReadRaw(index, subIndex, data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return od.ErrTypeMismatch
+	}
+	return nil
+}
+
+// Write process data over the configured transport
+func (io *IO) write(index uint16, subIndex uint8, data []byte) error {
+	if io.transport == TransportSDO {
+		return io.node.WriteRaw(index, subIndex, data)
+	}
+	entry := io.node.GetOD().Index(index)
+	err := entry.WriteExactly(subIndex, data, false)
+	if err != nil {
+		return err
+	}
+	// Request transmission of event driven TPDOs
+	entry.RequestTPDO(subIndex)
+	return nil
+}
+
+func (io *IO) readUint8(index uint16, subIndex uint8) (uint8, error) {
+	data := make([]byte, 1)
+	err := io.read(index, subIndex, data)
+	return data[0], err
+}
+
+// ReadDigitalInputs reads a group of 8 digital inputs (0x6000), groups start at 1
+func (io *IO) ReadDigitalInputs(group uint8) (uint8, error) {
+	return io.readUint8(EntryReadInput8, group)
+}
+
+// DigitalInput reads a single digital input, lines start at 0
+func (io *IO) DigitalInput(line uint16) (bool, error) {
+	group, bit, err := lineBit(line)
+	if err != nil {
+		return false, err
+	}
+	value, err := io.ReadDigitalInputs(group)
+	return value&(1<<bit) != 0, err
+}
+
+// ReadDigitalOutputs reads a group of 8 digital outputs (0x6200), groups start at 1
+func (io *IO) ReadDigitalOutputs(group uint8) (uint8, error) {
+	return io.readUint8(EntryWriteOutput8, group)
+}
+
+// WriteDigitalOutputs writes a group of 8 digital outputs (0x6200), groups start at 1
+func (io *IO) WriteDigitalOutputs(group uint8, value uint8) error {
+	io.mu.Lock()
+	defer io.mu.Unlock()
+	return io.write(EntryWriteOutput8, group, []byte{value})
+}
+
+// SetDigitalOutput sets a single digital output, lines start at 0.
+// This does a read-modify-write of the group of the line.
+func (io *IO) SetDigitalOutput(line uint16, on bool) error {
+	group, bit, err := lineBit(line)
+	if err != nil {
+		return err
+	}
+	io.mu.Lock()
+	defer io.mu.Unlock()
+	value, err := io.readUint8(EntryWriteOutput8, group)
+	if err != nil {
+		return err
+	}
+	if on {
+		value |= 1 << bit
+	} else {
+		value &^= 1 << bit
+	}
+	return io.write(EntryWriteOutput8, group, []byte{value})
+}
+
+// ReadAnalogInput reads a 16-bit analog input (0x6401), channels start at 1
+func (io *IO) ReadAnalogInput(channel uint8) (int16, error) {
+	data := make([]byte, 2)
+	err := io.read(EntryReadAnalogInput16, channel, data)
+	return int16(binary.LittleEndian.Uint16(data)), err
+}
+
+// ReadAnalogOutput reads a 16-bit analog output (0x6411), channels start at 1
+func (io *IO) ReadAnalogOutput(channel uint8) (int16, error) {
+	data := make([]byte, 2)
+	err := io.read(EntryWriteAnalogOutput16, channel, data)
+	return int16(binary.LittleEndian.Uint16(data)), err
+}
+
+// WriteAnalogOutput writes a 16-bit analog output (0x6411), channels start at 1
+func (io *IO) WriteAnalogOutput(channel uint8, value int16) error {
+	data := make([]byte, 2)
+	binary.LittleEndian.PutUint16(data, uint16(value))
+	return io.write(EntryWriteAnalogOutput16, channel, data)
+}
+
+// InputPolarity reads the polarity mask of a group of digital inputs (0x6002),
+// inputs are inverted where bits are set
+func (io *IO) InputPolarity(group uint8) (uint8, error) {
+	return node.ReadValue[uint8](io.node, EntryPolarityInput8, group)
+}
+
+// SetInputPolarity writes the polarity mask of a group of digital inputs (0x6002)
+func (io *IO) SetInputPolarity(group uint8, mask uint8) error {
+	return node.WriteValue(io.node, EntryPolarityInput8, group, mask)
+}
+
+// InputFilter reads the filter mask of a group of digital inputs (0x6003),
+// inputs are filtered where bits are set
+func (io *IO) InputFilter(group uint8) (uint8, error) {
+	return node.ReadValue[uint8](io.node, EntryFilterInput8, group)
+}
+
+// SetInputFilter writes the filter mask of a group of digital inputs (0x6003)
+func (io *IO) SetInputFilter(group uint8, mask uint8) error {
+	return node.WriteValue(io.node, EntryFilterInput8, group, mask)
+}
+
+// OutputPolarity reads the polarity mask of a group of digital outputs (0x6202),
+// outputs are inverted where bits are set
+func (io *IO) OutputPolarity(group uint8) (uint8, error) {
+	return node.ReadValue[uint8](io.node, EntryPolarityOutput8, group)
+}
+
+// SetOutputPolarity writes the polarity mask of a group of digital outputs (0x6202)
+func (io *IO) SetOutputPolarity(group uint8, mask uint8) error {
+	return node.WriteValue(io.node, EntryPolarityOutput8, group, mask)
+}
+
+// OutputFilter reads the filter mask of a group of digital outputs (0x6208),
+// only outputs where bits are set are changed when writing outputs
+func (io *IO) OutputFilter(group uint8) (uint8, error) {
+	return node.ReadValue[uint8](io.node, EntryFilterOutput8, group)
+}
+
+// SetOutputFilter writes the filter mask of a group of digital outputs (0x6208)
+func (io *IO) SetOutputFilter(group uint8, mask uint8) error {
+	return node.WriteValue(io.node, EntryFilterOutput8, group, mask)
+}
+
+// Objects of an I/O module, see [AddObjects]
+type Objects struct {
+	DigitalInputs  uint8 // Number of groups of 8 digital inputs
+	DigitalOutputs uint8 // Number of groups of 8 digital outputs
+	AnalogInputs   uint8 // Number of 16-bit analog inputs
+	AnalogOutputs  uint8 // Number of 16-bit analog outputs
+}
+
+// Add an ARRAY of nbEntries values to the OD, nothing is added if nbEntries is 0
+func addArray(odict *od.ObjectDictionary, index uint16, name string, datatype uint8, attribute uint8, value string, nbEntries uint8) error {
+	if nbEntries == 0 {
+		return nil
+	}
+	if nbEntries > 254 {
+		return od.ErrDevIncompat
+	}
+	array := od.NewArray(nbEntries + 1)
+	_, err := array.AddSubObject(0, "Highest sub-index supported", od.UNSIGNED8, od.AttributeSdoR, fmt.Sprintf("0x%x", nbEntries))
+	if err != nil {
+		return err
+	}
+	for i := uint8(1); i <= nbEntries; i++ {
+		_, err = array.AddSubObject(i, fmt.Sprintf("%v %d", name, i), datatype, attribute, value)
+		if err != nil {
+			return err
+		}
+	}
+	odict.AddVariableList(index, name, array)
+	return nil
+}
+
+// AddObjects adds the CiA 401 objects of an I/O module to an OD, e.g. for
+// implementing an I/O module with a [node.LocalNode]. Process data objects are
+// PDO mappable. Applying polarity & filters is up to the application.
+func AddObjects(odict *od.ObjectDictionary, objects Objects) error {
+	arrays := []struct {
+		index     uint16
+		name      string
+		datatype  uint8
+		attribute uint8
+		nbEntries uint8
+	}{
+		{EntryReadInput8, "Read input 8-bit", od.UNSIGNED8, od.AttributeSdoR | od.AttributeTrpdo, objects.DigitalInputs},
+		{EntryPolarityInput8, "Polarity input 8-bit", od.UNSIGNED8, od.AttributeSdoRw, objects.DigitalInputs},
+		{EntryFilterInput8, "Filter constant input 8-bit", od.UNSIGNED8, od.AttributeSdoRw, objects.DigitalInputs},
+		{EntryWriteOutput8, "Write output 8-bit", od.UNSIGNED8, od.AttributeSdoRw | od.AttributeTrpdo, objects.DigitalOutputs},
+		{EntryPolarityOutput8, "Change polarity output 8-bit", od.UNSIGNED8, od.AttributeSdoRw, objects.DigitalOutputs},
+		{EntryFilterOutput8, "Filter mask output 8-bit", od.UNSIGNED8, od.AttributeSdoRw, objects.DigitalOutputs},
+		{EntryReadAnalogInput16, "Read analog input 16-bit", od.INTEGER16, od.AttributeSdoR | od.AttributeTrpdo, objects.AnalogInputs},
+		{EntryWriteAnalogOutput16, "Write analog output 16-bit", od.INTEGER16, od.AttributeSdoRw | od.AttributeTrpdo, objects.AnalogOutputs},
+	}
+	for _, array := range arrays {
+		value := "0x0"
+		if array.index == EntryFilterOutput8 {
+			// All outputs are enabled by default
+			value = "0xFF"
+		}
+		err := addArray(odict, array.index, array.name, array.datatype, array.attribute, value, array.nbEntries)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package cia401
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/can"
+	"github.com/samsamfire/gocanopen/pkg/config"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/node"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+const moduleId = 0x10
+
+func newOD(t *testing.T) *od.ObjectDictionary {
+	odict := od.Default()
+	assert.Nil(t, AddObjects(odict, Objects{DigitalInputs: 2, DigitalOutputs: 2, AnalogInputs: 1, AnalogOutputs: 1}))
+	return odict
+}
+
+// I/O module on a loopback channel & remote node on a master network
+func newModule(t *testing.T, channel string) (*network.Network, *node.LocalNode, *node.RemoteNode) {
+	moduleNetwork := network.NewNetwork(can.NewLoopbackBus(channel))
+	assert.Nil(t, moduleNetwork.Connect())
+	t.Cleanup(moduleNetwork.Disconnect)
+	module, err := moduleNetwork.CreateLocalNode(moduleId, newOD(t))
+	assert.Nil(t, err)
+	master := network.NewNetwork(can.NewLoopbackBus(channel))
+	assert.Nil(t, master.Connect())
+	t.Cleanup(master.Disconnect)
+	remote, err := master.AddRemoteNode(moduleId, newOD(t))
+	assert.Nil(t, err)
+	return &master, module, remote
+}
+
+func TestAddObjects(t *testing.T) {
+	odict := newOD(t)
+	count, err := odict.Index(EntryReadInput8).Uint8(0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, count)
+	mask, err := odict.Index(EntryFilterOutput8).Uint8(2)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0xFF, mask)
+	assert.Nil(t, odict.Index(0x6410))
+	assert.Equal(t, od.ErrDevIncompat, AddObjects(od.NewOD(), Objects{DigitalInputs: 255}))
+}
+
+func TestSDO(t *testing.T) {
+	_, module, remote := newModule(t, "cia401-sdo")
+	io := New(remote, TransportSDO)
+	inputs := module.GetOD().Index(EntryReadInput8)
+	assert.Nil(t, inputs.PutUint8(2, 0b1010_0101, true))
+
+	value, err := io.ReadDigitalInputs(2)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0b1010_0101, value)
+	on, err := io.DigitalInput(8)
+	assert.Nil(t, err)
+	assert.True(t, on)
+	on, err = io.DigitalInput(9)
+	assert.Nil(t, err)
+	assert.False(t, on)
+	_, err = io.DigitalInput(2040)
+	assert.Equal(t, ErrLineRange, err)
+
+	outputs := module.GetOD().Index(EntryWriteOutput8)
+	assert.Nil(t, io.WriteDigitalOutputs(1, 0x81))
+	assert.Nil(t, io.SetDigitalOutput(3, true))
+	assert.Nil(t, io.SetDigitalOutput(7, false))
+	value, err = outputs.Uint8(1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x09, value)
+	value, err = io.ReadDigitalOutputs(1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0x09, value)
+
+	assert.Nil(t, module.GetOD().Index(EntryReadAnalogInput16).PutUint16(1, uint16(0xFFF6), true))
+	analog, err := io.ReadAnalogInput(1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, -10, analog)
+	assert.Nil(t, io.WriteAnalogOutput(1, 1234))
+	analog, err = io.ReadAnalogOutput(1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1234, analog)
+
+	t.Run("configuration", func(t *testing.T) {
+		assert.Nil(t, io.SetInputPolarity(1, 0x0F))
+		assert.Nil(t, io.SetInputFilter(2, 0x03))
+		assert.Nil(t, io.SetOutputPolarity(1, 0xF0))
+		assert.Nil(t, io.SetOutputFilter(2, 0x3C))
+		mask, err := io.InputPolarity(1)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x0F, mask)
+		mask, err = io.InputFilter(2)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x03, mask)
+		mask, err = io.OutputPolarity(1)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0xF0, mask)
+		mask, err = io.OutputFilter(2)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0x3C, mask)
+	})
+}
+
+func TestPDO(t *testing.T) {
+	master, module, remote := newModule(t, "cia401-pdo")
+	conf := master.Configurator(moduleId)
+	// Inputs are sent by module TPDO 1, outputs received by module RPDO 1
+	assert.Nil(t, conf.DisablePDO(257))
+	assert.Nil(t, conf.WriteConfigurationPDO(257, config.PDOConfigurationParameter{
+		CanId: 0x180 + moduleId, TransmissionType: 255, EventTimer: 10,
+		Mappings: []config.PDOMappingParameter{{Index: EntryReadInput8, Subindex: 1, LengthBits: 8}, {Index: EntryReadAnalogInput16, Subindex: 1, LengthBits: 16}},
+	}))
+	assert.Nil(t, conf.EnablePDO(257))
+	assert.Nil(t, conf.DisablePDO(1))
+	assert.Nil(t, conf.WriteConfigurationPDO(1, config.PDOConfigurationParameter{
+		CanId: 0x200 + moduleId, TransmissionType: 255,
+		Mappings: []config.PDOMappingParameter{{Index: EntryWriteOutput8, Subindex: 1, LengthBits: 8}, {Index: EntryWriteAnalogOutput16, Subindex: 1, LengthBits: 16}},
+	}))
+	assert.Nil(t, conf.EnablePDO(1))
+	io := New(remote, TransportPDO)
+	assert.Nil(t, remote.StartPDOs(false))
+
+	assert.Nil(t, module.GetOD().Index(EntryReadInput8).PutUint8(1, 0x42, true))
+	assert.Nil(t, module.GetOD().Index(EntryReadAnalogInput16).PutUint16(1, 500, true))
+	assert.Eventually(t, func() bool {
+		value, err := io.ReadDigitalInputs(1)
+		analog, errAnalog := io.ReadAnalogInput(1)
+		return err == nil && errAnalog == nil && value == 0x42 && analog == 500
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Nil(t, io.SetDigitalOutput(4, true))
+	assert.Nil(t, io.WriteAnalogOutput(1, -300))
+	outputs := module.GetOD().Index(EntryWriteOutput8)
+	analogOutputs := module.GetOD().Index(EntryWriteAnalogOutput16)
+	assert.Eventually(t, func() bool {
+		value, _ := outputs.Uint8(1)
+		analog, _ := analogOutputs.Uint16(1)
+		return value == 0x10 && int16(analog) == -300
+	}, time.Second, 10*time.Millisecond)
+
+	// Not mapped in PDO, only in local OD
+	_, err := io.ReadDigitalInputs(3)
+	assert.Equal(t, od.ErrSubNotExist, err)
+}
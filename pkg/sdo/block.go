@@ -0,0 +1,169 @@
+package sdo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrBlockUnsupported = errors.New("block transfer is not supported by server")
+
+// Selects when block transfers are used
+type BlockMode uint8
+
+const (
+	// Block transfer is used when the transfer size is above the threshold
+	// (or unknown) and the server is not known to lack block support.
+	// If the server rejects the block initiate, the transfer transparently
+	// falls back to expedited / segmented transfer.
+	BlockAuto BlockMode = iota
+	// Block transfer is never used
+	BlockNever
+	// Block transfer is always used, regardless of the size.
+	// Fails with [ErrBlockUnsupported] if server is known to lack block support.
+	BlockAlways
+)
+
+func (mode BlockMode) String() string {
+	switch mode {
+	case BlockAuto:
+		return "auto"
+	case BlockNever:
+		return "never"
+	case BlockAlways:
+		return "always"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(mode))
+	}
+}
+
+// Per transfer options, see e.g. [SDOClient.NewRawReaderWith]
+type TransferOptions struct {
+	Block BlockMode
+	// Block transfer is used in [BlockAuto] mode only if transfer
+	// size is strictly above threshold (in bytes).
+	// If 0, the client threshold is used, see [SDOClient.SetBlockThreshold]
+	BlockThreshold uint32
+	// Number of segments per sub-block requested by the client in block
+	// uploads (1 - 127), it is further limited by the free buffer space.
+	// If 0, the client maximum is used, see [SDOClient.SetBlockMaxSize].
+	// Block size of downloads is chosen by the server.
+	BlockSize uint8
+	// Protocol switch threshold sent to the server in block uploads : the server
+	// may switch to a normal transfer if the object size is at most this value.
+	// If 0, the block threshold is used (capped to 255), or 0 with [BlockAlways].
+	ProtocolSwitchThreshold uint8
+	// Called from the goroutine doing the transfer, each time
+	// more data has been transferred. It should not block.
+	Progress func(progress Progress)
+	// Number of times [SDOClient.UploadTo] and [SDOClient.DownloadFrom]
+	// resume a transfer aborted with a resumable error, see [IsResumable].
+	// Progress is reported again from 0 for each attempt.
+	Resume int
+}
+
+// Legacy blockEnabled flag to options
+func blockOptions(blockEnabled bool) TransferOptions {
+	if blockEnabled {
+		return TransferOptions{Block: BlockAuto}
+	}
+	return TransferOptions{Block: BlockNever}
+}
+
+// Returns true if server abort code means that block transfer is not implemented
+func isBlockUnsupportedAbort(abortCode Abort) bool {
+	return abortCode == AbortCmd || abortCode == AbortUnsupportedAccess
+}
+
+// Resolve threshold from options or client defaults
+func (c *SDOClient) threshold(opts TransferOptions) uint32 {
+	if opts.BlockThreshold != 0 {
+		return opts.BlockThreshold
+	}
+	return c.blockThreshold
+}
+
+// Resolve protocol switch threshold sent in block uploads
+func (c *SDOClient) protocolSwitchThreshold(opts TransferOptions) uint8 {
+	if opts.ProtocolSwitchThreshold != 0 {
+		return opts.ProtocolSwitchThreshold
+	}
+	if opts.Block == BlockAlways {
+		return 0
+	}
+	return uint8(min(c.threshold(opts), 0xFF))
+}
+
+// Decide whether block transfer should be attempted.
+// size 0 means size is unknown
+func (c *SDOClient) useBlock(opts TransferOptions, size uint32) (bool, error) {
+	supported, known := c.blockSupport[c.nodeIdServer]
+	switch opts.Block {
+	case BlockNever:
+		return false, nil
+	case BlockAlways:
+		if known && !supported {
+			return false, fmt.Errorf("%w : x%x", ErrBlockUnsupported, c.nodeIdServer)
+		}
+		return true, nil
+	case BlockAuto:
+		if known && !supported {
+			return false, nil
+		}
+		return size == 0 || size > c.threshold(opts), nil
+	default:
+		return false, ErrInvalidArgs
+	}
+}
+
+// Record block support of current server. Returns true if the on-going
+// transfer can fall back to a normal transfer.
+func (c *SDOClient) blockRejected(abortCode Abort) bool {
+	if !isBlockUnsupportedAbort(abortCode) {
+		return false
+	}
+	c.blockSupport[c.nodeIdServer] = false
+	c.logger.Info("server does not support block transfers",
+		"server", fmt.Sprintf("x%x", c.nodeIdServer),
+		"code", uint32(abortCode),
+	)
+	if c.blockMode != BlockAuto {
+		return false
+	}
+	c.addRetry()
+	return true
+}
+
+// Set default threshold in bytes above which block transfer is used
+// in [BlockAuto] mode. For uploads, this is also sent to the server as
+// the protocol switch threshold (capped to 255) so that it can switch
+// to a normal transfer for small objects.
+// Default is [ClientProtocolSwitchThreshold].
+func (c *SDOClient) SetBlockThreshold(threshold uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blockThreshold = threshold
+}
+
+// Get default block transfer threshold
+func (c *SDOClient) BlockThreshold() uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.blockThreshold
+}
+
+// Returns whether block transfer is supported by a given server.
+// known is false if no block transfer was attempted yet.
+func (c *SDOClient) BlockSupported(nodeId uint8) (supported bool, known bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	supported, known = c.blockSupport[nodeId]
+	return supported, known
+}
+
+// Forget block support information of a given server,
+// e.g. after a firmware update
+func (c *SDOClient) ResetBlockSupport(nodeId uint8) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.blockSupport, nodeId)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	canopen "github.com/samsamfire/gocanopen"
 	"github.com/samsamfire/gocanopen/internal/crc"
@@ -28,7 +29,7 @@ type SDOClient struct {
 	mu                         sync.Mutex
 	od                         *od.ObjectDictionary
 	streamer                   *od.Streamer
-	rw                         *sdoRawReadWriter
+	transferSlot               chan struct{} // Held by the on-going [Transfer]
 	localBuffer                []byte
 	nodeId                     uint8
 	txBuffer                   canopen.Frame
@@ -43,6 +44,7 @@ type SDOClient struct {
 	sizeTransferred            uint32
 	state                      internalState
 	processingPeriodUs         int
+	stepper                    func(d time.Duration) // See [SDOClient.SetStepper]
 	fifo                       *fifo.Fifo
 	rxNew                      bool
 	response                   SDOMessage
@@ -58,6 +60,16 @@ type SDOClient struct {
 	blockCRCEnabled            bool
 	blockDataUploadLast        [BlockSeqSize]byte
 	blockCRC                   crc.CRC16
+	blockThreshold             uint32
+	blockMode                  BlockMode
+	blockThresholdPst          uint8
+	blockSizeRequested         uint8          // Max block size requested by client in block uploads
+	blockSupport               map[uint8]bool // Known block support per server
+	channelClientToServer      uint32         // See [SDOClient.SetServerChannel]
+	channelServerToClient      uint32
+	statsMu                    sync.Mutex
+	stats                      Stats
+	transferHook               func(result TransferResult)
 }
 
 // Handle [SDOClient] related RX CAN frames
@@ -153,10 +165,15 @@ func (c *SDOClient) setupServer(cobIdClientToServer uint32, cobIdServerToClient
 }
 
 // Start a new download sequence
-func (c *SDOClient) downloadSetup(index uint16, subindex uint8, sizeIndicated uint32, blockEnabled bool) error {
+func (c *SDOClient) downloadSetup(index uint16, subindex uint8, sizeIndicated uint32, opts TransferOptions) error {
 	if !c.valid {
 		return ErrInvalidArgs
 	}
+	block, err := c.useBlock(opts, sizeIndicated)
+	if err != nil {
+		return err
+	}
+	c.blockMode = opts.Block
 	c.index = index
 	c.subindex = subindex
 	c.sizeIndicated = sizeIndicated
@@ -171,7 +188,7 @@ func (c *SDOClient) downloadSetup(index uint16, subindex uint8, sizeIndicated ui
 		c.streamer.SetWriter(nil)
 		// Local transfer
 		c.state = stateDownloadLocalTransfer
-	case blockEnabled && (sizeIndicated == 0 || sizeIndicated > ClientProtocolSwitchThreshold):
+	case block:
 		// Block download
 		c.state = stateDownloadBlkInitiateReq
 	default:
@@ -211,7 +228,13 @@ func (c *SDOClient) downloadMain(
 		}
 	} else if c.rxNew {
 		response := c.response
-		if response.IsAbort() {
+		if response.IsAbort() && c.state == stateDownloadBlkInitiateRsp && c.blockRejected(response.GetAbortCode()) {
+			// Block not supported, retry with a normal transfer
+			c.state = stateDownloadInitiateReq
+			c.timeoutTimer = 0
+			timeDifferenceUs = 0
+			c.rxNew = false
+		} else if response.IsAbort() {
 			abortCode = response.GetAbortCode()
 			c.logger.Info("[RX] server abort",
 				"server", fmt.Sprintf("x%x", c.nodeIdServer),
@@ -297,6 +320,7 @@ func (c *SDOClient) downloadMain(
 					c.state = stateAbort
 					break
 				}
+				c.blockSupport[c.nodeIdServer] = true
 				c.blockCRC = crc.CRC16(0)
 				c.blockSize = response.GetBlockSize()
 				if c.blockSize < 1 || c.blockSize > BlockMaxSize {
@@ -317,6 +341,7 @@ func (c *SDOClient) downloadMain(
 
 				if response.GetNumberOfSegments() < c.blockSequenceNb {
 					c.logger.Error("not all segments transferred successfully")
+					c.addRetry()
 					c.fifo.AltBegin(int(response.raw[1]) * BlockSeqSize)
 					c.finished = false
 
@@ -329,7 +354,21 @@ func (c *SDOClient) downloadMain(
 				if c.finished {
 					c.state = stateDownloadBlkEndReq
 				} else {
-					c.blockSize = response.raw[2]
+					// Server may change block size for each sub-block
+					blockSize := response.raw[2]
+					if blockSize < 1 || blockSize > BlockMaxSize {
+						abortCode = AbortBlockSize
+						c.state = stateAbort
+						break
+					}
+					if blockSize != c.blockSize {
+						c.logger.Debug("[RX] download block size changed",
+							"server", fmt.Sprintf("x%x", c.nodeIdServer),
+							"blksizePrev", c.blockSize,
+							"blksize", blockSize,
+						)
+					}
+					c.blockSize = blockSize
 					c.blockSequenceNb = 0
 					c.fifo.AltBegin(0)
 					c.state = stateDownloadBlkSubblockReq
@@ -670,24 +709,49 @@ func (c *SDOClient) abort(abortCode Abort) {
 	_ = c.Send(c.txBuffer)
 }
 
+// Cancel any on-going transfer, sending an abort to the server if needed
+func (c *SDOClient) cancel(abortCode Abort) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == stateIdle {
+		return
+	}
+	if c.state != stateUploadLocalTransfer && c.state != stateDownloadLocalTransfer {
+		c.abort(abortCode)
+	}
+	c.state = stateIdle
+}
+
 /////////////////////////////////////
 ////////////SDO UPLOAD///////////////
 /////////////////////////////////////
 
-func (c *SDOClient) uploadSetup(index uint16, subindex uint8, blockEnabled bool) error {
+func (c *SDOClient) uploadSetup(index uint16, subindex uint8, size uint32, opts TransferOptions) error {
 	if !c.valid {
 		return ErrInvalidArgs
 	}
+	block, err := c.useBlock(opts, size)
+	if err != nil {
+		return err
+	}
+	c.blockMode = opts.Block
+	// Server switches to normal transfer if size is below threshold
+	c.blockThresholdPst = c.protocolSwitchThreshold(opts)
+	c.blockSizeRequested = uint8(c.blockMaxSize)
+	if opts.BlockSize > 0 {
+		c.blockSizeRequested = min(opts.BlockSize, BlockMaxSize)
+	}
 	c.index = index
 	c.subindex = subindex
 	c.sizeIndicated = 0
 	c.sizeTransferred = 0
 	c.finished = false
+	c.timeoutTimer = 0
 	c.fifo.Reset()
 	if c.od != nil && c.nodeIdServer == c.nodeId {
 		c.streamer.SetReader(nil)
 		c.state = stateUploadLocalTransfer
-	} else if blockEnabled {
+	} else if block {
 		c.state = stateUploadBlkInitiateReq
 	} else {
 		c.state = stateUploadInitiateReq
@@ -808,7 +872,10 @@ func (c *SDOClient) upload(
 		}
 	} else if c.rxNew {
 		response := c.response
-		if response.IsAbort() {
+		if response.IsAbort() && c.state == stateUploadBlkInitiateRsp && c.blockRejected(response.GetAbortCode()) {
+			// Block not supported, retry with a normal transfer
+			c.state = stateUploadInitiateReq
+		} else if response.IsAbort() {
 			abortCode = response.GetAbortCode()
 			c.logger.Info("[RX] server abort",
 				"server", fmt.Sprintf("x%x", c.nodeIdServer),
@@ -929,6 +996,7 @@ func (c *SDOClient) upload(
 				}
 				// Block is supported
 				if (response.raw[0] & 0xF9) == 0xC0 {
+					c.blockSupport[c.nodeIdServer] = true
 					c.blockCRCEnabled = response.IsCRCEnabled()
 					if (response.raw[0] & 0x02) != 0 {
 						c.sizeIndicated = uint32(response.GetBlockSize())
@@ -1101,8 +1169,8 @@ func (c *SDOClient) upload(
 			c.txBuffer.Data[3] = c.subindex
 			// Calculate number of block segments from free space
 			count := c.fifo.GetSpace() / BlockSeqSize
-			if count >= BlockMaxSize {
-				count = BlockMaxSize
+			if count >= int(c.blockSizeRequested) {
+				count = int(c.blockSizeRequested)
 			} else if count == 0 {
 				abortCode = AbortOutOfMem
 				c.state = stateAbort
@@ -1110,7 +1178,7 @@ func (c *SDOClient) upload(
 			}
 			c.blockSize = uint8(count)
 			c.txBuffer.Data[4] = c.blockSize
-			c.txBuffer.Data[5] = ClientProtocolSwitchThreshold
+			c.txBuffer.Data[5] = c.blockThresholdPst
 			c.timeoutTimer = 0
 			_ = c.Send(c.txBuffer)
 			c.state = stateUploadBlkInitiateRsp
@@ -1148,8 +1216,8 @@ func (c *SDOClient) upload(
 				}
 				// Calculate number of block segments from remaining space
 				count := c.fifo.GetSpace() / BlockSeqSize
-				if count >= BlockMaxSize {
-					count = BlockMaxSize
+				if count >= int(c.blockSizeRequested) {
+					count = int(c.blockSizeRequested)
 				} else if c.fifo.GetOccupied() > 0 {
 					ret = uploadDataFull
 					if transferShort {
@@ -1233,16 +1301,16 @@ func NewSDOClient(
 	c.od = odict
 	c.nodeId = nodeId
 	c.streamer = &od.Streamer{}
-	c.fifo = fifo.NewFifo(BlockMaxSize * BlockSeqSize)
+	// One byte is always kept free in fifo, so that a full sub-block fits
+	c.fifo = fifo.NewFifo(BlockMaxSize*BlockSeqSize + 1)
 	c.localBuffer = make([]byte, DefaultClientBufferSize+2)
 	c.SetTimeout(DefaultClientTimeout)
 	c.SetTimeoutBlockTransfer(DefaultClientTimeout)
 	c.SetBlockMaxSize(BlockMaxSize)
+	c.blockThreshold = ClientProtocolSwitchThreshold
+	c.blockSupport = make(map[uint8]bool)
 	c.SetProcessingPeriod(DefaultClientProcessPeriodUs)
-	rw := &sdoRawReadWriter{
-		client: c,
-	}
-	c.rw = rw
+	c.transferSlot = make(chan struct{}, 1)
 
 	var nodeIdServer uint8
 	var CobIdClientToServer, CobIdServerToClient uint32
@@ -1295,7 +1363,29 @@ func (c *SDOClient) SetProcessingPeriod(periodUs int) {
 	c.processingPeriodUs = periodUs
 }
 
-// Set maximum block size to use during block transfers
+// Drive blocking transfers from a simulation : instead of sleeping for the
+// processing period between two processing cycles, step is called with the
+// processing period and is expected to run the processing of the remote nodes
+// during that time. A nil step restores sleeping.
+func (c *SDOClient) SetStepper(step func(d time.Duration)) {
+	c.stepper = step
+}
+
+// SetServerChannel makes next transfers use the given COB-IDs instead of the default
+// SDO server channel of the node (0x600 + id / 0x580 + id), e.g. for an additional
+// server channel (0x1201 - 0x127F). 0 restores the default channel.
+func (c *SDOClient) SetServerChannel(cobIdClientToServer uint32, cobIdServerToClient uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cobIdClientToServer == 0 || cobIdServerToClient == 0 {
+		cobIdClientToServer, cobIdServerToClient = 0, 0
+	}
+	c.channelClientToServer = cobIdClientToServer
+	c.channelServerToClient = cobIdServerToClient
+}
+
+// Set maximum block size requested by the client in block uploads, it can be
+// overridden per transfer with [TransferOptions.BlockSize].
 // Some devices may not support big block sizes as it can use a lot of RAM.
 func (c *SDOClient) SetBlockMaxSize(size int) {
 	c.blockMaxSize = max(min(size, BlockMaxSize), BlockMinSize)
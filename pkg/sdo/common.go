@@ -11,6 +11,7 @@ import (
 
 var ErrWrongClientReturnValue = errors.New("wrong client return value")
 var ErrInvalidArgs = errors.New("error in arguments")
+var ErrTransferFinished = errors.New("sdo transfer is already finished")
 
 type internalState uint8
 
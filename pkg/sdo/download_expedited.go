@@ -53,6 +53,7 @@ func (s *SDOServer) rxDownloadInitiate(rx SDOMessage) error {
 	} else if sizeInOd > 0 && sizeInOd < 4 {
 		nbToWrite = int(sizeInOd)
 	}
+	nbData := nbToWrite
 
 	if s.streamer.HasAttribute(od.AttributeStr) &&
 		(sizeInOd == 0 || uint32(nbToWrite) < sizeInOd) {
@@ -72,7 +73,10 @@ func (s *SDOServer) rxDownloadInitiate(rx SDOMessage) error {
 			return AbortDataShort
 		}
 	}
-	_, err := s.streamer.Write(rx.raw[4 : 4+nbToWrite])
+	// Strings are null terminated, which can exceed the 4 bytes of data
+	data := make([]byte, nbToWrite)
+	copy(data, rx.raw[4:4+nbData])
+	_, err := s.streamer.Write(data)
 	if err != nil {
 		return ConvertOdToSdoAbort(err.(od.ODR))
 	}
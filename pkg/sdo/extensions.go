@@ -16,6 +16,9 @@ func writeEntry1201(stream *od.Stream, data []byte, countWritten *uint16) error
 	if !ok {
 		return od.ErrDevIncompat
 	}
+	// Channel can be reconfigured while server is running
+	server.procMu.Lock()
+	defer server.procMu.Unlock()
 	switch stream.Subindex {
 	case 0:
 		return od.ErrReadonly
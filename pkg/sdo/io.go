@@ -1,93 +1,151 @@
 package sdo
 
 import (
+	"context"
 	"encoding/binary"
 	"io"
-	"time"
 
 	"github.com/samsamfire/gocanopen/pkg/od"
 )
 
-type sdoRawReadWriter struct {
-	client *SDOClient
-}
-
 // Create a new raw SDO reader
 // This does not need an object dictionary but no checks will be made for the expected data
-// If blockEnabled is set to true, reading attempted using block transfer
+// If blockEnabled is set to true, reading attempted using block transfer ([BlockAuto])
 // If counterpart does not support block transfer or if transfer size is too small, this should
-// default to expedited / segmented transfer
+// default to expedited / segmented transfer.
+// The reader is a [Transfer] : it should be read until EOF or closed (it implements [io.Closer]),
+// otherwise the client stays reserved. Use [SDOClient.NewRawReaderWith] for getting the handle.
 func (client *SDOClient) NewRawReader(nodeId uint8, index uint16, subindex uint8, blockEnabled bool, size uint32,
 ) (io.Reader, error) {
-	// Setup client for a new transfer
-	err := client.setupServer(
-		uint32(ClientServiceId)+uint32(nodeId),
-		uint32(ServerServiceId)+uint32(nodeId),
-		nodeId,
-	)
+	return client.NewRawReaderCtx(context.Background(), nodeId, index, subindex, blockEnabled, size)
+}
+
+// Same as [SDOClient.NewRawReader] but reading can be cancelled with ctx.
+// On cancellation, an SDO abort is sent to the server.
+func (client *SDOClient) NewRawReaderCtx(ctx context.Context, nodeId uint8, index uint16, subindex uint8, blockEnabled bool, size uint32,
+) (io.Reader, error) {
+	tr, err := client.NewRawReaderWith(ctx, nodeId, index, subindex, size, blockOptions(blockEnabled))
+	if err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// Same as [SDOClient.NewRawReaderCtx] but with explicit transfer options.
+// size is the expected size if known (0 otherwise), it is used for selecting
+// the transfer type, see [TransferOptions].
+// The returned transfer should be read until EOF or closed, see [Transfer].
+func (client *SDOClient) NewRawReaderWith(ctx context.Context, nodeId uint8, index uint16, subindex uint8, size uint32, opts TransferOptions,
+) (*Transfer, error) {
+	tr, err := client.newTransfer(ctx, nodeId, index, subindex, true, opts)
 	if err != nil {
 		return nil, err
 	}
 	// Setup client for reading
-	err = client.uploadSetup(index, subindex, blockEnabled)
-	return client.rw, err
+	err = client.uploadSetup(index, subindex, size, opts)
+	if err != nil {
+		return nil, tr.finish(err)
+	}
+	return tr, nil
 }
 
 // Create a new raw SDO writer
 // This does not need an object dictionary but no checks will be made for the expected data
-// If blockEnabled is set to true, writing attempted using block transfer
+// If blockEnabled is set to true, writing attempted using block transfer ([BlockAuto])
 // If counterpart does not support block transfer or if transfer size is too small, this should
-// default to expedited / segmented transfer
+// default to expedited / segmented transfer.
+// The writer is a [Transfer] : it should be written to completion or closed (it implements [io.Closer]),
+// otherwise the client stays reserved. Use [SDOClient.NewRawWriterWith] for getting the handle.
 func (client *SDOClient) NewRawWriter(nodeId uint8, index uint16, subindex uint8, blockEnabled bool, size uint32,
 ) (io.Writer, error) {
-	// Setup client for a new transfer
-	err := client.setupServer(
-		uint32(ClientServiceId)+uint32(nodeId),
-		uint32(ServerServiceId)+uint32(nodeId),
-		nodeId,
-	)
+	return client.NewRawWriterCtx(context.Background(), nodeId, index, subindex, blockEnabled, size)
+}
+
+// Same as [SDOClient.NewRawWriter] but writing can be cancelled with ctx.
+// On cancellation, an SDO abort is sent to the server.
+func (client *SDOClient) NewRawWriterCtx(ctx context.Context, nodeId uint8, index uint16, subindex uint8, blockEnabled bool, size uint32,
+) (io.Writer, error) {
+	tr, err := client.NewRawWriterWith(ctx, nodeId, index, subindex, size, blockOptions(blockEnabled))
+	if err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// Same as [SDOClient.NewRawWriterCtx] but with explicit transfer options.
+// size is the total size to be written (0 if unknown), it is used for selecting
+// the transfer type, see [TransferOptions].
+// The returned transfer should be written to completion or closed, see [Transfer].
+// It also implements [io.ReaderFrom] for streaming data.
+func (client *SDOClient) NewRawWriterWith(ctx context.Context, nodeId uint8, index uint16, subindex uint8, size uint32, opts TransferOptions,
+) (*Transfer, error) {
+	tr, err := client.newTransfer(ctx, nodeId, index, subindex, false, opts)
 	if err != nil {
 		return nil, err
 	}
 	// Setup client for writing
-	err = client.downloadSetup(index, subindex, size, blockEnabled)
-	return client.rw, err
+	err = client.downloadSetup(index, subindex, size, opts)
+	if err != nil {
+		return nil, tr.finish(err)
+	}
+	tr.size = size
+	return tr, nil
 }
 
 // Implements io.Reader interface
 // Read bytes from remote node using sdo client
-func (rw *sdoRawReadWriter) Read(b []byte) (n int, err error) {
-	client := rw.client
+func (tr *Transfer) Read(b []byte) (n int, err error) {
+	client := tr.client
 	n = 0
+	if tr.done {
+		if tr.err == nil {
+			return 0, io.EOF
+		}
+		return 0, tr.err
+	}
+
+	var size, transferred uint32
 
 	for {
-		ret, err := client.upload(DefaultClientProcessPeriodUs, false, nil, nil, nil)
+		ret, err := client.upload(uint32(client.processingPeriodUs), false, &size, &transferred, nil)
+		tr.update(transferred, size)
 		switch {
 		case err != nil:
-			return n, err
+			return n, tr.finish(err)
 		case ret == uploadDataFull:
 			// Fifo needs emptying
 			n += client.fifo.Read(b[n:], nil)
 		case ret == success:
 			// Read finished successfully, empty fifo one last time and return EOF
 			n += client.fifo.Read(b[n:], nil)
+			tr.finish(nil)
 			return n, io.EOF
 		}
 		// If no more space in buffer return
 		if n >= len(b) {
 			return n, err
 		}
-		time.Sleep(time.Duration(client.processingPeriodUs) * time.Microsecond)
+		if err := tr.wait(); err != nil {
+			return n, tr.finish(err)
+		}
 	}
 }
 
 // Read a given index/subindex from node into data
-// This is blocking
+// This is blocking. If the object does not fit in data, the first len(data) bytes
+// are returned and the rest of the transfer is aborted with [AbortGeneral],
+// unless the object was already received entirely.
 func (client *SDOClient) ReadRaw(nodeId uint8, index uint16, subindex uint8, data []byte) (int, error) {
-	r, err := client.NewRawReader(nodeId, index, subindex, false, 0) // size not specified
+	return client.ReadRawCtx(context.Background(), nodeId, index, subindex, data)
+}
+
+// Same as [SDOClient.ReadRaw] but can be cancelled with ctx
+func (client *SDOClient) ReadRawCtx(ctx context.Context, nodeId uint8, index uint16, subindex uint8, data []byte) (int, error) {
+	r, err := client.NewRawReaderWith(ctx, nodeId, index, subindex, 0, blockOptions(false)) // size not specified
 	if err != nil {
 		return 0, err
 	}
+	defer r.Close()
 	n, err := r.Read(data)
 	if err != nil && err != io.EOF {
 		return n, err
@@ -98,21 +156,59 @@ func (client *SDOClient) ReadRaw(nodeId uint8, index uint16, subindex uint8, dat
 // Read everything from a given index/subindex from node and return all bytes
 // Similar to io.ReadAll
 func (client *SDOClient) ReadAll(nodeId uint8, index uint16, subindex uint8) ([]byte, error) {
-	r, err := client.NewRawReader(nodeId, index, subindex, true, 0) // size not specified
+	return client.ReadAllCtx(context.Background(), nodeId, index, subindex)
+}
+
+// Same as [SDOClient.ReadAll] but can be cancelled with ctx, e.g. for long block transfers
+func (client *SDOClient) ReadAllCtx(ctx context.Context, nodeId uint8, index uint16, subindex uint8) ([]byte, error) {
+	return client.ReadAllWith(ctx, nodeId, index, subindex, TransferOptions{Block: BlockAuto})
+}
+
+// Same as [SDOClient.ReadAllCtx] but with explicit transfer options
+func (client *SDOClient) ReadAllWith(ctx context.Context, nodeId uint8, index uint16, subindex uint8, opts TransferOptions) ([]byte, error) {
+	r, err := client.NewRawReaderWith(ctx, nodeId, index, subindex, 0, opts) // size not specified
 	if err != nil {
 		return nil, err
 	}
+	defer r.Close()
 	return io.ReadAll(r)
 }
 
+// Implements io.WriterTo interface, used by io.Copy.
+// Data is streamed to w until the end of the transfer, without
+// buffering the whole object in memory.
+func (tr *Transfer) WriteTo(w io.Writer) (n int64, err error) {
+	buf := make([]byte, BlockMaxSize*BlockSeqSize)
+	for {
+		nRead, errRead := tr.Read(buf)
+		if nRead > 0 {
+			nWritten, errWrite := w.Write(buf[:nRead])
+			n += int64(nWritten)
+			if errWrite != nil {
+				tr.Close()
+				return n, errWrite
+			}
+		}
+		if errRead == io.EOF {
+			return n, nil
+		}
+		if errRead != nil {
+			return n, errRead
+		}
+	}
+}
+
 // Implements io.Writer interface
 // Write bytes from remote node using sdo client
 // Writing in several iterations is only possible in block transfers
 // as in regular small transfers, client state machine starts processing
 // internal fifo as soon a we call downloadMain. This means that for small
 // transfers, exact size should be written.
-func (rw *sdoRawReadWriter) Write(b []byte) (n int, err error) {
-	client := rw.client
+func (tr *Transfer) Write(b []byte) (n int, err error) {
+	client := tr.client
+	if tr.done {
+		return 0, ErrTransferFinished
+	}
 
 	// Fill fifo buffer
 	nUint32 := uint32(0)
@@ -123,16 +219,17 @@ func (rw *sdoRawReadWriter) Write(b []byte) (n int, err error) {
 	}
 	for {
 		ret, err := client.downloadMain(
-			DefaultClientProcessPeriodUs,
+			uint32(client.processingPeriodUs),
 			false,
 			bufferPartial,
 			&nUint32,
 			nil,
 			false,
 		)
+		tr.update(nUint32, tr.size)
 		switch {
 		case err != nil:
-			return int(nUint32), err
+			return int(nUint32), tr.finish(err)
 		case ret == blockDownloadInProgress && bufferPartial:
 			// Fill buffer whilst block download in progress
 			n += client.fifo.Write(b[n:], nil)
@@ -140,24 +237,96 @@ func (rw *sdoRawReadWriter) Write(b []byte) (n int, err error) {
 				bufferPartial = false
 			}
 		case ret == success:
-			return int(nUint32), err
+			return int(nUint32), tr.finish(nil)
+		}
+		if err := tr.wait(); err != nil {
+			return int(nUint32), tr.finish(err)
+		}
+	}
+}
+
+// Implements io.ReaderFrom interface, used by io.Copy.
+// Data is streamed from r until EOF, the internal fifo being refilled
+// whilst the transfer is in progress. This allows writing big objects
+// (e.g. with block transfer) without buffering them in memory.
+// For segmented transfers, r should not starve, otherwise empty segments are sent.
+func (tr *Transfer) ReadFrom(r io.Reader) (n int64, err error) {
+	client := tr.client
+	if tr.done {
+		return 0, ErrTransferFinished
+	}
+	buf := make([]byte, BlockMaxSize*BlockSeqSize)
+	var pending []byte
+	eof := false
+	nUint32 := uint32(0)
+
+	for {
+		// Fill fifo as much as possible
+		for client.fifo.GetSpace() > 0 && (len(pending) > 0 || !eof) {
+			if len(pending) == 0 {
+				nRead, errRead := r.Read(buf)
+				pending = buf[:nRead]
+				if errRead == io.EOF {
+					eof = true
+				} else if errRead != nil {
+					client.cancel(AbortGeneral)
+					return int64(nUint32), errRead
+				}
+			}
+			pending = pending[client.fifo.Write(pending, nil):]
+		}
+		bufferPartial := len(pending) > 0 || !eof
+		ret, err := client.downloadMain(
+			uint32(client.processingPeriodUs),
+			false,
+			bufferPartial,
+			&nUint32,
+			nil,
+			false,
+		)
+		tr.update(nUint32, tr.size)
+		if err != nil {
+			return int64(nUint32), tr.finish(err)
+		}
+		if ret == success {
+			return int64(nUint32), tr.finish(nil)
+		}
+		// Sub-block segments are sent back to back
+		if ret == blockDownloadInProgress && tr.ctx.Err() == nil {
+			continue
+		}
+		if err := tr.wait(); err != nil {
+			return int64(nUint32), tr.finish(err)
 		}
-		time.Sleep(time.Duration(client.processingPeriodUs) * time.Microsecond)
 	}
 }
 
 // Write a given index/subindex from node into data
 // This is blocking
 func (client *SDOClient) WriteRaw(nodeId uint8, index uint16, subindex uint8, data any, forceSegmented bool) error {
+	return client.WriteRawCtx(context.Background(), nodeId, index, subindex, data, forceSegmented)
+}
+
+// Same as [SDOClient.WriteRaw] but can be cancelled with ctx
+func (client *SDOClient) WriteRawCtx(ctx context.Context, nodeId uint8, index uint16, subindex uint8, data any, forceSegmented bool) error {
 	_ = forceSegmented
+	return client.WriteRawWith(ctx, nodeId, index, subindex, data, TransferOptions{Block: BlockNever})
+}
+
+// Same as [SDOClient.WriteRawCtx] but with explicit transfer options,
+// e.g. for using block transfer on big objects :
+//
+//	client.WriteRawWith(ctx, nodeId, index, subindex, data, sdo.TransferOptions{Block: sdo.BlockAuto})
+func (client *SDOClient) WriteRawWith(ctx context.Context, nodeId uint8, index uint16, subindex uint8, data any, opts TransferOptions) error {
 	encoded, err := od.EncodeFromGeneric(data)
 	if err != nil {
 		return err
 	}
-	w, err := client.NewRawWriter(nodeId, index, subindex, false, uint32(len(encoded)))
+	w, err := client.NewRawWriterWith(ctx, nodeId, index, subindex, uint32(len(encoded)), opts)
 	if err != nil {
 		return err
 	}
+	defer w.Close()
 	_, err = w.Write(encoded)
 	return err
 }
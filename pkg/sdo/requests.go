@@ -10,6 +10,7 @@ func (s *SDOServer) processIncoming(rx SDOMessage) error {
 		s.state = stateIdle
 		abortCode := binary.LittleEndian.Uint32(rx.raw[4:])
 		s.logger.Warn("[RX] abort received from client", "code", abortCode, "description", Abort(abortCode))
+		s.transferAborted(Abort(abortCode))
 		return nil
 	}
 
@@ -53,6 +54,7 @@ func (s *SDOServer) processIncoming(rx SDOMessage) error {
 		if err != nil {
 			return err
 		}
+		s.transferStarted(s.state == stateUploadInitiateReq || s.state == stateUploadBlkInitiateReq)
 	}
 
 	// Process receive state machine
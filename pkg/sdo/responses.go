@@ -47,11 +47,12 @@ func (s *SDOServer) processOutgoing() error {
 }
 
 func (s *SDOServer) txAbort(err error) {
-	if sdoAbort, ok := err.(Abort); !ok {
+	sdoAbort, ok := err.(Abort)
+	if !ok {
 		s.logger.Error("[TX] Abort internal error : unknown abort code", "err", err)
-		s.SendAbort(AbortGeneral)
-	} else {
-		s.SendAbort(sdoAbort)
+		sdoAbort = AbortGeneral
 	}
+	s.SendAbort(sdoAbort)
 	s.state = stateIdle
+	s.transferAborted(sdoAbort)
 }
@@ -0,0 +1,111 @@
+package sdo
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrObjectChanged is returned when an upload is resumed but the size indicated
+// by the server differs from the aborted attempt
+var ErrObjectChanged = errors.New("object size changed between transfer attempts")
+
+// IsResumable returns true if err is an SDO abort that may be transient, e.g.
+// a protocol timeout, a CRC error or lack of ressources on the server.
+// Context cancellation is never resumable.
+func IsResumable(err error) bool {
+	var abort Abort
+	if !errors.As(err, &abort) {
+		return false
+	}
+	switch abort {
+	case AbortToggleBit, AbortTimeout, AbortBlockSize, AbortSeqNum, AbortCRC, AbortOutOfMem, AbortNoRessource:
+		return true
+	default:
+		return false
+	}
+}
+
+// Upload a given index/subindex from node into w, without buffering the
+// whole object in memory. If the transfer is aborted with a resumable error
+// (see [IsResumable]), it is resumed up to opts.Resume times.
+// SDO has no notion of offset, so a resumed upload restarts from the beginning
+// on the bus, but bytes already written to w are skipped : w receives the object once.
+// Returns the number of bytes written to w.
+func (client *SDOClient) UploadTo(ctx context.Context, nodeId uint8, index uint16, subindex uint8, w io.Writer, opts TransferOptions,
+) (int64, error) {
+	resumed := &resumeWriter{w: w}
+	for attempt := 0; ; attempt++ {
+		tr, err := client.NewRawReaderWith(ctx, nodeId, index, subindex, 0, opts)
+		if err != nil {
+			return resumed.written, err
+		}
+		resumed.tr = tr
+		resumed.skip = resumed.written
+		_, err = tr.WriteTo(resumed)
+		tr.Close()
+		if resumed.size == 0 {
+			resumed.size = tr.size
+		}
+		if err == nil || attempt >= opts.Resume || !IsResumable(err) {
+			return resumed.written, err
+		}
+	}
+}
+
+// Download r into a given index/subindex of node, without buffering the
+// whole object in memory. size is the total size to be written (0 if unknown).
+// If the transfer is aborted with a resumable error (see [IsResumable]),
+// it is resumed up to opts.Resume times : r is seeked back to its initial
+// offset and the object is downloaded again from the beginning, as SDO has
+// no notion of offset.
+// Returns the number of bytes downloaded by the last attempt.
+func (client *SDOClient) DownloadFrom(ctx context.Context, nodeId uint8, index uint16, subindex uint8, r io.ReadSeeker, size uint32, opts TransferOptions,
+) (int64, error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	for attempt := 0; ; attempt++ {
+		tr, err := client.NewRawWriterWith(ctx, nodeId, index, subindex, size, opts)
+		if err != nil {
+			return 0, err
+		}
+		n, err := tr.ReadFrom(r)
+		tr.Close()
+		if err == nil || attempt >= opts.Resume || !IsResumable(err) {
+			return n, err
+		}
+		_, err = r.Seek(start, io.SeekStart)
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// Writer that skips the bytes already written by a previous attempt
+type resumeWriter struct {
+	w       io.Writer
+	tr      *Transfer
+	size    uint32 // Size indicated by first attempt
+	skip    int64  // Bytes to skip in this attempt
+	written int64  // Bytes written to w
+}
+
+func (rw *resumeWriter) Write(b []byte) (int, error) {
+	if rw.size != 0 && rw.tr.size != 0 && rw.tr.size != rw.size {
+		return 0, ErrObjectChanged
+	}
+	n := len(b)
+	if rw.skip > 0 {
+		skipped := min(rw.skip, int64(len(b)))
+		rw.skip -= skipped
+		b = b[skipped:]
+	}
+	written, err := rw.w.Write(b)
+	rw.written += int64(written)
+	if err != nil {
+		return n - len(b) + written, err
+	}
+	return n, nil
+}
@@ -49,24 +49,133 @@ type SDOServer struct {
 	errorExtraInfo  error
 
 	nmt uint8
+	// Expedited fast path
+	fastPath bool
+	procMu   sync.Mutex    // Held while processing the state machine
+	pending  int           // Frames queued but not processed yet
+	kick     chan struct{} // Restarts timeout of Process when a transfer is started in RX path
+	timerUs  uint32        // Time without request during a transfer, see [SDOServer.ProcessPending]
+	// Access control, see [SDOServer.SetAccessCheck]
+	accessCheck func(access Access) error
+	// Transfer events, see [SDOServer.SetEventHook]
+	eventHook func(event ServerEvent)
+	activity  serverActivity
+}
+
+// An Access to an OD entry requested by an SDO client, see [SDOServer.SetAccessCheck]
+type Access struct {
+	CobId    uint32 // CAN ID of the requests (client to server)
+	Index    uint16
+	Subindex uint8
+	Write    bool  // true for downloads, false for uploads
+	NMTState uint8 // Current NMT state of the server
 }
 
 // Handle [SDOServer] related RX CAN frames
 func (server *SDOServer) Handle(frame canopen.Frame) {
 	server.mu.Lock()
-	defer server.mu.Unlock()
 	if frame.DLC != 8 {
+		server.mu.Unlock()
 		return
 	}
 	rx := SDOMessage{}
 	rx.raw = frame.Data
+	if server.fastPath && server.pending == 0 && server.isFastPathRequest(rx) && server.procMu.TryLock() {
+		if server.state == stateIdle {
+			server.mu.Unlock()
+			server.processFastPath(rx)
+			return
+		}
+		server.procMu.Unlock()
+	}
 	select {
 	case server.rx <- rx:
+		server.pending++
 	default:
 		server.logger.Warn("dropped SDO server RX frame")
 		// Drop frame
 	}
+	server.mu.Unlock()
+}
+
+// Enable answering expedited requests directly inside of [SDOServer.Handle], i.e.
+// in the CAN receive path, instead of waiting for [SDOServer.Process].
+// This reduces the response latency of expedited uploads & downloads, but
+// OD accesses (including extensions) are then done from the receive goroutine
+// and should be fast. Other requests, or requests received while a transfer
+// is in progress, are still handled by [SDOServer.Process].
+// This should not be used with buses that deliver own frames synchronously
+// to the same network, e.g. loopback bus with receive own enabled.
+func (server *SDOServer) SetExpeditedFastPath(enabled bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.fastPath = enabled
+}
 
+// Set a callback authorizing every upload & download before it starts, e.g. for
+// refusing writes of configuration objects in operational state. Returning an [Abort]
+// sends it to the client, any other error aborts with [AbortDataDeviceState].
+// The callback is called from the goroutine processing the server (or the receive
+// goroutine, see [SDOServer.SetExpeditedFastPath]) and should not block. nil removes it.
+func (server *SDOServer) SetAccessCheck(check func(access Access) error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.accessCheck = check
+}
+
+// Authorize access to current entry
+func (server *SDOServer) checkAccess(write bool) error {
+	server.mu.Lock()
+	check := server.accessCheck
+	access := Access{
+		CobId:    server.cobIdClientToServer,
+		Index:    server.index,
+		Subindex: server.subindex,
+		Write:    write,
+		NMTState: server.nmt,
+	}
+	server.mu.Unlock()
+	if check == nil {
+		return nil
+	}
+	err := check(access)
+	if err == nil {
+		return nil
+	}
+	if abort, ok := err.(Abort); ok {
+		return abort
+	}
+	server.errorExtraInfo = err
+	return AbortDataDeviceState
+}
+
+// Returns true if request is an upload initiate or an expedited download
+// and server is ready to answer it
+func (server *SDOServer) isFastPathRequest(rx SDOMessage) bool {
+	if !server.valid || (server.nmt != nmt.StateOperational && server.nmt != nmt.StatePreOperational) {
+		return false
+	}
+	switch rx.raw[0] & MaskCS {
+	case CSUploadInitiate:
+		return true
+	case CSDownloadInitiate:
+		return rx.IsExpedited()
+	default:
+		return false
+	}
+}
+
+// Process request inside of RX path, procMu should be held
+func (server *SDOServer) processFastPath(rx SDOMessage) {
+	defer server.procMu.Unlock()
+	server.processRx(rx)
+	if server.state != stateIdle {
+		// e.g. segmented upload, remaining is handled by Process
+		select {
+		case server.kick <- struct{}{}:
+		default:
+		}
+	}
 }
 
 // Process [SDOServer] state machine and TX CAN frames
@@ -80,6 +189,7 @@ func (server *SDOServer) Process(ctx context.Context) (state uint8, err error) {
 	for {
 		server.mu.Lock()
 		nmtIsPreOrOperationnal := server.nmt == nmt.StateOperational || server.nmt == nmt.StatePreOperational
+		valid := server.valid
 		server.mu.Unlock()
 
 		select {
@@ -87,8 +197,11 @@ func (server *SDOServer) Process(ctx context.Context) (state uint8, err error) {
 			server.logger.Info("exiting sdo server process")
 			return
 		default:
-			if !server.valid || !nmtIsPreOrOperationnal {
+			if !valid || !nmtIsPreOrOperationnal {
+				server.procMu.Lock()
 				server.state = stateIdle
+				server.transferAborted(AbortDataDeviceState)
+				server.procMu.Unlock()
 				// Sleep to avoid huge CPU load when idling
 				time.Sleep(100 * time.Millisecond)
 				continue
@@ -98,26 +211,89 @@ func (server *SDOServer) Process(ctx context.Context) (state uint8, err error) {
 		select {
 		case rx := <-server.rx:
 			// New frame received, do what we need to do !
-			err := server.processIncoming(rx)
-			if err != nil && err != od.ErrPartial {
-				// Abort straight away, nothing to send afterwards
-				server.txAbort(err)
-				break
-			}
-			// A response is expected
-			err = server.processOutgoing()
-			if err != nil {
-				server.txAbort(err)
-			}
+			server.procMu.Lock()
+			server.processRx(rx)
+			server.procMu.Unlock()
+			server.mu.Lock()
+			server.pending--
+			server.mu.Unlock()
+
+		case <-server.kick:
+			// Transfer started by fast path, restart timeout
 
 		case <-time.After(timeout):
+			server.procMu.Lock()
 			if server.state != stateIdle {
 				server.txAbort(AbortTimeout)
 			}
+			server.procMu.Unlock()
+		}
+	}
+}
+
+// Process the received requests without blocking. timeDifferenceUs is the time
+// elapsed since the previous call, used for the transfer timeout.
+// This can be used instead of [SDOServer.Process] for driving processing
+// from a custom timer, e.g. for deterministic simulations.
+func (server *SDOServer) ProcessPending(timeDifferenceUs uint32) {
+	server.mu.Lock()
+	nmtIsPreOrOperationnal := server.nmt == nmt.StateOperational || server.nmt == nmt.StatePreOperational
+	valid := server.valid
+	server.mu.Unlock()
+
+	server.procMu.Lock()
+	defer server.procMu.Unlock()
+	if !valid || !nmtIsPreOrOperationnal {
+		server.state = stateIdle
+		server.timerUs = 0
+		server.transferAborted(AbortDataDeviceState)
+		return
+	}
+	processed := false
+	for {
+		select {
+		case rx := <-server.rx:
+			server.processRx(rx)
+			server.mu.Lock()
+			server.pending--
+			server.mu.Unlock()
+			processed = true
+			continue
+		case <-server.kick:
+			processed = true
+			continue
+		default:
 		}
+		break
+	}
+	if processed || server.state == stateIdle {
+		server.timerUs = 0
+		return
+	}
+	server.timerUs += timeDifferenceUs
+	if server.timerUs >= server.timeoutTimeUs {
+		server.txAbort(AbortTimeout)
+		server.timerUs = 0
 	}
 }
 
+// Process a single received frame and send the response if any
+func (server *SDOServer) processRx(rx SDOMessage) {
+	err := server.processIncoming(rx)
+	if err != nil && err != od.ErrPartial {
+		// Abort straight away, nothing to send afterwards
+		server.txAbort(err)
+		return
+	}
+	// A response is expected
+	err = server.processOutgoing()
+	if err != nil {
+		server.txAbort(err)
+		return
+	}
+	server.transferUpdated()
+}
+
 func (server *SDOServer) initRxTx(cobIdClientToServer uint32, cobIdServerToClient uint32) error {
 	// Only proceed if parameters change (i.e. different client)
 	if cobIdServerToClient == server.cobIdServerToClient && cobIdClientToServer == server.cobIdClientToServer {
@@ -138,19 +314,21 @@ func (server *SDOServer) initRxTx(cobIdClientToServer uint32, cobIdServerToClien
 	} else {
 		CanIdS2C = 0
 	}
-	if CanIdC2S != 0 && CanIdS2C != 0 {
-		server.valid = true
-	} else {
+	valid := CanIdC2S != 0 && CanIdS2C != 0
+	if !valid {
 		CanIdC2S = 0
 		CanIdS2C = 0
-		server.valid = false
 	}
 	// Configure buffers, if initializing then insert in buffer, otherwise, update
+	// server.mu is not held as RX frames are handled with bus manager locked
 	err := server.Subscribe(uint32(CanIdC2S), 0x7FF, false, server)
+	server.mu.Lock()
+	defer server.mu.Unlock()
 	if err != nil {
 		server.valid = false
 		return err
 	}
+	server.valid = valid
 	server.txBuffer = canopen.NewFrame(uint32(CanIdS2C), 0, 8)
 	return nil
 }
@@ -306,6 +484,12 @@ func (server *SDOServer) updateStreamer(response SDOMessage) error {
 	if !upload && !server.streamer.HasAttribute(od.AttributeSdoW) {
 		return AbortReadOnly
 	}
+	err = server.checkAccess(!upload)
+	if err != nil {
+		return err
+	}
+	server.sizeIndicated = 0
+	server.sizeTransferred = 0
 
 	// In case of reading, we need to prepare data now
 	if upload {
@@ -367,6 +551,11 @@ func (server *SDOServer) SendAbort(abortCode Abort) {
 	)
 }
 
+// Returns the number of received frames waiting to be processed
+func (server *SDOServer) Backlog() int {
+	return len(server.rx)
+}
+
 // Set internal nmt state
 func (server *SDOServer) SetNMTState(state uint8) {
 	server.mu.Lock()
@@ -396,6 +585,7 @@ func NewSDOServer(
 	server.timeoutTimeUs = timeoutMs * 1000
 	server.blockTimeout = timeoutMs * 700
 	server.rx = make(chan SDOMessage, 127)
+	server.kick = make(chan struct{}, 1)
 	server.buf = bytes.NewBuffer(make([]byte, 0, 1000))
 	server.intermediateBuf = make([]byte, 1000)
 	var canIdClientToServer uint16
@@ -0,0 +1,126 @@
+package sdo
+
+import (
+	"fmt"
+	"time"
+)
+
+// Type of a [ServerEvent]
+type ServerEventType uint8
+
+const (
+	ServerTransferStarted  ServerEventType = 1 // Upload or download accepted by the server
+	ServerTransferProgress ServerEventType = 2 // More data has been transferred
+	ServerTransferFinished ServerEventType = 3 // Transfer finished successfully
+	ServerTransferAborted  ServerEventType = 4 // Transfer aborted by the server or by the client
+)
+
+func (t ServerEventType) String() string {
+	switch t {
+	case ServerTransferStarted:
+		return "started"
+	case ServerTransferProgress:
+		return "progress"
+	case ServerTransferFinished:
+		return "finished"
+	case ServerTransferAborted:
+		return "aborted"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint8(t))
+	}
+}
+
+// Activity of an [SDOServer], see [SDOServer.SetEventHook]
+type ServerEvent struct {
+	Type        ServerEventType
+	CobId       uint32 // CAN ID of the requests (client to server)
+	Index       uint16
+	Subindex    uint8
+	Upload      bool          // Upload (read) if true, download (write) otherwise
+	Transferred uint32        // Bytes transferred so far
+	Size        uint32        // Total size if indicated, 0 otherwise
+	Elapsed     time.Duration // Time since transfer start
+	Abort       Abort         // For [ServerTransferAborted]
+}
+
+// Transfer reported to the event hook
+type serverActivity struct {
+	active   bool
+	upload   bool
+	start    time.Time
+	reported uint32
+}
+
+// Set a hook called on every transfer event of this server, e.g. for displaying the progress
+// of long domain downloads or for implementing watchdogs. It is called from the goroutine
+// processing the server (or the receive goroutine, see [SDOServer.SetExpeditedFastPath]),
+// so it should not block nor use the same server. nil removes the hook.
+func (server *SDOServer) SetEventHook(hook func(event ServerEvent)) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.eventHook = hook
+}
+
+// Bytes transferred by current transfer, expedited transfers do not update counters
+func (server *SDOServer) transferred(eventType ServerEventType) uint32 {
+	if server.sizeTransferred == 0 && eventType == ServerTransferFinished {
+		if server.activity.upload {
+			return server.sizeIndicated
+		}
+		return server.streamer.DataLength
+	}
+	return server.sizeTransferred
+}
+
+func (server *SDOServer) notify(eventType ServerEventType, abort Abort) {
+	server.mu.Lock()
+	hook := server.eventHook
+	cobId := server.cobIdClientToServer
+	server.mu.Unlock()
+	if hook == nil {
+		return
+	}
+	transferred := server.transferred(eventType)
+	server.activity.reported = transferred
+	hook(ServerEvent{
+		Type:        eventType,
+		CobId:       cobId,
+		Index:       server.index,
+		Subindex:    server.subindex,
+		Upload:      server.activity.upload,
+		Transferred: transferred,
+		Size:        server.sizeIndicated,
+		Elapsed:     time.Since(server.activity.start),
+		Abort:       abort,
+	})
+}
+
+// Transfer accepted, procMu should be held
+func (server *SDOServer) transferStarted(upload bool) {
+	server.activity = serverActivity{active: true, upload: upload, start: time.Now()}
+	server.notify(ServerTransferStarted, 0)
+}
+
+// Report progress or end of current transfer after processing a request, procMu should be held
+func (server *SDOServer) transferUpdated() {
+	if !server.activity.active {
+		return
+	}
+	if server.state == stateIdle {
+		server.activity.active = false
+		server.notify(ServerTransferFinished, 0)
+		return
+	}
+	if server.sizeTransferred != server.activity.reported {
+		server.notify(ServerTransferProgress, 0)
+	}
+}
+
+// Current transfer aborted, procMu should be held
+func (server *SDOServer) transferAborted(abort Abort) {
+	if !server.activity.active {
+		return
+	}
+	server.activity.active = false
+	server.notify(ServerTransferAborted, abort)
+}
@@ -0,0 +1,234 @@
+package sdo
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"testing"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	nodeIdTest = 0x10
+	// Simulated elapsed time between two client process calls
+	clientStepUs = 10_000
+)
+
+// Bus that records all sent frames
+type recordBus struct {
+	mu   sync.Mutex
+	sent []canopen.Frame
+}
+
+func (b *recordBus) Connect(...any) error                           { return nil }
+func (b *recordBus) Disconnect() error                              { return nil }
+func (b *recordBus) Subscribe(callback canopen.FrameListener) error { return nil }
+func (b *recordBus) Send(frame canopen.Frame) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, frame)
+	return nil
+}
+
+// Check that every abort frame sent uses an abort code from the spec
+func assertAbortsValid(t *testing.T, bus *recordBus) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	for _, frame := range bus.sent {
+		if frame.Data[0] != CSAbort {
+			continue
+		}
+		code := Abort(binary.LittleEndian.Uint32(frame.Data[4:]))
+		_, ok := AbortCodeDescriptionMap[code]
+		assert.True(t, ok, "abort code x%x not in spec", uint32(code))
+	}
+}
+
+// Split raw bytes into 8 bytes CAN frames
+func toFrames(id uint32, raw []byte) []canopen.Frame {
+	frames := make([]canopen.Frame, 0)
+	for len(raw) > 0 {
+		frame := canopen.NewFrame(id, 0, 8)
+		n := copy(frame.Data[:], raw)
+		raw = raw[n:]
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+func newServerTest(t testing.TB) (*SDOServer, *recordBus) {
+	bus := &recordBus{}
+	bm := canopen.NewBusManager(bus)
+	odict := od.Default()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server, err := NewSDOServer(bm, logger, odict, nodeIdTest, DefaultServerTimeout, odict.Index(0x1200))
+	assert.Nil(t, err)
+	server.SetNMTState(nmt.StateOperational)
+	return server, bus
+}
+
+func newClientTest(t testing.TB) (*SDOClient, *recordBus) {
+	bus := &recordBus{}
+	bm := canopen.NewBusManager(bus)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client, err := NewSDOClient(bm, logger, nil, 0, DefaultClientTimeout, nil)
+	assert.Nil(t, err)
+	err = client.setupServer(
+		uint32(ClientServiceId)+nodeIdTest,
+		uint32(ServerServiceId)+nodeIdTest,
+		nodeIdTest,
+	)
+	assert.Nil(t, err)
+	return client, bus
+}
+
+// Feed frames to server and check invariants
+func checkServer(t *testing.T, frames []canopen.Frame) {
+	server, bus := newServerTest(t)
+	for _, frame := range frames {
+		assert.NotPanics(t, func() { server.processRx(SDOMessage{raw: frame.Data}) })
+		assert.NotEqual(t, stateAbort, server.state)
+	}
+	assertAbortsValid(t, bus)
+	// Whatever happened before, an abort from client always brings back to idle
+	server.processRx(SDOMessage{raw: [8]byte{CSAbort}})
+	assert.Equal(t, stateIdle, server.state)
+}
+
+// Run a client upload or download, feeding frames as server responses and check invariants
+func checkClient(t *testing.T, frames []canopen.Frame, download bool, block bool) {
+	client, bus := newClientTest(t)
+	var err error
+	if download {
+		err = client.downloadSetup(0x2001, 0, 20, blockOptions(block))
+		client.fifo.Write(make([]byte, 20), nil)
+	} else {
+		err = client.uploadSetup(0x2001, 0, 0, blockOptions(block))
+	}
+	assert.Nil(t, err)
+	buf := make([]byte, 100)
+	// Once frames are exhausted, client should timeout
+	maxIter := len(frames) + int(client.timeoutTimeUs/clientStepUs) + 10
+	for i := 0; i < maxIter; i++ {
+		if i < len(frames) {
+			client.Handle(frames[i])
+		}
+		var ret uint8
+		assert.NotPanics(t, func() {
+			if download {
+				ret, err = client.downloadMain(clientStepUs, false, false, nil, nil, false)
+			} else {
+				ret, err = client.upload(clientStepUs, false, nil, nil, nil)
+				client.fifo.Read(buf, nil)
+			}
+		})
+		if err != nil || ret == success {
+			break
+		}
+	}
+	assert.Equal(t, stateIdle, client.state, "client should always end up in idle")
+	assertAbortsValid(t, bus)
+}
+
+// Valid sequences of client requests used as seeds
+var serverSeeds = [][]byte{
+	// Expedited download 0x2001
+	{0x2F, 0x01, 0x20, 0x00, 0x05, 0, 0, 0},
+	// Expedited upload 0x2001
+	{0x40, 0x01, 0x20, 0x00, 0, 0, 0, 0},
+	// Segmented upload 0x1008
+	{0x40, 0x08, 0x10, 0x00, 0, 0, 0, 0, 0x60, 0, 0, 0, 0, 0, 0, 0, 0x70, 0, 0, 0, 0, 0, 0, 0},
+	// Segmented download 0x2001
+	{0x21, 0x01, 0x20, 0x00, 0x01, 0, 0, 0, 0x0D, 0x05, 0, 0, 0, 0, 0, 0},
+	// Block download 0x2001
+	{0xC6, 0x01, 0x20, 0x00, 0x01, 0, 0, 0, 0x81, 0x05, 0, 0, 0, 0, 0, 0, 0xD9, 0, 0, 0, 0, 0, 0, 0},
+	// Block upload 0x1008
+	{0xA4, 0x08, 0x10, 0x00, 0x7F, 0, 0, 0, 0xA3, 0, 0, 0, 0, 0, 0, 0, 0xA2, 0x01, 0x7F, 0, 0, 0, 0, 0, 0xA1, 0, 0, 0, 0, 0, 0, 0},
+}
+
+// Valid sequences of server responses used as seeds
+var clientSeeds = [][]byte{
+	// Expedited upload response
+	{0x4F, 0x01, 0x20, 0x00, 0x05, 0, 0, 0},
+	// Expedited download response
+	{0x60, 0x01, 0x20, 0x00, 0, 0, 0, 0},
+	// Segmented upload
+	{0x41, 0x01, 0x20, 0x00, 0x08, 0, 0, 0, 0x00, 1, 2, 3, 4, 5, 6, 7, 0x1D, 8, 0, 0, 0, 0, 0, 0},
+	// Block upload initiate
+	{0xC6, 0x01, 0x20, 0x00, 0x08, 0, 0, 0, 0x01, 1, 2, 3, 4, 5, 6, 7, 0x82, 8, 0, 0, 0, 0, 0, 0},
+	// Abort
+	{0x80, 0x01, 0x20, 0x00, 0x00, 0x00, 0x04, 0x05},
+}
+
+// Mutate a valid frame sequence by shuffling, duplicating, dropping & corrupting frames
+func mutate(r *rand.Rand, frames []canopen.Frame) []canopen.Frame {
+	mutated := make([]canopen.Frame, 0, len(frames)*2)
+	for _, frame := range frames {
+		switch r.Intn(6) {
+		case 0:
+			// Drop
+			continue
+		case 1:
+			// Duplicate
+			mutated = append(mutated, frame, frame)
+		case 2:
+			// Corrupt a random byte
+			frame.Data[r.Intn(8)] = byte(r.Intn(256))
+			mutated = append(mutated, frame)
+		default:
+			mutated = append(mutated, frame)
+		}
+	}
+	r.Shuffle(len(mutated), func(i, j int) { mutated[i], mutated[j] = mutated[j], mutated[i] })
+	return mutated
+}
+
+func TestServerRandomFrames(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, seed := range serverSeeds {
+		frames := toFrames(uint32(ClientServiceId)+nodeIdTest, seed)
+		t.Run("valid", func(t *testing.T) { checkServer(t, frames) })
+		for range 50 {
+			checkServer(t, mutate(r, frames))
+		}
+	}
+}
+
+func TestClientRandomFrames(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, seed := range clientSeeds {
+		frames := toFrames(uint32(ServerServiceId)+nodeIdTest, seed)
+		for range 20 {
+			mutated := mutate(r, frames)
+			checkClient(t, mutated, false, false)
+			checkClient(t, mutated, false, true)
+			checkClient(t, mutated, true, false)
+			checkClient(t, mutated, true, true)
+		}
+	}
+}
+
+func FuzzServer(f *testing.F) {
+	for _, seed := range serverSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		checkServer(t, toFrames(uint32(ClientServiceId)+nodeIdTest, raw))
+	})
+}
+
+func FuzzClient(f *testing.F) {
+	for _, seed := range clientSeeds {
+		f.Add(seed, false, false)
+		f.Add(seed, true, true)
+	}
+	f.Fuzz(func(t *testing.T, raw []byte, download bool, block bool) {
+		checkClient(t, toFrames(uint32(ServerServiceId)+nodeIdTest, raw), download, block)
+	})
+}
@@ -0,0 +1,87 @@
+package sdo
+
+import (
+	"errors"
+	"maps"
+	"time"
+)
+
+// Counters of an [SDOClient], counters are 32 bits and wrap around.
+type Stats struct {
+	Uploads         uint32           // Uploads (reads) finished, successful or not
+	Downloads       uint32           // Downloads (writes) finished, successful or not
+	Failed          uint32           // Transfers finished with an error
+	BytesUploaded   uint32           // Bytes transferred by uploads
+	BytesDownloaded uint32           // Bytes transferred by downloads
+	Retries         uint32           // Block transfers retried as normal transfers & block segments sent again
+	Aborts          map[Abort]uint32 // Failed transfers by SDO abort code, e.g. [AbortTimeout]
+}
+
+// Result of a finished [Transfer], see [SDOClient.SetTransferHook]
+type TransferResult struct {
+	NodeId      uint8
+	Index       uint16
+	Subindex    uint8
+	Upload      bool   // Upload (read) if true, download (write) otherwise
+	Transferred uint32 // Bytes transferred
+	Duration    time.Duration
+	Err         error // nil if successful, an [Abort] for SDO aborts
+}
+
+// Get counters of [SDOClient]
+func (client *SDOClient) Stats() Stats {
+	client.statsMu.Lock()
+	defer client.statsMu.Unlock()
+	stats := client.stats
+	stats.Aborts = maps.Clone(client.stats.Aborts)
+	return stats
+}
+
+// Reset counters of [SDOClient]
+func (client *SDOClient) ResetStats() {
+	client.statsMu.Lock()
+	defer client.statsMu.Unlock()
+	client.stats = Stats{}
+}
+
+// Set a hook called every time a transfer of this client finishes, e.g. for
+// instrumentation. It is called from the goroutine using the transfer, so it
+// should not block nor use the same client. nil removes the hook.
+func (client *SDOClient) SetTransferHook(hook func(result TransferResult)) {
+	client.statsMu.Lock()
+	defer client.statsMu.Unlock()
+	client.transferHook = hook
+}
+
+func (client *SDOClient) addRetry() {
+	client.statsMu.Lock()
+	defer client.statsMu.Unlock()
+	client.stats.Retries++
+}
+
+// Update counters with a finished transfer & call hook if any
+func (client *SDOClient) recordTransfer(result TransferResult) {
+	client.statsMu.Lock()
+	if result.Upload {
+		client.stats.Uploads++
+		client.stats.BytesUploaded += result.Transferred
+	} else {
+		client.stats.Downloads++
+		client.stats.BytesDownloaded += result.Transferred
+	}
+	if result.Err != nil {
+		client.stats.Failed++
+		var abort Abort
+		if errors.As(result.Err, &abort) {
+			if client.stats.Aborts == nil {
+				client.stats.Aborts = make(map[Abort]uint32)
+			}
+			client.stats.Aborts[abort]++
+		}
+	}
+	hook := client.transferHook
+	client.statsMu.Unlock()
+	if hook != nil {
+		hook(result)
+	}
+}
@@ -0,0 +1,194 @@
+package sdo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// A Transfer is a single SDO upload or download, as returned by e.g.
+// [SDOClient.NewRawReaderWith] or [SDOClient.NewRawWriterWith].
+// It implements [io.Reader], [io.Writer], [io.ReaderFrom], [io.WriterTo] and [io.Closer].
+//
+// The protocol state is kept by the [SDOClient], so a client handles one
+// transfer at a time : creating a new transfer waits until the previous one
+// is finished, so that several goroutines can safely share the same client.
+// A transfer is finished when it completes, fails or is closed. A transfer
+// that is not read or written to completion must be closed, otherwise the
+// client stays reserved and new transfers block until their context is done.
+// A Transfer itself should only be used by one goroutine.
+type Transfer struct {
+	client      *SDOClient
+	ctx         context.Context
+	nodeId      uint8
+	index       uint16
+	subindex    uint8
+	upload      bool
+	done        bool
+	err         error
+	onProgress  func(progress Progress)
+	start       time.Time
+	transferred uint32
+	size        uint32
+}
+
+// Progress of a [Transfer], see [TransferOptions]
+type Progress struct {
+	Transferred uint32        // Bytes transferred so far
+	Size        uint32        // Total size if indicated, 0 otherwise
+	Elapsed     time.Duration // Time since transfer creation
+}
+
+// Mean transfer rate in bytes per second
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Transferred) / p.Elapsed.Seconds()
+}
+
+// TransferError is returned when a [Transfer] is aborted, either by the server
+// or by the client. It gives the context of the failed transfer and wraps
+// the [Abort] code, so that errors.Is & errors.As can be used e.g.
+//
+//	errors.Is(err, sdo.AbortNotExist)
+type TransferError struct {
+	Abort       Abort
+	NodeId      uint8
+	Index       uint16
+	Subindex    uint8
+	Upload      bool   // Upload (read) if true, download (write) otherwise
+	Transferred uint32 // Bytes transferred before the abort
+}
+
+func (e *TransferError) Error() string {
+	direction := "download"
+	if e.Upload {
+		direction = "upload"
+	}
+	return fmt.Sprintf("sdo %v node x%x x%x:%02x failed after %v bytes : %v",
+		direction, e.NodeId, e.Index, e.Subindex, e.Transferred, e.Abort)
+}
+
+func (e *TransferError) Unwrap() error {
+	return e.Abort
+}
+
+// Reserve the client & setup the server for a new transfer.
+// Waits for the on-going transfer (if any) to finish, or for ctx to be done.
+func (client *SDOClient) newTransfer(ctx context.Context, nodeId uint8, index uint16, subindex uint8, upload bool, opts TransferOptions) (*Transfer, error) {
+	select {
+	case client.transferSlot <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	tr := &Transfer{
+		client:     client,
+		ctx:        ctx,
+		nodeId:     nodeId,
+		index:      index,
+		subindex:   subindex,
+		upload:     upload,
+		onProgress: opts.Progress,
+		start:      time.Now(),
+	}
+	cobIdClientToServer := uint32(ClientServiceId) + uint32(nodeId)
+	cobIdServerToClient := uint32(ServerServiceId) + uint32(nodeId)
+	client.mu.Lock()
+	if client.channelClientToServer != 0 {
+		cobIdClientToServer, cobIdServerToClient = client.channelClientToServer, client.channelServerToClient
+	}
+	client.mu.Unlock()
+	err := client.setupServer(cobIdClientToServer, cobIdServerToClient, nodeId)
+	if err != nil {
+		return nil, tr.finish(err)
+	}
+	return tr, nil
+}
+
+// Mark transfer as finished & release the client for the next transfer.
+// SDO aborts are returned as a [TransferError].
+func (tr *Transfer) finish(err error) error {
+	if tr.done {
+		return err
+	}
+	tr.done = true
+	tr.client.recordTransfer(TransferResult{
+		NodeId:      tr.nodeId,
+		Index:       tr.index,
+		Subindex:    tr.subindex,
+		Upload:      tr.upload,
+		Transferred: tr.transferred,
+		Duration:    time.Since(tr.start),
+		Err:         err,
+	})
+	if abort, ok := err.(Abort); ok {
+		err = &TransferError{
+			Abort:       abort,
+			NodeId:      tr.nodeId,
+			Index:       tr.index,
+			Subindex:    tr.subindex,
+			Upload:      tr.upload,
+			Transferred: tr.transferred,
+		}
+	}
+	tr.err = err
+	<-tr.client.transferSlot
+	return err
+}
+
+// Update transferred size & report progress if it changed
+func (tr *Transfer) update(transferred uint32, size uint32) {
+	if transferred == tr.transferred && size == tr.size {
+		return
+	}
+	tr.transferred = transferred
+	tr.size = size
+	if tr.onProgress != nil {
+		tr.onProgress(Progress{Transferred: transferred, Size: size, Elapsed: time.Since(tr.start)})
+	}
+}
+
+// Number of bytes transferred so far. SDO transfers can not be resumed
+// at an offset, see [SDOClient.UploadTo] & [SDOClient.DownloadFrom] for
+// resuming a transfer after an abort.
+func (tr *Transfer) Transferred() uint32 {
+	return tr.transferred
+}
+
+// Close the transfer. If it is not finished yet, it is aborted
+// and an SDO abort is sent to the server. Closing a finished transfer
+// does nothing.
+func (tr *Transfer) Close() error {
+	if tr.done {
+		return nil
+	}
+	tr.client.cancel(AbortGeneral)
+	tr.finish(ErrTransferFinished)
+	return nil
+}
+
+// Wait for next processing cycle. If context is done, the on-going transfer
+// is aborted and context error is returned.
+func (tr *Transfer) wait() error {
+	period := time.Duration(tr.client.processingPeriodUs) * time.Microsecond
+	if tr.client.stepper != nil && tr.ctx.Err() == nil {
+		tr.client.stepper(period)
+		return nil
+	}
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-tr.ctx.Done():
+		err := tr.ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			tr.client.cancel(AbortTimeout)
+		} else {
+			tr.client.cancel(AbortGeneral)
+		}
+		return err
+	}
+}
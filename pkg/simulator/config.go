@@ -0,0 +1,178 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Configuration of the simulated devices, e.g. loaded from a JSON file with [LoadConfig].
+// Indexes, subindexes & codes are strings parsed like Go integer literals (e.g. "0x6401"),
+// durations are parsed with [time.ParseDuration] (e.g. "1.5s").
+type Config struct {
+	Devices []DeviceConfig `json:"devices"`
+}
+
+// Devices created from the same EDS file, one per node id
+type DeviceConfig struct {
+	EDS        string            `json:"eds"`
+	NodeIds    []uint8           `json:"node-ids"`
+	Generators []GeneratorConfig `json:"generators,omitempty"`
+	Faults     []FaultConfig     `json:"faults,omitempty"`
+}
+
+// Configuration of a [Generator], Type is one of "constant", "ramp", "sine" or "random-walk"
+type GeneratorConfig struct {
+	Index    string `json:"index"`
+	Subindex string `json:"subindex,omitempty"`
+	Type     string `json:"type"`
+	// Constant
+	Value float64 `json:"value,omitempty"`
+	// Ramp
+	From float64 `json:"from,omitempty"`
+	To   float64 `json:"to,omitempty"`
+	// Sine
+	Offset    float64 `json:"offset,omitempty"`
+	Amplitude float64 `json:"amplitude,omitempty"`
+	// Ramp & sine
+	Period string `json:"period,omitempty"`
+	// Random walk, seed defaults to the node id
+	Start float64 `json:"start,omitempty"`
+	Step  float64 `json:"step,omitempty"`
+	Min   float64 `json:"min,omitempty"`
+	Max   float64 `json:"max,omitempty"`
+	Seed  *int64  `json:"seed,omitempty"`
+}
+
+// Configuration of a [Fault], Type is one of "heartbeat" or "emcy"
+type FaultConfig struct {
+	Type      string `json:"type"`
+	At        string `json:"at"`
+	Duration  string `json:"duration,omitempty"`
+	ErrorBit  string `json:"error-bit,omitempty"`
+	ErrorCode string `json:"error-code,omitempty"`
+	InfoCode  string `json:"info-code,omitempty"`
+}
+
+// Load a JSON configuration file
+func LoadConfig(path string) (Config, error) {
+	var config Config
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	err = json.Unmarshal(raw, &config)
+	return config, err
+}
+
+func parseUint(value string, defaultValue uint64, bitSize int) (uint64, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.ParseUint(value, 0, bitSize)
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// Create the generator, nodeId is the default seed of random walks
+func (c GeneratorConfig) Generator(nodeId uint8) (Generator, error) {
+	period, err := parseDuration(c.Period)
+	if err != nil {
+		return nil, err
+	}
+	switch c.Type {
+	case "constant":
+		return Constant(c.Value), nil
+	case "ramp":
+		return Ramp{From: c.From, To: c.To, Period: period}, nil
+	case "sine":
+		return Sine{Offset: c.Offset, Amplitude: c.Amplitude, Period: period}, nil
+	case "random-walk":
+		seed := int64(nodeId)
+		if c.Seed != nil {
+			seed = *c.Seed
+		}
+		return NewRandomWalk(c.Start, c.Step, c.Min, c.Max, seed), nil
+	default:
+		return nil, fmt.Errorf("unknown generator type : %v", c.Type)
+	}
+}
+
+// Create the fault
+func (c FaultConfig) Fault() (Fault, error) {
+	var f Fault
+	switch c.Type {
+	case "heartbeat":
+		f.Kind = FaultHeartbeat
+	case "emcy":
+		f.Kind = FaultEMCY
+	default:
+		return f, fmt.Errorf("unknown fault type : %v", c.Type)
+	}
+	var err error
+	if f.At, err = parseDuration(c.At); err != nil {
+		return f, err
+	}
+	if f.Duration, err = parseDuration(c.Duration); err != nil {
+		return f, err
+	}
+	errorBit, err := parseUint(c.ErrorBit, 0, 8)
+	if err != nil {
+		return f, err
+	}
+	errorCode, err := parseUint(c.ErrorCode, 0, 16)
+	if err != nil {
+		return f, err
+	}
+	infoCode, err := parseUint(c.InfoCode, 0, 32)
+	if err != nil {
+		return f, err
+	}
+	f.ErrorBit, f.ErrorCode, f.InfoCode = byte(errorBit), uint16(errorCode), uint32(infoCode)
+	return f, nil
+}
+
+// Add the devices of the configuration, with their generators & faults
+func (s *Simulator) Apply(config Config) error {
+	for _, deviceConfig := range config.Devices {
+		for _, nodeId := range deviceConfig.NodeIds {
+			device, err := s.AddDevice(nodeId, deviceConfig.EDS)
+			if err != nil {
+				return fmt.Errorf("node x%x : %w", nodeId, err)
+			}
+			for _, c := range deviceConfig.Generators {
+				index, err := parseUint(c.Index, 0, 16)
+				if err != nil {
+					return err
+				}
+				subindex, err := parseUint(c.Subindex, 0, 8)
+				if err != nil {
+					return err
+				}
+				generator, err := c.Generator(nodeId)
+				if err != nil {
+					return err
+				}
+				err = device.AddGenerator(uint16(index), int(subindex), generator)
+				if err != nil {
+					return fmt.Errorf("node x%x, x%x|x%x : %w", nodeId, index, subindex, err)
+				}
+			}
+			for _, c := range deviceConfig.Faults {
+				f, err := c.Fault()
+				if err != nil {
+					return err
+				}
+				device.AddFault(f)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package simulator
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// A Generator produces the value of a simulated OD entry
+type Generator interface {
+	// Value at time t, elapsed since start of the simulation
+	Value(t time.Duration) float64
+}
+
+// Constant value
+type Constant float64
+
+func (c Constant) Value(t time.Duration) float64 {
+	return float64(c)
+}
+
+// Ramp goes linearly from From to To during Period, then starts again
+type Ramp struct {
+	From   float64
+	To     float64
+	Period time.Duration
+}
+
+func (r Ramp) Value(t time.Duration) float64 {
+	if r.Period <= 0 {
+		return r.From
+	}
+	phase := float64(t%r.Period) / float64(r.Period)
+	return r.From + (r.To-r.From)*phase
+}
+
+// Sine oscillates around Offset with the given Amplitude & Period
+type Sine struct {
+	Offset    float64
+	Amplitude float64
+	Period    time.Duration
+}
+
+func (s Sine) Value(t time.Duration) float64 {
+	if s.Period <= 0 {
+		return s.Offset
+	}
+	return s.Offset + s.Amplitude*math.Sin(2*math.Pi*float64(t)/float64(s.Period))
+}
+
+// RandomWalk moves by a random step in [-MaxStep, MaxStep] every time it is
+// evaluated, staying within [Min, Max]. A given seed always gives the same values.
+type RandomWalk struct {
+	mu      sync.Mutex
+	rng     *rand.Rand
+	value   float64
+	maxStep float64
+	min     float64
+	max     float64
+}
+
+// Create a new [RandomWalk] starting at start
+func NewRandomWalk(start float64, maxStep float64, min float64, max float64, seed int64) *RandomWalk {
+	return &RandomWalk{
+		rng:     rand.New(rand.NewSource(seed)),
+		value:   start,
+		maxStep: maxStep,
+		min:     min,
+		max:     max,
+	}
+}
+
+func (w *RandomWalk) Value(t time.Duration) float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.value += (w.rng.Float64()*2 - 1) * w.maxStep
+	w.value = max(min(w.value, w.max), w.min)
+	return w.value
+}
@@ -0,0 +1,340 @@
+// Package simulator simulates CANopen devices, so that master applications
+// can be developed without hardware. Devices are local nodes created from
+// EDS files, with OD entries driven by value generators (ramps, sine, random walk)
+// and scheduled faults (lost heartbeats, EMCY).
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/node"
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+// Default period for updating generated values, see [Simulator.Run]
+const DefaultUpdatePeriod = 10 * time.Millisecond
+
+var ErrDeviceExists = errors.New("a device already exists with this node id")
+
+// Kind of a [Fault]
+type FaultKind uint8
+
+const (
+	// Heartbeat producer is stopped during the fault
+	FaultHeartbeat FaultKind = 1
+	// An EMCY error is reported during the fault
+	FaultEMCY FaultKind = 2
+)
+
+func (kind FaultKind) String() string {
+	switch kind {
+	case FaultHeartbeat:
+		return "heartbeat"
+	case FaultEMCY:
+		return "emcy"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint8(kind))
+	}
+}
+
+// A Fault injected into a device at a given time of the simulation.
+// A fault without duration is never cleared.
+type Fault struct {
+	Kind      FaultKind
+	At        time.Duration
+	Duration  time.Duration
+	ErrorBit  byte   // For [FaultEMCY], see [emergency.EMCY.ErrorReport]
+	ErrorCode uint16 // For [FaultEMCY]
+	InfoCode  uint32 // For [FaultEMCY]
+}
+
+type behavior struct {
+	entry     *od.Entry
+	variable  *od.Variable
+	generator Generator
+}
+
+type fault struct {
+	Fault
+	active  bool
+	cleared bool
+}
+
+// A Device is a simulated node
+type Device struct {
+	*node.LocalNode
+	logger            *slog.Logger
+	mu                sync.Mutex
+	updateMu          sync.Mutex // Serializes [Device.Update]
+	behaviors         []behavior
+	faults            []*fault
+	heartbeatPeriod   uint16
+	heartbeatsDropped bool
+}
+
+// Simulator runs simulated devices on a network
+type Simulator struct {
+	logger  *slog.Logger
+	network *network.Network
+	mu      sync.Mutex
+	devices map[uint8]*Device
+}
+
+// Create a new [Simulator] on a connected network
+func NewSimulator(network *network.Network, logger *slog.Logger) *Simulator {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Simulator{
+		logger:  logger.With("service", "[SIM]"),
+		network: network,
+		devices: make(map[uint8]*Device),
+	}
+}
+
+// Add a device with the given node id. odict is either a path to an EDS file
+// or an OD, like for [network.Network.CreateLocalNode].
+func (s *Simulator) AddDevice(nodeId uint8, odict any) (*Device, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.devices[nodeId]; ok {
+		return nil, ErrDeviceExists
+	}
+	local, err := s.network.CreateLocalNode(nodeId, odict)
+	if err != nil {
+		return nil, err
+	}
+	device := &Device{LocalNode: local, logger: s.logger.With("id", nodeId)}
+	s.devices[nodeId] = device
+	return device, nil
+}
+
+// Get a device by its node id
+func (s *Simulator) Device(nodeId uint8) (*Device, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.devices[nodeId]
+	return device, ok
+}
+
+// All the devices, sorted by node id
+func (s *Simulator) Devices() []*Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	devices := make([]*Device, 0, len(s.devices))
+	for _, device := range s.devices {
+		devices = append(devices, device)
+	}
+	slices.SortFunc(devices, func(a, b *Device) int { return int(a.GetID()) - int(b.GetID()) })
+	return devices
+}
+
+// Update generated values & faults of all the devices,
+// t is the time elapsed since start of the simulation
+func (s *Simulator) Update(t time.Duration) {
+	for _, device := range s.Devices() {
+		device.Update(t)
+	}
+}
+
+// Update the devices every period until ctx is cancelled
+func (s *Simulator) Run(ctx context.Context, period time.Duration) error {
+	if period <= 0 {
+		period = DefaultUpdatePeriod
+	}
+	start := time.Now()
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	s.Update(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.Update(time.Since(start))
+		}
+	}
+}
+
+// Drive the value of an OD entry with a generator. The entry
+// should be a numeric variable, values are rounded & clamped to its data type.
+func (d *Device) AddGenerator(index any, subindex any, generator Generator) error {
+	entry := d.GetOD().Index(index)
+	if entry == nil {
+		return od.ErrIdxNotExist
+	}
+	variable, err := entry.SubIndex(subindex)
+	if err != nil {
+		return err
+	}
+	if _, err := encodeValue(0, variable.DataType); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.behaviors = append(d.behaviors, behavior{entry: entry, variable: variable, generator: generator})
+	return nil
+}
+
+// Schedule a fault
+func (d *Device) AddFault(f Fault) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.faults = append(d.faults, &fault{Fault: f})
+}
+
+// Update generated values & faults, t is the time
+// elapsed since start of the simulation
+func (d *Device) Update(t time.Duration) {
+	d.updateMu.Lock()
+	defer d.updateMu.Unlock()
+	d.mu.Lock()
+	behaviors := slices.Clone(d.behaviors)
+	faults := slices.Clone(d.faults)
+	d.mu.Unlock()
+
+	for _, b := range behaviors {
+		data, err := encodeValue(b.generator.Value(t), b.variable.DataType)
+		if err == nil {
+			err = b.entry.WriteExactly(b.variable.SubIndex, data, false)
+		}
+		if err != nil {
+			d.logger.Warn("failed to update value", "index", fmt.Sprintf("x%x", b.entry.Index), "subindex", b.variable.SubIndex, "error", err)
+		}
+	}
+	for _, f := range faults {
+		var err error
+		switch {
+		case !f.active && !f.cleared && t >= f.At:
+			f.active = true
+			d.logger.Info("fault injected", "kind", f.Kind)
+			err = d.setFault(f.Fault, true)
+		case f.active && f.Duration > 0 && t >= f.At+f.Duration:
+			f.active = false
+			f.cleared = true
+			d.logger.Info("fault cleared", "kind", f.Kind)
+			err = d.setFault(f.Fault, false)
+		}
+		if err != nil {
+			d.logger.Warn("failed to inject fault", "kind", f.Kind, "error", err)
+		}
+	}
+}
+
+func (d *Device) setFault(f Fault, active bool) error {
+	switch f.Kind {
+	case FaultHeartbeat:
+		if active {
+			return d.DropHeartbeats()
+		}
+		return d.ResumeHeartbeats()
+	case FaultEMCY:
+		if active {
+			d.EMCY.ErrorReport(f.ErrorBit, f.ErrorCode, f.InfoCode)
+		} else {
+			d.EMCY.ErrorReset(f.ErrorBit, f.InfoCode)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown fault kind : %v", f.Kind)
+	}
+}
+
+// Stop sending heartbeats, as if the device was lost
+func (d *Device) DropHeartbeats() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.heartbeatsDropped {
+		return nil
+	}
+	period, err := d.HeartbeatPeriod()
+	if err != nil {
+		return err
+	}
+	err = d.SetHeartbeatPeriod(0)
+	if err != nil {
+		return err
+	}
+	d.heartbeatPeriod = period
+	d.heartbeatsDropped = true
+	return nil
+}
+
+// Send heartbeats again after [Device.DropHeartbeats]
+func (d *Device) ResumeHeartbeats() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.heartbeatsDropped {
+		return nil
+	}
+	d.heartbeatsDropped = false
+	return d.SetHeartbeatPeriod(d.heartbeatPeriod)
+}
+
+// Send an EMCY error, see [emergency.EMCY.ErrorReport]
+func (d *Device) SendEMCY(errorBit byte, errorCode uint16, infoCode uint32) {
+	d.EMCY.ErrorReport(errorBit, errorCode, infoCode)
+}
+
+// Clear an EMCY error, see [emergency.EMCY.ErrorReset]
+func (d *Device) ClearEMCY(errorBit byte, infoCode uint32) {
+	d.EMCY.ErrorReset(errorBit, infoCode)
+}
+
+// Range of an integer data type
+func integerRange(dataType uint8) (min float64, max float64, ok bool) {
+	switch dataType {
+	case od.BOOLEAN:
+		return 0, 1, true
+	case od.UNSIGNED8:
+		return 0, math.MaxUint8, true
+	case od.UNSIGNED16:
+		return 0, math.MaxUint16, true
+	case od.UNSIGNED32:
+		return 0, math.MaxUint32, true
+	case od.UNSIGNED64:
+		return 0, math.MaxUint64, true
+	case od.INTEGER8:
+		return math.MinInt8, math.MaxInt8, true
+	case od.INTEGER16:
+		return math.MinInt16, math.MaxInt16, true
+	case od.INTEGER32:
+		return math.MinInt32, math.MaxInt32, true
+	case od.INTEGER64:
+		return math.MinInt64, math.MaxInt64, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// Encode a generated value to the given data type
+func encodeValue(value float64, dataType uint8) ([]byte, error) {
+	switch dataType {
+	case od.REAL32, od.REAL64:
+		return od.EncodeFromString(strconv.FormatFloat(value, 'g', -1, 64), dataType, 0)
+	}
+	low, high, ok := integerRange(dataType)
+	if !ok {
+		return nil, od.ErrTypeMismatch
+	}
+	value = max(min(math.Round(value), high), low)
+	if low < 0 {
+		if value >= math.MaxInt64 {
+			return od.EncodeFromString(strconv.FormatInt(math.MaxInt64, 10), dataType, 0)
+		}
+		return od.EncodeFromString(strconv.FormatInt(int64(value), 10), dataType, 0)
+	}
+	if value >= math.MaxUint64 {
+		return od.EncodeFromString(strconv.FormatUint(math.MaxUint64, 10), dataType, 0)
+	}
+	return od.EncodeFromString(strconv.FormatUint(uint64(value), 10), dataType, 0)
+}
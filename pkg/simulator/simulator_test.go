@@ -0,0 +1,189 @@
+package simulator
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/heartbeat"
+	"github.com/samsamfire/gocanopen/pkg/network"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerators(t *testing.T) {
+	assert.Equal(t, 5.0, Constant(5).Value(time.Hour))
+	ramp := Ramp{From: 10, To: 20, Period: time.Second}
+	assert.Equal(t, 10.0, ramp.Value(0))
+	assert.Equal(t, 15.0, ramp.Value(500*time.Millisecond))
+	assert.Equal(t, 10.0, ramp.Value(time.Second))
+	sine := Sine{Offset: 100, Amplitude: 10, Period: time.Second}
+	assert.InDelta(t, 100, sine.Value(0), 1e-9)
+	assert.InDelta(t, 110, sine.Value(250*time.Millisecond), 1e-9)
+	assert.InDelta(t, 90, sine.Value(750*time.Millisecond), 1e-9)
+	// Same seed, same values
+	a := NewRandomWalk(0, 5, -10, 10, 42)
+	b := NewRandomWalk(0, 5, -10, 10, 42)
+	for i := range 100 {
+		value := a.Value(time.Duration(i))
+		assert.Equal(t, value, b.Value(time.Duration(i)))
+		assert.True(t, value >= -10 && value <= 10)
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	data, err := encodeValue(300.4, od.UNSIGNED8)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0xFF}, data)
+	data, err = encodeValue(-1.6, od.INTEGER16)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0xFE, 0xFF}, data)
+	data, err = encodeValue(1e30, od.INTEGER64)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F}, data)
+	_, err = encodeValue(1, od.VISIBLE_STRING)
+	assert.Equal(t, od.ErrTypeMismatch, err)
+}
+
+type emcyRecorder struct {
+	mu          sync.Mutex
+	emergencies []network.Emergency
+}
+
+func (r *emcyRecorder) record(emcy network.Emergency) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emergencies = append(r.emergencies, emcy)
+}
+
+func (r *emcyRecorder) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.emergencies)
+}
+
+func TestSimulator(t *testing.T) {
+	sim := network.NewSimulation()
+	defer sim.Close()
+	master, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	devices, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	simulator := NewSimulator(devices, nil)
+	device, err := simulator.AddDevice(0x20, od.Default())
+	assert.Nil(t, err)
+	_, err = simulator.AddDevice(0x20, od.Default())
+	assert.Equal(t, ErrDeviceExists, err)
+	monitor, err := simulator.AddDevice(0x21, od.Default())
+	assert.Nil(t, err)
+	update := func(d time.Duration) {
+		sim.Advance(d)
+		simulator.Update(sim.Elapsed())
+	}
+
+	t.Run("generators", func(t *testing.T) {
+		assert.Nil(t, device.AddGenerator(0x2006, 0, Ramp{From: 0, To: 1000, Period: time.Second}))
+		assert.Nil(t, device.AddGenerator(0x2003, 0, Constant(-1234)))
+		assert.Nil(t, device.AddGenerator(0x2008, 0, Constant(1.5)))
+		assert.Equal(t, od.ErrTypeMismatch, device.AddGenerator(0x2009, 0, Constant(1)))
+		assert.Equal(t, od.ErrIdxNotExist, device.AddGenerator(0x5555, 0, Constant(1)))
+		start := sim.Elapsed()
+		update(500*time.Millisecond - start%time.Second)
+		value, err := master.ReadUint16(0x20, 0x2006, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 500, value)
+		value, err = master.ReadUint16(0x20, 0x2003, 0)
+		assert.Nil(t, err)
+		assert.EqualValues(t, -1234, int16(value))
+		raw, err := master.ReadUint32(0x20, 0x2008, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, float32(1.5), math.Float32frombits(raw))
+	})
+
+	t.Run("heartbeat fault", func(t *testing.T) {
+		timeouts := 0
+		monitor.HBConsumer.OnEvent(func(event uint8, index uint8, nodeId uint8, nmtState uint8) {
+			if event == heartbeat.EventTimeout {
+				timeouts++
+			}
+		})
+		assert.Nil(t, monitor.Configurator().WriteMonitoredNode(1, 0x20, 100))
+		assert.Nil(t, device.SetHeartbeatPeriod(20))
+		now := sim.Elapsed()
+		device.AddFault(Fault{Kind: FaultHeartbeat, At: now + 200*time.Millisecond, Duration: 500 * time.Millisecond})
+		for range 25 {
+			update(10 * time.Millisecond)
+		}
+		assert.Equal(t, 0, timeouts)
+		period, err := device.HeartbeatPeriod()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, period)
+		for range 55 {
+			update(10 * time.Millisecond)
+		}
+		assert.Equal(t, 1, timeouts)
+		period, err = device.HeartbeatPeriod()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 20, period)
+	})
+
+	t.Run("emcy fault", func(t *testing.T) {
+		recorder := &emcyRecorder{}
+		assert.Nil(t, master.OnEmergency(0x20, recorder.record))
+		device.AddFault(Fault{Kind: FaultEMCY, At: sim.Elapsed() + 50*time.Millisecond, ErrorBit: 0x28, ErrorCode: 0x8130, InfoCode: 0x1234})
+		update(10 * time.Millisecond)
+		assert.Equal(t, 0, recorder.len())
+		update(50 * time.Millisecond)
+		sim.Advance(10 * time.Millisecond)
+		assert.Equal(t, 1, recorder.len())
+		emcy := master.Emergencies(0x20)[0]
+		assert.EqualValues(t, 0x8130, emcy.ErrorCode)
+		assert.EqualValues(t, 0x28, emcy.ErrorBit)
+		assert.EqualValues(t, 0x1234, emcy.InfoCode)
+	})
+}
+
+func TestConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "simulation.json")
+	err := os.WriteFile(path, []byte(`{
+		"devices": [{
+			"eds": "../od/base.eds",
+			"node-ids": [16, 17],
+			"generators": [
+				{"index": "0x2006", "type": "sine", "offset": 1000, "amplitude": 500, "period": "2s"},
+				{"index": "0x2005", "type": "random-walk", "start": 50, "step": 5, "min": 0, "max": 100}
+			],
+			"faults": [{"type": "emcy", "at": "1s", "duration": "500ms", "error-bit": "0x28", "error-code": "0x8130"}]
+		}]
+	}`), 0o644)
+	assert.Nil(t, err)
+	config, err := LoadConfig(path)
+	assert.Nil(t, err)
+	assert.Len(t, config.Devices, 1)
+	assert.Equal(t, []uint8{16, 17}, config.Devices[0].NodeIds)
+
+	f, err := config.Devices[0].Faults[0].Fault()
+	assert.Nil(t, err)
+	assert.Equal(t, Fault{Kind: FaultEMCY, At: time.Second, Duration: 500 * time.Millisecond, ErrorBit: 0x28, ErrorCode: 0x8130}, f)
+	_, err = FaultConfig{Type: "unknown"}.Fault()
+	assert.NotNil(t, err)
+	_, err = GeneratorConfig{Type: "unknown"}.Generator(1)
+	assert.NotNil(t, err)
+
+	sim := network.NewSimulation()
+	defer sim.Close()
+	devices, err := sim.NewNetwork()
+	assert.Nil(t, err)
+	simulator := NewSimulator(devices, nil)
+	assert.Nil(t, simulator.Apply(config))
+	assert.Len(t, simulator.Devices(), 2)
+	assert.Equal(t, ErrDeviceExists, errors.Unwrap(simulator.Apply(config)))
+	simulator.Update(0)
+	value, err := simulator.Devices()[0].ReadUint(0x2006, 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1000, value)
+}
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"encoding/binary"
+	"time"
 
 	canopen "github.com/samsamfire/gocanopen"
 	"github.com/samsamfire/gocanopen/pkg/od"
@@ -66,6 +67,8 @@ func writeEntry1006(stream *od.Stream, data []byte, countWritten *uint16) error
 
 	cyclePeriodUs := binary.LittleEndian.Uint32(data)
 	sync.logger.Info("updating communication cycle", "periodMs", cyclePeriodUs/1000)
+	// Don't account the reconfiguration as jitter or missed SYNCs
+	sync.lastSync = time.Time{}
 	return od.WriteEntryDefault(stream, data, countWritten)
 }
 
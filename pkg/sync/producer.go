@@ -0,0 +1,100 @@
+package sync
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/samsamfire/gocanopen/pkg/od"
+)
+
+var (
+	ErrInvalidPeriod          = errors.New("SYNC period should be a positive multiple of 1µs, up to 4294s")
+	ErrInvalidCounterOverflow = errors.New("SYNC counter overflow should be 0 or 2 - 240")
+)
+
+// CommCyclePeriodUs converts a SYNC period to the value of the communication
+// cycle period (0x1006). period should be a multiple of 1µs, 0 disables SYNC.
+func CommCyclePeriodUs(period time.Duration) (uint32, error) {
+	if period < 0 || period%time.Microsecond != 0 || period/time.Microsecond > math.MaxUint32 {
+		return 0, ErrInvalidPeriod
+	}
+	return uint32(period / time.Microsecond), nil
+}
+
+// Start producing SYNC messages (0x1005 producer bit), they are sent
+// every communication cycle period once it is not 0, see [SYNC.SetPeriod].
+func (sync *SYNC) Start() error {
+	return sync.setProducer(true)
+}
+
+// Stop producing SYNC messages, the node becomes a SYNC consumer again
+func (sync *SYNC) Stop() error {
+	return sync.setProducer(false)
+}
+
+func (sync *SYNC) setProducer(producer bool) error {
+	cobId, err := sync.entry1005.Uint32(0)
+	if err != nil {
+		return err
+	}
+	if producer {
+		cobId |= 1 << 30
+	} else {
+		cobId &^= 1 << 30
+	}
+	return sync.entry1005.PutUint32(0, cobId, false)
+}
+
+// Returns true if this node is the SYNC producer
+func (sync *SYNC) IsProducer() bool {
+	sync.mu.Lock()
+	defer sync.mu.Unlock()
+	return sync.isProducer
+}
+
+// Communication cycle period (0x1006)
+func (sync *SYNC) Period() (time.Duration, error) {
+	periodUs, err := sync.commCyclePeriod.Uint32(0)
+	return time.Duration(periodUs) * time.Microsecond, err
+}
+
+// Update the communication cycle period (0x1006) at runtime, this is the SYNC period
+// if producer or the SYNC timeout (1.5 x period) if consumer. 0 disables SYNC.
+func (sync *SYNC) SetPeriod(period time.Duration) error {
+	periodUs, err := CommCyclePeriodUs(period)
+	if err != nil {
+		return err
+	}
+	return sync.commCyclePeriod.PutUint32(0, periodUs, false)
+}
+
+// Update the synchronous counter overflow (0x1019) at runtime, 0 for SYNC
+// messages without counter or 2 - 240. CiA 301 only allows this while the
+// communication cycle period is 0 so it is disabled during the update.
+func (sync *SYNC) SetCounterOverflow(counterOverflow uint8) error {
+	if sync.entry1019 == nil {
+		return od.ErrIdxNotExist
+	}
+	if counterOverflow == 1 || counterOverflow > 240 {
+		return ErrInvalidCounterOverflow
+	}
+	periodUs, err := sync.commCyclePeriod.Uint32(0)
+	if err != nil {
+		return err
+	}
+	if periodUs != 0 {
+		err = sync.commCyclePeriod.PutUint32(0, 0, false)
+		if err != nil {
+			return err
+		}
+	}
+	err = sync.entry1019.PutUint8(0, counterOverflow, false)
+	if periodUs != 0 {
+		errRestore := sync.commCyclePeriod.PutUint32(0, periodUs, false)
+		if err == nil {
+			err = errRestore
+		}
+	}
+	return err
+}
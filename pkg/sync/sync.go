@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	s "sync"
+	"time"
 
 	canopen "github.com/samsamfire/gocanopen"
 	"github.com/samsamfire/gocanopen/pkg/emergency"
@@ -29,11 +30,33 @@ type SYNC struct {
 	counter             uint8
 	syncIsOutsideWindow bool
 	timer               uint32
+	entry1005           *od.Entry
 	commCyclePeriod     *od.Entry
 	syncWindowLength    *od.Entry
+	entry1019           *od.Entry // Optional
 	isProducer          bool
+	sending             bool // Own SYNC is being sent, see [SYNC.send]
 	cobId               uint32
 	txBuffer            canopen.Frame
+	stats               Stats
+	lastSync            time.Time
+	periodSum           time.Duration
+	periodCount         uint64
+	now                 func() time.Time
+}
+
+// Diagnostic counters of [SYNC], counters are 32 bits and wrap around.
+// Periods are measured between two consecutive SYNC messages, received
+// if consumer or sent if producer, and are zero until two SYNCs were seen.
+type Stats struct {
+	Received   uint32        // SYNC messages received
+	Sent       uint32        // SYNC messages sent, if producer
+	Errors     uint32        // SYNC messages received with a wrong length
+	Missed     uint32        // SYNC messages expected from 0x1006 but not received, if consumer
+	MinPeriod  time.Duration // Shortest measured period
+	MaxPeriod  time.Duration // Longest measured period
+	MeanPeriod time.Duration // Average measured period
+	MaxJitter  time.Duration // Largest deviation of a measured period from 0x1006
 }
 
 // Handle [SYNC] related RX CAN frames
@@ -41,6 +64,10 @@ func (sync *SYNC) Handle(frame canopen.Frame) {
 	sync.mu.Lock()
 	defer sync.mu.Unlock()
 
+	if sync.sending {
+		// Own SYNC, delivered synchronously by the bus
+		return
+	}
 	syncReceived := false
 	if sync.counterOverflow == 0 {
 		if frame.DLC == 0 {
@@ -59,6 +86,12 @@ func (sync *SYNC) Handle(frame canopen.Frame) {
 	if syncReceived {
 		sync.rxToggle = !sync.rxToggle
 		sync.rxNew = true
+		sync.stats.Received++
+		if !sync.isProducer {
+			sync.recordPeriod(true)
+		}
+	} else {
+		sync.stats.Errors++
 	}
 }
 
@@ -75,6 +108,7 @@ func (sync *SYNC) Process(nmtIsPreOrOperational bool, timeDifferenceUs uint32, t
 		sync.receiveError = 0
 		sync.counter = 0
 		sync.timer = 0
+		sync.lastSync = time.Time{}
 		return EventNone
 	}
 
@@ -161,10 +195,61 @@ func (sync *SYNC) send() {
 	sync.timer = 0
 	sync.rxToggle = !sync.rxToggle
 	sync.txBuffer.Data[0] = sync.counter
+	sync.sending = true
 	sync.mu.Unlock()
 	// When listening to own messages, this will trigger Handle to be called
 	// So make sure sync is unlocked before sending
-	_ = sync.Send(sync.txBuffer)
+	err := sync.Send(sync.txBuffer)
+	sync.mu.Lock()
+	sync.sending = false
+	sync.mu.Unlock()
+	if err == nil {
+		sync.mu.Lock()
+		sync.stats.Sent++
+		sync.recordPeriod(false)
+		sync.mu.Unlock()
+	}
+}
+
+// Update period statistics with a new SYNC, sent or received.
+// If countMissed is set, periods spanning several communication
+// cycles are accounted as missed SYNC messages.
+// sync.mu should be held when calling this
+func (sync *SYNC) recordPeriod(countMissed bool) {
+	now := sync.now()
+	last := sync.lastSync
+	sync.lastSync = now
+	if last.IsZero() {
+		return
+	}
+	period := now.Sub(last)
+	if sync.periodCount == 0 || period < sync.stats.MinPeriod {
+		sync.stats.MinPeriod = period
+	}
+	if period > sync.stats.MaxPeriod {
+		sync.stats.MaxPeriod = period
+	}
+	sync.periodSum += period
+	sync.periodCount++
+
+	commCyclePeriod, err := sync.commCyclePeriod.Uint32(0)
+	if err != nil || commCyclePeriod == 0 {
+		return
+	}
+	expected := time.Duration(commCyclePeriod) * time.Microsecond
+	jitter := period - expected
+	if jitter < 0 {
+		jitter = -jitter
+	}
+	if jitter > sync.stats.MaxJitter {
+		sync.stats.MaxJitter = jitter
+	}
+	if countMissed {
+		cycles := (period + expected/2) / expected
+		if cycles > 1 {
+			sync.stats.Missed += uint32(cycles - 1)
+		}
+	}
 }
 
 func (sync *SYNC) Counter() uint8 {
@@ -181,6 +266,26 @@ func (sync *SYNC) RxToggle() bool {
 	return sync.rxToggle
 }
 
+// Get diagnostic counters of [SYNC]
+func (sync *SYNC) Stats() Stats {
+	sync.mu.Lock()
+	defer sync.mu.Unlock()
+	stats := sync.stats
+	if sync.periodCount > 0 {
+		stats.MeanPeriod = sync.periodSum / time.Duration(sync.periodCount)
+	}
+	return stats
+}
+
+// Reset diagnostic counters of [SYNC]
+func (sync *SYNC) ResetStats() {
+	sync.mu.Lock()
+	defer sync.mu.Unlock()
+	sync.stats = Stats{}
+	sync.periodSum = 0
+	sync.periodCount = 0
+}
+
 func (sync *SYNC) CounterOverflow() uint8 {
 	sync.mu.Lock()
 	defer sync.mu.Unlock()
@@ -202,7 +307,7 @@ func NewSYNC(
 		logger = slog.Default()
 	}
 
-	sync := &SYNC{BusManager: bm, logger: logger.With("service", "[SYNC]")}
+	sync := &SYNC{BusManager: bm, logger: logger.With("service", "[SYNC]"), now: time.Now}
 	if entry1005 == nil {
 		return nil, canopen.ErrIllegalArgument
 	}
@@ -216,6 +321,7 @@ func NewSYNC(
 		return nil, canopen.ErrOdParameters
 	}
 	entry1005.AddExtension(sync, od.ReadEntryDefault, writeEntry1005)
+	sync.entry1005 = entry1005
 
 	if entry1006 == nil {
 		sync.logger.Error("not found", "index", "x1006", "name", "COMM CYCLE PERIOD")
@@ -261,6 +367,7 @@ func NewSYNC(
 			syncCounterOverflow = 240
 		}
 		entry1019.AddExtension(sync, od.ReadEntryDefault, writeEntry1019)
+		sync.entry1019 = entry1019
 		sync.logger.Info("sync counter overflow",
 			"index", "x1019",
 			"name", entry1019.Name,
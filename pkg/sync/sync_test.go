@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/can"
+	"github.com/samsamfire/gocanopen/pkg/emergency"
+	"github.com/samsamfire/gocanopen/pkg/od"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPeriodStats(t *testing.T) {
+	bm := canopen.NewBusManager(can.NewLoopbackBus("sync"))
+	odict := od.Default()
+	assert.Nil(t, odict.Index(od.EntryCobIdSYNC).PutUint32(0, 0x80, true))
+	assert.Nil(t, odict.Index(od.EntryCommunicationCyclePeriod).PutUint32(0, 10_000, true))
+	sync, err := NewSYNC(
+		bm,
+		nil,
+		&emergency.EMCY{},
+		odict.Index(od.EntryCobIdSYNC),
+		odict.Index(od.EntryCommunicationCyclePeriod),
+		odict.Index(od.EntrySynchronousWindowLength),
+		nil,
+	)
+	assert.Nil(t, err)
+	now := time.Now()
+	sync.now = func() time.Time { return now }
+
+	frame := canopen.NewFrame(0x80, 0, 0)
+	for _, period := range []time.Duration{0, 9 * time.Millisecond, 11 * time.Millisecond, 10 * time.Millisecond} {
+		now = now.Add(period)
+		sync.Handle(frame)
+	}
+	stats := sync.Stats()
+	assert.EqualValues(t, 4, stats.Received)
+	assert.EqualValues(t, 0, stats.Missed)
+	assert.Equal(t, 9*time.Millisecond, stats.MinPeriod)
+	assert.Equal(t, 11*time.Millisecond, stats.MaxPeriod)
+	assert.Equal(t, 10*time.Millisecond, stats.MeanPeriod)
+	assert.Equal(t, time.Millisecond, stats.MaxJitter)
+
+	// Two SYNCs missing
+	now = now.Add(31 * time.Millisecond)
+	sync.Handle(frame)
+	stats = sync.Stats()
+	assert.EqualValues(t, 2, stats.Missed)
+	assert.Equal(t, 21*time.Millisecond, stats.MaxJitter)
+
+	sync.ResetStats()
+	assert.Equal(t, Stats{}, sync.Stats())
+}
+
+func TestSyncProducerControl(t *testing.T) {
+	bus := can.NewLoopbackBus("sync-producer")
+	assert.Nil(t, bus.Connect())
+	defer bus.Disconnect()
+	bm := canopen.NewBusManager(bus)
+	odict := od.Default()
+	sync, err := NewSYNC(
+		bm,
+		nil,
+		&emergency.EMCY{},
+		odict.Index(od.EntryCobIdSYNC),
+		odict.Index(od.EntryCommunicationCyclePeriod),
+		odict.Index(od.EntrySynchronousWindowLength),
+		odict.Index(od.EntrySynchronousCounterOverflow),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrInvalidPeriod, sync.SetPeriod(1500*time.Nanosecond))
+	assert.Equal(t, ErrInvalidPeriod, sync.SetPeriod(-time.Millisecond))
+	assert.Nil(t, sync.SetPeriod(10*time.Millisecond))
+	period, err := sync.Period()
+	assert.Nil(t, err)
+	assert.Equal(t, 10*time.Millisecond, period)
+
+	assert.Nil(t, sync.Start())
+	assert.True(t, sync.IsProducer())
+	for range 3 {
+		sync.Process(true, 10_000, nil)
+	}
+	assert.EqualValues(t, 3, sync.Stats().Sent)
+
+	// Counter overflow is updated without stopping SYNC
+	assert.Equal(t, ErrInvalidCounterOverflow, sync.SetCounterOverflow(1))
+	assert.Nil(t, sync.SetCounterOverflow(4))
+	assert.EqualValues(t, 4, sync.CounterOverflow())
+	period, err = sync.Period()
+	assert.Nil(t, err)
+	assert.Equal(t, 10*time.Millisecond, period)
+	for range 5 {
+		sync.Process(true, 10_000, nil)
+	}
+	assert.EqualValues(t, 2, sync.Counter())
+
+	assert.Nil(t, sync.Stop())
+	assert.False(t, sync.IsProducer())
+	sync.Process(true, 10_000, nil)
+	assert.EqualValues(t, 8, sync.Stats().Sent)
+}
@@ -14,6 +14,9 @@ import (
 // time origin is 1st of jan 1984
 var timestampOrigin = time.Date(1984, time.January, 1, 0, 0, 0, 0, time.Local)
 
+// Callback on reception of a TIME message, with the decoded time of day
+type TIMERxCallback func(timestamp time.Time)
+
 type TIME struct {
 	*canopen.BusManager
 	logger             *slog.Logger
@@ -28,6 +31,10 @@ type TIME struct {
 	producerIntervalMs uint32
 	producerTimerMs    uint32
 	cobId              uint32
+	hostClock          bool
+	lastProduced       time.Time
+	rxCallback         TIMERxCallback
+	now                func() time.Time
 }
 
 // Handle [TIME] related RX CAN frames
@@ -46,7 +53,19 @@ func (t *TIME) Handle(frame canopen.Frame) {
 // This should be called periodically
 func (t *TIME) Process(nmtIsPreOrOperational bool, timeDifferenceUs uint32) (bool, error) {
 	t.mu.Lock()
-	defer t.mu.Unlock()
+	timestampReceived, err := t.process(nmtIsPreOrOperational, timeDifferenceUs)
+	callback := t.rxCallback
+	timestamp := t.internalTime()
+	t.mu.Unlock()
+
+	// Callback is called without holding the lock so that TIME can be used inside
+	if timestampReceived && callback != nil {
+		callback(timestamp)
+	}
+	return timestampReceived, err
+}
+
+func (t *TIME) process(nmtIsPreOrOperational bool, timeDifferenceUs uint32) (bool, error) {
 	timestampReceived := false
 	if nmtIsPreOrOperational && t.isConsumer {
 		if t.rxNew {
@@ -70,27 +89,47 @@ func (t *TIME) Process(nmtIsPreOrOperational bool, timeDifferenceUs uint32) (boo
 			t.days += 1
 		}
 	}
-	var err error
+	if nmtIsPreOrOperational && t.isProducer && t.producerIntervalMs > 0 && t.hostClock {
+		// Send on each multiple of the interval of the host clock
+		now := t.now()
+		slot := now.Truncate(time.Duration(t.producerIntervalMs) * time.Millisecond)
+		if !slot.After(t.lastProduced) {
+			return timestampReceived, nil
+		}
+		t.lastProduced = slot
+		t.setInternalTime(now)
+		return timestampReceived, t.send()
+	}
 	if nmtIsPreOrOperational && t.isProducer && t.producerIntervalMs > 0 {
 		if t.producerTimerMs < t.producerIntervalMs {
 			t.producerTimerMs += ms
 			return timestampReceived, nil
 		}
 		t.producerTimerMs -= t.producerIntervalMs
-		frame := canopen.NewFrame(t.cobId, 0, 6)
-		binary.LittleEndian.PutUint32(frame.Data[0:4], t.ms)
-		binary.LittleEndian.PutUint16(frame.Data[4:6], t.days)
-		return timestampReceived, t.Send(frame)
+		return timestampReceived, t.send()
 
 	}
 	t.producerTimerMs = t.producerIntervalMs
-	return timestampReceived, err
+	return timestampReceived, nil
+}
+
+func (t *TIME) send() error {
+	frame := canopen.NewFrame(t.cobId, 0, 6)
+	binary.LittleEndian.PutUint32(frame.Data[0:4], t.ms)
+	binary.LittleEndian.PutUint16(frame.Data[4:6], t.days)
+	return t.Send(frame)
 }
 
 // Sets the internal time
 func (t *TIME) SetInternalTime(internalTime time.Time) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	t.setInternalTime(internalTime)
+	t.logger.Info("setting date", "internal time", internalTime)
+	t.logger.Info("since 01/01/1984|00:00:00", "days", t.days, "ms", t.ms)
+}
+
+func (t *TIME) setInternalTime(internalTime time.Time) {
 	// Get the total number of days since 1st of jan 1984
 	days := uint16(internalTime.Sub(timestampOrigin).Hours() / 24)
 	// Get number of milliseconds after midnight
@@ -99,8 +138,6 @@ func (t *TIME) SetInternalTime(internalTime time.Time) {
 	t.residualUs = 0
 	t.ms = uint32(ms)
 	t.days = days
-	t.logger.Info("setting date", "internal time", internalTime)
-	t.logger.Info("since 01/01/1984|00:00:00", "days", days, "ms", ms)
 }
 
 // Update the producer interval time in milliseconds
@@ -111,10 +148,32 @@ func (t *TIME) SetProducerIntervalMs(producerIntervalMs uint32) {
 	t.producerTimerMs = producerIntervalMs
 }
 
+// Produce the host clock instead of the internal time, aligned on
+// multiples of the producer interval, e.g. every full second for 1000ms.
+// The internal time is updated from the host clock on each transmission.
+func (t *TIME) SetHostClockSync(enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.hostClock = enabled
+	t.lastProduced = time.Time{}
+}
+
+// Set a callback on reception of a TIME message, if consumer.
+// This can be used to discipline the host clock from the bus.
+func (t *TIME) SetCallback(callback TIMERxCallback) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rxCallback = callback
+}
+
 // Get the internal time
 func (t *TIME) InternalTime() time.Time {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	return t.internalTime()
+}
+
+func (t *TIME) internalTime() time.Time {
 	internalTime := timestampOrigin.AddDate(0, 0, int(t.days))
 	return internalTime.Add(time.Duration(t.ms)*time.Millisecond + time.Duration(t.residualUs)*time.Microsecond)
 }
@@ -143,7 +202,7 @@ func NewTIME(
 		logger = slog.Default()
 	}
 
-	t := &TIME{BusManager: bm, logger: logger.With("service", "[TIME]")}
+	t := &TIME{BusManager: bm, logger: logger.With("service", "[TIME]"), now: time.Now}
 	// Read param from OD
 	cobId, err := entry1012.Uint32(0)
 	if err != nil {
@@ -6,6 +6,9 @@ import (
 	"testing"
 	"time"
 
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/can"
+	"github.com/samsamfire/gocanopen/pkg/od"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,3 +34,38 @@ func TestSetProducerIntervalMs(t *testing.T) {
 	timeInstance.SetProducerIntervalMs(1000)
 	assert.Equal(t, timeInstance.producerIntervalMs, uint32(1000))
 }
+
+func TestHostClockProducer(t *testing.T) {
+	busProducer, busConsumer := can.NewLoopbackBus("time"), can.NewLoopbackBus("time")
+	bmProducer, bmConsumer := canopen.NewBusManager(busProducer), canopen.NewBusManager(busConsumer)
+	assert.Nil(t, busConsumer.Subscribe(bmConsumer))
+	for _, bus := range []*can.LoopbackBus{busProducer, busConsumer} {
+		assert.Nil(t, bus.Connect())
+		defer bus.Disconnect()
+	}
+	odProducer, odConsumer := od.Default(), od.Default()
+	assert.Nil(t, odConsumer.Index(od.EntryCobIdTIME).PutUint32(0, 0x80000100, true))
+	producer, err := NewTIME(bmProducer, nil, odProducer.Index(od.EntryCobIdTIME), 1000)
+	assert.Nil(t, err)
+	consumer, err := NewTIME(bmConsumer, nil, odConsumer.Index(od.EntryCobIdTIME), 0)
+	assert.Nil(t, err)
+
+	now := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.Local)
+	producer.now = func() time.Time { return now }
+	producer.SetHostClockSync(true)
+	received := []time.Time{}
+	consumer.SetCallback(func(timestamp time.Time) { received = append(received, timestamp) })
+
+	for _, elapsed := range []time.Duration{250, 500, 250, 1000} {
+		now = now.Add(elapsed * time.Millisecond)
+		_, err := producer.Process(true, 0)
+		assert.Nil(t, err)
+		_, err = consumer.Process(true, 0)
+		assert.Nil(t, err)
+	}
+	// Sent on first process, then on each full second
+	assert.Len(t, received, 3)
+	assert.Equal(t, time.Date(2024, time.May, 1, 12, 0, 0, 250_000_000, time.Local), received[0])
+	assert.Equal(t, time.Date(2024, time.May, 1, 12, 0, 1, 0, time.Local), received[1])
+	assert.Equal(t, time.Date(2024, time.May, 1, 12, 0, 2, 0, time.Local), received[2])
+}
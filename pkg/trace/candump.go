@@ -0,0 +1,135 @@
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+)
+
+// A frame of a candump log
+type Record struct {
+	Timestamp time.Time // Zero if not in log
+	Interface string
+	Direction canopen.TraceDirection // 0 if not in log
+	Frame     canopen.Frame
+}
+
+// ParseCandumpLine parses a line in candump log format, as written by
+// [canopen.CandumpTracer] or candump -L, e.g.
+//
+//	(1700000000.123456) can0 601#4000100000000000 T
+//
+// The compact format without timestamp & interface e.g. "601#40001000" is also accepted.
+func ParseCandumpLine(line string) (Record, error) {
+	record := Record{}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return record, fmt.Errorf("empty line")
+	}
+	if strings.HasPrefix(fields[0], "(") {
+		if len(fields) < 3 {
+			return record, fmt.Errorf("invalid line %q", line)
+		}
+		timestamp, err := parseTimestamp(strings.Trim(fields[0], "()"))
+		if err != nil {
+			return record, err
+		}
+		record.Timestamp = timestamp
+		record.Interface = fields[1]
+		fields = fields[2:]
+	}
+	if len(fields) > 1 {
+		switch fields[1] {
+		case "R":
+			record.Direction = canopen.TraceRx
+		case "T":
+			record.Direction = canopen.TraceTx
+		}
+	}
+	frame, err := parseFrame(fields[0])
+	record.Frame = frame
+	return record, err
+}
+
+// Parse seconds with fractional part e.g. "1700000000.123456"
+func parseTimestamp(s string) (time.Time, error) {
+	secStr, fracStr, _ := strings.Cut(s, ".")
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	nsec := int64(0)
+	if fracStr != "" {
+		fracStr = (fracStr + "000000000")[:9]
+		nsec, err = strconv.ParseInt(fracStr, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+		}
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// Parse a frame in compact format e.g. "601#4000100000000000" or "701#R" for RTR
+func parseFrame(s string) (canopen.Frame, error) {
+	idStr, dataStr, ok := strings.Cut(s, "#")
+	if !ok {
+		return canopen.Frame{}, fmt.Errorf("invalid frame %q : missing '#'", s)
+	}
+	id, err := strconv.ParseUint(idStr, 16, 32)
+	if err != nil || id > uint64(canopen.CanSffMask) {
+		return canopen.Frame{}, fmt.Errorf("invalid frame id %q", idStr)
+	}
+	if strings.HasPrefix(dataStr, "R") {
+		return canopen.NewFrame(uint32(id)|canopen.CanRtrFlag, 0, 0), nil
+	}
+	data, err := hex.DecodeString(strings.ReplaceAll(dataStr, ".", ""))
+	if err != nil || len(data) > 8 {
+		return canopen.Frame{}, fmt.Errorf("invalid frame data %q", dataStr)
+	}
+	frame := canopen.NewFrame(uint32(id), 0, uint8(len(data)))
+	copy(frame.Data[:], data)
+	return frame, nil
+}
+
+// DecodeTracer is a [canopen.FrameTracer] writing decoded frames, one per line, e.g.
+//
+//	(1700000000.123456) T SDO request node 0x10 initiate upload 0x6041:00
+type DecodeTracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Create a [DecodeTracer] writing to w
+func NewDecodeTracer(w io.Writer) *DecodeTracer {
+	return &DecodeTracer{w: w}
+}
+
+func (t *DecodeTracer) Trace(timestamp time.Time, direction canopen.TraceDirection, frame canopen.Frame) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := fmt.Fprintln(t.w, FormatRecord(Record{Timestamp: timestamp, Direction: direction, Frame: frame}))
+	return err
+}
+
+// FormatRecord returns the decoded frame of record, prefixed with
+// timestamp & direction when known
+func FormatRecord(record Record) string {
+	var b strings.Builder
+	if !record.Timestamp.IsZero() {
+		fmt.Fprintf(&b, "(%d.%06d) ", record.Timestamp.Unix(), record.Timestamp.Nanosecond()/1000)
+	}
+	switch record.Direction {
+	case canopen.TraceRx:
+		b.WriteString("R ")
+	case canopen.TraceTx:
+		b.WriteString("T ")
+	}
+	b.WriteString(Decode(record.Frame))
+	return b.String()
+}
@@ -0,0 +1,339 @@
+// Package trace decodes CANopen frames into human readable descriptions,
+// e.g. for logs or for analyzing captures made with [canopen.BusManager.SetTracer].
+package trace
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/samsamfire/gocanopen/pkg/emergency"
+	"github.com/samsamfire/gocanopen/pkg/lss"
+	"github.com/samsamfire/gocanopen/pkg/nmt"
+	"github.com/samsamfire/gocanopen/pkg/sdo"
+)
+
+// Kind of CANopen frame, based on the predefined connection set (CiA 301)
+type Kind uint8
+
+const (
+	KindUnknown Kind = iota
+	KindNMT
+	KindSYNC
+	KindEMCY
+	KindTIME
+	KindTPDO
+	KindRPDO
+	KindSDORequest  // Client to server
+	KindSDOResponse // Server to client
+	KindHeartbeat   // Heartbeat, boot-up, node guarding request or response
+	KindLSSMaster
+	KindLSSSlave
+)
+
+var kindMap = map[Kind]string{
+	KindUnknown:     "UNKNOWN",
+	KindNMT:         "NMT",
+	KindSYNC:        "SYNC",
+	KindEMCY:        "EMCY",
+	KindTIME:        "TIME",
+	KindTPDO:        "TPDO",
+	KindRPDO:        "RPDO",
+	KindSDORequest:  "SDO request",
+	KindSDOResponse: "SDO response",
+	KindHeartbeat:   "HB",
+	KindLSSMaster:   "LSS master",
+	KindLSSSlave:    "LSS slave",
+}
+
+func (kind Kind) String() string {
+	description, ok := kindMap[kind]
+	if !ok {
+		return kindMap[KindUnknown]
+	}
+	return description
+}
+
+// Classification of a frame
+type Info struct {
+	Kind   Kind
+	NodeId uint8 // Node id derived from CAN id, 0 if not applicable
+	PDO    uint8 // PDO number (1 to 4) for TPDOs & RPDOs
+}
+
+// Classify a frame from its CAN id, assuming default COB-IDs
+func Classify(frame canopen.Frame) Info {
+	id := frame.ID & canopen.CanSffMask
+	nodeId := uint8(id & 0x7F)
+	function := id &^ 0x7F
+	switch {
+	case id == uint32(nmt.ServiceId):
+		return Info{Kind: KindNMT}
+	case id == 0x80:
+		return Info{Kind: KindSYNC}
+	case function == 0x80:
+		return Info{Kind: KindEMCY, NodeId: nodeId}
+	case id == 0x100:
+		return Info{Kind: KindTIME}
+	case id == lss.ServiceIdMaster:
+		return Info{Kind: KindLSSMaster}
+	case id == lss.ServiceIdSlave:
+		return Info{Kind: KindLSSSlave}
+	case nodeId == 0:
+		return Info{Kind: KindUnknown}
+	case function >= 0x180 && function <= 0x500:
+		// TPDO1 0x180, RPDO1 0x200, TPDO2 0x280, ...
+		kind := KindTPDO
+		if (function-0x180)%0x100 != 0 {
+			kind = KindRPDO
+		}
+		return Info{Kind: kind, NodeId: nodeId, PDO: uint8((function-0x180)/0x100) + 1}
+	case function == 0x580:
+		return Info{Kind: KindSDOResponse, NodeId: nodeId}
+	case function == 0x600:
+		return Info{Kind: KindSDORequest, NodeId: nodeId}
+	case function == 0x700:
+		return Info{Kind: KindHeartbeat, NodeId: nodeId}
+	}
+	return Info{Kind: KindUnknown}
+}
+
+// Decode a frame into a one-line description, e.g.
+//
+//	SDO request node 0x10 initiate upload 0x6041:00
+//
+// Decoding is stateless, so SDO block transfer segments can not be
+// told apart from other SDO commands and are decoded as such.
+func Decode(frame canopen.Frame) string {
+	info := Classify(frame)
+	data := frame.Data[:min(frame.DLC, 8)]
+	rtr := frame.ID&canopen.CanRtrFlag != 0
+	node := fmt.Sprintf("node 0x%02X", info.NodeId)
+	switch info.Kind {
+	case KindNMT:
+		return decodeNMT(data)
+	case KindSYNC:
+		if len(data) > 0 {
+			return fmt.Sprintf("SYNC counter %d", data[0])
+		}
+		return "SYNC"
+	case KindEMCY:
+		return "EMCY " + node + " " + decodeEMCY(data)
+	case KindTIME:
+		if len(data) < 6 {
+			return "TIME " + formatData(data)
+		}
+		ms := binary.LittleEndian.Uint32(data[0:4]) & 0x0FFFFFFF
+		days := binary.LittleEndian.Uint16(data[4:6])
+		return fmt.Sprintf("TIME %d ms after midnight, %d days since 1984", ms, days)
+	case KindTPDO, KindRPDO:
+		if rtr {
+			return fmt.Sprintf("%v%d %v remote request", info.Kind, info.PDO, node)
+		}
+		return fmt.Sprintf("%v%d %v %v", info.Kind, info.PDO, node, formatData(data))
+	case KindSDORequest:
+		return "SDO request " + node + " " + decodeSDORequest(data)
+	case KindSDOResponse:
+		return "SDO response " + node + " " + decodeSDOResponse(data)
+	case KindHeartbeat:
+		return decodeHeartbeat(node, rtr, data)
+	case KindLSSMaster:
+		return "LSS master " + decodeLSS(data)
+	case KindLSSSlave:
+		return "LSS slave " + decodeLSS(data)
+	}
+	return fmt.Sprintf("%03X %v", frame.ID&canopen.CanSffMask, formatData(data))
+}
+
+// Data bytes e.g. "[01 02 03]"
+func formatData(data []byte) string {
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+func decodeNMT(data []byte) string {
+	if len(data) < 2 {
+		return "NMT invalid " + formatData(data)
+	}
+	command, ok := nmt.CommandDescription[nmt.Command(data[0])]
+	if !ok {
+		command = fmt.Sprintf("unknown command 0x%02X", data[0])
+	}
+	if data[1] == 0 {
+		return "NMT " + command + " all nodes"
+	}
+	return fmt.Sprintf("NMT %v node 0x%02X", command, data[1])
+}
+
+func decodeEMCY(data []byte) string {
+	if len(data) < 3 {
+		return "invalid " + formatData(data)
+	}
+	code := binary.LittleEndian.Uint16(data[0:2])
+	if code == 0 {
+		return fmt.Sprintf("error reset, register 0x%02X", data[2])
+	}
+	return fmt.Sprintf("code 0x%04X (%v), register 0x%02X, manufacturer %v",
+		code, emergency.ErrorCodeDescription(code), data[2], formatData(data[3:]))
+}
+
+func decodeHeartbeat(node string, rtr bool, data []byte) string {
+	if rtr {
+		return "node guarding request " + node
+	}
+	if len(data) < 1 {
+		return "HB " + node + " invalid " + formatData(data)
+	}
+	state := data[0] & 0x7F
+	if data[0] == nmt.StateInitializing {
+		return "boot-up " + node
+	}
+	if data[0]&0x80 != 0 {
+		return fmt.Sprintf("node guarding response %v %v toggle 1", node, nmt.StateDescription(state))
+	}
+	return "HB " + node + " " + nmt.StateDescription(state)
+}
+
+// Multiplexer of an SDO frame e.g. "0x6041:00"
+func sdoMux(data []byte) string {
+	return fmt.Sprintf("0x%04X:%02X", binary.LittleEndian.Uint16(data[1:3]), data[3])
+}
+
+func sdoAbort(data []byte) string {
+	abort := sdo.Abort(binary.LittleEndian.Uint32(data[4:8]))
+	return fmt.Sprintf("abort %v code 0x%08X (%v)", sdoMux(data), uint32(abort), abort.Description())
+}
+
+// Initiate upload response or initiate download request
+func sdoInitiate(name string, data []byte) string {
+	expedited := data[0]&0x02 != 0
+	sizeIndicated := data[0]&0x01 != 0
+	switch {
+	case expedited && sizeIndicated:
+		n := 4 - (data[0]>>2)&0x03
+		return fmt.Sprintf("%v %v expedited %v", name, sdoMux(data), formatData(data[4:4+n]))
+	case expedited:
+		return fmt.Sprintf("%v %v expedited %v", name, sdoMux(data), formatData(data[4:8]))
+	case sizeIndicated:
+		return fmt.Sprintf("%v %v size %d", name, sdoMux(data), binary.LittleEndian.Uint32(data[4:8]))
+	}
+	return name + " " + sdoMux(data)
+}
+
+// Download segment request or upload segment response
+func sdoSegment(name string, data []byte) string {
+	toggle := (data[0] >> 4) & 0x01
+	n := 7 - (data[0]>>1)&0x07
+	description := fmt.Sprintf("%v toggle %d %v", name, toggle, formatData(data[1:1+n]))
+	if data[0]&0x01 != 0 {
+		description += " last"
+	}
+	return description
+}
+
+func decodeSDORequest(data []byte) string {
+	if len(data) != 8 {
+		return "invalid " + formatData(data)
+	}
+	switch data[0] >> 5 {
+	case 0:
+		return sdoSegment("download segment", data)
+	case 1:
+		return sdoInitiate("initiate download", data)
+	case 2:
+		return "initiate upload " + sdoMux(data)
+	case 3:
+		return fmt.Sprintf("upload segment toggle %d", (data[0]>>4)&0x01)
+	case 4:
+		return sdoAbort(data)
+	case 5:
+		switch data[0] & 0x03 {
+		case 0:
+			return fmt.Sprintf("block upload initiate %v blksize %d", sdoMux(data), data[4])
+		case 1:
+			return "block upload end"
+		case 2:
+			return fmt.Sprintf("block upload ack seqno %d blksize %d", data[1], data[2])
+		default:
+			return "block upload start"
+		}
+	case 6:
+		if data[0]&0x01 != 0 {
+			return fmt.Sprintf("block download end crc 0x%04X", binary.LittleEndian.Uint16(data[1:3]))
+		}
+		if data[0]&0x02 != 0 {
+			return fmt.Sprintf("block download initiate %v size %d", sdoMux(data), binary.LittleEndian.Uint32(data[4:8]))
+		}
+		return "block download initiate " + sdoMux(data)
+	}
+	return fmt.Sprintf("unknown command 0x%02X %v", data[0], formatData(data[1:]))
+}
+
+func decodeSDOResponse(data []byte) string {
+	if len(data) != 8 {
+		return "invalid " + formatData(data)
+	}
+	switch data[0] >> 5 {
+	case 0:
+		return sdoSegment("upload segment", data)
+	case 1:
+		return fmt.Sprintf("download segment toggle %d", (data[0]>>4)&0x01)
+	case 2:
+		return sdoInitiate("initiate upload", data)
+	case 3:
+		return "initiate download " + sdoMux(data)
+	case 4:
+		return sdoAbort(data)
+	case 5:
+		switch data[0] & 0x03 {
+		case 0:
+			return fmt.Sprintf("block download initiate %v blksize %d", sdoMux(data), data[4])
+		case 1:
+			return "block download end"
+		default:
+			return fmt.Sprintf("block download ack seqno %d blksize %d", data[1], data[2])
+		}
+	case 6:
+		if data[0]&0x01 != 0 {
+			return fmt.Sprintf("block upload end crc 0x%04X", binary.LittleEndian.Uint16(data[1:3]))
+		}
+		if data[0]&0x02 != 0 {
+			return fmt.Sprintf("block upload initiate %v size %d", sdoMux(data), binary.LittleEndian.Uint32(data[4:8]))
+		}
+		return "block upload initiate " + sdoMux(data)
+	}
+	return fmt.Sprintf("unknown command 0x%02X %v", data[0], formatData(data[1:]))
+}
+
+var lssCommandMap = map[uint8]string{
+	0x04: "switch state global",
+	0x11: "configure node id",
+	0x13: "configure bit timing",
+	0x15: "activate bit timing",
+	0x17: "store configuration",
+	0x40: "switch state selective vendor id",
+	0x41: "switch state selective product code",
+	0x42: "switch state selective revision number",
+	0x43: "switch state selective serial number",
+	0x44: "switch state selective response",
+	0x5A: "inquire vendor id",
+	0x5B: "inquire product code",
+	0x5C: "inquire revision number",
+	0x5D: "inquire serial number",
+	0x5E: "inquire node id",
+}
+
+func decodeLSS(data []byte) string {
+	if len(data) != 8 {
+		return "invalid " + formatData(data)
+	}
+	command, ok := lssCommandMap[data[0]]
+	if !ok {
+		return fmt.Sprintf("unknown command 0x%02X %v", data[0], formatData(data[1:]))
+	}
+	return command + " " + formatData(data[1:])
+}
@@ -0,0 +1,107 @@
+package trace
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		id   uint32
+		info Info
+	}{
+		{0x000, Info{Kind: KindNMT}},
+		{0x080, Info{Kind: KindSYNC}},
+		{0x090, Info{Kind: KindEMCY, NodeId: 0x10}},
+		{0x100, Info{Kind: KindTIME}},
+		{0x190, Info{Kind: KindTPDO, NodeId: 0x10, PDO: 1}},
+		{0x210, Info{Kind: KindRPDO, NodeId: 0x10, PDO: 1}},
+		{0x490, Info{Kind: KindTPDO, NodeId: 0x10, PDO: 4}},
+		{0x510, Info{Kind: KindRPDO, NodeId: 0x10, PDO: 4}},
+		{0x590, Info{Kind: KindSDOResponse, NodeId: 0x10}},
+		{0x610, Info{Kind: KindSDORequest, NodeId: 0x10}},
+		{0x710, Info{Kind: KindHeartbeat, NodeId: 0x10}},
+		{0x7E5, Info{Kind: KindLSSMaster}},
+		{0x7E4, Info{Kind: KindLSSSlave}},
+		{0x180, Info{Kind: KindUnknown}},
+		{0x7F0, Info{Kind: KindUnknown}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.info, Classify(canopen.NewFrame(test.id, 0, 0)), "id 0x%x", test.id)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		frame    string
+		expected string
+	}{
+		{"000#0110", "NMT ENTER-OPERATIONAL node 0x10"},
+		{"000#8100", "NMT RESET-NODE all nodes"},
+		{"080#", "SYNC"},
+		{"080#05", "SYNC counter 5"},
+		{"090#0000000000000000", "EMCY node 0x10 error reset, register 0x00"},
+		{"090#1081110000000000", "EMCY node 0x10 code 0x8110 (CAN Overrun (Objects lost)), register 0x11, manufacturer [00 00 00 00 00]"},
+		{"190#1234", "TPDO1 node 0x10 [12 34]"},
+		{"310#01", "RPDO2 node 0x10 [01]"},
+		{"610#4041600000000000", "SDO request node 0x10 initiate upload 0x6041:00"},
+		{"590#4B41600037020000", "SDO response node 0x10 initiate upload 0x6041:00 expedited [37 02]"},
+		{"590#4118100004000000", "SDO response node 0x10 initiate upload 0x1018:00 size 4"},
+		{"610#2F00200105000000", "SDO request node 0x10 initiate download 0x2000:01 expedited [05]"},
+		{"590#6000200100000000", "SDO response node 0x10 initiate download 0x2000:01"},
+		{"610#6000000000000000", "SDO request node 0x10 upload segment toggle 0"},
+		{"590#1B61626364000000", "SDO response node 0x10 upload segment toggle 1 [61 62] last"},
+		{"590#8000100000000206", "SDO response node 0x10 abort 0x1000:00 code 0x06020000 (Object does not exist in the object dictionary)"},
+		{"610#C600200104000000", "SDO request node 0x10 block download initiate 0x2000:01 size 4"},
+		{"590#A400200110000000", "SDO response node 0x10 block download initiate 0x2000:01 blksize 16"},
+		{"610#C134120000000000", "SDO request node 0x10 block download end crc 0x1234"},
+		{"710#00", "boot-up node 0x10"},
+		{"710#05", "HB node 0x10 OPERATIONAL"},
+		{"710#7F", "HB node 0x10 PRE-OPERATIONAL"},
+		{"710#R", "node guarding request node 0x10"},
+		{"710#85", "node guarding response node 0x10 OPERATIONAL toggle 1"},
+		{"7E5#0401000000000000", "LSS master switch state global [01 00 00 00 00 00 00]"},
+		{"7E4#5E10000000000000", "LSS slave inquire node id [10 00 00 00 00 00 00]"},
+		{"7F0#01", "7F0 [01]"},
+	}
+	for _, test := range tests {
+		record, err := ParseCandumpLine(test.frame)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, Decode(record.Frame), test.frame)
+	}
+}
+
+func TestParseCandumpLine(t *testing.T) {
+	record, err := ParseCandumpLine("(1700000000.123456) can0 601#4000100000000000 T")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Unix(1700000000, 123456000), record.Timestamp)
+	assert.Equal(t, "can0", record.Interface)
+	assert.Equal(t, canopen.TraceTx, record.Direction)
+	assert.EqualValues(t, 0x601, record.Frame.ID)
+	assert.EqualValues(t, 8, record.Frame.DLC)
+	assert.Equal(t, "(1700000000.123456) T SDO request node 0x01 initiate upload 0x1000:00", FormatRecord(record))
+
+	_, err = ParseCandumpLine("(1700000000.123456) can0")
+	assert.NotNil(t, err)
+	_, err = ParseCandumpLine("601-40")
+	assert.NotNil(t, err)
+	_, err = ParseCandumpLine("601#400010000000000000")
+	assert.NotNil(t, err)
+}
+
+func TestDecodeTracer(t *testing.T) {
+	// Lines written by candump tracer can be read back
+	buffer := &bytes.Buffer{}
+	timestamp := time.Unix(1700000000, 5000)
+	frame := canopen.NewFrame(0x710, 0, 1)
+	assert.Nil(t, canopen.NewCandumpTracer(buffer, "can0").Trace(timestamp, canopen.TraceRx, frame))
+	record, err := ParseCandumpLine(buffer.String())
+	assert.Nil(t, err)
+	decoded := &bytes.Buffer{}
+	assert.Nil(t, NewDecodeTracer(decoded).Trace(record.Timestamp, record.Direction, record.Frame))
+	assert.Equal(t, "(1700000000.000005) R boot-up node 0x10\n", decoded.String())
+}
@@ -0,0 +1,211 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+)
+
+var ErrUnsupportedCapture = errors.New("unsupported capture")
+
+const linkTypeCANSocketCAN = 227
+
+// Read a capture, either a candump log or a PCAP / PCAPNG file
+// using LINKTYPE_CAN_SOCKETCAN, e.g. as written by [canopen.PcapTracer].
+// Format is detected from content.
+func Read(r io.Reader) ([]Record, error) {
+	reader := bufio.NewReader(r)
+	magic, err := reader.Peek(4)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if len(magic) == 4 {
+		switch binary.LittleEndian.Uint32(magic) {
+		case 0x0A0D0D0A:
+			return readPcapng(reader)
+		case 0xA1B2C3D4, 0xD4C3B2A1, 0xA1B23C4D, 0x4D3CB2A1:
+			return readPcap(reader)
+		}
+	}
+	return ReadCandump(reader)
+}
+
+// Read a candump log, one frame per line, see [ParseCandumpLine].
+// Empty lines and lines starting with '#' are ignored.
+func ReadCandump(r io.Reader) ([]Record, error) {
+	records := []Record{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		record, err := ParseCandumpLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %v : %w", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// Frame in SocketCAN format, CAN id is big endian
+func parseSocketCAN(raw []byte) (canopen.Frame, error) {
+	if len(raw) < 8 {
+		return canopen.Frame{}, fmt.Errorf("%w : truncated frame", ErrUnsupportedCapture)
+	}
+	id := binary.BigEndian.Uint32(raw[0:4])
+	frame := canopen.NewFrame(id&(canopen.CanSffMask|canopen.CanRtrFlag), 0, min(raw[4], 8))
+	copy(frame.Data[:], raw[8:min(len(raw), 16)])
+	return frame, nil
+}
+
+func readPcap(r io.Reader) ([]Record, error) {
+	header := make([]byte, 24)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	magic := order.Uint32(header[0:4])
+	if magic == 0xD4C3B2A1 || magic == 0x4D3CB2A1 {
+		order = binary.BigEndian
+		magic = order.Uint32(header[0:4])
+	}
+	nanoseconds := magic == 0xA1B23C4D
+	if order.Uint32(header[20:24]) != linkTypeCANSocketCAN {
+		return nil, fmt.Errorf("%w : link type %v", ErrUnsupportedCapture, order.Uint32(header[20:24]))
+	}
+	records := []Record{}
+	recordHeader := make([]byte, 16)
+	for {
+		_, err := io.ReadFull(r, recordHeader)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, order.Uint32(recordHeader[8:12]))
+		_, err = io.ReadFull(r, data)
+		if err != nil {
+			return nil, err
+		}
+		frame, err := parseSocketCAN(data)
+		if err != nil {
+			return nil, err
+		}
+		fraction := int64(order.Uint32(recordHeader[4:8]))
+		if !nanoseconds {
+			fraction *= 1000
+		}
+		records = append(records, Record{
+			Timestamp: time.Unix(int64(order.Uint32(recordHeader[0:4])), fraction),
+			Frame:     frame,
+		})
+	}
+}
+
+// Read a PCAPNG file, only the first section is read & timestamps
+// are expected in microseconds (default resolution)
+func readPcapng(r io.Reader) ([]Record, error) {
+	records := []Record{}
+	var order binary.ByteOrder = binary.LittleEndian
+	linkTypes := []uint16{}
+	sections := 0
+	header := make([]byte, 8)
+	for {
+		_, err := io.ReadFull(r, header)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		blockType := order.Uint32(header[0:4])
+		if blockType == 0x0A0D0D0A {
+			// Byte order magic follows block length
+			magic := make([]byte, 4)
+			_, err = io.ReadFull(r, magic)
+			if err != nil {
+				return nil, err
+			}
+			if binary.BigEndian.Uint32(magic) == 0x1A2B3C4D {
+				order = binary.BigEndian
+			} else {
+				order = binary.LittleEndian
+			}
+			sections++
+			if sections > 1 {
+				return records, nil
+			}
+			header = append(header, magic...)
+		}
+		length := order.Uint32(header[4:8])
+		if length < uint32(len(header))+4 || length%4 != 0 {
+			return nil, fmt.Errorf("%w : invalid block length %v", ErrUnsupportedCapture, length)
+		}
+		body := make([]byte, length-uint32(len(header)))
+		_, err = io.ReadFull(r, body)
+		if err != nil {
+			return nil, err
+		}
+		body = body[:len(body)-4] // Trailing block length
+		header = header[:8]
+		switch blockType {
+		case 1:
+			// Interface description
+			if len(body) < 2 {
+				return nil, fmt.Errorf("%w : truncated interface", ErrUnsupportedCapture)
+			}
+			linkTypes = append(linkTypes, order.Uint16(body[0:2]))
+		case 6:
+			// Enhanced packet
+			if len(body) < 20 {
+				return nil, fmt.Errorf("%w : truncated packet", ErrUnsupportedCapture)
+			}
+			iface := order.Uint32(body[0:4])
+			if int(iface) >= len(linkTypes) || linkTypes[iface] != linkTypeCANSocketCAN {
+				continue
+			}
+			micros := uint64(order.Uint32(body[4:8]))<<32 | uint64(order.Uint32(body[8:12]))
+			captured := order.Uint32(body[12:16])
+			if int(captured) > len(body)-20 {
+				return nil, fmt.Errorf("%w : truncated packet", ErrUnsupportedCapture)
+			}
+			frame, err := parseSocketCAN(body[20 : 20+captured])
+			if err != nil {
+				return nil, err
+			}
+			record := Record{Timestamp: time.UnixMicro(int64(micros)), Frame: frame}
+			options := body[min(int(20+(captured+3)/4*4), len(body)):]
+			record.Direction = pcapngDirection(order, options)
+			records = append(records, record)
+		}
+	}
+}
+
+// Direction from epb_flags option, 0 if unknown
+func pcapngDirection(order binary.ByteOrder, options []byte) canopen.TraceDirection {
+	for len(options) >= 4 {
+		code := order.Uint16(options[0:2])
+		length := int(order.Uint16(options[2:4]))
+		padded := (length + 3) / 4 * 4
+		if code == 0 || len(options) < 4+padded {
+			return 0
+		}
+		if code == 2 && length == 4 {
+			return canopen.TraceDirection(order.Uint32(options[4:8]) & 0x03)
+		}
+		options = options[4+padded:]
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package trace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	canopen "github.com/samsamfire/gocanopen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	timestamp := time.Unix(1700000000, 123456000)
+	frames := []canopen.Frame{
+		canopen.NewFrame(0x000, 0, 2),
+		canopen.NewFrame(0x701|canopen.CanRtrFlag, 0, 0),
+		canopen.NewFrame(0x601, 0, 8),
+	}
+	frames[2].Data = [8]byte{0x40, 0x00, 0x10}
+	directions := []canopen.TraceDirection{canopen.TraceTx, canopen.TraceRx, canopen.TraceTx}
+
+	newPcap := func(w *bytes.Buffer) canopen.FrameTracer {
+		tracer, err := canopen.NewPcapTracer(w)
+		assert.Nil(t, err)
+		return tracer
+	}
+	newPcapng := func(w *bytes.Buffer) canopen.FrameTracer {
+		tracer, err := canopen.NewPcapngTracer(w)
+		assert.Nil(t, err)
+		return tracer
+	}
+	newCandump := func(w *bytes.Buffer) canopen.FrameTracer {
+		return canopen.NewCandumpTracer(w, "can0")
+	}
+	tests := []struct {
+		name      string
+		tracer    func(w *bytes.Buffer) canopen.FrameTracer
+		direction bool
+	}{
+		{"pcap", newPcap, false},
+		{"pcapng", newPcapng, true},
+		{"candump", newCandump, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			tracer := test.tracer(buffer)
+			for i, frame := range frames {
+				assert.Nil(t, tracer.Trace(timestamp.Add(time.Duration(i)*time.Millisecond), directions[i], frame))
+			}
+			records, err := Read(buffer)
+			assert.Nil(t, err)
+			assert.Len(t, records, len(frames))
+			for i, record := range records {
+				assert.Equal(t, frames[i], record.Frame)
+				assert.True(t, timestamp.Add(time.Duration(i)*time.Millisecond).Equal(record.Timestamp))
+				if test.direction {
+					assert.Equal(t, directions[i], record.Direction)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid candump", func(t *testing.T) {
+		_, err := Read(strings.NewReader("601#40\nnot a frame\n"))
+		assert.ErrorContains(t, err, "line 2")
+	})
+}
@@ -0,0 +1,196 @@
+package canopen
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const DefaultRxQueueSize = 64
+
+// Time during which [CanErrorRxOverflow] is reported after a frame was dropped
+const rxOverflowHold = 100 * time.Millisecond
+
+// Options for the RX dispatcher, see [BusManager.StartRxDispatch]
+type RxDispatchOptions struct {
+	// Size of the queue of each listener, frames are dropped when it is full
+	QueueSize int
+}
+
+// Frames waiting to be handled by a listener, in its own goroutine
+type rxWorker struct {
+	listener FrameListener
+	queue    chan Frame
+	stop     chan struct{}
+	done     chan struct{} // Closed once run returned
+}
+
+func (w *rxWorker) run(wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer close(w.done)
+	for {
+		select {
+		case frame := <-w.queue:
+			w.listener.Handle(frame)
+		case <-w.stop:
+			// Handle frames received before stopping
+			for len(w.queue) > 0 {
+				w.listener.Handle(<-w.queue)
+			}
+			return
+		}
+	}
+}
+
+type rxDispatcher struct {
+	mu        sync.Mutex
+	queueSize int
+	workers   map[FrameListener]*rxWorker
+	closed    bool
+	wg        sync.WaitGroup
+	stop      chan struct{} // Closed by [BusManager.StopRxDispatch]
+	stopOnce  sync.Once
+	stopped   chan struct{} // Closed once all the workers returned
+}
+
+// Queue frame for listener, returns false if dropped.
+// Workers are created on first frame, frame is handled inline once closed.
+func (d *rxDispatcher) dispatch(listener FrameListener, frame Frame) bool {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		listener.Handle(frame)
+		return true
+	}
+	worker, ok := d.workers[listener]
+	if !ok {
+		worker = &rxWorker{
+			listener: listener,
+			queue:    make(chan Frame, d.queueSize),
+			stop:     make(chan struct{}),
+			done:     make(chan struct{}),
+		}
+		d.workers[listener] = worker
+		d.wg.Add(1)
+		go worker.run(&d.wg)
+	}
+	// Queue while locked, so that worker can not be stopped before handling frame
+	defer d.mu.Unlock()
+	select {
+	case worker.queue <- frame:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop the worker of listener, if any, and wait for it to handle the queued frames.
+// It should be called once the listener is no longer subscribed, otherwise
+// a new worker is created for the next frame.
+func (d *rxDispatcher) remove(listener FrameListener) {
+	d.mu.Lock()
+	worker, ok := d.workers[listener]
+	if ok {
+		close(worker.stop)
+		delete(d.workers, listener)
+	}
+	d.mu.Unlock()
+	if ok {
+		<-worker.done
+	}
+}
+
+// Stop all the workers and wait for them to handle the queued frames,
+// new workers are created for next frames
+func (d *rxDispatcher) removeAll() {
+	d.mu.Lock()
+	workers := make([]*rxWorker, 0, len(d.workers))
+	for listener, worker := range d.workers {
+		close(worker.stop)
+		delete(d.workers, listener)
+		workers = append(workers, worker)
+	}
+	d.mu.Unlock()
+	for _, worker := range workers {
+		<-worker.done
+	}
+}
+
+// Stop all the workers and wait for them to handle remaining frames
+func (d *rxDispatcher) close() {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	d.removeAll()
+	d.wg.Wait()
+}
+
+// StartRxDispatch handles received frames outside of the receive path until ctx is cancelled.
+// Each listener gets a bounded queue and a dedicated goroutine, so that a slow listener
+// only delays its own frames. Frames are dropped when the queue of a listener is full,
+// they are counted in [BusStats] and reported as [CanErrorRxOverflow], i.e. as
+// an RX overflow EMCY by local nodes.
+// Calling this while dispatcher is already running does nothing.
+func (bm *BusManager) StartRxDispatch(ctx context.Context, opts *RxDispatchOptions) {
+	if opts == nil {
+		opts = &RxDispatchOptions{}
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultRxQueueSize
+	}
+	dispatcher := &rxDispatcher{
+		queueSize: opts.QueueSize,
+		workers:   map[FrameListener]*rxWorker{},
+		stop:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	if !bm.dispatcher.CompareAndSwap(nil, dispatcher) {
+		return
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-dispatcher.stop:
+		}
+		// Dispatcher is kept until closed, so that it can still be stopped & waited for
+		dispatcher.close()
+		bm.dispatcher.CompareAndSwap(dispatcher, nil)
+		close(dispatcher.stopped)
+	}()
+}
+
+// StopRxDispatch handles received frames inline again, like cancelling the context given to
+// [BusManager.StartRxDispatch]. It returns once the queued frames are handled, or with ctx error
+// if ctx expires before. Does nothing if dispatcher is not running.
+func (bm *BusManager) StopRxDispatch(ctx context.Context) error {
+	dispatcher := bm.dispatcher.Load()
+	if dispatcher == nil {
+		return nil
+	}
+	dispatcher.stopOnce.Do(func() { close(dispatcher.stop) })
+	select {
+	case <-dispatcher.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Give frame to listener, inline or via the dispatcher
+func (bm *BusManager) deliver(listener FrameListener, frame Frame) {
+	dispatcher := bm.dispatcher.Load()
+	if dispatcher == nil {
+		listener.Handle(frame)
+		return
+	}
+	if !dispatcher.dispatch(listener, frame) {
+		bm.rxDropped.Add(1)
+		bm.rxDroppedAt.Store(time.Now().UnixNano())
+	}
+}
+
+// Returns true if a received frame was dropped recently
+func (bm *BusManager) rxOverflow() bool {
+	droppedAt := bm.rxDroppedAt.Load()
+	return droppedAt != 0 && time.Since(time.Unix(0, droppedAt)) < rxOverflowHold
+}
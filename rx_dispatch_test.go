@@ -0,0 +1,145 @@
+package canopen
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Listener recording frames, blocked until gate is closed if any
+type gatedListener struct {
+	mu     sync.Mutex
+	gate   chan struct{}
+	frames []Frame
+}
+
+func (l *gatedListener) Handle(frame Frame) {
+	if l.gate != nil {
+		<-l.gate
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.frames = append(l.frames, frame)
+}
+
+func (l *gatedListener) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.frames)
+}
+
+func TestRxDispatch(t *testing.T) {
+	bm := NewBusManager(&slowBus{})
+	slow := &gatedListener{gate: make(chan struct{})}
+	fast := &gatedListener{}
+	assert.Nil(t, bm.Subscribe(0x181, 0x7FF, false, slow))
+	assert.Nil(t, bm.Subscribe(0x182, 0x7FF, false, fast))
+	bm.StartRxDispatch(context.Background(), &RxDispatchOptions{QueueSize: 2})
+
+	t.Run("slow listener", func(t *testing.T) {
+		// First frame is being handled, 2 are queued & the others are dropped
+		bm.Handle(NewFrame(0x181, 0, 1))
+		assert.Eventually(t, func() bool {
+			bm.Handle(NewFrame(0x181, 0, 1))
+			return bm.BusStats().RxDropped > 0
+		}, time.Second, time.Millisecond)
+		for i := range 10 {
+			bm.Handle(NewFrame(0x182, 0, 1))
+			assert.Eventually(t, func() bool { return fast.count() == i+1 }, time.Second, time.Millisecond)
+		}
+		assert.Equal(t, 0, slow.count())
+		assert.Nil(t, bm.Process())
+		assert.NotZero(t, bm.Error()&CanErrorRxOverflow)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, bm.StopRxDispatch(ctx), context.DeadlineExceeded)
+		close(slow.gate)
+		assert.Nil(t, bm.StopRxDispatch(context.Background()))
+		assert.Equal(t, 3, slow.count())
+		// Handled inline
+		bm.Handle(NewFrame(0x182, 0, 1))
+		assert.Equal(t, 11, fast.count())
+		bm.ResetBusStats()
+		assert.EqualValues(t, 0, bm.BusStats().RxDropped)
+	})
+}
+
+func TestRxDispatchRemoveConcurrent(t *testing.T) {
+	d := &rxDispatcher{queueSize: 64, workers: map[FrameListener]*rxWorker{}}
+	listener := &gatedListener{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 1000 {
+			d.remove(listener)
+		}
+	}()
+	accepted := 0
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		if d.dispatch(listener, NewFrame(0x181, 0, 8)) {
+			accepted++
+		}
+	}
+	d.close()
+	// Every accepted frame is handled, even if its worker was removed meanwhile
+	assert.Equal(t, accepted, listener.count())
+}
+
+func TestRxDispatchUnsubscribeConcurrent(t *testing.T) {
+	bm := NewBusManager(&slowBus{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bm.StartRxDispatch(ctx, nil)
+	dispatcher := bm.dispatcher.Load()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				bm.Handle(NewFrame(0x181, 0, 8))
+			}
+		}
+	}()
+	for i := range 20 {
+		listener := &gatedListener{}
+		if i%2 == 0 {
+			assert.Nil(t, bm.Subscribe(0x181, 0x7FF, false, listener))
+		} else {
+			assert.Nil(t, bm.Subscribe(0x180, 0x780, false, listener))
+		}
+		assert.Eventually(t, func() bool { return listener.count() > 0 }, time.Second, time.Microsecond)
+		if i%4 < 2 {
+			bm.Unsubscribe(listener)
+		} else {
+			bm.UnsubscribeAll()
+		}
+		handled := listener.count()
+		dispatcher.mu.Lock()
+		_, ok := dispatcher.workers[listener]
+		dispatcher.mu.Unlock()
+		assert.False(t, ok)
+		time.Sleep(100 * time.Microsecond)
+		assert.Equal(t, handled, listener.count())
+	}
+	close(done)
+	wg.Wait()
+	dispatcher.mu.Lock()
+	assert.Empty(t, dispatcher.workers)
+	dispatcher.mu.Unlock()
+}
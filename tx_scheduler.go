@@ -0,0 +1,267 @@
+package canopen
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const DefaultTxQueueSize = 64
+
+// Options for the TX scheduler, see [BusManager.StartTxScheduler]
+type TxSchedulerOptions struct {
+	// Size of each TX queue. When the bulk queue is full, sending blocks
+	// so that bulk traffic is kept here rather than in the driver.
+	QueueSize int
+	// Returns true if frame should be sent with high priority.
+	// Defaults to [IsHighPriority].
+	IsHighPriority func(frame Frame) bool
+	// Maximum rate in bits/s of throttled frames, 0 disables throttling.
+	// This keeps bandwidth available for PDOs during bulk transfers, e.g. a rate
+	// of 250_000 at 500 kbit/s limits SDO block downloads to half of the bus.
+	ThrottleRate int
+	// Returns true if frame should be throttled, defaults to [IsSDO].
+	IsThrottled func(frame Frame) bool
+}
+
+// IsSDO returns true for SDO frames using the default channels (0x581 - 0x67F)
+func IsSDO(frame Frame) bool {
+	id := frame.ID & CanSffMask
+	return id >= 0x580 && id <= 0x67F
+}
+
+// Token bucket limiting the rate of throttled frames, in bits
+type throttle struct {
+	rate   float64 // bits/s
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newThrottle(rate int) *throttle {
+	// Allow a few frames in a row, so that short transfers are not delayed
+	burst := float64(max(rate/100, 4*int(FrameBits(Frame{DLC: 8}))))
+	return &throttle{rate: float64(rate), burst: burst, tokens: burst}
+}
+
+// Time to wait before sending frame, tokens are consumed if 0
+func (t *throttle) delay(now time.Time, frame Frame) time.Duration {
+	if !t.last.IsZero() {
+		t.tokens = min(t.burst, t.tokens+now.Sub(t.last).Seconds()*t.rate)
+	}
+	t.last = now
+	bits := float64(FrameBits(frame))
+	if t.tokens >= bits {
+		t.tokens -= bits
+		return 0
+	}
+	return time.Duration((bits - t.tokens) / t.rate * float64(time.Second))
+}
+
+// IsHighPriority returns true for NMT, SYNC, EMCY and TIME frames
+// i.e. CAN IDs below the first TPDO.
+func IsHighPriority(frame Frame) bool {
+	return frame.ID&CanSffMask < 0x180
+}
+
+type txScheduler struct {
+	high        chan Frame
+	bulk        chan Frame
+	throttled   chan Frame
+	isHigh      func(frame Frame) bool
+	isThrottled func(frame Frame) bool
+	throttle    *throttle // nil if disabled
+	pending     *Frame    // Throttled frame waiting to be sent, only used by scheduler goroutine
+	mu          sync.Mutex
+	closed      bool           // Frames are no longer queued once set
+	senders     sync.WaitGroup // Senders that may still queue a frame
+	done        chan struct{}  // Closed once frames are no longer queued
+	stop        chan struct{}  // Closed by [BusManager.StopTxScheduler]
+	stopOnce    sync.Once
+	stopped     chan struct{} // Closed once remaining frames are sent
+}
+
+func (s *txScheduler) enqueue(bm *BusManager, frame Frame) error {
+	queue := s.bulk
+	if s.isHigh(frame) {
+		queue = s.high
+	} else if s.throttle != nil && s.isThrottled(frame) {
+		queue = s.throttled
+	}
+	// Frames are no longer queued once flushing started
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return bm.sendBus(frame)
+	}
+	s.senders.Add(1)
+	s.mu.Unlock()
+	defer s.senders.Done()
+	select {
+	case queue <- frame:
+		return nil
+	case <-s.done:
+		return bm.sendBus(frame)
+	}
+}
+
+func (s *txScheduler) send(bm *BusManager, frame Frame) {
+	err := bm.sendBus(frame)
+	if err != nil {
+		bm.logger.Warn("error sending frame", "err", err)
+	}
+}
+
+// Send frames one at a time, high priority frames always go first.
+// At most one bulk frame is sent before a pending high priority frame.
+// Throttled frames are sent once the throttle allows it, other bulk
+// frames are not queued behind them.
+func (s *txScheduler) run(ctx context.Context, bm *BusManager) {
+	for {
+		select {
+		case frame := <-s.high:
+			s.send(bm, frame)
+			continue
+		default:
+		}
+		throttled := s.throttled
+		var wait <-chan time.Time
+		if s.pending != nil {
+			throttled = nil
+			delay := s.throttle.delay(time.Now(), *s.pending)
+			if delay == 0 {
+				s.send(bm, *s.pending)
+				s.pending = nil
+				continue
+			}
+			wait = time.After(delay)
+		}
+		select {
+		case frame := <-s.high:
+			s.send(bm, frame)
+		case frame := <-s.bulk:
+			s.send(bm, frame)
+		case frame := <-throttled:
+			s.pending = &frame
+		case <-wait:
+		case <-ctx.Done():
+			s.flush(bm)
+			return
+		case <-s.stop:
+			s.flush(bm)
+			return
+		}
+	}
+}
+
+// StartTxScheduler enables prioritized transmission until ctx is cancelled.
+// Frames given to [BusManager.Send] are queued and sent by a dedicated goroutine,
+// high priority frames (e.g. NMT, EMCY) are never queued behind bulk traffic such as
+// SDO block transfers. Send errors are logged instead of being returned.
+// Calling this while scheduler is already running does nothing.
+func (bm *BusManager) StartTxScheduler(ctx context.Context, opts *TxSchedulerOptions) {
+	if opts == nil {
+		opts = &TxSchedulerOptions{}
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultTxQueueSize
+	}
+	if opts.IsHighPriority == nil {
+		opts.IsHighPriority = IsHighPriority
+	}
+	if opts.IsThrottled == nil {
+		opts.IsThrottled = IsSDO
+	}
+	bm.busMu.Lock()
+	defer bm.busMu.Unlock()
+	if bm.scheduler != nil {
+		return
+	}
+	scheduler := &txScheduler{
+		high:        make(chan Frame, opts.QueueSize),
+		bulk:        make(chan Frame, opts.QueueSize),
+		throttled:   make(chan Frame, opts.QueueSize),
+		isHigh:      opts.IsHighPriority,
+		isThrottled: opts.IsThrottled,
+		done:        make(chan struct{}),
+		stop:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+	if opts.ThrottleRate > 0 {
+		scheduler.throttle = newThrottle(opts.ThrottleRate)
+	}
+	bm.scheduler = scheduler
+	go scheduler.run(ctx, bm)
+}
+
+// Stop accepting frames and send remaining ones, high priority first
+func (s *txScheduler) flush(bm *BusManager) {
+	defer close(s.stopped)
+	// Scheduler is kept until flushed, so that it can still be stopped & waited for
+	defer func() {
+		bm.busMu.Lock()
+		bm.scheduler = nil
+		bm.busMu.Unlock()
+	}()
+	s.mu.Lock()
+	s.closed = true
+	close(s.done)
+	s.mu.Unlock()
+	// Senders already enqueueing may still queue frames,
+	// keep sending until they have all returned
+	returned := make(chan struct{})
+	go func() {
+		s.senders.Wait()
+		close(returned)
+	}()
+	s.drain(bm)
+	for {
+		select {
+		case frame := <-s.high:
+			s.send(bm, frame)
+		case frame := <-s.bulk:
+			s.send(bm, frame)
+		case frame := <-s.throttled:
+			s.send(bm, frame)
+		case <-returned:
+			s.drain(bm)
+			return
+		}
+	}
+}
+
+// Send queued frames, high priority first
+func (s *txScheduler) drain(bm *BusManager) {
+	for len(s.high) > 0 {
+		s.send(bm, <-s.high)
+	}
+	for len(s.bulk) > 0 {
+		s.send(bm, <-s.bulk)
+	}
+	if s.pending != nil {
+		s.send(bm, *s.pending)
+		s.pending = nil
+	}
+	for len(s.throttled) > 0 {
+		s.send(bm, <-s.throttled)
+	}
+}
+
+// StopTxScheduler stops prioritized transmission, like cancelling the context given to
+// [BusManager.StartTxScheduler]. Queued frames are sent first, it returns once they are
+// all sent or with ctx error if ctx expires before. Does nothing if scheduler is not running.
+func (bm *BusManager) StopTxScheduler(ctx context.Context) error {
+	bm.busMu.RLock()
+	scheduler := bm.scheduler
+	bm.busMu.RUnlock()
+	if scheduler == nil {
+		return nil
+	}
+	scheduler.stopOnce.Do(func() { close(scheduler.stop) })
+	select {
+	case <-scheduler.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,165 @@
+package canopen
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Bus taking a fixed time to send each frame
+type slowBus struct {
+	mu    sync.Mutex
+	delay time.Duration
+	sent  []Frame
+	times []time.Time
+}
+
+func (b *slowBus) Connect(...any) error                   { return nil }
+func (b *slowBus) Disconnect() error                      { return nil }
+func (b *slowBus) Subscribe(callback FrameListener) error { return nil }
+func (b *slowBus) Send(frame Frame) error {
+	time.Sleep(b.delay)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, frame)
+	b.times = append(b.times, time.Now())
+	return nil
+}
+
+func (b *slowBus) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.sent)
+}
+
+// Send bulk traffic and high priority frames concurrently, returns
+// the worst case latency and the max number of frames sent before a high priority frame
+func measureLatency(t *testing.T, bm *BusManager, bus *slowBus) (time.Duration, int) {
+	const nbBulk = 300
+	const nbHigh = 10
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range nbBulk {
+			assert.Nil(t, bm.Send(NewFrame(0x601, 0, 8)))
+		}
+	}()
+	sentAt := make([]int, nbHigh)
+	enqueuedAt := make([]time.Time, nbHigh)
+	for i := range nbHigh {
+		time.Sleep(2 * time.Millisecond)
+		sentAt[i] = bus.count()
+		enqueuedAt[i] = time.Now()
+		frame := NewFrame(0x0, 0, 2)
+		frame.Data[1] = uint8(i)
+		assert.Nil(t, bm.Send(frame))
+	}
+	wg.Wait()
+	assert.Eventually(t, func() bool { return bus.count() == nbBulk+nbHigh }, 5*time.Second, time.Millisecond)
+
+	worstLatency := time.Duration(0)
+	worstQueued := 0
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	for index, frame := range bus.sent {
+		if frame.ID != 0 {
+			continue
+		}
+		i := frame.Data[1]
+		worstLatency = max(worstLatency, bus.times[index].Sub(enqueuedAt[i]))
+		worstQueued = max(worstQueued, index-sentAt[i])
+	}
+	return worstLatency, worstQueued
+}
+
+func TestTxScheduler(t *testing.T) {
+	t.Run("with scheduler", func(t *testing.T) {
+		bus := &slowBus{delay: 50 * time.Microsecond}
+		bm := NewBusManager(bus)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		bm.StartTxScheduler(ctx, &TxSchedulerOptions{QueueSize: 32})
+		latency, queued := measureLatency(t, bm, bus)
+		t.Logf("worst case latency %v, %v frames before", latency, queued)
+		// At most the bulk frame being sent goes before a high priority frame
+		assert.LessOrEqual(t, queued, 2)
+		assert.Less(t, latency, 20*time.Millisecond)
+	})
+
+	t.Run("stop scheduler", func(t *testing.T) {
+		bus := &slowBus{}
+		bm := NewBusManager(bus)
+		ctx, cancel := context.WithCancel(context.Background())
+		bm.StartTxScheduler(ctx, nil)
+		for range 10 {
+			assert.Nil(t, bm.Send(NewFrame(0x601, 0, 8)))
+		}
+		cancel()
+		assert.Eventually(t, func() bool { return bus.count() == 10 }, time.Second, time.Millisecond)
+		// Frames are sent directly once stopped
+		assert.Nil(t, bm.Send(NewFrame(0x601, 0, 8)))
+		assert.Equal(t, 11, bus.count())
+	})
+	t.Run("stop and wait", func(t *testing.T) {
+		bus := &slowBus{delay: time.Millisecond}
+		bm := NewBusManager(bus)
+		bm.StartTxScheduler(context.Background(), nil)
+		for range 50 {
+			assert.Nil(t, bm.Send(NewFrame(0x601, 0, 8)))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, bm.StopTxScheduler(ctx))
+		assert.Nil(t, bm.StopTxScheduler(context.Background()))
+		assert.Equal(t, 50, bus.count())
+	})
+	t.Run("stop with concurrent senders", func(t *testing.T) {
+		for range 200 {
+			bus := &slowBus{}
+			bm := NewBusManager(bus)
+			bm.StartTxScheduler(context.Background(), &TxSchedulerOptions{
+				QueueSize:    1,
+				ThrottleRate: 1000 * int(FrameBits(NewFrame(0x601, 0, 8))),
+			})
+			wg := sync.WaitGroup{}
+			for _, id := range []uint32{0x80, 0x181, 0x181, 0x181, 0x601} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 30 {
+						assert.Nil(t, bm.Send(NewFrame(id, 0, 8)))
+					}
+				}()
+			}
+			time.Sleep(100 * time.Microsecond)
+			assert.Nil(t, bm.StopTxScheduler(context.Background()))
+			wg.Wait()
+			// No frame is lost, whether it was queued or sent directly
+			assert.Equal(t, 150, bus.count())
+		}
+	})
+	t.Run("throttle", func(t *testing.T) {
+		bus := &slowBus{}
+		bm := NewBusManager(bus)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		// About 100 SDO frames per second
+		bm.StartTxScheduler(ctx, &TxSchedulerOptions{ThrottleRate: 100 * int(FrameBits(NewFrame(0x601, 0, 8)))})
+		start := time.Now()
+		for range 30 {
+			assert.Nil(t, bm.Send(NewFrame(0x601, 0, 8)))
+		}
+		for range 10 {
+			assert.Nil(t, bm.Send(NewFrame(0x181, 0, 8)))
+		}
+		// PDOs are not queued behind throttled SDO frames
+		assert.Eventually(t, func() bool { return bus.count() >= 10 }, 50*time.Millisecond, time.Millisecond)
+		assert.Less(t, bus.count(), 30)
+		assert.Eventually(t, func() bool { return bus.count() == 40 }, 2*time.Second, time.Millisecond)
+		assert.Greater(t, time.Since(start), 200*time.Millisecond)
+	})
+}